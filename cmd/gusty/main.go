@@ -0,0 +1,347 @@
+// Command gusty provides tools for gusty source files.
+//
+// Usage:
+//
+//	gusty build [-plugin name]... [-max-instructions n] [-max-blocks n] [-max-function-instructions n] [-max-function-blocks n] [-warn-budget] [-type-check] [-warn-unused] [-warn-unreachable] [-warn-shadowing] [-eliminate-dead-code] [-fold-constant-calls] [-whole-program] [-library] [-gc] [-json-diagnostics] [-o file.ll] file.gusty
+//	gusty graph [-dot] file.gusty
+//	gusty stats file.gusty
+//	gusty lint [-max-complexity n] file.gusty
+//	gusty compare a.gusty b.gusty
+//	gusty sdiff old.gusty new.gusty
+//	gusty examples run [dir]
+//	gusty conformance [-json]
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/donutloop/gusty/pkg/examples"
+	"github.com/donutloop/gusty/pkg/lang"
+)
+
+// usage is printed if the command is invoked without a known subcommand.
+const usage = `usage: gusty <command> [arguments]
+
+commands:
+  build file.gusty  compile a program into LLVM IR, running the passes of the plugins
+  graph file.gusty  print the import graph of a program in build order
+  stats file.gusty  report metrics of a program
+  lint file.gusty   report functions which are too complex
+  compare a b       report how similar the structure of two programs is
+  sdiff old new     report the functions and declarations which changed between two programs
+  examples run      build and run the example programs, checking their output
+  conformance       report which features of the language every backend supports`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run executes the subcommand named by the first argument, writing its output to w.
+func run(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	switch args[0] {
+	case "build":
+		return build(args[1:], w)
+	case "graph":
+		return graph(args[1:], w)
+	case "stats":
+		if len(args) != 2 {
+			return errors.New("usage: gusty stats file.gusty")
+		}
+		return stats(args[1], w)
+	case "lint":
+		return lint(args[1:], w)
+	case "compare":
+		if len(args) != 3 {
+			return errors.New("usage: gusty compare a.gusty b.gusty")
+		}
+		return compare(args[1], args[2], w)
+	case "sdiff":
+		if len(args) != 3 {
+			return errors.New("usage: gusty sdiff old.gusty new.gusty")
+		}
+		return sdiff(args[1], args[2], w)
+	case "examples":
+		if len(args) < 2 || len(args) > 3 || args[1] != "run" {
+			return errors.New("usage: gusty examples run [dir]")
+		}
+		dir := "examples"
+		if len(args) == 3 {
+			dir = args[2]
+		}
+		return runExamples(dir, w)
+	case "conformance":
+		return conformance(args[1:], w)
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
+
+// parseFile reads and parses the gusty source file at the given path and the files it imports.
+func parseFile(path string) ([]lang.Node, error) {
+	nodes, err := lang.ParseProgram(os.DirFS(filepath.Dir(path)), filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return nodes, nil
+}
+
+// build compiles the program in the given file into LLVM IR, written to the output file or w.
+// Every -plugin flag names a pass to run. Passes are registered with lang.RegisterPass by the
+// modules linked into the command, so a plugin is added by importing its module.
+func build(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("build", flag.ContinueOnError)
+	var options lang.Options
+	flags.Func("plugin", "the name of a registered pass to run, may be repeated (registered: "+strings.Join(lang.Passes(), ", ")+")", func(name string) error {
+		options.Passes = append(options.Passes, name)
+		return nil
+	})
+	flags.IntVar(&options.Budget.Instructions, "max-instructions", 0, "the maximum number of instructions of the program, 0 is unlimited")
+	flags.IntVar(&options.Budget.Blocks, "max-blocks", 0, "the maximum number of basic blocks of the program, 0 is unlimited")
+	flags.IntVar(&options.Budget.FunctionInstructions, "max-function-instructions", 0, "the maximum number of instructions of a function, 0 is unlimited")
+	flags.IntVar(&options.Budget.FunctionBlocks, "max-function-blocks", 0, "the maximum number of basic blocks of a function, 0 is unlimited")
+	flags.BoolVar(&options.Budget.Warn, "warn-budget", false, "warn about exceeded limits instead of failing")
+	flags.BoolVar(&options.TypeCheck, "type-check", false, "check the names and data types of the program before generating code")
+	flags.BoolVar(&options.WarnUnused, "warn-unused", false, "warn about variables and parameters which are never read")
+	flags.BoolVar(&options.WarnUnreachable, "warn-unreachable", false, "warn about statements which can never run, e.g. after a return")
+	flags.BoolVar(&options.WarnShadowing, "warn-shadowing", false, "warn about parameters and variables which shadow a value of an enclosing scope")
+	flags.BoolVar(&options.EliminateDeadCode, "eliminate-dead-code", false, "remove the statements after a return and the functions which are never called and report them")
+	flags.BoolVar(&options.FoldConstantCalls, "fold-constant-calls", false, "evaluate calls of pure functions with constant arguments at compile time")
+	flags.BoolVar(&options.WholeProgram, "whole-program", false, "remove the functions main can't reach before generating code and report them")
+	flags.BoolVar(&options.Library, "library", false, "generate the functions without a main function, to link them into other programs")
+	flags.BoolVar(&options.GarbageCollection, "gc", false, "allocate heap values with a conservative mark-sweep garbage collector, which makes free a no-op")
+	jsonDiagnostics := flags.Bool("json-diagnostics", false, "write the errors and warnings as a JSON array instead of text")
+	output := flags.String("o", "", "the file to write the LLVM IR to instead of the standard output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: gusty build [-plugin name]... [-max-instructions n] [-max-blocks n] [-max-function-instructions n] [-max-function-blocks n] [-warn-budget] [-type-check] [-warn-unused] [-warn-unreachable] [-warn-shadowing] [-eliminate-dead-code] [-fold-constant-calls] [-whole-program] [-library] [-gc] [-json-diagnostics] [-o file.ll] file.gusty")
+	}
+
+	path := flags.Arg(0)
+	input, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	options.ImportFS = os.DirFS(filepath.Dir(path))
+	compiler := lang.Compiler{Options: options}
+	var diagnostics lang.Diagnostics
+	compiler.Hooks.OnDiagnostic = diagnostics.Report
+	compiler.Hooks.OnDeadCode = func(report lang.DeadCodeReport) {
+		for _, statement := range report.Statements {
+			fmt.Fprintf(os.Stderr, "%s:%v: dropped unreachable statement\n", path, statement.Pos())
+		}
+		if len(report.Functions) > 0 {
+			fmt.Fprintf(os.Stderr, "%s: dropped %s\n", path, strings.Join(report.Functions, ", "))
+		}
+	}
+	compiler.Hooks.OnPrune = func(functions []string) {
+		if len(functions) > 0 {
+			fmt.Fprintf(os.Stderr, "%s: pruned %s\n", path, strings.Join(functions, ", "))
+		}
+	}
+	llvmIR, err := compiler.Compile(string(input))
+	// The errors and warnings are written to the standard error with the source code they are about
+	if *jsonDiagnostics {
+		encoded, err := lang.MarshalDiagnostics(path, diagnostics.List())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, string(encoded))
+	} else {
+		for _, diagnostic := range diagnostics.List() {
+			fmt.Fprint(os.Stderr, lang.RenderDiagnostic(path, string(input), diagnostic))
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: compilation failed", path)
+	}
+
+	if *output != "" {
+		return os.WriteFile(*output, []byte(llvmIR), 0o644)
+	}
+	_, err = io.WriteString(w, llvmIR)
+	return err
+}
+
+// graph writes the import graph of the program in the given file to w, as text or in the dot language.
+func graph(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("graph", flag.ContinueOnError)
+	dot := flags.Bool("dot", false, "write the graph in the dot language of Graphviz")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: gusty graph [-dot] file.gusty")
+	}
+
+	path := flags.Arg(0)
+	g, err := lang.LoadImportGraph(os.DirFS(filepath.Dir(path)), filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if *dot {
+		_, err = io.WriteString(w, g.Dot())
+	} else {
+		_, err = io.WriteString(w, g.String())
+	}
+	return err
+}
+
+// stats writes the metrics of the program in the given file to w.
+func stats(path string, w io.Writer) error {
+	nodes, err := parseFile(path)
+	if err != nil {
+		return err
+	}
+
+	s := lang.Stats(nodes)
+	fmt.Fprintf(w, "functions:  %d\n", s.Functions)
+	fmt.Fprintf(w, "statements: %d\n", s.Statements)
+	fmt.Fprintf(w, "loops:      %d\n", s.Loops)
+	fmt.Fprintf(w, "max depth:  %d\n", s.MaxDepth)
+	if len(s.Complexity) > 0 {
+		fmt.Fprintln(w, "cyclomatic complexity:")
+		for _, c := range s.Complexity {
+			fmt.Fprintf(w, "  %s: %d\n", c.Name, c.Complexity)
+		}
+	}
+	return nil
+}
+
+// lint writes a diagnostic to w for every function of the program in the given file whose
+// cyclomatic complexity exceeds the threshold, failing if there is any.
+func lint(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	maxComplexity := flags.Int("max-complexity", lang.DefaultComplexityThreshold, "the maximum cyclomatic complexity of a function")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: gusty lint [-max-complexity n] file.gusty")
+	}
+
+	path := flags.Arg(0)
+	nodes, err := parseFile(path)
+	if err != nil {
+		return err
+	}
+
+	diagnostics := lang.LintComplexity(nodes, *maxComplexity)
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintf(w, "%s: %s\n", path, diagnostic)
+	}
+	if len(diagnostics) > 0 {
+		return fmt.Errorf("%s: %d lint issues", path, len(diagnostics))
+	}
+	return nil
+}
+
+// compare writes the similarity of the programs in the given files to w, as a percentage.
+func compare(pathA, pathB string, w io.Writer) error {
+	a, err := parseFile(pathA)
+	if err != nil {
+		return err
+	}
+	b, err := parseFile(pathB)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "similarity: %.1f%%\n", lang.Similarity(a, b)*100)
+	return nil
+}
+
+// sdiff writes the declarations which were removed, changed or added between the programs in the
+// given files to w, one per line.
+func sdiff(oldPath, newPath string, w io.Writer) error {
+	before, err := parseFile(oldPath)
+	if err != nil {
+		return err
+	}
+	after, err := parseFile(newPath)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range lang.SemanticDiff(before, after) {
+		fmt.Fprintln(w, change)
+	}
+	return nil
+}
+
+// runExamples builds and runs every example program in the directory, writing whether its output
+// matched the expected output to w and failing if any example didn't.
+func runExamples(dir string, w io.Writer) error {
+	programs, err := examples.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, example := range programs {
+		output, err := example.Run()
+		switch {
+		case err != nil:
+			failed++
+			fmt.Fprintf(w, "FAIL %s: %v\n", example.Name, err)
+		case output != example.Expected:
+			failed++
+			fmt.Fprintf(w, "FAIL %s: unexpected output\n%s", example.Name, output)
+		default:
+			fmt.Fprintf(w, "ok   %s\n", example.Name)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d examples failed", failed, len(programs))
+	}
+	return nil
+}
+
+// conformance runs the conformance suite against every backend and writes how many features of
+// every group each backend supports to w, or the whole feature matrix as JSON.
+func conformance(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("conformance", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "write the feature matrix as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: gusty conformance [-json]")
+	}
+
+	matrix := lang.Conformance(lang.LLVMBackend{})
+	if *asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(matrix)
+	}
+
+	for _, backend := range matrix.Backends {
+		fmt.Fprintf(w, "%s:\n", backend)
+		for _, group := range matrix.Groups(backend) {
+			fmt.Fprintf(w, "  %-12s %d/%d\n", group.Group, group.Passed, group.Total)
+		}
+		for _, feature := range matrix.Features {
+			if !feature.Supported[backend] {
+				fmt.Fprintf(w, "  unsupported %s: %s\n", feature.Name, feature.Errors[backend])
+			}
+		}
+	}
+	return nil
+}
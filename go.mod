@@ -3,6 +3,6 @@ module github.com/donutloop/gusty
 go 1.20
 
 require (
-	github.com/google/uuid v1.3.0 // indirect
-	tinygo.org/x/go-llvm v0.0.0-20230426222550-71df1cb8675c // indirect
+	github.com/google/uuid v1.3.0
+	tinygo.org/x/go-llvm v0.0.0-20230426222550-71df1cb8675c
 )
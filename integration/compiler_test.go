@@ -0,0 +1,689 @@
+package integration
+
+import (
+	"errors"
+	"github.com/donutloop/gusty/pkg/lang"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"tinygo.org/x/go-llvm"
+)
+
+func TestCompilerHooks(t *testing.T) {
+	var phases []lang.Phase
+	var counters lang.Counters
+	compiler := lang.Compiler{
+		Hooks: lang.Hooks{
+			OnPhaseStart: func(phase lang.Phase) {
+				phases = append(phases, phase)
+			},
+			OnCounters: func(c lang.Counters) {
+				counters = c
+			},
+		},
+	}
+
+	input := `function add(a i32, b i32) { let donut = 43 printf(donut) printf(a) } add(1,2)`
+	actualLvmIR, err := compiler.Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLvmIR), "program_1")
+
+	expectedPhases := []lang.Phase{lang.PhaseTokenize, lang.PhaseParse, lang.PhaseGenerate}
+	if len(phases) != len(expectedPhases) {
+		t.Fatalf("expected phases %v, got %v", expectedPhases, phases)
+	}
+	for i := range expectedPhases {
+		if phases[i] != expectedPhases[i] {
+			t.Fatalf("expected phases %v, got %v", expectedPhases, phases)
+		}
+	}
+
+	expectedCounters := lang.Counters{Tokens: 29, Nodes: 12, Functions: 2, Instructions: 8}
+	if counters != expectedCounters {
+		t.Errorf("expected counters %+v, got %+v", expectedCounters, counters)
+	}
+}
+
+func TestCompilerCountersNodes(t *testing.T) {
+	tests := []struct {
+		input string
+		nodes int
+	}{
+		{`printf(1)`, 2},
+		{`function f(n i32) i32 { return n + 1 } printf(f(2))`, 9},
+		{`function f() { for i := 0; i < 3; i++ { printf(i) } } f()`, 10},
+	}
+
+	for _, test := range tests {
+		var counters lang.Counters
+		compiler := lang.Compiler{Hooks: lang.Hooks{OnCounters: func(c lang.Counters) { counters = c }}}
+		if _, err := compiler.Compile(test.input); err != nil {
+			t.Fatal(err)
+		}
+		if counters.Nodes != test.nodes {
+			t.Errorf("expected %d nodes for %q, got %d", test.nodes, test.input, counters.Nodes)
+		}
+	}
+}
+
+func TestCompilerHooksDiagnostic(t *testing.T) {
+	var diagnosticPhase lang.Phase
+	var endErr error
+	compiler := lang.Compiler{
+		Hooks: lang.Hooks{
+			OnPhaseEnd: func(phase lang.Phase, duration time.Duration, err error) {
+				if err != nil {
+					endErr = err
+				}
+			},
+			OnDiagnostic: func(phase lang.Phase, err error) {
+				diagnosticPhase = phase
+			},
+		},
+	}
+
+	if _, err := compiler.Compile(`let donutloop = )`); err == nil {
+		t.Fatal("expected parse error")
+	}
+
+	if diagnosticPhase != lang.PhaseParse {
+		t.Errorf("expected diagnostic in phase %s, got %q", lang.PhaseParse, diagnosticPhase)
+	}
+	if endErr == nil {
+		t.Error("expected phase end hook to receive the error")
+	}
+}
+
+func TestCompilerBoundsChecks(t *testing.T) {
+	compiler := lang.Compiler{Options: lang.Options{BoundsChecks: true}}
+
+	input := `function get(v [3]i32, i i32) i32 { return v[i] } let a: [3]i32 = [10, 20, 30] let i = 2 a[0] = 5 a[i] = a[0] + a[1] printf(a[2]) printf(get(a, 1)) let m: [2][2]i64 = [[1, 2], [3, 4]] m[1][i as i8 as i32 + -1] = 9 printf(m[1][1]) printf([7, 8][1])`
+	actualLvmIR, err := compiler.Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLvmIR), "index_bounds_checks")
+}
+
+func TestCompilerCaseInsensitiveKeywords(t *testing.T) {
+	input := `FUNCTION add(a I32, b I32) I32 { Return a + b } Let x: i64 = I64(add(1, 2)) printf(x AS F64) let Flag = TRUE`
+	canonical := `function add(a i32, b i32) i32 { return a + b } let x: i64 = i64(add(1, 2)) printf(x as f64) let Flag = true`
+
+	if normalized := lang.NormalizeKeywords(input); normalized != canonical {
+		t.Errorf("expected normalized input %q, got %q", canonical, normalized)
+	}
+
+	if _, err := (&lang.Compiler{}).Compile(input); err == nil {
+		t.Error("expected keywords to be case-sensitive by default")
+	}
+
+	compiler := lang.Compiler{Options: lang.Options{CaseInsensitiveKeywords: true}}
+	actualLvmIR, err := compiler.Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedLvmIR, err := (&lang.Compiler{}).Compile(canonical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actualLvmIR != expectedLvmIR {
+		t.Errorf("expected the same LLVM IR as the canonical input, got:\n%s", actualLvmIR)
+	}
+}
+
+func TestCompilerLocale(t *testing.T) {
+	inputs := map[string]string{
+		`let donutloop = )`:                    "Wert an Position 3 erwartet",
+		`let x: i8 = 300`:                      "ungültiger Wert für let x: Konstante 300 läuft in i8 über",
+		`function f() i32 { return true } f()`: "ungültiger Rückgabewert in Funktion f: true kann nicht als i32-Wert verwendet werden",
+	}
+
+	for input, expected := range inputs {
+		_, englishErr := (&lang.Compiler{}).Compile(input)
+		if englishErr == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+
+		compiler := lang.Compiler{Options: lang.Options{Locale: lang.LocaleGerman}}
+		_, err := compiler.Compile(input)
+		if err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+		if err.Error() != expected {
+			t.Errorf("expected german message %q, got %q", expected, err.Error())
+		}
+		if english := lang.Localize(err, lang.LocaleEnglish); english != englishErr.Error() {
+			t.Errorf("expected english message %q, got %q", englishErr.Error(), english)
+		}
+
+		var messageError *lang.MessageError
+		if !errors.As(err, &messageError) {
+			t.Errorf("expected a message error for %q, got %T", input, err)
+		}
+	}
+}
+
+func TestCompilerMinifyIdentifiers(t *testing.T) {
+	compiler := lang.Compiler{Options: lang.Options{MinifyIdentifiers: true}}
+
+	input := `struct Point { x i32, y i32 } function add(first i32, second i32) i32 { return first + second } function Scale(point Point, factor i32) i32 { let scaled = point.x + factor return scaled } let counter = add(1, 2) let values: []i32 = [counter] values = append(values, 4) for index := 0; index < 2; index++ { printf(values[index]) } printf(len(values)) printf(Scale(Point{x: counter, y: 0}, 2))`
+	actualLvmIR, err := compiler.Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLvmIR), "minify")
+}
+
+func TestCompilerEmbed(t *testing.T) {
+	lang.GenerateRandomIdentifier = func() string {
+		return "9b3c24fa-f1d5-4d41-9fd1-0637244ce4f3"
+	}
+
+	files := fstest.MapFS{"greeting.txt": {Data: []byte("hi\n")}}
+	compiler := lang.Compiler{Options: lang.Options{EmbedFS: files}}
+
+	input := `embed greeting "greeting.txt" function first() i8 { return greeting[0] } printf(len(greeting)) printf(first()) let copy = greeting copy[0] = 72 printf(copy[0] as i32 + greeting[1] as i32)`
+	actualLvmIR, err := compiler.Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLvmIR), "embed")
+
+	inputs := []string{
+		`embed data "missing.txt"`,
+		`embed data "greeting.txt" embed data "greeting.txt"`,
+		`embed data "greeting.txt" data[0] = 1`,
+		`embed data "greeting.txt" data = [1]`,
+		`function f() { embed data "greeting.txt" }`,
+		`embed data`,
+		`embed "greeting.txt"`,
+	}
+	for _, input := range inputs {
+		if _, err := compiler.Compile(input); err == nil {
+			t.Errorf("expected invalid embed error for %q", input)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	files := fstest.MapFS{
+		"main.gusty":       {Data: []byte(`import "lib/math.gusty" import "lib/point.gusty" let p = Point{x: 1, y: 2} printf(add(p.x, p.y))`)},
+		"lib/math.gusty":   {Data: []byte(`type Number = i32 function add(a Number, b Number) Number { return a + b }`)},
+		"lib/point.gusty":  {Data: []byte(`import "math.gusty" struct Point { x Number y Number }`)},
+		"broken.gusty":     {Data: []byte(`let x =`)},
+		"nested.gusty":     {Data: []byte(`function f() { import "lib/math.gusty" }`)},
+		"cycle/a.gusty":    {Data: []byte(`import "b.gusty" function a() i32 { return b() }`)},
+		"cycle/b.gusty":    {Data: []byte(`import "a.gusty" function b() i32 { return 1 }`)},
+		"cycle/main.gusty": {Data: []byte(`import "a.gusty" printf(a())`)},
+	}
+	compiler := lang.Compiler{Options: lang.Options{ImportFS: files}}
+
+	actualLvmIR, err := compiler.Compile(string(files["main.gusty"].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLvmIR), "import")
+
+	nodes, err := lang.ParseProgram(files, "main.gusty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	llvmIR, err := lang.GenerateLLVMIR(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if llvmIR != actualLvmIR {
+		t.Errorf("expected ParseProgram to produce the program compiled by the compiler, got\n%s", llvmIR)
+	}
+
+	expectedCycle := "import cycle: cycle/a.gusty -> cycle/b.gusty -> cycle/a.gusty"
+	_, err = lang.ParseProgram(files, "cycle/main.gusty")
+	if err == nil || err.Error() != expectedCycle {
+		t.Errorf("expected error %q, got %v", expectedCycle, err)
+	}
+	if !errors.Is(err, lang.ErrImportCycle) || errors.Is(err, lang.ErrFile) {
+		t.Errorf("expected the import cycle to be of the kind ErrImportCycle only, got %v", err)
+	}
+
+	inputs := []string{
+		`import "missing.gusty"`,
+		`import "broken.gusty"`,
+		`import "nested.gusty"`,
+		`import "lib/math.gusty" type Number = i64`,
+		`import`,
+		`import lib`,
+	}
+	for _, input := range inputs {
+		if _, err := compiler.Compile(input); err == nil {
+			t.Errorf("expected invalid import error for %q", input)
+		}
+	}
+
+	nodes, err = lang.Parse(lang.Tokenize(`import "lib/math.gusty"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lang.GenerateLLVMIR(nodes); err == nil {
+		t.Error("expected unresolved import error")
+	}
+}
+
+func TestImportGraph(t *testing.T) {
+	files := fstest.MapFS{
+		"main.gusty":      {Data: []byte(`import "lib/point.gusty" import "lib/math.gusty" printf(add(1, 2))`)},
+		"lib/math.gusty":  {Data: []byte(`function add(a i32, b i32) i32 { return a + b }`)},
+		"lib/point.gusty": {Data: []byte(`import "math.gusty" struct Point { x i32 y i32 }`)},
+		"cycle.gusty":     {Data: []byte(`import "cycle.gusty"`)},
+	}
+
+	graph, err := lang.LoadImportGraph(files, "main.gusty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFiles := []string{"lib/math.gusty", "lib/point.gusty", "main.gusty"}
+	if !reflect.DeepEqual(graph.Files, expectedFiles) {
+		t.Errorf("expected build order %q, got %q", expectedFiles, graph.Files)
+	}
+
+	expectedText := "lib/math.gusty\nlib/point.gusty: lib/math.gusty\nmain.gusty: lib/point.gusty lib/math.gusty\n"
+	if text := graph.String(); text != expectedText {
+		t.Errorf("expected graph %q, got %q", expectedText, text)
+	}
+
+	expectedDot := `digraph imports {
+	"lib/math.gusty";
+	"lib/point.gusty";
+	"lib/point.gusty" -> "lib/math.gusty";
+	"main.gusty";
+	"main.gusty" -> "lib/point.gusty";
+	"main.gusty" -> "lib/math.gusty";
+}
+`
+	if dot := graph.Dot(); dot != expectedDot {
+		t.Errorf("expected dot graph %q, got %q", expectedDot, dot)
+	}
+
+	expectedCycle := "import cycle: cycle.gusty -> cycle.gusty"
+	if _, err := lang.LoadImportGraph(files, "cycle.gusty"); err == nil || err.Error() != expectedCycle {
+		t.Errorf("expected error %q, got %v", expectedCycle, err)
+	}
+}
+
+func TestPackages(t *testing.T) {
+	files := fstest.MapFS{
+		"math/abs.gusty":    {Data: []byte(`package math import "double.gusty" function abs(x i32) i32 { return x } function twice(x i32) i32 { return double(abs(x)) }`)},
+		"math/double.gusty": {Data: []byte(`package math function double(x i32) i32 { return x + x }`)},
+		"geometry.gusty":    {Data: []byte(`package geometry import "math/abs.gusty" function abs(x i32) i32 { return math.abs(x) + 1 }`)},
+	}
+	compiler := lang.Compiler{Options: lang.Options{ImportFS: files}}
+
+	input := `import "math/abs.gusty" import "geometry.gusty" function abs(x i32) i32 { return 0 } printf(math.twice(3)) printf(geometry.abs(2)) printf(abs(1))`
+	actualLvmIR, err := compiler.Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLvmIR), "packages")
+
+	inputs := []string{
+		`import "math/abs.gusty" printf(twice(1))`,
+		`import "math/double.gusty" printf(double(1))`,
+		`import "math/double.gusty" printf(math.abs(1))`,
+		`let x = 1 package math`,
+		`package`,
+		`package "math"`,
+	}
+	for _, input := range inputs {
+		if _, err := compiler.Compile(input); err == nil {
+			t.Errorf("expected invalid package error for %q", input)
+		}
+	}
+}
+
+func TestCompilerBudget(t *testing.T) {
+	input := `function f(a i32) i32 { for i := 0; i < 3; i++ { printf(a) } return a } printf(f(2))`
+
+	budgets := []lang.Budget{
+		{FunctionInstructions: 3},
+		{FunctionBlocks: 2},
+		{Instructions: 10},
+		{Blocks: 3},
+	}
+	for _, budget := range budgets {
+		compiler := lang.Compiler{Options: lang.Options{Budget: budget}}
+		if _, err := compiler.Compile(input); err == nil {
+			t.Errorf("expected budget %+v to be exceeded", budget)
+		}
+	}
+
+	compiler := lang.Compiler{Options: lang.Options{Budget: lang.Budget{FunctionInstructions: 1000, FunctionBlocks: 100, Instructions: 1000, Blocks: 100}}}
+	if _, err := compiler.Compile(input); err != nil {
+		t.Errorf("expected program to stay within the budget, got %v", err)
+	}
+
+	var warnings []string
+	compiler = lang.Compiler{
+		Hooks: lang.Hooks{
+			OnDiagnostic: func(phase lang.Phase, err error) {
+				if phase == lang.PhaseBudget {
+					warnings = append(warnings, err.Error())
+				}
+			},
+		},
+		Options: lang.Options{Budget: lang.Budget{FunctionBlocks: 1, Blocks: 2, Warn: true}},
+	}
+	if _, err := compiler.Compile(input); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"function f has 3 basic blocks, exceeding the budget of 1",
+		"program has 4 basic blocks, exceeding the budget of 2",
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("expected warnings %q, got %q", expected, warnings)
+	}
+}
+
+func TestWholeProgram(t *testing.T) {
+	files := fstest.MapFS{
+		"std.gusty": {Data: []byte(`function double(a i32) i32 { return a + a } function quadruple(a i32) i32 { return double(double(a)) } function even(a i32) bool { return odd(a) } function odd(a i32) bool { return even(a) } function unused() { printf(quadruple(1)) }`)},
+	}
+	var pruned []string
+	compiler := lang.Compiler{
+		Hooks:   lang.Hooks{OnPrune: func(functions []string) { pruned = functions }},
+		Options: lang.Options{ImportFS: files, WholeProgram: true},
+	}
+
+	input := `import "std.gusty" function countdown(done bool, n i32) i32 { return done ? double(n) : countdown(true, n) } printf(countdown(false, 21))`
+	actualLvmIR, err := compiler.Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLvmIR), "whole_program")
+
+	expected := []string{"quadruple", "even", "odd", "unused"}
+	if !reflect.DeepEqual(pruned, expected) {
+		t.Errorf("expected pruned functions %q, got %q", expected, pruned)
+	}
+}
+
+func TestEliminateDeadCode(t *testing.T) {
+	var report lang.DeadCodeReport
+	compiler := lang.Compiler{
+		Hooks:   lang.Hooks{OnDeadCode: func(r lang.DeadCodeReport) { report = r }},
+		Options: lang.Options{EliminateDeadCode: true},
+	}
+
+	input := `function g() i32 { return 2 } function f() i32 { for i := 0; i < 3; i++ { return i printf(i) } return 1 printf(g()) return g() } printf(f()) return 0 printf(4) function h() i32 { return 3 } function main() i32 { return h() }`
+	actualLvmIR, err := compiler.Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLvmIR), "dead_code")
+
+	var dropped []string
+	for _, statement := range report.Statements {
+		dropped = append(dropped, statement.Pos().String())
+	}
+	expected := []string{"1:84", "1:105", "1:117", "1:151"}
+	if !reflect.DeepEqual(dropped, expected) {
+		t.Errorf("expected dropped statements at %q, got %q", expected, dropped)
+	}
+	if !reflect.DeepEqual(report.Functions, []string{"g"}) {
+		t.Errorf("expected dropped functions [g], got %q", report.Functions)
+	}
+
+	compiler.Options.Library = true
+	if _, err := compiler.Compile(`function g() i32 { return 2 printf(1) } function f() i32 { return g() }`); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Statements) != 1 || len(report.Functions) != 0 {
+		t.Errorf("expected only the statement to be dropped from the library, got %v", report)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	type entry struct {
+		Severity lang.Severity
+		Code     lang.MessageID
+		Line     int
+		Column   int
+		Message  string
+	}
+	tests := []struct {
+		input    string
+		options  lang.Options
+		expected []entry
+	}{
+		{`function f(a i32) i32 { let u = 1 return 2 } printf(cuont)`, lang.Options{WarnUnused: true, TypeCheck: true}, []entry{
+			{lang.SeverityError, "variable_not_found", 1, 53, "1:53: variable not found in scope: cuont"},
+		}},
+		{`function f(a i32) i32 { let u = 1 return 2 } printf(f(1))`, lang.Options{WarnUnused: true, Budget: lang.Budget{Instructions: 1, Warn: true}}, []entry{
+			{lang.SeverityWarning, "unused_parameter", 1, 12, "1:12: parameter a of function f is never read"},
+			{lang.SeverityWarning, "unused_variable", 1, 25, "1:25: variable u is never read"},
+			{lang.SeverityWarning, "instruction_budget", 0, 0, "program has 6 instructions, exceeding the budget of 1"},
+		}},
+		{`let x = (1`, lang.Options{}, []entry{
+			{lang.SeverityError, "expected_value", 1, 9, "1:9: expected value at position 3"},
+		}},
+		{`function f() i32 { let x = 1 } function f() i32 { return 2 }`, lang.Options{TypeCheck: true, Locale: lang.LocaleGerman}, []entry{
+			{lang.SeverityError, "missing_return", 1, 1, "1:1: fehlendes return am Ende der Funktion f"},
+			{lang.SeverityError, "duplicate_function", 1, 32, "1:32: doppelte Deklaration der Funktion f, zuerst deklariert bei 1:1"},
+		}},
+		{`printf(g())`, lang.Options{}, []entry{
+			{lang.SeverityError, "caller_not_found", 0, 0, "caller not found in scope: g"},
+		}},
+	}
+	for _, test := range tests {
+		var diagnostics lang.Diagnostics
+		compiler := lang.Compiler{Hooks: lang.Hooks{OnDiagnostic: diagnostics.Report}, Options: test.options}
+		_, err := compiler.Compile(test.input)
+		var actual []entry
+		for _, d := range diagnostics.List() {
+			actual = append(actual, entry{d.Severity, d.Code(), d.From.Line, d.From.Column, d.Error()})
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("expected the diagnostics %v for %q, got %v", test.expected, test.input, actual)
+		}
+		if diagnostics.HasErrors() != (err != nil) {
+			t.Errorf("expected the collector to have errors if the compilation of %q fails, got %v", test.input, err)
+		}
+	}
+}
+
+func TestMarshalDiagnostics(t *testing.T) {
+	var diagnostics lang.Diagnostics
+	compiler := lang.Compiler{
+		Hooks:   lang.Hooks{OnDiagnostic: diagnostics.Report},
+		Options: lang.Options{WarnUnused: true, Budget: lang.Budget{Instructions: 1, Warn: true}, Locale: lang.LocaleGerman},
+	}
+	if _, err := compiler.Compile(`let x = 1 printf(2)`); err != nil {
+		t.Fatal(err)
+	}
+	data, err := lang.MarshalDiagnostics("main.gusty", diagnostics.List())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"file":"main.gusty","range":{"start":{"line":1,"column":1},"end":{"line":1,"column":10}},"severity":"warning","code":"unused_variable","message":"Variable x wird nie gelesen"},` +
+		`{"file":"main.gusty","severity":"warning","code":"instruction_budget","message":"Programm hat 2 Anweisungen und überschreitet das Budget von 1"}]`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	if data, err := lang.MarshalDiagnostics("", nil); err != nil || string(data) != "[]" {
+		t.Errorf("expected an empty array, got %s %v", data, err)
+	}
+}
+
+func TestLibrary(t *testing.T) {
+	compiler := lang.Compiler{Options: lang.Options{Library: true, WholeProgram: true}}
+	input := `let limit: i64 = 10 struct Point { x i32 y i32 } function sum(p Point) i32 { return p.x + p.y } function main() i32 { return 0 }`
+	actualLvmIR, err := compiler.Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLvmIR), "library")
+
+	for _, input := range []string{`printf(1)`, `function f() i32 { return 1 } let x = f()`, `return 1`} {
+		if _, err := compiler.Compile(input); err == nil {
+			t.Errorf("expected top-level statement error for %q in library mode", input)
+		}
+	}
+}
+
+func TestGarbageCollection(t *testing.T) {
+	compiler := lang.Compiler{Options: lang.Options{GarbageCollection: true}}
+	input := `function churn(n i32) i32 { let s: []i64 = [] s = append(s, 1, 2, 3) let t = "abc" + "def" let p = new(i64) free(p) return n } let keep: []i32 = [1, 2] let greeting = "hello " + "world" churn(1) println(greeting) printf(len(keep))`
+	actualLvmIR, err := compiler.Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLvmIR), "garbage_collection")
+
+	if _, err := compiler.Compile(`function work() {} spawn work()`); err == nil {
+		t.Error("expected spawn error with the garbage collector")
+	}
+}
+
+func TestConstantCalls(t *testing.T) {
+	var diagnostics []string
+	compiler := lang.Compiler{
+		Hooks: lang.Hooks{
+			OnDiagnostic: func(phase lang.Phase, err error) {
+				if phase == lang.PhaseConstantCalls {
+					diagnostics = append(diagnostics, err.Error())
+				}
+			},
+		},
+		Options: lang.Options{FoldConstantCalls: true, EvaluationSteps: 100},
+	}
+
+	input := `function double(a i32) i32 { return a + a } function shifted(a i32, b i32) i32 { let c = a << b return c >> 1 } function pick(a bool, b i32) i32 { return a ? double(b) : b } function wide(a i32) i64 { return a as i64 << 40 } function small(a i8) i8 { return a + a } function truth(a bool) bool { return a } function loop(a i32) i32 { return loop(a) } printf(double(21)) printf(shifted(3, 4)) printf(pick(true, 5)) printf(wide(1)) printf(small(100) as i32) printf(truth(false) ? 1 : 2) let x = 3 printf(double(x)) printf(loop(1))`
+	actualLvmIR, err := compiler.Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLvmIR), "constant_calls")
+
+	expected := []string{"cannot evaluate call of loop at compile time: evaluation stopped after 100 steps"}
+	if !reflect.DeepEqual(diagnostics, expected) {
+		t.Errorf("expected diagnostics %q, got %q", expected, diagnostics)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	matrix := lang.Conformance(lang.LLVMBackend{})
+	if len(matrix.Backends) != 1 || matrix.Backends[0] != "llvm" {
+		t.Fatalf("expected the llvm backend, got %v", matrix.Backends)
+	}
+
+	tokens := make(map[string]bool)
+	for _, token := range matrix.Tokens {
+		tokens[token] = true
+	}
+	for _, feature := range matrix.Features {
+		if !feature.Supported["llvm"] {
+			t.Errorf("expected feature %s to be supported, got %s", feature.Name, feature.Errors["llvm"])
+		}
+		for _, token := range feature.Tokens {
+			if !tokens[token] {
+				t.Errorf("expected token %q of feature %s in the feature matrix", token, feature.Name)
+			}
+		}
+	}
+
+	for _, group := range matrix.Groups("llvm") {
+		if group.Passed != group.Total {
+			t.Errorf("expected every feature of group %s to pass, got %d of %d", group.Group, group.Passed, group.Total)
+		}
+	}
+	if groups := matrix.Groups("interpreter"); len(groups) == 0 || groups[0].Passed != 0 {
+		t.Errorf("expected no features of an unknown backend to pass, got %+v", groups)
+	}
+}
+
+// listedFunctions holds the names of the functions the test-list-functions pass saw.
+var listedFunctions []string
+
+func TestCompilerPasses(t *testing.T) {
+	registerPass(t, "test-print-answer", lang.Pass{
+		AST: func(nodes []lang.Node) ([]lang.Node, error) {
+			answer, err := lang.Parse(lang.Tokenize(`printf(42)`))
+			if err != nil {
+				return nil, err
+			}
+			return append(nodes, answer...), nil
+		},
+	})
+	registerPass(t, "test-list-functions", lang.Pass{
+		Module: func(module llvm.Module) error {
+			for function := module.FirstFunction(); !function.IsNil(); function = llvm.NextFunction(function) {
+				listedFunctions = append(listedFunctions, function.Name())
+			}
+			return nil
+		},
+	})
+	registerPass(t, "test-reject", lang.Pass{
+		AST: func(nodes []lang.Node) ([]lang.Node, error) {
+			return nil, errors.New("rejected")
+		},
+	})
+
+	var phases []lang.Phase
+	listedFunctions = nil
+	compiler := lang.Compiler{
+		Options: lang.Options{Passes: []string{"test-print-answer", "test-list-functions"}},
+		Hooks: lang.Hooks{
+			OnPhaseStart: func(phase lang.Phase) {
+				phases = append(phases, phase)
+			},
+		},
+	}
+	actualLlvmIR, err := compiler.Compile(`function f() {} f()`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(actualLlvmIR, "i32 42") {
+		t.Errorf("expected the AST pass to add printf(42), got\n%s", actualLlvmIR)
+	}
+	if !reflect.DeepEqual(listedFunctions, []string{"main", "printf", "f"}) {
+		t.Errorf("expected the module pass to see the functions main, printf and f, got %v", listedFunctions)
+	}
+	expectedPhases := []lang.Phase{lang.PhaseTokenize, lang.PhaseParse, lang.PhaseASTPasses, lang.PhaseGenerate, lang.PhaseModulePasses}
+	if !reflect.DeepEqual(phases, expectedPhases) {
+		t.Errorf("expected phases %v, got %v", expectedPhases, phases)
+	}
+
+	for _, names := range [][]string{{"test-unknown"}, {"test-print-answer", "test-reject"}} {
+		compiler := lang.Compiler{Options: lang.Options{Passes: names}}
+		if _, err := compiler.Compile(`printf(1)`); err == nil {
+			t.Errorf("expected passes %v to fail", names)
+		}
+	}
+
+	registered := lang.Passes()
+	for _, name := range []string{"test-list-functions", "test-print-answer", "test-reject"} {
+		found := false
+		for _, passName := range registered {
+			found = found || passName == name
+		}
+		if !found {
+			t.Errorf("expected pass %s to be registered, got %v", name, registered)
+		}
+	}
+}
+
+// registerPass registers the pass unless a pass with the name is registered already, e.g. by a
+// previous run of the test.
+func registerPass(t *testing.T, name string, pass lang.Pass) {
+	for _, registered := range lang.Passes() {
+		if registered == name {
+			return
+		}
+	}
+	lang.RegisterPass(name, pass)
+}
@@ -0,0 +1,36 @@
+package integration
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/donutloop/gusty/pkg/examples"
+)
+
+func TestExamples(t *testing.T) {
+	for _, tool := range []string{examples.LLC, examples.CC} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s is required to run the examples: %v", tool, err)
+		}
+	}
+
+	programs, err := examples.Load("../examples")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) == 0 {
+		t.Fatal("expected examples")
+	}
+
+	for _, example := range programs {
+		t.Run(example.Name, func(t *testing.T) {
+			output, err := example.Run()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if output != example.Expected {
+				t.Errorf("expected output\n%s\ngot\n%s", example.Expected, output)
+			}
+		})
+	}
+}
@@ -1,14 +1,11 @@
 ; ModuleID = 'main'
 source_filename = "main"
 
-@format_string = constant [3 x i8] c"%d\0A"
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
 
 define i32 @main() {
 entry:
-  %"9b3c24fa-f1d5-4d41-9fd1-0637244ce4f3" = alloca i32, align 4
-  store i32 84, ptr %"9b3c24fa-f1d5-4d41-9fd1-0637244ce4f3", align 4
-  %0 = load ptr, ptr %"9b3c24fa-f1d5-4d41-9fd1-0637244ce4f3", align 8
-  %1 = call i32 (ptr, ...) @printf(ptr @format_string, ptr %0)
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 84)
   ret i32 0
 }
 
@@ -0,0 +1,44 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_argc = internal global i32 0
+@__gusty_argv = internal global ptr null
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@main.i = internal global i8 2, align 1
+@__gusty_format_string_println_s = constant [4 x i8] c"%s\0A\00"
+
+define i32 @main(i32 %0, ptr %1) {
+entry:
+  store i32 %0, ptr @__gusty_argc, align 4
+  store ptr %1, ptr @__gusty_argv, align 8
+  %argc = load i32, ptr @__gusty_argc, align 4
+  %2 = sext i32 %argc to i64
+  %n = alloca i64, align 8
+  store i64 %2, ptr %n, align 4
+  %nValue = load i64, ptr %n, align 4
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %nValue)
+  %argv = load ptr, ptr @__gusty_argv, align 8
+  %4 = getelementptr inbounds ptr, ptr %argv, i64 0
+  %arg = load ptr, ptr %4, align 8
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_s, ptr %arg)
+  %iValue = load i8, ptr @main.i, align 1
+  %6 = sext i8 %iValue to i64
+  %argv1 = load ptr, ptr @__gusty_argv, align 8
+  %7 = getelementptr inbounds ptr, ptr %argv1, i64 %6
+  %arg2 = load ptr, ptr %7, align 8
+  %8 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_s, ptr %arg2)
+  %9 = call ptr @first()
+  %10 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_s, ptr %9)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define ptr @first() {
+entry:
+  %argv = load ptr, ptr @__gusty_argv, align 8
+  %0 = getelementptr inbounds ptr, ptr %argv, i64 1
+  %arg = load ptr, ptr %0, align 8
+  ret ptr %arg
+}
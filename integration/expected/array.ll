@@ -0,0 +1,52 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.a = internal global [4 x i32] [i32 1, i32 2, i32 3, i32 4], align 4
+@main.b = internal global [2 x double] [double 1.500000e+00, double 2.500000e+00], align 8
+@main.c = internal global [3 x i64] [i64 7, i64 0, i64 0], align 8
+@main.x = internal global i32 5, align 4
+@main.h = internal global [2 x [2 x i1]] [[2 x i1] [i1 true, i1 false], [2 x i1] [i1 false, i1 true]], align 1
+
+define i32 @main() {
+entry:
+  %aValue = load [4 x i32], ptr @main.a, align 4
+  %0 = call [4 x i32] @pass([4 x i32] %aValue)
+  %d = alloca [4 x i32], align 4
+  store [4 x i32] %0, ptr %d, align 4
+  %cValue = load [3 x i64], ptr @main.c, align 4
+  %1 = insertvalue [2 x [3 x i64]] zeroinitializer, [3 x i64] %cValue, 0
+  %cValue1 = load [3 x i64], ptr @main.c, align 4
+  %2 = insertvalue [2 x [3 x i64]] %1, [3 x i64] %cValue1, 1
+  %e = alloca [2 x [3 x i64]], align 8
+  store [2 x [3 x i64]] %2, ptr %e, align 4
+  %3 = call [2 x i8] @zero()
+  %f = alloca [2 x i8], align 1
+  store [2 x i8] %3, ptr %f, align 1
+  %xValue = load i32, ptr @main.x, align 4
+  %4 = insertvalue [2 x i32] zeroinitializer, i32 %xValue, 0
+  %xValue2 = load i32, ptr @main.x, align 4
+  %5 = add i32 %xValue2, 1
+  %6 = insertvalue [2 x i32] %4, i32 %5, 1
+  %g = alloca [2 x i32], align 4
+  store [2 x i32] %6, ptr %g, align 4
+  %xValue3 = load i32, ptr @main.x, align 4
+  %7 = sitofp i32 %xValue3 to float
+  %8 = insertvalue [2 x float] zeroinitializer, float %7, 0
+  %9 = insertvalue [2 x float] %8, float 1.000000e+00, 1
+  %i = alloca [2 x float], align 4
+  store [2 x float] %9, ptr %i, align 4
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define [2 x i8] @zero() {
+entry:
+  ret [2 x i8] zeroinitializer
+}
+
+define [4 x i32] @pass([4 x i32] %0) {
+entry:
+  ret [4 x i32] %0
+}
@@ -0,0 +1,42 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@__gusty_format_string_f64 = constant [4 x i8] c"%f\0A\00"
+@main.x = internal global i32 300, align 4
+
+define i32 @main() {
+entry:
+  %xValue = load i32, ptr @main.x, align 4
+  %0 = sext i32 %xValue to i64
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %0)
+  %xValue1 = load i32, ptr @main.x, align 4
+  %2 = trunc i32 %xValue1 to i8
+  %3 = sext i8 %2 to i64
+  %4 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %3)
+  %xValue2 = load i32, ptr @main.x, align 4
+  %5 = sitofp i32 %xValue2 to double
+  %6 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %5)
+  %7 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 1)
+  %8 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 2)
+  %xValue3 = load i32, ptr @main.x, align 4
+  %9 = sext i32 %xValue3 to i64
+  %xValue4 = load i32, ptr @main.x, align 4
+  %10 = sitofp i32 %xValue4 to float
+  call void @show(i64 %9, float %10)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define void @show(i64 %0, float %1) {
+entry:
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %0)
+  %3 = fpext float %1 to double
+  %4 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %3)
+  %5 = trunc i64 %0 to i8
+  %6 = sext i8 %5 to i32
+  %7 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %6)
+  ret void
+}
@@ -0,0 +1,69 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_string = private unnamed_addr constant [6 x i8] c"gusty\00", align 1
+@main.name = internal global ptr @__gusty_string, align 8
+@main.n = internal global i32 5, align 4
+@main.big = internal global i64 3, align 8
+@main.b = internal global i1 true, align 1
+@__gusty_string.1 = private unnamed_addr constant [6 x i8] c"gusty\00", align 1
+@__gusty_string.2 = private unnamed_addr constant [3 x i8] c"go\00", align 1
+@__gusty_string.3 = private unnamed_addr constant [4 x i8] c"abc\00", align 1
+@__gusty_string.4 = private unnamed_addr constant [4 x i8] c"abd\00", align 1
+
+define i32 @main() {
+entry:
+  %nameValue = load ptr, ptr @main.name, align 8
+  %0 = call i1 @same(ptr %nameValue, ptr @__gusty_string.1)
+  %1 = zext i1 %0 to i32
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %1)
+  %nameValue1 = load ptr, ptr @main.name, align 8
+  %order = call i32 @strcmp(ptr %nameValue1, ptr @__gusty_string.2)
+  %3 = icmp ne i32 %order, 0
+  %4 = zext i1 %3 to i32
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %4)
+  %order2 = call i32 @strcmp(ptr @__gusty_string.3, ptr @__gusty_string.4)
+  %6 = icmp slt i32 %order2, 0
+  %7 = zext i1 %6 to i32
+  %8 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %7)
+  %nValue = load i32, ptr @main.n, align 4
+  %9 = add i32 %nValue, 1
+  %10 = icmp sge i32 %9, 6
+  br i1 %10, label %conditional_true, label %conditional_false
+
+conditional_true:                                 ; preds = %entry
+  br label %conditional_done
+
+conditional_false:                                ; preds = %entry
+  br label %conditional_done
+
+conditional_done:                                 ; preds = %conditional_false, %conditional_true
+  %conditional = phi i32 [ 7, %conditional_true ], [ 8, %conditional_false ]
+  %11 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %conditional)
+  %12 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 0)
+  %bValue = load i1, ptr @main.b, align 1
+  %13 = icmp eq i1 %bValue, false
+  %14 = zext i1 %13 to i32
+  %15 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %14)
+  %bigValue = load i64, ptr @main.big, align 4
+  %16 = icmp sle i64 %bigValue, 2
+  %17 = zext i1 %16 to i32
+  %18 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %17)
+  %nValue3 = load i32, ptr @main.n, align 4
+  %19 = icmp sgt i32 %nValue3, 4
+  %20 = zext i1 %19 to i32
+  %21 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %20)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i1 @same(ptr %0, ptr %1) {
+entry:
+  %order = call i32 @strcmp(ptr %0, ptr %1)
+  %2 = icmp eq i32 %order, 0
+  ret i1 %2
+}
+
+declare i32 @strcmp(ptr, ptr)
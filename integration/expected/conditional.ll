@@ -0,0 +1,105 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.t = internal global i1 true, align 1
+@main.f = internal global i1 false, align 1
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@__gusty_format_string_f64 = constant [4 x i8] c"%f\0A\00"
+
+define i32 @main() {
+entry:
+  %tValue = load i1, ptr @main.t, align 1
+  br i1 %tValue, label %conditional_true, label %conditional_false
+
+conditional_true:                                 ; preds = %entry
+  br label %conditional_done
+
+conditional_false:                                ; preds = %entry
+  br label %conditional_done
+
+conditional_done:                                 ; preds = %conditional_false, %conditional_true
+  %conditional = phi i32 [ 1, %conditional_true ], [ 2, %conditional_false ]
+  %x = alloca i32, align 4
+  store i32 %conditional, ptr %x, align 4
+  %fValue = load i1, ptr @main.f, align 1
+  br i1 %fValue, label %conditional_true1, label %conditional_false2
+
+conditional_true1:                                ; preds = %conditional_done
+  br label %conditional_done3
+
+conditional_false2:                               ; preds = %conditional_done
+  %tValue4 = load i1, ptr @main.t, align 1
+  br i1 %tValue4, label %conditional_true5, label %conditional_false6
+
+conditional_true5:                                ; preds = %conditional_false2
+  br label %conditional_done7
+
+conditional_false6:                               ; preds = %conditional_false2
+  br label %conditional_done7
+
+conditional_done7:                                ; preds = %conditional_false6, %conditional_true5
+  %conditional8 = phi i8 [ 3, %conditional_true5 ], [ 4, %conditional_false6 ]
+  br label %conditional_done3
+
+conditional_done3:                                ; preds = %conditional_done7, %conditional_true1
+  %conditional9 = phi i8 [ 1, %conditional_true1 ], [ %conditional8, %conditional_done7 ]
+  %y = alloca i8, align 1
+  store i8 %conditional9, ptr %y, align 1
+  %xValue = load i32, ptr %x, align 4
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %xValue)
+  %yValue = load i8, ptr %y, align 1
+  %1 = sext i8 %yValue to i32
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %1)
+  %fValue10 = load i1, ptr @main.f, align 1
+  %3 = call i64 @pick(i1 %fValue10, i64 9)
+  %4 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %3)
+  %tValue11 = load i1, ptr @main.t, align 1
+  br i1 %tValue11, label %conditional_true12, label %conditional_false13
+
+conditional_true12:                               ; preds = %conditional_done3
+  %xValue15 = load i32, ptr %x, align 4
+  %5 = add i32 %xValue15, 10
+  br label %conditional_done14
+
+conditional_false13:                              ; preds = %conditional_done3
+  %xValue16 = load i32, ptr %x, align 4
+  br label %conditional_done14
+
+conditional_done14:                               ; preds = %conditional_false13, %conditional_true12
+  %conditional17 = phi i32 [ %5, %conditional_true12 ], [ %xValue16, %conditional_false13 ]
+  %6 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %conditional17)
+  %fValue18 = load i1, ptr @main.f, align 1
+  br i1 %fValue18, label %conditional_true19, label %conditional_false20
+
+conditional_true19:                               ; preds = %conditional_done14
+  br label %conditional_done21
+
+conditional_false20:                              ; preds = %conditional_done14
+  br label %conditional_done21
+
+conditional_done21:                               ; preds = %conditional_false20, %conditional_true19
+  %conditional22 = phi double [ 2.500000e+00, %conditional_true19 ], [ 1.000000e+00, %conditional_false20 ]
+  %z = alloca double, align 8
+  store double %conditional22, ptr %z, align 8
+  %zValue = load double, ptr %z, align 8
+  %7 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %zValue)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i64 @pick(i1 %0, i64 %1) {
+entry:
+  br i1 %0, label %conditional_true, label %conditional_false
+
+conditional_true:                                 ; preds = %entry
+  br label %conditional_done
+
+conditional_false:                                ; preds = %entry
+  br label %conditional_done
+
+conditional_done:                                 ; preds = %conditional_false, %conditional_true
+  %conditional = phi i64 [ %1, %conditional_true ], [ 7, %conditional_false ]
+  ret i64 %conditional
+}
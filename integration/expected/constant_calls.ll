@@ -0,0 +1,91 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@main.x = internal global i32 3, align 4
+
+define i32 @main() {
+entry:
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 42)
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 24)
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 10)
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 1099511627776)
+  %4 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 -56)
+  br i1 false, label %conditional_true, label %conditional_false
+
+conditional_true:                                 ; preds = %entry
+  br label %conditional_done
+
+conditional_false:                                ; preds = %entry
+  br label %conditional_done
+
+conditional_done:                                 ; preds = %conditional_false, %conditional_true
+  %conditional = phi i32 [ 1, %conditional_true ], [ 2, %conditional_false ]
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %conditional)
+  %xValue = load i32, ptr @main.x, align 4
+  %6 = call i32 @double(i32 %xValue)
+  %7 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %6)
+  %8 = call i32 @loop(i32 1)
+  %9 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %8)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @double(i32 %0) {
+entry:
+  %1 = add i32 %0, %0
+  ret i32 %1
+}
+
+define i32 @shifted(i32 %0, i32 %1) {
+entry:
+  %shift_amount = and i32 %1, 31
+  %2 = shl i32 %0, %shift_amount
+  %c = alloca i32, align 4
+  store i32 %2, ptr %c, align 4
+  %cValue = load i32, ptr %c, align 4
+  %3 = ashr i32 %cValue, 1
+  ret i32 %3
+}
+
+define i32 @pick(i1 %0, i32 %1) {
+entry:
+  br i1 %0, label %conditional_true, label %conditional_false
+
+conditional_true:                                 ; preds = %entry
+  %2 = call i32 @double(i32 %1)
+  br label %conditional_done
+
+conditional_false:                                ; preds = %entry
+  br label %conditional_done
+
+conditional_done:                                 ; preds = %conditional_false, %conditional_true
+  %conditional = phi i32 [ %2, %conditional_true ], [ %1, %conditional_false ]
+  ret i32 %conditional
+}
+
+define i64 @wide(i32 %0) {
+entry:
+  %1 = sext i32 %0 to i64
+  %2 = shl i64 %1, 40
+  ret i64 %2
+}
+
+define i8 @small(i8 %0) {
+entry:
+  %1 = add i8 %0, %0
+  ret i8 %1
+}
+
+define i1 @truth(i1 %0) {
+entry:
+  ret i1 %0
+}
+
+define i32 @loop(i32 %0) {
+entry:
+  %1 = call i32 @loop(i32 %0)
+  ret i32 %1
+}
@@ -0,0 +1,13 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.x = internal global i32 1, align 4
+
+define i32 @main() {
+entry:
+  store i32 2, ptr @main.x, align 4
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
@@ -0,0 +1,19 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i32 @f(i32 1)
+  %x = alloca i32, align 4
+  store i32 %0, ptr %x, align 4
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @f(i32 %0) {
+entry:
+  ret i32 %0
+}
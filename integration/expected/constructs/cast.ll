@@ -0,0 +1,16 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.x = internal global i32 1, align 4
+
+define i32 @main() {
+entry:
+  %xValue = load i32, ptr @main.x, align 4
+  %0 = sext i32 %xValue to i64
+  %y = alloca i64, align 8
+  store i64 %0, ptr %y, align 4
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
@@ -0,0 +1,25 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.t = internal global i1 true, align 1
+
+define i32 @main() {
+entry:
+  %tValue = load i1, ptr @main.t, align 1
+  br i1 %tValue, label %conditional_true, label %conditional_false
+
+conditional_true:                                 ; preds = %entry
+  br label %conditional_done
+
+conditional_false:                                ; preds = %entry
+  br label %conditional_done
+
+conditional_done:                                 ; preds = %conditional_false, %conditional_true
+  %conditional = phi i32 [ 1, %conditional_true ], [ 2, %conditional_false ]
+  %x = alloca i32, align 4
+  store i32 %conditional, ptr %x, align 4
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
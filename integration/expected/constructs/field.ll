@@ -0,0 +1,17 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%P = type { i32 }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.p = internal global %P { i32 1 }, align 4
+
+define i32 @main() {
+entry:
+  %0 = load i32, ptr @main.p, align 4
+  %x = alloca i32, align 4
+  store i32 %0, ptr %x, align 4
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
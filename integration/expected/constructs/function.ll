@@ -0,0 +1,16 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define void @f() {
+entry:
+  ret void
+}
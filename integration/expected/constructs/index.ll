@@ -0,0 +1,15 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.a = internal global [2 x i32] [i32 1, i32 2], align 4
+
+define i32 @main() {
+entry:
+  %0 = load i32, ptr getelementptr inbounds ([2 x i32], ptr @main.a, i64 0, i64 1), align 4
+  %x = alloca i32, align 4
+  store i32 %0, ptr %x, align 4
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
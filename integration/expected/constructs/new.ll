@@ -0,0 +1,17 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call ptr @malloc(i64 4)
+  store i32 0, ptr %0, align 4
+  %p = alloca ptr, align 8
+  store ptr %0, ptr %p, align 8
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+declare ptr @malloc(i64)
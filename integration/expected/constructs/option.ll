@@ -0,0 +1,24 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.o = internal global { i1, i32 } { i1 true, i32 1 }, align 4
+
+define i32 @main() {
+entry:
+  %oValue = load { i1, i32 }, ptr @main.o, align 4
+  %present = extractvalue { i1, i32 } %oValue, 0
+  %value = extractvalue { i1, i32 } %oValue, 1
+  br i1 %present, label %option_done, label %option_none
+
+option_none:                                      ; preds = %entry
+  br label %option_done
+
+option_done:                                      ; preds = %option_none, %entry
+  %unwrapped = phi i32 [ %value, %entry ], [ 2, %option_none ]
+  %x = alloca i32, align 4
+  store i32 %unwrapped, ptr %x, align 4
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
@@ -0,0 +1,17 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.x = internal global i32 1, align 4
+@main.p = internal global ptr @main.x, align 8
+
+define i32 @main() {
+entry:
+  %pValue = load ptr, ptr @main.p, align 8
+  %0 = load i32, ptr %pValue, align 4
+  %y = alloca i32, align 4
+  store i32 %0, ptr %y, align 4
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
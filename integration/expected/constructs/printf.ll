@@ -0,0 +1,12 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 1)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
@@ -0,0 +1,23 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call ptr @malloc(i64 mul (i64 ptrtoint (ptr getelementptr (i32, ptr null, i32 1) to i64), i64 2))
+  %1 = getelementptr inbounds i32, ptr %0, i64 0
+  store i32 1, ptr %1, align 4
+  %2 = getelementptr inbounds i32, ptr %0, i64 1
+  store i32 2, ptr %2, align 4
+  %3 = insertvalue { ptr, i64, i64 } zeroinitializer, ptr %0, 0
+  %4 = insertvalue { ptr, i64, i64 } %3, i64 2, 1
+  %5 = insertvalue { ptr, i64, i64 } %4, i64 2, 2
+  %s = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } %5, ptr %s, align 8
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+declare ptr @malloc(i64)
@@ -0,0 +1,14 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%P = type { i32 }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.p = internal global %P { i32 1 }, align 4
+
+define i32 @main() {
+entry:
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
@@ -0,0 +1,45 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i32 @f()
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @f() {
+entry:
+  %for_init_i = alloca i32, align 4
+  store i32 0, ptr %for_init_i, align 4
+  br label %loop
+
+loop:                                             ; preds = %after_return, %entry
+  %iValue = load i32, ptr %for_init_i, align 4
+  ret i32 %iValue
+
+end:                                              ; preds = %after_return
+  ret i32 1
+
+after_return:                                     ; No predecessors!
+  %for_init_i_value = load i32, ptr %for_init_i, align 4
+  %for_init_i_value_updated = add i32 %for_init_i_value, 1
+  store i32 %for_init_i_value_updated, ptr %for_init_i, align 4
+  %loopCond = icmp ule i32 %for_init_i_value_updated, 3
+  br i1 %loopCond, label %loop, label %end
+}
+
+define i32 @h() {
+entry:
+  ret i32 3
+}
+
+define internal i32 @__gusty_main() {
+entry:
+  %0 = call i32 @h()
+  ret i32 %0
+}
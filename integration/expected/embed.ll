@@ -0,0 +1,36 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_embed_greeting = private constant [3 x i8] c"hi\0A"
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %greetingValue = load [3 x i8], ptr @__gusty_embed_greeting, align 1
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 3)
+  %1 = call i8 @first()
+  %2 = sext i8 %1 to i32
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %2)
+  %greetingValue1 = load [3 x i8], ptr @__gusty_embed_greeting, align 1
+  %copy = alloca [3 x i8], align 1
+  store [3 x i8] %greetingValue1, ptr %copy, align 1
+  %4 = getelementptr inbounds [3 x i8], ptr %copy, i64 0, i64 0
+  store i8 72, ptr %4, align 1
+  %5 = getelementptr inbounds [3 x i8], ptr %copy, i64 0, i64 0
+  %6 = load i8, ptr %5, align 1
+  %7 = sext i8 %6 to i32
+  %8 = load i8, ptr getelementptr inbounds ([3 x i8], ptr @__gusty_embed_greeting, i64 0, i64 1), align 1
+  %9 = sext i8 %8 to i32
+  %10 = add i32 %7, %9
+  %11 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %10)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i8 @first() {
+entry:
+  %0 = load i8, ptr @__gusty_embed_greeting, align 1
+  ret i8 %0
+}
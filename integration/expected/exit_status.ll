@@ -0,0 +1,29 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.code = internal global i32 3, align 4
+
+define i32 @main() {
+entry:
+  %codeValue = load i32, ptr @main.code, align 4
+  call void @check(i32 %codeValue)
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 1)
+  %codeValue1 = load i32, ptr @main.code, align 4
+  %1 = add i32 %codeValue1, 1
+  ret i32 %1
+}
+
+declare i32 @printf(ptr, ...)
+
+define void @check(i32 %0) {
+entry:
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  call void @exit(i32 %0)
+  unreachable
+}
+
+; Function Attrs: noreturn
+declare void @exit(i32) #0
+
+attributes #0 = { noreturn }
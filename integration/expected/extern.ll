@@ -0,0 +1,56 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_string = private unnamed_addr constant [7 x i8] c"hello \00", align 1
+@__gusty_string.1 = private unnamed_addr constant [6 x i8] c"gusty\00", align 1
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@__gusty_string.2 = private unnamed_addr constant [4 x i8] c"bye\00", align 1
+
+define i32 @main() {
+entry:
+  call void @srand(i32 1)
+  call void @greet(ptr @__gusty_string.1)
+  %0 = call i64 @labs(i64 -7)
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %0)
+  %2 = call i32 @puts(ptr @__gusty_string.2)
+  %n = alloca i32, align 4
+  store i32 %2, ptr %n, align 4
+  %nValue = load i32, ptr %n, align 4
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %nValue)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+declare i32 @puts(ptr)
+
+declare i64 @labs(i64)
+
+declare void @srand(i32)
+
+define void @greet(ptr %0) {
+entry:
+  %concat = call ptr @__gusty_string_concat(ptr @__gusty_string, ptr %0)
+  %1 = call i32 @puts(ptr %concat)
+  ret void
+}
+
+define internal ptr @__gusty_string_concat(ptr %0, ptr %1) {
+entry:
+  %left_length = call i64 @strlen(ptr %0)
+  %right_length = call i64 @strlen(ptr %1)
+  %right_size = add i64 %right_length, 1
+  %size = add i64 %left_length, %right_size
+  %data = call ptr @malloc(i64 %size)
+  %2 = call ptr @memcpy(ptr %data, ptr %0, i64 %left_length)
+  %end = getelementptr inbounds i8, ptr %data, i64 %left_length
+  %3 = call ptr @memcpy(ptr %end, ptr %1, i64 %right_size)
+  ret ptr %data
+}
+
+declare i64 @strlen(ptr)
+
+declare ptr @malloc(i64)
+
+declare ptr @memcpy(ptr, ptr, i64)
@@ -0,0 +1,100 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%Point = type { i32, i32 }
+%Line = type { %Point, %Point }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.p = internal global %Point { i32 1, i32 2 }, align 4
+@__gusty_format_string_f64 = constant [4 x i8] c"%f\0A\00"
+
+define i32 @main() {
+entry:
+  store i32 3, ptr @main.p, align 4
+  %0 = load i32, ptr @main.p, align 4
+  %1 = load i32, ptr getelementptr inbounds (%Point, ptr @main.p, i32 0, i32 1), align 4
+  %2 = add i32 %0, %1
+  store i32 %2, ptr getelementptr inbounds (%Point, ptr @main.p, i32 0, i32 1), align 4
+  %pValue = load %Point, ptr @main.p, align 4
+  %3 = insertvalue %Line zeroinitializer, %Point %pValue, 0
+  %4 = insertvalue %Line %3, %Point { i32 10, i32 0 }, 1
+  %l = alloca %Line, align 4
+  store %Line %4, ptr %l, align 4
+  %5 = getelementptr inbounds %Line, ptr %l, i32 0, i32 1
+  %6 = getelementptr inbounds %Point, ptr %5, i32 0, i32 1
+  store i32 4, ptr %6, align 4
+  %lValue = load %Line, ptr %l, align 4
+  %lValue1 = load %Line, ptr %l, align 4
+  %7 = call ptr @malloc(i64 mul (i64 ptrtoint (ptr getelementptr (%Line, ptr null, i32 1) to i64), i64 2))
+  %8 = getelementptr inbounds %Line, ptr %7, i64 0
+  store %Line %lValue, ptr %8, align 4
+  %9 = getelementptr inbounds %Line, ptr %7, i64 1
+  store %Line %lValue1, ptr %9, align 4
+  %10 = insertvalue { ptr, i64, i64 } zeroinitializer, ptr %7, 0
+  %11 = insertvalue { ptr, i64, i64 } %10, i64 2, 1
+  %12 = insertvalue { ptr, i64, i64 } %11, i64 2, 2
+  %ls = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } %12, ptr %ls, align 8
+  %13 = load { ptr, i64, i64 }, ptr %ls, align 8
+  %14 = extractvalue { ptr, i64, i64 } %13, 0
+  %15 = getelementptr inbounds %Line, ptr %14, i64 1
+  %16 = getelementptr inbounds %Line, ptr %15, i32 0, i32 0
+  %17 = getelementptr inbounds %Point, ptr %16, i32 0, i32 0
+  store i32 7, ptr %17, align 4
+  %pValue2 = load %Point, ptr @main.p, align 4
+  %18 = insertvalue [2 x %Point] zeroinitializer, %Point %pValue2, 0
+  %ps = alloca [2 x %Point], align 4
+  store [2 x %Point] %18, ptr %ps, align 4
+  %19 = getelementptr inbounds [2 x %Point], ptr %ps, i64 0, i64 1
+  %20 = getelementptr inbounds %Point, ptr %19, i32 0, i32 0
+  %21 = getelementptr inbounds [2 x %Point], ptr %ps, i64 0, i64 0
+  %22 = getelementptr inbounds %Point, ptr %21, i32 0, i32 1
+  %23 = load i32, ptr %22, align 4
+  store i32 %23, ptr %20, align 4
+  %24 = load i32, ptr @main.p, align 4
+  %25 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %24)
+  %26 = getelementptr inbounds %Line, ptr %l, i32 0, i32 1
+  %27 = getelementptr inbounds %Point, ptr %26, i32 0, i32 1
+  %28 = load i32, ptr %27, align 4
+  %29 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %28)
+  %30 = load { ptr, i64, i64 }, ptr %ls, align 8
+  %31 = extractvalue { ptr, i64, i64 } %30, 0
+  %32 = getelementptr inbounds %Line, ptr %31, i64 1
+  %33 = getelementptr inbounds %Line, ptr %32, i32 0, i32 0
+  %34 = getelementptr inbounds %Point, ptr %33, i32 0, i32 0
+  %35 = load i32, ptr %34, align 4
+  %36 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %35)
+  %37 = load { ptr, i64, i64 }, ptr %ls, align 8
+  %38 = extractvalue { ptr, i64, i64 } %37, 0
+  %39 = getelementptr inbounds %Line, ptr %38, i64 1
+  %40 = load %Line, ptr %39, align 4
+  %41 = call i32 @length(%Line %40)
+  %42 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %41)
+  %43 = alloca %Point, align 4
+  store %Point { i32 5, i32 0 }, ptr %43, align 4
+  %44 = getelementptr inbounds %Point, ptr %43, i32 0, i32 0
+  %45 = load i32, ptr %44, align 4
+  %46 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %45)
+  %47 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double 1.500000e+00)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @length(%Line %0) {
+entry:
+  %1 = alloca %Line, align 4
+  store %Line %0, ptr %1, align 4
+  %2 = getelementptr inbounds %Line, ptr %1, i32 0, i32 1
+  %3 = getelementptr inbounds %Point, ptr %2, i32 0, i32 0
+  %4 = load i32, ptr %3, align 4
+  %5 = alloca %Line, align 4
+  store %Line %0, ptr %5, align 4
+  %6 = getelementptr inbounds %Line, ptr %5, i32 0, i32 0
+  %7 = getelementptr inbounds %Point, ptr %6, i32 0, i32 0
+  %8 = load i32, ptr %7, align 4
+  %9 = add i32 %4, %8
+  ret i32 %9
+}
+
+declare ptr @malloc(i64)
@@ -1,7 +1,7 @@
 ; ModuleID = 'main'
 source_filename = "main"
 
-@format_string = constant [3 x i8] c"%d\0A"
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
 
 define i32 @main() {
 entry:
@@ -10,8 +10,8 @@ entry:
   br label %loop
 
 loop:                                             ; preds = %loop, %entry
-  %iValue = load ptr, ptr %for_init_i, align 8
-  %0 = call i32 (ptr, ...) @printf(ptr @format_string, ptr %iValue)
+  %iValue = load i32, ptr %for_init_i, align 4
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %iValue)
   %for_init_i_value = load i32, ptr %for_init_i, align 4
   %for_init_i_value_updated = add i32 %for_init_i_value, 1
   store i32 %for_init_i_value_updated, ptr %for_init_i, align 4
@@ -0,0 +1,64 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_string = private unnamed_addr constant [4 x i8] c"#%d\00", align 1
+@main.big = internal global i64 1099511627776, align 8
+@__gusty_string.1 = private unnamed_addr constant [25 x i8] c"x=%d big=%ld f=%.2f s=%s\00", align 1
+@__gusty_string.2 = private unnamed_addr constant [2 x i8] c"!\00", align 1
+@__gusty_format_string_println_s = constant [4 x i8] c"%s\0A\00"
+
+define i32 @main() {
+entry:
+  %bigValue = load i64, ptr @main.big, align 4
+  %0 = call ptr @label(i32 3)
+  %length = call i32 (ptr, i64, ptr, ...) @snprintf(ptr null, i64 0, ptr @__gusty_string.1, i32 42, i64 %bigValue, double 1.500000e+00, ptr %0)
+  %1 = sext i32 %length to i64
+  %size = add i64 %1, 1
+  %formatted = call ptr @malloc(i64 %size)
+  %2 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %formatted, i64 %size, ptr @__gusty_string.1, i32 42, i64 %bigValue, double 1.500000e+00, ptr %0)
+  %s = alloca ptr, align 8
+  store ptr %formatted, ptr %s, align 8
+  %sValue = load ptr, ptr %s, align 8
+  %length1 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr null, i64 0, ptr @__gusty_string.2)
+  %3 = sext i32 %length1 to i64
+  %size2 = add i64 %3, 1
+  %formatted3 = call ptr @malloc(i64 %size2)
+  %4 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %formatted3, i64 %size2, ptr @__gusty_string.2)
+  %concat = call ptr @__gusty_string_concat(ptr %sValue, ptr %formatted3)
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_s, ptr %concat)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define ptr @label(i32 %0) {
+entry:
+  %length = call i32 (ptr, i64, ptr, ...) @snprintf(ptr null, i64 0, ptr @__gusty_string, i32 %0)
+  %1 = sext i32 %length to i64
+  %size = add i64 %1, 1
+  %formatted = call ptr @malloc(i64 %size)
+  %2 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %formatted, i64 %size, ptr @__gusty_string, i32 %0)
+  ret ptr %formatted
+}
+
+declare i32 @snprintf(ptr, i64, ptr, ...)
+
+declare ptr @malloc(i64)
+
+define internal ptr @__gusty_string_concat(ptr %0, ptr %1) {
+entry:
+  %left_length = call i64 @strlen(ptr %0)
+  %right_length = call i64 @strlen(ptr %1)
+  %right_size = add i64 %right_length, 1
+  %size = add i64 %left_length, %right_size
+  %data = call ptr @malloc(i64 %size)
+  %2 = call ptr @memcpy(ptr %data, ptr %0, i64 %left_length)
+  %end = getelementptr inbounds i8, ptr %data, i64 %left_length
+  %3 = call ptr @memcpy(ptr %end, ptr %1, i64 %right_size)
+  ret ptr %data
+}
+
+declare i64 @strlen(ptr)
+
+declare ptr @memcpy(ptr, ptr, i64)
@@ -0,0 +1,48 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i32 @twice(i32 2)
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  %2 = call i32 @thrice(i32 2)
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %2)
+  %4 = call i32 @fail(i32 1)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+declare void @exit(i32)
+
+; Function Attrs: noreturn
+declare void @abort() #0
+
+; Function Attrs: alwaysinline
+define i32 @twice(i32 %0) #1 {
+entry:
+  %1 = add i32 %0, %0
+  ret i32 %1
+}
+
+; Function Attrs: noinline
+define i32 @thrice(i32 %0) #2 {
+entry:
+  %1 = add i32 %0, %0
+  %2 = add i32 %1, %0
+  ret i32 %2
+}
+
+; Function Attrs: noreturn
+define i32 @fail(i32 %0) #0 {
+entry:
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  call void @exit(i32 %0)
+  unreachable
+}
+
+attributes #0 = { noreturn }
+attributes #1 = { alwaysinline }
+attributes #2 = { noinline }
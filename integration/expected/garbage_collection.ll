@@ -0,0 +1,278 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_gc_stack_bottom = internal global ptr null
+@__gusty_gc_allocated = internal global i64 0
+@__gusty_gc_objects = internal global ptr null
+@__gusty_string = private unnamed_addr constant [7 x i8] c"abcdef\00", align 1
+@__gusty_string.1 = private unnamed_addr constant [12 x i8] c"hello world\00", align 1
+@main.greeting = internal global ptr @__gusty_string.1, align 8
+@__gusty_format_string_println_s = constant [4 x i8] c"%s\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %gc_stack_bottom = call ptr @llvm.frameaddress.p0(i32 0)
+  store ptr %gc_stack_bottom, ptr @__gusty_gc_stack_bottom, align 8
+  %0 = call ptr @__gusty_gc_alloc(i64 mul (i64 ptrtoint (ptr getelementptr (i32, ptr null, i32 1) to i64), i64 2))
+  %1 = getelementptr inbounds i32, ptr %0, i64 0
+  store i32 1, ptr %1, align 4
+  %2 = getelementptr inbounds i32, ptr %0, i64 1
+  store i32 2, ptr %2, align 4
+  %3 = insertvalue { ptr, i64, i64 } zeroinitializer, ptr %0, 0
+  %4 = insertvalue { ptr, i64, i64 } %3, i64 2, 1
+  %5 = insertvalue { ptr, i64, i64 } %4, i64 2, 2
+  %keep = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } %5, ptr %keep, align 8
+  %6 = call i32 @churn(i32 1)
+  %greetingValue = load ptr, ptr @main.greeting, align 8
+  %7 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_s, ptr %greetingValue)
+  %keepValue = load { ptr, i64, i64 }, ptr %keep, align 8
+  %8 = extractvalue { ptr, i64, i64 } %keepValue, 1
+  %9 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %8)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+; Function Attrs: nocallback nofree nosync nounwind readnone willreturn
+declare ptr @llvm.frameaddress.p0(i32 immarg) #0
+
+define i32 @churn(i32 %0) {
+entry:
+  %s = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } zeroinitializer, ptr %s, align 8
+  %sValue = load { ptr, i64, i64 }, ptr %s, align 8
+  %1 = call { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %sValue, i64 ptrtoint (ptr getelementptr (i64, ptr null, i32 1) to i64))
+  %2 = extractvalue { ptr, i64, i64 } %1, 0
+  %3 = extractvalue { ptr, i64, i64 } %1, 1
+  %4 = getelementptr inbounds i64, ptr %2, i64 %3
+  store i64 1, ptr %4, align 4
+  %5 = add i64 %3, 1
+  %6 = insertvalue { ptr, i64, i64 } %1, i64 %5, 1
+  %7 = call { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %6, i64 ptrtoint (ptr getelementptr (i64, ptr null, i32 1) to i64))
+  %8 = extractvalue { ptr, i64, i64 } %7, 0
+  %9 = extractvalue { ptr, i64, i64 } %7, 1
+  %10 = getelementptr inbounds i64, ptr %8, i64 %9
+  store i64 2, ptr %10, align 4
+  %11 = add i64 %9, 1
+  %12 = insertvalue { ptr, i64, i64 } %7, i64 %11, 1
+  %13 = call { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %12, i64 ptrtoint (ptr getelementptr (i64, ptr null, i32 1) to i64))
+  %14 = extractvalue { ptr, i64, i64 } %13, 0
+  %15 = extractvalue { ptr, i64, i64 } %13, 1
+  %16 = getelementptr inbounds i64, ptr %14, i64 %15
+  store i64 3, ptr %16, align 4
+  %17 = add i64 %15, 1
+  %18 = insertvalue { ptr, i64, i64 } %13, i64 %17, 1
+  store { ptr, i64, i64 } %18, ptr %s, align 8
+  %t = alloca ptr, align 8
+  store ptr @__gusty_string, ptr %t, align 8
+  %19 = call ptr @__gusty_gc_alloc(i64 8)
+  store i64 0, ptr %19, align 8
+  %p = alloca ptr, align 8
+  store ptr %19, ptr %p, align 8
+  %pValue = load ptr, ptr %p, align 8
+  ret i32 %0
+}
+
+define internal { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %0, i64 %1) {
+entry:
+  %length = extractvalue { ptr, i64, i64 } %0, 1
+  %capacity = extractvalue { ptr, i64, i64 } %0, 2
+  %full = icmp eq i64 %length, %capacity
+  br i1 %full, label %grow, label %done
+
+grow:                                             ; preds = %entry
+  %empty = icmp eq i64 %capacity, 0
+  %doubled = mul i64 %capacity, 2
+  %new_capacity = select i1 %empty, i64 4, i64 %doubled
+  %size = mul i64 %new_capacity, %1
+  %data = extractvalue { ptr, i64, i64 } %0, 0
+  %new_data = call ptr @__gusty_gc_realloc(ptr %data, i64 %size)
+  %2 = insertvalue { ptr, i64, i64 } %0, ptr %new_data, 0
+  %3 = insertvalue { ptr, i64, i64 } %2, i64 %new_capacity, 2
+  ret { ptr, i64, i64 } %3
+
+done:                                             ; preds = %entry
+  ret { ptr, i64, i64 } %0
+}
+
+define internal ptr @__gusty_gc_realloc(ptr %0, i64 %1) {
+entry:
+  %object = call ptr @__gusty_gc_alloc(i64 %1)
+  %empty = icmp eq ptr %0, null
+  br i1 %empty, label %done, label %copy
+
+copy:                                             ; preds = %entry
+  %2 = ptrtoint ptr %0 to i64
+  %3 = sub i64 %2, 24
+  %header = inttoptr i64 %3 to ptr
+  %4 = getelementptr inbounds { ptr, i64, i64 }, ptr %header, i32 0, i32 1
+  %old_size = load i64, ptr %4, align 4
+  %smaller = icmp ult i64 %old_size, %1
+  %count = select i1 %smaller, i64 %old_size, i64 %1
+  %5 = call ptr @memcpy(ptr %object, ptr %0, i64 %count)
+  br label %done
+
+done:                                             ; preds = %copy, %entry
+  ret ptr %object
+}
+
+define internal ptr @__gusty_gc_alloc(i64 %0) {
+entry:
+  %allocated = load i64, ptr @__gusty_gc_allocated, align 4
+  %1 = add i64 %allocated, %0
+  %exceeded = icmp ugt i64 %1, 1048576
+  br i1 %exceeded, label %collect, label %allocate
+
+collect:                                          ; preds = %entry
+  call void @__gusty_gc_collect()
+  store i64 0, ptr @__gusty_gc_allocated, align 4
+  br label %allocate
+
+allocate:                                         ; preds = %collect, %entry
+  %2 = load i64, ptr @__gusty_gc_allocated, align 4
+  %3 = add i64 %2, %0
+  store i64 %3, ptr @__gusty_gc_allocated, align 4
+  %4 = add i64 %0, 24
+  %header = call ptr @malloc(i64 %4)
+  %objects = load ptr, ptr @__gusty_gc_objects, align 8
+  %5 = getelementptr inbounds { ptr, i64, i64 }, ptr %header, i32 0, i32 0
+  store ptr %objects, ptr %5, align 8
+  %6 = getelementptr inbounds { ptr, i64, i64 }, ptr %header, i32 0, i32 1
+  store i64 %0, ptr %6, align 4
+  %7 = getelementptr inbounds { ptr, i64, i64 }, ptr %header, i32 0, i32 2
+  store i64 0, ptr %7, align 4
+  store ptr %header, ptr @__gusty_gc_objects, align 8
+  %object = getelementptr inbounds i8, ptr %header, i64 24
+  %8 = call ptr @memset(ptr %object, i32 0, i64 %0)
+  ret ptr %object
+}
+
+define internal void @__gusty_gc_collect() {
+entry:
+  %registers = alloca [256 x i8], align 16
+  %0 = call i32 @_setjmp(ptr %registers)
+  %bottom = load ptr, ptr @__gusty_gc_stack_bottom, align 8
+  %no_stack = icmp eq ptr %bottom, null
+  br i1 %no_stack, label %done, label %mark
+
+mark:                                             ; preds = %entry
+  call void @__gusty_gc_mark_range(ptr %registers, ptr %bottom)
+  call void @__gusty_gc_mark_globals()
+  br label %sweep
+
+sweep:                                            ; preds = %release, %keep, %mark
+  %link = phi ptr [ @__gusty_gc_objects, %mark ], [ %next_link, %keep ], [ %link, %release ]
+  %header = load ptr, ptr %link, align 8
+  %end = icmp eq ptr %header, null
+  br i1 %end, label %done, label %check
+
+check:                                            ; preds = %sweep
+  %1 = getelementptr inbounds { ptr, i64, i64 }, ptr %header, i32 0, i32 2
+  %marked = load i64, ptr %1, align 4
+  %reachable = icmp ne i64 %marked, 0
+  br i1 %reachable, label %keep, label %release
+
+keep:                                             ; preds = %check
+  store i64 0, ptr %1, align 4
+  %next_link = getelementptr inbounds { ptr, i64, i64 }, ptr %header, i32 0, i32 0
+  br label %sweep
+
+release:                                          ; preds = %check
+  %2 = getelementptr inbounds { ptr, i64, i64 }, ptr %header, i32 0, i32 0
+  %next = load ptr, ptr %2, align 8
+  store ptr %next, ptr %link, align 8
+  call void @free(ptr %header)
+  br label %sweep
+
+done:                                             ; preds = %sweep, %entry
+  ret void
+}
+
+; Function Attrs: returns_twice
+declare i32 @_setjmp(ptr) #1
+
+define internal void @__gusty_gc_mark_range(ptr %0, ptr %1) {
+entry:
+  %2 = ptrtoint ptr %1 to i64
+  %last = sub i64 %2, 8
+  br label %loop
+
+loop:                                             ; preds = %body, %entry
+  %word = phi ptr [ %0, %entry ], [ %next, %body ]
+  %3 = ptrtoint ptr %word to i64
+  %in_range = icmp sle i64 %3, %last
+  br i1 %in_range, label %body, label %done
+
+body:                                             ; preds = %loop
+  %value = load ptr, ptr %word, align 8
+  call void @__gusty_gc_mark(ptr %value)
+  %next = getelementptr inbounds i8, ptr %word, i64 8
+  br label %loop
+
+done:                                             ; preds = %loop
+  ret void
+}
+
+define internal void @__gusty_gc_mark(ptr %0) {
+entry:
+  %address = ptrtoint ptr %0 to i64
+  %first = load ptr, ptr @__gusty_gc_objects, align 8
+  br label %loop
+
+loop:                                             ; preds = %next, %entry
+  %header = phi ptr [ %first, %entry ], [ %following, %next ]
+  %end = icmp eq ptr %header, null
+  br i1 %end, label %done, label %check
+
+check:                                            ; preds = %loop
+  %object = getelementptr inbounds i8, ptr %header, i64 24
+  %object_start = ptrtoint ptr %object to i64
+  %1 = getelementptr inbounds { ptr, i64, i64 }, ptr %header, i32 0, i32 1
+  %size = load i64, ptr %1, align 4
+  %object_end = add i64 %object_start, %size
+  %2 = icmp uge i64 %address, %object_start
+  %3 = icmp ult i64 %address, %object_end
+  %inside = and i1 %2, %3
+  br i1 %inside, label %found, label %next
+
+next:                                             ; preds = %check
+  %4 = getelementptr inbounds { ptr, i64, i64 }, ptr %header, i32 0, i32 0
+  %following = load ptr, ptr %4, align 8
+  br label %loop
+
+found:                                            ; preds = %check
+  %5 = getelementptr inbounds { ptr, i64, i64 }, ptr %header, i32 0, i32 2
+  %marked = load i64, ptr %5, align 4
+  %reachable = icmp ne i64 %marked, 0
+  br i1 %reachable, label %done, label %mark
+
+mark:                                             ; preds = %found
+  store i64 1, ptr %5, align 4
+  %6 = inttoptr i64 %object_end to ptr
+  call void @__gusty_gc_mark_range(ptr %object, ptr %6)
+  br label %done
+
+done:                                             ; preds = %mark, %found, %loop
+  ret void
+}
+
+define internal void @__gusty_gc_mark_globals() {
+entry:
+  call void @__gusty_gc_mark_range(ptr @__gusty_gc_stack_bottom, ptr getelementptr inbounds (i8, ptr @__gusty_gc_stack_bottom, i64 8))
+  call void @__gusty_gc_mark_range(ptr @main.greeting, ptr getelementptr inbounds (i8, ptr @main.greeting, i64 8))
+  ret void
+}
+
+declare void @free(ptr)
+
+declare ptr @malloc(i64)
+
+declare ptr @memset(ptr, i32, i64)
+
+declare ptr @memcpy(ptr, ptr, i64)
+
+attributes #0 = { nocallback nofree nosync nounwind readnone willreturn }
+attributes #1 = { returns_twice }
@@ -0,0 +1,97 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.x = internal global i64 5, align 8
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@__gusty_format_string_f64 = constant [4 x i8] c"%f\0A\00"
+@__gusty_string = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@__gusty_string.1 = private unnamed_addr constant [2 x i8] c"b\00", align 1
+@__gusty_format_string_string = constant [4 x i8] c"%s\0A\00"
+
+define i32 @main() {
+entry:
+  %xValue = load i64, ptr @main.x, align 4
+  %0 = call i64 @"add<i64>"(i64 %xValue, i64 3)
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %0)
+  %2 = call i32 @"add<i32>"(i32 1, i32 2)
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %2)
+  %4 = call i32 @"add<i32>"(i32 2, i32 3)
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %4)
+  %6 = call double @"add<f64>"(double 1.500000e+00, double 5.000000e-01)
+  %7 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %6)
+  %8 = call ptr @"pick<string>"(i1 false, ptr @__gusty_string, ptr @__gusty_string.1)
+  %9 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_string, ptr %8)
+  %10 = call ptr @malloc(i64 mul (i64 ptrtoint (ptr getelementptr (i32, ptr null, i32 1) to i64), i64 2))
+  %11 = getelementptr inbounds i32, ptr %10, i64 0
+  store i32 7, ptr %11, align 4
+  %12 = getelementptr inbounds i32, ptr %10, i64 1
+  store i32 8, ptr %12, align 4
+  %13 = insertvalue { ptr, i64, i64 } zeroinitializer, ptr %10, 0
+  %14 = insertvalue { ptr, i64, i64 } %13, i64 2, 1
+  %15 = insertvalue { ptr, i64, i64 } %14, i64 2, 2
+  %s = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } %15, ptr %s, align 8
+  %sValue = load { ptr, i64, i64 }, ptr %s, align 8
+  %16 = call i32 @"first<i32>"({ ptr, i64, i64 } %sValue)
+  %17 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %16)
+  %xValue1 = load i64, ptr @main.x, align 4
+  %18 = call i64 @"convert<i32, i64>"(i32 2, i64 %xValue1)
+  %19 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %18)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i64 @"add<i64>"(i64 %0, i64 %1) {
+entry:
+  %2 = add i64 %0, %1
+  ret i64 %2
+}
+
+define i32 @"add<i32>"(i32 %0, i32 %1) {
+entry:
+  %2 = add i32 %0, %1
+  ret i32 %2
+}
+
+define double @"add<f64>"(double %0, double %1) {
+entry:
+  %2 = fadd double %0, %1
+  ret double %2
+}
+
+define ptr @"pick<string>"(i1 %0, ptr %1, ptr %2) {
+entry:
+  br i1 %0, label %conditional_true, label %conditional_false
+
+conditional_true:                                 ; preds = %entry
+  br label %conditional_done
+
+conditional_false:                                ; preds = %entry
+  br label %conditional_done
+
+conditional_done:                                 ; preds = %conditional_false, %conditional_true
+  %conditional = phi ptr [ %1, %conditional_true ], [ %2, %conditional_false ]
+  ret ptr %conditional
+}
+
+declare ptr @malloc(i64)
+
+define i32 @"first<i32>"({ ptr, i64, i64 } %0) {
+entry:
+  %1 = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } %0, ptr %1, align 8
+  %2 = load { ptr, i64, i64 }, ptr %1, align 8
+  %3 = extractvalue { ptr, i64, i64 } %2, 0
+  %4 = getelementptr inbounds i32, ptr %3, i64 0
+  %5 = load i32, ptr %4, align 4
+  ret i32 %5
+}
+
+define i64 @"convert<i32, i64>"(i32 %0, i64 %1) {
+entry:
+  %2 = sext i32 %0 to i64
+  %3 = add i64 %2, %1
+  ret i64 %3
+}
@@ -0,0 +1,39 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%P = type { i32, i32 }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.a = internal global i32 3, align 4
+@main.c = internal global [2 x [2 x i64]] [[2 x i64] [i64 1, i64 2], [2 x i64] [i64 3, i64 0]], align 8
+@main.p = internal global %P { i32 4, i32 5 }, align 4
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %aValue = load i32, ptr @main.a, align 4
+  %b = alloca i32, align 4
+  store i32 %aValue, ptr %b, align 4
+  %0 = call i32 @one()
+  %d = alloca i32, align 4
+  store i32 %0, ptr %d, align 4
+  store i32 7, ptr @main.a, align 4
+  %aValue1 = load i32, ptr @main.a, align 4
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %aValue1)
+  %bValue = load i32, ptr %b, align 4
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %bValue)
+  %3 = load i64, ptr getelementptr inbounds ([2 x [2 x i64]], ptr @main.c, i64 0, i64 1), align 4
+  %4 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %3)
+  %5 = load i32, ptr getelementptr inbounds (%P, ptr @main.p, i32 0, i32 1), align 4
+  %6 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %5)
+  %dValue = load i32, ptr %d, align 4
+  %7 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %dValue)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @one() {
+entry:
+  ret i32 1
+}
@@ -0,0 +1,42 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_string = private unnamed_addr constant [13 x i8] c"concatenated\00", align 1
+@main.s = internal global ptr @__gusty_string, align 8
+@__gusty_string.1 = private unnamed_addr constant [2 x i8] c"!\00", align 1
+@__gusty_format_string_println_s = constant [4 x i8] c"%s\0A\00"
+
+define i32 @main() {
+entry:
+  %sValue = load ptr, ptr @main.s, align 8
+  %concat = call ptr @__gusty_string_concat(ptr %sValue, ptr @__gusty_string.1)
+  %t = alloca ptr, align 8
+  store ptr %concat, ptr %t, align 8
+  %sValue1 = load ptr, ptr @main.s, align 8
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_s, ptr %sValue1)
+  %tValue = load ptr, ptr %t, align 8
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_s, ptr %tValue)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define internal ptr @__gusty_string_concat(ptr %0, ptr %1) {
+entry:
+  %left_length = call i64 @strlen(ptr %0)
+  %right_length = call i64 @strlen(ptr %1)
+  %right_size = add i64 %right_length, 1
+  %size = add i64 %left_length, %right_size
+  %data = call ptr @malloc(i64 %size)
+  %2 = call ptr @memcpy(ptr %data, ptr %0, i64 %left_length)
+  %end = getelementptr inbounds i8, ptr %data, i64 %left_length
+  %3 = call ptr @memcpy(ptr %end, ptr %1, i64 %right_size)
+  ret ptr %data
+}
+
+declare i64 @strlen(ptr)
+
+declare ptr @malloc(i64)
+
+declare ptr @memcpy(ptr, ptr, i64)
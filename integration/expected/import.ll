@@ -0,0 +1,24 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%Point = type { i32, i32 }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.p = internal global %Point { i32 1, i32 2 }, align 4
+
+define i32 @main() {
+entry:
+  %0 = load i32, ptr @main.p, align 4
+  %1 = load i32, ptr getelementptr inbounds (%Point, ptr @main.p, i32 0, i32 1), align 4
+  %2 = call i32 @add(i32 %0, i32 %1)
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %2)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @add(i32 %0, i32 %1) {
+entry:
+  %2 = add i32 %0, %1
+  ret i32 %2
+}
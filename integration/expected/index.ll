@@ -0,0 +1,52 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.a = internal global [3 x i32] [i32 10, i32 20, i32 30], align 4
+@main.i = internal global i32 2, align 4
+@main.m = internal global [2 x [2 x i64]] [[2 x i64] [i64 1, i64 2], [2 x i64] [i64 3, i64 4]], align 8
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  store i32 5, ptr @main.a, align 4
+  %iValue = load i32, ptr @main.i, align 4
+  %0 = sext i32 %iValue to i64
+  %1 = getelementptr inbounds [3 x i32], ptr @main.a, i64 0, i64 %0
+  %2 = load i32, ptr @main.a, align 4
+  %3 = load i32, ptr getelementptr inbounds ([3 x i32], ptr @main.a, i64 0, i64 1), align 4
+  %4 = add i32 %2, %3
+  store i32 %4, ptr %1, align 4
+  %5 = load i32, ptr getelementptr inbounds ([3 x i32], ptr @main.a, i64 0, i64 2), align 4
+  %6 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %5)
+  %aValue = load [3 x i32], ptr @main.a, align 4
+  %7 = call i32 @get([3 x i32] %aValue, i32 1)
+  %8 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %7)
+  %iValue1 = load i32, ptr @main.i, align 4
+  %9 = trunc i32 %iValue1 to i8
+  %10 = sext i8 %9 to i32
+  %11 = add i32 %10, -1
+  %12 = sext i32 %11 to i64
+  %13 = getelementptr inbounds [2 x i64], ptr getelementptr inbounds ([2 x [2 x i64]], ptr @main.m, i64 0, i64 1), i64 0, i64 %12
+  store i64 9, ptr %13, align 4
+  %14 = load i64, ptr getelementptr inbounds ([2 x [2 x i64]], ptr @main.m, i64 0, i64 1, i64 1), align 4
+  %15 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %14)
+  %16 = alloca [2 x i32], align 4
+  store [2 x i32] [i32 7, i32 8], ptr %16, align 4
+  %17 = getelementptr inbounds [2 x i32], ptr %16, i64 0, i64 1
+  %18 = load i32, ptr %17, align 4
+  %19 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %18)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @get([3 x i32] %0, i32 %1) {
+entry:
+  %2 = alloca [3 x i32], align 4
+  store [3 x i32] %0, ptr %2, align 4
+  %3 = sext i32 %1 to i64
+  %4 = getelementptr inbounds [3 x i32], ptr %2, i64 0, i64 %3
+  %5 = load i32, ptr %4, align 4
+  ret i32 %5
+}
@@ -0,0 +1,38 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_string = private unnamed_addr constant [6 x i8] c"hello\00", align 1
+
+define i32 @main() {
+entry:
+  %0 = call i32 @twice(i32 20)
+  call void @show(i32 %0, ptr @__gusty_string, i1 true)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define void @show(i32 %0, ptr %1, i1 %2) {
+entry:
+  call void @__gusty_llvm_1(i32 %0, ptr %1, i1 %2)
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  ret void
+}
+
+define i32 @twice(i32 %x) {
+  %y = add i32 %x, %x
+  ret i32 %y
+}
+
+; Function Attrs: alwaysinline
+define internal void @__gusty_llvm_1(i32 %a, ptr %s, i1 %flag) #0 {
+entry:
+  %sum = add i32 %a, 1
+  %0 = call i32 @puts(ptr %s)
+  ret void
+}
+
+declare i32 @puts(ptr)
+
+attributes #0 = { alwaysinline }
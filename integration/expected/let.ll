@@ -1,12 +1,11 @@
 ; ModuleID = 'main'
 source_filename = "main"
 
-@format_string = constant [3 x i8] c"%d\0A"
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.donutloop = internal global i32 42, align 4
 
 define i32 @main() {
 entry:
-  %donutloop = alloca i32, align 4
-  store i32 42, ptr %donutloop, align 4
   ret i32 0
 }
 
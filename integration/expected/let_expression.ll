@@ -0,0 +1,56 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.a = internal global i32 40, align 4
+@main.b = internal global i32 2, align 4
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %aValue = load i32, ptr @main.a, align 4
+  %bValue = load i32, ptr @main.b, align 4
+  %0 = add i32 %aValue, %bValue
+  %x = alloca i32, align 4
+  store i32 %0, ptr %x, align 4
+  %1 = call i32 @add(i32 1, i32 2)
+  %y = alloca i32, align 4
+  store i32 %1, ptr %y, align 4
+  %xValue = load i32, ptr %x, align 4
+  %yValue = load i32, ptr %y, align 4
+  %2 = call i32 @add(i32 %xValue, i32 %yValue)
+  %3 = add i32 %2, 1
+  %z = alloca i32, align 4
+  store i32 %3, ptr %z, align 4
+  %xValue1 = load i32, ptr %x, align 4
+  %s = alloca i32, align 4
+  store i32 %xValue1, ptr %s, align 4
+  %sValue = load i32, ptr %s, align 4
+  %4 = sext i32 %sValue to i64
+  %5 = add i64 %4, 1
+  %6 = call i64 @twice(i64 %5)
+  %w = alloca i64, align 8
+  store i64 %6, ptr %w, align 4
+  %zValue = load i32, ptr %z, align 4
+  %7 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %zValue)
+  %wValue = load i64, ptr %w, align 4
+  %8 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %wValue)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @add(i32 %0, i32 %1) {
+entry:
+  %2 = add i32 %0, %1
+  ret i32 %2
+}
+
+define i64 @twice(i64 %0) {
+entry:
+  %1 = add i64 %0, %0
+  %r = alloca i64, align 8
+  store i64 %1, ptr %r, align 4
+  %rValue = load i64, ptr %r, align 4
+  ret i64 %rValue
+}
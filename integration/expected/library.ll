@@ -0,0 +1,28 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%Point = type { i32, i32 }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.limit = internal global i64 10, align 8
+
+declare i32 @printf(ptr, ...)
+
+define i32 @sum(%Point %0) {
+entry:
+  %1 = alloca %Point, align 4
+  store %Point %0, ptr %1, align 4
+  %2 = getelementptr inbounds %Point, ptr %1, i32 0, i32 0
+  %3 = load i32, ptr %2, align 4
+  %4 = alloca %Point, align 4
+  store %Point %0, ptr %4, align 4
+  %5 = getelementptr inbounds %Point, ptr %4, i32 0, i32 1
+  %6 = load i32, ptr %5, align 4
+  %7 = add i32 %3, %6
+  ret i32 %7
+}
+
+define i32 @main() {
+entry:
+  ret i32 0
+}
@@ -0,0 +1,27 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 1)
+  %1 = call i32 @__gusty_main()
+  ret i32 %1
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @twice(i32 %0) {
+entry:
+  %1 = add i32 %0, %0
+  ret i32 %1
+}
+
+define internal i32 @__gusty_main() {
+entry:
+  %0 = call i32 @twice(i32 21)
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  %2 = call i32 @twice(i32 2)
+  ret i32 %2
+}
@@ -0,0 +1,72 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@__gusty_format_string_f64 = constant [4 x i8] c"%f\0A\00"
+
+define i32 @main() {
+entry:
+  call void @h()
+  %0 = call i32 @f()
+  call void @a(i32 %0)
+  call void @e(i32 1, i32 2)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define void @a(i32 %0) {
+entry:
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  ret void
+}
+
+define void @b(i64 %0) {
+entry:
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %0)
+  ret void
+}
+
+define void @c(double %0) {
+entry:
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %0)
+  ret void
+}
+
+define void @d(i8 %0) {
+entry:
+  %1 = sext i8 %0 to i32
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %1)
+  ret void
+}
+
+define void @e(i32 %0, i32 %1) {
+entry:
+  call void @a(i32 %0)
+  call void @a(i32 %1)
+  ret void
+}
+
+define i32 @f() {
+entry:
+  ret i32 6
+}
+
+define void @g(float %0) {
+entry:
+  %1 = fpext float %0 to double
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %1)
+  ret void
+}
+
+define void @h() {
+entry:
+  %0 = call i32 @f()
+  call void @e(i32 %0, i32 7)
+  call void @b(i64 8)
+  call void @c(double 9.500000e+00)
+  call void @d(i8 10)
+  call void @g(float 1.100000e+01)
+  ret void
+}
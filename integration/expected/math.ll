@@ -0,0 +1,85 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.a = internal global i32 -5, align 4
+@main.f = internal global double 2.250000e+00, align 8
+@main.g = internal global float 9.000000e+00, align 4
+@__gusty_format_string_f64 = constant [4 x i8] c"%f\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i8 @llvm.abs.i8(i8 -7, i1 false)
+  %b = alloca i8, align 1
+  store i8 %0, ptr %b, align 1
+  %1 = call i64 @llvm.smax.i64(i64 3, i64 4)
+  %c = alloca i64, align 8
+  store i64 %1, ptr %c, align 4
+  %aValue = load i32, ptr @main.a, align 4
+  %2 = call i32 @llvm.abs.i32(i32 %aValue, i1 false)
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %2)
+  %aValue1 = load i32, ptr @main.a, align 4
+  %4 = call i32 @llvm.smin.i32(i32 %aValue1, i32 3)
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %4)
+  %aValue2 = load i32, ptr @main.a, align 4
+  %6 = call i32 @llvm.smax.i32(i32 2, i32 %aValue2)
+  %7 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %6)
+  %fValue = load double, ptr @main.f, align 8
+  %8 = call double @llvm.sqrt.f64(double %fValue)
+  %9 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %8)
+  %fValue3 = load double, ptr @main.f, align 8
+  %10 = call double @llvm.pow.f64(double %fValue3, double 2.000000e+00)
+  %11 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %10)
+  %12 = call double @llvm.fabs.f64(double -1.500000e+00)
+  %13 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %12)
+  %gValue = load float, ptr @main.g, align 4
+  %14 = call float @llvm.sqrt.f32(float %gValue)
+  %15 = fpext float %14 to double
+  %16 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %15)
+  %bValue = load i8, ptr %b, align 1
+  %17 = sext i8 %bValue to i32
+  %18 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %17)
+  %cValue = load i64, ptr %c, align 4
+  %19 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %cValue)
+  %20 = call double @llvm.sqrt.f64(double 1.600000e+01)
+  %21 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %20)
+  %fValue4 = load double, ptr @main.f, align 8
+  %22 = call double @llvm.minnum.f64(double 1.500000e+00, double %fValue4)
+  %23 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %22)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+; Function Attrs: nocallback nofree nosync nounwind readnone speculatable willreturn
+declare i8 @llvm.abs.i8(i8, i1 immarg) #0
+
+; Function Attrs: nocallback nofree nosync nounwind readnone speculatable willreturn
+declare i64 @llvm.smax.i64(i64, i64) #0
+
+; Function Attrs: nocallback nofree nosync nounwind readnone speculatable willreturn
+declare i32 @llvm.abs.i32(i32, i1 immarg) #0
+
+; Function Attrs: nocallback nofree nosync nounwind readnone speculatable willreturn
+declare i32 @llvm.smin.i32(i32, i32) #0
+
+; Function Attrs: nocallback nofree nosync nounwind readnone speculatable willreturn
+declare i32 @llvm.smax.i32(i32, i32) #0
+
+; Function Attrs: nocallback nofree nosync nounwind readnone speculatable willreturn
+declare double @llvm.sqrt.f64(double) #0
+
+; Function Attrs: nocallback nofree nosync nounwind readnone speculatable willreturn
+declare double @llvm.pow.f64(double, double) #0
+
+; Function Attrs: nocallback nofree nosync nounwind readnone speculatable willreturn
+declare double @llvm.fabs.f64(double) #0
+
+; Function Attrs: nocallback nofree nosync nounwind readnone speculatable willreturn
+declare float @llvm.sqrt.f32(float) #0
+
+; Function Attrs: nocallback nofree nosync nounwind readnone speculatable willreturn
+declare double @llvm.minnum.f64(double, double) #0
+
+attributes #0 = { nocallback nofree nosync nounwind readnone speculatable willreturn }
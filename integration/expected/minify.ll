@@ -0,0 +1,119 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%Point = type { i32, i32 }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i32 @a(i32 1, i32 2)
+  %g = alloca i32, align 4
+  store i32 %0, ptr %g, align 4
+  %gValue = load i32, ptr %g, align 4
+  %1 = call ptr @malloc(i64 ptrtoint (ptr getelementptr (i32, ptr null, i32 1) to i64))
+  %2 = getelementptr inbounds i32, ptr %1, i64 0
+  store i32 %gValue, ptr %2, align 4
+  %3 = insertvalue { ptr, i64, i64 } zeroinitializer, ptr %1, 0
+  %4 = insertvalue { ptr, i64, i64 } %3, i64 1, 1
+  %5 = insertvalue { ptr, i64, i64 } %4, i64 1, 2
+  %h = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } %5, ptr %h, align 8
+  %hValue = load { ptr, i64, i64 }, ptr %h, align 8
+  %6 = call { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %hValue, i64 ptrtoint (ptr getelementptr (i32, ptr null, i32 1) to i64))
+  %7 = extractvalue { ptr, i64, i64 } %6, 0
+  %8 = extractvalue { ptr, i64, i64 } %6, 1
+  %9 = getelementptr inbounds i32, ptr %7, i64 %8
+  store i32 4, ptr %9, align 4
+  %10 = add i64 %8, 1
+  %11 = insertvalue { ptr, i64, i64 } %6, i64 %10, 1
+  store { ptr, i64, i64 } %11, ptr %h, align 8
+  %for_init_i = alloca i32, align 4
+  store i32 0, ptr %for_init_i, align 4
+  br label %loop
+
+loop:                                             ; preds = %loop, %entry
+  %iValue = load i32, ptr %for_init_i, align 4
+  %12 = sext i32 %iValue to i64
+  %13 = load { ptr, i64, i64 }, ptr %h, align 8
+  %14 = extractvalue { ptr, i64, i64 } %13, 0
+  %15 = getelementptr inbounds i32, ptr %14, i64 %12
+  %16 = load i32, ptr %15, align 4
+  %17 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %16)
+  %for_init_i_value = load i32, ptr %for_init_i, align 4
+  %for_init_i_value_updated = add i32 %for_init_i_value, 1
+  store i32 %for_init_i_value_updated, ptr %for_init_i, align 4
+  %loopCond = icmp ule i32 %for_init_i_value_updated, 2
+  br i1 %loopCond, label %loop, label %end
+
+end:                                              ; preds = %loop
+  %hValue1 = load { ptr, i64, i64 }, ptr %h, align 8
+  %18 = extractvalue { ptr, i64, i64 } %hValue1, 1
+  %19 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %18)
+  %gValue2 = load i32, ptr %g, align 4
+  %20 = insertvalue %Point zeroinitializer, i32 %gValue2, 0
+  %21 = insertvalue %Point %20, i32 0, 1
+  %22 = call i32 @Scale(%Point %21, i32 2)
+  %23 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %22)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @a(i32 %0, i32 %1) {
+entry:
+  %2 = add i32 %0, %1
+  ret i32 %2
+}
+
+define i32 @Scale(%Point %0, i32 %1) {
+entry:
+  %2 = alloca %Point, align 4
+  store %Point %0, ptr %2, align 4
+  %3 = getelementptr inbounds %Point, ptr %2, i32 0, i32 0
+  %4 = load i32, ptr %3, align 4
+  %5 = add i32 %4, %1
+  %f = alloca i32, align 4
+  store i32 %5, ptr %f, align 4
+  %fValue = load i32, ptr %f, align 4
+  ret i32 %fValue
+}
+
+declare ptr @malloc(i64)
+
+define internal { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %0, i64 %1) {
+entry:
+  %length = extractvalue { ptr, i64, i64 } %0, 1
+  %capacity = extractvalue { ptr, i64, i64 } %0, 2
+  %full = icmp eq i64 %length, %capacity
+  br i1 %full, label %grow, label %done
+
+grow:                                             ; preds = %entry
+  %empty = icmp eq i64 %capacity, 0
+  %doubled = mul i64 %capacity, 2
+  %new_capacity = select i1 %empty, i64 4, i64 %doubled
+  %size = mul i64 %new_capacity, %1
+  %data = extractvalue { ptr, i64, i64 } %0, 0
+  %new_data = call ptr @realloc(ptr %data, i64 %size)
+  %2 = insertvalue { ptr, i64, i64 } %0, ptr %new_data, 0
+  %3 = insertvalue { ptr, i64, i64 } %2, i64 %new_capacity, 2
+  ret { ptr, i64, i64 } %3
+
+done:                                             ; preds = %entry
+  ret { ptr, i64, i64 } %0
+}
+
+declare ptr @realloc(ptr, i64)
+
+!gusty.identifiers = !{!0, !1, !2, !3, !4, !5, !6, !7, !8}
+
+!0 = !{!"a", !"add"}
+!1 = !{!"b", !"first"}
+!2 = !{!"c", !"second"}
+!3 = !{!"d", !"point"}
+!4 = !{!"e", !"factor"}
+!5 = !{!"f", !"scaled"}
+!6 = !{!"g", !"counter"}
+!7 = !{!"h", !"values"}
+!8 = !{!"i", !"index"}
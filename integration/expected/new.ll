@@ -0,0 +1,87 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%Node = type { i32, ptr }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call ptr @malloc(i64 16)
+  store %Node zeroinitializer, ptr %0, align 8
+  %1 = call ptr @push(ptr %0, i32 1)
+  %2 = call ptr @push(ptr %1, i32 2)
+  %list = alloca ptr, align 8
+  store ptr %2, ptr %list, align 8
+  %3 = load ptr, ptr %list, align 8
+  %4 = getelementptr inbounds %Node, ptr %3, i32 0, i32 0
+  %5 = load i32, ptr %4, align 4
+  %6 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %5)
+  %7 = load ptr, ptr %list, align 8
+  %8 = getelementptr inbounds %Node, ptr %7, i32 0, i32 1
+  %9 = load ptr, ptr %8, align 8
+  %10 = getelementptr inbounds %Node, ptr %9, i32 0, i32 0
+  %11 = load i32, ptr %10, align 4
+  %12 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %11)
+  %13 = call ptr @malloc(i64 8)
+  store i64 0, ptr %13, align 8
+  %counter = alloca ptr, align 8
+  store ptr %13, ptr %counter, align 8
+  %counterValue = load ptr, ptr %counter, align 8
+  %counterValue1 = load ptr, ptr %counter, align 8
+  %14 = load i64, ptr %counterValue1, align 4
+  %15 = add i64 %14, 41
+  store i64 %15, ptr %counterValue, align 4
+  %counterValue2 = load ptr, ptr %counter, align 8
+  %16 = load i64, ptr %counterValue2, align 4
+  %17 = add i64 %16, 1
+  %18 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %17)
+  %19 = call ptr @malloc(i64 32)
+  store [4 x double] zeroinitializer, ptr %19, align 8
+  %buffer = alloca ptr, align 8
+  store ptr %19, ptr %buffer, align 8
+  %bufferValue = load ptr, ptr %buffer, align 8
+  %20 = load [4 x double], ptr %bufferValue, align 8
+  %21 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 4)
+  %counterValue3 = load ptr, ptr %counter, align 8
+  call void @free(ptr %counterValue3)
+  %bufferValue4 = load ptr, ptr %buffer, align 8
+  call void @free(ptr %bufferValue4)
+  %22 = call ptr @malloc(i64 mul (i64 ptrtoint (ptr getelementptr (i32, ptr null, i32 1) to i64), i64 2))
+  %23 = getelementptr inbounds i32, ptr %22, i64 0
+  store i32 1, ptr %23, align 4
+  %24 = getelementptr inbounds i32, ptr %22, i64 1
+  store i32 2, ptr %24, align 4
+  %25 = insertvalue { ptr, i64, i64 } zeroinitializer, ptr %22, 0
+  %26 = insertvalue { ptr, i64, i64 } %25, i64 2, 1
+  %27 = insertvalue { ptr, i64, i64 } %26, i64 2, 2
+  %s = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } %27, ptr %s, align 8
+  %sValue = load { ptr, i64, i64 }, ptr %s, align 8
+  %28 = extractvalue { ptr, i64, i64 } %sValue, 0
+  call void @free(ptr %28)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define ptr @push(ptr %0, i32 %1) {
+entry:
+  %2 = call ptr @malloc(i64 16)
+  store %Node zeroinitializer, ptr %2, align 8
+  %n = alloca ptr, align 8
+  store ptr %2, ptr %n, align 8
+  %3 = load ptr, ptr %n, align 8
+  %4 = getelementptr inbounds %Node, ptr %3, i32 0, i32 0
+  store i32 %1, ptr %4, align 4
+  %5 = load ptr, ptr %n, align 8
+  %6 = getelementptr inbounds %Node, ptr %5, i32 0, i32 1
+  store ptr %0, ptr %6, align 8
+  %nValue = load ptr, ptr %n, align 8
+  ret ptr %nValue
+}
+
+declare ptr @malloc(i64)
+
+declare void @free(ptr)
@@ -0,0 +1,114 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%Node = type { i32 }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.a = internal global { i1, i64 } { i1 true, i64 5 }, align 8
+@main.b = internal global { i1, i64 } zeroinitializer, align 8
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@main.n = internal global %Node { i32 4 }, align 4
+@main.nested = internal global { i1, { i1, i8 } } { i1 true, { i1, i8 } { i1 true, i8 3 } }, align 1
+
+define i32 @main() {
+entry:
+  %aValue = load { i1, i64 }, ptr @main.a, align 4
+  %present = extractvalue { i1, i64 } %aValue, 0
+  %value = extractvalue { i1, i64 } %aValue, 1
+  br i1 %present, label %option_done, label %option_none
+
+option_none:                                      ; preds = %entry
+  br label %option_done
+
+option_done:                                      ; preds = %option_none, %entry
+  %unwrapped = phi i64 [ %value, %entry ], [ 0, %option_none ]
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %unwrapped)
+  %bValue = load { i1, i64 }, ptr @main.b, align 4
+  %1 = call i64 @or({ i1, i64 } %bValue)
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %1)
+  %3 = call { i1, i32 } @find(i32 2)
+  %present3 = extractvalue { i1, i32 } %3, 0
+  %value4 = extractvalue { i1, i32 } %3, 1
+  br i1 %present3, label %option_done2, label %option_none1
+
+option_none1:                                     ; preds = %option_done
+  br label %option_done2
+
+option_done2:                                     ; preds = %option_none1, %option_done
+  %unwrapped5 = phi i32 [ %value4, %option_done ], [ 0, %option_none1 ]
+  %4 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %unwrapped5)
+  %5 = call { i1, ptr } @missing()
+  %present8 = extractvalue { i1, ptr } %5, 0
+  %value9 = extractvalue { i1, ptr } %5, 1
+  br i1 %present8, label %option_done7, label %option_none6
+
+option_none6:                                     ; preds = %option_done2
+  br label %option_done7
+
+option_done7:                                     ; preds = %option_none6, %option_done2
+  %unwrapped10 = phi ptr [ %value9, %option_done2 ], [ @main.n, %option_none6 ]
+  %p = alloca ptr, align 8
+  store ptr %unwrapped10, ptr %p, align 8
+  %6 = load ptr, ptr %p, align 8
+  %7 = getelementptr inbounds %Node, ptr %6, i32 0, i32 0
+  %8 = load i32, ptr %7, align 4
+  %9 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %8)
+  %aValue11 = load { i1, i64 }, ptr @main.a, align 4
+  store { i1, i64 } %aValue11, ptr @main.b, align 4
+  %bValue12 = load { i1, i64 }, ptr @main.b, align 4
+  %10 = call i64 @or({ i1, i64 } %bValue12)
+  %11 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %10)
+  %nestedValue = load { i1, { i1, i8 } }, ptr @main.nested, align 1
+  %present15 = extractvalue { i1, { i1, i8 } } %nestedValue, 0
+  %value16 = extractvalue { i1, { i1, i8 } } %nestedValue, 1
+  br i1 %present15, label %option_done14, label %option_none13
+
+option_none13:                                    ; preds = %option_done7
+  br label %option_done14
+
+option_done14:                                    ; preds = %option_none13, %option_done7
+  %unwrapped17 = phi { i1, i8 } [ %value16, %option_done7 ], [ zeroinitializer, %option_none13 ]
+  %present20 = extractvalue { i1, i8 } %unwrapped17, 0
+  %value21 = extractvalue { i1, i8 } %unwrapped17, 1
+  br i1 %present20, label %option_done19, label %option_none18
+
+option_none18:                                    ; preds = %option_done14
+  br label %option_done19
+
+option_done19:                                    ; preds = %option_none18, %option_done14
+  %unwrapped22 = phi i8 [ %value21, %option_done14 ], [ 9, %option_none18 ]
+  %12 = sext i8 %unwrapped22 to i32
+  %13 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %12)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define { i1, i32 } @find(i32 %0) {
+entry:
+  %1 = add i32 %0, 1
+  %2 = insertvalue { i1, i32 } { i1 true, i32 0 }, i32 %1, 1
+  %r = alloca { i1, i32 }, align 4
+  store { i1, i32 } %2, ptr %r, align 4
+  %rValue = load { i1, i32 }, ptr %r, align 4
+  ret { i1, i32 } %rValue
+}
+
+define { i1, ptr } @missing() {
+entry:
+  ret { i1, ptr } zeroinitializer
+}
+
+define i64 @or({ i1, i64 } %0) {
+entry:
+  %present = extractvalue { i1, i64 } %0, 0
+  %value = extractvalue { i1, i64 } %0, 1
+  br i1 %present, label %option_done, label %option_none
+
+option_none:                                      ; preds = %entry
+  br label %option_done
+
+option_done:                                      ; preds = %option_none, %entry
+  %unwrapped = phi i64 [ %value, %entry ], [ 1, %option_none ]
+  ret i64 %unwrapped
+}
@@ -0,0 +1,47 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i32 @math.twice(i32 3)
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  %2 = call i32 @geometry.abs(i32 2)
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %2)
+  %4 = call i32 @abs(i32 1)
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %4)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @math.double(i32 %0) {
+entry:
+  %1 = add i32 %0, %0
+  ret i32 %1
+}
+
+define i32 @math.abs(i32 %0) {
+entry:
+  ret i32 %0
+}
+
+define i32 @math.twice(i32 %0) {
+entry:
+  %1 = call i32 @math.abs(i32 %0)
+  %2 = call i32 @math.double(i32 %1)
+  ret i32 %2
+}
+
+define i32 @geometry.abs(i32 %0) {
+entry:
+  %1 = call i32 @math.abs(i32 %0)
+  %2 = add i32 %1, 1
+  ret i32 %2
+}
+
+define i32 @abs(i32 %0) {
+entry:
+  ret i32 0
+}
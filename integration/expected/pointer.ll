@@ -0,0 +1,87 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%Node = type { i32, ptr }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.x = internal global i32 1, align 4
+@main.p = internal global ptr @main.x, align 8
+@main.second = internal global %Node { i32 2, ptr null }, align 8
+@main.first = internal global %Node { i32 1, ptr @main.second }, align 8
+@main.a = internal global [2 x i32] [i32 1, i32 2], align 4
+@main.q = internal global ptr getelementptr inbounds ([2 x i32], ptr @main.a, i64 0, i64 1), align 8
+@main.pp = internal global ptr @main.p, align 8
+
+define i32 @main() {
+entry:
+  call void @set(ptr @main.x, i32 5)
+  %xValue = load i32, ptr @main.x, align 4
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %xValue)
+  %pValue = load ptr, ptr @main.p, align 8
+  %pValue1 = load ptr, ptr @main.p, align 8
+  %1 = load i32, ptr %pValue1, align 4
+  %2 = add i32 %1, 1
+  store i32 %2, ptr %pValue, align 4
+  %xValue2 = load i32, ptr @main.x, align 4
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %xValue2)
+  call void @grow(ptr @main.first)
+  %4 = call i32 @sum(ptr @main.first)
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %4)
+  %6 = load ptr, ptr getelementptr inbounds (%Node, ptr @main.first, i32 0, i32 1), align 8
+  %7 = getelementptr inbounds %Node, ptr %6, i32 0, i32 0
+  store i32 7, ptr %7, align 4
+  %8 = load i32, ptr @main.second, align 4
+  %9 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %8)
+  %qValue = load ptr, ptr @main.q, align 8
+  store i32 9, ptr %qValue, align 4
+  %10 = load i32, ptr getelementptr inbounds ([2 x i32], ptr @main.a, i64 0, i64 1), align 4
+  %11 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %10)
+  %ppValue = load ptr, ptr @main.pp, align 8
+  %12 = load ptr, ptr %ppValue, align 8
+  store i32 3, ptr %12, align 4
+  %xValue3 = load i32, ptr @main.x, align 4
+  %13 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %xValue3)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define void @set(ptr %0, i32 %1) {
+entry:
+  store i32 %1, ptr %0, align 4
+  ret void
+}
+
+define i32 @sum(ptr %0) {
+entry:
+  %1 = alloca ptr, align 8
+  store ptr %0, ptr %1, align 8
+  %2 = load ptr, ptr %1, align 8
+  %3 = getelementptr inbounds %Node, ptr %2, i32 0, i32 0
+  %4 = load i32, ptr %3, align 4
+  %5 = alloca ptr, align 8
+  store ptr %0, ptr %5, align 8
+  %6 = load ptr, ptr %5, align 8
+  %7 = getelementptr inbounds %Node, ptr %6, i32 0, i32 1
+  %8 = load ptr, ptr %7, align 8
+  %9 = getelementptr inbounds %Node, ptr %8, i32 0, i32 0
+  %10 = load i32, ptr %9, align 4
+  %11 = add i32 %4, %10
+  ret i32 %11
+}
+
+define void @grow(ptr %0) {
+entry:
+  %1 = alloca ptr, align 8
+  store ptr %0, ptr %1, align 8
+  %2 = load ptr, ptr %1, align 8
+  %3 = getelementptr inbounds %Node, ptr %2, i32 0, i32 0
+  %4 = alloca ptr, align 8
+  store ptr %0, ptr %4, align 8
+  %5 = load ptr, ptr %4, align 8
+  %6 = getelementptr inbounds %Node, ptr %5, i32 0, i32 0
+  %7 = load i32, ptr %6, align 4
+  %8 = add i32 %7, 10
+  store i32 %8, ptr %3, align 4
+  ret void
+}
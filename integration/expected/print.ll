@@ -0,0 +1,41 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.b = internal global i8 -3, align 1
+@main.big = internal global i64 5000000000, align 8
+@main.f = internal global float 1.500000e+00, align 4
+@__gusty_format_string_print_d = constant [3 x i8] c"%d\00"
+@__gusty_string = private unnamed_addr constant [2 x i8] c" \00", align 1
+@__gusty_format_string_print_s = constant [3 x i8] c"%s\00"
+@__gusty_format_string_println_ld = constant [5 x i8] c"%ld\0A\00"
+@__gusty_format_string_println_f = constant [4 x i8] c"%f\0A\00"
+@__gusty_string_true = constant [5 x i8] c"true\00"
+@__gusty_string_false = constant [6 x i8] c"false\00"
+@__gusty_format_string_println_s = constant [4 x i8] c"%s\0A\00"
+@main.t = internal global i32 1, align 4
+@__gusty_string.1 = private unnamed_addr constant [2 x i8] c"!\00", align 1
+@__gusty_string.2 = private unnamed_addr constant [1 x i8] zeroinitializer, align 1
+
+define i32 @main() {
+entry:
+  %bValue = load i8, ptr @main.b, align 1
+  %0 = sext i8 %bValue to i32
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_print_d, i32 %0)
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_print_s, ptr @__gusty_string)
+  %bigValue = load i64, ptr @main.big, align 4
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_ld, i64 %bigValue)
+  %fValue = load float, ptr @main.f, align 4
+  %4 = fpext float %fValue to double
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_f, double %4)
+  %6 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_f, double 2.250000e+00)
+  %7 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_s, ptr @__gusty_string_true)
+  %tValue = load i32, ptr @main.t, align 4
+  %8 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_print_d, i32 %tValue)
+  %9 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_s, ptr @__gusty_string.1)
+  %10 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_print_s, ptr @__gusty_string_false)
+  %11 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_s, ptr @__gusty_string.2)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
@@ -1,7 +1,7 @@
 ; ModuleID = 'main'
 source_filename = "main"
 
-@format_string = constant [3 x i8] c"%d\0A"
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
 
 define i32 @main() {
 entry:
@@ -15,8 +15,8 @@ define void @add(i32 %0, i32 %1) {
 entry:
   %donut = alloca i32, align 4
   store i32 43, ptr %donut, align 4
-  %donutValue = load ptr, ptr %donut, align 8
-  %2 = call i32 (ptr, ...) @printf(ptr @format_string, ptr %donutValue)
-  %3 = call i32 (ptr, ...) @printf(ptr @format_string, i32 %0)
+  %donutValue = load i32, ptr %donut, align 4
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %donutValue)
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
   ret void
 }
@@ -0,0 +1,75 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_read_int = constant [4 x i8] c"%ld\00"
+@stdin = external global ptr
+@__gusty_format_string_println_s = constant [4 x i8] c"%s\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %read = alloca i64, align 8
+  store i64 0, ptr %read, align 4
+  %0 = call i32 (ptr, ...) @scanf(ptr @__gusty_format_string_read_int, ptr %read)
+  %1 = load i64, ptr %read, align 4
+  %n = alloca i64, align 8
+  store i64 %1, ptr %n, align 4
+  %2 = call ptr @__gusty_read_line()
+  %name = alloca ptr, align 8
+  store ptr %2, ptr %name, align 8
+  %nValue = load i64, ptr %n, align 4
+  %read1 = alloca i64, align 8
+  store i64 0, ptr %read1, align 4
+  %3 = call i32 (ptr, ...) @scanf(ptr @__gusty_format_string_read_int, ptr %read1)
+  %4 = load i64, ptr %read1, align 4
+  %5 = add i64 %nValue, %4
+  %total = alloca i64, align 8
+  store i64 %5, ptr %total, align 4
+  %nameValue = load ptr, ptr %name, align 8
+  %6 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_println_s, ptr %nameValue)
+  %totalValue = load i64, ptr %total, align 4
+  %7 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %totalValue)
+  %8 = call ptr @__gusty_read_line()
+  %9 = call i64 @strlen(ptr %8)
+  %10 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %9)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+declare i32 @scanf(ptr, ...)
+
+define internal ptr @__gusty_read_line() {
+entry:
+  %buffer = call ptr @malloc(i64 1024)
+  %stdin = load ptr, ptr @stdin, align 8
+  %line = call ptr @fgets(ptr %buffer, i32 1024, ptr %stdin)
+  %eof = icmp eq ptr %line, null
+  br i1 %eof, label %end, label %check
+
+end:                                              ; preds = %entry
+  store i8 0, ptr %buffer, align 1
+  ret ptr %buffer
+
+check:                                            ; preds = %entry
+  %length = call i64 @strlen(ptr %buffer)
+  %last_index = sub i64 %length, 1
+  %last = getelementptr inbounds i8, ptr %buffer, i64 %last_index
+  %last_byte = load i8, ptr %last, align 1
+  %newline = icmp eq i8 %last_byte, 10
+  br i1 %newline, label %strip, label %done
+
+strip:                                            ; preds = %check
+  store i8 0, ptr %last, align 1
+  br label %done
+
+done:                                             ; preds = %strip, %check
+  ret ptr %buffer
+}
+
+declare ptr @malloc(i64)
+
+declare ptr @fgets(ptr, i32, ptr)
+
+declare i64 @strlen(ptr)
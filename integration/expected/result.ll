@@ -0,0 +1,118 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@main.r = internal global { i1, i1, i32 } { i1 false, i1 false, i32 2 }, align 4
+
+define i32 @main() {
+entry:
+  %0 = call { i1, i64, i32 } @twice(i32 3)
+  %present = extractvalue { i1, i64, i32 } %0, 0
+  %value = extractvalue { i1, i64, i32 } %0, 1
+  br i1 %present, label %option_done, label %option_none
+
+option_none:                                      ; preds = %entry
+  br label %option_done
+
+option_done:                                      ; preds = %option_none, %entry
+  %unwrapped = phi i64 [ %value, %entry ], [ -1, %option_none ]
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %unwrapped)
+  %2 = call { i1, i32, i32 } @safe(i32 4)
+  %present3 = extractvalue { i1, i32, i32 } %2, 0
+  %value4 = extractvalue { i1, i32, i32 } %2, 1
+  br i1 %present3, label %option_done2, label %option_none1
+
+option_none1:                                     ; preds = %option_done
+  br label %option_done2
+
+option_done2:                                     ; preds = %option_none1, %option_done
+  %unwrapped5 = phi i32 [ %value4, %option_done ], [ -1, %option_none1 ]
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %unwrapped5)
+  %rValue = load { i1, i1, i32 }, ptr @main.r, align 4
+  %present8 = extractvalue { i1, i1, i32 } %rValue, 0
+  %value9 = extractvalue { i1, i1, i32 } %rValue, 1
+  br i1 %present8, label %option_done7, label %option_none6
+
+option_none6:                                     ; preds = %option_done2
+  br label %option_done7
+
+option_done7:                                     ; preds = %option_none6, %option_done2
+  %unwrapped10 = phi i1 [ %value9, %option_done2 ], [ true, %option_none6 ]
+  %4 = zext i1 %unwrapped10 to i32
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %4)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define { i1, i32, i32 } @parse(i32 %0) {
+entry:
+  %1 = insertvalue { i1, i32, i32 } zeroinitializer, i32 %0, 2
+  %r = alloca { i1, i32, i32 }, align 4
+  store { i1, i32, i32 } %1, ptr %r, align 4
+  %rValue = load { i1, i32, i32 }, ptr %r, align 4
+  ret { i1, i32, i32 } %rValue
+}
+
+define { i1, i32, i32 } @half(i32 %0) {
+entry:
+  %1 = insertvalue { i1, i32, i32 } { i1 true, i32 0, i32 0 }, i32 %0, 1
+  ret { i1, i32, i32 } %1
+}
+
+define { i1, i64, i32 } @twice(i32 %0) {
+entry:
+  %1 = call { i1, i32, i32 } @half(i32 %0)
+  %ok = extractvalue { i1, i32, i32 } %1, 0
+  br i1 %ok, label %try_ok, label %try_error
+
+try_error:                                        ; preds = %entry
+  %error = extractvalue { i1, i32, i32 } %1, 2
+  %2 = insertvalue { i1, i64, i32 } zeroinitializer, i32 %error, 2
+  ret { i1, i64, i32 } %2
+
+try_ok:                                           ; preds = %entry
+  %value = extractvalue { i1, i32, i32 } %1, 1
+  %h = alloca i32, align 4
+  store i32 %value, ptr %h, align 4
+  %hValue = load i32, ptr %h, align 4
+  %3 = call { i1, i32, i32 } @parse(i32 %hValue)
+  %ok3 = extractvalue { i1, i32, i32 } %3, 0
+  br i1 %ok3, label %try_ok2, label %try_error1
+
+try_error1:                                       ; preds = %try_ok
+  %error4 = extractvalue { i1, i32, i32 } %3, 2
+  %4 = insertvalue { i1, i64, i32 } zeroinitializer, i32 %error4, 2
+  ret { i1, i64, i32 } %4
+
+try_ok2:                                          ; preds = %try_ok
+  %value5 = extractvalue { i1, i32, i32 } %3, 1
+  %x = alloca i32, align 4
+  store i32 %value5, ptr %x, align 4
+  %xValue = load i32, ptr %x, align 4
+  %5 = sext i32 %xValue to i64
+  %6 = insertvalue { i1, i64, i32 } { i1 true, i64 0, i32 0 }, i64 %5, 1
+  ret { i1, i64, i32 } %6
+}
+
+define { i1, i32, i32 } @safe(i32 %0) {
+entry:
+  %1 = call { i1, i32, i32 } @half(i32 %0)
+  %ok = extractvalue { i1, i32, i32 } %1, 0
+  br i1 %ok, label %try_ok, label %try_error
+
+try_error:                                        ; preds = %entry
+  %error = extractvalue { i1, i32, i32 } %1, 2
+  %2 = insertvalue { i1, i32, i32 } zeroinitializer, i32 %error, 2
+  ret { i1, i32, i32 } %2
+
+try_ok:                                           ; preds = %entry
+  %value = extractvalue { i1, i32, i32 } %1, 1
+  %v = alloca i32, align 4
+  store i32 %value, ptr %v, align 4
+  %vValue = load i32, ptr %v, align 4
+  %3 = add i32 %vValue, 1
+  %4 = insertvalue { i1, i32, i32 } { i1 true, i32 0, i32 0 }, i32 %3, 1
+  ret { i1, i32, i32 } %4
+}
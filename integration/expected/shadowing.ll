@@ -0,0 +1,37 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i32 @f()
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @f() {
+entry:
+  %x = alloca i32, align 4
+  store i32 1, ptr %x, align 4
+  %for_init_i = alloca i32, align 4
+  store i32 0, ptr %for_init_i, align 4
+  br label %loop
+
+loop:                                             ; preds = %loop, %entry
+  %x1 = alloca i32, align 4
+  store i32 2, ptr %x1, align 4
+  %xValue = load i32, ptr %x1, align 4
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %xValue)
+  %for_init_i_value = load i32, ptr %for_init_i, align 4
+  %for_init_i_value_updated = add i32 %for_init_i_value, 1
+  store i32 %for_init_i_value_updated, ptr %for_init_i, align 4
+  %loopCond = icmp ule i32 %for_init_i_value_updated, 3
+  br i1 %loopCond, label %loop, label %end
+
+end:                                              ; preds = %loop
+  %xValue2 = load i32, ptr %x, align 4
+  ret i32 %xValue2
+}
@@ -0,0 +1,69 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.a = internal global i32 16, align 4
+@main.b = internal global i32 65, align 4
+@main.c = internal global i8 -16, align 1
+@main.n = internal global i64 40, align 8
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %aValue = load i32, ptr @main.a, align 4
+  %nValue = load i64, ptr @main.n, align 4
+  %0 = trunc i64 %nValue to i32
+  %shift_amount = and i32 %0, 31
+  %1 = shl i32 %aValue, %shift_amount
+  %d = alloca i32, align 4
+  store i32 %1, ptr %d, align 4
+  %aValue1 = load i32, ptr @main.a, align 4
+  %2 = ashr i32 %aValue1, 1
+  %3 = insertvalue { i1, i32 } { i1 true, i32 0 }, i32 %2, 1
+  %4 = insertvalue { i1, { i1, i32 } } { i1 true, { i1, i32 } zeroinitializer }, { i1, i32 } %3, 1
+  %o = alloca { i1, { i1, i32 } }, align 4
+  store { i1, { i1, i32 } } %4, ptr %o, align 4
+  %aValue2 = load i32, ptr @main.a, align 4
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %aValue2)
+  %bValue = load i32, ptr @main.b, align 4
+  %6 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %bValue)
+  %cValue = load i8, ptr @main.c, align 1
+  %7 = sext i8 %cValue to i32
+  %8 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %7)
+  %dValue = load i32, ptr %d, align 4
+  %9 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %dValue)
+  %10 = call i64 @bit(i32 33)
+  %11 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %10)
+  %oValue = load { i1, { i1, i32 } }, ptr %o, align 4
+  %present = extractvalue { i1, { i1, i32 } } %oValue, 0
+  %value = extractvalue { i1, { i1, i32 } } %oValue, 1
+  br i1 %present, label %option_done, label %option_none
+
+option_none:                                      ; preds = %entry
+  br label %option_done
+
+option_done:                                      ; preds = %option_none, %entry
+  %unwrapped = phi { i1, i32 } [ %value, %entry ], [ zeroinitializer, %option_none ]
+  %present5 = extractvalue { i1, i32 } %unwrapped, 0
+  %value6 = extractvalue { i1, i32 } %unwrapped, 1
+  br i1 %present5, label %option_done4, label %option_none3
+
+option_none3:                                     ; preds = %option_done
+  br label %option_done4
+
+option_done4:                                     ; preds = %option_none3, %option_done
+  %unwrapped7 = phi i32 [ %value6, %option_done ], [ 0, %option_none3 ]
+  %12 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %unwrapped7)
+  %13 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 32)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i64 @bit(i32 %0) {
+entry:
+  %1 = zext i32 %0 to i64
+  %shift_amount = and i64 %1, 63
+  %2 = shl i64 1, %shift_amount
+  ret i64 %2
+}
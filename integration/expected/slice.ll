@@ -0,0 +1,167 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@main.e = internal global { ptr, i64, i64 } zeroinitializer, align 8
+@main.a = internal global [3 x i32] [i32 1, i32 2, i32 3], align 4
+
+define i32 @main() {
+entry:
+  %0 = call ptr @malloc(i64 mul (i64 ptrtoint (ptr getelementptr (i64, ptr null, i32 1) to i64), i64 3))
+  %1 = getelementptr inbounds i64, ptr %0, i64 0
+  store i64 1, ptr %1, align 4
+  %2 = getelementptr inbounds i64, ptr %0, i64 1
+  store i64 2, ptr %2, align 4
+  %3 = getelementptr inbounds i64, ptr %0, i64 2
+  store i64 3, ptr %3, align 4
+  %4 = insertvalue { ptr, i64, i64 } zeroinitializer, ptr %0, 0
+  %5 = insertvalue { ptr, i64, i64 } %4, i64 3, 1
+  %6 = insertvalue { ptr, i64, i64 } %5, i64 3, 2
+  %s = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } %6, ptr %s, align 8
+  %sValue = load { ptr, i64, i64 }, ptr %s, align 8
+  %7 = call { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %sValue, i64 ptrtoint (ptr getelementptr (i64, ptr null, i32 1) to i64))
+  %8 = extractvalue { ptr, i64, i64 } %7, 0
+  %9 = extractvalue { ptr, i64, i64 } %7, 1
+  %10 = getelementptr inbounds i64, ptr %8, i64 %9
+  store i64 4, ptr %10, align 4
+  %11 = add i64 %9, 1
+  %12 = insertvalue { ptr, i64, i64 } %7, i64 %11, 1
+  %13 = call { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %12, i64 ptrtoint (ptr getelementptr (i64, ptr null, i32 1) to i64))
+  %14 = extractvalue { ptr, i64, i64 } %13, 0
+  %15 = extractvalue { ptr, i64, i64 } %13, 1
+  %16 = getelementptr inbounds i64, ptr %14, i64 %15
+  store i64 5, ptr %16, align 4
+  %17 = add i64 %15, 1
+  %18 = insertvalue { ptr, i64, i64 } %13, i64 %17, 1
+  store { ptr, i64, i64 } %18, ptr %s, align 8
+  %sValue1 = load { ptr, i64, i64 }, ptr %s, align 8
+  %19 = call { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %sValue1, i64 ptrtoint (ptr getelementptr (i64, ptr null, i32 1) to i64))
+  %20 = extractvalue { ptr, i64, i64 } %19, 0
+  %21 = extractvalue { ptr, i64, i64 } %19, 1
+  %22 = getelementptr inbounds i64, ptr %20, i64 %21
+  store i64 6, ptr %22, align 4
+  %23 = add i64 %21, 1
+  %24 = insertvalue { ptr, i64, i64 } %19, i64 %23, 1
+  store { ptr, i64, i64 } %24, ptr %s, align 8
+  %25 = load { ptr, i64, i64 }, ptr %s, align 8
+  %26 = extractvalue { ptr, i64, i64 } %25, 0
+  %27 = getelementptr inbounds i64, ptr %26, i64 0
+  store i64 10, ptr %27, align 4
+  %sValue2 = load { ptr, i64, i64 }, ptr %s, align 8
+  %28 = extractvalue { ptr, i64, i64 } %sValue2, 1
+  %29 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %28)
+  %sValue3 = load { ptr, i64, i64 }, ptr %s, align 8
+  %30 = extractvalue { ptr, i64, i64 } %sValue3, 2
+  %31 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %30)
+  %32 = load { ptr, i64, i64 }, ptr %s, align 8
+  %33 = extractvalue { ptr, i64, i64 } %32, 0
+  %34 = getelementptr inbounds i64, ptr %33, i64 5
+  %35 = load i64, ptr %34, align 4
+  %36 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %35)
+  %eValue = load { ptr, i64, i64 }, ptr @main.e, align 8
+  %37 = extractvalue { ptr, i64, i64 } %eValue, 2
+  %38 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %37)
+  %eValue4 = load { ptr, i64, i64 }, ptr @main.e, align 8
+  %39 = call { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %eValue4, i64 ptrtoint (ptr getelementptr (i32, ptr null, i32 1) to i64))
+  %40 = extractvalue { ptr, i64, i64 } %39, 0
+  %41 = extractvalue { ptr, i64, i64 } %39, 1
+  %42 = getelementptr inbounds i32, ptr %40, i64 %41
+  store i32 1, ptr %42, align 4
+  %43 = add i64 %41, 1
+  %44 = insertvalue { ptr, i64, i64 } %39, i64 %43, 1
+  store { ptr, i64, i64 } %44, ptr @main.e, align 8
+  %eValue5 = load { ptr, i64, i64 }, ptr @main.e, align 8
+  %45 = extractvalue { ptr, i64, i64 } %eValue5, 2
+  %46 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %45)
+  %aValue = load [3 x i32], ptr @main.a, align 4
+  %47 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 3)
+  %for_init_i = alloca i32, align 4
+  store i32 0, ptr %for_init_i, align 4
+  br label %loop
+
+loop:                                             ; preds = %loop, %entry
+  %sValue6 = load { ptr, i64, i64 }, ptr %s, align 8
+  %48 = call { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %sValue6, i64 ptrtoint (ptr getelementptr (i64, ptr null, i32 1) to i64))
+  %49 = extractvalue { ptr, i64, i64 } %48, 0
+  %50 = extractvalue { ptr, i64, i64 } %48, 1
+  %51 = getelementptr inbounds i64, ptr %49, i64 %50
+  store i64 7, ptr %51, align 4
+  %52 = add i64 %50, 1
+  %53 = insertvalue { ptr, i64, i64 } %48, i64 %52, 1
+  store { ptr, i64, i64 } %53, ptr %s, align 8
+  %for_init_i_value = load i32, ptr %for_init_i, align 4
+  %for_init_i_value_updated = add i32 %for_init_i_value, 1
+  store i32 %for_init_i_value_updated, ptr %for_init_i, align 4
+  %loopCond = icmp ule i32 %for_init_i_value_updated, 2
+  br i1 %loopCond, label %loop, label %end
+
+end:                                              ; preds = %loop
+  %sValue7 = load { ptr, i64, i64 }, ptr %s, align 8
+  %54 = extractvalue { ptr, i64, i64 } %sValue7, 1
+  %55 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %54)
+  %sValue8 = load { ptr, i64, i64 }, ptr %s, align 8
+  %56 = call i64 @sum({ ptr, i64, i64 } %sValue8)
+  %57 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %56)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i64 @sum({ ptr, i64, i64 } %0) {
+entry:
+  %total = alloca i64, align 8
+  store i64 0, ptr %total, align 4
+  %for_init_i = alloca i32, align 4
+  store i32 0, ptr %for_init_i, align 4
+  br label %loop
+
+loop:                                             ; preds = %loop, %entry
+  %totalValue = load i64, ptr %total, align 4
+  %1 = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } %0, ptr %1, align 8
+  %iValue = load i32, ptr %for_init_i, align 4
+  %2 = sext i32 %iValue to i64
+  %3 = load { ptr, i64, i64 }, ptr %1, align 8
+  %4 = extractvalue { ptr, i64, i64 } %3, 0
+  %5 = getelementptr inbounds i64, ptr %4, i64 %2
+  %6 = load i64, ptr %5, align 4
+  %7 = add i64 %totalValue, %6
+  store i64 %7, ptr %total, align 4
+  %for_init_i_value = load i32, ptr %for_init_i, align 4
+  %for_init_i_value_updated = add i32 %for_init_i_value, 1
+  store i32 %for_init_i_value_updated, ptr %for_init_i, align 4
+  %loopCond = icmp ule i32 %for_init_i_value_updated, 8
+  br i1 %loopCond, label %loop, label %end
+
+end:                                              ; preds = %loop
+  %totalValue1 = load i64, ptr %total, align 4
+  ret i64 %totalValue1
+}
+
+declare ptr @malloc(i64)
+
+define internal { ptr, i64, i64 } @__gusty_slice_reserve({ ptr, i64, i64 } %0, i64 %1) {
+entry:
+  %length = extractvalue { ptr, i64, i64 } %0, 1
+  %capacity = extractvalue { ptr, i64, i64 } %0, 2
+  %full = icmp eq i64 %length, %capacity
+  br i1 %full, label %grow, label %done
+
+grow:                                             ; preds = %entry
+  %empty = icmp eq i64 %capacity, 0
+  %doubled = mul i64 %capacity, 2
+  %new_capacity = select i1 %empty, i64 4, i64 %doubled
+  %size = mul i64 %new_capacity, %1
+  %data = extractvalue { ptr, i64, i64 } %0, 0
+  %new_data = call ptr @realloc(ptr %data, i64 %size)
+  %2 = insertvalue { ptr, i64, i64 } %0, ptr %new_data, 0
+  %3 = insertvalue { ptr, i64, i64 } %2, i64 %new_capacity, 2
+  ret { ptr, i64, i64 } %3
+
+done:                                             ; preds = %entry
+  ret { ptr, i64, i64 } %0
+}
+
+declare ptr @realloc(ptr, i64)
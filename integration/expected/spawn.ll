@@ -0,0 +1,71 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %spawn_parameters = call ptr @malloc(i64 ptrtoint (ptr getelementptr ({ i32, i64 }, ptr null, i32 1) to i64))
+  %0 = getelementptr inbounds { i32, i64 }, ptr %spawn_parameters, i32 0, i32 0
+  store i32 1, ptr %0, align 4
+  %1 = getelementptr inbounds { i32, i64 }, ptr %spawn_parameters, i32 0, i32 1
+  store i64 10, ptr %1, align 4
+  %thread = alloca i64, align 8
+  %2 = call i32 @pthread_create(ptr %thread, ptr null, ptr @__gusty_spawn_work, ptr %spawn_parameters)
+  %3 = load i64, ptr %thread, align 4
+  %first = alloca i64, align 8
+  store i64 %3, ptr %first, align 4
+  %firstValue = load i64, ptr %first, align 4
+  %4 = call i32 @pthread_join(i64 %firstValue, ptr null)
+  %5 = call i64 @start(i64 20)
+  %6 = call i32 @pthread_join(i64 %5, ptr null)
+  %spawn_parameters1 = call ptr @malloc(i64 ptrtoint (ptr getelementptr ({ i32, i64 }, ptr null, i32 1) to i64))
+  %7 = getelementptr inbounds { i32, i64 }, ptr %spawn_parameters1, i32 0, i32 0
+  store i32 3, ptr %7, align 4
+  %8 = getelementptr inbounds { i32, i64 }, ptr %spawn_parameters1, i32 0, i32 1
+  store i64 30, ptr %8, align 4
+  %thread2 = alloca i64, align 8
+  %9 = call i32 @pthread_create(ptr %thread2, ptr null, ptr @__gusty_spawn_work, ptr %spawn_parameters1)
+  %10 = load i64, ptr %thread2, align 4
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define void @work(i32 %0, i64 %1) {
+entry:
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  ret void
+}
+
+define i64 @start(i64 %0) {
+entry:
+  %spawn_parameters = call ptr @malloc(i64 ptrtoint (ptr getelementptr ({ i32, i64 }, ptr null, i32 1) to i64))
+  %1 = getelementptr inbounds { i32, i64 }, ptr %spawn_parameters, i32 0, i32 0
+  store i32 2, ptr %1, align 4
+  %2 = getelementptr inbounds { i32, i64 }, ptr %spawn_parameters, i32 0, i32 1
+  store i64 %0, ptr %2, align 4
+  %thread = alloca i64, align 8
+  %3 = call i32 @pthread_create(ptr %thread, ptr null, ptr @__gusty_spawn_work, ptr %spawn_parameters)
+  %4 = load i64, ptr %thread, align 4
+  ret i64 %4
+}
+
+declare ptr @malloc(i64)
+
+define internal ptr @__gusty_spawn_work(ptr %0) {
+entry:
+  %1 = getelementptr inbounds { i32, i64 }, ptr %0, i32 0, i32 0
+  %2 = load i32, ptr %1, align 4
+  %3 = getelementptr inbounds { i32, i64 }, ptr %0, i32 0, i32 1
+  %4 = load i64, ptr %3, align 4
+  call void @free(ptr %0)
+  call void @work(i32 %2, i64 %4)
+  ret ptr null
+}
+
+declare void @free(ptr)
+
+declare i32 @pthread_create(ptr, ptr, ptr, ptr)
+
+declare i32 @pthread_join(i64, ptr)
@@ -0,0 +1,63 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_string = private unnamed_addr constant [7 x i8] c"hello \00", align 1
+@__gusty_string.1 = private unnamed_addr constant [4 x i8] c"foo\00", align 1
+@main.a = internal global ptr @__gusty_string.1, align 8
+@__gusty_string.2 = private unnamed_addr constant [4 x i8] c"bar\00", align 1
+@__gusty_format_string_string = constant [4 x i8] c"%s\0A\00"
+@__gusty_string.3 = private unnamed_addr constant [6 x i8] c"gusty\00", align 1
+@__gusty_string.4 = private unnamed_addr constant [2 x i8] c"!\00", align 1
+@__gusty_string.5 = private unnamed_addr constant [6 x i8] c"line\0A\00", align 1
+
+define i32 @main() {
+entry:
+  %aValue = load ptr, ptr @main.a, align 8
+  %concat = call ptr @__gusty_string_concat(ptr %aValue, ptr @__gusty_string.2)
+  %b = alloca ptr, align 8
+  store ptr %concat, ptr %b, align 8
+  %bValue = load ptr, ptr %b, align 8
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_string, ptr %bValue)
+  %1 = call ptr @greet(ptr @__gusty_string.3)
+  %concat1 = call ptr @__gusty_string_concat(ptr %1, ptr @__gusty_string.4)
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_string, ptr %concat1)
+  %aValue2 = load ptr, ptr @main.a, align 8
+  %bValue3 = load ptr, ptr %b, align 8
+  %concat4 = call ptr @__gusty_string_concat(ptr %aValue2, ptr %bValue3)
+  %aValue5 = load ptr, ptr @main.a, align 8
+  %concat6 = call ptr @__gusty_string_concat(ptr %concat4, ptr %aValue5)
+  %c = alloca ptr, align 8
+  store ptr %concat6, ptr %c, align 8
+  %cValue = load ptr, ptr %c, align 8
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_string, ptr %cValue)
+  %4 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_string, ptr @__gusty_string.5)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define ptr @greet(ptr %0) {
+entry:
+  %concat = call ptr @__gusty_string_concat(ptr @__gusty_string, ptr %0)
+  ret ptr %concat
+}
+
+define internal ptr @__gusty_string_concat(ptr %0, ptr %1) {
+entry:
+  %left_length = call i64 @strlen(ptr %0)
+  %right_length = call i64 @strlen(ptr %1)
+  %right_size = add i64 %right_length, 1
+  %size = add i64 %left_length, %right_size
+  %data = call ptr @malloc(i64 %size)
+  %2 = call ptr @memcpy(ptr %data, ptr %0, i64 %left_length)
+  %end = getelementptr inbounds i8, ptr %data, i64 %left_length
+  %3 = call ptr @memcpy(ptr %end, ptr %1, i64 %right_size)
+  ret ptr %data
+}
+
+declare i64 @strlen(ptr)
+
+declare ptr @malloc(i64)
+
+declare ptr @memcpy(ptr, ptr, i64)
@@ -0,0 +1,56 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_string = private unnamed_addr constant [6 x i8] c"hello\00", align 1
+@main.s = internal global ptr @__gusty_string, align 8
+@main.a = internal global [3 x i32] [i32 1, i32 2, i32 3], align 4
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@__gusty_string.1 = private unnamed_addr constant [7 x i8] c" gusty\00", align 1
+@__gusty_string.2 = private unnamed_addr constant [5 x i8] c"four\00", align 1
+@__gusty_string.3 = private unnamed_addr constant [1 x i8] zeroinitializer, align 1
+
+define i32 @main() {
+entry:
+  %sValue = load ptr, ptr @main.s, align 8
+  %0 = call i64 @strlen(ptr %sValue)
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %0)
+  %sValue1 = load ptr, ptr @main.s, align 8
+  %concat = call ptr @__gusty_string_concat(ptr %sValue1, ptr @__gusty_string.1)
+  %2 = call i64 @strlen(ptr %concat)
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %2)
+  %4 = call i64 @size(ptr @__gusty_string.2)
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %4)
+  %aValue = load [3 x i32], ptr @main.a, align 4
+  %6 = call i64 @strlen(ptr @__gusty_string.3)
+  %7 = add i64 3, %6
+  %8 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %7)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i64 @size(ptr %0) {
+entry:
+  %1 = call i64 @strlen(ptr %0)
+  ret i64 %1
+}
+
+declare i64 @strlen(ptr)
+
+define internal ptr @__gusty_string_concat(ptr %0, ptr %1) {
+entry:
+  %left_length = call i64 @strlen(ptr %0)
+  %right_length = call i64 @strlen(ptr %1)
+  %right_size = add i64 %right_length, 1
+  %size = add i64 %left_length, %right_size
+  %data = call ptr @malloc(i64 %size)
+  %2 = call ptr @memcpy(ptr %data, ptr %0, i64 %left_length)
+  %end = getelementptr inbounds i8, ptr %data, i64 %left_length
+  %3 = call ptr @memcpy(ptr %end, ptr %1, i64 %right_size)
+  ret ptr %data
+}
+
+declare ptr @malloc(i64)
+
+declare ptr @memcpy(ptr, ptr, i64)
@@ -0,0 +1,65 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%Point = type { i32, i32 }
+%Line = type { %Point, %Point, { ptr, i64, i64 } }
+%Rect = type { [2 x %Point], i1 }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.p = internal global %Point { i32 1, i32 2 }, align 4
+
+define i32 @main() {
+entry:
+  %pValue = load %Point, ptr @main.p, align 4
+  %0 = call %Point @move(%Point %pValue, i32 3)
+  %q = alloca %Point, align 4
+  store %Point %0, ptr %q, align 4
+  %1 = call %Point @origin()
+  %2 = insertvalue %Line zeroinitializer, %Point %1, 0
+  %qValue = load %Point, ptr %q, align 4
+  %3 = insertvalue %Line %2, %Point %qValue, 1
+  %4 = call ptr @malloc(i64 mul (i64 ptrtoint (ptr getelementptr (i8, ptr null, i32 1) to i64), i64 2))
+  %5 = getelementptr inbounds i8, ptr %4, i64 0
+  store i8 1, ptr %5, align 1
+  %6 = getelementptr inbounds i8, ptr %4, i64 1
+  store i8 2, ptr %6, align 1
+  %7 = insertvalue { ptr, i64, i64 } zeroinitializer, ptr %4, 0
+  %8 = insertvalue { ptr, i64, i64 } %7, i64 2, 1
+  %9 = insertvalue { ptr, i64, i64 } %8, i64 2, 2
+  %10 = insertvalue %Line %3, { ptr, i64, i64 } %9, 2
+  %l = alloca %Line, align 8
+  store %Line %10, ptr %l, align 8
+  %lValue = load %Line, ptr %l, align 8
+  %11 = call ptr @malloc(i64 ptrtoint (ptr getelementptr (%Line, ptr null, i32 1) to i64))
+  %12 = getelementptr inbounds %Line, ptr %11, i64 0
+  store %Line %lValue, ptr %12, align 8
+  %13 = insertvalue { ptr, i64, i64 } zeroinitializer, ptr %11, 0
+  %14 = insertvalue { ptr, i64, i64 } %13, i64 1, 1
+  %15 = insertvalue { ptr, i64, i64 } %14, i64 1, 2
+  %ls = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } %15, ptr %ls, align 8
+  %pValue1 = load %Point, ptr @main.p, align 4
+  %16 = insertvalue [2 x %Point] zeroinitializer, %Point %pValue1, 0
+  %qValue2 = load %Point, ptr %q, align 4
+  %17 = insertvalue [2 x %Point] %16, %Point %qValue2, 1
+  %18 = insertvalue %Rect zeroinitializer, [2 x %Point] %17, 0
+  %r = alloca %Rect, align 4
+  store %Rect %18, ptr %r, align 4
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define %Point @origin() {
+entry:
+  ret %Point zeroinitializer
+}
+
+define %Point @move(%Point %0, i32 %1) {
+entry:
+  %2 = insertvalue %Point zeroinitializer, i32 %1, 0
+  %3 = insertvalue %Point %2, i32 2, 1
+  ret %Point %3
+}
+
+declare ptr @malloc(i64)
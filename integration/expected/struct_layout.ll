@@ -0,0 +1,53 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%Entry = type <{ i8, [15 x i8], i64, %Header, [3 x i8] }>
+%Header = type <{ i8, i32 }>
+%Pair = type <{ i8, [3 x i8], i16, [2 x i8] }>
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.e = internal global %Entry <{ i8 1, [15 x i8] zeroinitializer, i64 2, %Header zeroinitializer, [3 x i8] zeroinitializer }>, align 16
+@main.p = internal global %Pair <{ i8 0, [3 x i8] zeroinitializer, i16 3, [2 x i8] zeroinitializer }>, align 4
+
+define i32 @main() {
+entry:
+  store i32 9, ptr getelementptr inbounds (%Entry, ptr @main.e, i32 0, i32 3, i32 1), align 1
+  %0 = call i32 @sum(ptr @main.e)
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  %2 = load i16, ptr getelementptr inbounds (%Pair, ptr @main.p, i32 0, i32 2), align 4
+  %3 = add i16 %2, 1
+  store i16 %3, ptr getelementptr inbounds (%Pair, ptr @main.p, i32 0, i32 2), align 4
+  %4 = load i16, ptr getelementptr inbounds (%Pair, ptr @main.p, i32 0, i32 2), align 4
+  %5 = sext i16 %4 to i32
+  %6 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %5)
+  %7 = call ptr @malloc(i64 5)
+  store %Header zeroinitializer, ptr %7, align 1
+  %h = alloca ptr, align 8
+  store ptr %7, ptr %h, align 8
+  %8 = load ptr, ptr %h, align 8
+  %9 = getelementptr inbounds %Header, ptr %8, i32 0, i32 1
+  store i32 5, ptr %9, align 1
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @sum(ptr %0) {
+entry:
+  %1 = alloca ptr, align 8
+  store ptr %0, ptr %1, align 8
+  %2 = load ptr, ptr %1, align 8
+  %3 = getelementptr inbounds %Entry, ptr %2, i32 0, i32 3
+  %4 = getelementptr inbounds %Header, ptr %3, i32 0, i32 1
+  %5 = load i32, ptr %4, align 1
+  %6 = alloca ptr, align 8
+  store ptr %0, ptr %6, align 8
+  %7 = load ptr, ptr %6, align 8
+  %8 = getelementptr inbounds %Entry, ptr %7, i32 0, i32 2
+  %9 = load i64, ptr %8, align 16
+  %10 = trunc i64 %9 to i32
+  %11 = add i32 %5, %10
+  ret i32 %11
+}
+
+declare ptr @malloc(i64)
@@ -0,0 +1,86 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call ptr @malloc(i64 8)
+  store i64 0, ptr %0, align 8
+  %counter = alloca ptr, align 8
+  store ptr %0, ptr %counter, align 8
+  %mutex = call ptr @malloc(i64 64)
+  %1 = call i32 @pthread_mutex_init(ptr %mutex, ptr null)
+  %2 = ptrtoint ptr %mutex to i64
+  %m = alloca i64, align 8
+  store i64 %2, ptr %m, align 4
+  %counterValue = load ptr, ptr %counter, align 8
+  %mValue = load i64, ptr %m, align 4
+  %spawn_parameters = call ptr @malloc(i64 ptrtoint (ptr getelementptr ({ ptr, i64 }, ptr null, i32 1) to i64))
+  %3 = getelementptr inbounds { ptr, i64 }, ptr %spawn_parameters, i32 0, i32 0
+  store ptr %counterValue, ptr %3, align 8
+  %4 = getelementptr inbounds { ptr, i64 }, ptr %spawn_parameters, i32 0, i32 1
+  store i64 %mValue, ptr %4, align 4
+  %thread = alloca i64, align 8
+  %5 = call i32 @pthread_create(ptr %thread, ptr null, ptr @__gusty_spawn_work, ptr %spawn_parameters)
+  %6 = load i64, ptr %thread, align 4
+  %worker = alloca i64, align 8
+  store i64 %6, ptr %worker, align 4
+  %workerValue = load i64, ptr %worker, align 4
+  %7 = call i32 @pthread_join(i64 %workerValue, ptr null)
+  %counterValue1 = load ptr, ptr %counter, align 8
+  %counterValue2 = load ptr, ptr %counter, align 8
+  %8 = load atomic i64, ptr %counterValue2 seq_cst, align 8
+  %9 = add i64 %8, 1
+  store atomic i64 %9, ptr %counterValue1 seq_cst, align 8
+  %10 = call ptr @malloc(i64 8)
+  store ptr null, ptr %10, align 8
+  %p = alloca ptr, align 8
+  store ptr %10, ptr %p, align 8
+  %pValue = load ptr, ptr %p, align 8
+  %counterValue3 = load ptr, ptr %counter, align 8
+  store atomic ptr %counterValue3, ptr %pValue seq_cst, align 8
+  %pValue4 = load ptr, ptr %p, align 8
+  %11 = load atomic ptr, ptr %pValue4 seq_cst, align 8
+  %12 = load i64, ptr %11, align 4
+  %13 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %12)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define void @work(ptr %0, i64 %1) {
+entry:
+  %2 = atomicrmw add ptr %0, i64 1 seq_cst, align 8
+  %3 = inttoptr i64 %1 to ptr
+  %4 = call i32 @pthread_mutex_lock(ptr %3)
+  %5 = inttoptr i64 %1 to ptr
+  %6 = call i32 @pthread_mutex_unlock(ptr %5)
+  ret void
+}
+
+declare i32 @pthread_mutex_lock(ptr)
+
+declare i32 @pthread_mutex_unlock(ptr)
+
+declare ptr @malloc(i64)
+
+declare i32 @pthread_mutex_init(ptr, ptr)
+
+define internal ptr @__gusty_spawn_work(ptr %0) {
+entry:
+  %1 = getelementptr inbounds { ptr, i64 }, ptr %0, i32 0, i32 0
+  %2 = load ptr, ptr %1, align 8
+  %3 = getelementptr inbounds { ptr, i64 }, ptr %0, i32 0, i32 1
+  %4 = load i64, ptr %3, align 4
+  call void @free(ptr %0)
+  call void @work(ptr %2, i64 %4)
+  ret ptr null
+}
+
+declare void @free(ptr)
+
+declare i32 @pthread_create(ptr, ptr, ptr, ptr)
+
+declare i32 @pthread_join(i64, ptr)
@@ -0,0 +1,32 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@main.seed = internal thread_local global i64 42, align 8
+@main.table = internal thread_local global [2 x i32] [i32 1, i32 5], align 4
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i64 @next()
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %0)
+  %2 = call i64 @next()
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %2)
+  %4 = load i32, ptr getelementptr inbounds ([2 x i32], ptr @main.table, i64 0, i64 1), align 4
+  %5 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %4)
+  store i64 7, ptr @main.seed, align 4
+  %seedValue = load i64, ptr @main.seed, align 4
+  %6 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %seedValue)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i64 @next() {
+entry:
+  %seedValue = load i64, ptr @main.seed, align 4
+  %0 = add i64 %seedValue, 1
+  store i64 %0, ptr @main.seed, align 4
+  %seedValue1 = load i64, ptr @main.seed, align 4
+  ret i64 %seedValue1
+}
@@ -0,0 +1,39 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@main.x = internal thread_local global i32 1, align 4
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i32 @f(i32 5)
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  %xValue = load i32, ptr @main.x, align 4
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %xValue)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @f(i32 %0) {
+entry:
+  %1 = add i32 %0, 1
+  %y = alloca i32, align 4
+  store i32 %1, ptr %y, align 4
+  %for_init_i = alloca i32, align 4
+  store i32 0, ptr %for_init_i, align 4
+  br label %loop
+
+loop:                                             ; preds = %loop, %entry
+  %yValue = load i32, ptr %y, align 4
+  %x = alloca i32, align 4
+  store i32 %yValue, ptr %x, align 4
+  %for_init_i_value = load i32, ptr %for_init_i, align 4
+  %for_init_i_value_updated = add i32 %for_init_i_value, 1
+  store i32 %for_init_i_value_updated, ptr %for_init_i, align 4
+  %loopCond = icmp ule i32 %for_init_i_value_updated, 2
+  br i1 %loopCond, label %loop, label %end
+
+end:                                              ; preds = %loop
+  ret i32 %0
+}
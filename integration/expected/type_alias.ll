@@ -0,0 +1,76 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+%Point = type { i32, i32 }
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i32 @next(i32 5)
+  %a = alloca i32, align 4
+  store i32 %0, ptr %a, align 4
+  %aValue = load i32, ptr %a, align 4
+  %1 = call ptr @malloc(i64 mul (i64 ptrtoint (ptr getelementptr (i32, ptr null, i32 1) to i64), i64 2))
+  %2 = getelementptr inbounds i32, ptr %1, i64 0
+  store i32 %aValue, ptr %2, align 4
+  %3 = getelementptr inbounds i32, ptr %1, i64 1
+  store i32 2, ptr %3, align 4
+  %4 = insertvalue { ptr, i64, i64 } zeroinitializer, ptr %1, 0
+  %5 = insertvalue { ptr, i64, i64 } %4, i64 2, 1
+  %6 = insertvalue { ptr, i64, i64 } %5, i64 2, 2
+  %s = alloca { ptr, i64, i64 }, align 8
+  store { ptr, i64, i64 } %6, ptr %s, align 8
+  %aValue1 = load i32, ptr %a, align 4
+  %7 = insertvalue { i1, i32 } { i1 true, i32 0 }, i32 %aValue1, 1
+  %m = alloca { i1, i32 }, align 4
+  store { i1, i32 } %7, ptr %m, align 4
+  %aValue2 = load i32, ptr %a, align 4
+  %8 = insertvalue %Point zeroinitializer, i32 %aValue2, 0
+  %9 = load { ptr, i64, i64 }, ptr %s, align 8
+  %10 = extractvalue { ptr, i64, i64 } %9, 0
+  %11 = getelementptr inbounds i32, ptr %10, i64 1
+  %12 = load i32, ptr %11, align 4
+  %13 = insertvalue %Point %8, i32 %12, 1
+  %p = alloca %Point, align 4
+  store %Point %13, ptr %p, align 4
+  %q = alloca ptr, align 8
+  store ptr %p, ptr %q, align 8
+  %aValue3 = load i32, ptr %a, align 4
+  %w = alloca i32, align 4
+  store i32 %aValue3, ptr %w, align 4
+  %aValue4 = load i32, ptr %a, align 4
+  %14 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %aValue4)
+  %sValue = load { ptr, i64, i64 }, ptr %s, align 8
+  %15 = extractvalue { ptr, i64, i64 } %sValue, 1
+  %16 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %15)
+  %mValue = load { i1, i32 }, ptr %m, align 4
+  %present = extractvalue { i1, i32 } %mValue, 0
+  %value = extractvalue { i1, i32 } %mValue, 1
+  br i1 %present, label %option_done, label %option_none
+
+option_none:                                      ; preds = %entry
+  br label %option_done
+
+option_done:                                      ; preds = %option_none, %entry
+  %unwrapped = phi i32 [ %value, %entry ], [ 0, %option_none ]
+  %17 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %unwrapped)
+  %18 = load ptr, ptr %q, align 8
+  %19 = getelementptr inbounds %Point, ptr %18, i32 0, i32 1
+  %20 = load i32, ptr %19, align 4
+  %21 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %20)
+  %wValue = load i32, ptr %w, align 4
+  %22 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %wValue)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @next(i32 %0) {
+entry:
+  %1 = add i32 %0, 1
+  ret i32 %1
+}
+
+declare ptr @malloc(i64)
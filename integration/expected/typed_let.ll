@@ -0,0 +1,27 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.a = internal global i32 5, align 4
+@main.b = internal global i64 5000000000, align 8
+@main.c = internal global float 2.000000e+00, align 4
+@main.d = internal global double 2.500000e+00, align 8
+@main.e = internal global i1 true, align 1
+@main.f = internal global i8 -128, align 1
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+@__gusty_format_string_f64 = constant [4 x i8] c"%f\0A\00"
+
+define i32 @main() {
+entry:
+  %bValue = load i64, ptr @main.b, align 4
+  %0 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %bValue)
+  %cValue = load float, ptr @main.c, align 4
+  %1 = fpext float %cValue to double
+  %2 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_f64, double %1)
+  %fValue = load i8, ptr @main.f, align 1
+  %3 = sext i8 %fValue to i32
+  %4 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %3)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
@@ -0,0 +1,44 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+@main.counter = internal global i64 0, align 8
+@main.flag = internal global i1 true, align 1
+@main.hits = internal global i32 1, align 4
+@__gusty_format_string_i64 = constant [5 x i8] c"%ld\0A\00"
+
+define i32 @main() {
+entry:
+  %counterValue = load atomic i64, ptr @main.counter seq_cst, align 8
+  %0 = add i64 %counterValue, 5
+  store atomic i64 %0, ptr @main.counter seq_cst, align 8
+  store volatile i1 false, ptr @main.flag, align 1
+  %hitsValue = load atomic i32, ptr @main.hits monotonic, align 4
+  %counterValue1 = load atomic i64, ptr @main.counter seq_cst, align 8
+  %1 = trunc i64 %counterValue1 to i32
+  %2 = add i32 %hitsValue, %1
+  store atomic i32 %2, ptr @main.hits monotonic, align 4
+  call void @bump()
+  %counterValue2 = load atomic i64, ptr @main.counter seq_cst, align 8
+  %3 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string_i64, i64 %counterValue2)
+  %hitsValue3 = load atomic i32, ptr @main.hits monotonic, align 4
+  %4 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %hitsValue3)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define void @bump() {
+entry:
+  %local = alloca i32, align 4
+  store i32 2, ptr %local, align 4
+  %localValue = load i32, ptr %local, align 4
+  %seen = alloca i32, align 4
+  store atomic volatile i32 %localValue, ptr %seen release, align 4
+  %seenValue = load atomic volatile i32, ptr %seen acquire, align 4
+  %0 = add i32 %seenValue, 1
+  store atomic volatile i32 %0, ptr %seen release, align 4
+  %seenValue1 = load atomic volatile i32, ptr %seen acquire, align 4
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %seenValue1)
+  ret void
+}
@@ -0,0 +1,36 @@
+; ModuleID = 'main'
+source_filename = "main"
+
+@__gusty_format_string = constant [4 x i8] c"%d\0A\00"
+
+define i32 @main() {
+entry:
+  %0 = call i32 @countdown(i1 false, i32 21)
+  %1 = call i32 (ptr, ...) @printf(ptr @__gusty_format_string, i32 %0)
+  ret i32 0
+}
+
+declare i32 @printf(ptr, ...)
+
+define i32 @double(i32 %0) {
+entry:
+  %1 = add i32 %0, %0
+  ret i32 %1
+}
+
+define i32 @countdown(i1 %0, i32 %1) {
+entry:
+  br i1 %0, label %conditional_true, label %conditional_false
+
+conditional_true:                                 ; preds = %entry
+  %2 = call i32 @double(i32 %1)
+  br label %conditional_done
+
+conditional_false:                                ; preds = %entry
+  %3 = call i32 @countdown(i1 true, i32 %1)
+  br label %conditional_done
+
+conditional_done:                                 ; preds = %conditional_false, %conditional_true
+  %conditional = phi i32 [ %2, %conditional_true ], [ %3, %conditional_false ]
+  ret i32 %conditional
+}
@@ -2,8 +2,19 @@ package integration
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/donutloop/gusty/pkg/lang"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math/rand"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -17,6 +28,211 @@ func TestLet(t *testing.T) {
 	assert(t, generate(t, input), "let")
 }
 
+func TestTypedLet(t *testing.T) {
+	input := `let a = 5 let b: i64 = 5000000000 let c: f32 = 2 let d = 2.5 let e: bool = true let f: i8 = -128 printf(b) printf(c) printf(f)`
+	assert(t, generate(t, input), "typed_let")
+}
+
+func TestGlobalLet(t *testing.T) {
+	input := `struct P { x i32, y i32 } function one() i32 { return 1 } let a = 1 + 2 let b = a let c: [2][2]i64 = [[1, 2], [3]] let p = P{x: 4, y: 5} let d = one() a = 7 printf(a) printf(b) printf(c[1][0]) printf(p.y) printf(d)`
+	assert(t, generate(t, input), "global_let")
+}
+
+func TestGlobalLetString(t *testing.T) {
+	input := `let s = "con" + "cat" + "enated" let t = s + "!" println(s) println(t)`
+	assert(t, generate(t, input), "global_let_string")
+}
+
+func TestTypedLetInvalidInitializer(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let x: i8 = 300`, lang.MessageConstantOverflow, ""},
+		{`let x: bool = 1`, lang.MessageIntegerLiteralType, ""},
+		{`let x: i32 = 2.5`, lang.MessageFloatLiteralType, ""},
+	})
+}
+
+func TestLetExpression(t *testing.T) {
+	input := `function add(a i32, b i32) i32 { return a + b } function twice(n i64) i64 { let r = n + n return r } let a = 40 let b = 2 let x = a + b let y = add(1,2) let z = add(x, y) + 1 let s = x let w: i64 = twice(i64(s) + 1) printf(z) printf(w)`
+	assert(t, generate(t, input), "let_expression")
+}
+
+func TestLetExpressionInvalidInitializer(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let s = other`, lang.MessageVariableNotFound, ""},
+		{`function f() { } let v = f()`, lang.MessageVoidCallAsValue, ""},
+		{`let a = 1 let x: i64 = a`, lang.MessageValueType, ""},
+		{`function f() i32 { let a = 1 }`, lang.MessageMissingReturn, ""},
+	})
+}
+
+func TestArray(t *testing.T) {
+	input := `function zero() [2]i8 { return [0, 0] } function pass(v [4]i32) [4]i32 { return v } let a: [4]i32 = [1, 2, 3, 4] let b = [1.5, 2.5] let c: [3]i64 = [7] let d = pass(a) let e = [c, c] let f = zero() let x = 5 let g = [x, x + 1] let h: [2][2]bool = [[true], [false, true]] let i: [2]f32 = [x as f32, 1]`
+	assert(t, generate(t, input), "array")
+}
+
+func TestArrayInvalidInitializer(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let a: [2]i32 = [1, 2, 3]`, lang.MessageArrayLiteralOverflow, ""},
+		{`let a: [2]i32 = 5`, lang.MessageIntegerLiteralType, ""},
+		{`let a: i32 = [5]`, lang.MessageArrayLiteralType, ""},
+		{`let a: [2]i8 = [1, 300]`, lang.MessageConstantOverflow, ""},
+		{`let a = []`, lang.MessageEmptyArrayLiteral, ""},
+		{`let a = [1, 2.5]`, lang.MessageFloatLiteralType, ""},
+		{`let a = [1] printf(a)`, lang.MessagePrintType, ""},
+		{`let a: [2]i32 = [1, 2] let b: [3]i32 = a`, lang.MessageValueType, ""},
+	})
+}
+
+func TestIndex(t *testing.T) {
+	input := `function get(v [3]i32, i i32) i32 { return v[i] } let a: [3]i32 = [10, 20, 30] let i = 2 a[0] = 5 a[i] = a[0] + a[1] printf(a[2]) printf(get(a, 1)) let m: [2][2]i64 = [[1, 2], [3, 4]] m[1][i as i8 as i32 + -1] = 9 printf(m[1][1]) printf([7, 8][1])`
+	assert(t, generate(t, input), "index")
+}
+
+func TestIndexInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let a = [1, 2] printf(a[2])`, lang.MessageIndexOutOfBounds, ""},
+		{`let a = [1, 2] printf(a[-1])`, lang.MessageIndexOutOfBounds, ""},
+		{`let a = [1, 2] a[2] = 1`, lang.MessageIndexOutOfBounds, ""},
+		{`let a = 1 printf(a[0])`, lang.MessageIndexType, ""},
+		{`let a = [1, 2] printf(a[1.5])`, lang.MessageFloatLiteralType, ""},
+		{`let a = [1, 2] a[0] = 2.5`, lang.MessageFloatLiteralType, ""},
+		{`function f(v [2]i32) { v[0] = 1 }`, lang.MessageAssignToArrayValue, ""},
+	})
+}
+
+func TestReservedIdentifier(t *testing.T) {
+	inputs := []string{
+		`let __gusty_format_string = 1`,
+		`function __gusty_f() { }`,
+		`function f(__gusty_a i32) { }`,
+		`for __gusty_i := 0; __gusty_i < 10; __gusty_i++ { }`,
+	}
+
+	for _, input := range inputs {
+		if _, err := lang.Parse(lang.Tokenize(input)); err == nil {
+			t.Errorf("expected reserved identifier error for %q", input)
+		}
+	}
+}
+
+func TestSlice(t *testing.T) {
+	input := `function sum(s []i64) i64 { let total: i64 = 0 for i := 0; i < 8; i++ { total = total + s[i] } return total } let s: []i64 = [1, 2, 3] s = append(s, 4, 5) s = append(s, 6) s[0] = 10 printf(len(s)) printf(cap(s)) printf(s[5]) let e: []i32 = [] printf(cap(e)) e = append(e, 1) printf(cap(e)) let a = [1, 2, 3] printf(len(a)) for i := 0; i < 2; i++ { s = append(s, 7) } printf(len(s)) printf(sum(s))`
+	assert(t, generate(t, input), "slice")
+}
+
+func TestSliceInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let s: []i32 = [1, 2.5]`, lang.MessageFloatLiteralType, ""},
+		{`let s: []i32 = [1] s = append(s, true)`, lang.MessageBoolLiteralType, ""},
+		{`let a = [1] let b = append(a, 2)`, lang.MessageAppendType, ""},
+		{`let s: []i32 = [1] s = append(s)`, lang.MessageExpectedSliceAndValues, ""},
+		{`let s: []i32 = [1] let n = len(s, s)`, lang.MessageExpectedOneParameter, ""},
+		{`let x = 1 let n = len(x)`, lang.MessageInvalidBuiltinValue, ""},
+		{`let s: []i32 = [1] printf(s)`, lang.MessagePrintType, ""},
+		{`let s: []i32 = [1] let t: []i64 = s`, lang.MessageValueType, ""},
+		{`let s: []i32 = [1] printf(s[-1])`, lang.MessageIndexOutOfBounds, ""},
+		{`function f(s []i32) { s = append(s, 1) }`, lang.MessageAssignToArgument, ""},
+		{`x = 1`, lang.MessageVariableNotFound, ""},
+	})
+}
+
+func TestReservedWord(t *testing.T) {
+	inputs := []string{
+		`let if = 1`,
+		`function package() { }`,
+		`function f(import i32) { }`,
+		`for else := 0; else < 10; else++ { }`,
+		`printf(match)`,
+		`const(1)`,
+		`let x = 1 x = var`,
+	}
+
+	for _, input := range inputs {
+		if _, err := lang.Parse(lang.Tokenize(input)); err == nil {
+			t.Errorf("expected reserved word error for %q", input)
+		}
+	}
+}
+
+func TestStruct(t *testing.T) {
+	input := `function origin() Point { return Point{} } function move(p Point, dx i32) Point { return Point{x: dx, y: 2} } let p: Point = Point{x: 1, y: 2} let q = move(p, 3) let l = Line{from: origin(), to: q, tags: [1, 2]} let ls: []Line = [l] let r = Rect{corners: [p, q]} struct Point { x i32, y i32 } struct Line { from Point to Point tags []i8 } struct Rect { corners [2]Point flag bool }`
+	expected := generate(t, input)
+	// Struct types are named, generating the program again must not rename them
+	if actual := generate(t, input); string(actual) != string(expected) {
+		t.Fatalf("generated LLVM IR differs between runs:\n%s\n%s", expected, actual)
+	}
+	assert(t, expected, "struct")
+}
+
+func TestStructInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`struct A { x i32 } struct A { y i32 }`, lang.MessageDuplicateStruct, "1:20"},
+		{`struct A { x i32 x i64 }`, lang.MessageDuplicateField, ""},
+		{`struct A { b B } struct B { a [2]A }`, lang.MessageRecursiveStruct, ""},
+		{`struct A { b B }`, lang.MessageUnknownType, ""},
+		{`let p: Point = 1`, lang.MessageUnknownType, ""},
+		{`function f(p Point) { }`, lang.MessageUnknownType, ""},
+		{`struct A { x i32 } let a = A{y: 1}`, lang.MessageUnknownField, ""},
+		{`struct A { x i32 } let a = A{x: 1, x: 2}`, lang.MessageDuplicateFieldValue, ""},
+		{`struct A { x i32 } let a = A{x: true}`, lang.MessageBoolLiteralType, ""},
+		{`struct A { x i32 } struct B { x i32 } let a: A = B{}`, lang.MessageValueType, ""},
+		{`struct A { x i32 } printf(A{})`, lang.MessagePrintType, ""},
+		{`function f() { struct A { x i32 } }`, lang.MessageNestedStruct, ""},
+	})
+}
+
+func TestField(t *testing.T) {
+	input := `struct Point { x i32, y i32 } struct Line { from Point to Point } function length(l Line) i32 { return l.to.x + l.from.x } let p = Point{x: 1, y: 2} p.x = 3 p.y = p.x + p.y let l = Line{from: p, to: Point{x: 10}} l.to.y = 4 let ls: []Line = [l, l] ls[1].from.x = 7 let ps: [2]Point = [p] ps[1].x = ps[0].y printf(p.x) printf(l.to.y) printf(ls[1].from.x) printf(length(ls[1])) printf(Point{x: 5}.x) printf(1.5)`
+	assert(t, generate(t, input), "field")
+}
+
+func TestFieldInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`struct A { x i32 } let a = A{} printf(a.y)`, lang.MessageUnknownField, ""},
+		{`struct A { x i32 } let a = A{} a.y = 1`, lang.MessageUnknownField, ""},
+		{`struct A { x i32 } let a = A{} a.x = true`, lang.MessageBoolLiteralType, ""},
+		{`let a = 1 printf(a.x)`, lang.MessageFieldType, ""},
+		{`let a = [1, 2] a.x = 1`, lang.MessageFieldType, ""},
+		{`struct A { x i32 } function f(a A) { a.x = 1 }`, lang.MessageAssignToStructValue, ""},
+		{`struct A { x i32 } let a = A{} a. = 1`, lang.MessageExpectedFieldNameAfterDot, "1:35"},
+	})
+}
+
+func TestPointer(t *testing.T) {
+	input := `struct Node { value i32, next *Node } function set(out *i32, value i32) { *out = value } function sum(n *Node) i32 { return n.value + n.next.value } function grow(p *Node) { p.value = p.value + 10 } let x = 1 set(&x, 5) printf(x) let p = &x *p = *p + 1 printf(x) let second = Node{value: 2} let first = Node{value: 1, next: &second} grow(&first) printf(sum(&first)) first.next.value = 7 printf(second.value) let a: [2]i32 = [1, 2] let q: *i32 = &a[1] *q = 9 printf(a[1]) let pp = &p **pp = 3 printf(x)`
+	assert(t, generate(t, input), "pointer")
+}
+
+func TestPointerInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let p = &1`, lang.MessageAddressOfValue, ""},
+		{`function f(a i32) { let p = &a }`, lang.MessageAddressOfValue, ""},
+		{`let x = 1 printf(*x)`, lang.MessageDereferenceType, ""},
+		{`let x = 1 *x = 2`, lang.MessageDereferenceType, ""},
+		{`let x = 1 let p = &x *p = true`, lang.MessageBoolLiteralType, ""},
+		{`let x = 1 let p: *i64 = &x`, lang.MessageValueType, ""},
+		{`let x = 1 let p = &x printf(p)`, lang.MessagePrintType, ""},
+		{`function f(p *Missing) { }`, lang.MessageUnknownType, ""},
+	})
+}
+
+func TestNew(t *testing.T) {
+	input := `struct Node { value i32, next *Node } function push(head *Node, value i32) *Node { let n = new(Node) n.value = value n.next = head return n } let list = push(push(new(Node), 1), 2) printf(list.value) printf(list.next.value) let counter = new(i64) *counter = *counter + 41 printf(*counter + 1) let buffer = new([4]f64) printf(len(*buffer)) free(counter) free(buffer) let s: []i32 = [1, 2] free(s)`
+	assert(t, generate(t, input), "new")
+}
+
+func TestNewInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let p = new(Missing)`, lang.MessageUnknownType, ""},
+		{`let x = 1 free(x)`, lang.MessageFreeType, ""},
+		{`let p = new(i32) free(p, p)`, lang.MessageExpectedOneParameter, ""},
+		{`let p = new(i32) let x = free(p)`, lang.MessageVoidCallAsValue, ""},
+		{`let p: *i64 = new(i32)`, lang.MessageValueType, ""},
+		{`let p = new()`, lang.MessageExpectedTypeAfterNew, "1:13"},
+		{`let p = new(i32`, lang.MessageUnexpectedEndOfInput, "1:16"},
+	})
+}
+
 func TestAddTwoConst(t *testing.T) {
 	lang.GenerateRandomIdentifier = func() string {
 		return "9b3c24fa-f1d5-4d41-9fd1-0637244ce4f3"
@@ -36,6 +252,28 @@ func TestNestedFor(t *testing.T) {
 	assert(t, generate(t, input), "nested_for")
 }
 
+func TestCast(t *testing.T) {
+	input := `function show(a i64, b f32) { printf(a) printf(b as f64) printf(i8(a)) } let x = 300 printf(i64(x)) printf(x as i8 as i64) printf(f64(x)) printf(i32(true)) printf(2.5 as i32) show(i64(x), f32(x))`
+	assert(t, generate(t, input), "cast")
+}
+
+func TestInvalidCast(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`printf(f64(true))`, lang.MessageInvalidCast, ""},
+	})
+}
+
+func TestManyFunctions(t *testing.T) {
+	input := `function a(x i32) { printf(x) } function b(x i64) { printf(x) } function c(x f64) { printf(x) } function d(x i8) { printf(x) } function e(x i32, y i32) { a(x) a(y) } function f() i32 { return 6 } function g(x f32) { printf(x) } function h() { e(f(), 7) b(8) c(9.5) d(i8(10)) g(f32(11)) } h() a(f()) e(1, 2)`
+	expected := generate(t, input)
+	for i := 0; i < 20; i++ {
+		if actual := generate(t, input); string(actual) != string(expected) {
+			t.Fatalf("generated LLVM IR differs between runs:\n%s\n%s", expected, actual)
+		}
+	}
+	assert(t, expected, "many_functions")
+}
+
 func generate(t *testing.T, input string) []byte {
 	tokens := lang.Tokenize(input)
 	nodes, err := lang.Parse(tokens)
@@ -51,6 +289,67 @@ func generate(t *testing.T, input string) []byte {
 	return []byte(actualLvmIR)
 }
 
+// invalidProgram is a program which doesn't compile, with the ID of the message it fails with and
+// the position of the error, e.g. 1:9, or "" if the error has no position, as most errors of the
+// code generation.
+type invalidProgram struct {
+	input   string
+	message lang.MessageID
+	pos     string
+}
+
+// assertInvalid parses and generates every program and checks that its first error has the message,
+// which may be wrapped by other messages, e.g. of the let statement, and is at the position.
+func assertInvalid(t *testing.T, programs []invalidProgram) {
+	t.Helper()
+	for _, program := range programs {
+		nodes, err := lang.Parse(lang.Tokenize(program.input))
+		if err == nil {
+			_, err = lang.GenerateLLVMIR(nodes)
+		}
+		if err == nil {
+			t.Errorf("expected error %s for %q", program.message, program.input)
+			continue
+		}
+		if syntaxErrors, ok := err.(lang.SyntaxErrors); ok {
+			err = syntaxErrors[0]
+		}
+
+		if !hasMessage(err, program.message) {
+			t.Errorf("expected error %s for %q, got %v", program.message, program.input, err)
+		}
+		pos := ""
+		var parseError *lang.ParseError
+		var diagnostic lang.Diagnostic
+		if errors.As(err, &parseError) {
+			pos = parseError.Pos.String()
+		} else if errors.As(err, &diagnostic) && diagnostic.From.IsValid() {
+			pos = diagnostic.From.String()
+		}
+		if pos != program.pos {
+			t.Errorf("expected the error of %q at %q, got %q", program.input, program.pos, pos)
+		}
+	}
+}
+
+// hasMessage reports whether the error or an error it wraps has the message with the ID.
+func hasMessage(err error, id lang.MessageID) bool {
+	if messageError, ok := err.(*lang.MessageError); ok && messageError.ID == id {
+		return true
+	}
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range e.Unwrap() {
+			if hasMessage(wrapped, id) {
+				return true
+			}
+		}
+	case interface{ Unwrap() error }:
+		return hasMessage(e.Unwrap(), id)
+	}
+	return false
+}
+
 func assert(t *testing.T, actualLvmIR []byte, filename string) {
 	expectedLlvmIR, err := os.ReadFile("./expected/" + filename + ".ll")
 	if err != nil {
@@ -68,3 +367,1855 @@ func assert(t *testing.T, actualLvmIR []byte, filename string) {
 	t.Log("Expected LLVM IR: ")
 	t.Log(string(expectedLlvmIR))
 }
+
+// constructs holds a tiny program for every language construct. Each program is compared on
+// its own against its golden file in expected/constructs, so a regression in the code generation
+// of one construct fails only the test of that construct.
+var constructs = []struct {
+	name  string
+	input string
+}{
+	{"let", `let x = 1`},
+	{"typed_let", `let x: i64 = 1`},
+	{"assignment", `let x = 1 x = 2`},
+	{"add", `let x = 1 let y = x + 2`},
+	{"printf", `printf(1)`},
+	{"function", `function f() {}`},
+	{"call", `function f(a i32) i32 { return a } let x = f(1)`},
+	{"return", `function f() i32 { return 1 }`},
+	{"for", `for i := 0; i < 2; i++ {}`},
+	{"cast", `let x = 1 let y = x as i64`},
+	{"array", `let a = [1, 2]`},
+	{"index", `let a = [1, 2] let x = a[1]`},
+	{"slice", `let s: []i32 = [1, 2]`},
+	{"struct", `struct P { x i32 } let p = P{x: 1}`},
+	{"field", `struct P { x i32 } let p = P{x: 1} let x = p.x`},
+	{"pointer", `let x = 1 let p = &x let y = *p`},
+	{"new", `let p = new(i32)`},
+	{"option", `let o: option<i32> = some(1) let x = unwrap_or(o, 2)`},
+	{"result", `let r: result<i32> = ok(1) let x = unwrap_or(r, 2)`},
+	{"conditional", `let t = true let x = t ? 1 : 2`},
+}
+
+func TestConstructs(t *testing.T) {
+	for _, construct := range constructs {
+		t.Run(construct.name, func(t *testing.T) {
+			assertNormalized(t, generate(t, construct.input), "constructs/"+construct.name)
+		})
+	}
+}
+
+// assertNormalized compares the generated LLVM IR with the golden file after normalizing
+// both, so that comments and blank lines don't cause differences.
+func assertNormalized(t *testing.T, actualLlvmIR []byte, filename string) {
+	expectedLlvmIR, err := os.ReadFile("./expected/" + filename + ".ll")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, actual := normalizeIR(string(expectedLlvmIR)), normalizeIR(string(actualLlvmIR))
+	if expected != actual {
+		t.Errorf("LLVM IR of %s differs from the golden file\nactual:\n%s\nexpected:\n%s", filename, actual, expected)
+	}
+}
+
+// normalizeIR removes comments, trailing white space and blank lines from the LLVM IR.
+func normalizeIR(llvmIR string) string {
+	var lines []string
+	for _, line := range strings.Split(llvmIR, "\n") {
+		if i := strings.Index(line, ";"); i >= 0 && !strings.Contains(line[:i], "\"") {
+			line = line[:i]
+		}
+		if line = strings.TrimRight(line, " \t"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestStats(t *testing.T) {
+	input := `struct P { x i32 } function f(n i32) i32 { for i := 0; i < 3; i++ { for j := 0; j < 2; j++ { printf(j) } } return n } function g() { printf(1) } let a = f(2) g()`
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := lang.ProgramStats{
+		Functions:  2,
+		Statements: 7,
+		Loops:      2,
+		MaxDepth:   3,
+		Complexity: []lang.FunctionComplexity{{Name: "f", Complexity: 3}, {Name: "g", Complexity: 1}},
+	}
+	if actual := lang.Stats(nodes); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected stats %+v, got %+v", expected, actual)
+	}
+
+	// Conditional and try expressions are decision points as well
+	input = `function half(n i32) result<i32> { return ok(n) } function f(n i32) result<i32> { let h = try half(n) return ok(h > 1 ? h : n > 0 ? 1 : 0) }`
+	if nodes, err = lang.Parse(lang.Tokenize(input)); err != nil {
+		t.Fatal(err)
+	}
+	complexity := []lang.FunctionComplexity{{Name: "half", Complexity: 1}, {Name: "f", Complexity: 4}}
+	if actual := lang.Stats(nodes).Complexity; !reflect.DeepEqual(actual, complexity) {
+		t.Errorf("expected complexity %+v, got %+v", complexity, actual)
+	}
+}
+
+func TestLintComplexity(t *testing.T) {
+	input := `function f() { for i := 0; i < 3; i++ { for j := 0; j < 2; j++ { printf(j) } } } function g() { for i := 0; i < 3; i++ { } }`
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diagnostics := lang.LintComplexity(nodes, 2)
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected one diagnostic, got %v", diagnostics)
+	}
+	expected := "function f has a cyclomatic complexity of 3, which exceeds 2"
+	if diagnostics[0].Error() != expected {
+		t.Errorf("expected diagnostic %q, got %q", expected, diagnostics[0])
+	}
+
+	if diagnostics := lang.LintComplexity(nodes, lang.DefaultComplexityThreshold); len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	parse := func(input string) []lang.Node {
+		nodes, err := lang.Parse(lang.Tokenize(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nodes
+	}
+
+	original := parse(`function add(a i32, b i32) i32 { return a + b } let x = add(1, 2) printf(x)`)
+	renamed := parse(`function sum(left i32, right i32) i32 { return left + right } let total = sum(3, 4) printf(total)`)
+	changed := parse(`function add(a i32, b i32) i32 { return a + b } let x = add(1, 2) let y: i64 = 5 printf(y)`)
+	different := parse(`struct P { x f64 } let p = P{x: 1.5} for i := 0; i < 3; i++ { printf(p.x) }`)
+
+	if similarity := lang.Similarity(original, renamed); similarity != 1 {
+		t.Errorf("expected renamed program to be identical, got %f", similarity)
+	}
+	changedSimilarity := lang.Similarity(original, changed)
+	if changedSimilarity <= 0.5 || changedSimilarity >= 1 {
+		t.Errorf("expected changed program to be similar, got %f", changedSimilarity)
+	}
+	if similarity := lang.Similarity(original, different); similarity >= changedSimilarity {
+		t.Errorf("expected different program to be less similar than %f, got %f", changedSimilarity, similarity)
+	}
+	if similarity := lang.Similarity(changed, original); similarity != changedSimilarity {
+		t.Errorf("expected symmetric similarity %f, got %f", changedSimilarity, similarity)
+	}
+}
+
+func TestSemanticDiff(t *testing.T) {
+	parse := func(input string) []lang.Node {
+		nodes, err := lang.Parse(lang.Tokenize(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nodes
+	}
+
+	before := parse(`extern function puts(s string) i32 struct P { x i32 } type Index = i32 function add(a i32, b i32) i32 { return a + b } function sub(a i32) i32 { return a } function id(a i32) i32 { return a } function hot() {} printf(add(1, 2))`)
+	reformatted := parse(`extern function puts(s string) i32
+struct P {
+	x i32
+}
+type Index = i32
+function add(a i32, b i32) i32 {
+	return a + b
+}
+function sub(a i32) i32 { return a }
+function id(a i32) i32 { return a }
+function hot() {}
+printf(add(1, 2))`)
+	after := parse(`extern function puts(s string, n i32) i32 struct P { x i32 y i32 } type Index = i64 function add(a i64, b i32) i64 { return a + b } function id(a i32) i32 { return 1 } @inline function hot() {} function neg(a i32) i32 { return a } printf(add(1, 3))`)
+
+	if changes := lang.SemanticDiff(before, reformatted); len(changes) != 0 {
+		t.Errorf("expected no changes for a reformatted program, got %v", changes)
+	}
+
+	var lines []string
+	for _, change := range lang.SemanticDiff(before, after) {
+		lines = append(lines, change.String())
+	}
+	expected := []string{
+		"~ extern function puts: signature changed from puts(s string) i32 to puts(s string, n i32) i32",
+		"~ struct P: fields changed",
+		"~ type Index: type changed from i32 to i64",
+		"~ function add: signature changed from add(a i32, b i32) i32 to add(a i64, b i32) i64",
+		"- function sub(a i32) i32",
+		"~ function id: body changed",
+		"~ function hot: attributes changed",
+		"+ function neg(a i32) i32",
+		"~ main: top-level statements changed",
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("expected changes %q, got %q", expected, lines)
+	}
+}
+
+func TestDiffAST(t *testing.T) {
+	parse := func(input string) []lang.Node {
+		nodes, err := lang.Parse(lang.Tokenize(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nodes
+	}
+
+	before := parse(`let x = 1 function f(a i32) i32 { return a } printf(x) printf(f(2)) printf(x)`)
+	after := parse(`let x = 2 function f(a i64) i32 { let b = a return b } printf(f(3)) let y = x printf(x)`)
+	if changes := lang.DiffAST(before, parse("let x = 1\nfunction f(a i32) i32 {\n\treturn a\n}\nprintf(x) printf(f(2)) printf(x)")); len(changes) != 0 {
+		t.Errorf("expected no changes for a reformatted program, got %v", changes)
+	}
+
+	changes := lang.DiffAST(before, after)
+	var lines []string
+	for _, change := range changes {
+		lines = append(lines, change.String())
+	}
+	expected := []string{
+		"~ IntLiteralNode [0].Value: Value changed from 1 to 2",
+		"~ Parameter [1].Parameters[0]: Type changed from i32 to i64",
+		"~ ReturnNode [1].Body[0]: replaced by LetNode",
+		"+ ReturnNode [1].Body[1]",
+		"~ IdentifierNode [2].Args[0]: replaced by CallerNode",
+		"~ CallerNode [3]: replaced by LetNode",
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("expected changes %q, got %q", expected, lines)
+	}
+	if len(changes) > 0 && (changes[0].Before != before[0].(*lang.LetNode).Value || changes[0].After != after[0].(*lang.LetNode).Value) {
+		t.Errorf("expected the change to hold the literals before and after, got %v and %v", changes[0].Before, changes[0].After)
+	}
+
+	lines = nil
+	for _, change := range lang.DiffAST(before, before[:2]) {
+		lines = append(lines, change.String())
+	}
+	expected = []string{"- CallerNode [2]", "- CallerNode [3]", "- CallerNode [4]"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("expected changes %q, got %q", expected, lines)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	tokens := lang.Tokenize("let s = \"é\"\n  printf(add(1, 2) >= 3)")
+	expectedPositions := []string{"1:1-1:4", "1:5-1:6", "1:7-1:8", "1:9-1:12", "2:3-2:9", "2:9-2:10", "2:10-2:13"}
+	for i, expected := range expectedPositions {
+		if got := tokens[i].Pos.String() + "-" + tokens[i].End.String(); got != expected {
+			t.Errorf("expected token %d at %s, got %s", i, expected, got)
+		}
+	}
+
+	nodes, err := lang.Parse(tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	callerNode := nodes[1].(*lang.CallerNode)
+	comparisonNode := callerNode.Args[0].(*lang.ComparisonNode)
+	spans := map[string]lang.Node{
+		"1:1-1:12":  nodes[0],
+		"2:3-2:25":  callerNode,
+		"2:10-2:24": comparisonNode,
+		"2:10-2:19": comparisonNode.LeftValue,
+	}
+	for expected, node := range spans {
+		if got := node.Pos().String() + "-" + node.End().String(); got != expected {
+			t.Errorf("expected %T at %s, got %s", node, expected, got)
+		}
+	}
+
+	if pos := (&lang.LetNode{}).Pos(); pos.IsValid() {
+		t.Errorf("expected an unknown position for a node which wasn't parsed, got %s", pos)
+	}
+}
+
+func TestCallerArgs(t *testing.T) {
+	nodes, err := lang.Parse(lang.Tokenize("f(a + 1, g(2), x << 1, 3, b)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	callerNode := nodes[0].(*lang.CallerNode)
+	var kinds []string
+	for _, arg := range callerNode.Args {
+		kinds = append(kinds, fmt.Sprintf("%T", arg))
+	}
+	expected := []string{"*lang.AddOperationNode", "*lang.CallerNode", "*lang.ShiftOperationNode", "*lang.IntLiteralNode", "*lang.IdentifierNode"}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("expected arguments %q, got %q", expected, kinds)
+	}
+}
+
+func TestParseRecovery(t *testing.T) {
+	input := `let a = ) function f() i32 { let b = ] printf(b) let c = } let d = 2 bogus printf(d) let e = 3`
+	_, err := lang.Parse(lang.Tokenize(input))
+
+	var syntaxErrors lang.SyntaxErrors
+	if !errors.As(err, &syntaxErrors) {
+		t.Fatalf("expected syntax errors, got %v", err)
+	}
+	var ids []lang.MessageID
+	for _, syntaxError := range syntaxErrors {
+		var messageError *lang.MessageError
+		if !errors.As(syntaxError, &messageError) {
+			t.Fatalf("expected a message error, got %T", syntaxError)
+		}
+		ids = append(ids, messageError.ID)
+	}
+	expected := []lang.MessageID{lang.MessageExpectedValue, lang.MessageExpectedValue, lang.MessageExpectedValue, lang.MessageUnexpectedIdentifier}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected errors %q, got %q", expected, ids)
+	}
+	if lines := strings.Split(err.Error(), "\n"); len(lines) != len(expected) {
+		t.Errorf("expected a line per error, got %q", err.Error())
+	}
+
+	if _, err := lang.Parse(lang.Tokenize(`let a = 1 printf(a)`)); err != nil {
+		t.Errorf("expected no errors, got %v", err)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected lang.ParseError
+	}{
+		{`let = 5`, lang.ParseError{Pos: lang.Pos{Line: 1, Column: 5}, Expected: "identifier", Got: "'='", Context: "let statement"}},
+		{"let a = 1\nprintf(a", lang.ParseError{Pos: lang.Pos{Line: 2, Column: 9}, Expected: "')'", Got: "end of input", Context: "call"}},
+		{`printf(1 as )`, lang.ParseError{Pos: lang.Pos{Line: 1, Column: 13}, Expected: "type", Got: "')'", Context: "cast"}},
+		{`struct P { x i32 } let p = P{x "a"}`, lang.ParseError{Pos: lang.Pos{Line: 1, Column: 32}, Expected: "':'", Got: `string literal "a"`, Context: "struct literal"}},
+	}
+
+	for _, test := range tests {
+		_, err := lang.Parse(lang.Tokenize(test.input))
+		var parseError *lang.ParseError
+		if !errors.As(err, &parseError) {
+			t.Fatalf("expected a parse error for %q, got %v", test.input, err)
+		}
+		got := *parseError
+		got.Err = nil
+		if got != test.expected {
+			t.Errorf("expected parse error %+v for %q, got %+v", test.expected, test.input, got)
+		}
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	path := t.TempDir() + "/main.gusty"
+	if err := os.WriteFile(path, []byte("let a = 1\nprintf(a\nlet b = )"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := lang.ParseFile(path)
+	if err == nil {
+		t.Fatal("expected syntax errors")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	expected := []string{path + ":3:1: expected value at position 7", path + ":3:9: expected value at position 10"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("expected errors %q, got %q", expected, lines)
+	}
+	var parseError *lang.ParseError
+	if !errors.As(err, &parseError) || parseError.Filename != path {
+		t.Errorf("expected a parse error in %s, got %v", path, err)
+	}
+
+	if err := os.WriteFile(path, []byte("printf(1)"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if nodes, err := lang.ParseFile(path); err != nil || len(nodes) != 1 {
+		t.Errorf("expected one node, got %d and %v", len(nodes), err)
+	}
+	if _, err := lang.ParseFile(path + ".missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a missing file, got %v", err)
+	}
+}
+
+func TestReparse(t *testing.T) {
+	source := "let a = 1\nfunction f(x i32) i32 {\n  return x + a\n}\nprintf(f(2))\nlet b = 3"
+	nodes, err := lang.Parse(lang.Tokenize(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edits := []lang.TextEdit{
+		{Span: lang.Span{From: lang.Pos{Line: 3, Column: 14}, To: lang.Pos{Line: 3, Column: 15}}, Text: "a + 10"},
+		{Span: lang.Span{From: lang.Pos{Line: 1, Column: 10}, To: lang.Pos{Line: 1, Column: 10}}, Text: "\n\nprintf(a)"},
+		{Span: lang.Span{From: lang.Pos{Line: 7, Column: 1}, To: lang.Pos{Line: 7, Column: 13}}, Text: ""},
+	}
+	for _, edit := range edits {
+		last := nodes[len(nodes)-1]
+		source, nodes, err = lang.Reparse(source, nodes, edit)
+		if err != nil {
+			t.Fatalf("expected no errors reparsing %q, got %v", source, err)
+		}
+		expected, err := lang.Parse(lang.Tokenize(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lang.Dump(nodes) != lang.Dump(expected) {
+			t.Errorf("expected the nodes of %q\n%s\ngot\n%s", source, lang.Dump(expected), lang.Dump(nodes))
+		}
+		if nodes[len(nodes)-1] != last {
+			t.Errorf("expected the last statement of %q to be reused", source)
+		}
+	}
+	if source != "let a = 1\n\nprintf(a)\nfunction f(x i32) i32 {\n  return x + a + 10\n}\n\nlet b = 3" {
+		t.Errorf("unexpected edited source code %q", source)
+	}
+
+	// A syntax error is reported like parsing the whole source code reports it
+	_, _, err = lang.Reparse(source, nodes, lang.TextEdit{Span: lang.Span{From: lang.Pos{Line: 3, Column: 9}, To: lang.Pos{Line: 3, Column: 10}}})
+	_, expected := lang.Parse(lang.Tokenize("let a = 1\n\nprintf(a\nfunction f(x i32) i32 {\n  return x + a + 10\n}\n\nlet b = 3"))
+	if err == nil || err.Error() != expected.Error() {
+		t.Errorf("expected the error %v, got %v", expected, err)
+	}
+
+	var messageError *lang.MessageError
+	_, _, err = lang.Reparse(source, nodes, lang.TextEdit{Span: lang.Span{From: lang.Pos{Line: 1, Column: 20}, To: lang.Pos{Line: 1, Column: 21}}})
+	if !errors.As(err, &messageError) || messageError.ID != lang.MessageInvalidEdit {
+		t.Errorf("expected an invalid edit, got %v", err)
+	}
+}
+
+func TestReparseContinuedStatement(t *testing.T) {
+	tests := []struct {
+		source string
+		edit   lang.TextEdit
+	}{
+		{"let a = 1 let b = 2 printf(a + b)", lang.TextEdit{Span: lang.Span{From: lang.Pos{Line: 1, Column: 21}, To: lang.Pos{Line: 1, Column: 21}}, Text: "+ 2"}},
+		{"struct P { x i32 }\nlet p = P{x: 1}\np.x = 2\nprintf(p.x)\n", lang.TextEdit{Span: lang.Span{From: lang.Pos{Line: 3, Column: 1}, To: lang.Pos{Line: 3, Column: 2}}}},
+	}
+	for _, test := range tests {
+		nodes, err := lang.Parse(lang.Tokenize(test.source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		source, reparsed, err := lang.Reparse(test.source, nodes, test.edit)
+		expected, expectedErr := lang.Parse(lang.Tokenize(source))
+		if (err == nil) != (expectedErr == nil) || err != nil && err.Error() != expectedErr.Error() {
+			t.Errorf("expected the error %v reparsing %q, got %v", expectedErr, source, err)
+		} else if err == nil && lang.Dump(reparsed) != lang.Dump(expected) {
+			t.Errorf("expected the nodes of %q\n%s\ngot\n%s", source, lang.Dump(expected), lang.Dump(reparsed))
+		}
+	}
+}
+
+func TestReparseRandom(t *testing.T) {
+	sources := []string{
+		"let a = 1 let b = 2 printf(a + b)",
+		"struct P { x i32 }\nlet p = P{x: 1}\np.x = 2\nprintf(p.x)\n",
+		"let a = 1\nfunction f(x i32) i32 {\n  return x + a\n}\nprintf(f(2))\nlet b = 3",
+		"let xs = [1, 2, 3]\nfor i := 0; i < 2; i++ {\n  xs[i] = i\n}\nprintf(xs[1] as i64)",
+	}
+	texts := []string{"", "+ 2", "p", "2", "\n", " ", "let c = 3 ", "printf(a)", "(", ")", "{", "}", "x", "as i64", "= 1", "function g() {}"}
+	random := rand.New(rand.NewSource(1))
+	for _, original := range sources {
+		source := original
+		nodes, err := lang.Parse(lang.Tokenize(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 200; i++ {
+			from := random.Intn(len(source) + 1)
+			to := from + random.Intn(len(source)-from+1)
+			if random.Intn(2) == 0 {
+				to = from
+			}
+			edit := lang.TextEdit{Span: lang.Span{From: sourcePos(source, from), To: sourcePos(source, to)}, Text: texts[random.Intn(len(texts))]}
+			edited, reparsed, err := lang.Reparse(source, nodes, edit)
+			if err != nil && errors.Is(err, lang.ErrInvalidEdit) {
+				t.Fatalf("expected a valid edit %v of %q, got %v", edit, source, err)
+			}
+			expected, expectedErr := lang.Parse(lang.Tokenize(edited))
+			if (err == nil) != (expectedErr == nil) || err != nil && err.Error() != expectedErr.Error() {
+				t.Fatalf("expected the error %v reparsing %q, got %v", expectedErr, edited, err)
+			}
+			if err == nil && lang.Dump(reparsed) != lang.Dump(expected) {
+				t.Fatalf("expected the nodes of %q\n%s\ngot\n%s", edited, lang.Dump(expected), lang.Dump(reparsed))
+			}
+
+			// Edits continue from the edited source code while it parses, otherwise from the original one
+			source, nodes = edited, reparsed
+			if err != nil {
+				source = original
+				nodes, _ = lang.Parse(lang.Tokenize(source))
+			}
+		}
+	}
+}
+
+// sourcePos returns the position of the byte offset in the source code, which only holds ASCII.
+func sourcePos(source string, offset int) lang.Pos {
+	line := strings.Count(source[:offset], "\n") + 1
+	return lang.Pos{Line: line, Column: offset - strings.LastIndex(source[:offset], "\n")}
+}
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"n + 1 < limit", "n + 1 < limit"},
+		{"f(x, 2) as i64", "i64(f(x, 2))"},
+		{"done ? 0 : n + 1", "done ? 0 : n + 1"},
+		{"[1, 2, 3][i]", "[1, 2, 3][i]"},
+	}
+	for _, test := range tests {
+		expr, err := lang.ParseExpr(test.input)
+		if err != nil {
+			t.Fatalf("expected no errors parsing %q, got %v", test.input, err)
+		}
+		if got := lang.Format([]lang.Node{expr}); got != test.expected+"\n" {
+			t.Errorf("expected the expression %q for %q, got %q", test.expected, test.input, got)
+		}
+	}
+
+	errorTests := []struct {
+		parser   lang.Parser
+		input    string
+		expected lang.MessageID
+	}{
+		{lang.Parser{}, "a + 1 printf(a)", lang.MessageUnexpectedTokenAfterExpression},
+		{lang.Parser{}, "a +", lang.MessageUnexpectedEndOfInput},
+		{lang.Parser{}, "", lang.MessageUnexpectedEndOfInput},
+		{lang.Parser{Disabled: lang.FeatureSpawn}, "spawn f()", lang.MessageFeatureDisabled},
+	}
+	for _, test := range errorTests {
+		_, err := test.parser.ParseExpr(test.input)
+		var messageError *lang.MessageError
+		if !errors.As(err, &messageError) || messageError.ID != test.expected {
+			t.Errorf("expected the error %s for %q, got %v", test.expected, test.input, err)
+		}
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	nodes := []lang.Node{
+		lang.NewFunc("add", []*lang.Parameter{lang.NewParam("a", lang.Integer32Type), lang.NewParam("b", lang.Integer32Type)}, lang.Integer32Type,
+			lang.NewReturn(lang.NewAdd(lang.Ident("a"), lang.Ident("b")))),
+		lang.NewLet("x", lang.NewCall("add", lang.Int(2), lang.Int(3))),
+		lang.NewTypedLet("y", lang.Integer64Type, lang.NewCast(lang.Integer64Type, lang.Ident("x"))),
+		lang.NewAssign("x", lang.NewAdd(lang.Ident("x"), lang.Int(1))),
+		lang.NewCall("printf", lang.Ident("x")),
+		lang.NewCall("printf", lang.Ident("y")),
+	}
+	llvmIR, err := lang.GenerateLLVMIR(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := `function add(a i32, b i32) i32 { return a + b } let x = add(2, 3) let y: i64 = x as i64 x = x + 1 printf(x) printf(y)`
+	parsed, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := lang.GenerateLLVMIR(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if llvmIR != expected {
+		t.Errorf("expected the built program to generate the IR of %q, got\n%s", input, llvmIR)
+	}
+
+	invalid := map[string]func(){
+		"empty identifier": func() { lang.Ident("") },
+		"keyword":          func() { lang.NewLet("let", lang.Int(1)) },
+		"reserved word":    func() { lang.NewParam("if", lang.Integer32Type) },
+		"number":           func() { lang.NewAssign("1", lang.Int(1)) },
+		"two words":        func() { lang.NewFunc("a b", nil, lang.VoidType) },
+		"nil value":        func() { lang.NewLet("x", nil) },
+		"void type":        func() { lang.NewParam("x", lang.VoidType) },
+		"duplicate param": func() {
+			lang.NewFunc("f", []*lang.Parameter{lang.NewParam("a", lang.BoolType), lang.NewParam("a", lang.BoolType)}, lang.VoidType)
+		},
+		"invalid operator":   func() { lang.NewCompare(lang.Int(1), lang.ComparisonOperator(-1), lang.Int(2)) },
+		"invalid call name":  func() { lang.NewCall("math.") },
+		"nil call argument":  func() { lang.NewCall("printf", nil) },
+		"nil body statement": func() { lang.NewFunc("f", nil, lang.VoidType, nil) },
+	}
+	for name, build := range invalid {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected the constructor to panic for the %s", name)
+				}
+			}()
+			build()
+		}()
+	}
+}
+
+func TestValidate(t *testing.T) {
+	input := `struct Point { x i32 y i32 } function add(a i32, b i32) i32 { return a + b } let p = Point{x: 1, y: 2} let x: i64 = add(p.x, 2) as i64 for i := 0; i < 3; i++ { printf(x + 1) }`
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lang.Validate(nodes); err != nil {
+		t.Errorf("expected the parsed program to be valid, got %v", err)
+	}
+
+	decoded, err := lang.UnmarshalNodes([]byte(`[{"node":"LetNode","Identifier":"x"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		nodes    []lang.Node
+		expected string
+	}{
+		{decoded, "LetNode has no Value"},
+		{[]lang.Node{nil}, "program has no statement[0]"},
+		{[]lang.Node{&lang.FunctionNode{Name: "f", ReturnType: lang.VoidType, Body: []lang.Node{(*lang.CallerNode)(nil)}}}, "FunctionNode has no Body[0]"},
+		{[]lang.Node{&lang.CallerNode{Span: lang.Span{From: lang.Pos{Line: 1, Column: 5}}, FunctionName: "printf", Args: []lang.Expr{nil}}}, "CallerNode at 1:5 has no Args[0]"},
+		{[]lang.Node{&lang.AssignmentNode{Value: lang.Int(1)}}, "AssignmentNode has an empty Identifier"},
+		{[]lang.Node{lang.NewLet("x", &lang.ComparisonNode{LeftValue: lang.Int(1), RightValue: lang.Int(2), Operator: 42})}, "ComparisonNode has the unknown operator 42"},
+		{[]lang.Node{&lang.LetNode{Identifier: "x", Type: lang.VoidType, Value: lang.Int(1)}}, "LetNode has the invalid data type void"},
+		{[]lang.Node{&lang.CastNode{Type: 1 << 20, Value: lang.Int(1)}}, "CastNode has the invalid data type unknown(1048576)"},
+		{[]lang.Node{&lang.IndexAssignmentNode{Value: lang.Int(1)}}, "IndexAssignmentNode has no IndexNode"},
+		{[]lang.Node{&lang.SpawnNode{Call: &lang.CallerNode{}}}, "CallerNode has an empty FunctionName"},
+		{[]lang.Node{&lang.ForNode{}}, "ShortVariableAssigmentNode has an empty Identifier"},
+		{[]lang.Node{&lang.ForNode{Init: lang.ShortVariableAssigmentNode{Identifier: "i", Value: lang.Int(0)}, Condition: lang.ConditionNode{LeftValue: "i", Operator: lang.OperatorGreater, RightValue: lang.Int(3)}, Post: lang.PostNode{Identifier: "i", Increment: true}}}, "ConditionNode has the unknown operator 4"},
+	}
+	for _, test := range tests {
+		err := lang.Validate(test.nodes)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("expected the error %q, got %v", test.expected, err)
+		}
+		if _, err := lang.GenerateLLVMIR(test.nodes); err == nil || err.Error() != test.expected {
+			t.Errorf("expected GenerateLLVMIR to return the error %q, got %v", test.expected, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	input := `struct Point { x i32 y i32 } function add(a i32, b i32) i32 { return a + b } let p = Point{x: 1, y: 2} let x: i64 = add(p.x, 2) as i64 let t = x > 1 ? x : 0 printf(t)`
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diagnostics, err := lang.Check(nodes); err != nil || len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %v %v", diagnostics, err)
+	}
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`let x: i32 = true`, []string{"1:14: invalid value for let node x: cannot use true as i32 value"}},
+		{`function f(a i32) i32 { return a } printf(f(true))`, []string{"1:45: invalid parameter 1 of caller f: cannot use true as i32 value"}},
+		{`function f() i32 { return true }`, []string{"1:27: invalid return value in function f: cannot use true as i32 value"}},
+		{`let x = 1 + true let y = x`, []string{"1:13: cannot use true as i32 value"}},
+		{`let b = 1 let x = b ? 1 : 2`, []string{"1:19: condition must be a bool value, got i32"}},
+		{`function add(a i32, b i32) i32 { return a + b } printf(add(1)) exit("1") let s = append([1]) printf(sqrt(1.0, 2.0))`, []string{"1:56: expected 2 parameters for caller add, got 1", "1:69: invalid parameter 1 of caller exit: cannot use string value as i32 value", "1:82: expected a slice and at least one value for append, got 1 parameters", "1:101: expected exactly one parameter for sqrt, got 2"}},
+		{`function pick<T>(a T, b T) T { return a } function exit(a i32, b i32) { } exit(1, 2) printf(pick(1)) lock() let x = format(1)`, []string{"1:93: expected 2 parameters for caller pick, got 1", "1:102: expected exactly one parameter for lock, got 0", "1:124: invalid parameter 1 of caller format: cannot use 1 as string value"}},
+		{`struct Point { x i32 } let count = 1 printf(cuont) function total(a i32) i32 { return a } printf(totl(count)) let p = Piont{x: 1}`, []string{"1:45: variable not found in scope: cuont, did you mean count?", "1:98: caller not found in scope: totl, did you mean total?", "1:119: unknown struct Piont, did you mean Point?"}},
+		{`function f(value i32) i32 { let later = 1 return valeu + b } for i := 0; i < 3; i++ { j = i } let x = sqr(2.0)`, []string{"1:50: variable not found in scope: valeu, did you mean value?", "1:58: variable not found in scope: b", "1:87: variable not found in scope: j", "1:103: caller not found in scope: sqr, did you mean sqrt?"}},
+		{`printf(len(5)) printf(abs(true)) printf(sqrt("x")) free(3) let c = cap("ab")`, []string{"1:12: invalid i32 value for len", "1:27: abs expects integer or floating point values, got bool", "1:46: sqrt expects floating point values, got string", "1:57: cannot free i32 value", "1:72: invalid string value for cap"}},
+		{`let n: i32 = format("%d", 1) let y: bool = read_int() let a = 1 let f = 1.5 let x = max(a, f) let s = sqrt(a)`, []string{"1:14: invalid value for let node n: cannot use string value as i32 value", "1:44: invalid value for let node y: cannot use i64 value as bool value", "1:92: invalid parameter 2 of caller max: cannot use f64 value as i32 value", "1:108: sqrt expects floating point values, got i32"}},
+		{`let xs = [1, 2] let n: i64 = len(xs) + cap(xs) let r: f64 = sqrt(2.0) + pow(2.0, 3) let m: i8 = max(1, 2) let p = new(i32) free(p) let l: string = read_line() let k: i32 = abs(m as i32)`, nil},
+		{`struct Point { x i32 y i32 } let p = Point{x: 1, y: 2} printf(p.z) let q: i32 = 1.5`, []string{"1:63: unknown field z in struct Point", "1:81: invalid value for let node q: cannot use 1.5 as i32 value"}},
+		{`function f() i32 { for i := 0; i < 3; i++ { return i } } function g() i32 { exit(1) } @noreturn function h() i32 { printf(1) } function pick<T>(a T) T { printf(1) } function v() { }`, []string{"1:1: missing return at end of function f", "1:128: missing return at end of function pick"}},
+	}
+	for _, test := range tests {
+		nodes, err := lang.Parse(lang.Tokenize(test.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		diagnostics, err := lang.Check(nodes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var messages []string
+		for _, diagnostic := range diagnostics {
+			messages = append(messages, diagnostic.Error())
+		}
+		if !reflect.DeepEqual(messages, test.expected) {
+			t.Errorf("expected the diagnostics %q for %q, got %q", test.expected, test.input, messages)
+		}
+	}
+
+	if _, err := lang.Check([]lang.Node{&lang.LetNode{Identifier: "x"}}); err == nil || err.Error() != "LetNode has no Value" {
+		t.Errorf("expected the invalid node to be rejected, got %v", err)
+	}
+
+	var phases []lang.Phase
+	compiler := lang.Compiler{Options: lang.Options{TypeCheck: true}}
+	compiler.Hooks.OnPhaseStart = func(phase lang.Phase) {
+		phases = append(phases, phase)
+	}
+	_, err = compiler.Compile(`let x: i32 = true`)
+	var diagnostic lang.Diagnostic
+	if !errors.As(err, &diagnostic) || diagnostic.From.Line != 1 || diagnostic.From.Column != 14 {
+		t.Errorf("expected the diagnostic at 1:14, got %v", err)
+	}
+	if len(phases) == 0 || phases[len(phases)-1] != lang.PhaseCheck {
+		t.Errorf("expected the compiler to stop in the check phase, got %v", phases)
+	}
+
+	compiler = lang.Compiler{Options: lang.Options{TypeCheck: true, Locale: lang.LocaleGerman}}
+	expected := "1:22: Variable nicht im Gültigkeitsbereich gefunden: cuont, meinten Sie count?"
+	if _, err := compiler.Compile(`let count = 1 printf(cuont)`); err == nil || err.Error() != expected {
+		t.Errorf("expected the error %q, got %v", expected, err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	input := `embed data "data.txt" let x = 1 function f(x i32) i32 { let y = x for i := 0; i < 3; i++ { let x = i printf(x) } return g(y) } function g(a i32) i32 { return a } let z = x + f(2) printf(z) x = missing`
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolution, err := lang.Resolve(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := nodes[2].(*lang.FunctionNode)
+	forNode := f.Body[1].(*lang.ForNode)
+	bindings := []struct {
+		use         lang.Node
+		declaration lang.Node
+	}{
+		{f.Body[0].(*lang.LetNode).Value, f.Parameters[0]},
+		{&forNode.Condition, &forNode.Init},
+		{forNode.Body[0].(*lang.LetNode).Value, &forNode.Init},
+		{forNode.Body[1].(*lang.CallerNode).Args[0], forNode.Body[0]},
+		{f.Body[2].(*lang.ReturnNode).Value, nodes[3]},
+		{f.Body[2].(*lang.ReturnNode).Value.(*lang.CallerNode).Args[0], f.Body[0]},
+		{nodes[4].(*lang.LetNode).Value.(*lang.AddOperationNode).LeftValue, nodes[1]},
+		{nodes[6], nodes[1]},
+	}
+	for _, binding := range bindings {
+		symbol := resolution.Bindings[binding.use]
+		if symbol == nil || symbol.Node != binding.declaration {
+			t.Errorf("expected %v to bind to %v, got %v", binding.use, binding.declaration, symbol)
+		}
+	}
+	if symbol := resolution.Declarations[nodes[1]]; len(symbol.Uses) != 2 || symbol.Kind != lang.SymbolVariable || symbol.Scope.Kind != lang.ScopeFunction || symbol.Scope.Node != nil {
+		t.Errorf("expected x to be a variable of the top-level statements used twice, got %+v", symbol)
+	}
+	if symbol, _ := resolution.Global.Values.Get("data"); symbol == nil || symbol.Kind != lang.SymbolEmbed {
+		t.Errorf("expected the embedded file to be declared in the global scope, got %+v", symbol)
+	}
+	unresolved := resolution.Unresolved
+	if len(unresolved) != 1 || unresolved[0] != nodes[6].(*lang.AssignmentNode).Value {
+		t.Errorf("expected the use of missing to be unresolved, got %v", unresolved)
+	}
+
+	var kinds []string
+	var walk func(scope *lang.SymbolScope, depth int)
+	walk = func(scope *lang.SymbolScope, depth int) {
+		kinds = append(kinds, strings.Repeat(" ", depth)+scope.Kind.String()+" "+strings.Join(scope.Values.Names(), ","))
+		for _, child := range scope.Children {
+			walk(child, depth+1)
+		}
+	}
+	walk(resolution.Global, 0)
+	expected := []string{"global data", " function x,z", " function x,y", "  loop i", "   block x", " function a"}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("expected the scopes %q, got %q", expected, kinds)
+	}
+
+	// Functions don't see the variables of the top-level statements
+	nodes, err = lang.Parse(lang.Tokenize(`let x = 1 function f() i32 { return x } printf(f())`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolution, err := lang.Resolve(nodes); err != nil || len(resolution.Unresolved) != 1 {
+		t.Errorf("expected x to be unresolved in f, got %v %v", resolution, err)
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	inputs := map[string]string{
+		`function f() i32 { return 1 } function f() i32 { return 2 } printf(f())`:     "1:31: duplicate declaration of function f, first declared at 1:1",
+		`let x = 1 let x = 2 printf(x)`:                                               "1:11: duplicate declaration of variable x, first declared at 1:1",
+		`function f(a i32, a i32) i32 { return a } printf(f(1, 2))`:                   "1:19: duplicate parameter a of function f, first declared at 1:12",
+		`function f() { for i := 0; i < 3; i++ { let y = i let y = 2 } } f()`:         "1:51: duplicate declaration of variable y, first declared at 1:41",
+		`struct P { x i32 } struct P { y i32 }`:                                       "1:20: duplicate declaration of struct P, first declared at 1:1",
+		`extern function puts(s string) i32 function puts(s string) i32 { return 0 }`: "1:36: duplicate declaration of function puts, first declared at 1:1",
+	}
+	for input, expected := range inputs {
+		nodes, err := lang.Parse(lang.Tokenize(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := lang.GenerateLLVMIR(nodes); err == nil || err.Error() != expected {
+			t.Errorf("expected the error %q for %q, got %v", expected, input, err)
+		}
+	}
+
+	// A variable of a loop body or a function doesn't clash with the variables outside of it
+	input := `let x = 1 function f(x i32) i32 { let y = x return y } for i := 0; i < 3; i++ { let x = i printf(x) } let y = f(x) printf(y)`
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diagnostics, err := lang.Check(nodes); err != nil || len(diagnostics) != 0 {
+		t.Errorf("expected no duplicates, got %v %v", diagnostics, err)
+	}
+}
+
+func TestUnused(t *testing.T) {
+	input := `function f(a i32, b i32, _c i32) i32 { let d = 1 let e = 2 e = 3 for i := 0; i < 3; i++ { let g = i } return a } let x = 1 let y = f(x, 2, 3)`
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	diagnostics, err := lang.Unused(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity != lang.SeverityWarning {
+			t.Errorf("expected a warning, got %v", diagnostic.Severity)
+		}
+		messages = append(messages, diagnostic.Error())
+	}
+	expected := []string{"1:19: parameter b of function f is never read", "1:40: variable d is never read", "1:50: variable e is never read", "1:91: variable g is never read", "1:124: variable y is never read"}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("expected the warnings %q, got %q", expected, messages)
+	}
+
+	var warnings []string
+	compiler := lang.Compiler{Options: lang.Options{WarnUnused: true}}
+	compiler.Hooks.OnDiagnostic = func(phase lang.Phase, err error) {
+		if phase == lang.PhaseUnused {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	if _, err := compiler.Compile(input); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("expected the compiler to report the warnings %q, got %q", expected, warnings)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	input := `function f() i32 { for i := 0; i < 3; i++ { exit(1) printf(i) return i } return 1 printf(2) printf(3) } printf(f()) return 0 printf(4) function g() i32 { return 2 }`
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for _, diagnostic := range lang.Unreachable(nodes) {
+		if diagnostic.Severity != lang.SeverityWarning {
+			t.Errorf("expected a warning, got %v", diagnostic.Severity)
+		}
+		messages = append(messages, diagnostic.Error())
+	}
+	expected := []string{"1:53: unreachable code after the call of exit at 1:45", "1:83: unreachable code after the return at 1:74", "1:126: unreachable code after the return at 1:117"}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("expected the warnings %q, got %q", expected, messages)
+	}
+
+	var warnings []string
+	compiler := lang.Compiler{Options: lang.Options{WarnUnreachable: true, Locale: lang.LocaleGerman}}
+	compiler.Hooks.OnDiagnostic = func(phase lang.Phase, err error) {
+		if phase == lang.PhaseUnreachable {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	if _, err := compiler.Compile(`function exit(n i32) { printf(n) } exit(1) printf(2) return 0 printf(3)`); err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"1:63: unerreichbarer Code nach dem return bei 1:54"}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("expected the compiler to report the warnings %q, got %q", expected, warnings)
+	}
+}
+
+func TestShadowed(t *testing.T) {
+	input := `threadlocal let limit = 10 function f(limit i32, n i32) i32 { let x = n for i := 0; i < 3; i++ { let x = i for n := 0; n < 2; n++ { printf(n) } } for i := 0; i < 2; i++ { let y = i } let y = 1 return x } let i = 0 for i := 0; i < 3; i++ { printf(i) } printf(f(1, 2))`
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	diagnostics, err := lang.Shadowed(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity != lang.SeverityWarning {
+			t.Errorf("expected a warning, got %v", diagnostic.Severity)
+		}
+		messages = append(messages, diagnostic.Error())
+	}
+	expected := []string{"1:39: parameter limit of function f shadows the global declared at 1:1", "1:98: variable x shadows the declaration at 1:63", "1:112: variable n shadows the declaration at 1:50", "1:219: variable i shadows the declaration at 1:205"}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("expected the warnings %q, got %q", expected, messages)
+	}
+
+	// The variables of a loop shadow the variables of the function until the loop ends
+	compiler := lang.Compiler{Options: lang.Options{WarnShadowing: true}}
+	var warnings []string
+	compiler.Hooks.OnDiagnostic = func(phase lang.Phase, err error) {
+		if phase == lang.PhaseShadowing {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	actualLlvmIR, err := compiler.Compile(`function f() i32 { let x = 1 for i := 0; i < 3; i++ { let x = 2 printf(x) } return x } printf(f())`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, []byte(actualLlvmIR), "shadowing")
+	expected = []string{"1:55: variable x shadows the declaration at 1:20"}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("expected the compiler to report the warnings %q, got %q", expected, warnings)
+	}
+}
+
+func TestRenderDiagnostic(t *testing.T) {
+	input := "function f() i32 {\n\tlet x: i32 = true\n\treturn x\n}\nfunction g() i32 {\n\tprintf(1)\n}"
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	diagnostics, err := lang.Check(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rendered []string
+	for _, diagnostic := range diagnostics {
+		rendered = append(rendered, lang.RenderDiagnostic("main.gusty", input, diagnostic))
+	}
+	expected := []string{
+		"2 | \tlet x: i32 = true\n  | \t             ^~~~\nmain.gusty:2:15: error: invalid value for let node x: cannot use true as i32 value\n",
+		"5 | function g() i32 {\n  | ^~~~~~~~~~~~~~~~~~\nmain.gusty:5:1: error: missing return at end of function g\n",
+	}
+	if !reflect.DeepEqual(rendered, expected) {
+		t.Errorf("expected the rendered diagnostics %q, got %q", expected, rendered)
+	}
+
+	for _, test := range []struct {
+		diagnostic lang.Diagnostic
+		expected   string
+	}{
+		{lang.Diagnostic{Span: lang.Span{From: lang.Pos{Line: 1, Column: 5}, To: lang.Pos{Line: 1, Column: 5}}, Err: errors.New("expected value")}, "1 | let = 1\n  |     ^\n1:5: error: expected value\n"},
+		{lang.Diagnostic{Severity: lang.SeverityWarning, Err: errors.New("program has 8 instructions")}, "warning: program has 8 instructions\n"},
+		{lang.Diagnostic{Span: lang.Span{From: lang.Pos{Line: 3, Column: 1}}, Err: errors.New("unexpected end of input")}, "3:1: error: unexpected end of input\n"},
+	} {
+		if actual := lang.RenderDiagnostic("", "let = 1", test.diagnostic); actual != test.expected {
+			t.Errorf("expected the rendered diagnostic %q, got %q", test.expected, actual)
+		}
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	tests := []struct {
+		input   string
+		options lang.Options
+		kinds   []error
+	}{
+		{`let x = (1`, lang.Options{}, []error{lang.ErrSyntax, lang.ErrUnexpectedToken}},
+		{`let if = 1`, lang.Options{Locale: lang.LocaleGerman}, []error{lang.ErrSyntax}},
+		{`printf(f())`, lang.Options{}, []error{lang.ErrUnknownFunction}},
+		{`printf(x)`, lang.Options{TypeCheck: true}, []error{lang.ErrUnknownVariable}},
+		{`let x: i32 = true`, lang.Options{}, []error{lang.ErrTypeMismatch, lang.ErrInvalidValueType}},
+		{`let x: i32 = true`, lang.Options{TypeCheck: true, Locale: lang.LocaleGerman}, []error{lang.ErrTypeMismatch, lang.ErrInvalidValueType}},
+		{`let p = Point{x: 1}`, lang.Options{}, []error{lang.ErrUnknownType, lang.ErrInvalidValueType}},
+		{`function f(a i32) i32 { return a } printf(f())`, lang.Options{}, []error{lang.ErrArgumentCount}},
+		{`function f() i32 { return 1 } function f() i32 { return 2 }`, lang.Options{}, []error{lang.ErrDuplicateDeclaration}},
+		{`function f() i32 { printf(1) }`, lang.Options{}, []error{lang.ErrMissingReturn}},
+		{`function f() { struct P { x i32 } }`, lang.Options{}, []error{lang.ErrUnsupported}},
+		{`import "missing.gusty"`, lang.Options{}, []error{lang.ErrFile}},
+		{`printf(1)`, lang.Options{Budget: lang.Budget{Instructions: 1}}, []error{lang.ErrBudgetExceeded}},
+	}
+	kinds := []error{lang.ErrSyntax, lang.ErrUnexpectedToken, lang.ErrUnknownFunction, lang.ErrUnknownVariable, lang.ErrUnknownType, lang.ErrTypeMismatch, lang.ErrInvalidValueType,
+		lang.ErrInvalidType, lang.ErrArgumentCount, lang.ErrDuplicateDeclaration, lang.ErrMissingReturn, lang.ErrUnsupported, lang.ErrFile, lang.ErrImportCycle, lang.ErrBudgetExceeded, lang.ErrInvalidNode, lang.ErrInvalidEdit, lang.ErrJSON,
+		lang.ErrUnknownPass, lang.ErrPassFailed}
+	for _, test := range tests {
+		compiler := lang.Compiler{Options: test.options}
+		_, err := compiler.Compile(test.input)
+		if err == nil {
+			t.Fatalf("expected an error for %q", test.input)
+		}
+		var actual []error
+		for _, kind := range kinds {
+			if errors.Is(err, kind) {
+				actual = append(actual, kind)
+			}
+		}
+		if !reflect.DeepEqual(actual, test.kinds) {
+			t.Errorf("expected the error %q of %q to be of the kinds %v, got %v", err, test.input, test.kinds, actual)
+		}
+	}
+}
+
+// TestMessageKinds checks that the message of every error, read from the declarations of the
+// message IDs, is of a kind. Only the messages of warnings and notes have no kind.
+func TestMessageKinds(t *testing.T) {
+	withoutKind := map[string]bool{
+		"MessageDidYouMean":             true,
+		"MessageFirstDeclaredAt":        true,
+		"MessageUnusedVariable":         true,
+		"MessageUnusedParameter":        true,
+		"MessageUnreachableAfterReturn": true,
+		"MessageUnreachableAfterExit":   true,
+		"MessageShadowedParameter":      true,
+		"MessageShadowedVariable":       true,
+		"MessageEvaluationIncomplete":   true,
+		"MessageComplexityTooHigh":      true,
+	}
+	kinds := []error{lang.ErrSyntax, lang.ErrUnexpectedToken, lang.ErrUnknownFunction, lang.ErrUnknownVariable, lang.ErrUnknownType, lang.ErrTypeMismatch, lang.ErrInvalidValueType,
+		lang.ErrInvalidType, lang.ErrArgumentCount, lang.ErrDuplicateDeclaration, lang.ErrMissingReturn, lang.ErrUnsupported, lang.ErrFile, lang.ErrImportCycle, lang.ErrBudgetExceeded, lang.ErrInvalidNode, lang.ErrInvalidEdit, lang.ErrJSON,
+		lang.ErrUnknownPass, lang.ErrPassFailed}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "../pkg/lang/messages.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for _, declaration := range file.Decls {
+		genDecl, ok := declaration.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			if identifier, ok := valueSpec.Type.(*ast.Ident); !ok || identifier.Name != "MessageID" {
+				continue
+			}
+			name := valueSpec.Names[0].Name
+			id, err := strconv.Unquote(valueSpec.Values[0].(*ast.BasicLit).Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			count++
+
+			err = &lang.MessageError{ID: lang.MessageID(id)}
+			hasKind := false
+			for _, kind := range kinds {
+				hasKind = hasKind || errors.Is(err, kind)
+			}
+			if hasKind == withoutKind[name] {
+				t.Errorf("expected %s to have a kind: %t, got %t", name, !withoutKind[name], hasKind)
+			}
+		}
+	}
+	if count == 0 {
+		t.Error("expected message IDs in messages.go")
+	}
+}
+
+func TestUnexpectedEndOfInput(t *testing.T) {
+	input := `function add(a i32, b i32) i32 { return a + b } let x = add(1, 2) as i64 for i := 0; i < 3; i++ { printf(x + 1) }`
+	tokens := lang.Tokenize(input)
+	for end := 1; end < len(tokens); end++ {
+		_, err := lang.Parse(tokens[:end])
+		var parseError *lang.ParseError
+		if !errors.As(err, &parseError) || parseError.Got != "end of input" {
+			continue
+		}
+		var messageError *lang.MessageError
+		if !errors.As(err, &messageError) || messageError.ID != lang.MessageUnexpectedEndOfInput {
+			t.Errorf("expected an unexpected end of input for %d tokens, got %v", end, err)
+		}
+	}
+
+	// An identifier at the end of the input isn't taken as call or add operation
+	if _, err := lang.Parse(lang.Tokenize("add")); !strings.Contains(fmt.Sprint(err), "unexpected identifier") {
+		t.Errorf("expected an unexpected identifier, got %v", err)
+	}
+}
+
+func TestParser(t *testing.T) {
+	tests := []struct {
+		parser   lang.Parser
+		input    string
+		expected []lang.MessageID
+	}{
+		{lang.Parser{MaxErrors: 2}, `let a = ) let b = ] let c = }`, []lang.MessageID{lang.MessageExpectedValue, lang.MessageExpectedValue}},
+		{lang.Parser{MaxErrors: 1}, `function f() { let b = ] } let c = }`, []lang.MessageID{lang.MessageExpectedValue}},
+		{lang.Parser{Strict: true}, `let a = 1 } let b = 2 ; printf(a)`, []lang.MessageID{lang.MessageUnexpectedToken, lang.MessageUnexpectedToken}},
+		{lang.Parser{Disabled: lang.FeatureInlineIR | lang.FeatureGenerics}, `function f<T>(a T) T { return a } llvm { ret void } printf(1)`, []lang.MessageID{lang.MessageFeatureDisabled, lang.MessageFeatureDisabled}},
+		{lang.Parser{Disabled: lang.FeatureSpawn}, `function f() { spawn g() }`, []lang.MessageID{lang.MessageFeatureDisabled}},
+		{lang.Parser{Disabled: lang.FeatureAtomics}, `atomic(relaxed) let c = 0 threadlocal let s = 1`, []lang.MessageID{lang.MessageFeatureDisabled}},
+		{lang.Parser{MaxDepth: 3}, `printf(f(g(1))) printf(f(1))`, []lang.MessageID{lang.MessageNestingTooDeep}},
+		{lang.Parser{MaxDepth: 3}, `function f() { for i := 0; i < 3; i++ { for j := 0; j < 3; j++ { printf(j) } } }`, []lang.MessageID{lang.MessageNestingTooDeep}},
+		{lang.Parser{}, strings.Repeat("printf(", 100000), []lang.MessageID{lang.MessageNestingTooDeep}},
+		{lang.Parser{}, strings.Repeat("function f() { ", 100000), []lang.MessageID{lang.MessageNestingTooDeep}},
+		{lang.Parser{}, "let p: " + strings.Repeat("*", 100000) + "i32 = " + strings.Repeat("&", 100000) + "x", []lang.MessageID{lang.MessageNestingTooDeep}},
+	}
+
+	for _, test := range tests {
+		_, err := test.parser.Parse(lang.Tokenize(test.input))
+		var syntaxErrors lang.SyntaxErrors
+		if !errors.As(err, &syntaxErrors) {
+			t.Fatalf("expected syntax errors for %q, got %v", test.input, err)
+		}
+		var ids []lang.MessageID
+		for _, syntaxError := range syntaxErrors {
+			var messageError *lang.MessageError
+			if errors.As(syntaxError, &messageError) {
+				ids = append(ids, messageError.ID)
+			}
+		}
+		if !reflect.DeepEqual(ids, test.expected) {
+			t.Errorf("expected errors %q for %q, got %q", test.expected, test.input, ids)
+		}
+	}
+
+	if _, err := (&lang.Parser{Disabled: lang.FeatureSpawn}).Parse(lang.Tokenize(`llvm { ret void }`)); err != nil {
+		t.Errorf("expected only spawn to be disabled, got %v", err)
+	}
+	if _, err := lang.Parse(lang.Tokenize(`let a = 1 } ; llvm { ret void }`)); err != nil {
+		t.Errorf("expected the zero parser to accept stray tokens and features, got %v", err)
+	}
+	if features := (lang.FeatureSpawn | lang.FeatureGenerics).String(); features != "generics|spawn" {
+		t.Errorf("expected features generics|spawn, got %s", features)
+	}
+}
+
+// TestParserConcurrent parses with parsers configured differently at the same time, run it with
+// -race to find state shared between the parsers.
+func TestParserConcurrent(t *testing.T) {
+	input := `let a = ) let b = ] } let c = } function f() { printf(g(h(1))) }`
+	tests := []struct {
+		parser lang.Parser
+		errors int
+	}{
+		{lang.Parser{MaxErrors: 1}, 1},
+		{lang.Parser{Strict: true}, 5},
+		{lang.Parser{MaxDepth: 3}, 4},
+	}
+
+	var wg sync.WaitGroup
+	for _, test := range tests {
+		test := test
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, err := test.parser.Parse(lang.Tokenize(input))
+				var syntaxErrors lang.SyntaxErrors
+				if !errors.As(err, &syntaxErrors) || len(syntaxErrors) != test.errors {
+					t.Errorf("expected %d syntax errors with parser %+v, got %v", test.errors, test.parser, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWalk(t *testing.T) {
+	nodes, err := lang.Parse(lang.Tokenize(`function f(a i32) i32 { return a + 1 } let x = f(2) as i64 for i := 0; i < 3; i++ { printf(x) }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var kinds []string
+	lang.WalkNodes(nodes, func(node lang.Node) bool {
+		kinds = append(kinds, strings.TrimPrefix(fmt.Sprintf("%T", node), "*lang."))
+		return true
+	})
+	expected := []string{"FunctionNode", "Parameter", "ReturnNode", "AddOperationNode", "IdentifierNode", "IntLiteralNode", "LetNode", "CastNode", "CallerNode", "IntLiteralNode",
+		"ForNode", "ShortVariableAssigmentNode", "IntLiteralNode", "ConditionNode", "IntLiteralNode", "PostNode", "CallerNode", "IdentifierNode"}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("expected nodes %q, got %q", expected, kinds)
+	}
+
+	// The children of a node aren't walked if the visitor returns false
+	count := 0
+	lang.WalkNodes(nodes, func(node lang.Node) bool {
+		count++
+		_, isFunction := node.(*lang.FunctionNode)
+		return !isFunction
+	})
+	if count != len(expected)-5 {
+		t.Errorf("expected %d nodes without the function body, got %d", len(expected)-5, count)
+	}
+}
+
+func TestDump(t *testing.T) {
+	nodes, err := lang.Parse(lang.Tokenize("let x = a + 1\nprintf(x)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `LetNode 1:1-1:14
+  Identifier: "x"
+  Type: i32
+  Value: AddOperationNode 1:9-1:14
+    LeftValue: IdentifierNode 1:9-1:10
+      Name: "a"
+    RightValue: IntLiteralNode 1:13-1:14
+      Value: 1
+CallerNode 2:1-2:10
+  FunctionName: "printf"
+  Args:
+    IdentifierNode 2:8-2:9
+      Name: "x"
+`
+	if dump := lang.Dump(nodes); dump != expected {
+		t.Errorf("expected dump\n%s\ngot\n%s", expected, dump)
+	}
+
+	// Missing statements and arguments are dumped as nil
+	nodes, err = lang.UnmarshalNodes([]byte(`[null, {"node": "CallerNode", "FunctionName": "printf", "Args": [null]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `nil
+CallerNode
+  FunctionName: "printf"
+  Args:
+    nil
+`
+	if dump := lang.Dump(nodes); dump != expected {
+		t.Errorf("expected dump\n%s\ngot\n%s", expected, dump)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	for _, construct := range constructs {
+		nodes, err := lang.Parse(lang.Tokenize(construct.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded, err := json.Marshal(nodes)
+		if err != nil {
+			t.Fatalf("%s: %v", construct.name, err)
+		}
+
+		decoded, err := lang.UnmarshalNodes(encoded)
+		if err != nil {
+			t.Fatalf("%s: %v", construct.name, err)
+		}
+		if reencoded, err := json.Marshal(decoded); err != nil || !bytes.Equal(reencoded, encoded) {
+			t.Errorf("%s: expected the decoded nodes to encode to %s, got %s", construct.name, encoded, reencoded)
+		}
+		if dump := lang.Dump(decoded); dump != lang.Dump(nodes) {
+			t.Errorf("%s: expected the decoded nodes\n%s\ngot\n%s", construct.name, lang.Dump(nodes), dump)
+		}
+		expected, _ := lang.GenerateLLVMIR(nodes)
+		if actual, _ := lang.GenerateLLVMIR(decoded); actual != expected {
+			t.Errorf("%s: expected the decoded nodes to generate the same IR", construct.name)
+		}
+	}
+
+	for _, invalid := range []string{`[{"node": "UnknownNode"}]`, `[{"node": "LetNode", "Type": "[i32"}]`, `[{"Value": 1}]`, `[1]`} {
+		if _, err := lang.UnmarshalNodes([]byte(invalid)); err == nil {
+			t.Errorf("expected an error decoding %s", invalid)
+		}
+	}
+}
+
+func TestCommentMap(t *testing.T) {
+	input := `// add returns the sum.
+function add(a i32, b i32) i32 {
+	// The sum
+	return a + b // fits into i32
+	// end of body
+}
+let x = add(1, 2) // three
+function empty() {
+	// nothing
+}
+// the end`
+	tokens, comments := lang.TokenizeComments(input)
+	if len(comments) != 7 || comments[0].Text != "// add returns the sum." || comments[0].Pos().String() != "1:1" {
+		t.Fatalf("expected 7 comments, got %d", len(comments))
+	}
+	nodes, err := lang.Parse(tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commentMap := lang.NewCommentMap(nodes, comments)
+	function := nodes[0].(*lang.FunctionNode)
+	attached := map[lang.Node][2][]string{
+		function:         {{"// add returns the sum."}, nil},
+		function.Body[0]: {{"// The sum"}, {"// fits into i32", "// end of body"}},
+		nodes[1]:         {nil, {"// three"}},
+		nodes[2]:         {nil, {"// nothing", "// the end"}},
+	}
+	for node, expected := range attached {
+		var got [2][]string
+		for _, comment := range commentMap[node].Leading {
+			got[0] = append(got[0], comment.Text)
+		}
+		for _, comment := range commentMap[node].Trailing {
+			got[1] = append(got[1], comment.Text)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected the comments %q of %T, got %q", expected, node, got)
+		}
+	}
+
+	expected := `// add returns the sum.
+function add(a i32, b i32) i32 {
+	// The sum
+	return a + b // fits into i32
+	// end of body
+}
+let x = add(1, 2) // three
+function empty() {
+}
+// nothing
+// the end
+`
+	if actual := lang.FormatWithComments(nodes, commentMap); actual != expected {
+		t.Errorf("expected the source\n%s\ngot\n%s", expected, actual)
+	}
+	if root, err := lang.ParseCST(input); err != nil || root.String() != input {
+		t.Errorf("expected the concrete syntax tree to keep the comments, got %v", err)
+	}
+	if normalized := lang.NormalizeKeywords("LET x = 1 // LET"); normalized != "let x = 1 // LET" {
+		t.Errorf("expected the comment to be kept, got %q", normalized)
+	}
+}
+
+func TestParseCST(t *testing.T) {
+	source := "function f(a i32, b i32) i32 {\n\treturn a + b\n}\nprintf(f(1, 2))  \n"
+	root, err := lang.ParseCST(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shape func(element lang.SyntaxElement) string
+	shape = func(element lang.SyntaxElement) string {
+		node, ok := element.(*lang.SyntaxNode)
+		if !ok {
+			return strings.TrimSpace(element.String())
+		}
+		var children []string
+		for _, child := range node.Children {
+			children = append(children, shape(child))
+		}
+		return strings.TrimPrefix(fmt.Sprintf("%T", node.Node), "*lang.") + "[" + strings.Join(children, " ") + "]"
+	}
+	expected := "<nil>[FunctionNode[function f ( Parameter[a i32] , Parameter[b i32] ) i32 { ReturnNode[return AddOperationNode[IdentifierNode[a] + IdentifierNode[b]]] }] " +
+		"CallerNode[printf ( CallerNode[f ( IntLiteralNode[1] , IntLiteralNode[2] )] )]]"
+	if got := shape(root); got != expected {
+		t.Errorf("expected the tree\n%s\ngot\n%s", expected, got)
+	}
+	if root.Trailing != "  \n" {
+		t.Errorf("expected the trailing whitespace %q, got %q", "  \n", root.Trailing)
+	}
+
+	// Every token of the source code is in the tree once, with its whitespace
+	for _, construct := range constructs {
+		root, err := lang.ParseCST(construct.input)
+		if err != nil {
+			t.Fatalf("%s: %v", construct.name, err)
+		}
+		if got := root.String(); got != construct.input {
+			t.Errorf("%s: expected the source code %q, got %q", construct.name, construct.input, got)
+		}
+		if got, expected := len(root.Tokens()), len(lang.Tokenize(construct.input)); got != expected {
+			t.Errorf("%s: expected %d tokens, got %d", construct.name, expected, got)
+		}
+	}
+}
+
+func TestFormatSource(t *testing.T) {
+	input := `struct P { x i32 @align(4) } atomic(relaxed) let c = 0 function f(p *P, n i64) i64 { for i := 0; i < 3; i++ { p.x = i } return n << 1 > 2 ? i64(p.x + 1) : 1.0 as i64 } printf("%d\n", f(&P{x: 1}, 2))`
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `struct P {
+	x i32 @align(4)
+}
+atomic(relaxed) let c = 0
+function f(p *P, n i64) i64 {
+	for i := 0; i < 3; i++ {
+		p.x = i
+	}
+	return n << 1 > 2 ? i64(p.x + 1) : i64(1.0)
+}
+printf("%d\n", f(&P{x: 1}, 2))
+`
+	if actual := lang.Format(nodes); actual != expected {
+		t.Errorf("expected the source\n%s\ngot\n%s", expected, actual)
+	}
+
+	// A missing field of a struct literal is written like a missing value
+	literal := &lang.StructLiteralNode{Name: "P", Fields: []*lang.FieldValue{nil, {Identifier: "x", Value: nil}}}
+	if actual := lang.Format([]lang.Node{literal}); actual != "P{<nil>, x: <nil>}\n" {
+		t.Errorf("expected the source P{<nil>, x: <nil>}, got %s", actual)
+	}
+
+	for _, construct := range constructs {
+		nodes, err := lang.Parse(lang.Tokenize(construct.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		source := lang.Format(nodes)
+		reparsed, err := lang.Parse(lang.Tokenize(source))
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", construct.name, err, source)
+		}
+		if formatted := lang.Format(reparsed); formatted != source {
+			t.Errorf("%s: expected the formatted source\n%s\nto format to itself, got\n%s", construct.name, source, formatted)
+		}
+		expected, _ := lang.GenerateLLVMIR(nodes)
+		if actual, _ := lang.GenerateLLVMIR(reparsed); actual != expected {
+			t.Errorf("%s: expected the formatted source to generate the same IR", construct.name)
+		}
+	}
+}
+
+func TestMinify(t *testing.T) {
+	input := `function a(b i32) i32 { return b } function f(x i32) i32 { let y = a(x) return y } let Total = f(1) printf(c)`
+	nodes, err := lang.Parse(lang.Tokenize(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	originals := lang.Minify(nodes)
+	expected := map[string]string{"a": "a", "b": "b", "d": "f", "e": "x", "f": "y"}
+	if !reflect.DeepEqual(originals, expected) {
+		t.Errorf("expected renamed identifiers %v, got %v", expected, originals)
+	}
+
+	function := nodes[1].(*lang.FunctionNode)
+	if function.Name != "d" || function.Parameters[0].Identifier != "e" {
+		t.Errorf("expected function d(e), got %s(%s)", function.Name, function.Parameters[0].Identifier)
+	}
+	if let := nodes[2].(*lang.LetNode); let.Identifier != "Total" {
+		t.Errorf("expected exported identifier Total to keep its name, got %s", let.Identifier)
+	}
+}
+
+func TestThreadLocal(t *testing.T) {
+	input := `threadlocal let seed: i64 = 42 threadlocal let table: [2]i32 = [1, 2 + 3] function next() i64 { seed = seed + 1 return seed } printf(next()) printf(next()) printf(table[1]) seed = 7 printf(seed)`
+	assert(t, generate(t, input), "threadlocal")
+}
+
+func TestThreadLocalShadowedByParameter(t *testing.T) {
+	input := `threadlocal let x: i32 = 1 function f(x i32) i32 { let y = x + 1 for i := 0; i < 2; i++ { let x = y } return x } printf(f(5)) printf(x)`
+	assert(t, generate(t, input), "threadlocal_shadowed")
+}
+
+func TestThreadLocalInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`function one() i32 { return 1 } threadlocal let x = one()`, lang.MessageThreadLocalInitializer, ""},
+		{`threadlocal let x = 1 threadlocal let y = x`, lang.MessageThreadLocalInitializer, ""},
+		{`threadlocal let p = new(i32)`, lang.MessageThreadLocalInitializer, ""},
+		{`function f() { threadlocal let x = 1 }`, lang.MessageNestedThreadLocal, ""},
+		{`threadlocal let x: i8 = 300`, lang.MessageConstantOverflow, ""},
+		{`threadlocal let t: []i32 = [1, 2]`, lang.MessageThreadLocalInitializer, ""},
+		{`struct Table { values []i32 } threadlocal let t = Table{values: [1, 2]}`, lang.MessageThreadLocalInitializer, ""},
+		{`threadlocal x = 1`, lang.MessageExpectedLetAfterQualifier, "1:13"},
+		{`threadlocal function f() {}`, lang.MessageExpectedLetAfterQualifier, "1:13"},
+	})
+}
+
+func TestVolatileAtomic(t *testing.T) {
+	input := `atomic let counter: i64 = 0 volatile let flag = true atomic(relaxed) let hits = 1 function bump() { let local = 2 atomic(acq_rel) volatile let seen: i32 = local seen = seen + 1 printf(seen) } counter = counter + 5 flag = false hits = hits + counter as i32 bump() printf(counter) printf(hits)`
+	assert(t, generate(t, input), "volatile_atomic")
+}
+
+func TestVolatileAtomicInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`atomic let b = true`, lang.MessageAtomicType, ""},
+		{`atomic let a: [2]i32 = [1, 2]`, lang.MessageAtomicType, ""},
+		{`struct P { x i32 } function f() { atomic let p = P{x: 1} }`, lang.MessageAtomicType, ""},
+		{`volatile volatile let x = 1`, lang.MessageDuplicateQualifier, "1:10"},
+		{`atomic(weak) let x = 1`, lang.MessageUnknownMemoryOrdering, "1:8"},
+		{`atomic( let x = 1`, lang.MessageExpectedMemoryOrdering, "1:9"},
+		{`atomic(relaxed let x = 1`, lang.MessageExpectedCloseParenthesisAfterMemoryOrdering, "1:16"},
+		{`volatile x = 1`, lang.MessageExpectedLetAfterQualifier, "1:10"},
+		{`atomic`, lang.MessageUnexpectedEndOfInput, "1:7"},
+	})
+}
+
+func TestStructLayout(t *testing.T) {
+	input := `@packed struct Header { tag i8, size i32 } struct Entry { tag i8, value i64 @align(16), header Header } @packed struct Pair { a i8, b i16 @align(4) } function sum(e *Entry) i32 { return e.header.size + e.value as i32 } let e = Entry{tag: 1, value: 2} e.header.size = 9 printf(sum(&e)) let p = Pair{b: 3} p.b = p.b + 1 printf(p.b) let h = new(Header) h.size = 5`
+	assert(t, generate(t, input), "struct_layout")
+}
+
+func TestStructLayoutInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`@aligned struct A { x i32 }`, lang.MessageUnknownStructAttribute, "1:2"},
+		{`@packed let x = 1`, lang.MessageExpectedStructAfterAttribute, "1:9"},
+		{`@packed`, lang.MessageUnexpectedEndOfInput, "1:8"},
+		{`struct A { x i32 @packed }`, lang.MessageUnknownFieldAttribute, "1:19"},
+		{`struct A { x i32 @align }`, lang.MessageExpectedOpenParenthesisAfterAlign, "1:25"},
+		{`struct A { x i32 @align() }`, lang.MessageExpectedAlignment, "1:25"},
+		{`struct A { x i32 @align(3) }`, lang.MessageInvalidAlignment, "1:25"},
+		{`struct A { x i32 @align(0) }`, lang.MessageInvalidAlignment, "1:25"},
+		{`struct A { x i32 @align(8 }`, lang.MessageExpectedCloseParenthesisAfterAlignment, "1:27"},
+	})
+}
+
+func TestOption(t *testing.T) {
+	input := `struct Node { value i32 } function find(n i32) option<i32> { let r = some(n + 1) return r } function missing() option<*Node> { return none } function or(x option<i64>) i64 { return unwrap_or(x, 1) } let a: option<i64> = some(5) let b: option<i64> = none printf(unwrap_or(a, 0)) printf(or(b)) printf(unwrap_or(find(2), 0)) let n = Node{value: 4} let p = unwrap_or(missing(), &n) printf(p.value) b = a printf(or(b)) let nested: option<option<i8>> = some(some(3)) printf(unwrap_or(unwrap_or(nested, none), 9))`
+	assert(t, generate(t, input), "option")
+}
+
+func TestOptionInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let x = none`, lang.MessageUntypedNone, ""},
+		{`let x: option<i32> = some(1) let y: i32 = x`, lang.MessageValueType, ""},
+		{`let x: option<i32> = 5`, lang.MessageIntegerLiteralType, ""},
+		{`let x: option<i32> = some(true)`, lang.MessageBoolLiteralType, ""},
+		{`printf(unwrap_or(5, 1))`, lang.MessageUnwrapType, ""},
+		{`let x: option<i32> = none printf(unwrap_or(x, true))`, lang.MessageBoolLiteralType, ""},
+		{`let x: option<i32> = none printf(unwrap_or(x))`, lang.MessageExpectedTwoParameters, ""},
+		{`let x = some()`, lang.MessageExpectedOneParameter, ""},
+		{`let x: option<Missing> = none`, lang.MessageUnknownType, ""},
+		{`let x: option<i32> = none printf(x)`, lang.MessagePrintType, ""},
+		{`let x: option<i32 = none`, lang.MessageExpectedGreaterThanAfterElementType, "1:19"},
+		{`let x: option<> = none`, lang.MessageExpectedType, "1:15"},
+	})
+}
+
+func TestResult(t *testing.T) {
+	input := `function parse(n i32) result<i32> { let r: result<i32> = err(n) return r } function half(n i32) result<i32> { return ok(n) } function twice(n i32) result<i64> { let h = try half(n) let x = try parse(h) return ok(x as i64) } function safe(n i32) result<i32> { let v = try half(n) return ok(v + 1) } printf(unwrap_or(twice(3), -1)) printf(unwrap_or(safe(4), -1)) let r: result<bool> = err(2) printf(unwrap_or(r, true))`
+	assert(t, generate(t, input), "result")
+}
+
+func TestResultInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let r = err(1)`, lang.MessageUntypedErr, ""},
+		{`let r: result<i32> = err(true)`, lang.MessageBoolLiteralType, ""},
+		{`let r: result<i32> = ok(1.5)`, lang.MessageFloatLiteralType, ""},
+		{`let r: result<i32> = ok(1) let x: i32 = r`, lang.MessageValueType, ""},
+		{`let r: result<i32> = ok(1) let x = try r`, lang.MessageTryOutsideFunction, ""},
+		{`function f() i32 { let r: result<i32> = ok(1) let x = try r return x }`, lang.MessageTryReturnType, ""},
+		{`function f() result<i32> { let x = try 5 return ok(x) }`, lang.MessageTryType, ""},
+		{`let r: option<i32> = ok(1)`, lang.MessageValueType, ""},
+		{`let r: result<i32 = ok(1)`, lang.MessageExpectedGreaterThanAfterElementType, "1:19"},
+		{`let x = try`, lang.MessageUnexpectedEndOfInput, "1:12"},
+	})
+}
+
+func TestConditional(t *testing.T) {
+	input := `function pick(c bool, a i64) i64 { return c ? a : 7 } let t = true let f = false let x = t ? 1 : 2 let y: i8 = f ? 1 : t ? 3 : 4 printf(x) printf(y) printf(pick(f, 9)) printf(t ? x + 10 : x) let z = f ? 2.5 : 1 printf(z)`
+	assert(t, generate(t, input), "conditional")
+}
+
+func TestConditionalInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let x = 1 ? 2 : 3`, lang.MessageConditionType, ""},
+		{`let t = true let x = t ? 1 : true`, lang.MessageBoolLiteralType, ""},
+		{`let t = true let x: bool = t ? 1 : 2`, lang.MessageIntegerLiteralType, ""},
+		{`function f() {} let t = true let x = t ? f() : f()`, lang.MessageVoidCallAsValue, ""},
+		{`let t = true let x = t ? 1`, lang.MessageUnexpectedEndOfInput, "1:27"},
+		{`let t = true let x = t ? 1 2`, lang.MessageExpectedColonInConditional, "1:28"},
+		{`let t = true let x = t ?`, lang.MessageUnexpectedEndOfInput, "1:25"},
+	})
+}
+
+func TestString(t *testing.T) {
+	input := `function greet(name string) string { return "hello " + name } let a = "foo" let b = a + "bar" printf(b) printf(greet("gusty") + "!") let c: string = a + b + a printf(c) printf("line\n")`
+	assert(t, generate(t, input), "string")
+}
+
+func TestRead(t *testing.T) {
+	input := `let n = read_int() let name = read_line() let total = n + read_int() println(name) printf(total) printf(len(read_line()))`
+	assert(t, generate(t, input), "read")
+}
+
+func TestReadInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let n = read_int(1)`, lang.MessageExpectedNoParameters, ""},
+		{`let s = read_line("prompt")`, lang.MessageExpectedNoParameters, ""},
+		{`let n: i32 = read_int()`, lang.MessageValueType, ""},
+		{`let s = read_line() + 1`, lang.MessageIntegerLiteralType, ""},
+	})
+}
+
+func TestArguments(t *testing.T) {
+	input := `function first() string { return arg(1) } let n = args_count() printf(n) let i: i8 = 2 println(arg(0)) println(arg(i)) println(first())`
+	assert(t, generate(t, input), "arguments")
+}
+
+func TestArgumentsInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let n = args_count(1)`, lang.MessageExpectedNoParameters, ""},
+		{`let s = arg()`, lang.MessageExpectedOneParameter, ""},
+		{`let s = arg(1, 2)`, lang.MessageExpectedOneParameter, ""},
+		{`let s = arg("1")`, lang.MessageInvalidArrayIndexType, ""},
+		{`let s = arg(1.5)`, lang.MessageFloatLiteralType, ""},
+		{`let n: i32 = args_count()`, lang.MessageValueType, ""},
+	})
+}
+
+func TestExitStatus(t *testing.T) {
+	input := `function check(n i32) { printf(n) exit(n) } let code: i32 = 3 check(code) printf(1) return code + 1`
+	assert(t, generate(t, input), "exit_status")
+}
+
+func TestExitStatusInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`exit()`, lang.MessageExpectedOneParameter, ""},
+		{`exit(1, 2)`, lang.MessageExpectedOneParameter, ""},
+		{`exit("a")`, lang.MessageValueType, ""},
+		{`exit(1.5)`, lang.MessageFloatLiteralType, ""},
+		{`return "a"`, lang.MessageValueType, ""},
+		{`let x: i64 = 1 return x`, lang.MessageValueType, ""},
+	})
+}
+
+func TestMainFunction(t *testing.T) {
+	input := `function twice(a i32) i32 { return a + a } function main() i32 { printf(twice(21)) return twice(2) } printf(1)`
+	assert(t, generate(t, input), "main_function")
+}
+
+func TestMainFunctionInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`function main(a i32) {}`, lang.MessageInvalidMain, ""},
+		{`function main() string { return "a" }`, lang.MessageInvalidMain, ""},
+		{`function main<T>() {}`, lang.MessageInvalidMain, ""},
+		{`function main() {} function main() {}`, lang.MessageDuplicateFunction, "1:20"},
+	})
+}
+
+func TestSpawn(t *testing.T) {
+	input := `function work(id i32, n i64) { printf(id) } function start(n i64) i64 { return spawn work(2, n) } let first = spawn work(1, 10) join(first) join(start(20)) spawn work(3, 30)`
+	assert(t, generate(t, input), "spawn")
+}
+
+func TestSpawnInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`spawn 1`, lang.MessageExpectedCallAfterSpawn, "1:7"},
+		{`spawn work`, lang.MessageExpectedCallAfterSpawn, "1:7"},
+		{`let t = spawn`, lang.MessageUnexpectedEndOfInput, "1:14"},
+		{`spawn missing()`, lang.MessageInvalidSpawnTarget, ""},
+		{`spawn printf(1)`, lang.MessageInvalidSpawnTarget, ""},
+		{`function work(a i32) {} spawn work()`, lang.MessageExpectedParameters, ""},
+		{`function work(a i32) {} spawn work("a")`, lang.MessageValueType, ""},
+		{`join()`, lang.MessageExpectedOneParameter, ""},
+		{`join("a")`, lang.MessageValueType, ""},
+	})
+}
+
+func TestSync(t *testing.T) {
+	input := `function work(counter *i64, m i64) { atomic_add(counter, 1) lock(m) unlock(m) } let counter = new(i64) let m = mutex() let worker = spawn work(counter, m) join(worker) atomic_store(counter, atomic_load(counter) + 1) let p = new(*i64) atomic_store(p, counter) printf(*atomic_load(p))`
+	assert(t, generate(t, input), "sync")
+}
+
+func TestSyncInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let x: i64 = 1 atomic_add(x, 1)`, lang.MessageAtomicOperand, ""},
+		{`let p = new(f64) atomic_add(p, 1)`, lang.MessageAtomicAddOperand, ""},
+		{`let p = new(bool) atomic_load(p)`, lang.MessageAtomicOperand, ""},
+		{`let p = new(i32) atomic_store(p)`, lang.MessageExpectedTwoParameters, ""},
+		{`let p = new(i32) atomic_store(p, "a")`, lang.MessageValueType, ""},
+		{`let m = mutex(1)`, lang.MessageExpectedNoParameters, ""},
+		{`lock()`, lang.MessageExpectedOneParameter, ""},
+		{`unlock("a")`, lang.MessageValueType, ""},
+	})
+}
+
+func TestFormat(t *testing.T) {
+	input := `function label(n i32) string { return format("#%d", n) } let big: i64 = 1 << 40 let s = format("x=%d big=%ld f=%.2f s=%s", 42, big, 1.5, label(3)) println(s + format("!"))`
+	assert(t, generate(t, input), "format")
+}
+
+func TestFormatInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let s = format()`, lang.MessageExpectedFormatString, ""},
+		{`let s = format(1)`, lang.MessageIntegerLiteralType, ""},
+		{`let a: []i32 = [1] let s = format("%d", a)`, lang.MessageFormatType, ""},
+		{`let s = format("%d", missing)`, lang.MessageVariableNotFound, ""},
+	})
+}
+
+func TestComparison(t *testing.T) {
+	input := `function same(a string, b string) bool { return a == b } let name = "gusty" let n = 5 let big: i64 = 3 let b = true printf(same(name, "gusty")) printf(name != "go") printf("abc" < "abd") printf(n + 1 >= 6 ? 7 : 8) printf(1.5 > 2.0) printf(b == false) printf(big <= 2) printf(n > 4)`
+	assert(t, generate(t, input), "comparison")
+}
+
+func TestComparisonInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let p = new(i32) let q = new(i32) let same = p == q`, lang.MessageComparisonType, ""},
+		{`let a = "a" let same = a == 1`, lang.MessageIntegerLiteralType, ""},
+		{`let b = true let less = b < false`, lang.MessageComparisonType, ""},
+		{`let s: []i32 = [1] let same = s == s`, lang.MessageComparisonType, ""},
+		{`let n = 1 let same = n == "a"`, lang.MessageValueType, ""},
+	})
+}
+
+func TestExtern(t *testing.T) {
+	input := `extern function puts(s string) i32 extern function labs(n i64) i64 extern function srand(seed i32) function greet(name string) { puts("hello " + name) } srand(1) greet("gusty") printf(labs(-7 as i64)) let n = puts("bye") printf(n)`
+	assert(t, generate(t, input), "extern")
+}
+
+func TestExternInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`extern function puts(s string) i32 extern function puts(s string) i32`, lang.MessageDuplicateFunction, "1:36"},
+		{`extern function printf(s string) i32`, lang.MessageExternDeclared, ""},
+		{`extern function f(p Point)`, lang.MessageUnknownType, ""},
+		{`extern function f() i32 let x: bool = f()`, lang.MessageValueType, ""},
+		{`extern function f(a i32) f()`, lang.MessageExpectedParameters, ""},
+		{`function g() { extern function f() }`, lang.MessageNestedExtern, ""},
+		{`extern puts(s string) i32`, lang.MessageExpectedFunctionAfterExtern, "1:8"},
+		{`extern function (s string)`, lang.MessageExpectedIdentifierAfterFunction, "1:17"},
+		{`extern function f(s string) {`, lang.MessageExternBody, "1:29"},
+	})
+}
+
+func TestInlineIR(t *testing.T) {
+	input := `llvm {
+	declare i32 @puts(ptr)
+	define i32 @twice(i32 %x) {
+		%y = add i32 %x, %x ; doubled, see "{"
+		ret i32 %y
+	}
+} extern function twice(x i32) i32 function show(a i32, s string, flag bool) {
+	llvm {
+		%sum = add i32 %a, 1
+		call i32 @puts(ptr %s)
+	}
+	printf(a)
+} show(twice(20), "hello", true)`
+	assert(t, generate(t, input), "inline_ir")
+}
+
+func TestInlineIRInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`llvm { define i32 @main() { ret i32 0 } }`, lang.MessageInlineIRRedefined, ""},
+		{`llvm { this is not llvm ir }`, lang.MessageInlineIR, ""},
+		{`function f() { llvm { %x = add i32 %missing, 1 } }`, lang.MessageInlineIR, ""},
+		{`llvm { @__gusty_format_string = global i32 0 }`, lang.MessageInlineIRRedefined, ""},
+		{`llvm`, lang.MessageUnexpectedEndOfInput, "1:5"},
+		{`llvm { declare i32 @puts(ptr)`, lang.MessageExpectedInlineIRAfterLLVM, "1:6"},
+		{`let llvm = 1`, lang.MessageExpectedIdentifierAfterLet, "1:5"},
+	})
+}
+
+func TestFunctionAttributes(t *testing.T) {
+	input := `extern function exit(code i32) @noreturn extern function abort() @inline function twice(a i32) i32 { return a + a } @noinline @noinline function thrice(a i32) i32 { return a + a + a } @noreturn function fail(code i32) i32 { printf(code) exit(code) } printf(twice(2)) printf(thrice(2)) fail(1)`
+	assert(t, generate(t, input), "function_attributes")
+}
+
+func TestFunctionAttributesInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`@hot function f() {}`, lang.MessageUnknownFunctionAttribute, "1:2"},
+		{`@packed function f() {}`, lang.MessageUnknownFunctionAttribute, "1:2"},
+		{`@inline struct A { x i32 }`, lang.MessageUnknownStructAttribute, "1:2"},
+		{`@inline let x = 1`, lang.MessageExpectedStructAfterAttribute, "1:9"},
+		{`@inline`, lang.MessageUnexpectedEndOfInput, "1:8"},
+		{`@ function f() {}`, lang.MessageExpectedStructAfterAttribute, "1:3"},
+		{`@inline @noinline function f() {}`, lang.MessageConflictingFunctionAttributes, ""},
+	})
+}
+
+func TestGenerics(t *testing.T) {
+	input := `function pick<T>(c bool, a T, b T) T { return c ? a : b } function add<T>(a T, b T) T { return a + b } function first<T>(s []T) T { return s[0] } function convert<T, U>(x T, y U) U { return U(x) + y } let x: i64 = 5 printf(add(x, 3)) printf(add(1, 2)) printf(add(2, 3)) printf(add(1.5, 0.5)) printf(pick(false, "a", "b")) let s: []i32 = [7, 8] printf(first(s)) printf(convert(2, x))`
+	assert(t, generate(t, input), "generics")
+}
+
+func TestGenericsInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`function f<>() {}`, lang.MessageExpectedTypeParameter, "1:12"},
+		{`function f<T() {}`, lang.MessageExpectedCloseAngleAfterTypeParameters, "1:13"},
+		{`function f<T, T>(a T) {}`, lang.MessageDuplicateTypeParameter, ""},
+		{`function f<let>(a i32) {}`, lang.MessageExpectedTypeParameter, "1:12"},
+		{`extern function f<T>(a T)`, lang.MessageGenericExtern, ""},
+		{`function add<T>(a T, b T) T { return a + b } let x = add(1, 1.5)`, lang.MessageFloatLiteralType, ""},
+		{`function add<T>(a T, b T) T { return a + b } let x = add(1)`, lang.MessageExpectedParameters, ""},
+		{`function add<T>(a T, b T) T { return a + b } let x = add(true, false)`, lang.MessageInvalidAddOperandType, ""},
+		{`function first<T>(s []T) T { return s[0] } let x = first(1)`, lang.MessageGenericParameterMismatch, ""},
+		{`function zero<T>() T { return T(0) } let x = zero()`, lang.MessageUninferredTypeParameter, ""},
+	})
+}
+
+func TestStringLength(t *testing.T) {
+	input := `function size(s string) i64 { return len(s) } let s = "hello" let a: [3]i32 = [1, 2, 3] printf(len(s)) printf(len(s + " gusty")) printf(size("four")) printf(len(a) + len(""))`
+	assert(t, generate(t, input), "string_length")
+}
+
+func TestStringInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let x = "a" + 1`, lang.MessageIntegerLiteralType, ""},
+		{`let x: i32 = "a"`, lang.MessageValueType, ""},
+		{`let x = "a" let y = x as i32`, lang.MessageInvalidCast, ""},
+		{`let b = true let x = b + "a"`, lang.MessageValueType, ""},
+		{`threadlocal let s = "x"`, lang.MessageThreadLocalInitializer, ""},
+		{`let s = "a" let n = cap(s)`, lang.MessageInvalidBuiltinValue, ""},
+	})
+}
+
+func TestShift(t *testing.T) {
+	input := `function bit(n i32) i64 { return 1 << n } let a = 1 << 4 let b = 256 >> 2 + 1 let c: i8 = -128 >> 3 let n: i64 = 40 let d = a << n let o: option<option<i32>> = some(some(a >> 1)) printf(a) printf(b) printf(c) printf(d) printf(bit(33)) printf(unwrap_or(unwrap_or(o, none), 0)) printf(1 << 2 << 3)`
+	assert(t, generate(t, input), "shift")
+}
+
+func TestTypeAlias(t *testing.T) {
+	input := `type Index = i32 type Indices = []Index type Maybe = option<Index> struct Point { x Index y Index } type Position = Point function next(i Index) Index { return i + Index(1) } let a: Index = next(Index(5)) let s: Indices = [a, 2] let m: Maybe = some(a) let p = Position{x: a, y: s[1]} let q: *Position = &p let w = a as Index printf(a) printf(len(s)) printf(unwrap_or(m, 0)) printf(q.y) printf(w)`
+	assert(t, generate(t, input), "type_alias")
+}
+
+func TestTypeAliasInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`type A = B type B = A let x: A = 1`, lang.MessageTypeAliasCycle, ""},
+		{`type A = *A`, lang.MessageTypeAliasCycle, ""},
+		{`type A = i32 type A = i64`, lang.MessageDuplicateTypeName, ""},
+		{`struct P { x i32 } type P = i32`, lang.MessageDuplicateTypeName, ""},
+		{`type = i32`, lang.MessageExpectedIdentifierAfterTypeKeyword, "1:6"},
+		{`type A i32`, lang.MessageExpectedEqualsAfterTypeAlias, "1:8"},
+		{`function f() { type A = i32 }`, lang.MessageNestedTypeAlias, ""},
+	})
+}
+
+func TestPrint(t *testing.T) {
+	input := `let b: i8 = -3 let big: i64 = 5000000000 let f: f32 = 1.5 print(b) print(" ") println(big) println(f) println(2.25) println(true) let t = 1 print(t) println("!") print(false) println("")`
+	assert(t, generate(t, input), "print")
+}
+
+func TestPrintInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`print()`, lang.MessageExpectedOneParameter, ""},
+		{`println(1, 2)`, lang.MessageExpectedOneParameter, ""},
+		{`let a = [1, 2] println(a)`, lang.MessagePrintType, ""},
+		{`function f() {} println(f())`, lang.MessagePrintType, ""},
+		{`struct P { x i32 } let p = P{x: 1} print(p)`, lang.MessagePrintType, ""},
+	})
+}
+
+func TestMath(t *testing.T) {
+	input := `let a = -5 let f = 2.25 let g: f32 = 9.0 let b: i8 = abs(-7) let c: i64 = max(3, 4) printf(abs(a)) printf(min(a, 3)) printf(max(2, a)) printf(sqrt(f)) printf(pow(f, 2)) printf(abs(0.0 + -1.5)) printf(sqrt(g)) printf(b) printf(c) printf(sqrt(16)) printf(min(1.5, f))`
+	assert(t, generate(t, input), "math")
+
+	shadowed := string(generate(t, `function abs(x i32) i32 { return 0 } printf(abs(-3))`))
+	if strings.Contains(shadowed, "llvm.abs") {
+		t.Errorf("expected the abs function of the program to shadow the builtin, got\n%s", shadowed)
+	}
+}
+
+func TestMathInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let x = abs()`, lang.MessageExpectedOneParameter, ""},
+		{`let x = sqrt(2.0, 3.0)`, lang.MessageExpectedOneParameter, ""},
+		{`let x = max(1)`, lang.MessageExpectedTwoParameters, ""},
+		{`let b = true let x = abs(b)`, lang.MessageMathType, ""},
+		{`let a = 1 let x = pow(a, 2)`, lang.MessageMathFloatType, ""},
+		{`let a = 1 let x = sqrt(a)`, lang.MessageMathFloatType, ""},
+		{`let a = 1 let f = 1.5 let x = max(a, f)`, lang.MessageValueType, ""},
+		{`let x: i8 = max(1, 300)`, lang.MessageConstantOverflow, ""},
+		{`let x: bool = abs(1)`, lang.MessageValueType, ""},
+		{`let x = min(true, false)`, lang.MessageMathType, ""},
+	})
+}
+
+func TestShiftInvalid(t *testing.T) {
+	assertInvalid(t, []invalidProgram{
+		{`let x = 1 << 32`, lang.MessageShiftAmount, ""},
+		{`let x: i8 = 1 << 8`, lang.MessageShiftAmount, ""},
+		{`let x = 1 << -1`, lang.MessageShiftAmount, ""},
+		{`let x = 1.5 << 1`, lang.MessageShiftOperandType, ""},
+		{`let x = 1 << true`, lang.MessageBoolLiteralType, ""},
+		{`let b = true let x = b >> 1`, lang.MessageShiftOperandType, ""},
+		{`let f = 1.5 let x = 1 << f`, lang.MessageShiftOperandType, ""},
+		{`let x = 1 <<`, lang.MessageUnexpectedEndOfInput, "1:13"},
+		{`let x: option<i32>> = none`, lang.MessageUnexpectedGreaterThanAfterType, "1:18"},
+		{`let x: option<option<i32> = none`, lang.MessageExpectedGreaterThanAfterElementType, "1:27"},
+	})
+}
@@ -0,0 +1,120 @@
+// Package examples builds and runs the example programs of the examples directory. Every
+// example is a gusty program next to a file holding the output it is expected to print, so the
+// examples double as documentation of the supported language features.
+package examples
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/donutloop/gusty/pkg/lang"
+)
+
+// Constants for the file extensions of the files of an example.
+const (
+	sourceExtension = ".gusty" // The extension of the program.
+	outputExtension = ".out"   // The extension of the expected output.
+	inputExtension  = ".in"    // The extension of the optional input.
+)
+
+// LLC and CC are the commands used to compile the LLVM IR of an example into an object file and
+// to link the object file into an executable.
+var (
+	LLC = "llc"
+	CC  = "gcc"
+)
+
+// Example is a gusty program together with the output it is expected to print.
+type Example struct {
+	Name     string // The file name of the program without its extension.
+	Source   string // The source code of the program.
+	Expected string // The expected output of the program.
+	Input    string // The standard input of the program, empty if the example has no input file.
+}
+
+// Load reads every example of the directory, sorted by name. Every program needs a file with its
+// expected output next to it, e.g. fibonacci.gusty and fibonacci.out. A file with the extension .in
+// next to it, e.g. fibonacci.in, is passed to the program as its standard input.
+func Load(dir string) ([]Example, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+sourceExtension))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	examples := make([]Example, 0, len(paths))
+	for _, path := range paths {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		expected, err := os.ReadFile(strings.TrimSuffix(path, sourceExtension) + outputExtension)
+		if err != nil {
+			return nil, err
+		}
+		input, err := os.ReadFile(strings.TrimSuffix(path, sourceExtension) + inputExtension)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		examples = append(examples, Example{
+			Name:     strings.TrimSuffix(filepath.Base(path), sourceExtension),
+			Source:   string(source),
+			Expected: string(expected),
+			Input:    string(input),
+		})
+	}
+	return examples, nil
+}
+
+// Run compiles the example into an executable in a temporary directory, runs it with the input of
+// the example and returns what the executable prints. It fails if the example doesn't compile or the executable exits with an error.
+func (e Example) Run() (string, error) {
+	var compiler lang.Compiler
+	llvmIR, err := compiler.Compile(e.Source)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", e.Name, err)
+	}
+
+	dir, err := os.MkdirTemp("", "gusty-example-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	irPath := filepath.Join(dir, e.Name+".ll")
+	objectPath := filepath.Join(dir, e.Name+".o")
+	executablePath := filepath.Join(dir, e.Name)
+	if err := os.WriteFile(irPath, []byte(llvmIR), 0o644); err != nil {
+		return "", err
+	}
+	if err := command(LLC, "-opaque-pointers", "-relocation-model=pic", "-filetype=obj", irPath, "-o", objectPath).Run(); err != nil {
+		return "", fmt.Errorf("%s: %s: %w", e.Name, LLC, err)
+	}
+	if err := command(CC, objectPath, "-o", executablePath).Run(); err != nil {
+		return "", fmt.Errorf("%s: %s: %w", e.Name, CC, err)
+	}
+
+	var output bytes.Buffer
+	run := command(executablePath)
+	run.Stdin = strings.NewReader(e.Input)
+	run.Stdout = &output
+	if err := run.Run(); err != nil {
+		return output.String(), fmt.Errorf("%s: %w", e.Name, err)
+	}
+	return output.String(), nil
+}
+
+// command returns the command running the program with the arguments, reporting errors on
+// the standard error of the process.
+func command(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	return cmd
+}
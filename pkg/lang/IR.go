@@ -1,7 +1,9 @@
 package lang
 
 import (
-	"fmt"
+	"io/fs"
+	"os"
+
 	"github.com/google/uuid"
 	"tinygo.org/x/go-llvm"
 )
@@ -60,18 +62,24 @@ func init() {
 
 // Caller represents a function or method in the LLVM IR.
 type Caller struct {
-	Value *llvm.Value // The LLVM value representing the function or method.
-	Type  *llvm.Type  // The LLVM type representing the function or method signature.
+	Value          *llvm.Value // The LLVM value representing the function or method.
+	Type           *llvm.Type  // The LLVM type representing the function or method signature.
+	ParameterTypes []dataType  // The data types of the function or method parameters.
+	ReturnType     dataType    // The data type of the value returned by the function or method.
 }
 
 // Variable represents a local variable in the LLVM IR.
 type Variable struct {
-	Value *llvm.Value // The LLVM value representing the local variable.
+	Value    *llvm.Value    // The LLVM value representing the local variable.
+	Type     dataType       // The data type of the value stored in the local variable.
+	Volatile bool           // Whether the loads and stores of the variable are volatile.
+	Ordering MemoryOrdering // The ordering of the atomic loads and stores of the variable, if any.
 }
 
 // Argument represents a function or method argument in the LLVM IR.
 type Argument struct {
 	Value *llvm.Value // The LLVM value representing the function or method argument.
+	Type  dataType    // The data type of the function or method argument.
 }
 
 // Global represents a global variable in the LLVM IR.
@@ -79,44 +87,150 @@ type Global struct {
 	Value *llvm.Value // The LLVM value representing the global variable.
 }
 
+// Struct represents a struct type declared in the module.
+type Struct struct {
+	Type       llvm.Type // The named LLVM struct type.
+	Fields     []*Field  // The fields of the struct in declaration order.
+	Elements   []int     // The position of every field among the elements of the LLVM type, nil if they are equal.
+	Alignments []int     // The alignment of every field in bytes if the struct is packed or has aligned fields.
+	Alignment  int       // The alignment of the struct in bytes, 0 for the alignment of its most aligned field.
+}
+
+// element returns the position of the field at the given position among the elements of the LLVM
+// struct type, which differ if padding elements were inserted for the layout of the struct.
+func (s Struct) element(i int) int {
+	if s.Elements == nil {
+		return i
+	}
+	return s.Elements[i]
+}
+
+// field returns the position and the declaration of the field with the given name.
+// It returns false if the struct has no such field.
+func (s Struct) field(name string) (int, *Field, bool) {
+	for i, field := range s.Fields {
+		if field.Identifier == name {
+			return i, field, true
+		}
+	}
+	return -1, nil, false
+}
+
 // Scope represents the current scope for an LLVM function or method.
-// It contains mappings of names to callers (functions or methods),
-// local variables, and function or method arguments.
+// It contains mappings of names to callers (functions or methods)
+// and function or method arguments. The variables are looked up by
+// the declarations the resolution of the global scope binds their uses to.
 type Scope struct {
-	Callers          map[string]Caller
-	Variables        map[string]Variable
-	Arguments        map[string]Argument
-	PreviousVariable Variable
+	Callers   *Symbols[Caller]
+	Arguments *Symbols[Argument]
+	Function  *FunctionNode // The function the scope belongs to, nil for the synthesized main function.
 }
 
 // GlobalScope represents the global scope for the LLVM module.
 // It contains mappings of names to callers (functions or methods),
-// global variables, and module-level globals.
+// module-level globals and struct types, and the variables of the
+// module by the symbols of their declarations.
 type GlobalScope struct {
-	Callers   map[string]Caller
-	Variables map[string]Variable
-	Globals   map[string]Global
+	Callers    *Symbols[Caller]
+	Resolution *Resolution          // The scopes of the program, binding every use of a name to its declaration.
+	Variables  map[*Symbol]Variable // The variables generated so far, by the symbols of their declarations.
+	Globals    *Symbols[Global]
+	Structs    *Symbols[Struct]
+	Embeds     *Symbols[Variable]      // The constant global arrays holding the bytes of embedded files.
+	Generics   *Symbols[*FunctionNode] // The generic functions, instantiated at their calls.
+	InlineIR   []string                // The LLVM IR of the inline blocks, linked into the module once it is generated.
+	Options    Options                 // The options the module is generated with.
+	Context    llvm.Context            // The LLVM context owning the module and all its types.
+}
+
+// Options holds the options which change how source code is read and which code is generated.
+// The zero value generates code without runtime checks.
+type Options struct {
+	// BoundsChecks enables runtime checks which trap if an array index is out of range.
+	// Constant indexes are always checked at compile time.
+	BoundsChecks bool
+	// CaseInsensitiveKeywords accepts keywords in any case, e.g. LET or Function, which helps
+	// beginners. Source code can be rewritten to the canonical spelling with NormalizeKeywords.
+	CaseInsensitiveKeywords bool
+	// MinifyIdentifiers renames the functions, variables and parameters which aren't exported to
+	// short names before generating code, see Minify. The original names are kept in the
+	// gusty.identifiers metadata of the module.
+	MinifyIdentifiers bool
+	// EmbedFS is the file system the files of embed declarations are read from.
+	// If it is nil, they are read relative to the current working directory.
+	EmbedFS fs.FS
+	// Locale is the locale the errors returned by the compiler render their messages in.
+	// The empty locale renders them in English.
+	Locale Locale
+	// ImportFS is the file system the files of import declarations are read from by the Compiler.
+	// If it is nil, they are read relative to the current working directory.
+	ImportFS fs.FS
+	// WholeProgram compiles the program as a whole, with main as its only entry point, so the
+	// Compiler removes every function main can't reach before it generates code, see PruneUnreachable.
+	WholeProgram bool
+	// TypeCheck checks the data types of the program with Check before the Compiler generates code,
+	// so type errors are reported at their position.
+	TypeCheck bool
+	// WarnUnused reports the variables and parameters the program never reads as warnings to the
+	// OnDiagnostic hook of the Compiler, see Unused.
+	WarnUnused bool
+	// WarnUnreachable reports the statements which can never run, e.g. because they follow a
+	// return, as warnings to the OnDiagnostic hook of the Compiler, see Unreachable.
+	WarnUnreachable bool
+	// WarnShadowing reports the parameters and variables which shadow a value of an enclosing
+	// scope as warnings to the OnDiagnostic hook of the Compiler, see Shadowed.
+	WarnShadowing bool
+	// EliminateDeadCode removes the statements following a return and, unless the program is a
+	// library, the functions which are never called before the Compiler generates code, see
+	// EliminateDeadCode. The removed code is reported to the OnDeadCode hook.
+	EliminateDeadCode bool
+	// FoldConstantCalls replaces the calls of pure functions with constant parameters with their
+	// results before the Compiler generates code, see FoldConstantCalls.
+	FoldConstantCalls bool
+	// EvaluationSteps is the number of steps the evaluation of a call may take, see FoldConstantCalls.
+	EvaluationSteps int
+	// GarbageCollection allocates heap memory, e.g. of strings, slices and new, with a conservative
+	// mark-sweep collector generated into the module, which releases the memory once nothing points
+	// into it anymore, so programs don't need to call free, which does nothing. Values are only
+	// collected while main runs and the collector isn't safe for threads.
+	GarbageCollection bool
+	// Library generates the functions and global declarations of the program without a main
+	// function, so the module can be linked into other programs. The top-level statements may only
+	// declare global variables of constants. The Compiler doesn't prune the functions of a library.
+	Library bool
+	// Budget bounds the number of instructions and basic blocks the Compiler generates.
+	Budget Budget
+	// Passes holds the names of the registered passes the Compiler runs, in order, see RegisterPass.
+	Passes []string
 }
 
 // newScope creates a new empty scope.
 func newScope() Scope {
 	return Scope{
-		Callers:   make(map[string]Caller),
-		Variables: make(map[string]Variable),
-		Arguments: make(map[string]Argument),
+		Callers:   newSymbols[Caller](),
+		Arguments: newSymbols[Argument](),
+	}
+}
+
+// newGlobalScope creates a new empty global scope.
+func newGlobalScope() GlobalScope {
+	return GlobalScope{
+		Callers:   newSymbols[Caller](),
+		Variables: make(map[*Symbol]Variable),
+		Globals:   newSymbols[Global](),
+		Structs:   newSymbols[Struct](),
+		Embeds:    newSymbols[Variable](),
+		Generics:  newSymbols[*FunctionNode](),
 	}
 }
 
 // globalScope is a package-level variable holding the global scope for the LLVM module.
+// It is reset whenever a new module is generated.
 var globalScope GlobalScope
 
 // init initializes the global scope.
 func init() {
-	globalScope = GlobalScope{
-		Callers:   make(map[string]Caller),
-		Variables: make(map[string]Variable),
-		Globals:   make(map[string]Global),
-	}
+	globalScope = newGlobalScope()
 }
 
 // printfIndentifier is a constant string representing the printf function identifier.
@@ -124,350 +238,2320 @@ const (
 	printfIndentifier = "printf"
 )
 
+// Constants for the identifiers of the builtin functions working on arrays and slices.
+const (
+	lenIdentifier    = "len"
+	capIdentifier    = "cap"
+	appendIdentifier = "append"
+)
+
+// Constants for the identifiers of the builtin functions managing heap memory.
+const (
+	newIdentifier  = "new"
+	freeIdentifier = "free"
+)
+
+// Constants for the identifier of option types and the builtin functions creating and unwrapping
+// options. unwrap_or unwraps results as well.
+const (
+	optionIdentifier   = "option"
+	someIdentifier     = "some"
+	unwrapOrIdentifier = "unwrap_or"
+)
+
+// Constants for the identifier of result types and the builtin functions creating results.
+const (
+	resultIdentifier = "result"
+	okIdentifier     = "ok"
+	errIdentifier    = "err"
+)
+
+// runtimePrefix is the namespace reserved for the globals and helpers generated by the compiler.
+// User identifiers must not start with it, so generated symbols never collide with user symbols,
+// neither in gusty code nor in C code linked with it.
+const runtimePrefix = "__gusty_"
+
+// formatStringIdentifier is a constant string representing the identifier of the global holding
+// the printf format string for values printed as int.
+const formatStringIdentifier = runtimePrefix + "format_string"
+
+// stringLiteralIdentifier is the name of the constant globals holding the bytes of string literals.
+const stringLiteralIdentifier = runtimePrefix + "string"
+
+// printfFormat describes a printf format string global used to print values of a data type.
+type printfFormat struct {
+	Name   string // The name of the global holding the format string.
+	Format string // The printf format string.
+}
+
+// printfFormats maps data types which need their own format string to the format.
+// Every data type not listed here is printed as an int using the format string named formatStringIdentifier.
+var printfFormats = map[dataType]printfFormat{
+	Integer64Type: {Name: formatStringIdentifier + "_i64", Format: "%ld\n"},
+	Float32Type:   {Name: formatStringIdentifier + "_f64", Format: "%f\n"},
+	Float64Type:   {Name: formatStringIdentifier + "_f64", Format: "%f\n"},
+	StringType:    {Name: formatStringIdentifier + "_string", Format: "%s\n"},
+}
+
+// GenerateLLVMIR generates the LLVM IR for the given nodes with the default options
+// and returns it in its textual form.
 func GenerateLLVMIR(nodes []Node) (string, error) {
+	module, err := generateModule(nodes, Options{})
+	if err != nil {
+		return "", err
+	}
+	defer disposeModule(module)
+
+	return module.String(), nil
+}
+
+// disposeModule disposes a module created by generateModule together with its LLVM context.
+func disposeModule(module llvm.Module) {
+	context := module.Context()
+	module.Dispose()
+	context.Dispose()
+}
+
+// generateModule generates the LLVM module for the given nodes and verifies it.
+// Top-level statements become the body of a synthesized main function. Invalid nodes, e.g. built
+// in Go code, and duplicate declarations are rejected before generating any code.
+func generateModule(nodes []Node, options Options) (llvm.Module, error) {
+	resolution, err := Resolve(nodes)
+	if err != nil {
+		return llvm.Module{}, err
+	}
+	if len(resolution.Duplicates) > 0 {
+		return llvm.Module{}, duplicateDiagnostic(resolution.Duplicates[0])
+	}
+
+	globalScope = newGlobalScope()
+	globalScope.Resolution = resolution
+	globalScope.Options = options
+
+	// Every module gets its own LLVM context, so types named while generating it, e.g. structs,
+	// don't clash with the types of previously generated modules
+	globalScope.Context = llvm.NewContext()
+	module := globalScope.Context.NewModule("main")
 
+	if err := generateProgram(module, nodes); err != nil {
+		disposeModule(module)
+		return llvm.Module{}, err
+	}
+
+	// Verify the module
+	if err := llvm.VerifyModule(module, llvm.ReturnStatusAction); err != nil {
+		disposeModule(module)
+		return llvm.Module{}, err
+	}
+
+	return module, nil
+}
+
+// generateProgram generates the declarations, functions and the synthesized main function of
+// the program into the module. Struct types are registered before anything else, so they can
+// be used before their declaration.
+func generateProgram(module llvm.Module, nodes []Node) error {
 	mainFunctionScope := newScope()
 
-	module := llvm.NewModule("main")
+	if err := generateStructs(module, nodes); err != nil {
+		return err
+	}
+	if err := generateEmbeds(module, nodes); err != nil {
+		return err
+	}
+	if err := generateThreadLocals(module, nodes); err != nil {
+		return err
+	}
 
-	mainType := llvm.FunctionType(llvm.Int32Type(), []llvm.Type{}, false)
-	mainFunc := llvm.AddFunction(module, "main", mainType)
+	// main only takes argc and argv if the program reads its command-line arguments. A library has
+	// no main function, the top-level declarations are generated into a function removed afterwards.
+	mainName := mainIdentifier
+	mainParameters := []llvm.Type{}
+	arguments := usesArguments(nodes) && !globalScope.Options.Library
+	if globalScope.Options.Library {
+		mainName = libraryMainIdentifier
+	} else if arguments {
+		mainParameters = []llvm.Type{globalScope.Context.Int32Type(), llvm.PointerType(globalScope.Context.Int8Type(), 0)}
+	}
+	mainType := llvm.FunctionType(globalScope.Context.Int32Type(), mainParameters, false)
+	mainFunc := llvm.AddFunction(module, mainName, mainType)
 
-	printfType := llvm.FunctionType(llvm.Int32Type(), []llvm.Type{llvm.PointerType(llvm.Int32Type(), 0)}, true)
+	printfType := llvm.FunctionType(globalScope.Context.Int32Type(), []llvm.Type{llvm.PointerType(globalScope.Context.Int32Type(), 0)}, true)
 	printf := llvm.AddFunction(module, printfIndentifier, printfType)
-	globalScope.Callers[printfIndentifier] = Caller{
-		Value: &printf,
-		Type:  &printfType,
-	}
+	globalScope.Callers.Set(printfIndentifier, Caller{
+		Value:      &printf,
+		Type:       &printfType,
+		ReturnType: Integer32Type,
+	})
 
 	// Create format string
-	formatString := llvm.ConstString("%d\n", false)
-	formatGlobal := llvm.AddGlobal(module, formatString.Type(), "format_string")
+	formatString := globalScope.Context.ConstString("%d\n", true)
+	formatGlobal := llvm.AddGlobal(module, formatString.Type(), formatStringIdentifier)
 	formatGlobal.SetInitializer(formatString)
 	formatGlobal.SetGlobalConstant(true)
-	globalScope.Globals["format_string"] = Global{
+	globalScope.Globals.Set(formatStringIdentifier, Global{
 		Value: &formatGlobal,
-	}
+	})
 
-	entry := llvm.AddBasicBlock(mainFunc, "entry")
-	mainBuilder := llvm.NewBuilder()
+	entry := globalScope.Context.AddBasicBlock(mainFunc, "entry")
+	mainBuilder := globalScope.Context.NewBuilder()
 	defer mainBuilder.Dispose()
 	mainBuilder.SetInsertPointAtEnd(entry)
+	if arguments {
+		generateMainArguments(module, mainFunc, mainBuilder)
+	}
+	if globalScope.Options.GarbageCollection && !globalScope.Options.Library {
+		generateGCStackBottom(mainBuilder)
+	}
 
 	for i := 0; i < len(nodes); i++ {
 		node := nodes[i]
-		switch n := node.(type) {
-		case *ForNode:
-			err := generateFor(&mainFunctionScope, mainFunc, mainBuilder, n)
-			if err != nil {
-				return "", err
+		if functionNode, ok := node.(*FunctionNode); ok {
+			if functionNode.Name == mainIdentifier && !globalScope.Options.Library {
+				if err := generateUserMain(module, functionNode); err != nil {
+					return err
+				}
+				continue
 			}
-		case *AddOperationNode:
-			err := generateAdd(&mainFunctionScope, mainBuilder, n)
-			if err != nil {
-				return "", err
+			if len(functionNode.TypeParameters) > 0 {
+				globalScope.Generics.Set(functionNode.Name, functionNode)
+				continue
 			}
-		case *CallerNode:
-			err := generateCaller(&mainFunctionScope, mainBuilder, n)
+			err := generateFunction(module, functionNode)
 			if err != nil {
-				return "", err
+				return err
+			}
+			continue
+		}
+		switch n := node.(type) {
+		case *StructNode, *EmbedNode, *TypeAliasNode:
+			continue
+		case *ExternNode:
+			if err := generateExtern(module, n); err != nil {
+				return err
 			}
+			continue
+		case *InlineIRNode:
+			globalScope.InlineIR = append(globalScope.InlineIR, n.IR)
+			continue
+		case *ImportNode:
+			return newError(MessageUnresolvedImport, n.Path)
 		case *LetNode:
-			err := generateLet(&mainFunctionScope, mainBuilder, n)
-			if err != nil {
-				return "", err
+			if n.ThreadLocal {
+				continue
 			}
-		case *WhileNode:
-			// Skipping LLVM IR generation for while node for simplicity
-			// todo to be implemented
-		case *FunctionNode:
-			// Create function prototype
-			var llvmParameters []llvm.Type
-			for _, parameter := range n.Parameters {
-				if parameter.Type == Integer32Type {
-					llvmParameters = append(llvmParameters, llvm.Int32Type())
-				}
+			if err := generateGlobalLet(module, &mainFunctionScope, mainBuilder, n); err != nil {
+				return err
 			}
+			continue
+		}
 
-			functionType := llvm.FunctionType(llvm.VoidType(), llvmParameters, false)
-			function := llvm.AddFunction(module, n.Name, functionType)
-			function.SetFunctionCallConv(llvm.CCallConv)
+		err := generateStatement(&mainFunctionScope, mainFunc, mainBuilder, node)
+		if err != nil {
+			return err
+		}
+	}
 
-			currentFunctionScope := newScope()
+	if globalScope.Options.Library {
+		if err := removeLibraryMain(mainFunc); err != nil {
+			return err
+		}
+	} else {
+		generateMainReturn(mainBuilder)
+	}
+	generateGCMarkGlobals(module)
 
-			var i int
-			for _, parameter := range n.Parameters {
-				llvmParameter := function.Param(i)
-				currentFunctionScope.Arguments[parameter.Identifier] = Argument{
-					Value: &llvmParameter,
-				}
-				i++
-			}
+	return linkInlineIR(module)
+}
 
-			currentFunctionBuilder := llvm.NewBuilder()
-			defer currentFunctionBuilder.Dispose()
-
-			// Create a new basic block and set the builder's insert point
-			entry := llvm.AddBasicBlock(function, "entry")
-			currentFunctionBuilder.SetInsertPointAtEnd(entry)
-
-			// Generate LLVM IR for the function body
-			for _, bodyNode := range n.Body {
-				switch bodyNode := bodyNode.(type) {
-				case *LetNode:
-					err := generateLet(&currentFunctionScope, currentFunctionBuilder, bodyNode)
-					if err != nil {
-						return "", err
-					}
-				case *CallerNode:
-					err := generateCaller(&currentFunctionScope, currentFunctionBuilder, bodyNode)
-					if err != nil {
-						return "", err
-					}
-				}
-			}
+// generateEmbeds is a function that creates a constant global array holding the bytes of the file
+// of every embed declaration among the nodes and registers it in the global scope. The files are
+// read from the file system of the options of the global scope.
+//
+// module:  The LLVM module the global arrays are added to.
+// nodes:   The abstract syntax tree (AST) nodes of the program.
+//
+// Returns an error if an identifier is embedded twice or a file can't be read.
+func generateEmbeds(module llvm.Module, nodes []Node) error {
+	files := globalScope.Options.EmbedFS
+	if files == nil {
+		files = os.DirFS(".")
+	}
 
-			mainFunctionScope.Callers[n.Name] = Caller{
-				Value: &function,
-				Type:  &functionType,
-			}
+	for _, node := range nodes {
+		embedNode, ok := node.(*EmbedNode)
+		if !ok {
+			continue
+		}
+		if _, ok := globalScope.Embeds.Get(embedNode.Identifier); ok {
+			return newError(MessageDuplicateEmbed, embedNode.Identifier)
+		}
 
-			// Return void
-			currentFunctionBuilder.CreateRetVoid()
+		data, err := fs.ReadFile(files, embedNode.Path)
+		if err != nil {
+			return newError(MessageEmbedFile, embedNode.Path, err)
 		}
+
+		content := globalScope.Context.ConstString(string(data), false)
+		global := llvm.AddGlobal(module, content.Type(), runtimePrefix+"embed_"+embedNode.Identifier)
+		global.SetInitializer(content)
+		global.SetGlobalConstant(true)
+		global.SetLinkage(llvm.PrivateLinkage)
+		globalScope.Embeds.Set(embedNode.Identifier, Variable{
+			Value: &global,
+			Type:  arrayOf(Integer8Type, len(data)),
+		})
 	}
+	return nil
+}
 
-	mainBuilder.CreateRet(llvm.ConstInt(llvm.Int32Type(), 0, false))
+// generateThreadLocals is a function that creates a thread-local global variable for every
+// threadlocal let declaration among the nodes and registers it in the global scope, so it's
+// visible in all functions. Every thread starts with its own copy of the variable holding the
+// value of the declaration, which therefore has to be a constant expression.
+//
+// module:  The LLVM module the global variables are added to.
+// nodes:   The abstract syntax tree (AST) nodes of the program.
+//
+// Returns an error if the value of a declaration isn't a constant of its type.
+func generateThreadLocals(module llvm.Module, nodes []Node) error {
+	scope := newScope()
+	builder := globalScope.Context.NewBuilder()
+	defer builder.Dispose()
+
+	for _, node := range nodes {
+		letNode, ok := node.(*LetNode)
+		if !ok || !letNode.ThreadLocal {
+			continue
+		}
 
-	// Verify the module
-	if err := llvm.VerifyModule(module, llvm.ReturnStatusAction); err != nil {
-		return "", err
+		if !isConstantExpression(letNode.Value, letNode.Type) {
+			return newError(MessageThreadLocalInitializer, letNode.Identifier)
+		}
+		value, letType, err := generateLetValue(&scope, builder, letNode)
+		if err != nil {
+			return err
+		}
+
+		global := llvm.AddGlobal(module, llvmType(letType), "main."+letNode.Identifier)
+		global.SetInitializer(value)
+		global.SetLinkage(llvm.InternalLinkage)
+		global.SetAlignment(dataTypeAlignment(letType))
+		global.SetThreadLocal(true)
+		declareVariable(letNode, letVariable(letNode, &global, letType))
+	}
+	return nil
+}
+
+// isConstantExpression reports whether the value only consists of literals, so it can be evaluated
+// at compile time as a value of the data type without generating any instructions. Array literals
+// of slice types aren't constant, unless they are empty, since their elements are stored into a
+// heap-allocated backing array.
+func isConstantExpression(value Expr, t dataType) bool {
+	switch v := value.(type) {
+	case *IntLiteralNode, *FloatLiteralNode, *BoolLiteralNode, *NoneNode:
+		return true
+	case *AddOperationNode:
+		return isConstantExpression(v.LeftValue, t) && isConstantExpression(v.RightValue, t)
+	case *ShiftOperationNode:
+		return isConstantExpression(v.LeftValue, t) && isConstantExpression(v.RightValue, t)
+	case *CastNode:
+		return isConstantExpression(v.Value, 0)
+	case *ArrayLiteralNode:
+		if _, ok := t.slice(); ok {
+			return len(v.Elements) == 0
+		}
+		var element dataType
+		if arrayType, ok := t.array(); ok {
+			element = arrayType.Element
+		}
+		for _, e := range v.Elements {
+			if !isConstantExpression(e, element) {
+				return false
+			}
+		}
+		return true
+	case *StructLiteralNode:
+		structure, _ := globalScope.Structs.Get(v.Name)
+		for _, fieldValue := range v.Fields {
+			var fieldType dataType
+			if _, field, ok := structure.field(fieldValue.Identifier); ok {
+				fieldType = field.Type
+			}
+			if !isConstantExpression(fieldValue.Value, fieldType) {
+				return false
+			}
+		}
+		return true
 	}
+	return false
+}
 
-	return module.String(), nil
+// declareVariable declares the variable of the node declaring it, a *LetNode or the
+// *ShortVariableAssigmentNode of a for loop, so the uses the resolution binds to it find it.
+func declareVariable(node Node, variable Variable) {
+	globalScope.Variables[globalScope.Resolution.Declarations[node]] = variable
 }
 
-// generateCaller takes a scope, a functionBuilder builder, and a callerNode,
-// and generates the LLVM IR for calling the function represented by the callerNode.
-// It returns an error if any issues are encountered.
+// lookupSymbol returns the symbol the resolution binds the node using a name to, or nil if the
+// name isn't declared, see Resolve.
+func lookupSymbol(node Node) *Symbol {
+	return globalScope.Resolution.Bindings[node]
+}
+
+// lookupVariable returns the variable the node using a name binds to and whether the name is the
+// name of a variable.
+func lookupVariable(node Node) (Variable, bool) {
+	symbol := lookupSymbol(node)
+	if symbol == nil || symbol.Kind != SymbolVariable {
+		return Variable{}, false
+	}
+	variable, ok := globalScope.Variables[symbol]
+	return variable, ok
+}
+
+// generateStructs is a function that registers the struct declarations among the nodes in the global
+// scope and creates a named LLVM struct type for each of them. All structs are registered before
+// their fields are resolved, so fields can refer to structs declared later.
 //
-// scope:            A pointer to the current scope.
-// functionBuilder:  The LLVM builder associated with the current function.
-// callerNode:          The abstract syntax tree (AST) node representing the caller statement.
-func generateCaller(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) error {
-	if callerNode.isParameterOperation {
-		err := generateAdd(scope, functionBuilder, callerNode.AddOperationNode)
-		if err != nil {
-			return err
+// module:  The LLVM module, whose data layout determines the layout of packed and aligned structs.
+// nodes:   The abstract syntax tree (AST) nodes of the program.
+//
+// Returns an error if a struct is declared twice, has duplicate or invalid fields or contains itself.
+func generateStructs(module llvm.Module, nodes []Node) error {
+	var structNodes []*StructNode
+	for _, node := range nodes {
+		structNode, ok := node.(*StructNode)
+		if !ok {
+			continue
+		}
+		if _, ok := globalScope.Structs.Get(structNode.Name); ok {
+			return newError(MessageDuplicateStruct, structNode.Name)
 		}
+		globalScope.Structs.Set(structNode.Name, Struct{
+			Type:   globalScope.Context.StructCreateNamed(structNode.Name),
+			Fields: structNode.Fields,
+		})
+		structNodes = append(structNodes, structNode)
 	}
 
-	// Special case for handling printf calls
-	if callerNode.FunctionName == printfIndentifier {
-		// Load the value of the parameter and create a GEP for the format string
-		format := functionBuilder.CreateInBoundsGEP(globalScope.Globals["format_string"].Value.Type(), *globalScope.Globals["format_string"].Value, []llvm.Value{llvm.ConstInt(llvm.Int32Type(), 0, false), llvm.ConstInt(llvm.Int32Type(), 0, false)}, "format")
-
-		if callerNode.isParameterOperation {
-			// Create the call instruction for printf with the format string and value as arguments
-			value := functionBuilder.CreateLoad(scope.PreviousVariable.Value.Type(), *scope.PreviousVariable.Value, "")
-			functionBuilder.CreateCall(*globalScope.Callers[printfIndentifier].Type, *globalScope.Callers[printfIndentifier].Value, []llvm.Value{format, value}, "")
-			scope.PreviousVariable.Value = nil
-		} else if variable, ok := scope.Variables[callerNode.Parameters[0].Identifier]; ok {
-			value := functionBuilder.CreateLoad(variable.Value.Type(), *variable.Value, callerNode.Parameters[0].Identifier+"Value")
-			// Create the call instruction for printf with the format string and value as arguments
-			functionBuilder.CreateCall(*globalScope.Callers[printfIndentifier].Type, *globalScope.Callers[printfIndentifier].Value, []llvm.Value{format, value}, "")
-		} else if argument, ok := scope.Arguments[callerNode.Parameters[0].Identifier]; ok {
-			// Create the call instruction for printf with the format string and value as arguments
-			functionBuilder.CreateCall(*globalScope.Callers[printfIndentifier].Type, *globalScope.Callers[printfIndentifier].Value, []llvm.Value{format, *argument.Value}, "")
+	for _, structNode := range structNodes {
+		structure, _ := globalScope.Structs.Get(structNode.Name)
+
+		for i, field := range structNode.Fields {
+			if index, _, _ := structure.field(field.Identifier); index != i {
+				return newError(MessageDuplicateField, field.Identifier, structNode.Name)
+			}
+			if err := validateType(field.Type); err != nil {
+				return newError(MessageInvalidFieldType, field.Identifier, structNode.Name, err)
+			}
+			if containsStruct(field.Type, structNode.Name, make(map[string]bool)) {
+				return newError(MessageRecursiveStruct, structNode.Name)
+			}
 		}
+	}
 
-		return nil
+	targetData := llvm.NewTargetData(module.DataLayout())
+	defer targetData.Dispose()
+	laidOut := make(map[string]bool)
+	for _, structNode := range structNodes {
+		layoutStruct(targetData, structNode, structNodes, laidOut)
 	}
 
-	// Retrieve the caller from the global scope using the function name
-	caller, ok := scope.Callers[callerNode.FunctionName]
-	// If the caller is not found, return an error
-	if !ok {
-		return fmt.Errorf("caller not found in scope: %s", callerNode.FunctionName)
+	return nil
+}
+
+// layoutStruct is a function that sets the body of the LLVM type of the declared struct, after the
+// bodies of the structs its fields contain. Structs without attributes are laid out by LLVM.
+// Packed structs and structs with aligned fields are laid out explicitly: every field is placed
+// at the next offset which is a multiple of its alignment, which is 1 in packed structs unless
+// it is declared with @align, and the padding before fields and at the end of the struct is
+// inserted as byte arrays into a packed LLVM struct type.
+//
+// targetData:   The layout of the target, which determines the sizes of the fields.
+// structNode:   The abstract syntax tree (AST) node of the struct.
+// structNodes:  The nodes of all declared structs.
+// laidOut:      The names of the structs whose bodies have already been set.
+func layoutStruct(targetData llvm.TargetData, structNode *StructNode, structNodes []*StructNode, laidOut map[string]bool) {
+	if laidOut[structNode.Name] {
+		return
 	}
+	laidOut[structNode.Name] = true
 
-	// If the caller's Value is nil, return an error
-	if caller.Value == nil {
-		return fmt.Errorf("nil function value for caller: %s", callerNode.FunctionName)
+	explicit := structNode.Packed
+	for _, field := range structNode.Fields {
+		for _, other := range structNodes {
+			if containsStruct(field.Type, other.Name, make(map[string]bool)) {
+				layoutStruct(targetData, other, structNodes, laidOut)
+			}
+		}
+		if field.Alignment != 0 {
+			explicit = true
+		}
 	}
 
-	// If the caller's Type is nil, return an error
-	if caller.Type == nil {
-		return fmt.Errorf("nil function type for caller: %s", callerNode.FunctionName)
+	structure, _ := globalScope.Structs.Get(structNode.Name)
+	if !explicit {
+		var fieldTypes []llvm.Type
+		for _, field := range structNode.Fields {
+			fieldTypes = append(fieldTypes, llvmType(field.Type))
+		}
+		structure.Type.StructSetBody(fieldTypes, false)
+		return
 	}
 
-	// Dereference the caller's Type and Value pointers
-	callerType := *caller.Type
-	callerValue := *caller.Value
+	padding := func(elements []llvm.Type, size uint64) []llvm.Type {
+		if size == 0 {
+			return elements
+		}
+		return append(elements, llvm.ArrayType(globalScope.Context.Int8Type(), int(size)))
+	}
 
-	var llvmParameterValues []llvm.Value
-	for _, parameter := range callerNode.Parameters {
-		if parameter.Type == Integer32Type {
-			llvmParameterValues = append(llvmParameterValues, llvm.ConstInt(llvm.Int32Type(), uint64(parameter.Value.(int32)), true))
+	var elements []llvm.Type
+	var offset uint64
+	structAlignment := 1
+	structure.Elements = make([]int, len(structNode.Fields))
+	structure.Alignments = make([]int, len(structNode.Fields))
+	for i, field := range structNode.Fields {
+		alignment := 1
+		if !structNode.Packed {
+			alignment = dataTypeAlignment(field.Type)
 		}
+		if field.Alignment > alignment {
+			alignment = field.Alignment
+		}
+		if alignment > structAlignment {
+			structAlignment = alignment
+		}
+
+		fieldType := llvmType(field.Type)
+		gap := (uint64(alignment) - offset%uint64(alignment)) % uint64(alignment)
+		elements = padding(elements, gap)
+		structure.Elements[i] = len(elements)
+		structure.Alignments[i] = alignment
+		elements = append(elements, fieldType)
+		offset += gap + targetData.TypeAllocSize(fieldType)
 	}
+	elements = padding(elements, (uint64(structAlignment)-offset%uint64(structAlignment))%uint64(structAlignment))
 
-	// Create the LLVM IR call instruction with the function scope builder,
-	// using the caller's Type, Value, and an empty slice of llvm.Value as arguments.
-	functionBuilder.CreateCall(callerType, callerValue, llvmParameterValues, "")
+	structure.Type.StructSetBody(elements, true)
+	structure.Alignment = structAlignment
+	globalScope.Structs.Set(structNode.Name, structure)
+}
 
-	// If no issues were encountered, return nil
-	return nil
+// containsStruct reports whether values of the data type contain a value of the named struct,
+// either directly, as array element or as field of another struct. Slices and pointers refer to
+// their elements and don't contain them, so a struct may hold a pointer to itself.
+func containsStruct(t dataType, name string, visited map[string]bool) bool {
+	if arrayType, ok := t.array(); ok {
+		return containsStruct(arrayType.Element, name, visited)
+	}
+	if optionType, ok := t.option(); ok {
+		return containsStruct(optionType.Element, name, visited)
+	}
+	if resultType, ok := t.result(); ok {
+		return containsStruct(resultType.Element, name, visited)
+	}
+	structType, ok := t.structure()
+	if !ok {
+		return false
+	}
+	if structType.Name == name {
+		return true
+	}
+	if visited[structType.Name] {
+		return false
+	}
+	visited[structType.Name] = true
+
+	structure, _ := globalScope.Structs.Get(structType.Name)
+	for _, field := range structure.Fields {
+		if containsStruct(field.Type, name, visited) {
+			return true
+		}
+	}
+	return false
 }
 
-// generateLet is a function that generates LLVM IR code for a "let" statement.
-// The let statement assigns a value to a new local variable in the current scope.
-// This function handles the case where the value is an int32.
-//
-// scope:            A pointer to the current scope.
-// functionBuilder:  The LLVM builder associated with the current function.
-// letNode:          The abstract syntax tree (AST) node representing the let statement.
-//
-// Returns an error if the value type of the letNode is not supported.
-func generateLet(scope *Scope, functionBuilder llvm.Builder, letNode *LetNode) error {
-	// Check if the value is of type int32
-	if intValue, ok := letNode.Value.(int32); ok {
-		// Create an alloca instruction to allocate memory for the new local variable
-		letNodeAlloca := functionBuilder.CreateAlloca(llvm.Int32Type(), letNode.Identifier)
-		// Set the alignment of the allocated memory to 4 bytes
-		letNodeAlloca.SetAlignment(4)
-		// Create a constant int32 LLVM value from the intValue
-		letNodeConstInt := llvm.ConstInt(llvm.Int32Type(), uint64(intValue), true)
-		// Store the constant int32 value in the allocated memory
-		functionBuilder.CreateStore(letNodeConstInt, letNodeAlloca)
-		// Add the new local variable to the current scope
-		scope.Variables[letNode.Identifier] = Variable{
-			Value: &letNodeAlloca,
+// validateType returns an error if the data type refers to a struct which isn't declared in the module.
+func validateType(t dataType) error {
+	if arrayType, ok := t.array(); ok {
+		return validateType(arrayType.Element)
+	}
+	if sliceType, ok := t.slice(); ok {
+		return validateType(sliceType.Element)
+	}
+	if pointerType, ok := t.pointer(); ok {
+		return validateType(pointerType.Element)
+	}
+	if optionType, ok := t.option(); ok {
+		return validateType(optionType.Element)
+	}
+	if resultType, ok := t.result(); ok {
+		return validateType(resultType.Element)
+	}
+	if structType, ok := t.structure(); ok {
+		if _, ok := globalScope.Structs.Get(structType.Name); !ok {
+			return newError(MessageUnknownType, structType.Name)
 		}
-	} else {
-		// Return an error if the value type is not supported
-		return fmt.Errorf("invalid value type for let node: %v", letNode)
 	}
 	return nil
 }
 
-// generateAdd is a function that generates LLVM IR code for an "add" statement.
-// The add statement adds two number values in the current scope.
-// This function handles the case where the value is an int32.
+// generateExtern is a function that generates the LLVM IR declaration of an extern function, a
+// prototype without a body which the linker resolves, and registers it in the global scope like a
+// function definition. Parameters and the result use the C representation of their data types,
+// e.g. a string is passed as a pointer to its null terminated bytes.
 //
-// scope:            A pointer to the current scope.
-// functionBuilder:  The LLVM builder associated with the current function.
-// AddOperationNode: The abstract syntax tree (AST) node representing the let statement.
+// module:      The LLVM module the declaration is added to.
+// externNode:  The abstract syntax tree (AST) node representing the extern declaration.
 //
-// Returns an error if the value type of the AddOperationNode is not supported.
-func generateAdd(scope *Scope, functionBuilder llvm.Builder, addOperationNode *AddOperationNode) error {
-	// Check if the left value is of type int32
-	intLeftValue, ok := addOperationNode.LeftValue.(int32)
-	if !ok {
-		// Return an error if the value type is not supported
-		return fmt.Errorf("invalid value type for add operation node: %v", addOperationNode)
+// Returns an error if a data type is invalid or the module already declares a function of the name.
+func generateExtern(module llvm.Module, externNode *ExternNode) error {
+	if err := validateType(externNode.ReturnType); err != nil {
+		return newError(MessageInvalidReturnType, externNode.Name, err)
+	}
+	if function := module.NamedFunction(externNode.Name); !function.IsNil() {
+		return newError(MessageExternDeclared, externNode.Name)
 	}
 
-	// Check if the right value is of type int32
-	intRightValue, ok := addOperationNode.RightValue.(int32)
-	if !ok {
-		// Return an error if the value type is not supported
-		return fmt.Errorf("invalid value type for operation node: %v", addOperationNode)
+	var llvmParameters []llvm.Type
+	var parameterTypes []dataType
+	for _, parameter := range externNode.Parameters {
+		if err := validateType(parameter.Type); err != nil {
+			return newError(MessageInvalidParameterType, parameter.Identifier, externNode.Name, err)
+		}
+		llvmParameters = append(llvmParameters, llvmType(parameter.Type))
+		parameterTypes = append(parameterTypes, parameter.Type)
 	}
 
-	// Create a constant int32 LLVM value from the left int32 value
-	leftValueConstInt := llvm.ConstInt(llvm.Int32Type(), uint64(intLeftValue), true)
-	// Create a constant int32 LLVM value from the left int32 value
-	rightValueConstInt := llvm.ConstInt(llvm.Int32Type(), uint64(intRightValue), true)
-	// Create an add instruction to add left and right constant int32 values
-	v := functionBuilder.CreateAdd(leftValueConstInt, rightValueConstInt, "")
+	functionType := llvm.FunctionType(llvmType(externNode.ReturnType), llvmParameters, false)
+	function := llvm.AddFunction(module, externNode.Name, functionType)
+	function.SetFunctionCallConv(llvm.CCallConv)
+	if err := addFunctionAttributes(function, externNode.Name, externNode.Attributes); err != nil {
+		return err
+	}
 
-	variableName := GenerateRandomIdentifier()
-	resultAlloca := functionBuilder.CreateAlloca(llvm.Int32Type(), variableName)
-	// Set the alignment of the allocated memory to 4 bytes
-	resultAlloca.SetAlignment(4)
-	// Store the constant int32 value in the allocated memory
-	functionBuilder.CreateStore(v, resultAlloca)
+	globalScope.Callers.Set(externNode.Name, Caller{
+		Value:          &function,
+		Type:           &functionType,
+		ParameterTypes: parameterTypes,
+		ReturnType:     externNode.ReturnType,
+	})
+	return nil
+}
 
-	// Add the new local variable to the current scope
-	scope.PreviousVariable = Variable{
-		Value: &resultAlloca,
-	}
+// llvmFunctionAttributes maps the names of function attributes to the LLVM attributes they add.
+var llvmFunctionAttributes = map[string]string{
+	inlineAttribute:   "alwaysinline",
+	noinlineAttribute: "noinline",
+	noreturnAttribute: "noreturn",
+}
 
+// addFunctionAttributes adds the LLVM attribute of every attribute a function was declared with
+// to the function.
+//
+// Returns an error if the function is declared both @inline and @noinline.
+func addFunctionAttributes(function llvm.Value, name string, attributes []string) error {
+	if hasAttribute(attributes, inlineAttribute) && hasAttribute(attributes, noinlineAttribute) {
+		return newError(MessageConflictingFunctionAttributes, name)
+	}
+	for _, attribute := range attributes {
+		kind := llvm.AttributeKindID(llvmFunctionAttributes[attribute])
+		function.AddFunctionAttr(globalScope.Context.CreateEnumAttribute(kind, 0))
+	}
 	return nil
 }
 
-// generateFor is a function that generates LLVM IR code for a "for" loop in the form of "for i := init; i < limit; i++".
-// The function takes the initial value, limit, and body of the loop and generates the appropriate LLVM IR code.
+// hasAttribute reports whether the attributes of a function contain the attribute with the given name.
+func hasAttribute(attributes []string, name string) bool {
+	for _, attribute := range attributes {
+		if attribute == name {
+			return true
+		}
+	}
+	return false
+}
+
+// generateFunction is a function that generates LLVM IR code for a function definition.
+// The function is registered in the global scope before its body is generated, so it can
+// call itself and be called by every function generated after it.
 //
-// scope:            A pointer to the current scope containing local variables and function calls.
-// function:         The LLVM function value representing the current function.
-// functionBuilder:  The LLVM builder associated with the current function.
-// forNode:          The abstract syntax tree (AST) node representing the for loop.
+// module:        The LLVM module the function is added to.
+// functionNode:  The abstract syntax tree (AST) node representing the function definition.
 //
-// Returns an error if the value type of the loop variables is not supported.
-func generateFor(scope *Scope, function llvm.Value, functionBuilder llvm.Builder, forNode *ForNode) error {
-	// Check if the init value is of type int32
-	initValue, ok := forNode.Init.Value.(int32)
-	if !ok {
-		// Return an error if the value type is not supported
-		return fmt.Errorf("invalid value type for init: %v", forNode.Init.Value)
+// Returns an error if the body can't be generated or doesn't end with a return although
+// the function declares a return type.
+func generateFunction(module llvm.Module, functionNode *FunctionNode) error {
+	if err := validateType(functionNode.ReturnType); err != nil {
+		return newError(MessageInvalidReturnType, functionNode.Name, err)
 	}
 
-	// Define loop variables.
-	// Allocate memory for the loop variable in the current function
-	initAlloca := functionBuilder.CreateAlloca(llvm.Int32Type(), "for_init_"+forNode.Init.Identifier)
-	// Set the alignment of the allocated memory to 4 bytes
-	initAlloca.SetAlignment(4)
-	// Create a constant int32 LLVM value from the init value
-	initConst := llvm.ConstInt(llvm.Int32Type(), uint64(initValue), true)
-	// Store the constant int32 value in the allocated memory
-	functionBuilder.CreateStore(initConst, initAlloca)
+	// Create function prototype
+	var llvmParameters []llvm.Type
+	var parameterTypes []dataType
+	for _, parameter := range functionNode.Parameters {
+		if err := validateType(parameter.Type); err != nil {
+			return newError(MessageInvalidParameterType, parameter.Identifier, functionNode.Name, err)
+		}
+		llvmParameters = append(llvmParameters, llvmType(parameter.Type))
+		parameterTypes = append(parameterTypes, parameter.Type)
+	}
 
-	// Add the new local variable to the current scope
-	scope.Variables[forNode.Init.Identifier] = Variable{
-		Value: &initAlloca,
+	functionType := llvm.FunctionType(llvmType(functionNode.ReturnType), llvmParameters, false)
+	function := llvm.AddFunction(module, functionNode.Name, functionType)
+	function.SetFunctionCallConv(llvm.CCallConv)
+	if err := addFunctionAttributes(function, functionNode.Name, functionNode.Attributes); err != nil {
+		return err
 	}
 
-	// Create basic blocks for the loop and the end of the loop
-	loopBlock := llvm.AddBasicBlock(function, "loop")
-	endBlock := llvm.AddBasicBlock(function, "end")
+	globalScope.Callers.Set(functionNode.Name, Caller{
+		Value:          &function,
+		Type:           &functionType,
+		ParameterTypes: parameterTypes,
+		ReturnType:     functionNode.ReturnType,
+	})
+
+	currentFunctionScope := newScope()
+	currentFunctionScope.Function = functionNode
+
+	for i, parameter := range functionNode.Parameters {
+		llvmParameter := function.Param(i)
+		currentFunctionScope.Arguments.Set(parameter.Identifier, Argument{
+			Value: &llvmParameter,
+			Type:  parameter.Type,
+		})
+	}
 
-	// Branch to loop condition from entry block
-	functionBuilder.CreateBr(loopBlock)
+	currentFunctionBuilder := globalScope.Context.NewBuilder()
+	defer currentFunctionBuilder.Dispose()
 
-	// Set the insertion point to the loop block
-	functionBuilder.SetInsertPointAtEnd(loopBlock)
+	// Create a new basic block and set the builder's insert point
+	entry := globalScope.Context.AddBasicBlock(function, "entry")
+	currentFunctionBuilder.SetInsertPointAtEnd(entry)
 
-	// Generate all instructions in the loop body
-	for i := 0; i < len(forNode.Body); i++ {
-		node := forNode.Body[i]
-		switch n := node.(type) {
-		case *ForNode:
-			// generate nested for loop
-			err := generateFor(scope, function, functionBuilder, n)
-			if err != nil {
-				return err
-			}
-		case *AddOperationNode:
-			// generate add operation
-			err := generateAdd(scope, functionBuilder, n)
+	// Generate LLVM IR for the function body
+	err := generateStatements(&currentFunctionScope, function, currentFunctionBuilder, functionNode.Body)
+	if err != nil {
+		return err
+	}
+
+	// Terminate the last block if the body didn't end with a return
+	last := currentFunctionBuilder.GetInsertBlock()
+	if isTerminated(last) {
+		return nil
+	}
+	if isUnreachable(last) {
+		if last.FirstInstruction().IsNil() {
+			last.EraseFromParent()
+		} else {
+			currentFunctionBuilder.CreateUnreachable()
+		}
+		return nil
+	}
+	// The end of the body of a function which never returns can't be reached
+	if hasAttribute(functionNode.Attributes, noreturnAttribute) {
+		currentFunctionBuilder.CreateUnreachable()
+		return nil
+	}
+	if functionNode.ReturnType != VoidType {
+		return newError(MessageMissingReturn, functionNode.Name)
+	}
+
+	// Return void
+	currentFunctionBuilder.CreateRetVoid()
+
+	return nil
+}
+
+// generateStatements is a function that generates LLVM IR code for a sequence of statements,
+// e.g. the body of a function or a loop.
+//
+// scope:            A pointer to the current scope.
+// function:         The LLVM function value representing the current function.
+// functionBuilder:  The LLVM builder associated with the current function.
+// nodes:            The abstract syntax tree (AST) nodes representing the statements.
+func generateStatements(scope *Scope, function llvm.Value, functionBuilder llvm.Builder, nodes []Node) error {
+	for _, node := range nodes {
+		err := generateStatement(scope, function, functionBuilder, node)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateStatement is a function that generates LLVM IR code for a single statement.
+//
+// scope:            A pointer to the current scope.
+// function:         The LLVM function value representing the current function.
+// functionBuilder:  The LLVM builder associated with the current function.
+// node:             The abstract syntax tree (AST) node representing the statement.
+func generateStatement(scope *Scope, function llvm.Value, functionBuilder llvm.Builder, node Node) error {
+	switch n := node.(type) {
+	case *ForNode:
+		return generateFor(scope, function, functionBuilder, n)
+	case *AddOperationNode:
+		return generateAdd(scope, functionBuilder, n)
+	case *CallerNode:
+		return generateCaller(scope, functionBuilder, n)
+	case *SpawnNode:
+		_, _, err := generateSpawn(scope, functionBuilder, n)
+		return err
+	case *LetNode:
+		if n.ThreadLocal {
+			return newError(MessageNestedThreadLocal, n.Identifier)
+		}
+		return generateLet(scope, functionBuilder, n)
+	case *AssignmentNode:
+		return generateAssignment(scope, functionBuilder, n)
+	case *IndexAssignmentNode:
+		return generateIndexAssignment(scope, functionBuilder, n)
+	case *FieldAssignmentNode:
+		return generateFieldAssignment(scope, functionBuilder, n)
+	case *DereferenceAssignmentNode:
+		return generateDereferenceAssignment(scope, functionBuilder, n)
+	case *ReturnNode:
+		return generateReturn(scope, function, functionBuilder, n)
+	case *WhileNode:
+		// Skipping LLVM IR generation for while node for simplicity
+		// todo to be implemented
+	case *FunctionNode:
+		return newError(MessageNestedFunction, n.Name)
+	case *StructNode:
+		return newError(MessageNestedStruct, n.Name)
+	case *EmbedNode:
+		return newError(MessageNestedEmbed, n.Identifier)
+	case *TypeAliasNode:
+		return newError(MessageNestedTypeAlias, n.Name)
+	case *ImportNode:
+		return newError(MessageNestedImport, n.Path)
+	case *ExternNode:
+		return newError(MessageNestedExtern, n.Name)
+	case *InlineIRNode:
+		return generateInlineIR(scope, functionBuilder, n)
+	}
+	return nil
+}
+
+// generateReturn is a function that generates LLVM IR code for a "return" statement.
+// Statements following the return are generated into a new block without predecessors,
+// which is removed again if it stays empty. A return among the top-level statements returns
+// from the main function, its value is the exit status of the program, an i32, or 0 if it has none.
+//
+// scope:            A pointer to the current scope.
+// function:         The LLVM function value representing the current function.
+// functionBuilder:  The LLVM builder associated with the current function.
+// returnNode:       The abstract syntax tree (AST) node representing the return statement.
+//
+// Returns an error if the value doesn't match the return type.
+func generateReturn(scope *Scope, function llvm.Value, functionBuilder llvm.Builder, returnNode *ReturnNode) error {
+	if scope.Function == nil {
+		status := llvm.ConstInt(globalScope.Context.Int32Type(), 0, false)
+		if returnNode.Value != nil {
+			var err error
+			status, err = generateTypedValue(scope, functionBuilder, returnNode.Value, Integer32Type)
 			if err != nil {
-				return err
+				return newError(MessageInvalidExitStatus, err)
 			}
-		case *CallerNode:
-			// generate function call
-			err := generateCaller(scope, functionBuilder, n)
-			if err != nil {
-				return err
+		}
+		functionBuilder.CreateRet(status)
+	} else if returnType := scope.Function.ReturnType; returnNode.Value == nil {
+		if returnType != VoidType {
+			return newError(MessageMissingReturnValue, scope.Function.Name)
+		}
+		functionBuilder.CreateRetVoid()
+	} else {
+		if returnType == VoidType {
+			return newError(MessageUnexpectedReturnValue, scope.Function.Name)
+		}
+		value, err := generateTypedValue(scope, functionBuilder, returnNode.Value, returnType)
+		if err != nil {
+			return newError(MessageInvalidReturnValue, scope.Function.Name, err)
+		}
+		functionBuilder.CreateRet(value)
+	}
+
+	afterReturn := globalScope.Context.AddBasicBlock(function, "after_return")
+	functionBuilder.SetInsertPointAtEnd(afterReturn)
+
+	return nil
+}
+
+// isTerminated reports whether the basic block ends with a terminator instruction.
+func isTerminated(block llvm.BasicBlock) bool {
+	last := block.LastInstruction()
+	if last.IsNil() {
+		return false
+	}
+	switch last.InstructionOpcode() {
+	case llvm.Ret, llvm.Br, llvm.Switch, llvm.IndirectBr, llvm.Unreachable:
+		return true
+	}
+	return false
+}
+
+// isUnreachable reports whether the basic block can't be reached, because it isn't the
+// entry block of its function and no instruction branches to it.
+func isUnreachable(block llvm.BasicBlock) bool {
+	return block != block.Parent().EntryBasicBlock() && block.AsValue().FirstUse().IsNil()
+}
+
+// generateCaller takes a scope, a functionBuilder builder, and a callerNode,
+// and generates the LLVM IR for calling the function represented by the callerNode.
+// It returns an error if any issues are encountered.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:          The abstract syntax tree (AST) node representing the caller statement.
+func generateCaller(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) error {
+	_, _, err := generateCall(scope, functionBuilder, callerNode)
+	return err
+}
+
+// generateCall generates the LLVM IR for calling the function represented by the callerNode
+// and returns the value returned by the call together with its data type.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+func generateCall(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	// Special case for handling printf calls
+	if callerNode.FunctionName == printfIndentifier {
+		if len(callerNode.Args) != 1 {
+			return llvm.Value{}, 0, newError(MessageExpectedOneParameter, printfIndentifier, len(callerNode.Args))
+		}
+
+		value, valueType, err := generateValue(scope, functionBuilder, callerNode.Args[0])
+		if err != nil {
+			return llvm.Value{}, 0, err
+		}
+
+		if valueType.composite() != nil {
+			return llvm.Value{}, 0, newError(MessagePrintType, valueType)
+		}
+
+		// Promote the value the same way C promotes variadic arguments and pick the matching format string
+		value, format := generatePrintfArgument(functionBuilder, value, valueType)
+
+		// Create the call instruction for printf with the format string and value as arguments
+		printf, _ := globalScope.Callers.Get(printfIndentifier)
+		call := functionBuilder.CreateCall(*printf.Type, *printf.Value, []llvm.Value{format, value}, "")
+
+		return call, Integer32Type, nil
+	}
+
+	switch callerNode.FunctionName {
+	case printIdentifier, printlnIdentifier:
+		return generatePrint(scope, functionBuilder, callerNode)
+	case lenIdentifier, capIdentifier:
+		return generateLength(scope, functionBuilder, callerNode)
+	case readIntIdentifier, readLineIdentifier:
+		return generateRead(functionBuilder, callerNode)
+	case argsCountIdentifier, argIdentifier:
+		return generateArguments(scope, functionBuilder, callerNode)
+	case appendIdentifier:
+		return generateAppend(scope, functionBuilder, callerNode)
+	case freeIdentifier:
+		return generateFree(scope, functionBuilder, callerNode)
+	case someIdentifier:
+		return generateSome(scope, functionBuilder, callerNode, nil)
+	case unwrapOrIdentifier:
+		return generateUnwrapOr(scope, functionBuilder, callerNode)
+	case okIdentifier:
+		return generateOk(scope, functionBuilder, callerNode, nil)
+	case errIdentifier:
+		return llvm.Value{}, 0, newError(MessageUntypedErr)
+	}
+	if generic, ok := globalScope.Generics.Get(callerNode.FunctionName); ok {
+		return generateGenericCall(scope, functionBuilder, generic, callerNode)
+	}
+	if isMathBuiltin(scope, callerNode.FunctionName) {
+		return generateMath(scope, functionBuilder, callerNode, nil)
+	}
+	if isExitBuiltin(scope, callerNode.FunctionName) {
+		return generateExit(scope, functionBuilder, callerNode)
+	}
+	if isJoinBuiltin(scope, callerNode.FunctionName) {
+		return generateJoin(scope, functionBuilder, callerNode)
+	}
+	if isFormatBuiltin(scope, callerNode.FunctionName) {
+		return generateFormat(scope, functionBuilder, callerNode)
+	}
+	if isSyncBuiltin(scope, callerNode.FunctionName) {
+		return generateSync(scope, functionBuilder, callerNode)
+	}
+
+	// Retrieve the caller from the current scope, falling back to the global scope
+	caller, ok := scope.Callers.Get(callerNode.FunctionName)
+	if !ok {
+		caller, ok = globalScope.Callers.Get(callerNode.FunctionName)
+	}
+	// If the caller is not found, return an error
+	if !ok {
+		return llvm.Value{}, 0, newError(MessageCallerNotFound, callerNode.FunctionName)
+	}
+
+	// If the caller's Value is nil, return an error
+	if caller.Value == nil {
+		return llvm.Value{}, 0, newError(MessageNilFunctionValue, callerNode.FunctionName)
+	}
+
+	// If the caller's Type is nil, return an error
+	if caller.Type == nil {
+		return llvm.Value{}, 0, newError(MessageNilFunctionType, callerNode.FunctionName)
+	}
+
+	// Dereference the caller's Type and Value pointers
+	callerType := *caller.Type
+	callerValue := *caller.Value
+
+	if len(caller.ParameterTypes) != len(callerNode.Args) {
+		return llvm.Value{}, 0, newError(MessageExpectedParameters, len(caller.ParameterTypes), callerNode.FunctionName, len(callerNode.Args))
+	}
+
+	var llvmParameterValues []llvm.Value
+	for i, arg := range callerNode.Args {
+		value, err := generateTypedValue(scope, functionBuilder, arg, caller.ParameterTypes[i])
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, i+1, callerNode.FunctionName, err)
+		}
+		llvmParameterValues = append(llvmParameterValues, value)
+	}
+
+	// Create the LLVM IR call instruction with the function scope builder,
+	// using the caller's Type, Value, and the generated parameter values as arguments.
+	call := functionBuilder.CreateCall(callerType, callerValue, llvmParameterValues, "")
+
+	// If no issues were encountered, return the call
+	return call, caller.ReturnType, nil
+}
+
+// generateLet is a function that generates LLVM IR code for a "let" statement.
+// The let statement assigns a value to a new local variable in the current scope.
+// Literal values take the type of the let node, every other value is generated as
+// an expression whose type becomes the type of the variable unless a type was declared.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// letNode:          The abstract syntax tree (AST) node representing the let statement.
+//
+// Returns an error if the value of the letNode can't be used as a value of its type.
+func generateLet(scope *Scope, functionBuilder llvm.Builder, letNode *LetNode) error {
+	letNodeValue, letType, err := generateLetValue(scope, functionBuilder, letNode)
+	if err != nil {
+		return err
+	}
+	generateLocalVariable(scope, functionBuilder, letNode, letNodeValue, letType)
+	return nil
+}
+
+// generateGlobalLet is a function that generates LLVM IR code for a top-level "let" statement.
+// If the value is a constant expression, e.g. a literal, an array or struct literal of constants, an
+// addition of constants or a concatenation of string literals, it is evaluated at compile time and becomes the initializer of a global
+// variable, which needs no code at runtime. Every other value is stored into a local variable of
+// the main function like in generateLet.
+//
+// module:           The LLVM module the global variable is added to.
+// scope:            A pointer to the scope of the main function.
+// functionBuilder:  The LLVM builder associated with the main function.
+// letNode:          The abstract syntax tree (AST) node representing the let statement.
+//
+// Returns an error if the value of the letNode can't be used as a value of its type.
+func generateGlobalLet(module llvm.Module, scope *Scope, functionBuilder llvm.Builder, letNode *LetNode) error {
+	letNodeValue, letType, err := generateLetValue(scope, functionBuilder, letNode)
+	if err != nil {
+		return err
+	}
+	if !letNodeValue.IsConstant() {
+		generateLocalVariable(scope, functionBuilder, letNode, letNodeValue, letType)
+		return nil
+	}
+
+	global := llvm.AddGlobal(module, llvmType(letType), "main."+letNode.Identifier)
+	global.SetInitializer(letNodeValue)
+	global.SetLinkage(llvm.InternalLinkage)
+	global.SetAlignment(dataTypeAlignment(letType))
+	declareVariable(letNode, letVariable(letNode, &global, letType))
+	return nil
+}
+
+// generateLetValue is a function that generates LLVM IR code producing the value of a "let" statement.
+// Literal values take the type of the let node, every other value is generated as an expression
+// whose type becomes the type of the variable unless a type was declared.
+//
+// Returns the value and the data type of the variable.
+func generateLetValue(scope *Scope, functionBuilder llvm.Builder, letNode *LetNode) (llvm.Value, dataType, error) {
+	if err := validateType(letNode.Type); letNode.HasType && err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidLetType, letNode.Identifier, err)
+	}
+
+	letType := letNode.Type
+	var letNodeValue llvm.Value
+	var err error
+	if _, ok := literalDataType(letNode.Value); ok || letNode.HasType {
+		// Create an LLVM value of the declared type
+		letNodeValue, err = generateTypedValue(scope, functionBuilder, letNode.Value, letType)
+	} else {
+		// Infer the type of the variable from the value
+		letNodeValue, letType, err = generateValue(scope, functionBuilder, letNode.Value)
+		if err == nil && letType == VoidType {
+			err = newError(MessageVoidCallAsValue)
+		}
+	}
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidLetValue, letNode.Identifier, err)
+	}
+	if _, ok := letType.pointer(); letNode.Ordering != NotAtomic && !letType.isInteger() && !letType.isFloat() && !ok {
+		return llvm.Value{}, 0, newError(MessageAtomicType, letNode.Identifier, letType)
+	}
+	return letNodeValue, letType, nil
+}
+
+// generateLocalVariable is a function that generates LLVM IR code storing a value into a new
+// local variable of the current scope.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// letNode:          The abstract syntax tree (AST) node declaring the variable.
+// value:            The LLVM value the variable is initialized with.
+// t:                The data type of the variable.
+func generateLocalVariable(scope *Scope, functionBuilder llvm.Builder, letNode *LetNode, value llvm.Value, t dataType) {
+	// Create an alloca instruction to allocate memory for the new local variable
+	alloca := functionBuilder.CreateAlloca(llvmType(t), letNode.Identifier)
+	// Align the allocated memory to the size of the type
+	alloca.SetAlignment(dataTypeAlignment(t))
+	variable := letVariable(letNode, &alloca, t)
+	// Store the value in the allocated memory
+	storeVariable(functionBuilder, value, variable)
+	// Declare the new local variable for the uses binding to it
+	declareVariable(letNode, variable)
+}
+
+// letVariable returns the variable declared by the let node, which is stored at the given value.
+func letVariable(letNode *LetNode, value *llvm.Value, t dataType) Variable {
+	return Variable{
+		Value:    value,
+		Type:     t,
+		Volatile: letNode.Volatile,
+		Ordering: letNode.Ordering,
+	}
+}
+
+// loadVariable generates LLVM IR code loading the current value of the variable. The load is
+// volatile or atomic if the variable was declared so.
+func loadVariable(functionBuilder llvm.Builder, variable Variable, name string) llvm.Value {
+	load := functionBuilder.CreateLoad(llvmType(variable.Type), *variable.Value, name)
+	annotateMemoryAccess(load, variable, llvm.AtomicOrderingAcquire)
+	return load
+}
+
+// storeVariable generates LLVM IR code storing the value into the variable. The store is
+// volatile or atomic if the variable was declared so.
+func storeVariable(functionBuilder llvm.Builder, value llvm.Value, variable Variable) {
+	store := functionBuilder.CreateStore(value, *variable.Value)
+	annotateMemoryAccess(store, variable, llvm.AtomicOrderingRelease)
+}
+
+// annotateMemoryAccess marks the load or store of the variable as volatile and atomic according
+// to its declaration. Acquire-release variables are accessed with the given ordering, which is
+// acquire for loads and release for stores.
+func annotateMemoryAccess(access llvm.Value, variable Variable, acquireRelease llvm.AtomicOrdering) {
+	if variable.Volatile {
+		access.SetVolatile(true)
+	}
+
+	switch variable.Ordering {
+	case OrderingRelaxed:
+		access.SetOrdering(llvm.AtomicOrderingMonotonic)
+	case OrderingAcquireRelease:
+		access.SetOrdering(acquireRelease)
+	case OrderingSequentiallyConsistent:
+		access.SetOrdering(llvm.AtomicOrderingSequentiallyConsistent)
+	default:
+		return
+	}
+	// Atomic accesses have to be aligned to the size of their type
+	access.SetAlignment(dataTypeAlignment(variable.Type))
+}
+
+// generateTypedValue is a function that generates LLVM IR code producing a value of the given data type.
+// Literals are converted into a constant of the data type and the elements of array literals into
+// values of the element type, also if the data type is a slice type. Every other value must already
+// have the data type.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// value:            The value taken from the abstract syntax tree (AST).
+// t:                The data type the value must have.
+func generateTypedValue(scope *Scope, functionBuilder llvm.Builder, value Expr, t dataType) (llvm.Value, error) {
+	if _, ok := literalDataType(value); ok {
+		return generateConstant(value, t)
+	}
+	if arrayLiteralNode, ok := value.(*ArrayLiteralNode); ok {
+		if _, ok := t.slice(); ok {
+			return generateSliceLiteral(scope, functionBuilder, arrayLiteralNode, t)
+		}
+		return generateArrayLiteral(scope, functionBuilder, arrayLiteralNode, t)
+	}
+	if conditionalNode, ok := value.(*ConditionalNode); ok {
+		conditional, _, err := generateConditional(scope, functionBuilder, conditionalNode, &t)
+		return conditional, err
+	}
+	if shiftOperationNode, ok := value.(*ShiftOperationNode); ok && t.isInteger() {
+		shift, _, err := generateShift(scope, functionBuilder, shiftOperationNode, &t)
+		return shift, err
+	}
+	if callerNode, ok := value.(*CallerNode); ok && (t.isInteger() || t.isFloat()) && isMathBuiltin(scope, callerNode.FunctionName) {
+		math, mathType, err := generateMath(scope, functionBuilder, callerNode, &t)
+		if err == nil && mathType != t {
+			err = newError(MessageValueType, mathType, t)
+		}
+		return math, err
+	}
+	if optionType, ok := t.option(); ok {
+		// Nones and the values of options take the element type of the option
+		if _, ok := value.(*NoneNode); ok {
+			return llvm.ConstNull(llvmType(t)), nil
+		}
+		if callerNode, ok := value.(*CallerNode); ok && callerNode.FunctionName == someIdentifier {
+			option, _, err := generateSome(scope, functionBuilder, callerNode, &optionType.Element)
+			return option, err
+		}
+	}
+	if resultType, ok := t.result(); ok {
+		// Values and errors take the element type of the result
+		if callerNode, ok := value.(*CallerNode); ok && callerNode.FunctionName == okIdentifier {
+			result, _, err := generateOk(scope, functionBuilder, callerNode, &resultType.Element)
+			return result, err
+		}
+		if callerNode, ok := value.(*CallerNode); ok && callerNode.FunctionName == errIdentifier {
+			return generateErr(scope, functionBuilder, callerNode, t)
+		}
+	}
+
+	llvmValue, valueType, err := generateValue(scope, functionBuilder, value)
+	if err != nil {
+		return llvm.Value{}, err
+	}
+	if valueType != t {
+		return llvm.Value{}, newError(MessageValueType, valueType, t)
+	}
+	return llvmValue, nil
+}
+
+// generateConstant creates a constant LLVM value of the given data type from a literal.
+// Integer literals may be used for every integer type they fit into and for floating
+// point types, floating point literals only for floating point types and boolean
+// literals only for the bool type.
+//
+// Returns an error if the literal can't be represented by the data type.
+func generateConstant(value Expr, t dataType) (llvm.Value, error) {
+	switch v := value.(type) {
+	case *IntLiteralNode:
+		if t.isFloat() {
+			return llvm.ConstFloat(llvmType(t), float64(v.Value)), nil
+		}
+		if !t.isInteger() {
+			return llvm.Value{}, newError(MessageIntegerLiteralType, v.Value, t)
+		}
+		bits := dataTypeBits(t)
+		if bits < 64 && (v.Value < -(1<<(bits-1)) || v.Value > 1<<(bits-1)-1) {
+			return llvm.Value{}, newError(MessageConstantOverflow, v.Value, t)
+		}
+		return llvm.ConstInt(llvmType(t), uint64(v.Value), true), nil
+	case *FloatLiteralNode:
+		if !t.isFloat() {
+			return llvm.Value{}, newError(MessageFloatLiteralType, v.Value, t)
+		}
+		return llvm.ConstFloat(llvmType(t), v.Value), nil
+	case *BoolLiteralNode:
+		if t != BoolType {
+			return llvm.Value{}, newError(MessageBoolLiteralType, v.Value, t)
+		}
+		var boolValue uint64
+		if v.Value {
+			boolValue = 1
+		}
+		return llvm.ConstInt(globalScope.Context.Int1Type(), boolValue, false), nil
+	default:
+		return llvm.Value{}, newError(MessageInvalidLiteral, value)
+	}
+}
+
+// generateArrayLiteral is a function that generates LLVM IR code producing an array of the given data type
+// from an array literal. Every element is converted into a value of the element type, missing elements are zero.
+//
+// scope:             A pointer to the current scope.
+// functionBuilder:   The LLVM builder associated with the current function.
+// arrayLiteralNode:  The abstract syntax tree (AST) node representing the array literal.
+// t:                 The array data type the value must have.
+//
+// Returns an error if the data type isn't an array type or the literal has too many or invalid elements.
+func generateArrayLiteral(scope *Scope, functionBuilder llvm.Builder, arrayLiteralNode *ArrayLiteralNode, t dataType) (llvm.Value, error) {
+	arrayType, ok := t.array()
+	if !ok {
+		return llvm.Value{}, newError(MessageArrayLiteralType, t)
+	}
+	if len(arrayLiteralNode.Elements) > arrayType.Length {
+		return llvm.Value{}, newError(MessageArrayLiteralOverflow, len(arrayLiteralNode.Elements), t)
+	}
+
+	return generateArrayElements(scope, functionBuilder, llvm.ConstNull(llvmType(t)), arrayLiteralNode.Elements, 0, arrayType.Element)
+}
+
+// generateSliceLiteral is a function that generates LLVM IR code producing a slice of the given data type
+// from an array literal. The elements are stored into a new heap-allocated backing array, which is exactly
+// as large as the literal, an empty literal produces a slice without backing array.
+//
+// scope:             A pointer to the current scope.
+// functionBuilder:   The LLVM builder associated with the current function.
+// arrayLiteralNode:  The abstract syntax tree (AST) node representing the array literal.
+// t:                 The slice data type the value must have.
+//
+// Returns an error if an element can't be used as value of the element type.
+func generateSliceLiteral(scope *Scope, functionBuilder llvm.Builder, arrayLiteralNode *ArrayLiteralNode, t dataType) (llvm.Value, error) {
+	sliceType, _ := t.slice()
+	slice := llvm.ConstNull(llvmType(t))
+	if len(arrayLiteralNode.Elements) == 0 {
+		return slice, nil
+	}
+
+	var values []llvm.Value
+	for i, element := range arrayLiteralNode.Elements {
+		value, err := generateTypedValue(scope, functionBuilder, element, sliceType.Element)
+		if err != nil {
+			return llvm.Value{}, newError(MessageInvalidArrayElement, i+1, err)
+		}
+		values = append(values, value)
+	}
+
+	// Allocate the backing array and store the elements into it
+	length := llvm.ConstInt(globalScope.Context.Int64Type(), uint64(len(values)), false)
+	elementType := llvmType(sliceType.Element)
+	mallocType, malloc := heapAllocFunction(functionBuilder)
+	data := functionBuilder.CreateCall(mallocType, malloc, []llvm.Value{functionBuilder.CreateMul(llvm.SizeOf(elementType), length, "")}, "")
+	for i, value := range values {
+		address := functionBuilder.CreateInBoundsGEP(elementType, data, []llvm.Value{llvm.ConstInt(globalScope.Context.Int64Type(), uint64(i), false)}, "")
+		functionBuilder.CreateStore(value, address)
+	}
+
+	slice = functionBuilder.CreateInsertValue(slice, data, sliceData, "")
+	slice = functionBuilder.CreateInsertValue(slice, length, sliceLength, "")
+	slice = functionBuilder.CreateInsertValue(slice, length, sliceCapacity, "")
+	return slice, nil
+}
+
+// generateStructLiteral is a function that generates LLVM IR code producing a struct value from a
+// struct literal. Every field value is converted into a value of the field type, missing fields are zero.
+//
+// scope:              A pointer to the current scope.
+// functionBuilder:    The LLVM builder associated with the current function.
+// structLiteralNode:  The abstract syntax tree (AST) node representing the struct literal.
+//
+// Returns the struct value and its data type, or an error if the struct or one of the fields is unknown,
+// a field is given twice or a value doesn't match the type of its field.
+func generateStructLiteral(scope *Scope, functionBuilder llvm.Builder, structLiteralNode *StructLiteralNode) (llvm.Value, dataType, error) {
+	structure, ok := globalScope.Structs.Get(structLiteralNode.Name)
+	if !ok {
+		return llvm.Value{}, 0, newError(MessageUnknownStruct, structLiteralNode.Name)
+	}
+
+	value := llvm.ConstNull(structure.Type)
+	assigned := make(map[string]bool)
+	for _, fieldValue := range structLiteralNode.Fields {
+		index, field, ok := structure.field(fieldValue.Identifier)
+		if !ok {
+			return llvm.Value{}, 0, newError(MessageUnknownField, fieldValue.Identifier, structLiteralNode.Name)
+		}
+		if assigned[field.Identifier] {
+			return llvm.Value{}, 0, newError(MessageDuplicateFieldValue, field.Identifier, structLiteralNode.Name)
+		}
+		assigned[field.Identifier] = true
+
+		fieldLlvmValue, err := generateTypedValue(scope, functionBuilder, fieldValue.Value, field.Type)
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidFieldValue, field.Identifier, structLiteralNode.Name, err)
+		}
+		value = functionBuilder.CreateInsertValue(value, fieldLlvmValue, structure.element(index), "")
+	}
+
+	return value, structOf(structLiteralNode.Name), nil
+}
+
+// generateLength is a function that generates LLVM IR code for a call of the len or cap builtin, which
+// return the length or the capacity of an array or slice as i64. The capacity of an array is its length,
+// which is known at compile time. The length of a string is the number of its bytes, a string has no
+// capacity.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+//
+// Returns an error if the call doesn't pass exactly one array, slice or, for len, string.
+func generateLength(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) != 1 {
+		return llvm.Value{}, 0, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+	}
+
+	value, t, err := generateValue(scope, functionBuilder, callerNode.Args[0])
+	if err != nil {
+		return llvm.Value{}, 0, err
+	}
+	if arrayType, ok := t.array(); ok {
+		return llvm.ConstInt(globalScope.Context.Int64Type(), uint64(arrayType.Length), false), Integer64Type, nil
+	}
+	if t == StringType && callerNode.FunctionName == lenIdentifier {
+		strlenType, strlen := strlenFunction(functionBuilder)
+		return functionBuilder.CreateCall(strlenType, strlen, []llvm.Value{value}, ""), Integer64Type, nil
+	}
+	if _, ok := t.slice(); !ok {
+		return llvm.Value{}, 0, newError(MessageInvalidBuiltinValue, t, callerNode.FunctionName)
+	}
+
+	field := sliceLength
+	if callerNode.FunctionName == capIdentifier {
+		field = sliceCapacity
+	}
+	return functionBuilder.CreateExtractValue(value, field, ""), Integer64Type, nil
+}
+
+// generateAppend is a function that generates LLVM IR code for a call of the append builtin, which
+// returns the slice passed as first parameter with the remaining parameters added to its end. The
+// backing array is grown by the sliceReserveIdentifier runtime helper whenever it is full.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+//
+// Returns an error if the first parameter isn't a slice or a value doesn't match its element type.
+func generateAppend(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) < 2 {
+		return llvm.Value{}, 0, newError(MessageExpectedSliceAndValues, appendIdentifier, len(callerNode.Args))
+	}
+
+	slice, t, err := generateValue(scope, functionBuilder, callerNode.Args[0])
+	if err != nil {
+		return llvm.Value{}, 0, err
+	}
+	sliceType, ok := t.slice()
+	if !ok {
+		return llvm.Value{}, 0, newError(MessageAppendType, t)
+	}
+
+	elementType := llvmType(sliceType.Element)
+	reserveType, reserve := sliceReserveFunction(functionBuilder)
+	for i, arg := range callerNode.Args[1:] {
+		value, err := generateTypedValue(scope, functionBuilder, arg, sliceType.Element)
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidBuiltinParameter, i+2, appendIdentifier, err)
+		}
+
+		// Make room for the value and store it behind the last element
+		slice = functionBuilder.CreateCall(reserveType, reserve, []llvm.Value{slice, llvm.SizeOf(elementType)}, "")
+		data := functionBuilder.CreateExtractValue(slice, sliceData, "")
+		length := functionBuilder.CreateExtractValue(slice, sliceLength, "")
+		address := functionBuilder.CreateInBoundsGEP(elementType, data, []llvm.Value{length}, "")
+		functionBuilder.CreateStore(value, address)
+		length = functionBuilder.CreateAdd(length, llvm.ConstInt(globalScope.Context.Int64Type(), 1, false), "")
+		slice = functionBuilder.CreateInsertValue(slice, length, sliceLength, "")
+	}
+
+	return slice, t, nil
+}
+
+// generateNew is a function that generates LLVM IR code allocating a zeroed value of the given type
+// on the heap. The size of the allocation is taken from the data layout of the module.
+//
+// functionBuilder:  The LLVM builder associated with the current function.
+// newNode:          The abstract syntax tree (AST) node representing the allocation.
+//
+// Returns the pointer to the allocated value and its pointer data type.
+func generateNew(functionBuilder llvm.Builder, newNode *NewNode) (llvm.Value, dataType, error) {
+	if err := validateType(newNode.Type); err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidNewType, err)
+	}
+	t := llvmType(newNode.Type)
+
+	module := functionBuilder.GetInsertBlock().Parent().GlobalParent()
+	targetData := llvm.NewTargetData(module.DataLayout())
+	size := targetData.TypeAllocSize(t)
+	targetData.Dispose()
+
+	mallocType, malloc := heapAllocFunction(functionBuilder)
+	pointer := functionBuilder.CreateCall(mallocType, malloc, []llvm.Value{llvm.ConstInt(globalScope.Context.Int64Type(), size, false)}, "")
+	store := functionBuilder.CreateStore(llvm.ConstNull(t), pointer)
+	store.SetAlignment(dataTypeAlignment(newNode.Type))
+	return pointer, pointerTo(newNode.Type), nil
+}
+
+// generateFree is a function that generates LLVM IR code releasing heap memory: the value a pointer
+// returned by new refers to or the backing array of a slice.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call of free.
+//
+// Returns an error if the call doesn't pass exactly one pointer or slice.
+func generateFree(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) != 1 {
+		return llvm.Value{}, 0, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+	}
+	value, t, err := generateValue(scope, functionBuilder, callerNode.Args[0])
+	if err != nil {
+		return llvm.Value{}, 0, err
+	}
+
+	pointer := value
+	if _, ok := t.slice(); ok {
+		pointer = functionBuilder.CreateExtractValue(value, sliceData, "")
+	} else if _, ok := t.pointer(); !ok {
+		return llvm.Value{}, 0, newError(MessageFreeType, t)
+	}
+
+	// The garbage collector releases the memory once nothing points into it anymore
+	if globalScope.Options.GarbageCollection {
+		return llvm.Value{}, VoidType, nil
+	}
+	freeType, free := freeFunction(functionBuilder)
+	functionBuilder.CreateCall(freeType, free, []llvm.Value{pointer}, "")
+	return llvm.Value{}, VoidType, nil
+}
+
+// generateConditional is a function that generates LLVM IR code producing the true or the false value
+// of a conditional expression depending on its condition. Each value is generated in its own block
+// and only evaluated if it is selected, the blocks join in a phi node. A literal value takes the
+// type of the other value.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// conditionalNode:  The abstract syntax tree (AST) node representing the conditional expression.
+// t:                A pointer to the data type of the values, nil to take it from the values.
+//
+// Returns an error if the condition isn't a bool or the values have different data types.
+func generateConditional(scope *Scope, functionBuilder llvm.Builder, conditionalNode *ConditionalNode, t *dataType) (llvm.Value, dataType, error) {
+	condition, conditionType, err := generateValue(scope, functionBuilder, conditionalNode.Condition)
+	if err != nil {
+		return llvm.Value{}, 0, err
+	}
+	if conditionType != BoolType {
+		return llvm.Value{}, 0, newError(MessageConditionType, conditionType)
+	}
+
+	function := functionBuilder.GetInsertBlock().Parent()
+	trueBlock := globalScope.Context.AddBasicBlock(function, "conditional_true")
+	falseBlock := globalScope.Context.AddBasicBlock(function, "conditional_false")
+	doneBlock := globalScope.Context.AddBasicBlock(function, "conditional_done")
+	functionBuilder.CreateCondBr(condition, trueBlock, falseBlock)
+
+	values := []Expr{conditionalNode.True, conditionalNode.False}
+	blocks := []llvm.BasicBlock{trueBlock, falseBlock}
+	order := []int{0, 1}
+	if _, ok := literalDataType(conditionalNode.True); ok && t == nil {
+		if _, ok := literalDataType(conditionalNode.False); !ok {
+			// Generate the false value first, so the literal takes its type
+			order = []int{1, 0}
+		}
+	}
+
+	var valueType dataType
+	known := t != nil
+	if known {
+		valueType = *t
+	}
+	llvmValues := make([]llvm.Value, 2)
+	for _, i := range order {
+		functionBuilder.SetInsertPointAtEnd(blocks[i])
+		if known {
+			llvmValues[i], err = generateTypedValue(scope, functionBuilder, values[i], valueType)
+		} else {
+			llvmValues[i], valueType, err = generateValue(scope, functionBuilder, values[i])
+			if err == nil && valueType == VoidType {
+				err = newError(MessageVoidCallAsValue)
+			}
+			known = true
+		}
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidConditionalValue, err)
+		}
+		// The value may have created blocks, the phi needs the block it ends in
+		blocks[i] = functionBuilder.GetInsertBlock()
+		functionBuilder.CreateBr(doneBlock)
+	}
+
+	// Keep the join block after the blocks created by the values
+	doneBlock.MoveAfter(function.LastBasicBlock())
+	functionBuilder.SetInsertPointAtEnd(doneBlock)
+	result := functionBuilder.CreatePHI(llvmType(valueType), "conditional")
+	result.AddIncoming(llvmValues, blocks)
+	return result, valueType, nil
+}
+
+// generateSome is a function that generates LLVM IR code producing an option holding a value. The
+// element type is known if the type of the option follows from where it is used.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call of some.
+// element:          A pointer to the element type of the option, nil to take it from the value.
+//
+// Returns an error if the call doesn't pass exactly one value of the element type.
+func generateSome(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode, element *dataType) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) != 1 {
+		return llvm.Value{}, 0, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+	}
+
+	var value llvm.Value
+	var t dataType
+	var err error
+	if element != nil {
+		t = *element
+		value, err = generateTypedValue(scope, functionBuilder, callerNode.Args[0], t)
+	} else {
+		value, t, err = generateValue(scope, functionBuilder, callerNode.Args[0])
+		if err == nil && t == VoidType {
+			err = newError(MessageVoidCallAsValue)
+		}
+	}
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidOptionValue, err)
+	}
+
+	option := llvm.ConstNull(llvmType(optionOf(t)))
+	option = functionBuilder.CreateInsertValue(option, llvm.ConstInt(globalScope.Context.Int1Type(), 1, false), optionPresent, "")
+	option = functionBuilder.CreateInsertValue(option, value, optionValue, "")
+	return option, optionOf(t), nil
+}
+
+// generateUnwrapOr is a function that generates LLVM IR code producing the value of an option or a
+// result, or the fallback value if the option holds no value or the result holds an error. The
+// fallback is only evaluated if it is needed.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call of unwrap_or.
+//
+// Returns an error if the call doesn't pass an option or result and a fallback value of its element type.
+func generateUnwrapOr(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) != 2 {
+		return llvm.Value{}, 0, newError(MessageExpectedTwoParameters, callerNode.FunctionName, len(callerNode.Args))
+	}
+	option, t, err := generateValue(scope, functionBuilder, callerNode.Args[0])
+	if err != nil {
+		return llvm.Value{}, 0, err
+	}
+	element, ok := unwrappedType(t)
+	if !ok {
+		return llvm.Value{}, 0, newError(MessageUnwrapType, t)
+	}
+
+	function := functionBuilder.GetInsertBlock().Parent()
+	someBlock := functionBuilder.GetInsertBlock()
+	noneBlock := globalScope.Context.AddBasicBlock(function, "option_none")
+	doneBlock := globalScope.Context.AddBasicBlock(function, "option_done")
+
+	present := functionBuilder.CreateExtractValue(option, optionPresent, "present")
+	value := functionBuilder.CreateExtractValue(option, optionValue, "value")
+	functionBuilder.CreateCondBr(present, doneBlock, noneBlock)
+
+	functionBuilder.SetInsertPointAtEnd(noneBlock)
+	fallback, err := generateTypedValue(scope, functionBuilder, callerNode.Args[1], element)
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidUnwrapFallback, err)
+	}
+	// The fallback may have created blocks, the phi needs the block it ends in
+	noneBlock = functionBuilder.GetInsertBlock()
+	functionBuilder.CreateBr(doneBlock)
+
+	functionBuilder.SetInsertPointAtEnd(doneBlock)
+	result := functionBuilder.CreatePHI(llvmType(element), "unwrapped")
+	result.AddIncoming([]llvm.Value{value, fallback}, []llvm.BasicBlock{someBlock, noneBlock})
+	return result, element, nil
+}
+
+// unwrappedType returns the element type of an option or result type and whether the data type
+// is one of them. Options and results both hold whether they have a value and the value at the
+// same positions, so they are unwrapped the same way.
+func unwrappedType(t dataType) (dataType, bool) {
+	if optionType, ok := t.option(); ok {
+		return optionType.Element, true
+	}
+	if resultType, ok := t.result(); ok {
+		return resultType.Element, true
+	}
+	return 0, false
+}
+
+// generateOk is a function that generates LLVM IR code producing a result holding a value. The
+// element type is known if the type of the result follows from where it is used.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call of ok.
+// element:          A pointer to the element type of the result, nil to take it from the value.
+//
+// Returns an error if the call doesn't pass exactly one value of the element type.
+func generateOk(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode, element *dataType) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) != 1 {
+		return llvm.Value{}, 0, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+	}
+
+	var value llvm.Value
+	var t dataType
+	var err error
+	if element != nil {
+		t = *element
+		value, err = generateTypedValue(scope, functionBuilder, callerNode.Args[0], t)
+	} else {
+		value, t, err = generateValue(scope, functionBuilder, callerNode.Args[0])
+		if err == nil && t == VoidType {
+			err = newError(MessageVoidCallAsValue)
+		}
+	}
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidResultValue, err)
+	}
+
+	result := llvm.ConstNull(llvmType(resultOf(t)))
+	result = functionBuilder.CreateInsertValue(result, llvm.ConstInt(globalScope.Context.Int1Type(), 1, false), resultOk, "")
+	result = functionBuilder.CreateInsertValue(result, value, resultValue, "")
+	return result, resultOf(t), nil
+}
+
+// generateErr is a function that generates LLVM IR code producing a result of the given data type
+// holding an error code.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call of err.
+// t:                The result data type.
+//
+// Returns an error if the call doesn't pass exactly one i32 error code.
+func generateErr(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode, t dataType) (llvm.Value, error) {
+	if len(callerNode.Args) != 1 {
+		return llvm.Value{}, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+	}
+	code, err := generateTypedValue(scope, functionBuilder, callerNode.Args[0], Integer32Type)
+	if err != nil {
+		return llvm.Value{}, newError(MessageInvalidErrorCode, err)
+	}
+	return functionBuilder.CreateInsertValue(llvm.ConstNull(llvmType(t)), code, resultError, ""), nil
+}
+
+// generateTry is a function that generates LLVM IR code producing the value of a result. If the
+// result holds an error, the current function returns a result holding the same error code.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// tryNode:          The abstract syntax tree (AST) node representing the try.
+//
+// Returns an error if the value isn't a result or the current function doesn't return a result.
+func generateTry(scope *Scope, functionBuilder llvm.Builder, tryNode *TryNode) (llvm.Value, dataType, error) {
+	result, t, err := generateValue(scope, functionBuilder, tryNode.Value)
+	if err != nil {
+		return llvm.Value{}, 0, err
+	}
+	resultType, ok := t.result()
+	if !ok {
+		return llvm.Value{}, 0, newError(MessageTryType, t)
+	}
+	if scope.Function == nil {
+		return llvm.Value{}, 0, newError(MessageTryOutsideFunction)
+	}
+	returnType := scope.Function.ReturnType
+	if _, ok := returnType.result(); !ok {
+		return llvm.Value{}, 0, newError(MessageTryReturnType, scope.Function.Name, returnType)
+	}
+
+	function := functionBuilder.GetInsertBlock().Parent()
+	errorBlock := globalScope.Context.AddBasicBlock(function, "try_error")
+	okBlock := globalScope.Context.AddBasicBlock(function, "try_ok")
+
+	isOk := functionBuilder.CreateExtractValue(result, resultOk, "ok")
+	functionBuilder.CreateCondBr(isOk, okBlock, errorBlock)
+
+	// Return the error code of the result from the current function
+	functionBuilder.SetInsertPointAtEnd(errorBlock)
+	code := functionBuilder.CreateExtractValue(result, resultError, "error")
+	functionBuilder.CreateRet(functionBuilder.CreateInsertValue(llvm.ConstNull(llvmType(returnType)), code, resultError, ""))
+
+	functionBuilder.SetInsertPointAtEnd(okBlock)
+	return functionBuilder.CreateExtractValue(result, resultValue, "value"), resultType.Element, nil
+}
+
+// generateAssignment is a function that generates LLVM IR code storing a new value into a local variable.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// assignmentNode:   The abstract syntax tree (AST) node representing the assignment.
+//
+// Returns an error if the variable doesn't exist or the value doesn't match its data type.
+func generateAssignment(scope *Scope, functionBuilder llvm.Builder, assignmentNode *AssignmentNode) error {
+	variable, ok := lookupVariable(assignmentNode)
+	if !ok {
+		if symbol := lookupSymbol(assignmentNode); symbol != nil && symbol.Kind == SymbolParameter {
+			return newError(MessageAssignToArgument, assignmentNode.Identifier)
+		}
+		return newError(MessageVariableNotFound, assignmentNode.Identifier)
+	}
+
+	value, err := generateTypedValue(scope, functionBuilder, assignmentNode.Value, variable.Type)
+	if err != nil {
+		return newError(MessageInvalidAssignmentValue, assignmentNode.Identifier, err)
+	}
+
+	storeVariable(functionBuilder, value, variable)
+	return nil
+}
+
+// generateArrayElements is a function that generates LLVM IR code inserting values of the element type
+// into an array, starting at the given position.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// array:            The LLVM value of the array the elements are inserted into.
+// elements:         The elements taken from the abstract syntax tree (AST).
+// start:            The position of the first element in the array.
+// element:          The data type of the elements.
+//
+// Returns the array holding the elements, or an error if an element can't be used as value of the element type.
+func generateArrayElements(scope *Scope, functionBuilder llvm.Builder, array llvm.Value, elements []Expr, start int, element dataType) (llvm.Value, error) {
+	for i, value := range elements {
+		elementValue, err := generateTypedValue(scope, functionBuilder, value, element)
+		if err != nil {
+			return llvm.Value{}, newError(MessageInvalidArrayElement, start+i+1, err)
+		}
+		array = functionBuilder.CreateInsertValue(array, elementValue, start+i, "")
+	}
+	return array, nil
+}
+
+// generateIndexAssignment is a function that generates LLVM IR code storing a value into an array element.
+// Only elements of local variables can be assigned.
+//
+// scope:                A pointer to the current scope.
+// functionBuilder:      The LLVM builder associated with the current function.
+// indexAssignmentNode:  The abstract syntax tree (AST) node representing the assignment.
+//
+// Returns an error if the element can't be assigned or the value doesn't match the element type.
+func generateIndexAssignment(scope *Scope, functionBuilder llvm.Builder, indexAssignmentNode *IndexAssignmentNode) error {
+	address, element, assignable, err := generateIndexAddress(scope, functionBuilder, indexAssignmentNode.Target)
+	if err != nil {
+		return err
+	}
+	if !assignable {
+		return newError(MessageAssignToArrayValue)
+	}
+
+	value, err := generateTypedValue(scope, functionBuilder, indexAssignmentNode.Value, element)
+	if err != nil {
+		return newError(MessageInvalidArrayElementValue, err)
+	}
+
+	functionBuilder.CreateStore(value, address)
+	return nil
+}
+
+// generateFieldAssignment is a function that generates LLVM IR code storing a value into a struct field.
+// Only fields of local variables and of slice elements can be assigned.
+//
+// scope:                A pointer to the current scope.
+// functionBuilder:      The LLVM builder associated with the current function.
+// fieldAssignmentNode:  The abstract syntax tree (AST) node representing the assignment.
+//
+// Returns an error if the field can't be assigned or the value doesn't match the field type.
+func generateFieldAssignment(scope *Scope, functionBuilder llvm.Builder, fieldAssignmentNode *FieldAssignmentNode) error {
+	address, field, alignment, assignable, err := generateFieldAddress(scope, functionBuilder, fieldAssignmentNode.Target)
+	if err != nil {
+		return err
+	}
+	if !assignable {
+		return newError(MessageAssignToStructValue)
+	}
+
+	value, err := generateTypedValue(scope, functionBuilder, fieldAssignmentNode.Value, field)
+	if err != nil {
+		return newError(MessageInvalidFieldAssignmentValue, fieldAssignmentNode.Target.Field, err)
+	}
+
+	store := functionBuilder.CreateStore(value, address)
+	if alignment != 0 {
+		store.SetAlignment(alignment)
+	}
+	return nil
+}
+
+// generateDereferenceAssignment is a function that generates LLVM IR code storing a value into the
+// value a pointer refers to.
+//
+// scope:                      A pointer to the current scope.
+// functionBuilder:            The LLVM builder associated with the current function.
+// dereferenceAssignmentNode:  The abstract syntax tree (AST) node representing the assignment.
+//
+// Returns an error if the target isn't a pointer or the value doesn't match the type it refers to.
+func generateDereferenceAssignment(scope *Scope, functionBuilder llvm.Builder, dereferenceAssignmentNode *DereferenceAssignmentNode) error {
+	address, element, _, err := generateAddress(scope, functionBuilder, dereferenceAssignmentNode.Target)
+	if err != nil {
+		return err
+	}
+
+	value, err := generateTypedValue(scope, functionBuilder, dereferenceAssignmentNode.Value, element)
+	if err != nil {
+		return newError(MessageInvalidDereferenceAssignmentValue, err)
+	}
+
+	functionBuilder.CreateStore(value, address)
+	return nil
+}
+
+// generateAddress is a function that generates LLVM IR code producing a pointer to the given value.
+// Local variables, array elements and struct fields of local variables and the values pointers refer
+// to are addressed directly, every other value is stored into a new temporary local variable.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// value:            The value taken from the abstract syntax tree (AST).
+//
+// Returns the pointer, the data type of the value and whether the pointer refers to a local variable.
+func generateAddress(scope *Scope, functionBuilder llvm.Builder, value Expr) (llvm.Value, dataType, bool, error) {
+	switch v := value.(type) {
+	case *IdentifierNode:
+		if variable, ok := lookupVariable(v); ok {
+			return *variable.Value, variable.Type, true, nil
+		}
+		if symbol := lookupSymbol(v); symbol != nil && symbol.Kind == SymbolEmbed {
+			if embed, ok := globalScope.Embeds.Get(symbol.Name); ok {
+				return *embed.Value, embed.Type, false, nil
 			}
-		case *WhileNode:
-			// todo to be implemented
 		}
+	case *IndexNode:
+		return generateIndexAddress(scope, functionBuilder, v)
+	case *FieldNode:
+		address, field, _, assignable, err := generateFieldAddress(scope, functionBuilder, v)
+		return address, field, assignable, err
+	case *DereferenceNode:
+		pointer, t, err := generateValue(scope, functionBuilder, v.Value)
+		if err != nil {
+			return llvm.Value{}, 0, false, err
+		}
+		pointerType, ok := t.pointer()
+		if !ok {
+			return llvm.Value{}, 0, false, newError(MessageDereferenceType, t)
+		}
+		return pointer, pointerType.Element, true, nil
+	}
+
+	llvmValue, valueType, err := generateValue(scope, functionBuilder, value)
+	if err != nil {
+		return llvm.Value{}, 0, false, err
+	}
+	if valueType == VoidType {
+		return llvm.Value{}, 0, false, newError(MessageVoidCallAsValue)
+	}
+	temporary := functionBuilder.CreateAlloca(llvmType(valueType), "")
+	temporary.SetAlignment(dataTypeAlignment(valueType))
+	functionBuilder.CreateStore(llvmValue, temporary)
+	return temporary, valueType, false, nil
+}
+
+// generateIndexAddress is a function that generates LLVM IR code producing a pointer to an array or slice
+// element. Constant indexes into arrays are checked at compile time, every other index is checked at runtime
+// if bounds checks are enabled.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// indexNode:        The abstract syntax tree (AST) node representing the element.
+//
+// Returns the pointer, the data type of the element and whether the element can be assigned, which is
+// the case for elements of local array variables and of every slice.
+func generateIndexAddress(scope *Scope, functionBuilder llvm.Builder, indexNode *IndexNode) (llvm.Value, dataType, bool, error) {
+	address, t, assignable, err := generateAddress(scope, functionBuilder, indexNode.Value)
+	if err != nil {
+		return llvm.Value{}, 0, false, err
+	}
+	arrayType, isArray := t.array()
+	sliceType, isSlice := t.slice()
+	if !isArray && !isSlice {
+		return llvm.Value{}, 0, false, newError(MessageIndexType, t)
+	}
+
+	var index llvm.Value
+	if _, ok := literalDataType(indexNode.Index); ok {
+		index, err = generateConstant(indexNode.Index, Integer64Type)
+		if err != nil {
+			return llvm.Value{}, 0, false, newError(MessageInvalidArrayIndex, err)
+		}
+		if constant := index.SExtValue(); constant < 0 || (isArray && constant >= int64(arrayType.Length)) {
+			return llvm.Value{}, 0, false, newError(MessageIndexOutOfBounds, constant, t)
+		}
+	} else {
+		var indexType dataType
+		index, indexType, err = generateValue(scope, functionBuilder, indexNode.Index)
+		if err != nil {
+			return llvm.Value{}, 0, false, err
+		}
+		if !indexType.isInteger() {
+			return llvm.Value{}, 0, false, newError(MessageInvalidArrayIndexType, indexType)
+		}
+		if indexType != Integer64Type {
+			index = functionBuilder.CreateSExt(index, globalScope.Context.Int64Type(), "")
+		}
+		if globalScope.Options.BoundsChecks && isArray {
+			generateBoundsCheck(functionBuilder, index, llvm.ConstInt(globalScope.Context.Int64Type(), uint64(arrayType.Length), false))
+		}
+	}
+
+	if isSlice {
+		// Index the backing array the slice refers to
+		slice := functionBuilder.CreateLoad(llvmType(t), address, "")
+		if globalScope.Options.BoundsChecks {
+			generateBoundsCheck(functionBuilder, index, functionBuilder.CreateExtractValue(slice, sliceLength, ""))
+		}
+		data := functionBuilder.CreateExtractValue(slice, sliceData, "")
+		elementAddress := functionBuilder.CreateInBoundsGEP(llvmType(sliceType.Element), data, []llvm.Value{index}, "")
+		return elementAddress, sliceType.Element, true, nil
+	}
+
+	zero := llvm.ConstInt(globalScope.Context.Int64Type(), 0, false)
+	elementAddress := functionBuilder.CreateInBoundsGEP(llvmType(t), address, []llvm.Value{zero, index}, "")
+	return elementAddress, arrayType.Element, assignable, nil
+}
+
+// generateFieldAddress is a function that generates LLVM IR code producing a pointer to a struct field.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// fieldNode:        The abstract syntax tree (AST) node representing the field.
+//
+// Fields of a struct a pointer refers to are selected through the pointer, like in p.x for a
+// pointer p.
+//
+// The alignment of fields of packed structs and of structs nested in them may be lower than the
+// alignment of their types, so loads and stores of the field have to use the returned alignment
+// unless it is 0.
+//
+// Returns the pointer, the data type of the field, the alignment of the pointer or 0 for the
+// alignment of the data type and whether the field can be assigned, which is the case if the
+// struct can be assigned.
+func generateFieldAddress(scope *Scope, functionBuilder llvm.Builder, fieldNode *FieldNode) (llvm.Value, dataType, int, bool, error) {
+	var address llvm.Value
+	var t dataType
+	var structAlignment int
+	var assignable bool
+	var err error
+	if inner, ok := fieldNode.Value.(*FieldNode); ok {
+		address, t, structAlignment, assignable, err = generateFieldAddress(scope, functionBuilder, inner)
+	} else {
+		address, t, assignable, err = generateAddress(scope, functionBuilder, fieldNode.Value)
+	}
+	if err != nil {
+		return llvm.Value{}, 0, 0, false, err
+	}
+	if pointerType, ok := t.pointer(); ok {
+		if _, ok := pointerType.Element.structure(); ok {
+			// Select the field of the struct the pointer refers to
+			address = loadAligned(functionBuilder, llvmType(t), address, structAlignment)
+			t, structAlignment, assignable = pointerType.Element, 0, true
+		}
+	}
+	structType, ok := t.structure()
+	if !ok {
+		return llvm.Value{}, 0, 0, false, newError(MessageFieldType, fieldNode.Field, t)
+	}
+	structure, ok := globalScope.Structs.Get(structType.Name)
+	if !ok {
+		return llvm.Value{}, 0, 0, false, newError(MessageUnknownStruct, structType.Name)
+	}
+	i, field, ok := structure.field(fieldNode.Field)
+	if !ok {
+		return llvm.Value{}, 0, 0, false, newError(MessageUnknownField, fieldNode.Field, structType.Name)
+	}
+
+	// A field is aligned like in its struct unless the struct itself is less aligned
+	var alignment int
+	if structure.Alignments != nil {
+		alignment = structure.Alignments[i]
+	}
+	if structAlignment != 0 {
+		if alignment == 0 {
+			alignment = dataTypeAlignment(field.Type)
+		}
+		if structAlignment < alignment {
+			alignment = structAlignment
+		}
+	}
+
+	fieldAddress := functionBuilder.CreateStructGEP(structure.Type, address, structure.element(i), "")
+	return fieldAddress, field.Type, alignment, assignable, nil
+}
+
+// loadAligned generates LLVM IR code loading a value of the given type from the address, which has
+// the given alignment, or the alignment of the type if it is 0.
+func loadAligned(functionBuilder llvm.Builder, t llvm.Type, address llvm.Value, alignment int) llvm.Value {
+	load := functionBuilder.CreateLoad(t, address, "")
+	if alignment != 0 {
+		load.SetAlignment(alignment)
+	}
+	return load
+}
+
+// generateBoundsCheck is a function that generates LLVM IR code which traps if the index is not
+// smaller than the length of the array or slice. Negative indexes are treated as large unsigned values,
+// so a single comparison covers both bounds. Code generation continues in the block of valid indexes.
+//
+// functionBuilder:  The LLVM builder associated with the current function.
+// index:            The 64-bit LLVM value of the index.
+// length:           The 64-bit LLVM value of the length of the indexed array or slice.
+func generateBoundsCheck(functionBuilder llvm.Builder, index llvm.Value, length llvm.Value) {
+	function := functionBuilder.GetInsertBlock().Parent()
+	inBounds := functionBuilder.CreateICmp(llvm.IntULT, index, length, "in_bounds")
+
+	outOfBoundsBlock := globalScope.Context.AddBasicBlock(function, "index_out_of_bounds")
+	inBoundsBlock := globalScope.Context.AddBasicBlock(function, "index_in_bounds")
+	functionBuilder.CreateCondBr(inBounds, inBoundsBlock, outOfBoundsBlock)
+
+	functionBuilder.SetInsertPointAtEnd(outOfBoundsBlock)
+	trapType, trap := trapFunction(functionBuilder)
+	functionBuilder.CreateCall(trapType, trap, []llvm.Value{}, "")
+	functionBuilder.CreateUnreachable()
+
+	functionBuilder.SetInsertPointAtEnd(inBoundsBlock)
+}
+
+// generateAdd is a function that generates LLVM IR code for an "add" statement.
+// The add statement adds two number values in the current scope, the result isn't used.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// AddOperationNode: The abstract syntax tree (AST) node representing the let statement.
+//
+// Returns an error if the value type of the AddOperationNode is not supported.
+func generateAdd(scope *Scope, functionBuilder llvm.Builder, addOperationNode *AddOperationNode) error {
+	_, _, err := generateAddValue(scope, functionBuilder, addOperationNode)
+	return err
+}
+
+// generateAddValue is a function that generates LLVM IR code adding the two values of an
+// AddOperationNode and returns the result together with its data type. Integers are added
+// with add, floating point values with fadd, and strings are concatenated into a new string, or at
+// compile time into a constant string if they are string literals.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// AddOperationNode: The abstract syntax tree (AST) node representing the add operation.
+//
+// Returns an error if the operands have different or non-numeric types.
+func generateAddValue(scope *Scope, functionBuilder llvm.Builder, addOperationNode *AddOperationNode) (llvm.Value, dataType, error) {
+	// Concatenations of string literals are evaluated at compile time
+	if value, ok := constantString(addOperationNode); ok {
+		return functionBuilder.CreateGlobalStringPtr(value, stringLiteralIdentifier), StringType, nil
+	}
+
+	leftValue, rightValue, operandType, err := generateOperands(scope, functionBuilder, addOperationNode.LeftValue, addOperationNode.RightValue)
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidAddOperation, err)
+	}
+
+	switch {
+	case operandType.isInteger():
+		// Create an add instruction to add left and right values
+		return functionBuilder.CreateAdd(leftValue, rightValue, ""), operandType, nil
+	case operandType.isFloat():
+		// Create a fadd instruction to add left and right values
+		return functionBuilder.CreateFAdd(leftValue, rightValue, ""), operandType, nil
+	case operandType == StringType:
+		// Call the runtime helper allocating the concatenated string
+		concatType, concat := stringConcatFunction(functionBuilder)
+		return functionBuilder.CreateCall(concatType, concat, []llvm.Value{leftValue, rightValue}, "concat"), operandType, nil
+	default:
+		return llvm.Value{}, 0, newError(MessageInvalidAddOperandType, operandType)
+	}
+}
+
+// constantString returns the string of the value if it is a string literal or a concatenation of
+// string literals, which is evaluated at compile time.
+func constantString(value Expr) (string, bool) {
+	switch v := value.(type) {
+	case *StringLiteralNode:
+		return v.Value, true
+	case *AddOperationNode:
+		left, ok := constantString(v.LeftValue)
+		if !ok {
+			return "", false
+		}
+		right, ok := constantString(v.RightValue)
+		return left + right, ok
+	}
+	return "", false
+}
+
+// generateShift is a function that generates LLVM IR code shifting the integer value of a
+// ShiftOperationNode by a number of bits and returns the result together with its data type.
+// Values are shifted to the left with shl and arithmetically to the right with ashr. The number of
+// bits may be of any integer type. A constant number of bits must be smaller than the bit width of
+// the value, other numbers of bits are masked to it, so a shift never produces a poison value. A
+// literal value without a known type takes the default type of the literal.
+//
+// scope:              A pointer to the current scope.
+// functionBuilder:    The LLVM builder associated with the current function.
+// shiftOperationNode: The abstract syntax tree (AST) node representing the shift operation.
+// t:                  A pointer to the data type of the value, nil to take it from the value.
+//
+// Returns an error if the value or the number of bits isn't an integer or the number of bits is
+// out of range.
+func generateShift(scope *Scope, functionBuilder llvm.Builder, shiftOperationNode *ShiftOperationNode, t *dataType) (llvm.Value, dataType, error) {
+	var value llvm.Value
+	var valueType dataType
+	var err error
+	if t != nil {
+		valueType = *t
+		value, err = generateTypedValue(scope, functionBuilder, shiftOperationNode.LeftValue, valueType)
+	} else {
+		value, valueType, err = generateValue(scope, functionBuilder, shiftOperationNode.LeftValue)
+	}
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidShiftOperation, err)
+	}
+	if !valueType.isInteger() {
+		return llvm.Value{}, 0, newError(MessageInvalidShiftOperation, newError(MessageShiftOperandType, valueType))
+	}
+
+	bits := dataTypeBits(valueType)
+	var amount llvm.Value
+	if _, ok := literalDataType(shiftOperationNode.RightValue); ok {
+		if literal, ok := integerLiteral(shiftOperationNode.RightValue); ok && (literal < 0 || literal >= int64(bits)) {
+			return llvm.Value{}, 0, newError(MessageInvalidShiftOperation, newError(MessageShiftAmount, literal, valueType))
+		}
+		amount, err = generateConstant(shiftOperationNode.RightValue, valueType)
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidShiftOperation, err)
+		}
+	} else {
+		var amountType dataType
+		amount, amountType, err = generateValue(scope, functionBuilder, shiftOperationNode.RightValue)
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidShiftOperation, err)
+		}
+		if !amountType.isInteger() {
+			return llvm.Value{}, 0, newError(MessageInvalidShiftOperation, newError(MessageShiftOperandType, amountType))
+		}
+
+		// Bring the number of bits to the type of the value and mask it to the bit width
+		switch {
+		case dataTypeBits(amountType) < bits:
+			amount = functionBuilder.CreateZExt(amount, llvmType(valueType), "")
+		case dataTypeBits(amountType) > bits:
+			amount = functionBuilder.CreateTrunc(amount, llvmType(valueType), "")
+		}
+		amount = functionBuilder.CreateAnd(amount, llvm.ConstInt(llvmType(valueType), uint64(bits-1), false), "shift_amount")
+	}
+
+	if shiftOperationNode.Left {
+		return functionBuilder.CreateShl(value, amount, ""), valueType, nil
+	}
+	return functionBuilder.CreateAShr(value, amount, ""), valueType, nil
+}
+
+// integerLiteral returns the value of an integer literal and whether the value is one.
+func integerLiteral(value Expr) (int64, bool) {
+	if literal, ok := value.(*IntLiteralNode); ok {
+		return literal.Value, true
+	}
+	return 0, false
+}
+
+// generateOperands is a function that generates LLVM IR code for both operands of a binary
+// operation and returns them together with their common data type. A literal operand takes
+// the type of the other operand, two literals take their default type.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// left:             The left operand taken from the abstract syntax tree (AST).
+// right:            The right operand taken from the abstract syntax tree (AST).
+//
+// Returns an error if the operands have different types.
+func generateOperands(scope *Scope, functionBuilder llvm.Builder, left Expr, right Expr) (llvm.Value, llvm.Value, dataType, error) {
+	_, leftIsLiteral := literalDataType(left)
+	_, rightIsLiteral := literalDataType(right)
+
+	if leftIsLiteral && !rightIsLiteral {
+		rightValue, rightType, err := generateValue(scope, functionBuilder, right)
+		if err != nil {
+			return llvm.Value{}, llvm.Value{}, 0, err
+		}
+		leftValue, err := generateConstant(left, rightType)
+		if err != nil {
+			return llvm.Value{}, llvm.Value{}, 0, err
+		}
+		return leftValue, rightValue, rightType, nil
+	}
+
+	leftValue, leftType, err := generateValue(scope, functionBuilder, left)
+	if err != nil {
+		return llvm.Value{}, llvm.Value{}, 0, err
+	}
+
+	rightValue, err := generateTypedValue(scope, functionBuilder, right, leftType)
+	if err != nil {
+		return llvm.Value{}, llvm.Value{}, 0, err
+	}
+
+	return leftValue, rightValue, leftType, nil
+}
+
+// generateFor is a function that generates LLVM IR code for a "for" loop in the form of "for i := init; i < limit; i++".
+// The function takes the initial value, limit, and body of the loop and generates the appropriate LLVM IR code.
+// The loop variable and the variables declared in the body can only be used in the loop.
+//
+// scope:            A pointer to the current scope containing local variables and function calls.
+// function:         The LLVM function value representing the current function.
+// functionBuilder:  The LLVM builder associated with the current function.
+// forNode:          The abstract syntax tree (AST) node representing the for loop.
+//
+// Returns an error if the value type of the loop variables is not supported.
+func generateFor(scope *Scope, function llvm.Value, functionBuilder llvm.Builder, forNode *ForNode) error {
+	// Check if the init value is an integer literal
+	initLiteral, ok := forNode.Init.Value.(*IntLiteralNode)
+	if !ok {
+		// Return an error if the value type is not supported
+		return newError(MessageInvalidInitValueType, forNode.Init.Value)
+	}
+	initValue := int32(initLiteral.Value)
+
+	// Define loop variables.
+	// Allocate memory for the loop variable in the current function
+	initAlloca := functionBuilder.CreateAlloca(globalScope.Context.Int32Type(), "for_init_"+forNode.Init.Identifier)
+	// Set the alignment of the allocated memory to 4 bytes
+	initAlloca.SetAlignment(4)
+	// Create a constant int32 LLVM value from the init value
+	initConst := llvm.ConstInt(globalScope.Context.Int32Type(), uint64(initValue), true)
+	// Store the constant int32 value in the allocated memory
+	functionBuilder.CreateStore(initConst, initAlloca)
+
+	// Declare the loop variable, which only the uses in the loop bind to, see Resolve
+	declareVariable(&forNode.Init, Variable{
+		Value: &initAlloca,
+		Type:  Integer32Type,
+	})
+
+	// Create basic blocks for the loop and the end of the loop
+	loopBlock := globalScope.Context.AddBasicBlock(function, "loop")
+	endBlock := globalScope.Context.AddBasicBlock(function, "end")
+
+	// Branch to loop condition from entry block
+	functionBuilder.CreateBr(loopBlock)
+
+	// Set the insertion point to the loop block
+	functionBuilder.SetInsertPointAtEnd(loopBlock)
+
+	// Generate all instructions in the loop body
+	err := generateStatements(scope, function, functionBuilder, forNode.Body)
+	if err != nil {
+		return err
 	}
 
 	// Load the current value of the loop variable
-	initValueFromLoad := functionBuilder.CreateLoad(llvm.Int32Type(), initAlloca, "for_init_"+forNode.Condition.LeftValue+"_value")
+	initValueFromLoad := functionBuilder.CreateLoad(globalScope.Context.Int32Type(), initAlloca, "for_init_"+forNode.Condition.LeftValue+"_value")
 
 	// Update the loop variable by incrementing it
 	var postValue int32
@@ -475,25 +2559,25 @@ func generateFor(scope *Scope, function llvm.Value, functionBuilder llvm.Builder
 		postValue = 1
 	}
 
-	updatedInit := functionBuilder.CreateAdd(initValueFromLoad, llvm.ConstInt(llvm.Int32Type(), uint64(postValue), false), "for_init_"+forNode.Condition.LeftValue+"_value_updated")
+	updatedInit := functionBuilder.CreateAdd(initValueFromLoad, llvm.ConstInt(globalScope.Context.Int32Type(), uint64(postValue), false), "for_init_"+forNode.Condition.LeftValue+"_value_updated")
 
 	// Store the updated loop variable back into memory
 	functionBuilder.CreateStore(updatedInit, initAlloca)
 
 	// Determine the loop limit
 	var limit int32
-	if l, ok := forNode.Condition.RightValue.(int32); ok {
-		limit = l
+	if l, ok := forNode.Condition.RightValue.(*IntLiteralNode); ok {
+		limit = int32(l.Value)
 	}
 
 	// Determine the loop condition based on the comparison operator
 	var predicate llvm.IntPredicate
-	if _, ok := forNode.Condition.Operator.(LessThanOperator); ok {
+	if forNode.Condition.Operator == OperatorLess {
 		predicate = llvm.IntULE
 	}
 
 	// Create the loop condition using the comparison operator and the loop limit
-	cond := functionBuilder.CreateICmp(predicate, updatedInit, llvm.ConstInt(llvm.Int32Type(), uint64(limit), false), "loopCond")
+	cond := functionBuilder.CreateICmp(predicate, updatedInit, llvm.ConstInt(globalScope.Context.Int32Type(), uint64(limit), false), "loopCond")
 
 	// Create a conditional branch to either the loop block or the end block
 	functionBuilder.CreateCondBr(cond, loopBlock, endBlock)
@@ -504,6 +2588,320 @@ func generateFor(scope *Scope, function llvm.Value, functionBuilder llvm.Builder
 	return nil
 }
 
+// generateValue is a function that generates LLVM IR code which produces the given value.
+// The value is either a literal (int32, int64, float64 or bool), the name of a local variable or
+// function argument, a cast of another value, an add operation, a function call or an array literal.
+// The type of an array literal is inferred from its first element and its number of elements.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// value:            The value taken from the abstract syntax tree (AST).
+//
+// Returns the LLVM value and its data type, or an error if the value is not supported.
+func generateValue(scope *Scope, functionBuilder llvm.Builder, value Expr) (llvm.Value, dataType, error) {
+	switch v := value.(type) {
+	case *IntLiteralNode:
+		t := v.dataType()
+		return llvm.ConstInt(llvmType(t), uint64(v.Value), true), t, nil
+	case *FloatLiteralNode:
+		return llvm.ConstFloat(globalScope.Context.DoubleType(), v.Value), Float64Type, nil
+	case *BoolLiteralNode:
+		var boolValue uint64
+		if v.Value {
+			boolValue = 1
+		}
+		return llvm.ConstInt(globalScope.Context.Int1Type(), boolValue, false), BoolType, nil
+	case *IdentifierNode:
+		symbol := lookupSymbol(v)
+		if symbol == nil {
+			return llvm.Value{}, 0, newError(MessageVariableNotFound, v.Name)
+		}
+		switch symbol.Kind {
+		case SymbolVariable:
+			if variable, ok := globalScope.Variables[symbol]; ok {
+				// Load the current value of the local variable
+				return loadVariable(functionBuilder, variable, v.Name+"Value"), variable.Type, nil
+			}
+		case SymbolParameter:
+			if argument, ok := scope.Arguments.Get(symbol.Name); ok {
+				return *argument.Value, argument.Type, nil
+			}
+		case SymbolEmbed:
+			if embed, ok := globalScope.Embeds.Get(symbol.Name); ok {
+				// Load the bytes of the embedded file
+				return functionBuilder.CreateLoad(llvmType(embed.Type), *embed.Value, v.Name+"Value"), embed.Type, nil
+			}
+		}
+		return llvm.Value{}, 0, newError(MessageVariableNotFound, v.Name)
+	case *CastNode:
+		castValue, castValueType, err := generateValue(scope, functionBuilder, v.Value)
+		if err != nil {
+			return llvm.Value{}, 0, err
+		}
+		return generateCast(functionBuilder, castValue, castValueType, v.Type)
+	case *AddOperationNode:
+		return generateAddValue(scope, functionBuilder, v)
+	case *ShiftOperationNode:
+		return generateShift(scope, functionBuilder, v, nil)
+	case *CallerNode:
+		return generateCall(scope, functionBuilder, v)
+	case *IndexNode:
+		address, element, _, err := generateIndexAddress(scope, functionBuilder, v)
+		if err != nil {
+			return llvm.Value{}, 0, err
+		}
+		return functionBuilder.CreateLoad(llvmType(element), address, ""), element, nil
+	case *FieldNode:
+		address, field, alignment, _, err := generateFieldAddress(scope, functionBuilder, v)
+		if err != nil {
+			return llvm.Value{}, 0, err
+		}
+		return loadAligned(functionBuilder, llvmType(field), address, alignment), field, nil
+	case *DereferenceNode:
+		address, element, _, err := generateAddress(scope, functionBuilder, v)
+		if err != nil {
+			return llvm.Value{}, 0, err
+		}
+		return functionBuilder.CreateLoad(llvmType(element), address, ""), element, nil
+	case *NewNode:
+		return generateNew(functionBuilder, v)
+	case *SpawnNode:
+		return generateSpawn(scope, functionBuilder, v)
+	case *NoneNode:
+		return llvm.Value{}, 0, newError(MessageUntypedNone)
+	case *StringLiteralNode:
+		return functionBuilder.CreateGlobalStringPtr(v.Value, stringLiteralIdentifier), StringType, nil
+	case *TryNode:
+		return generateTry(scope, functionBuilder, v)
+	case *ConditionalNode:
+		return generateConditional(scope, functionBuilder, v, nil)
+	case *ComparisonNode:
+		return generateComparison(scope, functionBuilder, v)
+	case *AddressNode:
+		address, element, assignable, err := generateAddress(scope, functionBuilder, v.Value)
+		if err != nil {
+			return llvm.Value{}, 0, err
+		}
+		if !assignable {
+			return llvm.Value{}, 0, newError(MessageAddressOfValue)
+		}
+		return address, pointerTo(element), nil
+	case *StructLiteralNode:
+		return generateStructLiteral(scope, functionBuilder, v)
+	case *ArrayLiteralNode:
+		if len(v.Elements) == 0 {
+			return llvm.Value{}, 0, newError(MessageEmptyArrayLiteral)
+		}
+		first, element, err := generateValue(scope, functionBuilder, v.Elements[0])
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidArrayElement, 1, err)
+		}
+		if element == VoidType {
+			return llvm.Value{}, 0, newError(MessageVoidCallAsValue)
+		}
+		t := arrayOf(element, len(v.Elements))
+		array := functionBuilder.CreateInsertValue(llvm.ConstNull(llvmType(t)), first, 0, "")
+		array, err = generateArrayElements(scope, functionBuilder, array, v.Elements[1:], 1, element)
+		if err != nil {
+			return llvm.Value{}, 0, err
+		}
+		return array, t, nil
+	default:
+		return llvm.Value{}, 0, newError(MessageInvalidValueType, value)
+	}
+}
+
+// generateCast is a function that generates LLVM IR code converting a value of one data type into another.
+// Integers are sign extended or truncated, booleans are zero extended into integers, and integers and
+// floating point values are converted into each other. Converting into a boolean, or between booleans and
+// floating point values, is rejected as an invalid cast.
+//
+// functionBuilder:  The LLVM builder associated with the current function.
+// value:            The LLVM value to convert.
+// from:             The data type of the value.
+// to:               The data type the value gets converted into.
+//
+// Returns the converted LLVM value and its data type, or an error if the cast is invalid.
+func generateCast(functionBuilder llvm.Builder, value llvm.Value, from dataType, to dataType) (llvm.Value, dataType, error) {
+	toType := llvmType(to)
+
+	switch {
+	case from == to:
+		return value, to, nil
+	case from.isInteger() && to.isInteger():
+		if dataTypeBits(from) < dataTypeBits(to) {
+			return functionBuilder.CreateSExt(value, toType, ""), to, nil
+		}
+		return functionBuilder.CreateTrunc(value, toType, ""), to, nil
+	case from == BoolType && to.isInteger():
+		return functionBuilder.CreateZExt(value, toType, ""), to, nil
+	case from.isInteger() && to.isFloat():
+		return functionBuilder.CreateSIToFP(value, toType, ""), to, nil
+	case from.isFloat() && to.isInteger():
+		return functionBuilder.CreateFPToSI(value, toType, ""), to, nil
+	case from.isFloat() && to.isFloat():
+		if dataTypeBits(from) < dataTypeBits(to) {
+			return functionBuilder.CreateFPExt(value, toType, ""), to, nil
+		}
+		return functionBuilder.CreateFPTrunc(value, toType, ""), to, nil
+	default:
+		return llvm.Value{}, 0, newError(MessageInvalidCast, from, to)
+	}
+}
+
+// generatePrintfArgument is a function that promotes a value passed to printf the same way C
+// promotes variadic arguments (small integers to i32, f32 to f64) and returns the promoted
+// value together with a pointer to the format string matching its data type.
+//
+// functionBuilder:  The LLVM builder associated with the current function.
+// value:            The LLVM value to print.
+// valueType:        The data type of the value.
+func generatePrintfArgument(functionBuilder llvm.Builder, value llvm.Value, valueType dataType) (llvm.Value, llvm.Value) {
+	value = promoteVariadicArgument(functionBuilder, value, valueType)
+
+	defaultFormat, _ := globalScope.Globals.Get(formatStringIdentifier)
+	formatGlobal := *defaultFormat.Value
+	if printfFormat, ok := printfFormats[valueType]; ok {
+		formatGlobal = formatStringGlobal(functionBuilder, printfFormat)
+	}
+
+	// Create a GEP for the format string
+	format := functionBuilder.CreateInBoundsGEP(formatGlobal.Type(), formatGlobal, []llvm.Value{llvm.ConstInt(globalScope.Context.Int32Type(), 0, false), llvm.ConstInt(globalScope.Context.Int32Type(), 0, false)}, "format")
+
+	return value, format
+}
+
+// promoteVariadicArgument promotes a value passed to a variadic C function the same way C does,
+// small integers and bools to i32 and f32 to f64.
+func promoteVariadicArgument(functionBuilder llvm.Builder, value llvm.Value, valueType dataType) llvm.Value {
+	switch valueType {
+	case BoolType:
+		return functionBuilder.CreateZExt(value, globalScope.Context.Int32Type(), "")
+	case Integer8Type, Integer16Type:
+		return functionBuilder.CreateSExt(value, globalScope.Context.Int32Type(), "")
+	case Float32Type:
+		return functionBuilder.CreateFPExt(value, globalScope.Context.DoubleType(), "")
+	}
+	return value
+}
+
+// formatStringGlobal returns the global holding the given printf format string. The global is
+// added to the module of the current function the first time it is requested.
+func formatStringGlobal(functionBuilder llvm.Builder, printfFormat printfFormat) llvm.Value {
+	module := functionBuilder.GetInsertBlock().Parent().GlobalParent()
+	if formatGlobal := module.NamedGlobal(printfFormat.Name); !formatGlobal.IsNil() {
+		return formatGlobal
+	}
+
+	formatString := globalScope.Context.ConstString(printfFormat.Format, true)
+	formatGlobal := llvm.AddGlobal(module, formatString.Type(), printfFormat.Name)
+	formatGlobal.SetInitializer(formatString)
+	formatGlobal.SetGlobalConstant(true)
+	globalScope.Globals.Set(printfFormat.Name, Global{
+		Value: &formatGlobal,
+	})
+
+	return formatGlobal
+}
+
+// llvmType returns the LLVM type used to represent values of the given data type.
+func llvmType(t dataType) llvm.Type {
+	switch t {
+	case Integer8Type:
+		return globalScope.Context.Int8Type()
+	case Integer16Type:
+		return globalScope.Context.Int16Type()
+	case Integer64Type:
+		return globalScope.Context.Int64Type()
+	case Float32Type:
+		return globalScope.Context.FloatType()
+	case Float64Type:
+		return globalScope.Context.DoubleType()
+	case BoolType:
+		return globalScope.Context.Int1Type()
+	case StringType:
+		return llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	case VoidType:
+		return globalScope.Context.VoidType()
+	}
+	if arrayType, ok := t.array(); ok {
+		return llvm.ArrayType(llvmType(arrayType.Element), arrayType.Length)
+	}
+	if _, ok := t.slice(); ok {
+		return sliceStructType()
+	}
+	if _, ok := t.pointer(); ok {
+		return llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	}
+	if optionType, ok := t.option(); ok {
+		return optionStructType(optionType)
+	}
+	if resultType, ok := t.result(); ok {
+		return resultStructType(resultType)
+	}
+	if structType, ok := t.structure(); ok {
+		if structure, ok := globalScope.Structs.Get(structType.Name); ok {
+			return structure.Type
+		}
+	}
+	return globalScope.Context.Int32Type()
+}
+
+// dataTypeAlignment returns the alignment in bytes of values of the given data type.
+// Arrays are aligned like their elements, slices like the pointer and lengths they hold and
+// structs like their most aligned field.
+func dataTypeAlignment(t dataType) int {
+	if arrayType, ok := t.array(); ok {
+		return dataTypeAlignment(arrayType.Element)
+	}
+	if _, ok := t.slice(); ok {
+		return 8
+	}
+	if _, ok := t.pointer(); ok || t == StringType {
+		return 8
+	}
+	if structType, ok := t.structure(); ok {
+		structure, _ := globalScope.Structs.Get(structType.Name)
+		if structure.Alignment != 0 {
+			return structure.Alignment
+		}
+		alignment := 1
+		for _, field := range structure.Fields {
+			if fieldAlignment := dataTypeAlignment(field.Type); fieldAlignment > alignment {
+				alignment = fieldAlignment
+			}
+		}
+		return alignment
+	}
+	if optionType, ok := t.option(); ok {
+		return dataTypeAlignment(optionType.Element)
+	}
+	if resultType, ok := t.result(); ok {
+		if alignment := dataTypeAlignment(resultType.Element); alignment > 4 {
+			return alignment
+		}
+		return 4
+	}
+	if t == BoolType {
+		return 1
+	}
+	return dataTypeBits(t) / 8
+}
+
+// dataTypeBits returns the width in bits of a numeric data type.
+func dataTypeBits(t dataType) int {
+	switch t {
+	case Integer8Type:
+		return 8
+	case Integer16Type:
+		return 16
+	case Integer64Type, Float64Type:
+		return 64
+	default:
+		return 32
+	}
+}
+
 var GenerateRandomIdentifier = func() string {
 	return uuid.New().String()
 }
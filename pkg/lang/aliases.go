@@ -0,0 +1,197 @@
+package lang
+
+// typeAliasResolver replaces the type aliases declared at the top level of a program with their
+// data types. Aliases resolve structurally: an alias is the same type as its data type, so values
+// of both types can be used interchangeably.
+type typeAliasResolver struct {
+	declared  map[string]dataType // The data type of every alias as declared.
+	resolved  map[string]dataType // The data type of every alias with all aliases it uses resolved.
+	resolving map[string]bool     // The aliases being resolved, to detect aliases referring to themselves.
+	err       error               // The first error encountered.
+}
+
+// resolveTypeAliases replaces every use of a type alias declared at the top level of the nodes with
+// the data type of the alias. The function name of a call of an alias becomes a cast into its data
+// type, e.g. Index(x), and the name of a struct literal of an alias of a struct the struct name.
+//
+// Returns an error if a type is declared more than once or an alias refers to itself.
+func resolveTypeAliases(nodes []Node) error {
+	r := typeAliasResolver{declared: make(map[string]dataType), resolved: make(map[string]dataType), resolving: make(map[string]bool)}
+	structs := make(map[string]bool)
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *StructNode:
+			structs[n.Name] = true
+		case *TypeAliasNode:
+			if _, ok := r.declared[n.Name]; ok {
+				return newError(MessageDuplicateTypeName, n.Name)
+			}
+			r.declared[n.Name] = n.Type
+		}
+	}
+	if len(r.declared) == 0 {
+		return nil
+	}
+	for name := range r.declared {
+		if structs[name] {
+			return newError(MessageDuplicateTypeName, name)
+		}
+	}
+
+	r.resolveNodes(nodes)
+	return r.err
+}
+
+// resolveAlias returns the data type of the alias with all aliases it uses resolved.
+func (r *typeAliasResolver) resolveAlias(name string) dataType {
+	if t, ok := r.resolved[name]; ok {
+		return t
+	}
+	if r.resolving[name] {
+		if r.err == nil {
+			r.err = newError(MessageTypeAliasCycle, name)
+		}
+		return r.declared[name]
+	}
+
+	r.resolving[name] = true
+	t := r.resolveType(r.declared[name])
+	delete(r.resolving, name)
+	r.resolved[name] = t
+	return t
+}
+
+// resolveType returns the data type with every alias it uses resolved.
+func (r *typeAliasResolver) resolveType(t dataType) dataType {
+	if arrayType, ok := t.array(); ok {
+		return arrayOf(r.resolveType(arrayType.Element), arrayType.Length)
+	}
+	if sliceType, ok := t.slice(); ok {
+		return sliceOf(r.resolveType(sliceType.Element))
+	}
+	if pointerType, ok := t.pointer(); ok {
+		return pointerTo(r.resolveType(pointerType.Element))
+	}
+	if optionType, ok := t.option(); ok {
+		return optionOf(r.resolveType(optionType.Element))
+	}
+	if resultType, ok := t.result(); ok {
+		return resultOf(r.resolveType(resultType.Element))
+	}
+	if structType, ok := t.structure(); ok {
+		if _, ok := r.declared[structType.Name]; ok {
+			return r.resolveAlias(structType.Name)
+		}
+	}
+	return t
+}
+
+// resolveNodes resolves the aliases used by the nodes in place.
+func (r *typeAliasResolver) resolveNodes(nodes []Node) {
+	for _, node := range nodes {
+		r.resolveNode(node)
+	}
+}
+
+// resolveNode resolves the aliases used by the node in place.
+func (r *typeAliasResolver) resolveNode(node any) {
+	switch n := node.(type) {
+	case *TypeAliasNode:
+		n.Type = r.resolveType(n.Type)
+	case *StructNode:
+		for _, field := range n.Fields {
+			field.Type = r.resolveType(field.Type)
+		}
+	case *FunctionNode:
+		for _, parameter := range n.Parameters {
+			parameter.Type = r.resolveType(parameter.Type)
+		}
+		n.ReturnType = r.resolveType(n.ReturnType)
+		r.resolveNodes(n.Body)
+	case *ExternNode:
+		for _, parameter := range n.Parameters {
+			parameter.Type = r.resolveType(parameter.Type)
+		}
+		n.ReturnType = r.resolveType(n.ReturnType)
+	case *LetNode:
+		n.Type = r.resolveType(n.Type)
+		n.Value = r.resolveValue(n.Value)
+	case *AssignmentNode:
+		n.Value = r.resolveValue(n.Value)
+	case *IndexAssignmentNode:
+		r.resolveNode(n.Target)
+		n.Value = r.resolveValue(n.Value)
+	case *FieldAssignmentNode:
+		r.resolveNode(n.Target)
+		n.Value = r.resolveValue(n.Value)
+	case *DereferenceAssignmentNode:
+		r.resolveNode(n.Target)
+		n.Value = r.resolveValue(n.Value)
+	case *ReturnNode:
+		n.Value = r.resolveValue(n.Value)
+	case *CallerNode:
+		for i, arg := range n.Args {
+			n.Args[i] = r.resolveValue(arg)
+		}
+	case *SpawnNode:
+		r.resolveNode(n.Call)
+	case *AddOperationNode:
+		n.LeftValue = r.resolveValue(n.LeftValue)
+		n.RightValue = r.resolveValue(n.RightValue)
+	case *ShiftOperationNode:
+		n.LeftValue = r.resolveValue(n.LeftValue)
+		n.RightValue = r.resolveValue(n.RightValue)
+	case *CastNode:
+		n.Type = r.resolveType(n.Type)
+		n.Value = r.resolveValue(n.Value)
+	case *NewNode:
+		n.Type = r.resolveType(n.Type)
+	case *IndexNode:
+		n.Value = r.resolveValue(n.Value)
+		n.Index = r.resolveValue(n.Index)
+	case *FieldNode:
+		n.Value = r.resolveValue(n.Value)
+	case *AddressNode:
+		n.Value = r.resolveValue(n.Value)
+	case *DereferenceNode:
+		n.Value = r.resolveValue(n.Value)
+	case *TryNode:
+		n.Value = r.resolveValue(n.Value)
+	case *ComparisonNode:
+		n.LeftValue = r.resolveValue(n.LeftValue)
+		n.RightValue = r.resolveValue(n.RightValue)
+	case *ConditionalNode:
+		n.Condition = r.resolveValue(n.Condition)
+		n.True = r.resolveValue(n.True)
+		n.False = r.resolveValue(n.False)
+	case *ArrayLiteralNode:
+		for i, element := range n.Elements {
+			n.Elements[i] = r.resolveValue(element)
+		}
+	case *StructLiteralNode:
+		if structType, ok := r.resolveType(structOf(n.Name)).structure(); ok {
+			n.Name = structType.Name
+		}
+		for _, field := range n.Fields {
+			field.Value = r.resolveValue(field.Value)
+		}
+	case *ForNode:
+		n.Init.Value = r.resolveValue(n.Init.Value)
+		n.Condition.RightValue = r.resolveValue(n.Condition.RightValue)
+		r.resolveNodes(n.Body)
+	case *WhileNode:
+		r.resolveNodes(n.Body)
+	}
+}
+
+// resolveValue resolves the aliases used by the value and returns the value, which is replaced
+// by a cast if it is a call of an alias.
+func (r *typeAliasResolver) resolveValue(value Expr) Expr {
+	if callerNode, ok := value.(*CallerNode); ok && len(callerNode.Args) == 1 {
+		if _, ok := r.declared[callerNode.FunctionName]; ok {
+			return &CastNode{Type: r.resolveAlias(callerNode.FunctionName), Value: r.resolveValue(callerNode.Args[0])}
+		}
+	}
+	r.resolveNode(value)
+	return value
+}
@@ -0,0 +1,112 @@
+package lang
+
+import (
+	"tinygo.org/x/go-llvm"
+)
+
+// Constants for the identifiers of the builtin functions reading the command-line arguments.
+const (
+	argsCountIdentifier = "args_count"
+	argIdentifier       = "arg"
+)
+
+// Constants for the identifiers of the globals holding the parameters of the main function.
+const (
+	// argcIdentifier is the identifier of the global holding the number of command-line arguments.
+	argcIdentifier = runtimePrefix + "argc"
+	// argvIdentifier is the identifier of the global holding the array of command-line arguments.
+	argvIdentifier = runtimePrefix + "argv"
+)
+
+// usesArguments reports whether the program refers to the args_count or arg builtin. Names are
+// resolved like the minifier sees them, so a variable named arg conservatively counts as a use.
+func usesArguments(nodes []Node) bool {
+	used := false
+	var m minifier
+	m.walkNodes(nodes, func(name *string, declaration bool) {
+		if !declaration && (*name == argsCountIdentifier || *name == argIdentifier) {
+			used = true
+		}
+	})
+	return used
+}
+
+// generateMainArguments stores the parameters argc and argv of the main function into the globals
+// the args_count and arg builtins read them from, so they are available in every function.
+//
+// module:       The LLVM module the globals are added to.
+// mainFunc:     The main function, which takes argc and argv.
+// mainBuilder:  The LLVM builder associated with the entry block of the main function.
+func generateMainArguments(module llvm.Module, mainFunc llvm.Value, mainBuilder llvm.Builder) {
+	mainBuilder.CreateStore(mainFunc.Param(0), argumentsGlobal(module, argcIdentifier, globalScope.Context.Int32Type()))
+	mainBuilder.CreateStore(mainFunc.Param(1), argumentsGlobal(module, argvIdentifier, llvm.PointerType(globalScope.Context.Int8Type(), 0)))
+}
+
+// argumentsGlobal returns the global of the module holding a parameter of the main function. The
+// global is added the first time it is requested and is zero until main stores the parameter.
+func argumentsGlobal(module llvm.Module, name string, t llvm.Type) llvm.Value {
+	if global := module.NamedGlobal(name); !global.IsNil() {
+		return global
+	}
+	global := llvm.AddGlobal(module, t, name)
+	global.SetInitializer(llvm.ConstNull(t))
+	global.SetLinkage(llvm.InternalLinkage)
+	return global
+}
+
+// generateArguments is a function that generates LLVM IR code for a call of the args_count or arg
+// builtin. args_count returns the number of command-line arguments as i64, including the name of
+// the program. arg returns the command-line argument at the index, which may be any integer, as a
+// string; arg(0) is the name of the program. Bounds checks trap if the index is out of range.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+//
+// Returns an error if the number of parameters doesn't match or the index isn't an integer.
+func generateArguments(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	module := functionBuilder.GetInsertBlock().Parent().GlobalParent()
+	int32Type := globalScope.Context.Int32Type()
+	int64Type := globalScope.Context.Int64Type()
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+
+	if callerNode.FunctionName == argsCountIdentifier {
+		if len(callerNode.Args) != 0 {
+			return llvm.Value{}, 0, newError(MessageExpectedNoParameters, callerNode.FunctionName, len(callerNode.Args))
+		}
+		argc := functionBuilder.CreateLoad(int32Type, argumentsGlobal(module, argcIdentifier, int32Type), "argc")
+		return functionBuilder.CreateSExt(argc, int64Type, ""), Integer64Type, nil
+	}
+
+	if len(callerNode.Args) != 1 {
+		return llvm.Value{}, 0, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+	}
+	var index llvm.Value
+	var err error
+	if _, ok := literalDataType(callerNode.Args[0]); ok {
+		index, err = generateConstant(callerNode.Args[0], Integer64Type)
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidArrayIndex, err)
+		}
+	} else {
+		var indexType dataType
+		index, indexType, err = generateValue(scope, functionBuilder, callerNode.Args[0])
+		if err != nil {
+			return llvm.Value{}, 0, err
+		}
+		if !indexType.isInteger() {
+			return llvm.Value{}, 0, newError(MessageInvalidArrayIndexType, indexType)
+		}
+		if indexType != Integer64Type {
+			index = functionBuilder.CreateSExt(index, int64Type, "")
+		}
+	}
+
+	if globalScope.Options.BoundsChecks {
+		argc := functionBuilder.CreateLoad(int32Type, argumentsGlobal(module, argcIdentifier, int32Type), "argc")
+		generateBoundsCheck(functionBuilder, index, functionBuilder.CreateSExt(argc, int64Type, ""))
+	}
+	argv := functionBuilder.CreateLoad(pointerType, argumentsGlobal(module, argvIdentifier, pointerType), "argv")
+	address := functionBuilder.CreateInBoundsGEP(pointerType, argv, []llvm.Value{index}, "")
+	return functionBuilder.CreateLoad(pointerType, address, "arg"), StringType, nil
+}
@@ -0,0 +1,121 @@
+package lang
+
+// builtinSignature describes the parameters and the result of a builtin function for the type
+// checker: it takes at least as many parameters as it has data types, or exactly as many unless it
+// is variadic. A parameter of unknownType takes any data type of the kind of the builtin.
+type builtinSignature struct {
+	parameters []dataType
+	variadic   bool
+	kind       builtinKind // The kind of the data types the parameters of unknownType take.
+	// result is the data type of the result, or unknownType if it depends on the parameters. The
+	// math builtins return the data type of their parameters which aren't literals.
+	result dataType
+}
+
+// builtinKind is a kind of data types the parameters of a builtin take.
+type builtinKind int
+
+const (
+	anyKind      builtinKind = iota // Any data type.
+	lengthKind                      // Arrays, slices and strings.
+	capacityKind                    // Arrays and slices.
+	numericKind                     // Integer and floating point types.
+	floatKind                       // Floating point types.
+	pointerKind                     // Pointers and slices.
+)
+
+// builtinSignatures holds the signatures of the builtin functions by identifier.
+var builtinSignatures = map[string]builtinSignature{
+	printfIndentifier:     {parameters: []dataType{unknownType}, result: Integer32Type},
+	printIdentifier:       {parameters: []dataType{unknownType}, result: Integer32Type},
+	printlnIdentifier:     {parameters: []dataType{unknownType}, result: Integer32Type},
+	formatIdentifier:      {parameters: []dataType{StringType}, variadic: true, result: StringType},
+	readIntIdentifier:     {result: Integer64Type},
+	readLineIdentifier:    {result: StringType},
+	argsCountIdentifier:   {result: Integer64Type},
+	argIdentifier:         {parameters: []dataType{unknownType}, result: StringType},
+	exitIdentifier:        {parameters: []dataType{Integer32Type}, result: VoidType},
+	joinIdentifier:        {parameters: []dataType{Integer64Type}, result: VoidType},
+	lenIdentifier:         {parameters: []dataType{unknownType}, kind: lengthKind, result: Integer64Type},
+	capIdentifier:         {parameters: []dataType{unknownType}, kind: capacityKind, result: Integer64Type},
+	appendIdentifier:      {parameters: []dataType{unknownType, unknownType}, variadic: true, result: unknownType},
+	freeIdentifier:        {parameters: []dataType{unknownType}, kind: pointerKind, result: VoidType},
+	someIdentifier:        {parameters: []dataType{unknownType}, result: unknownType},
+	unwrapOrIdentifier:    {parameters: []dataType{unknownType, unknownType}, result: unknownType},
+	okIdentifier:          {parameters: []dataType{unknownType}, result: unknownType},
+	errIdentifier:         {parameters: []dataType{Integer32Type}, result: unknownType},
+	atomicAddIdentifier:   {parameters: []dataType{unknownType, unknownType}, result: unknownType},
+	atomicLoadIdentifier:  {parameters: []dataType{unknownType}, result: unknownType},
+	atomicStoreIdentifier: {parameters: []dataType{unknownType, unknownType}, result: VoidType},
+	mutexIdentifier:       {result: Integer64Type},
+	lockIdentifier:        {parameters: []dataType{Integer64Type}, result: VoidType},
+	unlockIdentifier:      {parameters: []dataType{Integer64Type}, result: VoidType},
+	absIdentifier:         {parameters: []dataType{unknownType}, kind: numericKind, result: unknownType},
+	minIdentifier:         {parameters: []dataType{unknownType, unknownType}, kind: numericKind, result: unknownType},
+	maxIdentifier:         {parameters: []dataType{unknownType, unknownType}, kind: numericKind, result: unknownType},
+	powIdentifier:         {parameters: []dataType{unknownType, unknownType}, kind: floatKind, result: unknownType},
+	sqrtIdentifier:        {parameters: []dataType{unknownType}, kind: floatKind, result: unknownType},
+}
+
+// shadowableBuiltins holds the builtins which a function of the program with the same name is
+// called instead of. The other builtins are called even if the program declares such a function.
+var shadowableBuiltins = map[string]bool{
+	exitIdentifier: true, joinIdentifier: true, formatIdentifier: true, atomicAddIdentifier: true, atomicLoadIdentifier: true,
+	atomicStoreIdentifier: true, mutexIdentifier: true, lockIdentifier: true, unlockIdentifier: true, absIdentifier: true,
+	minIdentifier: true, maxIdentifier: true, powIdentifier: true, sqrtIdentifier: true,
+}
+
+// callsBuiltin reports whether a call of the function with the name calls a builtin, given
+// whether the program declares a function with the name.
+func callsBuiltin(name string, declared bool) bool {
+	if _, ok := builtinSignatures[name]; !ok {
+		return false
+	}
+	return !declared || !shadowableBuiltins[name]
+}
+
+// arityError returns the error about a call of the builtin with the signature with the given
+// number of parameters, or nil if the number matches.
+func (s builtinSignature) arityError(name string, parameters int) error {
+	expected := len(s.parameters)
+	switch {
+	case s.variadic && parameters >= expected, !s.variadic && parameters == expected:
+		return nil
+	case name == appendIdentifier:
+		return newError(MessageExpectedSliceAndValues, name, parameters)
+	case name == formatIdentifier:
+		return newError(MessageExpectedFormatString, name)
+	case expected == 0:
+		return newError(MessageExpectedNoParameters, name, parameters)
+	case expected == 1:
+		return newError(MessageExpectedOneParameter, name, parameters)
+	case expected == 2:
+		return newError(MessageExpectedTwoParameters, name, parameters)
+	}
+	return newError(MessageExpectedParameters, expected, name, parameters)
+}
+
+// kindError returns the error about a parameter of the data type of a call of the builtin with the
+// name, or nil if the kind of the builtin takes the data type.
+func (k builtinKind) kindError(name string, t dataType) error {
+	_, isArray := t.array()
+	_, isSlice := t.slice()
+	_, isPointer := t.pointer()
+	switch {
+	case k == lengthKind && !isArray && !isSlice && t != StringType, k == capacityKind && !isArray && !isSlice:
+		return newError(MessageInvalidBuiltinValue, t, name)
+	case k == numericKind && !t.isInteger() && !t.isFloat():
+		return newError(MessageMathType, name, t)
+	case k == floatKind && !t.isFloat():
+		return newError(MessageMathFloatType, name, t)
+	case k == pointerKind && !isPointer && !isSlice:
+		return newError(MessageFreeType, t)
+	}
+	return nil
+}
+
+// isMath reports whether the kind is the kind of the math builtins, whose parameters which are
+// literals take the data type of the other parameters.
+func (k builtinKind) isMath() bool {
+	return k == numericKind || k == floatKind
+}
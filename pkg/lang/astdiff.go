@@ -0,0 +1,144 @@
+package lang
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// nodeType is the type of the Node interface, which the fields and slices holding child nodes
+// implement.
+var nodeType = reflect.TypeOf((*Node)(nil)).Elem()
+
+// DiffAST compares the abstract syntax trees of two parses node by node and returns the nodes
+// which were removed, changed or added, in the order of the source code, e.g. to test that a
+// change of the parser only changes the expected nodes. Unlike SemanticDiff, it reports the
+// innermost nodes which differ: a change names the kind of the node and its path from the
+// program, e.g. [2].Body[0].Value for the value of the first statement of the third top-level
+// node, and holds the nodes before and after the change. The last index of the path of a removed
+// node is its index in the old slice, else in the new one. Nodes which only moved in the source
+// code are equal, as spans aren't compared.
+func DiffAST(a, b []Node) []Change {
+	var changes []Change
+	diffSlices("", reflect.ValueOf(a), reflect.ValueOf(b), &changes)
+	return changes
+}
+
+// diffNodes appends the changes between the nodes at the path to changes. Either node may be nil.
+func diffNodes(path string, a, b Node, changes *[]Change) {
+	switch {
+	case isNilNode(a) && isNilNode(b):
+		return
+	case isNilNode(a):
+		*changes = append(*changes, Change{Kind: ChangeAdded, Declaration: nodeKind(b), Name: path, After: b})
+		return
+	case isNilNode(b):
+		*changes = append(*changes, Change{Kind: ChangeRemoved, Declaration: nodeKind(a), Name: path, Before: a})
+		return
+	case equalSyntax(a, b):
+		return
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		detail := fmt.Sprintf("replaced by %s", nodeKind(b))
+		*changes = append(*changes, Change{Kind: ChangeChanged, Declaration: nodeKind(a), Name: path, Detail: detail, Before: a, After: b})
+		return
+	}
+
+	// Report the changed fields of the node itself before the changes of its child nodes
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	var details []string
+	var children []func()
+	for i := 0; i < va.NumField(); i++ {
+		field := va.Type().Field(i)
+		fa, fb := va.Field(i), vb.Field(i)
+		if field.Type == spanType || !field.IsExported() || equalValues(fa, fb) {
+			continue
+		}
+		fieldPath := path + "." + field.Name
+		switch {
+		case field.Type.Implements(nodeType):
+			children = append(children, func() { diffNodes(fieldPath, nodeOf(fa), nodeOf(fb), changes) })
+		case reflect.PointerTo(field.Type).Implements(nodeType):
+			children = append(children, func() { diffNodes(fieldPath, fa.Addr().Interface().(Node), fb.Addr().Interface().(Node), changes) })
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Implements(nodeType):
+			children = append(children, func() { diffSlices(fieldPath, fa, fb, changes) })
+		case field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Pointer:
+			details = append(details, field.Name+" changed")
+		default:
+			details = append(details, fmt.Sprintf("%s changed from %v to %v", field.Name, fa.Interface(), fb.Interface()))
+		}
+	}
+	if len(details) > 0 {
+		*changes = append(*changes, Change{Kind: ChangeChanged, Declaration: nodeKind(a), Name: path, Detail: strings.Join(details, ", "), Before: a, After: b})
+	}
+	for _, diffChild := range children {
+		diffChild()
+	}
+}
+
+// diffSlices appends the changes between the slices of nodes at the path to changes. The nodes
+// both slices have in common are matched by a longest common subsequence, the other nodes
+// between two matches are compared pairwise, and the remaining ones were removed or added.
+func diffSlices(path string, a, b reflect.Value, changes *[]Change) {
+	n, m := a.Len(), b.Len()
+
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, n+1)
+	for i := range common {
+		common[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if equalValues(a.Index(i), b.Index(j)) {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		if i < n && j < m && equalValues(a.Index(i), b.Index(j)) {
+			i, j = i+1, j+1
+			continue
+		}
+
+		// Find the nodes up to the next match, which were changed, removed or added
+		endA, endB := i, j
+		for endA < n && endB < m && !equalValues(a.Index(endA), b.Index(endB)) {
+			if common[endA+1][endB] >= common[endA][endB+1] {
+				endA++
+			} else {
+				endB++
+			}
+		}
+		if endA == n || endB == m {
+			endA, endB = n, m
+		}
+		for ; i < endA && j < endB; i, j = i+1, j+1 {
+			diffNodes(fmt.Sprintf("%s[%d]", path, j), nodeOf(a.Index(i)), nodeOf(b.Index(j)), changes)
+		}
+		for ; i < endA; i++ {
+			diffNodes(fmt.Sprintf("%s[%d]", path, i), nodeOf(a.Index(i)), nil, changes)
+		}
+		for ; j < endB; j++ {
+			diffNodes(fmt.Sprintf("%s[%d]", path, j), nil, nodeOf(b.Index(j)), changes)
+		}
+	}
+}
+
+// nodeOf returns the node held by the value of a field or slice element, or nil.
+func nodeOf(v reflect.Value) Node {
+	if v.Kind() == reflect.Interface && v.IsNil() {
+		return nil
+	}
+	return v.Interface().(Node)
+}
+
+// nodeKind returns the kind of the node, which is the name of its type, e.g. LetNode.
+func nodeKind(node Node) string {
+	return reflect.TypeOf(node).Elem().Name()
+}
@@ -0,0 +1,55 @@
+package lang
+
+import "tinygo.org/x/go-llvm"
+
+// Budget bounds the size of the code generated for a program, e.g. for environments where the
+// size of the code has to stay small. The instructions and basic blocks are counted in the LLVM
+// module after the module passes have run. A zero limit doesn't bound the size.
+type Budget struct {
+	FunctionInstructions int // The maximum number of instructions of a function, including main.
+	FunctionBlocks       int // The maximum number of basic blocks of a function, including main.
+	Instructions         int // The maximum number of instructions of the whole program.
+	Blocks               int // The maximum number of basic blocks of the whole program.
+	// Warn reports every exceeded limit to the OnDiagnostic hook instead of failing the compilation.
+	Warn bool
+}
+
+// limited reports whether any limit of the budget is set.
+func (b Budget) limited() bool {
+	return b.FunctionInstructions > 0 || b.FunctionBlocks > 0 || b.Instructions > 0 || b.Blocks > 0
+}
+
+// checkBudget returns an error for every limit of the budget the defined functions of the module exceed.
+func checkBudget(module llvm.Module, budget Budget) []error {
+	var errs []error
+	var instructions, blocks int
+	for function := module.FirstFunction(); !function.IsNil(); function = llvm.NextFunction(function) {
+		if function.IsDeclaration() {
+			continue
+		}
+
+		var functionInstructions, functionBlocks int
+		for block := function.FirstBasicBlock(); !block.IsNil(); block = llvm.NextBasicBlock(block) {
+			functionBlocks++
+			for instruction := block.FirstInstruction(); !instruction.IsNil(); instruction = llvm.NextInstruction(instruction) {
+				functionInstructions++
+			}
+		}
+		if budget.FunctionInstructions > 0 && functionInstructions > budget.FunctionInstructions {
+			errs = append(errs, newError(MessageFunctionInstructionBudget, function.Name(), functionInstructions, budget.FunctionInstructions))
+		}
+		if budget.FunctionBlocks > 0 && functionBlocks > budget.FunctionBlocks {
+			errs = append(errs, newError(MessageFunctionBlockBudget, function.Name(), functionBlocks, budget.FunctionBlocks))
+		}
+		instructions += functionInstructions
+		blocks += functionBlocks
+	}
+
+	if budget.Instructions > 0 && instructions > budget.Instructions {
+		errs = append(errs, newError(MessageInstructionBudget, instructions, budget.Instructions))
+	}
+	if budget.Blocks > 0 && blocks > budget.Blocks {
+		errs = append(errs, newError(MessageBlockBudget, blocks, budget.Blocks))
+	}
+	return errs
+}
@@ -0,0 +1,170 @@
+package lang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The constructors below build the nodes of a program in Go code rather than parsing source code,
+// e.g. for embedders generating programs, which pass the nodes straight to GenerateLLVMIR:
+//
+//	nodes := []Node{
+//		NewFunc("add", []*Parameter{NewParam("a", Integer32Type), NewParam("b", Integer32Type)}, Integer32Type,
+//			NewReturn(NewAdd(Ident("a"), Ident("b")))),
+//		NewLet("x", NewCall("add", Int(2), Int(3))),
+//		NewCall("printf", Ident("x")),
+//	}
+//
+// They validate their arguments like the parser validates source code, and panic if they are
+// invalid, e.g. an identifier which is a keyword or a reserved word, or a nil value. The nodes
+// have an unknown span.
+
+// Int returns an integer literal with the given value.
+func Int(value int64) *IntLiteralNode {
+	return &IntLiteralNode{Value: value}
+}
+
+// Float returns a floating point literal with the given value.
+func Float(value float64) *FloatLiteralNode {
+	return &FloatLiteralNode{Value: value}
+}
+
+// Bool returns the literal true or false.
+func Bool(value bool) *BoolLiteralNode {
+	return &BoolLiteralNode{Value: value}
+}
+
+// String returns a string literal with the given value.
+func String(value string) *StringLiteralNode {
+	return &StringLiteralNode{Value: value}
+}
+
+// Ident returns the name of a variable or parameter used as value.
+func Ident(name string) *IdentifierNode {
+	mustIdentifier("Ident", name)
+	return &IdentifierNode{Name: name}
+}
+
+// NewLet returns the declaration of a variable with the given value. A literal value gives the
+// variable its default type, the type of any other value is inferred during code generation.
+func NewLet(identifier string, value Expr) *LetNode {
+	mustIdentifier("NewLet", identifier)
+	mustValue("NewLet", value)
+	letNode := &LetNode{Identifier: identifier, Value: value}
+	if literalType, ok := literalDataType(value); ok {
+		letNode.Type = literalType
+	}
+	return letNode
+}
+
+// NewTypedLet returns the declaration of a variable of the given data type with the given value.
+func NewTypedLet(identifier string, letType dataType, value Expr) *LetNode {
+	mustIdentifier("NewTypedLet", identifier)
+	mustType("NewTypedLet", letType)
+	mustValue("NewTypedLet", value)
+	return &LetNode{Identifier: identifier, Type: letType, HasType: true, Value: value}
+}
+
+// NewAssign returns the assignment of a value to an existing variable.
+func NewAssign(identifier string, value Expr) *AssignmentNode {
+	mustIdentifier("NewAssign", identifier)
+	mustValue("NewAssign", value)
+	return &AssignmentNode{Identifier: identifier, Value: value}
+}
+
+// NewAdd returns the addition of two values.
+func NewAdd(left, right Expr) *AddOperationNode {
+	mustValue("NewAdd", left)
+	mustValue("NewAdd", right)
+	return &AddOperationNode{LeftValue: left, RightValue: right}
+}
+
+// NewCompare returns the comparison of two values with the operator.
+func NewCompare(left Expr, operator ComparisonOperator, right Expr) *ComparisonNode {
+	mustValue("NewCompare", left)
+	mustValue("NewCompare", right)
+	if operator < OperatorEqual || operator > OperatorGreaterEqual {
+		panic(fmt.Sprintf("lang: NewCompare with invalid operator %d", operator))
+	}
+	return &ComparisonNode{LeftValue: left, RightValue: right, Operator: operator}
+}
+
+// NewCast returns the conversion of a value to the given data type.
+func NewCast(castType dataType, value Expr) *CastNode {
+	mustType("NewCast", castType)
+	mustValue("NewCast", value)
+	return &CastNode{Type: castType, Value: value}
+}
+
+// NewCall returns the call of the function with the given name, e.g. printf, with the arguments.
+// The name of a function of another package is qualified with the package, e.g. math.abs.
+func NewCall(functionName string, args ...Expr) *CallerNode {
+	for _, name := range strings.SplitN(functionName, string(TokenDot), 2) {
+		mustIdentifier("NewCall", name)
+	}
+	for _, arg := range args {
+		mustValue("NewCall", arg)
+	}
+	return &CallerNode{FunctionName: functionName, Args: args}
+}
+
+// NewReturn returns the return of the value from a function, or of no value if it is nil.
+func NewReturn(value Expr) *ReturnNode {
+	return &ReturnNode{Value: value}
+}
+
+// NewParam returns a parameter of a function of the given data type.
+func NewParam(identifier string, paramType dataType) *Parameter {
+	mustIdentifier("NewParam", identifier)
+	mustType("NewParam", paramType)
+	return &Parameter{Identifier: identifier, Type: paramType}
+}
+
+// NewFunc returns the definition of the function with the given name, parameters, return type
+// and body. A function without result returns VoidType.
+func NewFunc(name string, parameters []*Parameter, returnType dataType, body ...Node) *FunctionNode {
+	mustIdentifier("NewFunc", name)
+	declared := make(map[string]bool, len(parameters))
+	for _, parameter := range parameters {
+		if parameter == nil {
+			panic("lang: NewFunc with nil parameter")
+		}
+		if declared[parameter.Identifier] {
+			panic(fmt.Sprintf("lang: NewFunc with duplicate parameter %q", parameter.Identifier))
+		}
+		declared[parameter.Identifier] = true
+	}
+	for _, node := range body {
+		if node == nil {
+			panic("lang: NewFunc with nil statement")
+		}
+	}
+	return &FunctionNode{Name: name, Parameters: parameters, ReturnType: returnType, Body: body}
+}
+
+// mustIdentifier panics if the name, passed to the constructor, isn't tokenized as a single
+// identifier, e.g. because it is empty, a number or a keyword, or if it is a reserved word or uses
+// the namespace reserved for generated symbols.
+func mustIdentifier(constructor, name string) {
+	tokens := Tokenize(name)
+	if len(tokens) != 1 || tokens[0].Type != TokenIdentifierType || tokens[0].Value != name || isNumberWord(name) {
+		panic(fmt.Sprintf("lang: %s with invalid identifier %q", constructor, name))
+	}
+	if err := checkDeclaredIdentifier(tokens, 0); err != nil {
+		panic(fmt.Sprintf("lang: %s with invalid identifier %q: %v", constructor, name, err))
+	}
+}
+
+// mustValue panics if the value, passed to the constructor, is nil.
+func mustValue(constructor string, value Expr) {
+	if value == nil {
+		panic("lang: " + constructor + " with nil value")
+	}
+}
+
+// mustType panics if the data type, passed to the constructor, isn't the type of a value.
+func mustType(constructor string, t dataType) {
+	if t == VoidType {
+		panic("lang: " + constructor + " with void type")
+	}
+}
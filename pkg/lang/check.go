@@ -0,0 +1,626 @@
+package lang
+
+import "sort"
+
+// unknownType is the data type of the values the type checker can't infer a data type for, e.g.
+// the results of append or unwrap_or. Values of unknown type aren't checked.
+const unknownType dataType = -1
+
+// Check verifies the data types of the program before code is generated for it. It reports values
+// of let statements, assignments and returns of the wrong data type. It reports calls with the
+// wrong number or data types of arguments, both of the functions the program declares and of
+// builtins, e.g. len only takes strings, arrays and slices. It reports operands of operations of
+// the wrong data type and conditions which aren't bool values. It reports every use of a name the
+// program doesn't declare, see Resolve, with the declared name closest to it as suggestion, e.g.
+// variable not found in scope: cuont, did you mean count?. It reports every duplicate declaration
+// with the position of the declaration before. It reports every function with a return type which
+// can reach the end of its body without a return.
+//
+// Every error is returned as a diagnostic at the span of the node it is about, in the order of
+// the source code. Values whose data type isn't known before code generation, e.g. the results of
+// generic functions, aren't checked. Neither are the bodies of generic functions.
+//
+// Returns an error if the nodes aren't valid, see Validate.
+func Check(nodes []Node) ([]Diagnostic, error) {
+	resolution, err := Resolve(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	c := checker{
+		resolution: resolution,
+		functions:  make(map[string]signature),
+		structs:    make(map[string]*StructNode),
+		types:      make(map[*Symbol]dataType),
+	}
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *FunctionNode:
+			if len(n.TypeParameters) == 0 {
+				c.functions[n.Name] = newSignature(n.Parameters, n.ReturnType)
+			}
+		case *ExternNode:
+			c.functions[n.Name] = newSignature(n.Parameters, n.ReturnType)
+		case *StructNode:
+			c.structs[n.Name] = n
+		}
+	}
+	c.checkDuplicates()
+	c.checkUndefined()
+	for _, node := range nodes {
+		c.checkStatement(node)
+	}
+	sort.SliceStable(c.diagnostics, func(i, j int) bool {
+		return before(c.diagnostics[i].From, c.diagnostics[j].From)
+	})
+	return c.diagnostics, nil
+}
+
+// signature is the data types of the parameters and the return type of a function.
+type signature struct {
+	parameters []dataType
+	returnType dataType
+}
+
+// newSignature returns the signature of the function with the given parameters and return type.
+func newSignature(parameters []*Parameter, returnType dataType) signature {
+	s := signature{returnType: returnType}
+	for _, parameter := range parameters {
+		s.parameters = append(s.parameters, parameter.Type)
+	}
+	return s
+}
+
+// checker is the state of the type checker while it checks a program.
+type checker struct {
+	resolution  *Resolution            // The declarations the names of the program bind to.
+	functions   map[string]signature   // The functions the program declares, by name.
+	structs     map[string]*StructNode // The structs the program declares, by name.
+	types       map[*Symbol]dataType   // The data types of the values declared so far.
+	function    *FunctionNode          // The function being checked, nil for the top-level statements.
+	diagnostics []Diagnostic
+}
+
+// report adds a diagnostic with the message at the span of the node.
+func (c *checker) report(node Node, err error) {
+	c.diagnostics = append(c.diagnostics, newDiagnostic(node, err))
+}
+
+// declare sets the data type of the value the node declares.
+func (c *checker) declare(node Node, t dataType) {
+	c.types[c.resolution.Declarations[node]] = t
+}
+
+// lookup returns the data type of the value the node using a name binds to, or unknownType.
+func (c *checker) lookup(node Node) dataType {
+	if t, ok := c.types[c.resolution.Bindings[node]]; ok {
+		return t
+	}
+	return unknownType
+}
+
+// checkBody checks the statements of a body.
+func (c *checker) checkBody(nodes []Node) {
+	for _, node := range nodes {
+		c.checkStatement(node)
+	}
+}
+
+// checkStatement checks the data types of the values of the statement.
+func (c *checker) checkStatement(node Node) {
+	switch n := node.(type) {
+	case *FunctionNode:
+		if c.function != nil {
+			return
+		}
+		c.checkReturns(n)
+		if len(n.TypeParameters) > 0 {
+			return
+		}
+		c.function = n
+		for _, parameter := range n.Parameters {
+			c.declare(parameter, parameter.Type)
+		}
+		c.checkBody(n.Body)
+		c.function = nil
+	case *LetNode:
+		c.declare(n, c.checkLet(n))
+	case *AssignmentNode:
+		if err := c.checkTypedValue(n.Value, c.lookup(n)); err != nil {
+			c.report(n.Value, newError(MessageInvalidAssignmentValue, n.Identifier, err))
+		}
+	case *IndexAssignmentNode:
+		if err := c.checkTypedValue(n.Value, c.checkValue(n.Target)); err != nil {
+			c.report(n.Value, newError(MessageInvalidArrayElementValue, err))
+		}
+	case *FieldAssignmentNode:
+		if err := c.checkTypedValue(n.Value, c.checkValue(n.Target)); err != nil {
+			c.report(n.Value, newError(MessageInvalidFieldAssignmentValue, n.Target.Field, err))
+		}
+	case *DereferenceAssignmentNode:
+		if err := c.checkTypedValue(n.Value, c.checkValue(n.Target)); err != nil {
+			c.report(n.Value, newError(MessageInvalidDereferenceAssignmentValue, err))
+		}
+	case *ReturnNode:
+		c.checkReturn(n)
+	case *CallerNode, *SpawnNode, *AddOperationNode:
+		c.checkValue(n.(Expr))
+	case *ForNode:
+		c.declare(&n.Init, Integer32Type)
+		c.checkValue(n.Condition.RightValue)
+		c.checkBody(n.Body)
+	case *WhileNode:
+		if t := c.lookup(n); t != unknownType && t != BoolType {
+			c.report(n, newError(MessageConditionType, t))
+		}
+		c.checkBody(n.Body)
+	}
+}
+
+// checkLet checks the value of the let statement and returns the data type of the variable.
+func (c *checker) checkLet(letNode *LetNode) dataType {
+	if _, ok := literalDataType(letNode.Value); ok || letNode.HasType {
+		if err := c.checkTypedValue(letNode.Value, letNode.Type); err != nil {
+			c.report(letNode.Value, newError(MessageInvalidLetValue, letNode.Identifier, err))
+		}
+		return letNode.Type
+	}
+	t := c.checkValue(letNode.Value)
+	if t == VoidType {
+		c.report(letNode.Value, newError(MessageInvalidLetValue, letNode.Identifier, newError(MessageVoidCallAsValue)))
+		return unknownType
+	}
+	return t
+}
+
+// checkReturn checks the value of the return statement against the return type of the function,
+// or against the exit status of the program for a top-level return.
+func (c *checker) checkReturn(returnNode *ReturnNode) {
+	if c.function == nil {
+		if returnNode.Value != nil {
+			if err := c.checkTypedValue(returnNode.Value, Integer32Type); err != nil {
+				c.report(returnNode.Value, newError(MessageInvalidExitStatus, err))
+			}
+		}
+		return
+	}
+
+	returnType := c.function.ReturnType
+	switch {
+	case returnNode.Value == nil && returnType != VoidType:
+		c.report(returnNode, newError(MessageMissingReturnValue, c.function.Name))
+	case returnNode.Value == nil:
+	case returnType == VoidType:
+		c.checkValue(returnNode.Value)
+		c.report(returnNode.Value, newError(MessageUnexpectedReturnValue, c.function.Name))
+	default:
+		if err := c.checkTypedValue(returnNode.Value, returnType); err != nil {
+			c.report(returnNode.Value, newError(MessageInvalidReturnValue, c.function.Name, err))
+		}
+	}
+}
+
+// checkTypedValue checks that the value can be used as a value of the data type like
+// generateTypedValue converts it: literals and the elements of array literals take the data type,
+// every other value must already have it. Type errors of the parts of the value are reported,
+// the error returned is about the value itself, nil if it can be used as a value of the data type
+// or the data type is unknown.
+func (c *checker) checkTypedValue(value Expr, t dataType) error {
+	if t == unknownType {
+		c.checkValue(value)
+		return nil
+	}
+	if _, ok := literalDataType(value); ok {
+		return checkConstant(value, t)
+	}
+
+	switch v := value.(type) {
+	case *ArrayLiteralNode:
+		element := unknownType
+		if sliceType, ok := t.slice(); ok {
+			element = sliceType.Element
+		} else if arrayType, ok := t.array(); ok {
+			element = arrayType.Element
+			if len(v.Elements) > arrayType.Length {
+				return newError(MessageArrayLiteralOverflow, len(v.Elements), t)
+			}
+		} else {
+			return newError(MessageArrayLiteralType, t)
+		}
+		c.checkElements(v.Elements, element)
+		return nil
+	case *ConditionalNode:
+		c.checkCondition(v.Condition)
+		for _, branch := range []Expr{v.True, v.False} {
+			if err := c.checkTypedValue(branch, t); err != nil {
+				c.report(branch, newError(MessageInvalidConditionalValue, err))
+			}
+		}
+		return nil
+	case *ShiftOperationNode:
+		if t.isInteger() {
+			c.checkShift(v, t)
+			return nil
+		}
+	case *NoneNode:
+		if _, ok := t.option(); ok {
+			return nil
+		}
+	case *CallerNode:
+		if optionType, ok := t.option(); ok && v.FunctionName == someIdentifier && len(v.Args) == 1 {
+			return c.checkTypedValue(v.Args[0], optionType.Element)
+		}
+		if resultType, ok := t.result(); ok && v.FunctionName == okIdentifier && len(v.Args) == 1 {
+			return c.checkTypedValue(v.Args[0], resultType.Element)
+		}
+	}
+
+	valueType := c.checkValue(value)
+	if valueType != unknownType && valueType != t {
+		return newError(MessageValueType, valueType, t)
+	}
+	return nil
+}
+
+// checkConstant checks that the literal can be represented by the data type like generateConstant
+// converts it.
+func checkConstant(value Expr, t dataType) error {
+	switch v := value.(type) {
+	case *IntLiteralNode:
+		if t.isFloat() {
+			return nil
+		}
+		if !t.isInteger() {
+			return newError(MessageIntegerLiteralType, v.Value, t)
+		}
+		if bits := dataTypeBits(t); bits < 64 && (v.Value < -(1<<(bits-1)) || v.Value > 1<<(bits-1)-1) {
+			return newError(MessageConstantOverflow, v.Value, t)
+		}
+	case *FloatLiteralNode:
+		if !t.isFloat() {
+			return newError(MessageFloatLiteralType, v.Value, t)
+		}
+	case *BoolLiteralNode:
+		if t != BoolType {
+			return newError(MessageBoolLiteralType, v.Value, t)
+		}
+	}
+	return nil
+}
+
+// checkElements checks that the elements of an array literal are values of the element type.
+func (c *checker) checkElements(elements []Expr, element dataType) {
+	for i, value := range elements {
+		if err := c.checkTypedValue(value, element); err != nil {
+			c.report(value, newError(MessageInvalidArrayElement, i+1, err))
+		}
+	}
+}
+
+// checkCondition checks that the condition is a bool value.
+func (c *checker) checkCondition(condition Expr) {
+	if t := c.checkValue(condition); t != unknownType && t != BoolType {
+		c.report(condition, newError(MessageConditionType, t))
+	}
+}
+
+// checkValue checks the value and returns its data type like generateValue infers it, or
+// unknownType if it can't be inferred or the value has a type error.
+func (c *checker) checkValue(value Expr) dataType {
+	switch v := value.(type) {
+	case *IntLiteralNode:
+		return v.dataType()
+	case *FloatLiteralNode:
+		return Float64Type
+	case *BoolLiteralNode:
+		return BoolType
+	case *StringLiteralNode:
+		return StringType
+	case *IdentifierNode:
+		return c.lookup(v)
+	case *CastNode:
+		from := c.checkValue(v.Value)
+		if from != unknownType && !isValidCast(from, v.Type) {
+			c.report(v, newError(MessageInvalidCast, from, v.Type))
+		}
+		return v.Type
+	case *AddOperationNode:
+		t := c.checkOperands(v.LeftValue, v.RightValue)
+		if t != unknownType && !t.isInteger() && !t.isFloat() && t != StringType {
+			c.report(v, newError(MessageInvalidAddOperation, newError(MessageInvalidAddOperandType, t)))
+			return unknownType
+		}
+		return t
+	case *ShiftOperationNode:
+		return c.checkShift(v, unknownType)
+	case *ComparisonNode:
+		t := c.checkOperands(v.LeftValue, v.RightValue)
+		equality := v.Operator == OperatorEqual || v.Operator == OperatorNotEqual
+		if t != unknownType && !t.isInteger() && !t.isFloat() && t != StringType && (t != BoolType || !equality) {
+			c.report(v, newError(MessageComparisonType, t, v.Operator))
+		}
+		return BoolType
+	case *ConditionalNode:
+		c.checkCondition(v.Condition)
+		first, second := v.True, v.False
+		if _, ok := literalDataType(first); ok {
+			if _, ok := literalDataType(second); !ok {
+				first, second = second, first
+			}
+		}
+		t := c.checkValue(first)
+		if t == VoidType {
+			c.report(first, newError(MessageInvalidConditionalValue, newError(MessageVoidCallAsValue)))
+			return unknownType
+		}
+		if err := c.checkTypedValue(second, t); err != nil {
+			c.report(second, newError(MessageInvalidConditionalValue, err))
+		}
+		return t
+	case *CallerNode:
+		return c.checkCall(v)
+	case *SpawnNode:
+		c.checkCall(v.Call)
+		return unknownType
+	case *IndexNode:
+		return c.checkIndex(v)
+	case *FieldNode:
+		return c.checkField(v)
+	case *DereferenceNode:
+		t := c.checkValue(v.Value)
+		if t == unknownType {
+			return unknownType
+		}
+		pointerType, ok := t.pointer()
+		if !ok {
+			c.report(v, newError(MessageDereferenceType, t))
+			return unknownType
+		}
+		return pointerType.Element
+	case *AddressNode:
+		if t := c.checkValue(v.Value); t != unknownType && t != VoidType {
+			return pointerTo(t)
+		}
+		return unknownType
+	case *NewNode:
+		return pointerTo(v.Type)
+	case *NoneNode:
+		c.report(v, newError(MessageUntypedNone))
+		return unknownType
+	case *TryNode:
+		c.checkValue(v.Value)
+		return unknownType
+	case *StructLiteralNode:
+		return c.checkStructLiteral(v)
+	case *ArrayLiteralNode:
+		if len(v.Elements) == 0 {
+			c.report(v, newError(MessageEmptyArrayLiteral))
+			return unknownType
+		}
+		element := c.checkValue(v.Elements[0])
+		c.checkElements(v.Elements[1:], element)
+		if element == unknownType || element == VoidType {
+			return unknownType
+		}
+		return arrayOf(element, len(v.Elements))
+	}
+	return unknownType
+}
+
+// checkOperands checks the operands of a binary operation and returns their common data type
+// like generateOperands infers it: a literal operand takes the type of the other operand.
+func (c *checker) checkOperands(left, right Expr) dataType {
+	_, leftIsLiteral := literalDataType(left)
+	_, rightIsLiteral := literalDataType(right)
+	if leftIsLiteral && !rightIsLiteral {
+		left, right = right, left
+	}
+	t := c.checkValue(left)
+	if err := c.checkTypedValue(right, t); err != nil {
+		c.report(right, err)
+		return unknownType
+	}
+	return t
+}
+
+// checkShift checks that the value and the number of bits of the shift are integers and returns
+// the data type of the value, which is t if it is known from where the shift is used.
+func (c *checker) checkShift(shiftOperationNode *ShiftOperationNode, t dataType) dataType {
+	if t != unknownType {
+		if err := c.checkTypedValue(shiftOperationNode.LeftValue, t); err != nil {
+			c.report(shiftOperationNode.LeftValue, newError(MessageInvalidShiftOperation, err))
+			return unknownType
+		}
+	} else {
+		t = c.checkValue(shiftOperationNode.LeftValue)
+	}
+	if t != unknownType && !t.isInteger() {
+		c.report(shiftOperationNode.LeftValue, newError(MessageInvalidShiftOperation, newError(MessageShiftOperandType, t)))
+		return unknownType
+	}
+
+	if _, ok := literalDataType(shiftOperationNode.RightValue); ok {
+		if literal, ok := integerLiteral(shiftOperationNode.RightValue); ok && t != unknownType && (literal < 0 || literal >= int64(dataTypeBits(t))) {
+			c.report(shiftOperationNode.RightValue, newError(MessageInvalidShiftOperation, newError(MessageShiftAmount, literal, t)))
+		} else if t != unknownType {
+			if err := checkConstant(shiftOperationNode.RightValue, t); err != nil {
+				c.report(shiftOperationNode.RightValue, newError(MessageInvalidShiftOperation, err))
+			}
+		}
+	} else if amount := c.checkValue(shiftOperationNode.RightValue); amount != unknownType && !amount.isInteger() {
+		c.report(shiftOperationNode.RightValue, newError(MessageInvalidShiftOperation, newError(MessageShiftOperandType, amount)))
+	}
+	return t
+}
+
+// checkCall checks the number and the data types of the arguments of the call against the
+// parameters of the called function or builtin, and returns its return type.
+func (c *checker) checkCall(callerNode *CallerNode) dataType {
+	name := callerNode.FunctionName
+	symbol, declared := c.resolution.Functions.Get(name)
+	if callsBuiltin(name, declared) {
+		return c.checkBuiltinCall(callerNode, builtinSignatures[name])
+	}
+
+	function, ok := c.functions[name]
+	if !ok {
+		// The parameters of generic functions take any data type, undeclared functions are reported
+		// by checkUndefined
+		if declared {
+			if generic, ok := symbol.Node.(*FunctionNode); ok && len(generic.Parameters) != len(callerNode.Args) {
+				c.report(callerNode, newError(MessageExpectedParameters, len(generic.Parameters), name, len(callerNode.Args)))
+			}
+		}
+		for _, arg := range callerNode.Args {
+			c.checkValue(arg)
+		}
+		return unknownType
+	}
+
+	if len(function.parameters) != len(callerNode.Args) {
+		c.report(callerNode, newError(MessageExpectedParameters, len(function.parameters), name, len(callerNode.Args)))
+		return function.returnType
+	}
+	for i, arg := range callerNode.Args {
+		if err := c.checkTypedValue(arg, function.parameters[i]); err != nil {
+			c.report(arg, newError(MessageInvalidCallerParameter, i+1, name, err))
+		}
+	}
+	return function.returnType
+}
+
+// checkBuiltinCall checks the number and the data types of the parameters of the call of the
+// builtin with the signature, and returns the data type of its result.
+func (c *checker) checkBuiltinCall(callerNode *CallerNode, builtin builtinSignature) dataType {
+	name := callerNode.FunctionName
+	if err := builtin.arityError(name, len(callerNode.Args)); err != nil {
+		c.report(callerNode, err)
+	}
+	result, typed := builtin.result, false
+	for i, arg := range callerNode.Args {
+		if i < len(builtin.parameters) && builtin.parameters[i] != unknownType {
+			if err := c.checkTypedValue(arg, builtin.parameters[i]); err != nil {
+				c.report(arg, newError(MessageInvalidCallerParameter, i+1, name, err))
+			}
+			continue
+		}
+		if _, ok := arg.(*NoneNode); ok {
+			// The other arguments of builtins may take their data type from each other, e.g. none
+			continue
+		}
+		t := c.checkValue(arg)
+		if _, ok := literalDataType(arg); ok && builtin.kind.isMath() && (t.isInteger() || t.isFloat()) {
+			continue
+		}
+		if t == unknownType || i >= len(builtin.parameters) {
+			continue
+		}
+		if err := builtin.kind.kindError(name, t); err != nil {
+			c.report(arg, err)
+		} else if builtin.kind.isMath() {
+			if typed && t != result {
+				c.report(arg, newError(MessageInvalidCallerParameter, i+1, name, newError(MessageValueType, t, result)))
+			}
+			result, typed = t, true
+		}
+	}
+	return result
+}
+
+// checkIndex checks that the indexed value is an array or slice and the index an integer, and
+// returns the data type of the element.
+func (c *checker) checkIndex(indexNode *IndexNode) dataType {
+	t := c.checkValue(indexNode.Value)
+	element := unknownType
+	length := -1
+	if arrayType, ok := t.array(); ok {
+		element, length = arrayType.Element, arrayType.Length
+	} else if sliceType, ok := t.slice(); ok {
+		element = sliceType.Element
+	} else if t != unknownType {
+		c.report(indexNode.Value, newError(MessageIndexType, t))
+	}
+
+	if _, ok := literalDataType(indexNode.Index); ok {
+		if err := checkConstant(indexNode.Index, Integer64Type); err != nil {
+			c.report(indexNode.Index, newError(MessageInvalidArrayIndex, err))
+		} else if constant, ok := integerLiteral(indexNode.Index); ok && element != unknownType && (constant < 0 || length >= 0 && constant >= int64(length)) {
+			c.report(indexNode.Index, newError(MessageIndexOutOfBounds, constant, t))
+		}
+	} else if indexType := c.checkValue(indexNode.Index); indexType != unknownType && !indexType.isInteger() {
+		c.report(indexNode.Index, newError(MessageInvalidArrayIndexType, indexType))
+	}
+	return element
+}
+
+// checkField checks that the value is a struct, or a pointer to one, which has the field, and
+// returns the data type of the field.
+func (c *checker) checkField(fieldNode *FieldNode) dataType {
+	t := c.checkValue(fieldNode.Value)
+	if t == unknownType {
+		return unknownType
+	}
+	if pointerType, ok := t.pointer(); ok {
+		if _, ok := pointerType.Element.structure(); ok {
+			t = pointerType.Element
+		}
+	}
+	structType, ok := t.structure()
+	if !ok {
+		c.report(fieldNode, newError(MessageFieldType, fieldNode.Field, t))
+		return unknownType
+	}
+	structNode, ok := c.structs[structType.Name]
+	if !ok {
+		return unknownType
+	}
+	for _, field := range structNode.Fields {
+		if field.Identifier == fieldNode.Field {
+			return field.Type
+		}
+	}
+	c.report(fieldNode, newError(MessageUnknownField, fieldNode.Field, structType.Name))
+	return unknownType
+}
+
+// checkStructLiteral checks the values of the fields of the struct literal against the fields of
+// the struct and returns the data type of the struct.
+func (c *checker) checkStructLiteral(structLiteralNode *StructLiteralNode) dataType {
+	structNode, ok := c.structs[structLiteralNode.Name]
+	if !ok {
+		for _, field := range structLiteralNode.Fields {
+			c.checkValue(field.Value)
+		}
+		return unknownType
+	}
+
+	for _, fieldValue := range structLiteralNode.Fields {
+		fieldType := unknownType
+		for _, field := range structNode.Fields {
+			if field.Identifier == fieldValue.Identifier {
+				fieldType = field.Type
+			}
+		}
+		if fieldType == unknownType {
+			c.report(fieldValue.Value, newError(MessageUnknownField, fieldValue.Identifier, structLiteralNode.Name))
+		}
+		if err := c.checkTypedValue(fieldValue.Value, fieldType); err != nil {
+			c.report(fieldValue.Value, newError(MessageInvalidFieldValue, fieldValue.Identifier, structLiteralNode.Name, err))
+		}
+	}
+	return structOf(structLiteralNode.Name)
+}
+
+// isValidCast reports whether a value of one data type can be converted into another like
+// generateCast converts it.
+func isValidCast(from, to dataType) bool {
+	switch {
+	case from == to:
+		return true
+	case from.isInteger() || from == BoolType:
+		return to.isInteger() || from != BoolType && to.isFloat()
+	case from.isFloat():
+		return to.isInteger() || to.isFloat()
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package lang
+
+// NodeComments are the comments attached to a statement.
+type NodeComments struct {
+	Leading  []*Comment // The comments on the lines preceding the statement.
+	Trailing []*Comment // The comments following the statement, e.g. on the rest of its last line.
+}
+
+// CommentMap maps the statements of a program to their comments, like the CommentMap of go/ast,
+// so tools which process the nodes, e.g. the formatter, can keep the comments.
+type CommentMap map[Node]*NodeComments
+
+// NewCommentMap attaches every comment to the nearest statement of the nodes or of their bodies. A
+// comment on the last line of a statement trails it, every other comment leads the next statement
+// of the same body, or trails the last statement of the body if no statement follows. A comment in
+// a statement without a statement next to it, e.g. in an empty body or between the fields of a
+// struct, trails the statement. The comments of a program without statements aren't attached.
+func NewCommentMap(nodes []Node, comments []*Comment) CommentMap {
+	m := make(CommentMap)
+	for _, comment := range comments {
+		node, trailing, ok := nearestStatement(nodes, comment)
+		if !ok {
+			continue
+		}
+		if m[node] == nil {
+			m[node] = &NodeComments{}
+		}
+		if trailing {
+			m[node].Trailing = append(m[node].Trailing, comment)
+		} else {
+			m[node].Leading = append(m[node].Leading, comment)
+		}
+	}
+	return m
+}
+
+// nearestStatement returns the statement of the body, or of the bodies of its statements, nearest
+// to the comment and whether the comment trails it, or false if the body has no statements.
+func nearestStatement(body []Node, comment *Comment) (Node, bool, bool) {
+	var previous Node
+	for _, node := range body {
+		if !node.Pos().IsValid() {
+			continue
+		}
+		if before(comment.Pos(), node.Pos()) {
+			if previous != nil && previous.End().Line == comment.Pos().Line {
+				return previous, true, true
+			}
+			return node, false, true
+		}
+		if before(comment.Pos(), node.End()) {
+			if nested, trailing, ok := nearestStatement(statementBody(node), comment); ok {
+				return nested, trailing, true
+			}
+			return node, true, true
+		}
+		previous = node
+	}
+	return previous, true, previous != nil
+}
+
+// statementBody returns the statements of the body of the statement, if it has one.
+func statementBody(node Node) []Node {
+	switch n := node.(type) {
+	case *FunctionNode:
+		return n.Body
+	case *ForNode:
+		return n.Body
+	case *WhileNode:
+		return n.Body
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package lang
+
+import (
+	"tinygo.org/x/go-llvm"
+)
+
+// strcmpIdentifier is the identifier of the C function comparing two strings.
+const strcmpIdentifier = "strcmp"
+
+// Maps from the comparison operators to the LLVM predicates comparing integers and floats. Floats
+// are ordered, unless they are unequal, so a NaN is unequal to every value but compares false otherwise.
+var (
+	integerPredicates = map[ComparisonOperator]llvm.IntPredicate{
+		OperatorEqual:        llvm.IntEQ,
+		OperatorNotEqual:     llvm.IntNE,
+		OperatorLess:         llvm.IntSLT,
+		OperatorLessEqual:    llvm.IntSLE,
+		OperatorGreater:      llvm.IntSGT,
+		OperatorGreaterEqual: llvm.IntSGE,
+	}
+	floatPredicates = map[ComparisonOperator]llvm.FloatPredicate{
+		OperatorEqual:        llvm.FloatOEQ,
+		OperatorNotEqual:     llvm.FloatUNE,
+		OperatorLess:         llvm.FloatOLT,
+		OperatorLessEqual:    llvm.FloatOLE,
+		OperatorGreater:      llvm.FloatOGT,
+		OperatorGreaterEqual: llvm.FloatOGE,
+	}
+)
+
+// generateComparison is a function that generates LLVM IR code for a comparison, which results in
+// a bool. A literal operand takes the type of the other operand. Integers are compared as signed
+// integers and bools only for equality. Strings are compared by their bytes with strcmp rather than
+// by their addresses, so equal strings compare equal wherever they are stored. Values of any other
+// data type, e.g. pointers, can't be compared.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// comparisonNode:   The abstract syntax tree (AST) node representing the comparison.
+//
+// Returns an error if the operands have different data types or values of their data type can't
+// be compared with the operator.
+func generateComparison(scope *Scope, functionBuilder llvm.Builder, comparisonNode *ComparisonNode) (llvm.Value, dataType, error) {
+	left, right, t, err := generateOperands(scope, functionBuilder, comparisonNode.LeftValue, comparisonNode.RightValue)
+	if err != nil {
+		return llvm.Value{}, 0, err
+	}
+
+	operator := comparisonNode.Operator
+	switch {
+	case t.isInteger():
+		return functionBuilder.CreateICmp(integerPredicates[operator], left, right, ""), BoolType, nil
+	case t.isFloat():
+		return functionBuilder.CreateFCmp(floatPredicates[operator], left, right, ""), BoolType, nil
+	case t == BoolType && (operator == OperatorEqual || operator == OperatorNotEqual):
+		return functionBuilder.CreateICmp(integerPredicates[operator], left, right, ""), BoolType, nil
+	case t == StringType:
+		strcmpType, strcmp := strcmpFunction(functionBuilder)
+		order := functionBuilder.CreateCall(strcmpType, strcmp, []llvm.Value{left, right}, "order")
+		zero := llvm.ConstInt(globalScope.Context.Int32Type(), 0, false)
+		return functionBuilder.CreateICmp(integerPredicates[operator], order, zero, ""), BoolType, nil
+	}
+	return llvm.Value{}, 0, newError(MessageComparisonType, t, operator)
+}
+
+// strcmpFunction returns the type and the declaration of the C strcmp function.
+func strcmpFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	return runtimeFunction(functionBuilder, strcmpIdentifier, llvm.FunctionType(globalScope.Context.Int32Type(), []llvm.Type{pointerType, pointerType}, false), nil)
+}
@@ -0,0 +1,318 @@
+package lang
+
+import (
+	"os"
+	"time"
+
+	"tinygo.org/x/go-llvm"
+)
+
+// Phase represents a phase of the compilation pipeline.
+type Phase string
+
+// Constants for the phases of the compilation pipeline, in the order they run.
+const (
+	PhaseTokenize      Phase = "tokenize"
+	PhaseParse         Phase = "parse"
+	PhaseASTPasses     Phase = "ast passes"     // Only runs if passes are configured.
+	PhaseCheck         Phase = "check"          // Only runs if type checking is enabled.
+	PhaseUnused        Phase = "unused"         // Only runs if unused warnings are enabled.
+	PhaseShadowing     Phase = "shadowing"      // Only runs if shadowing warnings are enabled.
+	PhaseUnreachable   Phase = "unreachable"    // Only runs if unreachable warnings are enabled.
+	PhaseDeadCode      Phase = "dead code"      // Only runs if dead code is eliminated.
+	PhaseConstantCalls Phase = "constant calls" // Only runs if constant calls are folded.
+	PhasePrune         Phase = "prune"          // Only runs in whole-program mode.
+	PhaseGenerate      Phase = "generate"
+	PhaseModulePasses  Phase = "module passes" // Only runs if passes are configured.
+	PhaseBudget        Phase = "budget"        // Only runs if a budget is configured.
+)
+
+// Counters holds the sizes measured while compiling a program.
+type Counters struct {
+	Tokens       int // The number of tokens produced by the tokenizer.
+	Nodes        int // The number of nodes in the abstract syntax tree, including nested nodes.
+	Functions    int // The number of functions defined in the LLVM module, including main.
+	Instructions int // The number of instructions in the LLVM module.
+}
+
+// Hooks holds optional callbacks which are invoked while compiling a program.
+// They allow embedders to export metrics or traces without changing the compiler.
+// Every callback may be nil.
+type Hooks struct {
+	// OnPhaseStart is called before a phase starts.
+	OnPhaseStart func(phase Phase)
+	// OnPhaseEnd is called after a phase has finished, with its duration and the error it failed with, if any.
+	OnPhaseEnd func(phase Phase, duration time.Duration, err error)
+	// OnDiagnostic is called for every error reported while compiling, and for every warning, which
+	// is a Diagnostic of SeverityWarning. A Diagnostics collector can be used as the hook.
+	OnDiagnostic func(phase Phase, err error)
+	// OnDeadCode is called with the report of the dead code removed from the program.
+	OnDeadCode func(report DeadCodeReport)
+	// OnPrune is called with the names of the functions removed in whole-program mode.
+	OnPrune func(functions []string)
+	// OnCounters is called once the program has been compiled successfully.
+	OnCounters func(counters Counters)
+}
+
+// Compiler compiles gusty source code into LLVM IR.
+// The zero value is ready to use.
+type Compiler struct {
+	Hooks   Hooks
+	Options Options
+}
+
+// Compile tokenizes, parses and generates the LLVM IR for the given input, together with the files
+// it imports, which are read from the import file system of the options relative to its root.
+// It returns the textual LLVM IR or the first error encountered, rendered in the configured locale.
+func (c *Compiler) Compile(input string) (string, error) {
+	var counters Counters
+
+	c.phaseStart(PhaseTokenize)
+	start := time.Now()
+	tokenize := Tokenize
+	if c.Options.CaseInsensitiveKeywords {
+		tokenize = TokenizeCaseInsensitive
+	}
+	tokens := tokenize(input)
+	counters.Tokens = len(tokens)
+	c.phaseEnd(PhaseTokenize, start, nil)
+
+	c.phaseStart(PhaseParse)
+	start = time.Now()
+	files := c.Options.ImportFS
+	if files == nil {
+		files = os.DirFS(".")
+	}
+	nodes, err := newImporter(files, tokenize).parse(tokens, "")
+	err = localize(err, c.Options.Locale)
+	c.phaseEnd(PhaseParse, start, err)
+	if err != nil {
+		return "", err
+	}
+	counters.Nodes = countNodes(nodes)
+
+	var selected []Pass
+	if len(c.Options.Passes) > 0 {
+		c.phaseStart(PhaseASTPasses)
+		start = time.Now()
+		selected, err = lookupPasses(c.Options.Passes)
+		if err == nil {
+			nodes, err = runASTPasses(selected, c.Options.Passes, nodes)
+		}
+		err = localize(err, c.Options.Locale)
+		c.phaseEnd(PhaseASTPasses, start, err)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if c.Options.TypeCheck {
+		c.phaseStart(PhaseCheck)
+		start = time.Now()
+		diagnostics, err := Check(nodes)
+		if err == nil && len(diagnostics) > 0 {
+			err = diagnostics[0]
+		}
+		err = localize(err, c.Options.Locale)
+		c.phaseEnd(PhaseCheck, start, err)
+		// The compilation fails with the first diagnostic, the others are only reported
+		if c.Hooks.OnDiagnostic != nil && len(diagnostics) > 1 {
+			for _, diagnostic := range diagnostics[1:] {
+				c.Hooks.OnDiagnostic(PhaseCheck, localize(diagnostic, c.Options.Locale))
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if c.Options.WarnUnused {
+		c.phaseStart(PhaseUnused)
+		start = time.Now()
+		diagnostics, err := Unused(nodes)
+		err = localize(err, c.Options.Locale)
+		c.phaseEnd(PhaseUnused, start, err)
+		if err != nil {
+			return "", err
+		}
+		// Unused variables and parameters don't fail the compilation, so they are only reported
+		for _, diagnostic := range diagnostics {
+			c.warn(PhaseUnused, diagnostic)
+		}
+	}
+
+	if c.Options.WarnShadowing {
+		c.phaseStart(PhaseShadowing)
+		start = time.Now()
+		diagnostics, err := Shadowed(nodes)
+		err = localize(err, c.Options.Locale)
+		c.phaseEnd(PhaseShadowing, start, err)
+		if err != nil {
+			return "", err
+		}
+		// Shadowing is allowed, so it is only reported
+		for _, diagnostic := range diagnostics {
+			c.warn(PhaseShadowing, diagnostic)
+		}
+	}
+
+	if c.Options.WarnUnreachable {
+		c.phaseStart(PhaseUnreachable)
+		start = time.Now()
+		diagnostics := Unreachable(nodes)
+		c.phaseEnd(PhaseUnreachable, start, nil)
+		// Unreachable statements don't fail the compilation, so they are only reported
+		for _, diagnostic := range diagnostics {
+			c.warn(PhaseUnreachable, diagnostic)
+		}
+	}
+
+	if c.Options.EliminateDeadCode {
+		c.phaseStart(PhaseDeadCode)
+		start = time.Now()
+		var report DeadCodeReport
+		nodes, report = EliminateDeadCode(nodes, c.Options.Library)
+		c.phaseEnd(PhaseDeadCode, start, nil)
+		if c.Hooks.OnDeadCode != nil {
+			c.Hooks.OnDeadCode(report)
+		}
+	}
+
+	if c.Options.FoldConstantCalls {
+		c.phaseStart(PhaseConstantCalls)
+		start = time.Now()
+		diagnostics := FoldConstantCalls(nodes, c.Options.EvaluationSteps)
+		c.phaseEnd(PhaseConstantCalls, start, nil)
+		// Calls which can't be evaluated are generated as calls, so they are only reported
+		for _, diagnostic := range diagnostics {
+			c.warn(PhaseConstantCalls, diagnostic)
+		}
+	}
+
+	if c.Options.WholeProgram && !c.Options.Library {
+		c.phaseStart(PhasePrune)
+		start = time.Now()
+		var pruned []string
+		nodes, pruned = PruneUnreachable(nodes)
+		c.phaseEnd(PhasePrune, start, nil)
+		if c.Hooks.OnPrune != nil {
+			c.Hooks.OnPrune(pruned)
+		}
+	}
+
+	var originals map[string]string
+	if c.Options.MinifyIdentifiers {
+		originals = Minify(nodes)
+	}
+
+	c.phaseStart(PhaseGenerate)
+	start = time.Now()
+	module, err := generateModule(nodes, c.Options)
+	err = localize(err, c.Options.Locale)
+	c.phaseEnd(PhaseGenerate, start, err)
+	if err != nil {
+		return "", err
+	}
+	defer disposeModule(module)
+	if len(selected) > 0 {
+		c.phaseStart(PhaseModulePasses)
+		start = time.Now()
+		err = localize(runModulePasses(selected, c.Options.Passes, module), c.Options.Locale)
+		c.phaseEnd(PhaseModulePasses, start, err)
+		if err != nil {
+			return "", err
+		}
+	}
+	if c.Options.Budget.limited() {
+		c.phaseStart(PhaseBudget)
+		start = time.Now()
+		err = c.checkBudget(module)
+		c.phaseEnd(PhaseBudget, start, err)
+		if err != nil {
+			return "", err
+		}
+	}
+	if c.Options.MinifyIdentifiers {
+		addIdentifierNames(module, originals)
+	}
+	counters.Functions, counters.Instructions = countInstructions(module)
+
+	if c.Hooks.OnCounters != nil {
+		c.Hooks.OnCounters(counters)
+	}
+
+	return module.String(), nil
+}
+
+// phaseStart invokes the OnPhaseStart hook if it is set.
+func (c *Compiler) phaseStart(phase Phase) {
+	if c.Hooks.OnPhaseStart != nil {
+		c.Hooks.OnPhaseStart(phase)
+	}
+}
+
+// phaseEnd invokes the OnPhaseEnd hook, and the OnDiagnostic hook if the phase failed.
+func (c *Compiler) phaseEnd(phase Phase, start time.Time, err error) {
+	if c.Hooks.OnPhaseEnd != nil {
+		c.Hooks.OnPhaseEnd(phase, time.Since(start), err)
+	}
+	if err != nil && c.Hooks.OnDiagnostic != nil {
+		c.Hooks.OnDiagnostic(phase, err)
+	}
+}
+
+// warn invokes the OnDiagnostic hook with the warning, as a Diagnostic of SeverityWarning.
+func (c *Compiler) warn(phase Phase, err error) {
+	if c.Hooks.OnDiagnostic == nil {
+		return
+	}
+	diagnostic, ok := err.(Diagnostic)
+	if !ok {
+		diagnostic = Diagnostic{Err: err}
+	}
+	diagnostic.Severity = SeverityWarning
+	c.Hooks.OnDiagnostic(phase, localize(diagnostic, c.Options.Locale))
+}
+
+// checkBudget returns the first limit of the budget the module exceeds, or reports every exceeded
+// limit to the OnDiagnostic hook and returns nil if the budget only warns.
+func (c *Compiler) checkBudget(module llvm.Module) error {
+	errs := checkBudget(module, c.Options.Budget)
+	if len(errs) == 0 {
+		return nil
+	}
+	if !c.Options.Budget.Warn {
+		return localize(errs[0], c.Options.Locale)
+	}
+	for _, err := range errs {
+		c.warn(PhaseBudget, err)
+	}
+	return nil
+}
+
+// countNodes returns the number of nodes in the abstract syntax tree, including nested nodes.
+func countNodes(nodes []Node) int {
+	count := 0
+	WalkNodes(nodes, func(Node) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// countInstructions returns the number of defined functions and the number of instructions in the module.
+func countInstructions(module llvm.Module) (int, int) {
+	var functions, instructions int
+	for function := module.FirstFunction(); !function.IsNil(); function = llvm.NextFunction(function) {
+		if function.IsDeclaration() {
+			continue
+		}
+		functions++
+		for block := function.FirstBasicBlock(); !block.IsNil(); block = llvm.NextBasicBlock(block) {
+			for instruction := block.FirstInstruction(); !instruction.IsNil(); instruction = llvm.NextInstruction(instruction) {
+				instructions++
+			}
+		}
+	}
+	return functions, instructions
+}
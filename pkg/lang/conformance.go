@@ -0,0 +1,189 @@
+package lang
+
+import (
+	"sort"
+)
+
+// Feature describes a language feature of the conformance suite by the tokens it introduces and
+// a small program using it. The name of the feature is the flag it is versioned by.
+type Feature struct {
+	Name    string   `json:"name"`
+	Group   string   `json:"group"`
+	Tokens  []string `json:"tokens"`
+	Program string   `json:"program"`
+}
+
+// features holds the conformance suite, grouped by the part of the language a feature belongs to.
+var features = []Feature{
+	{Name: "let", Group: "declarations", Tokens: []string{"let", "="}, Program: `let x = 1`},
+	{Name: "typed_let", Group: "declarations", Tokens: []string{"let", ":"}, Program: `let x: i64 = 1`},
+	{Name: "assignment", Group: "declarations", Tokens: []string{"="}, Program: `let x = 1 x = 2`},
+	{Name: "threadlocal", Group: "declarations", Tokens: []string{"threadlocal"}, Program: `threadlocal let x = 1`},
+	{Name: "volatile", Group: "declarations", Tokens: []string{"volatile"}, Program: `volatile let x = 1`},
+	{Name: "atomic", Group: "declarations", Tokens: []string{"atomic", "(", ")"}, Program: `atomic(acq_rel) let x = 1`},
+	{Name: "function", Group: "declarations", Tokens: []string{"function", "(", ")", "{", "}", ","}, Program: `function f(a i32, b i32) {} f(1, 2)`},
+	{Name: "extern", Group: "declarations", Tokens: []string{"extern", "function"}, Program: `extern function puts(s string) i32 puts("a")`},
+	{Name: "inline_llvm", Group: "declarations", Tokens: []string{"llvm", "{", "}"}, Program: `llvm { declare i32 @puts(ptr) } function f(s string) { llvm { call i32 @puts(ptr %s) } } f("a")`},
+	{Name: "function_attributes", Group: "declarations", Tokens: []string{"@"}, Program: `@inline function f() i32 { return 1 } @noinline function g() {} @noreturn extern function abort() let x = f()`},
+	{Name: "generics", Group: "declarations", Tokens: []string{"<", ">"}, Program: `function add<T>(a T, b T) T { return a + b } let x = add(1, 2) let y = add(1.5, 2.5)`},
+	{Name: "return", Group: "declarations", Tokens: []string{"return"}, Program: `function f() i32 { return 1 } let x = f()`},
+	{Name: "struct", Group: "declarations", Tokens: []string{"struct", "{", "}"}, Program: `struct P { x i32 } let p = P{x: 1}`},
+	{Name: "packed_struct", Group: "declarations", Tokens: []string{"@"}, Program: `@packed struct P { a i8 @align(4) b i32 }`},
+	{Name: "type_alias", Group: "declarations", Tokens: []string{"type", "="}, Program: `type Index = i32 let x: Index = Index(1)`},
+	{Name: "add", Group: "expressions", Tokens: []string{"+"}, Program: `let x = 1 + 2`},
+	{Name: "shift", Group: "expressions", Tokens: []string{"<<", ">>"}, Program: `let x = 1 << 4 >> 2`},
+	{Name: "cast", Group: "expressions", Tokens: []string{"as"}, Program: `let x = 1 as i64 let y = f64(x)`},
+	{Name: "comparison", Group: "expressions", Tokens: []string{"==", "!=", "<", "<=", ">", ">="}, Program: `let s = "a" let x = s == "b" let y = 1 <= 2`},
+	{Name: "conditional", Group: "expressions", Tokens: []string{"?", ":"}, Program: `let t = true let x = t ? 1 : 2`},
+	{Name: "address", Group: "expressions", Tokens: []string{"&", "*"}, Program: `let x = 1 let p = &x let y = *p`},
+	{Name: "index", Group: "expressions", Tokens: []string{"[", "]"}, Program: `let a = [1, 2] let x = a[1]`},
+	{Name: "field", Group: "expressions", Tokens: []string{"."}, Program: `struct P { x i32 } let p = P{x: 1} let x = p.x`},
+	{Name: "try", Group: "expressions", Tokens: []string{"try"}, Program: `function f() result<i32> { let r: result<i32> = ok(1) let x = try r return ok(x) }`},
+	{Name: "integers", Group: "types", Tokens: []string{"i8", "i16", "i32", "i64"}, Program: `let a: i8 = 1 let b: i16 = 1 let c: i32 = 1 let d: i64 = 1`},
+	{Name: "floats", Group: "types", Tokens: []string{"f32", "f64"}, Program: `let a: f32 = 1.5 let b: f64 = 1.5`},
+	{Name: "bool", Group: "types", Tokens: []string{"bool", "true", "false"}, Program: `let a: bool = true let b = false`},
+	{Name: "string", Group: "types", Tokens: []string{"string", "\""}, Program: `let s: string = "a" + "b"`},
+	{Name: "array", Group: "types", Tokens: []string{"[", "]"}, Program: `let a: [2]i32 = [1, 2]`},
+	{Name: "slice", Group: "types", Tokens: []string{"[", "]"}, Program: `let s: []i32 = [1, 2]`},
+	{Name: "pointer", Group: "types", Tokens: []string{"*"}, Program: `let x = 1 let p: *i32 = &x`},
+	{Name: "option", Group: "types", Tokens: []string{"none", "<", ">"}, Program: `let a: option<i32> = none let b: option<i32> = some(1)`},
+	{Name: "result", Group: "types", Tokens: []string{"<", ">"}, Program: `let a: result<i32> = ok(1) let b: result<i32> = err(2)`},
+	{Name: "for", Group: "control", Tokens: []string{"for", ":=", ";", "<"}, Program: `for i := 0; i < 2; i++ { printf(i) }`},
+	{Name: "printf", Group: "builtins", Program: `printf(1) printf(1.5) printf("a")`},
+	{Name: "print", Group: "builtins", Program: `print(1) print(" ") println(true) println(1.5)`},
+	{Name: "format", Group: "builtins", Program: `let s = format("%d %s", 1, "a") + "b"`},
+	{Name: "read", Group: "builtins", Program: `let n = read_int() let s = read_line()`},
+	{Name: "arguments", Group: "builtins", Program: `let n = args_count() let s = arg(0)`},
+	{Name: "exit", Group: "builtins", Program: `let x: i32 = 1 exit(x) return 2`},
+	{Name: "spawn", Group: "builtins", Tokens: []string{"spawn"}, Program: `function work(n i32) {} let t = spawn work(1) join(t)`},
+	{Name: "sync", Group: "builtins", Program: `let p = new(i64) atomic_add(p, 1) atomic_store(p, atomic_load(p)) let m = mutex() lock(m) unlock(m)`},
+	{Name: "len", Group: "builtins", Program: `let s: []i32 = [1] let n = len(s) let c = cap(s) let m = len("a")`},
+	{Name: "append", Group: "builtins", Program: `let s: []i32 = [] s = append(s, 1, 2)`},
+	{Name: "new", Group: "builtins", Program: `let p = new(i32) free(p)`},
+	{Name: "math", Group: "builtins", Program: `let a = abs(-1) let b = max(a, 2) let c = min(1.5, sqrt(2.0)) let d = pow(c, 2)`},
+	{Name: "unwrap_or", Group: "builtins", Program: `let a: option<i32> = none let x = unwrap_or(a, 1)`},
+}
+
+// Features returns the features of the conformance suite in the order of their groups.
+func Features() []Feature {
+	return append([]Feature(nil), features...)
+}
+
+// Backend is a way to translate gusty programs, e.g. into LLVM IR. A backend supports a feature
+// of the conformance suite if it translates the program of the feature without an error.
+type Backend interface {
+	// Name returns the name the backend is reported by.
+	Name() string
+	// Translate translates the program.
+	Translate(input string) error
+}
+
+// LLVMBackend is the backend translating programs into LLVM IR with the Compiler.
+type LLVMBackend struct {
+	Options Options
+}
+
+// Name returns the name of the LLVM backend, llvm.
+func (b LLVMBackend) Name() string {
+	return "llvm"
+}
+
+// Translate compiles the program into LLVM IR.
+func (b LLVMBackend) Translate(input string) error {
+	compiler := Compiler{Options: b.Options}
+	_, err := compiler.Compile(input)
+	return err
+}
+
+// Matrix is the machine-readable feature matrix of the language: the tokens and data types the
+// compiler knows and which backends support every feature of the conformance suite.
+type Matrix struct {
+	Tokens   []string        `json:"tokens"`
+	Types    []string        `json:"types"`
+	Backends []string        `json:"backends"`
+	Features []FeatureResult `json:"features"`
+}
+
+// FeatureResult holds whether every backend of a matrix supports a feature, and why not.
+type FeatureResult struct {
+	Feature
+	Supported map[string]bool   `json:"supported"`
+	Errors    map[string]string `json:"errors,omitempty"`
+}
+
+// GroupResult holds how many features of a group of the conformance suite a backend supports.
+type GroupResult struct {
+	Group  string `json:"group"`
+	Passed int    `json:"passed"`
+	Total  int    `json:"total"`
+}
+
+// Conformance runs the conformance suite against the backends and returns the feature matrix.
+func Conformance(backends ...Backend) Matrix {
+	matrix := Matrix{Tokens: tokenSpellings(), Types: typeSpellings()}
+	for _, backend := range backends {
+		matrix.Backends = append(matrix.Backends, backend.Name())
+	}
+
+	for _, feature := range features {
+		result := FeatureResult{Feature: feature, Supported: make(map[string]bool)}
+		for _, backend := range backends {
+			err := backend.Translate(feature.Program)
+			result.Supported[backend.Name()] = err == nil
+			if err != nil {
+				if result.Errors == nil {
+					result.Errors = make(map[string]string)
+				}
+				result.Errors[backend.Name()] = err.Error()
+			}
+		}
+		matrix.Features = append(matrix.Features, result)
+	}
+	return matrix
+}
+
+// Groups returns how many features of every group the backend with the given name supports, in
+// the order the groups appear in the matrix.
+func (m Matrix) Groups(backend string) []GroupResult {
+	var groups []GroupResult
+	positions := make(map[string]int)
+	for _, feature := range m.Features {
+		position, ok := positions[feature.Group]
+		if !ok {
+			position = len(groups)
+			positions[feature.Group] = position
+			groups = append(groups, GroupResult{Group: feature.Group})
+		}
+		groups[position].Total++
+		if feature.Supported[backend] {
+			groups[position].Passed++
+		}
+	}
+	return groups
+}
+
+// tokenSpellings returns the sorted spellings of every keyword and operator the tokenizer knows.
+func tokenSpellings() []string {
+	spellings := []string{string(TokenShortVariableAssignment), string(TokenShiftLeft), string(TokenShiftRight), string(TokenQuote), string(TokenDot)}
+	for keyword := range keywords {
+		spellings = append(spellings, string(keyword))
+	}
+	for r := range runeTokens {
+		spellings = append(spellings, string(r))
+	}
+	for r := range comparisonTokens {
+		spellings = append(spellings, string(r)+string(TokenEquals))
+	}
+	sort.Strings(spellings)
+	return spellings
+}
+
+// typeSpellings returns the sorted spellings of the built-in data types.
+func typeSpellings() []string {
+	var spellings []string
+	for _, t := range typeTokens {
+		spellings = append(spellings, t.String())
+	}
+	sort.Strings(spellings)
+	return spellings
+}
@@ -0,0 +1,488 @@
+package lang
+
+import "errors"
+
+// DefaultEvaluationSteps is the number of steps FoldConstantCalls may take to evaluate a call if
+// no other limit is given.
+const DefaultEvaluationSteps = 10000
+
+// errNotConstant reports that a value can't be evaluated at compile time, e.g. because it depends
+// on a value only known at runtime. The value is then left to the code generator.
+var errNotConstant = errors.New("not constant")
+
+// constantValue is a value computed at compile time: an integer of an integer type, or a bool
+// which is 1 for true and 0 for false.
+type constantValue struct {
+	Value int64
+	Type  dataType
+}
+
+// FoldConstantCalls replaces every call of a pure function with constant parameters in the nodes
+// with its result, which is evaluated at compile time. A function is pure if its parameters and
+// result are integers or bools and its body only declares and assigns local variables and returns
+// values computed from them by additions, shifts, casts, conditional expressions and calls of pure
+// functions. The calls are replaced in place by a cast of the result into the return type of the
+// function, the functions themselves are kept.
+//
+// The evaluation of a call stops after the given number of steps, or DefaultEvaluationSteps if it
+// isn't positive, e.g. if the function recurses endlessly. FoldConstantCalls returns a diagnostic
+// for every call whose evaluation didn't complete, the call is kept then.
+func FoldConstantCalls(nodes []Node, steps int) []error {
+	if steps <= 0 {
+		steps = DefaultEvaluationSteps
+	}
+	e := evaluator{functions: make(map[string]*FunctionNode), pure: make(map[string]bool), limit: steps}
+	for _, node := range nodes {
+		if functionNode, ok := node.(*FunctionNode); ok {
+			e.functions[functionNode.Name] = functionNode
+		}
+	}
+	e.foldNodes(nodes)
+	return e.diagnostics
+}
+
+// evaluator holds the state of a FoldConstantCalls run.
+type evaluator struct {
+	functions   map[string]*FunctionNode // The functions of the program by name.
+	pure        map[string]bool          // Whether every function checked so far is pure.
+	limit       int                      // The number of steps the evaluation of a call may take.
+	steps       int                      // The number of steps the evaluation of the current call may still take.
+	diagnostics []error                  // The calls whose evaluation didn't complete.
+}
+
+// builtinIdentifiers holds the names of the builtin functions, which are called instead of functions
+// of the program with the same name.
+var builtinIdentifiers = map[string]bool{
+	printfIndentifier: true, printIdentifier: true, printlnIdentifier: true, formatIdentifier: true, readIntIdentifier: true, readLineIdentifier: true, argsCountIdentifier: true, argIdentifier: true, exitIdentifier: true, joinIdentifier: true, lenIdentifier: true, capIdentifier: true,
+	appendIdentifier: true, newIdentifier: true, freeIdentifier: true, someIdentifier: true, unwrapOrIdentifier: true,
+	okIdentifier: true, errIdentifier: true, atomicAddIdentifier: true, atomicLoadIdentifier: true, atomicStoreIdentifier: true, mutexIdentifier: true,
+	lockIdentifier: true, unlockIdentifier: true,
+}
+
+// isPure reports whether the function with the given name can be evaluated at compile time.
+// A function calling itself is assumed to be pure while it is checked.
+func (e *evaluator) isPure(name string) bool {
+	if pure, ok := e.pure[name]; ok {
+		return pure
+	}
+	functionNode, ok := e.functions[name]
+	if !ok || builtinIdentifiers[name] || !isConstantType(functionNode.ReturnType) {
+		return false
+	}
+
+	e.pure[name] = true
+	locals := make(map[string]bool)
+	pure := true
+	for _, parameter := range functionNode.Parameters {
+		pure = pure && isConstantType(parameter.Type)
+		locals[parameter.Identifier] = true
+	}
+	for _, node := range functionNode.Body {
+		if !pure {
+			break
+		}
+		switch n := node.(type) {
+		case *LetNode:
+			pure = !n.ThreadLocal && !n.Volatile && n.Ordering == NotAtomic && (!n.HasType || isConstantType(n.Type)) && e.isPureValue(n.Value, locals)
+			locals[n.Identifier] = true
+		case *AssignmentNode:
+			pure = locals[n.Identifier] && e.isPureValue(n.Value, locals)
+		case *ReturnNode:
+			pure = e.isPureValue(n.Value, locals)
+		default:
+			pure = false
+		}
+	}
+	e.pure[name] = pure
+	return pure
+}
+
+// isPureValue reports whether the value only depends on the local variables and pure functions.
+func (e *evaluator) isPureValue(value Expr, locals map[string]bool) bool {
+	switch v := value.(type) {
+	case *IntLiteralNode, *BoolLiteralNode:
+		return true
+	case *IdentifierNode:
+		return locals[v.Name]
+	case *AddOperationNode:
+		return e.isPureValue(v.LeftValue, locals) && e.isPureValue(v.RightValue, locals)
+	case *ShiftOperationNode:
+		return e.isPureValue(v.LeftValue, locals) && e.isPureValue(v.RightValue, locals)
+	case *ComparisonNode:
+		return e.isPureValue(v.LeftValue, locals) && e.isPureValue(v.RightValue, locals)
+	case *ConditionalNode:
+		return e.isPureValue(v.Condition, locals) && e.isPureValue(v.True, locals) && e.isPureValue(v.False, locals)
+	case *CastNode:
+		return v.Type.isInteger() && e.isPureValue(v.Value, locals)
+	case *CallerNode:
+		for _, arg := range v.Args {
+			if !e.isPureValue(arg, locals) {
+				return false
+			}
+		}
+		return e.isPure(v.FunctionName)
+	}
+	return false
+}
+
+// isConstantType reports whether values of the data type can be computed at compile time.
+func isConstantType(t dataType) bool {
+	return t.isInteger() || t == BoolType
+}
+
+// foldNodes replaces the calls with constant parameters in the nodes.
+func (e *evaluator) foldNodes(nodes []Node) {
+	for _, node := range nodes {
+		e.fold(node)
+	}
+}
+
+// fold replaces the calls with constant parameters in the node.
+func (e *evaluator) fold(node any) {
+	switch n := node.(type) {
+	case *FunctionNode:
+		e.foldNodes(n.Body)
+	case *LetNode:
+		n.Value = e.foldValue(n.Value)
+	case *AssignmentNode:
+		n.Value = e.foldValue(n.Value)
+	case *IndexAssignmentNode:
+		e.fold(n.Target)
+		n.Value = e.foldValue(n.Value)
+	case *FieldAssignmentNode:
+		e.fold(n.Target)
+		n.Value = e.foldValue(n.Value)
+	case *DereferenceAssignmentNode:
+		e.fold(n.Target)
+		n.Value = e.foldValue(n.Value)
+	case *ReturnNode:
+		n.Value = e.foldValue(n.Value)
+	case *CallerNode:
+		for i, arg := range n.Args {
+			n.Args[i] = e.foldValue(arg)
+		}
+	case *AddOperationNode:
+		n.LeftValue = e.foldValue(n.LeftValue)
+		n.RightValue = e.foldValue(n.RightValue)
+	case *ShiftOperationNode:
+		n.LeftValue = e.foldValue(n.LeftValue)
+		n.RightValue = e.foldValue(n.RightValue)
+	case *CastNode:
+		n.Value = e.foldValue(n.Value)
+	case *IndexNode:
+		n.Value = e.foldValue(n.Value)
+		n.Index = e.foldValue(n.Index)
+	case *FieldNode:
+		n.Value = e.foldValue(n.Value)
+	case *AddressNode:
+		n.Value = e.foldValue(n.Value)
+	case *DereferenceNode:
+		n.Value = e.foldValue(n.Value)
+	case *TryNode:
+		n.Value = e.foldValue(n.Value)
+	case *ComparisonNode:
+		n.LeftValue = e.foldValue(n.LeftValue)
+		n.RightValue = e.foldValue(n.RightValue)
+	case *ConditionalNode:
+		n.Condition = e.foldValue(n.Condition)
+		n.True = e.foldValue(n.True)
+		n.False = e.foldValue(n.False)
+	case *ArrayLiteralNode:
+		for i, element := range n.Elements {
+			n.Elements[i] = e.foldValue(element)
+		}
+	case *StructLiteralNode:
+		for _, field := range n.Fields {
+			field.Value = e.foldValue(field.Value)
+		}
+	case *SpawnNode:
+		e.fold(n.Call)
+	case *ForNode:
+		e.foldNodes(n.Body)
+	case *WhileNode:
+		e.foldNodes(n.Body)
+	}
+}
+
+// foldValue returns the value with the calls with constant parameters replaced. A call which is
+// evaluated completely is replaced by its result.
+func (e *evaluator) foldValue(value Expr) Expr {
+	callerNode, ok := value.(*CallerNode)
+	if ok && e.isPure(callerNode.FunctionName) {
+		e.steps = e.limit
+		result, err := e.evaluate(callerNode, nil, nil)
+		switch {
+		case err == nil:
+			return constantNode(result)
+		case err != errNotConstant:
+			e.diagnostics = append(e.diagnostics, newError(MessageEvaluationIncomplete, callerNode.FunctionName, err))
+			return value
+		}
+	}
+	e.fold(value)
+	return value
+}
+
+// constantNode returns the node representing the result of a call evaluated at compile time,
+// which is a cast of the literal into the return type of the function.
+func constantNode(result constantValue) Expr {
+	if result.Type == BoolType {
+		return &BoolLiteralNode{Value: result.Value != 0}
+	}
+	return &CastNode{Type: result.Type, Value: &IntLiteralNode{Value: result.Value}}
+}
+
+// evaluate computes the value with the local variables and returns it. If t isn't nil, the value
+// is used as a value of its data type, which literals take.
+//
+// Returns errNotConstant if the value can't be computed at compile time, or another error if the
+// evaluation didn't complete.
+func (e *evaluator) evaluate(value Expr, locals map[string]constantValue, t *dataType) (constantValue, error) {
+	e.steps--
+	if e.steps < 0 {
+		return constantValue{}, newError(MessageEvaluationSteps, e.limit)
+	}
+
+	switch v := value.(type) {
+	case *IntLiteralNode:
+		return literalConstant(v.Value, v.dataType(), t)
+	case *BoolLiteralNode:
+		result := constantValue{Type: BoolType}
+		if v.Value {
+			result.Value = 1
+		}
+		return typedConstant(result, t)
+	case *IdentifierNode:
+		local, ok := locals[v.Name]
+		if !ok {
+			return constantValue{}, errNotConstant
+		}
+		return typedConstant(local, t)
+	case *AddOperationNode:
+		left, right, err := e.evaluateOperands(v.LeftValue, v.RightValue, locals)
+		if err != nil {
+			return constantValue{}, err
+		}
+		if !left.Type.isInteger() {
+			return constantValue{}, errNotConstant
+		}
+		return typedConstant(wrapConstant(left.Value+right.Value, left.Type), t)
+	case *ShiftOperationNode:
+		left, err := e.evaluate(v.LeftValue, locals, t)
+		if err != nil {
+			return constantValue{}, err
+		}
+		right, err := e.evaluate(v.RightValue, locals, nil)
+		if err != nil {
+			return constantValue{}, err
+		}
+		if !left.Type.isInteger() || !right.Type.isInteger() {
+			return constantValue{}, errNotConstant
+		}
+		// The number of bits is masked to the bit width like at runtime
+		bits := int64(dataTypeBits(left.Type))
+		if amount := right.Value & (bits - 1); v.Left {
+			left.Value <<= amount
+		} else {
+			left.Value >>= amount
+		}
+		return typedConstant(wrapConstant(left.Value, left.Type), t)
+	case *ComparisonNode:
+		left, right, err := e.evaluateOperands(v.LeftValue, v.RightValue, locals)
+		if err != nil {
+			return constantValue{}, err
+		}
+		if !left.Type.isInteger() && (left.Type != BoolType || v.Operator > OperatorNotEqual) {
+			return constantValue{}, errNotConstant
+		}
+		return typedConstant(compareConstants(left.Value, right.Value, v.Operator), t)
+	case *ConditionalNode:
+		condition, err := e.evaluate(v.Condition, locals, nil)
+		if err != nil {
+			return constantValue{}, err
+		}
+		if condition.Type != BoolType {
+			return constantValue{}, errNotConstant
+		}
+		// A literal value takes the type of the other value, which is only known but not computed
+		if _, ok := literalDataType(v.True); ok && t == nil {
+			if falseType, ok := e.typeOf(v.False, locals); ok {
+				t = &falseType
+			}
+		} else if _, ok := literalDataType(v.False); ok && t == nil {
+			if trueType, ok := e.typeOf(v.True, locals); ok {
+				t = &trueType
+			}
+		}
+		if condition.Value != 0 {
+			return e.evaluate(v.True, locals, t)
+		}
+		return e.evaluate(v.False, locals, t)
+	case *CastNode:
+		operand, err := e.evaluate(v.Value, locals, nil)
+		if err != nil {
+			return constantValue{}, err
+		}
+		if !operand.Type.isInteger() || !v.Type.isInteger() {
+			return constantValue{}, errNotConstant
+		}
+		return typedConstant(wrapConstant(operand.Value, v.Type), t)
+	case *CallerNode:
+		result, err := e.call(v, locals)
+		if err != nil {
+			return constantValue{}, err
+		}
+		return typedConstant(result, t)
+	}
+	return constantValue{}, errNotConstant
+}
+
+// typeOf returns the data type of the value with the local variables without computing it, and
+// whether the data type is known.
+func (e *evaluator) typeOf(value Expr, locals map[string]constantValue) (dataType, bool) {
+	switch v := value.(type) {
+	case *IdentifierNode:
+		local, ok := locals[v.Name]
+		return local.Type, ok
+	case *AddOperationNode:
+		if _, ok := literalDataType(v.LeftValue); ok {
+			if _, ok := literalDataType(v.RightValue); !ok {
+				return e.typeOf(v.RightValue, locals)
+			}
+		}
+		return e.typeOf(v.LeftValue, locals)
+	case *ShiftOperationNode:
+		return e.typeOf(v.LeftValue, locals)
+	case *ComparisonNode:
+		return BoolType, true
+	case *ConditionalNode:
+		if _, ok := literalDataType(v.True); ok {
+			return e.typeOf(v.False, locals)
+		}
+		return e.typeOf(v.True, locals)
+	case *CastNode:
+		return v.Type, true
+	case *CallerNode:
+		functionNode, ok := e.functions[v.FunctionName]
+		if !ok {
+			return 0, false
+		}
+		return functionNode.ReturnType, true
+	}
+	return literalDataType(value)
+}
+
+// evaluateOperands computes the operands of an operation. A literal operand takes the type of the
+// other operand, two literals take their default type.
+func (e *evaluator) evaluateOperands(left, right Expr, locals map[string]constantValue) (constantValue, constantValue, error) {
+	if _, ok := literalDataType(left); ok {
+		if _, ok := literalDataType(right); !ok {
+			rightValue, err := e.evaluate(right, locals, nil)
+			if err != nil {
+				return constantValue{}, constantValue{}, err
+			}
+			leftValue, err := e.evaluate(left, locals, &rightValue.Type)
+			return leftValue, rightValue, err
+		}
+	}
+
+	leftValue, err := e.evaluate(left, locals, nil)
+	if err != nil {
+		return constantValue{}, constantValue{}, err
+	}
+	rightValue, err := e.evaluate(right, locals, &leftValue.Type)
+	return leftValue, rightValue, err
+}
+
+// call evaluates the call of a pure function with the local variables of the caller.
+func (e *evaluator) call(callerNode *CallerNode, callerLocals map[string]constantValue) (constantValue, error) {
+	functionNode, ok := e.functions[callerNode.FunctionName]
+	if !ok || !e.isPure(callerNode.FunctionName) || len(functionNode.Parameters) != len(callerNode.Args) {
+		return constantValue{}, errNotConstant
+	}
+
+	locals := make(map[string]constantValue)
+	for i, parameter := range functionNode.Parameters {
+		value, err := e.evaluate(callerNode.Args[i], callerLocals, &parameter.Type)
+		if err != nil {
+			return constantValue{}, err
+		}
+		locals[parameter.Identifier] = value
+	}
+
+	for _, node := range functionNode.Body {
+		switch n := node.(type) {
+		case *LetNode:
+			var t *dataType
+			if n.HasType {
+				t = &n.Type
+			}
+			value, err := e.evaluate(n.Value, locals, t)
+			if err != nil {
+				return constantValue{}, err
+			}
+			locals[n.Identifier] = value
+		case *AssignmentNode:
+			local := locals[n.Identifier]
+			value, err := e.evaluate(n.Value, locals, &local.Type)
+			if err != nil {
+				return constantValue{}, err
+			}
+			locals[n.Identifier] = value
+		case *ReturnNode:
+			return e.evaluate(n.Value, locals, &functionNode.ReturnType)
+		}
+	}
+	return constantValue{}, errNotConstant
+}
+
+// literalConstant returns the value of an integer literal of the given default type, which takes
+// the data type t if it isn't nil.
+func literalConstant(value int64, defaultType dataType, t *dataType) (constantValue, error) {
+	if t == nil {
+		return constantValue{Value: value, Type: defaultType}, nil
+	}
+	bits := dataTypeBits(*t)
+	if !t.isInteger() || bits < 64 && (value < -(1<<(bits-1)) || value > 1<<(bits-1)-1) {
+		return constantValue{}, errNotConstant
+	}
+	return constantValue{Value: value, Type: *t}, nil
+}
+
+// typedConstant returns the value if it has the data type t or t is nil.
+func typedConstant(value constantValue, t *dataType) (constantValue, error) {
+	if t != nil && value.Type != *t {
+		return constantValue{}, errNotConstant
+	}
+	return value, nil
+}
+
+// compareConstants returns the bool resulting from comparing two integers with the operator.
+func compareConstants(left, right int64, operator ComparisonOperator) constantValue {
+	var result bool
+	switch operator {
+	case OperatorEqual:
+		result = left == right
+	case OperatorNotEqual:
+		result = left != right
+	case OperatorLess:
+		result = left < right
+	case OperatorLessEqual:
+		result = left <= right
+	case OperatorGreater:
+		result = left > right
+	case OperatorGreaterEqual:
+		result = left >= right
+	}
+	if result {
+		return constantValue{Value: 1, Type: BoolType}
+	}
+	return constantValue{Type: BoolType}
+}
+
+// wrapConstant returns the integer wrapped around to the bit width of the integer type, like the
+// integer arithmetic at runtime.
+func wrapConstant(value int64, t dataType) constantValue {
+	shift := 64 - dataTypeBits(t)
+	return constantValue{Value: value << shift >> shift, Type: t}
+}
@@ -0,0 +1,154 @@
+package lang
+
+import (
+	"sort"
+	"strings"
+)
+
+// SyntaxNode is a node of the concrete syntax tree, which is lossless: besides the nodes of the
+// abstract syntax tree it holds every token of the source code as written, including parentheses,
+// braces and the whitespace and comments between tokens, so the source code can be restored from
+// it exactly.
+type SyntaxNode struct {
+	Node     Node            // The node of the abstract syntax tree, nil for the root of the tree.
+	Children []SyntaxElement // The tokens and the child nodes in the order of the source code.
+	Trailing string          // The whitespace and comments following the last token, only the root has it.
+}
+
+// SyntaxElement is an element of the concrete syntax tree, i.e. a *SyntaxNode or a *SyntaxToken.
+type SyntaxElement interface {
+	// String returns the source code of the element including its whitespace and comments.
+	String() string
+}
+
+// SyntaxToken is a token of the concrete syntax tree.
+type SyntaxToken struct {
+	Token
+	Leading string // The whitespace and comments preceding the token.
+	Text    string // The source code of the token, e.g. a string literal with its quotes.
+}
+
+// ParseCST tokenizes and parses the source code like Parse, and returns its concrete syntax tree.
+// The children of its root are the top-level statements and the tokens which don't belong to a
+// statement, e.g. the package declaration. Every node refers to its node of the abstract syntax
+// tree, whose tokens are split between the node and its child nodes, e.g. the tokens of a call are
+// its name and parentheses, and the child nodes of its arguments.
+func ParseCST(source string) (*SyntaxNode, error) {
+	return (&Parser{}).ParseCST(source)
+}
+
+// ParseCST tokenizes and parses the source code like ParseCST, with the configuration of the parser.
+func (p *Parser) ParseCST(source string) (*SyntaxNode, error) {
+	tokens := Tokenize(source)
+	nodes, err := p.Parse(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	// The byte offset of every position in the source code
+	offsets := make(map[Pos]int)
+	pos := Pos{Line: 1, Column: 1}
+	for offset, r := range source {
+		offsets[pos] = offset
+		if r == '\n' {
+			pos = Pos{Line: pos.Line + 1, Column: 1}
+		} else {
+			pos.Column++
+		}
+	}
+	offsets[pos] = len(source)
+
+	b := cstBuilder{tokens: tokens, syntaxTokens: make([]*SyntaxToken, len(tokens))}
+	previous := 0
+	for i, token := range tokens {
+		start, end := offsets[token.Pos], offsets[token.End]
+		b.syntaxTokens[i] = &SyntaxToken{Token: token, Leading: source[previous:start], Text: source[start:end]}
+		previous = end
+	}
+
+	root := &SyntaxNode{Trailing: source[previous:]}
+	b.build(root, nodes, 0, len(tokens))
+	return root, nil
+}
+
+// String returns the source code of the node including its whitespace and comments.
+func (n *SyntaxNode) String() string {
+	var b strings.Builder
+	for _, child := range n.Children {
+		b.WriteString(child.String())
+	}
+	b.WriteString(n.Trailing)
+	return b.String()
+}
+
+// Tokens returns the tokens of the node and of its child nodes in the order of the source code.
+func (n *SyntaxNode) Tokens() []*SyntaxToken {
+	var tokens []*SyntaxToken
+	for _, child := range n.Children {
+		switch c := child.(type) {
+		case *SyntaxToken:
+			tokens = append(tokens, c)
+		case *SyntaxNode:
+			tokens = append(tokens, c.Tokens()...)
+		}
+	}
+	return tokens
+}
+
+// String returns the source code of the token preceded by its whitespace and comments.
+func (t *SyntaxToken) String() string {
+	return t.Leading + t.Text
+}
+
+// cstBuilder builds the concrete syntax tree from the tokens and the nodes parsed from them.
+type cstBuilder struct {
+	tokens       []Token
+	syntaxTokens []*SyntaxToken
+}
+
+// build adds the tokens from start up to end, excluding end, to the syntax node, except the tokens
+// of the child nodes, which are added to syntax nodes of their own.
+func (b *cstBuilder) build(node *SyntaxNode, children []Node, start, end int) {
+	sort.SliceStable(children, func(i, j int) bool {
+		return before(children[i].Pos(), children[j].Pos())
+	})
+
+	index := start
+	for _, child := range children {
+		childStart, childEnd := tokenIndex(b.tokens, child.Pos()), tokenIndex(b.tokens, child.End())
+		// A node which doesn't follow the previous node, e.g. a value shared with it, has no tokens of its own
+		if childStart < index || childEnd > end || childStart >= childEnd {
+			continue
+		}
+		b.addTokens(node, index, childStart)
+		syntaxNode := &SyntaxNode{Node: child}
+		b.build(syntaxNode, childNodes(child), childStart, childEnd)
+		node.Children = append(node.Children, syntaxNode)
+		index = childEnd
+	}
+	b.addTokens(node, index, end)
+}
+
+// addTokens adds the tokens from start up to end, excluding end, to the syntax node.
+func (b *cstBuilder) addTokens(node *SyntaxNode, start, end int) {
+	for _, token := range b.syntaxTokens[start:end] {
+		node.Children = append(node.Children, token)
+	}
+}
+
+// childNodes returns the child nodes of the node which have a span. The child nodes of a child node
+// without a span, e.g. a parameter, take its place.
+func childNodes(node Node) []Node {
+	var children []Node
+	Walk(node, func(child Node) bool {
+		if child == node {
+			return true
+		}
+		if child.Pos().IsValid() {
+			children = append(children, child)
+			return false
+		}
+		return true
+	})
+	return children
+}
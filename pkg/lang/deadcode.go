@@ -0,0 +1,75 @@
+package lang
+
+// DeadCodeReport lists what EliminateDeadCode removed from a program.
+type DeadCodeReport struct {
+	// Statements holds the removed statements which follow a return statement, in source order.
+	Statements []Node
+	// Functions holds the names of the removed functions which are never called, in declaration order.
+	Functions []string
+}
+
+// EliminateDeadCode removes the statements which can never run because they follow a return
+// statement in the same body, from the top-level statements, functions and loops, and, unless
+// the program is a library, every function main can't call, see PruneUnreachable. The functions
+// of a library are called by the programs it is linked into, so they are kept. Declarations
+// following a top-level return, e.g. of functions, structs and thread-local variables, are kept,
+// since they don't run. The bodies of the nodes are truncated in place.
+//
+// EliminateDeadCode returns the remaining nodes, in their original order, and a report of what it removed.
+func EliminateDeadCode(nodes []Node, library bool) ([]Node, DeadCodeReport) {
+	var report DeadCodeReport
+	kept := make([]Node, 0, len(nodes))
+	returned := false
+	for _, node := range nodes {
+		if returned && !isDeclaration(node) {
+			report.Statements = append(report.Statements, node)
+			continue
+		}
+		report.eliminateBody(node)
+		kept = append(kept, node)
+		if _, ok := node.(*ReturnNode); ok {
+			returned = true
+		}
+	}
+	nodes = kept
+	if !library {
+		nodes, report.Functions = PruneUnreachable(nodes)
+	}
+	return nodes, report
+}
+
+// eliminateStatements returns the nodes up to the first return statement and adds the statements
+// following it to the report, after removing the dead statements of their bodies.
+func (r *DeadCodeReport) eliminateStatements(nodes []Node) []Node {
+	for i, node := range nodes {
+		r.eliminateBody(node)
+		if _, ok := node.(*ReturnNode); ok {
+			r.Statements = append(r.Statements, nodes[i+1:]...)
+			return nodes[:i+1]
+		}
+	}
+	return nodes
+}
+
+// eliminateBody removes the dead statements of the body of the node, if it has one.
+func (r *DeadCodeReport) eliminateBody(node Node) {
+	switch n := node.(type) {
+	case *FunctionNode:
+		n.Body = r.eliminateStatements(n.Body)
+	case *ForNode:
+		n.Body = r.eliminateStatements(n.Body)
+	case *WhileNode:
+		n.Body = r.eliminateStatements(n.Body)
+	}
+}
+
+// isDeclaration reports whether the top-level node declares something rather than running.
+func isDeclaration(node Node) bool {
+	switch n := node.(type) {
+	case *FunctionNode, *StructNode, *TypeAliasNode, *ExternNode, *EmbedNode, *InlineIRNode, *ImportNode, *PackageNode:
+		return true
+	case *LetNode:
+		return n.ThreadLocal
+	}
+	return false
+}
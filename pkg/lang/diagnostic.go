@@ -0,0 +1,177 @@
+package lang
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Severity is the severity of a diagnostic.
+type Severity int
+
+const (
+	SeverityError   Severity = iota // An error, which the program doesn't compile with.
+	SeverityWarning                 // A warning about code which compiles, but is likely a mistake.
+)
+
+// String returns the name of the severity, e.g. warning.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Diagnostic is a problem found by checking the abstract syntax tree of a program, e.g. a type
+// error, at the span of the node it is about.
+type Diagnostic struct {
+	Span
+	Severity Severity
+	Err      error // The message of the diagnostic.
+}
+
+// Error returns the message of the diagnostic, prefixed with its position if it is known, e.g.
+// 1:9: invalid value for let node x: cannot use bool value as i32 value.
+func (d Diagnostic) Error() string {
+	if !d.From.IsValid() {
+		return d.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", d.From, d.Err)
+}
+
+// Unwrap returns the message of the diagnostic.
+func (d Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Code returns the ID of the message of the diagnostic, which tells the kind of the problem in
+// every locale, e.g. unused_variable, or "" if the message isn't from the message catalog. The
+// messages adding a suggestion or a position to another message have the code of that message.
+func (d Diagnostic) Code() MessageID {
+	var message *MessageError
+	if !errors.As(d.Err, &message) {
+		return ""
+	}
+	for message.ID == MessageDidYouMean || message.ID == MessageFirstDeclaredAt {
+		wrapped, ok := message.Args[0].(*MessageError)
+		if !ok {
+			break
+		}
+		message = wrapped
+	}
+	return message.ID
+}
+
+// newDiagnostic returns the diagnostic with the message at the span of the node.
+func newDiagnostic(node Node, err error) Diagnostic {
+	return Diagnostic{Span: Span{From: node.Pos(), To: node.End()}, Err: err}
+}
+
+// Diagnostics collects the errors and warnings of every phase of compiling a program as
+// diagnostics, in the order they are reported, so they can be rendered the same way. Its Report
+// method is the OnDiagnostic hook of a Compiler. The zero value is an empty collector.
+type Diagnostics struct {
+	list []Diagnostic
+}
+
+// Add adds the diagnostic to the collector.
+func (d *Diagnostics) Add(diagnostic Diagnostic) {
+	d.list = append(d.list, diagnostic)
+}
+
+// Report adds the diagnostics of an error reported in the phase, e.g. by the OnDiagnostic hook of
+// a Compiler: a diagnostic is added as it is, a syntax error at its position and every error of
+// SyntaxErrors on its own. Other errors, e.g. of the code generation, are added as errors without
+// a position. The messages keep the locale the error was rendered in.
+func (d *Diagnostics) Report(phase Phase, err error) {
+	locale := LocaleEnglish
+	if localized, ok := err.(*localizedError); ok {
+		err, locale = localized.err, localized.locale
+	}
+	for _, diagnostic := range diagnosticsOf(err) {
+		diagnostic.Err = localize(diagnostic.Err, locale)
+		d.Add(diagnostic)
+	}
+}
+
+// List returns the collected diagnostics in the order they were reported.
+func (d *Diagnostics) List() []Diagnostic {
+	list := make([]Diagnostic, len(d.list))
+	copy(list, d.list)
+	return list
+}
+
+// HasErrors reports whether an error was collected, rather than only warnings.
+func (d *Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d.list {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// diagnosticsOf returns the diagnostics of the error.
+func diagnosticsOf(err error) []Diagnostic {
+	switch e := err.(type) {
+	case Diagnostic:
+		return []Diagnostic{e}
+	case *ParseError:
+		return []Diagnostic{{Span: Span{From: e.Pos, To: e.Pos}, Err: e.Err}}
+	case SyntaxErrors:
+		var diagnostics []Diagnostic
+		for _, err := range e {
+			diagnostics = append(diagnostics, diagnosticsOf(err)...)
+		}
+		return diagnostics
+	}
+	return []Diagnostic{{Err: err}}
+}
+
+// jsonDiagnostic is the JSON encoding of a diagnostic.
+type jsonDiagnostic struct {
+	File     string     `json:"file,omitempty"`
+	Range    *jsonRange `json:"range,omitempty"`
+	Severity string     `json:"severity"`
+	Code     MessageID  `json:"code,omitempty"`
+	Message  string     `json:"message"`
+}
+
+// jsonRange is the JSON encoding of a span, from its start to the position following it.
+type jsonRange struct {
+	Start jsonPos `json:"start"`
+	End   jsonPos `json:"end"`
+}
+
+// jsonPos is the JSON encoding of a position.
+type jsonPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// MarshalDiagnostics encodes the diagnostics found in the file with the given name as a JSON array
+// holding an object per diagnostic, for tools which consume the diagnostics of the compiler, e.g.
+//
+//	[{"file":"main.gusty","range":{"start":{"line":1,"column":14},"end":{"line":1,"column":18}},
+//	"severity":"error","code":"invalid_let_value","message":"invalid value for let node x: ..."}]
+//
+// The file, the range and the code are left out if they are unknown, see Diagnostic.Code, and
+// the message is rendered in the locale of the diagnostic, without its position.
+func MarshalDiagnostics(filename string, diagnostics []Diagnostic) ([]byte, error) {
+	encoded := make([]jsonDiagnostic, len(diagnostics))
+	for i, diagnostic := range diagnostics {
+		encoded[i] = jsonDiagnostic{
+			File:     filename,
+			Severity: diagnostic.Severity.String(),
+			Code:     diagnostic.Code(),
+			Message:  diagnostic.Err.Error(),
+		}
+		if diagnostic.From.IsValid() {
+			encoded[i].Range = &jsonRange{Start: jsonPos(diagnostic.From), End: jsonPos(diagnostic.To)}
+		}
+	}
+	return json.Marshal(encoded)
+}
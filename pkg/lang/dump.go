@@ -0,0 +1,106 @@
+package lang
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// dumpIndent is the indentation of a nested line of a dump.
+const dumpIndent = "  "
+
+// dataTypeType is the type of the data types of nodes.
+var dataTypeType = reflect.TypeOf(dataType(0))
+
+// Dump renders the abstract syntax tree of the nodes for debugging, one line per node and field.
+// A node is rendered as its kind, e.g. LetNode, followed by its span, if it is known, and the
+// fields set, which are indented below it:
+//
+//	LetNode 1:1-1:14
+//	  Identifier: "x"
+//	  Type: i32
+//	  Value: AddOperationNode 1:9-1:14
+//	    LeftValue: IdentifierNode 1:9-1:10
+//	      Name: "a"
+//	    RightValue: IntLiteralNode 1:13-1:14
+//	      Value: 1
+func Dump(nodes []Node) string {
+	var sb strings.Builder
+	for _, node := range nodes {
+		dumpValue(&sb, "", reflect.ValueOf(node), 0)
+	}
+	return sb.String()
+}
+
+// dumpValue writes the value of the field with the label, or of an element of a list if the label
+// is empty, at the given depth.
+func dumpValue(sb *strings.Builder, label string, value reflect.Value, depth int) {
+	sb.WriteString(strings.Repeat(dumpIndent, depth))
+	if label != "" {
+		sb.WriteString(label + ":")
+		if value.Kind() != reflect.Slice {
+			sb.WriteString(" ")
+		}
+	}
+
+	for value.Kind() == reflect.Interface || (value.Kind() == reflect.Pointer && !value.IsNil()) {
+		if value.Kind() == reflect.Pointer && value.Elem().Kind() == reflect.Struct {
+			break
+		}
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Struct && value.CanAddr() {
+		value = value.Addr()
+	}
+
+	switch {
+	case !value.IsValid(), value.Kind() == reflect.Pointer && value.IsNil():
+		// A nil interface, e.g. a missing statement, or a nil pointer
+		sb.WriteString("nil\n")
+	case value.Kind() == reflect.Pointer || value.Kind() == reflect.Struct:
+		dumpStruct(sb, value, depth)
+	case value.Kind() == reflect.Slice:
+		sb.WriteString("\n")
+		for i := 0; i < value.Len(); i++ {
+			dumpValue(sb, "", value.Index(i), depth+1)
+		}
+	case value.Kind() == reflect.String:
+		fmt.Fprintf(sb, "%q\n", value.String())
+	default:
+		fmt.Fprintf(sb, "%v\n", value.Interface())
+	}
+}
+
+// dumpStruct writes the kind of the struct the value is or points to, its span if it is a node and
+// the fields set, which aren't zero.
+func dumpStruct(sb *strings.Builder, value reflect.Value, depth int) {
+	if node, ok := value.Interface().(Node); ok && node.Pos().IsValid() {
+		fmt.Fprintf(sb, "%s %s-%s\n", reflect.Indirect(value).Type().Name(), node.Pos(), node.End())
+	} else {
+		sb.WriteString(reflect.Indirect(value).Type().Name() + "\n")
+	}
+
+	// Data types and the values of literals are always written, as the zero data type is i32 and
+	// a zero literal is 0 or false
+	literal := isLiteral(value.Interface())
+	value = reflect.Indirect(value)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() || field.Type == spanType || (value.Field(i).IsZero() && field.Type != dataTypeType && !literal) {
+			continue
+		}
+		if value.Field(i).Kind() == reflect.Slice && value.Field(i).Len() == 0 {
+			continue
+		}
+		dumpValue(sb, field.Name, value.Field(i), depth+1)
+	}
+}
+
+// isLiteral reports whether the value is a literal of a number or a bool.
+func isLiteral(value any) bool {
+	switch value.(type) {
+	case *IntLiteralNode, *FloatLiteralNode, *BoolLiteralNode:
+		return true
+	}
+	return false
+}
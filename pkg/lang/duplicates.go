@@ -0,0 +1,35 @@
+package lang
+
+// checkDuplicates reports a diagnostic for every declaration of a name which was declared before,
+// see Resolution.Duplicates.
+func (c *checker) checkDuplicates() {
+	for _, duplicate := range c.resolution.Duplicates {
+		c.diagnostics = append(c.diagnostics, duplicateDiagnostic(duplicate))
+	}
+}
+
+// duplicateDiagnostic returns the diagnostic about the duplicate declaration at the span of the
+// declaration, naming the position of the previous declaration if it is known, e.g.
+// 1:31: duplicate declaration of function f, first declared at 1:1.
+func duplicateDiagnostic(duplicate Duplicate) Diagnostic {
+	symbol := duplicate.Symbol
+	var err error
+	switch symbol.Kind {
+	case SymbolVariable:
+		err = newError(MessageDuplicateVariable, symbol.Name)
+	case SymbolParameter:
+		err = newError(MessageDuplicateParameter, symbol.Name, symbol.Scope.Node.(*FunctionNode).Name)
+	case SymbolEmbed:
+		err = newError(MessageDuplicateEmbed, symbol.Name)
+	case SymbolFunction:
+		err = newError(MessageDuplicateFunction, symbol.Name)
+	case SymbolStruct:
+		err = newError(MessageDuplicateStruct, symbol.Name)
+	default:
+		err = newError(MessageDuplicateTypeName, symbol.Name)
+	}
+	if previous := duplicate.Previous.Node.Pos(); previous.IsValid() {
+		err = newError(MessageFirstDeclaredAt, err, previous)
+	}
+	return newDiagnostic(symbol.Node, err)
+}
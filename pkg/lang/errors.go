@@ -0,0 +1,269 @@
+package lang
+
+import "errors"
+
+// The kinds of the errors of the compiler. Every error the parser and the code generation fail
+// with is of one of the kinds, so programs embedding the compiler can tell them apart with
+// errors.Is instead of matching messages, e.g. errors.Is(err, lang.ErrTypeMismatch). An error may
+// be of more than one kind, e.g. an invalid value for a let statement which has the wrong data type
+// is of ErrInvalidValueType and ErrTypeMismatch. Warnings and the notes added to errors, e.g. of
+// the name probably meant, have no kind.
+var (
+	ErrSyntax               = errors.New("syntax error")               // The source code isn't valid, every ParseError is one.
+	ErrUnexpectedToken      = errors.New("unexpected token")           // The parser got a token or the end of the input it didn't expect.
+	ErrUnknownFunction      = errors.New("unknown function")           // A call of a function the program doesn't declare.
+	ErrUnknownVariable      = errors.New("unknown variable")           // A use of a variable which isn't declared in the scope.
+	ErrUnknownType          = errors.New("unknown type")               // A use of a type, struct or field the program doesn't declare.
+	ErrTypeMismatch         = errors.New("type mismatch")              // A value of a data type which can't be used where it is.
+	ErrInvalidValueType     = errors.New("invalid value type")         // A value which can't be used where it is, e.g. as the value of a let statement.
+	ErrInvalidType          = errors.New("invalid type")               // A declared data type which isn't valid, e.g. of a parameter.
+	ErrArgumentCount        = errors.New("wrong number of parameters") // A call with too few or too many parameters.
+	ErrDuplicateDeclaration = errors.New("duplicate declaration")      // A name declared more than once.
+	ErrMissingReturn        = errors.New("missing return")             // A function with a return type which can end without a return.
+	ErrUnsupported          = errors.New("unsupported")                // A declaration or statement where it isn't supported, e.g. a nested function.
+	ErrFile                 = errors.New("file error")                 // A file the program embeds or imports which can't be read.
+	ErrImportCycle          = errors.New("import cycle")               // Files importing each other, directly or through other files.
+	ErrBudgetExceeded       = errors.New("budget exceeded")            // Generated code or a compile-time evaluation exceeding its limit.
+	ErrInvalidNode          = errors.New("invalid node")               // A node of the abstract syntax tree which isn't valid, see Validate.
+	ErrInvalidEdit          = errors.New("invalid edit")               // A TextEdit which isn't within the source code it edits.
+	ErrJSON                 = errors.New("JSON error")                 // A syntax tree which can't be encoded as JSON, or JSON which isn't one.
+	ErrUnknownPass          = errors.New("unknown pass")               // A pass which isn't registered, see RegisterPass.
+	ErrPassFailed           = errors.New("pass failed")                // A pass which returned an error.
+)
+
+// messageKinds holds the kind of the messages of errors by ID.
+var messageKinds = map[MessageID]error{
+	MessageExpectedType:                                    ErrUnexpectedToken,
+	MessageExpectedIdentifierAfterSemicolon:                ErrUnexpectedToken,
+	MessageExpectedSemicolonAfterValue:                     ErrUnexpectedToken,
+	MessageExpectedCloseCurlyAfterFunctionBody:             ErrUnexpectedToken,
+	MessageExpectedOpenCurlyAfterStructName:                ErrUnexpectedToken,
+	MessageExpectedOpenCurlyAfterFunctionParameters:        ErrUnexpectedToken,
+	MessageExpectedIntValue:                                ErrUnexpectedToken,
+	MessageUnexpectedIdentifier:                            ErrUnexpectedToken,
+	MessageUnexpectedToken:                                 ErrUnexpectedToken,
+	MessageUnexpectedTokenAfterExpression:                  ErrUnexpectedToken,
+	MessageUnexpectedEndOfInput:                            ErrUnexpectedToken,
+	MessageExpectedValue:                                   ErrUnexpectedToken,
+	MessageExpectedTypeAfterStructField:                    ErrUnexpectedToken,
+	MessageExpectedTypeAfterFunctionParameter:              ErrUnexpectedToken,
+	MessageExpectedTypeAfterAs:                             ErrUnexpectedToken,
+	MessageExpectedTypeAfterColon:                          ErrUnexpectedToken,
+	MessageExpectedIndexAfterIdentifier:                    ErrUnexpectedToken,
+	MessageExpectedIdentifierAfterStruct:                   ErrUnexpectedToken,
+	MessageExpectedIdentifierAfterEmbed:                    ErrUnexpectedToken,
+	MessageExpectedFileNameAfterEmbed:                      ErrUnexpectedToken,
+	MessageExpectedFileNameAfterImport:                     ErrUnexpectedToken,
+	MessageExpectedFunctionAfterExtern:                     ErrUnexpectedToken,
+	MessageExpectedInlineIRAfterLLVM:                       ErrUnexpectedToken,
+	MessageExpectedPackageName:                             ErrUnexpectedToken,
+	MessageExpectedLetAfterQualifier:                       ErrUnexpectedToken,
+	MessageExpectedMemoryOrdering:                          ErrUnexpectedToken,
+	MessageExpectedCloseParenthesisAfterMemoryOrdering:     ErrUnexpectedToken,
+	MessageExpectedIdentifierAfterLet:                      ErrUnexpectedToken,
+	MessageExpectedIdentifierAfterFunction:                 ErrUnexpectedToken,
+	MessageExpectedIdentifierAfterFor:                      ErrUnexpectedToken,
+	MessageExpectedIdentifierAfterShortVariableAssignment:  ErrUnexpectedToken,
+	MessageExpectedFieldName:                               ErrUnexpectedToken,
+	MessageExpectedFieldNameAfterDot:                       ErrUnexpectedToken,
+	MessageExpectedArrayLength:                             ErrUnexpectedToken,
+	MessageExpectedLessThanAfterIdentifier:                 ErrUnexpectedToken,
+	MessageExpectedShortVariableAssignmentAfterIdentifier:  ErrUnexpectedToken,
+	MessageExpectedCloseCurlyAfterWhileBody:                ErrUnexpectedToken,
+	MessageExpectedCloseCurlyAfterStructFields:             ErrUnexpectedToken,
+	MessageExpectedStructAfterAttribute:                    ErrUnexpectedToken,
+	MessageExpectedOpenParenthesisAfterAlign:               ErrUnexpectedToken,
+	MessageExpectedAlignment:                               ErrUnexpectedToken,
+	MessageExpectedCloseParenthesisAfterAlignment:          ErrUnexpectedToken,
+	MessageExpectedGreaterThanAfterElementType:             ErrUnexpectedToken,
+	MessageExpectedColonInConditional:                      ErrUnexpectedToken,
+	MessageExpectedIdentifierAfterTypeKeyword:              ErrUnexpectedToken,
+	MessageExpectedEqualsAfterTypeAlias:                    ErrUnexpectedToken,
+	MessageUnexpectedGreaterThanAfterType:                  ErrUnexpectedToken,
+	MessageExpectedCloseCurlyAfterFieldValues:              ErrUnexpectedToken,
+	MessageExpectedOpenCurlyAfterWhileCondition:            ErrUnexpectedToken,
+	MessageExpectedFor:                                     ErrUnexpectedToken,
+	MessageExpectedAddSignAfterValue:                       ErrUnexpectedToken,
+	MessageExpectedAddSignAfterIdentifier:                  ErrUnexpectedToken,
+	MessageExpectedAddSignAfterAddSign:                     ErrUnexpectedToken,
+	MessageExpectedCloseSquareAfterIndex:                   ErrUnexpectedToken,
+	MessageExpectedCloseSquareAfterArrayLength:             ErrUnexpectedToken,
+	MessageExpectedCloseSquareAfterArrayElements:           ErrUnexpectedToken,
+	MessageExpectedOpenSquare:                              ErrUnexpectedToken,
+	MessageExpectedEqualsAfterLet:                          ErrUnexpectedToken,
+	MessageExpectedEqualsAfterIndex:                        ErrUnexpectedToken,
+	MessageExpectedEqualsAfterIdentifier:                   ErrUnexpectedToken,
+	MessageExpectedColonAfterFieldName:                     ErrUnexpectedToken,
+	MessageExpectedCloseParenthesisAfterWhileCondition:     ErrUnexpectedToken,
+	MessageExpectedCloseParenthesisAfterParameters:         ErrUnexpectedToken,
+	MessageExpectedCloseParenthesisAfterFunctionParameters: ErrUnexpectedToken,
+	MessageExpectedCloseParenthesisAfterCastValue:          ErrUnexpectedToken,
+	MessageExpectedOpenParenthesisAfterType:                ErrUnexpectedToken,
+	MessageExpectedOpenParenthesisAfterFunctionName:        ErrUnexpectedToken,
+	MessageExpectedOpenParenthesisAfterCaller:              ErrUnexpectedToken,
+	MessageExpectedTypeAfterNew:                            ErrUnexpectedToken,
+	MessageExpectedCloseParenthesisAfterNewType:            ErrUnexpectedToken,
+	MessageExpectedOpenParenthesisAfterWhile:               ErrUnexpectedToken,
+	MessageExpectedCallAfterSpawn:                          ErrUnexpectedToken,
+	MessageExpectedTypeParameter:                           ErrUnexpectedToken,
+	MessageExpectedCloseAngleAfterTypeParameters:           ErrUnexpectedToken,
+	MessageFeatureDisabled:                                 ErrSyntax,
+	MessageNestingTooDeep:                                  ErrSyntax,
+	MessageReservedWord:                                    ErrSyntax,
+	MessageInvalidArrayLength:                              ErrSyntax,
+	MessageReservedPrefix:                                  ErrSyntax,
+	MessageExternBody:                                      ErrSyntax,
+	MessagePackageNotFirst:                                 ErrSyntax,
+	MessageDuplicateQualifier:                              ErrSyntax,
+	MessageUnknownMemoryOrdering:                           ErrSyntax,
+	MessageUnknownStructAttribute:                          ErrSyntax,
+	MessageUnknownFunctionAttribute:                        ErrSyntax,
+	MessageConflictingFunctionAttributes:                   ErrSyntax,
+	MessageUnknownFieldAttribute:                           ErrSyntax,
+	MessageInvalidAlignment:                                ErrSyntax,
+	MessageInvalidSpawnTarget:                              ErrUnknownFunction,
+	MessageNilFunctionValue:                                ErrUnknownFunction,
+	MessageNilFunctionType:                                 ErrUnknownFunction,
+	MessageCallerNotFound:                                  ErrUnknownFunction,
+	MessageVariableNotFound:                                ErrUnknownVariable,
+	MessageUnknownType:                                     ErrUnknownType,
+	MessageUnknownStruct:                                   ErrUnknownType,
+	MessageUnknownField:                                    ErrUnknownType,
+	MessageVoidCallAsValue:                                 ErrTypeMismatch,
+	MessageFieldType:                                       ErrTypeMismatch,
+	MessageAtomicType:                                      ErrTypeMismatch,
+	MessageAtomicOperand:                                   ErrTypeMismatch,
+	MessageAtomicAddOperand:                                ErrTypeMismatch,
+	MessageInvalidAddOperandType:                           ErrTypeMismatch,
+	MessageInvalidCast:                                     ErrTypeMismatch,
+	MessageInvalidArrayIndexType:                           ErrTypeMismatch,
+	MessageInvalidBuiltinValue:                             ErrTypeMismatch,
+	MessageGenericParameterMismatch:                        ErrTypeMismatch,
+	MessageArrayLiteralType:                                ErrTypeMismatch,
+	MessageFloatLiteralType:                                ErrTypeMismatch,
+	MessageBoolLiteralType:                                 ErrTypeMismatch,
+	MessageValueType:                                       ErrTypeMismatch,
+	MessageIntegerLiteralType:                              ErrTypeMismatch,
+	MessagePrintType:                                       ErrTypeMismatch,
+	MessageComparisonType:                                  ErrTypeMismatch,
+	MessageFormatType:                                      ErrTypeMismatch,
+	MessageEmptyArrayLiteral:                               ErrTypeMismatch,
+	MessageIndexType:                                       ErrTypeMismatch,
+	MessageDereferenceType:                                 ErrTypeMismatch,
+	MessageAppendType:                                      ErrTypeMismatch,
+	MessageUntypedNone:                                     ErrTypeMismatch,
+	MessageUnwrapType:                                      ErrTypeMismatch,
+	MessageUntypedErr:                                      ErrTypeMismatch,
+	MessageTryType:                                         ErrTypeMismatch,
+	MessageTryReturnType:                                   ErrTypeMismatch,
+	MessageConditionType:                                   ErrTypeMismatch,
+	MessageShiftOperandType:                                ErrTypeMismatch,
+	MessageMathType:                                        ErrTypeMismatch,
+	MessageMathFloatType:                                   ErrTypeMismatch,
+	MessageFreeType:                                        ErrTypeMismatch,
+	MessageInvalidArrayElement:                             ErrInvalidValueType,
+	MessageUnexpectedReturnValue:                           ErrInvalidValueType,
+	MessageInvalidExitStatus:                               ErrInvalidValueType,
+	MessageThreadLocalInitializer:                          ErrInvalidValueType,
+	MessageMissingReturnValue:                              ErrInvalidValueType,
+	MessageInvalidValueType:                                ErrInvalidValueType,
+	MessageInvalidInitValueType:                            ErrInvalidValueType,
+	MessageInvalidLetValue:                                 ErrInvalidValueType,
+	MessageInvalidFieldValue:                               ErrInvalidValueType,
+	MessageInvalidAssignmentValue:                          ErrInvalidValueType,
+	MessageInvalidArrayElementValue:                        ErrInvalidValueType,
+	MessageInvalidFieldAssignmentValue:                     ErrInvalidValueType,
+	MessageInvalidDereferenceAssignmentValue:               ErrInvalidValueType,
+	MessageInvalidReturnValue:                              ErrInvalidValueType,
+	MessageInvalidCallerParameter:                          ErrInvalidValueType,
+	MessageInvalidBuiltinParameter:                         ErrInvalidValueType,
+	MessageInvalidLiteral:                                  ErrInvalidValueType,
+	MessageInvalidArrayIndex:                               ErrInvalidValueType,
+	MessageInvalidAddOperation:                             ErrInvalidValueType,
+	MessageIndexOutOfBounds:                                ErrInvalidValueType,
+	MessageConstantOverflow:                                ErrInvalidValueType,
+	MessageAssignToArgument:                                ErrInvalidValueType,
+	MessageAssignToArrayValue:                              ErrInvalidValueType,
+	MessageAssignToStructValue:                             ErrInvalidValueType,
+	MessageAddressOfValue:                                  ErrInvalidValueType,
+	MessageInvalidOptionValue:                              ErrInvalidValueType,
+	MessageInvalidUnwrapFallback:                           ErrInvalidValueType,
+	MessageInvalidResultValue:                              ErrInvalidValueType,
+	MessageInvalidErrorCode:                                ErrInvalidValueType,
+	MessageInvalidConditionalValue:                         ErrInvalidValueType,
+	MessageInvalidShiftOperation:                           ErrInvalidValueType,
+	MessageShiftAmount:                                     ErrInvalidValueType,
+	MessageArrayLiteralOverflow:                            ErrInvalidValueType,
+	MessageInvalidParameterType:                            ErrInvalidType,
+	MessageInvalidFieldType:                                ErrInvalidType,
+	MessageInvalidLetType:                                  ErrInvalidType,
+	MessageInvalidNewType:                                  ErrInvalidType,
+	MessageInvalidReturnType:                               ErrInvalidType,
+	MessageRecursiveStruct:                                 ErrInvalidType,
+	MessageUninferredTypeParameter:                         ErrInvalidType,
+	MessageGenericInstantiation:                            ErrInvalidType,
+	MessageTypeAliasCycle:                                  ErrInvalidType,
+	MessageExpectedOneParameter:                            ErrArgumentCount,
+	MessageExpectedNoParameters:                            ErrArgumentCount,
+	MessageExpectedTwoParameters:                           ErrArgumentCount,
+	MessageExpectedSliceAndValues:                          ErrArgumentCount,
+	MessageExpectedParameters:                              ErrArgumentCount,
+	MessageExpectedFormatString:                            ErrArgumentCount,
+	MessageDuplicateMain:                                   ErrDuplicateDeclaration,
+	MessageDuplicateTypeParameter:                          ErrDuplicateDeclaration,
+	MessageDuplicateField:                                  ErrDuplicateDeclaration,
+	MessageDuplicateFieldValue:                             ErrDuplicateDeclaration,
+	MessageDuplicateStruct:                                 ErrDuplicateDeclaration,
+	MessageDuplicateEmbed:                                  ErrDuplicateDeclaration,
+	MessageDuplicateFunction:                               ErrDuplicateDeclaration,
+	MessageDuplicateVariable:                               ErrDuplicateDeclaration,
+	MessageDuplicateParameter:                              ErrDuplicateDeclaration,
+	MessageDuplicateTypeName:                               ErrDuplicateDeclaration,
+	MessageExternDeclared:                                  ErrDuplicateDeclaration,
+	MessageInlineIRRedefined:                               ErrDuplicateDeclaration,
+	MessageMissingReturn:                                   ErrMissingReturn,
+	MessageInvalidMain:                                     ErrUnsupported,
+	MessageLibraryStatement:                                ErrUnsupported,
+	MessageSpawnGarbageCollection:                          ErrUnsupported,
+	MessageNestedStruct:                                    ErrUnsupported,
+	MessageNestedEmbed:                                     ErrUnsupported,
+	MessageNestedThreadLocal:                               ErrUnsupported,
+	MessageNestedFunction:                                  ErrUnsupported,
+	MessageGenericExtern:                                   ErrUnsupported,
+	MessageTryOutsideFunction:                              ErrUnsupported,
+	MessageNestedTypeAlias:                                 ErrUnsupported,
+	MessageNestedImport:                                    ErrUnsupported,
+	MessageNestedExtern:                                    ErrUnsupported,
+	MessageInlineIR:                                        ErrUnsupported,
+	MessageEmbedFile:                                       ErrFile,
+	MessageImportFile:                                      ErrFile,
+	MessageUnresolvedImport:                                ErrUnsupported,
+	MessageImportCycle:                                     ErrImportCycle,
+	MessageFunctionInstructionBudget:                       ErrBudgetExceeded,
+	MessageFunctionBlockBudget:                             ErrBudgetExceeded,
+	MessageInstructionBudget:                               ErrBudgetExceeded,
+	MessageBlockBudget:                                     ErrBudgetExceeded,
+	MessageEvaluationSteps:                                 ErrBudgetExceeded,
+	MessageInvalidNodeNil:                                  ErrInvalidNode,
+	MessageInvalidNodeName:                                 ErrInvalidNode,
+	MessageInvalidNodeType:                                 ErrInvalidNode,
+	MessageInvalidNodeOperator:                             ErrInvalidNode,
+	MessageInvalidNodeKind:                                 ErrInvalidNode,
+	MessageInvalidEdit:                                     ErrInvalidEdit,
+	MessageJSONDataType:                                    ErrJSON,
+	MessageJSONValue:                                       ErrJSON,
+	MessageJSONNode:                                        ErrJSON,
+	MessageUnknownPass:                                     ErrUnknownPass,
+	MessagePassFailed:                                      ErrPassFailed,
+}
+
+// Is reports whether the message is of the kind, one of the Err variables of the package.
+func (e *MessageError) Is(target error) bool {
+	kind, ok := messageKinds[e.ID]
+	return ok && kind == target
+}
+
+// Is reports whether the target is ErrSyntax, the kind of every syntax error.
+func (e *ParseError) Is(target error) bool {
+	return target == ErrSyntax
+}
@@ -0,0 +1,66 @@
+package lang
+
+import (
+	"tinygo.org/x/go-llvm"
+)
+
+// exitIdentifier is the identifier of the builtin function ending the program with an exit status,
+// which is also the identifier of the C function it calls.
+const exitIdentifier = "exit"
+
+// isExitBuiltin reports whether a call of the name refers to the exit builtin, which is the case
+// unless the program declares a function or an extern function named exit, e.g. to pass it attributes.
+func isExitBuiltin(scope *Scope, name string) bool {
+	return name == exitIdentifier && !declaresCaller(scope, name)
+}
+
+// declaresCaller reports whether the program declares a function or an extern function of the
+// name, which takes precedence over a builtin function of the same name.
+func declaresCaller(scope *Scope, name string) bool {
+	if _, ok := scope.Callers.Get(name); ok {
+		return true
+	}
+	_, ok := globalScope.Callers.Get(name)
+	return ok
+}
+
+// generateExit is a function that generates LLVM IR code for a call of the exit builtin, which ends
+// the program with the exit status passed as its only parameter, an i32, by calling the C exit
+// function. Statements following the call are generated into a new block without predecessors, like
+// statements following a return.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+//
+// Returns an error if there isn't exactly one parameter or it isn't an i32 value.
+func generateExit(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) != 1 {
+		return llvm.Value{}, 0, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+	}
+	status, err := generateTypedValue(scope, functionBuilder, callerNode.Args[0], Integer32Type)
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, 1, callerNode.FunctionName, err)
+	}
+
+	exitType, exit := exitFunction(functionBuilder)
+	call := functionBuilder.CreateCall(exitType, exit, []llvm.Value{status}, "")
+	functionBuilder.CreateUnreachable()
+
+	afterExit := globalScope.Context.AddBasicBlock(functionBuilder.GetInsertBlock().Parent(), "after_exit")
+	functionBuilder.SetInsertPointAtEnd(afterExit)
+	return call, VoidType, nil
+}
+
+// exitFunction returns the type and the declaration of the C exit function, which never returns.
+func exitFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	functionType := llvm.FunctionType(globalScope.Context.VoidType(), []llvm.Type{globalScope.Context.Int32Type()}, false)
+	module := functionBuilder.GetInsertBlock().Parent().GlobalParent()
+	if exit := module.NamedFunction(exitIdentifier); !exit.IsNil() {
+		return functionType, exit
+	}
+	_, exit := runtimeFunction(functionBuilder, exitIdentifier, functionType, nil)
+	// The attribute can't be invalid, so there is no error to handle
+	_ = addFunctionAttributes(exit, exitIdentifier, []string{noreturnAttribute})
+	return functionType, exit
+}
@@ -0,0 +1,170 @@
+package lang
+
+import (
+	"fmt"
+	"math"
+)
+
+// Expr is an interface representing the nodes of the abstract syntax tree which are values, e.g.
+// literals, identifiers, operations and calls. The values of statements and of other values are
+// expressions.
+type Expr interface {
+	Node
+	IsExpr()
+}
+
+// BinaryExpr is an interface representing the expressions combining two operands, i.e. additions,
+// shifts and comparisons.
+type BinaryExpr interface {
+	Expr
+	// Operands returns the left and the right operand.
+	Operands() (Expr, Expr)
+}
+
+// IdentifierNode represents the name of a variable, a parameter or an embedded file used as value.
+// example: x
+type IdentifierNode struct {
+	Span
+	Name string
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *IdentifierNode) IsNode() {}
+
+// String returns the name.
+func (n *IdentifierNode) String() string {
+	return n.Name
+}
+
+// IntLiteralNode represents an integer literal. Its data type is i32, or i64 if it doesn't fit into
+// 32 bits, unless it takes the data type of where it is used.
+// example: 42
+type IntLiteralNode struct {
+	Span
+	Value int64
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *IntLiteralNode) IsNode() {}
+
+// String returns the value in decimal.
+func (n *IntLiteralNode) String() string {
+	return fmt.Sprint(n.Value)
+}
+
+// dataType returns the default data type of the literal.
+func (n *IntLiteralNode) dataType() dataType {
+	if n.Value < math.MinInt32 || n.Value > math.MaxInt32 {
+		return Integer64Type
+	}
+	return Integer32Type
+}
+
+// FloatLiteralNode represents a floating point literal, which is a number with a fraction. Its data
+// type is f64, unless it takes the data type of where it is used.
+// example: 1.5
+type FloatLiteralNode struct {
+	Span
+	Value float64
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *FloatLiteralNode) IsNode() {}
+
+// String returns the value.
+func (n *FloatLiteralNode) String() string {
+	return fmt.Sprint(n.Value)
+}
+
+// BoolLiteralNode represents the literal true or false.
+// example: true
+type BoolLiteralNode struct {
+	Span
+	Value bool
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *BoolLiteralNode) IsNode() {}
+
+// String returns true or false.
+func (n *BoolLiteralNode) String() string {
+	return fmt.Sprint(n.Value)
+}
+
+// Operands returns the left and the right operand of the addition.
+func (n *AddOperationNode) Operands() (Expr, Expr) {
+	return n.LeftValue, n.RightValue
+}
+
+// Operands returns the shifted value and the number of bits.
+func (n *ShiftOperationNode) Operands() (Expr, Expr) {
+	return n.LeftValue, n.RightValue
+}
+
+// Operands returns the compared values.
+func (n *ComparisonNode) Operands() (Expr, Expr) {
+	return n.LeftValue, n.RightValue
+}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *IdentifierNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *IntLiteralNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *FloatLiteralNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *BoolLiteralNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *AddOperationNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *ShiftOperationNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *ComparisonNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *ConditionalNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *CallerNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *SpawnNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *CastNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *StringLiteralNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *ArrayLiteralNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *StructLiteralNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *NewNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *NoneNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *AddressNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *DereferenceNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *TryNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *IndexNode) IsExpr() {}
+
+// IsExpr is an empty method to satisfy the Expr interface.
+func (n *FieldNode) IsExpr() {}
@@ -0,0 +1,314 @@
+package lang
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format regenerates the canonical source code of the program represented by the nodes: one
+// statement per line, bodies indented by a tab and single spaces between operators, e.g.
+//
+//	function add(a i32, b i32) i32 {
+//		return a + b
+//	}
+//	printf(add(1, 2))
+//
+// Parsing the source code results in the same nodes. As gusty has no parentheses grouping values,
+// the values of nodes which the parser doesn't create, e.g. an add operation as the right operand
+// of another add operation, are written as if they were parsed, so they parse into other nodes.
+func Format(nodes []Node) string {
+	return FormatWithComments(nodes, nil)
+}
+
+// FormatWithComments regenerates the canonical source code of the program represented by the nodes
+// like Format, and keeps the comments attached to its statements: leading comments are written on
+// the lines preceding the statement, a trailing comment which was on the last line of the
+// statement follows it on its line, the other trailing comments follow it on lines of their own.
+func FormatWithComments(nodes []Node, comments CommentMap) string {
+	f := formatter{comments: comments}
+	f.statements(nodes)
+	return f.String()
+}
+
+// formatter writes the source code of nodes, indenting the statements by the depth of their body.
+type formatter struct {
+	strings.Builder
+	depth    int
+	comments CommentMap
+}
+
+// statements writes the statements, one per line, with their comments.
+func (f *formatter) statements(nodes []Node) {
+	for _, node := range nodes {
+		indent := strings.Repeat("\t", f.depth)
+		comments := f.comments[node]
+		if comments != nil {
+			for _, comment := range comments.Leading {
+				f.WriteString(indent + comment.Text + "\n")
+			}
+		}
+		f.WriteString(indent)
+		f.statement(node)
+		if comments != nil {
+			for _, comment := range comments.Trailing {
+				if comment.Pos().Line == node.End().Line {
+					f.WriteString(" " + comment.Text)
+				} else {
+					f.WriteString("\n" + indent + comment.Text)
+				}
+			}
+		}
+		f.WriteString("\n")
+	}
+}
+
+// body writes the statements of a body followed by the close curly bracket ending it, the open
+// curly bracket is written by the statement.
+func (f *formatter) body(nodes []Node) {
+	f.WriteString("{\n")
+	f.depth++
+	f.statements(nodes)
+	f.depth--
+	f.WriteString(strings.Repeat("\t", f.depth) + "}")
+}
+
+// statement writes the statement without indentation and line break.
+func (f *formatter) statement(node Node) {
+	switch n := node.(type) {
+	case *FunctionNode:
+		f.attributes(n.Attributes)
+		f.WriteString("function " + n.Name)
+		if len(n.TypeParameters) > 0 {
+			f.WriteString("<" + strings.Join(n.TypeParameters, ", ") + ">")
+		}
+		f.signature(n.Parameters, n.ReturnType)
+		f.WriteString(" ")
+		f.body(n.Body)
+	case *ExternNode:
+		f.attributes(n.Attributes)
+		f.WriteString("extern function " + n.Name)
+		f.signature(n.Parameters, n.ReturnType)
+	case *StructNode:
+		if n.Packed {
+			f.WriteString("@" + packedAttribute + " ")
+		}
+		f.WriteString("struct " + n.Name + " {\n")
+		for _, field := range n.Fields {
+			f.WriteString(strings.Repeat("\t", f.depth+1) + field.Identifier + " " + field.Type.String())
+			if field.Alignment != 0 {
+				fmt.Fprintf(f, " @%s(%d)", alignAttribute, field.Alignment)
+			}
+			f.WriteString("\n")
+		}
+		f.WriteString(strings.Repeat("\t", f.depth) + "}")
+	case *TypeAliasNode:
+		f.WriteString("type " + n.Name + " = " + n.Type.String())
+	case *EmbedNode:
+		f.WriteString("embed " + n.Identifier + " " + strconv.Quote(n.Path))
+	case *ImportNode:
+		f.WriteString("import " + strconv.Quote(n.Path))
+	case *PackageNode:
+		f.WriteString("package " + n.Name)
+	case *InlineIRNode:
+		f.WriteString("llvm {" + n.IR + "}")
+	case *LetNode:
+		if n.ThreadLocal {
+			f.WriteString("threadlocal ")
+		}
+		if n.Volatile {
+			f.WriteString("volatile ")
+		}
+		if n.Ordering != NotAtomic {
+			f.WriteString("atomic")
+			if n.Ordering != OrderingSequentiallyConsistent {
+				f.WriteString("(" + memoryOrderingName(n.Ordering) + ")")
+			}
+			f.WriteString(" ")
+		}
+		f.WriteString("let " + n.Identifier)
+		if n.HasType {
+			f.WriteString(": " + n.Type.String())
+		}
+		f.WriteString(" = ")
+		f.value(n.Value)
+	case *AssignmentNode:
+		f.WriteString(n.Identifier + " = ")
+		f.value(n.Value)
+	case *IndexAssignmentNode:
+		f.assignment(n.Target, n.Value)
+	case *FieldAssignmentNode:
+		f.assignment(n.Target, n.Value)
+	case *DereferenceAssignmentNode:
+		f.assignment(n.Target, n.Value)
+	case *ReturnNode:
+		f.WriteString("return")
+		if n.Value != nil {
+			f.WriteString(" ")
+			f.value(n.Value)
+		}
+	case *ForNode:
+		fmt.Fprintf(f, "for %s := ", n.Init.Identifier)
+		f.value(n.Init.Value)
+		fmt.Fprintf(f, "; %s < ", n.Condition.LeftValue)
+		f.value(n.Condition.RightValue)
+		fmt.Fprintf(f, "; %s++ ", n.Post.Identifier)
+		f.body(n.Body)
+	case *WhileNode:
+		fmt.Fprintf(f, "while (%s) ", n.Condition)
+		f.body(n.Body)
+	case Expr:
+		f.value(n)
+	}
+}
+
+// attributes writes the attributes of a function declaration, each followed by a space.
+func (f *formatter) attributes(attributes []string) {
+	for _, attribute := range attributes {
+		f.WriteString("@" + attribute + " ")
+	}
+}
+
+// signature writes the parameters and the return type of a function declaration.
+func (f *formatter) signature(parameters []*Parameter, returnType dataType) {
+	declarations := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		declarations[i] = parameter.Identifier + " " + parameter.Type.String()
+	}
+	f.WriteString("(" + strings.Join(declarations, ", ") + ")")
+	if returnType != VoidType {
+		f.WriteString(" " + returnType.String())
+	}
+}
+
+// assignment writes the assignment of the value to the array element, field or dereferenced pointer.
+func (f *formatter) assignment(target Expr, value Expr) {
+	f.value(target)
+	f.WriteString(" = ")
+	f.value(value)
+}
+
+// value writes the value.
+func (f *formatter) value(value Expr) {
+	switch v := value.(type) {
+	case *IdentifierNode, *IntLiteralNode, *BoolLiteralNode:
+		fmt.Fprint(f, v)
+	case *FloatLiteralNode:
+		// A float literal keeps a fraction, so it isn't parsed as an integer
+		literal := strconv.FormatFloat(v.Value, 'f', -1, 64)
+		if !strings.ContainsAny(literal, ".NI") {
+			literal += ".0"
+		}
+		f.WriteString(literal)
+	case *CallerNode:
+		f.WriteString(v.FunctionName + "(")
+		for i, arg := range v.Args {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			f.value(arg)
+		}
+		f.WriteString(")")
+	case *SpawnNode:
+		f.WriteString("spawn ")
+		f.value(v.Call)
+	case *AddOperationNode:
+		f.operation(v.LeftValue, string(TokenAdd), v.RightValue)
+	case *ShiftOperationNode:
+		operator := TokenShiftRight
+		if v.Left {
+			operator = TokenShiftLeft
+		}
+		f.operation(v.LeftValue, string(operator), v.RightValue)
+	case *ComparisonNode:
+		f.operation(v.LeftValue, v.Operator.String(), v.RightValue)
+	case *ConditionalNode:
+		f.value(v.Condition)
+		f.WriteString(" ? ")
+		f.value(v.True)
+		f.WriteString(" : ")
+		f.value(v.False)
+	case *CastNode:
+		// A cast to a built-in data type is written as a call of the type, which casts any value
+		if _, ok := v.Type.structure(); ok {
+			f.value(v.Value)
+			f.WriteString(" as " + v.Type.String())
+			return
+		}
+		f.WriteString(v.Type.String() + "(")
+		f.value(v.Value)
+		f.WriteString(")")
+	case *StringLiteralNode:
+		f.WriteString(strconv.Quote(v.Value))
+	case *ArrayLiteralNode:
+		f.WriteString("[")
+		for i, element := range v.Elements {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			f.value(element)
+		}
+		f.WriteString("]")
+	case *StructLiteralNode:
+		f.WriteString(v.Name + "{")
+		for i, field := range v.Fields {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			if field == nil {
+				// A missing field is written like a missing value
+				f.WriteString("<nil>")
+				continue
+			}
+			f.WriteString(field.Identifier + ": ")
+			f.value(field.Value)
+		}
+		f.WriteString("}")
+	case *NewNode:
+		f.WriteString("new(" + v.Type.String() + ")")
+	case *NoneNode:
+		f.WriteString("none")
+	case *AddressNode:
+		f.WriteString("&")
+		f.value(v.Value)
+	case *DereferenceNode:
+		f.WriteString("*")
+		f.value(v.Value)
+	case *TryNode:
+		f.WriteString("try ")
+		f.value(v.Value)
+	case *IndexNode:
+		f.value(v.Value)
+		f.WriteString("[")
+		f.value(v.Index)
+		f.WriteString("]")
+	case *FieldNode:
+		f.value(v.Value)
+		f.WriteString("." + v.Field)
+	default:
+		fmt.Fprint(f, v)
+	}
+}
+
+// operation writes the operands separated by the operator.
+func (f *formatter) operation(left Expr, operator string, right Expr) {
+	f.value(left)
+	f.WriteString(" " + operator + " ")
+	f.value(right)
+}
+
+// memoryOrderingName returns the name the memory ordering is declared with, e.g. relaxed.
+func memoryOrderingName(ordering MemoryOrdering) string {
+	names := make([]string, 0, len(memoryOrderings))
+	for name := range memoryOrderings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if memoryOrderings[name] == ordering {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,428 @@
+package lang
+
+import (
+	"tinygo.org/x/go-llvm"
+)
+
+// Constants for the identifiers of the runtime helpers and globals of the garbage collector.
+const (
+	// gcAllocIdentifier is the identifier of the runtime helper allocating a collected object.
+	gcAllocIdentifier = runtimePrefix + "gc_alloc"
+	// gcReallocIdentifier is the identifier of the runtime helper resizing a collected object.
+	gcReallocIdentifier = runtimePrefix + "gc_realloc"
+	// gcCollectIdentifier is the identifier of the runtime helper releasing the unreachable objects.
+	gcCollectIdentifier = runtimePrefix + "gc_collect"
+	// gcMarkIdentifier is the identifier of the runtime helper marking the object a word points into.
+	gcMarkIdentifier = runtimePrefix + "gc_mark"
+	// gcMarkRangeIdentifier is the identifier of the runtime helper marking the objects the words of a range point into.
+	gcMarkRangeIdentifier = runtimePrefix + "gc_mark_range"
+	// gcMarkGlobalsIdentifier is the identifier of the runtime helper marking the objects the globals point into.
+	gcMarkGlobalsIdentifier = runtimePrefix + "gc_mark_globals"
+	// gcObjectsIdentifier is the identifier of the global holding the list of allocated objects.
+	gcObjectsIdentifier = runtimePrefix + "gc_objects"
+	// gcAllocatedIdentifier is the identifier of the global counting the bytes allocated since the last collection.
+	gcAllocatedIdentifier = runtimePrefix + "gc_allocated"
+	// gcStackBottomIdentifier is the identifier of the global holding the address the stack of main starts at.
+	gcStackBottomIdentifier = runtimePrefix + "gc_stack_bottom"
+	// setjmpIdentifier is the identifier of the C function saving the registers, which the collector
+	// spills onto the stack so it finds the pointers they hold.
+	setjmpIdentifier = "_setjmp"
+	// memsetIdentifier is the identifier of the C function filling memory.
+	memsetIdentifier = "memset"
+	// frameAddressIdentifier is the identifier of the LLVM intrinsic returning the frame address of a function.
+	frameAddressIdentifier = "llvm.frameaddress.p0"
+)
+
+// gcThreshold is the number of bytes allocated since the last collection which starts a collection.
+const gcThreshold = 1 << 20
+
+// gcJmpBufSize is the number of bytes reserved for the registers saved by setjmp, which is larger
+// than the jmp_buf of the supported C libraries.
+const gcJmpBufSize = 256
+
+// Constants for the positions of the fields of the header preceding every collected object.
+const (
+	gcNext   = iota // The pointer to the header of the next object in the list of objects.
+	gcSize          // The number of bytes of the object.
+	gcMarked        // Whether the object is reachable, set while marking.
+)
+
+// gcHeaderType returns the LLVM struct type of the header preceding every collected object,
+// { ptr, i64, i64 }, whose size keeps the object aligned to 8 bytes.
+func gcHeaderType() llvm.Type {
+	return globalScope.Context.StructType([]llvm.Type{llvm.PointerType(globalScope.Context.Int8Type(), 0), globalScope.Context.Int64Type(), globalScope.Context.Int64Type()}, false)
+}
+
+// gcHeaderSize is the number of bytes of the header preceding every collected object.
+const gcHeaderSize = 24
+
+// heapAllocFunction returns the type and the function the generated code allocates heap memory
+// with, which is the allocator of the garbage collector if the options enable it and C malloc
+// otherwise. Both take the number of bytes and return a pointer to the memory.
+func heapAllocFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	if globalScope.Options.GarbageCollection {
+		return gcAllocFunction(functionBuilder)
+	}
+	return mallocFunction(functionBuilder)
+}
+
+// heapReallocFunction returns the type and the function the generated code resizes heap memory
+// with, which is the garbage collector's if the options enable it and C realloc otherwise.
+func heapReallocFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	if globalScope.Options.GarbageCollection {
+		return gcReallocFunction(functionBuilder)
+	}
+	return reallocFunction(functionBuilder)
+}
+
+// gcGlobal returns the global of the garbage collector with the given name and type, which is
+// added to the module of the current function, initialized to zero, the first time it is requested.
+func gcGlobal(functionBuilder llvm.Builder, name string, t llvm.Type) llvm.Value {
+	module := functionBuilder.GetInsertBlock().Parent().GlobalParent()
+	if global := module.NamedGlobal(name); !global.IsNil() {
+		return global
+	}
+	global := llvm.AddGlobal(module, t, name)
+	global.SetInitializer(llvm.ConstNull(t))
+	global.SetLinkage(llvm.InternalLinkage)
+	return global
+}
+
+// generateGCStackBottom stores the frame address of the main function into the global the collector
+// stops scanning the stack at, as the stack grows down and every variable of main lies below it.
+// Until it is stored, e.g. in a library, which has no main function, the collector never collects.
+//
+// mainBuilder:  The LLVM builder associated with the entry block of the main function.
+func generateGCStackBottom(mainBuilder llvm.Builder) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	frameAddressType, frameAddress := runtimeFunction(mainBuilder, frameAddressIdentifier, llvm.FunctionType(pointerType, []llvm.Type{globalScope.Context.Int32Type()}, false), nil)
+	bottom := mainBuilder.CreateCall(frameAddressType, frameAddress, []llvm.Value{llvm.ConstInt(globalScope.Context.Int32Type(), 0, false)}, "gc_stack_bottom")
+	mainBuilder.CreateStore(bottom, gcGlobal(mainBuilder, gcStackBottomIdentifier, pointerType))
+}
+
+// gcAllocFunction returns the type and the definition of the runtime helper allocating a collected
+// object. The object is allocated with malloc behind its header, zeroed and added to the list of
+// objects. Once more than gcThreshold bytes were allocated since the last collection, the
+// unreachable objects are released first.
+func gcAllocFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	int64Type := globalScope.Context.Int64Type()
+	functionType := llvm.FunctionType(pointerType, []llvm.Type{int64Type}, false)
+
+	return runtimeFunction(functionBuilder, gcAllocIdentifier, functionType, func(function llvm.Value) {
+		builder := globalScope.Context.NewBuilder()
+		defer builder.Dispose()
+
+		size := function.Param(0)
+		entry := globalScope.Context.AddBasicBlock(function, "entry")
+		collect := globalScope.Context.AddBasicBlock(function, "collect")
+		allocate := globalScope.Context.AddBasicBlock(function, "allocate")
+
+		// Collect once the threshold is exceeded
+		builder.SetInsertPointAtEnd(entry)
+		allocatedGlobal := gcGlobal(builder, gcAllocatedIdentifier, int64Type)
+		allocated := builder.CreateAdd(builder.CreateLoad(int64Type, allocatedGlobal, "allocated"), size, "")
+		exceeded := builder.CreateICmp(llvm.IntUGT, allocated, llvm.ConstInt(int64Type, gcThreshold, false), "exceeded")
+		builder.CreateCondBr(exceeded, collect, allocate)
+
+		builder.SetInsertPointAtEnd(collect)
+		collectType, collectFunction := gcCollectFunction(builder)
+		builder.CreateCall(collectType, collectFunction, []llvm.Value{}, "")
+		builder.CreateStore(llvm.ConstInt(int64Type, 0, false), allocatedGlobal)
+		builder.CreateBr(allocate)
+
+		// Allocate the object behind its header and add it to the list of objects
+		builder.SetInsertPointAtEnd(allocate)
+		builder.CreateStore(builder.CreateAdd(builder.CreateLoad(int64Type, allocatedGlobal, ""), size, ""), allocatedGlobal)
+		mallocType, malloc := mallocFunction(builder)
+		header := builder.CreateCall(mallocType, malloc, []llvm.Value{builder.CreateAdd(size, llvm.ConstInt(int64Type, gcHeaderSize, false), "")}, "header")
+		objectsGlobal := gcGlobal(builder, gcObjectsIdentifier, pointerType)
+		builder.CreateStore(builder.CreateLoad(pointerType, objectsGlobal, "objects"), builder.CreateStructGEP(gcHeaderType(), header, gcNext, ""))
+		builder.CreateStore(size, builder.CreateStructGEP(gcHeaderType(), header, gcSize, ""))
+		builder.CreateStore(llvm.ConstInt(int64Type, 0, false), builder.CreateStructGEP(gcHeaderType(), header, gcMarked, ""))
+		builder.CreateStore(header, objectsGlobal)
+
+		object := builder.CreateInBoundsGEP(globalScope.Context.Int8Type(), header, []llvm.Value{llvm.ConstInt(int64Type, gcHeaderSize, false)}, "object")
+		memsetType, memset := memsetFunction(builder)
+		builder.CreateCall(memsetType, memset, []llvm.Value{object, llvm.ConstInt(globalScope.Context.Int32Type(), 0, false), size}, "")
+		builder.CreateRet(object)
+	})
+}
+
+// gcReallocFunction returns the type and the definition of the runtime helper resizing a collected
+// object. It allocates a new object and copies the bytes of the old one, up to the smaller of both
+// sizes, into it; the old object is released by the next collection once it is unreachable.
+func gcReallocFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	int64Type := globalScope.Context.Int64Type()
+	functionType := llvm.FunctionType(pointerType, []llvm.Type{pointerType, int64Type}, false)
+
+	return runtimeFunction(functionBuilder, gcReallocIdentifier, functionType, func(function llvm.Value) {
+		builder := globalScope.Context.NewBuilder()
+		defer builder.Dispose()
+
+		old := function.Param(0)
+		size := function.Param(1)
+		entry := globalScope.Context.AddBasicBlock(function, "entry")
+		copying := globalScope.Context.AddBasicBlock(function, "copy")
+		done := globalScope.Context.AddBasicBlock(function, "done")
+
+		builder.SetInsertPointAtEnd(entry)
+		allocType, alloc := gcAllocFunction(builder)
+		object := builder.CreateCall(allocType, alloc, []llvm.Value{size}, "object")
+		empty := builder.CreateICmp(llvm.IntEQ, old, llvm.ConstPointerNull(pointerType), "empty")
+		builder.CreateCondBr(empty, done, copying)
+
+		builder.SetInsertPointAtEnd(copying)
+		headerAddress := builder.CreateSub(builder.CreatePtrToInt(old, int64Type, ""), llvm.ConstInt(int64Type, gcHeaderSize, false), "")
+		header := builder.CreateIntToPtr(headerAddress, pointerType, "header")
+		oldSize := builder.CreateLoad(int64Type, builder.CreateStructGEP(gcHeaderType(), header, gcSize, ""), "old_size")
+		smaller := builder.CreateICmp(llvm.IntULT, oldSize, size, "smaller")
+		count := builder.CreateSelect(smaller, oldSize, size, "count")
+		memcpyType, memcpy := memcpyFunction(builder)
+		builder.CreateCall(memcpyType, memcpy, []llvm.Value{object, old, count}, "")
+		builder.CreateBr(done)
+
+		builder.SetInsertPointAtEnd(done)
+		builder.CreateRet(object)
+	})
+}
+
+// gcCollectFunction returns the type and the definition of the runtime helper releasing the
+// unreachable objects. The collector is conservative: it spills the registers onto the stack with
+// setjmp, marks every object a word of the stack of main, a global or a marked object points into,
+// and releases the objects which weren't marked with free.
+func gcCollectFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	int64Type := globalScope.Context.Int64Type()
+	functionType := llvm.FunctionType(globalScope.Context.VoidType(), []llvm.Type{}, false)
+
+	return runtimeFunction(functionBuilder, gcCollectIdentifier, functionType, func(function llvm.Value) {
+		builder := globalScope.Context.NewBuilder()
+		defer builder.Dispose()
+
+		entry := globalScope.Context.AddBasicBlock(function, "entry")
+		mark := globalScope.Context.AddBasicBlock(function, "mark")
+		sweep := globalScope.Context.AddBasicBlock(function, "sweep")
+		check := globalScope.Context.AddBasicBlock(function, "check")
+		keep := globalScope.Context.AddBasicBlock(function, "keep")
+		release := globalScope.Context.AddBasicBlock(function, "release")
+		done := globalScope.Context.AddBasicBlock(function, "done")
+
+		// Spill the registers below the stack which is scanned, nothing is collected without a main function
+		builder.SetInsertPointAtEnd(entry)
+		registers := builder.CreateAlloca(llvm.ArrayType(globalScope.Context.Int8Type(), gcJmpBufSize), "registers")
+		registers.SetAlignment(16)
+		setjmpType, setjmp := setjmpFunction(builder)
+		builder.CreateCall(setjmpType, setjmp, []llvm.Value{registers}, "")
+		bottom := builder.CreateLoad(pointerType, gcGlobal(builder, gcStackBottomIdentifier, pointerType), "bottom")
+		builder.CreateCondBr(builder.CreateICmp(llvm.IntEQ, bottom, llvm.ConstPointerNull(pointerType), "no_stack"), done, mark)
+
+		// Mark the objects reachable from the stack and the globals
+		builder.SetInsertPointAtEnd(mark)
+		markRangeType, markRange := gcMarkRangeFunction(builder)
+		builder.CreateCall(markRangeType, markRange, []llvm.Value{registers, bottom}, "")
+		markGlobalsType, markGlobals := gcMarkGlobalsFunction(builder)
+		builder.CreateCall(markGlobalsType, markGlobals, []llvm.Value{}, "")
+		objectsGlobal := gcGlobal(builder, gcObjectsIdentifier, pointerType)
+		builder.CreateBr(sweep)
+
+		// Walk the list of objects through the address of the link to the current object
+		builder.SetInsertPointAtEnd(sweep)
+		link := builder.CreatePHI(pointerType, "link")
+		header := builder.CreateLoad(pointerType, link, "header")
+		builder.CreateCondBr(builder.CreateICmp(llvm.IntEQ, header, llvm.ConstPointerNull(pointerType), "end"), done, check)
+
+		builder.SetInsertPointAtEnd(check)
+		markedAddress := builder.CreateStructGEP(gcHeaderType(), header, gcMarked, "")
+		marked := builder.CreateLoad(int64Type, markedAddress, "marked")
+		builder.CreateCondBr(builder.CreateICmp(llvm.IntNE, marked, llvm.ConstInt(int64Type, 0, false), "reachable"), keep, release)
+
+		// Keep a marked object and clear its mark for the next collection
+		builder.SetInsertPointAtEnd(keep)
+		builder.CreateStore(llvm.ConstInt(int64Type, 0, false), markedAddress)
+		nextLink := builder.CreateStructGEP(gcHeaderType(), header, gcNext, "next_link")
+		builder.CreateBr(sweep)
+
+		// Unlink and release an object which isn't marked
+		builder.SetInsertPointAtEnd(release)
+		builder.CreateStore(builder.CreateLoad(pointerType, builder.CreateStructGEP(gcHeaderType(), header, gcNext, ""), "next"), link)
+		freeType, free := freeFunction(builder)
+		builder.CreateCall(freeType, free, []llvm.Value{header}, "")
+		builder.CreateBr(sweep)
+
+		link.AddIncoming([]llvm.Value{objectsGlobal, nextLink, link}, []llvm.BasicBlock{mark, keep, release})
+
+		builder.SetInsertPointAtEnd(done)
+		builder.CreateRetVoid()
+	})
+}
+
+// gcMarkRangeFunction returns the type and the definition of the runtime helper marking the object
+// every aligned word between the start and the end address points into.
+func gcMarkRangeFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	int64Type := globalScope.Context.Int64Type()
+	functionType := llvm.FunctionType(globalScope.Context.VoidType(), []llvm.Type{pointerType, pointerType}, false)
+
+	return runtimeFunction(functionBuilder, gcMarkRangeIdentifier, functionType, func(function llvm.Value) {
+		builder := globalScope.Context.NewBuilder()
+		defer builder.Dispose()
+
+		start := function.Param(0)
+		end := function.Param(1)
+		entry := globalScope.Context.AddBasicBlock(function, "entry")
+		loop := globalScope.Context.AddBasicBlock(function, "loop")
+		body := globalScope.Context.AddBasicBlock(function, "body")
+		done := globalScope.Context.AddBasicBlock(function, "done")
+
+		builder.SetInsertPointAtEnd(entry)
+		last := builder.CreateSub(builder.CreatePtrToInt(end, int64Type, ""), llvm.ConstInt(int64Type, 8, false), "last")
+		builder.CreateBr(loop)
+
+		builder.SetInsertPointAtEnd(loop)
+		word := builder.CreatePHI(pointerType, "word")
+		inRange := builder.CreateICmp(llvm.IntSLE, builder.CreatePtrToInt(word, int64Type, ""), last, "in_range")
+		builder.CreateCondBr(inRange, body, done)
+
+		builder.SetInsertPointAtEnd(body)
+		value := builder.CreateLoad(pointerType, word, "value")
+		value.SetAlignment(8)
+		markType, mark := gcMarkFunction(builder)
+		builder.CreateCall(markType, mark, []llvm.Value{value}, "")
+		next := builder.CreateInBoundsGEP(globalScope.Context.Int8Type(), word, []llvm.Value{llvm.ConstInt(int64Type, 8, false)}, "next")
+		builder.CreateBr(loop)
+		word.AddIncoming([]llvm.Value{start, next}, []llvm.BasicBlock{entry, body})
+
+		builder.SetInsertPointAtEnd(done)
+		builder.CreateRetVoid()
+	})
+}
+
+// gcMarkFunction returns the type and the definition of the runtime helper marking the object the
+// word points into, if any, and every object its words point into in turn.
+func gcMarkFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	int64Type := globalScope.Context.Int64Type()
+	functionType := llvm.FunctionType(globalScope.Context.VoidType(), []llvm.Type{pointerType}, false)
+
+	return runtimeFunction(functionBuilder, gcMarkIdentifier, functionType, func(function llvm.Value) {
+		builder := globalScope.Context.NewBuilder()
+		defer builder.Dispose()
+
+		word := function.Param(0)
+		entry := globalScope.Context.AddBasicBlock(function, "entry")
+		loop := globalScope.Context.AddBasicBlock(function, "loop")
+		check := globalScope.Context.AddBasicBlock(function, "check")
+		next := globalScope.Context.AddBasicBlock(function, "next")
+		found := globalScope.Context.AddBasicBlock(function, "found")
+		mark := globalScope.Context.AddBasicBlock(function, "mark")
+		done := globalScope.Context.AddBasicBlock(function, "done")
+
+		builder.SetInsertPointAtEnd(entry)
+		address := builder.CreatePtrToInt(word, int64Type, "address")
+		first := builder.CreateLoad(pointerType, gcGlobal(builder, gcObjectsIdentifier, pointerType), "first")
+		builder.CreateBr(loop)
+
+		// Find the object whose bytes hold the address
+		builder.SetInsertPointAtEnd(loop)
+		header := builder.CreatePHI(pointerType, "header")
+		builder.CreateCondBr(builder.CreateICmp(llvm.IntEQ, header, llvm.ConstPointerNull(pointerType), "end"), done, check)
+
+		builder.SetInsertPointAtEnd(check)
+		object := builder.CreateInBoundsGEP(globalScope.Context.Int8Type(), header, []llvm.Value{llvm.ConstInt(int64Type, gcHeaderSize, false)}, "object")
+		objectStart := builder.CreatePtrToInt(object, int64Type, "object_start")
+		size := builder.CreateLoad(int64Type, builder.CreateStructGEP(gcHeaderType(), header, gcSize, ""), "size")
+		objectEnd := builder.CreateAdd(objectStart, size, "object_end")
+		inside := builder.CreateAnd(builder.CreateICmp(llvm.IntUGE, address, objectStart, ""), builder.CreateICmp(llvm.IntULT, address, objectEnd, ""), "inside")
+		builder.CreateCondBr(inside, found, next)
+
+		builder.SetInsertPointAtEnd(next)
+		following := builder.CreateLoad(pointerType, builder.CreateStructGEP(gcHeaderType(), header, gcNext, ""), "following")
+		builder.CreateBr(loop)
+		header.AddIncoming([]llvm.Value{first, following}, []llvm.BasicBlock{entry, next})
+
+		// Mark the object unless it is marked already and scan its words
+		builder.SetInsertPointAtEnd(found)
+		markedAddress := builder.CreateStructGEP(gcHeaderType(), header, gcMarked, "")
+		marked := builder.CreateLoad(int64Type, markedAddress, "marked")
+		builder.CreateCondBr(builder.CreateICmp(llvm.IntNE, marked, llvm.ConstInt(int64Type, 0, false), "reachable"), done, mark)
+
+		builder.SetInsertPointAtEnd(mark)
+		builder.CreateStore(llvm.ConstInt(int64Type, 1, false), markedAddress)
+		markRangeType, markRange := gcMarkRangeFunction(builder)
+		builder.CreateCall(markRangeType, markRange, []llvm.Value{object, builder.CreateIntToPtr(objectEnd, pointerType, "")}, "")
+		builder.CreateBr(done)
+
+		builder.SetInsertPointAtEnd(done)
+		builder.CreateRetVoid()
+	})
+}
+
+// gcMarkGlobalsFunction returns the type and the declaration of the runtime helper marking the
+// objects the globals of the program point into. Its body is generated once the module is
+// generated, when every global is known, see generateGCMarkGlobals.
+func gcMarkGlobalsFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	functionType := llvm.FunctionType(globalScope.Context.VoidType(), []llvm.Type{}, false)
+	return runtimeFunction(functionBuilder, gcMarkGlobalsIdentifier, functionType, nil)
+}
+
+// generateGCMarkGlobals generates the body of the runtime helper marking the objects the globals of
+// the module point into, if the collector is used. Every global variable whose type holds pointers
+// is scanned, except for the list of objects of the collector itself.
+func generateGCMarkGlobals(module llvm.Module) {
+	function := module.NamedFunction(gcMarkGlobalsIdentifier)
+	if function.IsNil() || !function.IsDeclaration() {
+		return
+	}
+	function.SetLinkage(llvm.InternalLinkage)
+
+	builder := globalScope.Context.NewBuilder()
+	defer builder.Dispose()
+	entry := globalScope.Context.AddBasicBlock(function, "entry")
+	builder.SetInsertPointAtEnd(entry)
+
+	targetData := llvm.NewTargetData(module.DataLayout())
+	defer targetData.Dispose()
+	for global := module.FirstGlobal(); !global.IsNil(); global = llvm.NextGlobal(global) {
+		if global.IsDeclaration() || global.IsGlobalConstant() || global.Name() == gcObjectsIdentifier || !holdsPointers(global.GlobalValueType()) {
+			continue
+		}
+		size := targetData.TypeAllocSize(global.GlobalValueType())
+		end := builder.CreateInBoundsGEP(globalScope.Context.Int8Type(), global, []llvm.Value{llvm.ConstInt(globalScope.Context.Int64Type(), size, false)}, "")
+		markRangeType, markRange := gcMarkRangeFunction(builder)
+		builder.CreateCall(markRangeType, markRange, []llvm.Value{global, end}, "")
+	}
+	builder.CreateRetVoid()
+}
+
+// holdsPointers reports whether values of the LLVM type hold pointers.
+func holdsPointers(t llvm.Type) bool {
+	switch t.TypeKind() {
+	case llvm.PointerTypeKind:
+		return true
+	case llvm.StructTypeKind:
+		for _, element := range t.StructElementTypes() {
+			if holdsPointers(element) {
+				return true
+			}
+		}
+	case llvm.ArrayTypeKind:
+		return holdsPointers(t.ElementType())
+	}
+	return false
+}
+
+// setjmpFunction returns the type and the declaration of the C _setjmp function, which returns twice.
+func setjmpFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	functionType, function := runtimeFunction(functionBuilder, setjmpIdentifier, llvm.FunctionType(globalScope.Context.Int32Type(), []llvm.Type{pointerType}, false), nil)
+	function.AddFunctionAttr(globalScope.Context.CreateEnumAttribute(llvm.AttributeKindID("returns_twice"), 0))
+	return functionType, function
+}
+
+// memsetFunction returns the type and the declaration of the C memset function.
+func memsetFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	return runtimeFunction(functionBuilder, memsetIdentifier, llvm.FunctionType(pointerType, []llvm.Type{pointerType, globalScope.Context.Int32Type(), globalScope.Context.Int64Type()}, false), nil)
+}
@@ -0,0 +1,340 @@
+package lang
+
+import (
+	"strings"
+
+	"tinygo.org/x/go-llvm"
+)
+
+// generateGenericCall is a function that generates LLVM IR code for a call of a generic function.
+// The type parameters of the function are inferred from the data types of the parameters of the
+// call in order: a parameter whose type still uses an unbound type parameter is generated as an
+// expression and binds the type parameters by the structure of its data type, every other parameter
+// takes the type of the function parameter like in a call of a function which isn't generic, so in
+// max(x, 1) the literal takes the type of x.
+//
+// Every distinct binding of the type parameters is instantiated once as a function whose name
+// spells the binding, e.g. max<i64>, see instantiateGeneric.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// generic:          The declaration of the generic function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+//
+// Returns an error if the number of parameters doesn't match, a parameter doesn't match the type of
+// its function parameter, a type parameter can't be inferred or the instantiation fails.
+func generateGenericCall(scope *Scope, functionBuilder llvm.Builder, generic *FunctionNode, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(generic.Parameters) != len(callerNode.Args) {
+		return llvm.Value{}, 0, newError(MessageExpectedParameters, len(generic.Parameters), callerNode.FunctionName, len(callerNode.Args))
+	}
+
+	bindings := make(map[string]dataType)
+	var llvmParameterValues []llvm.Value
+	for i, arg := range callerNode.Args {
+		parameterType := substituteTypeParameters(generic.Parameters[i].Type, bindings)
+		if !usesTypeParameter(parameterType, generic.TypeParameters) {
+			value, err := generateTypedValue(scope, functionBuilder, arg, parameterType)
+			if err != nil {
+				return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, i+1, callerNode.FunctionName, err)
+			}
+			llvmParameterValues = append(llvmParameterValues, value)
+			continue
+		}
+
+		value, valueType, err := generateValue(scope, functionBuilder, arg)
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, i+1, callerNode.FunctionName, err)
+		}
+		if !inferTypeParameters(parameterType, valueType, generic.TypeParameters, bindings) {
+			return llvm.Value{}, 0, newError(MessageGenericParameterMismatch, i+1, callerNode.FunctionName, parameterType, valueType)
+		}
+		llvmParameterValues = append(llvmParameterValues, value)
+	}
+
+	typeArguments := make([]dataType, len(generic.TypeParameters))
+	for i, typeParameter := range generic.TypeParameters {
+		t, ok := bindings[typeParameter]
+		if !ok {
+			return llvm.Value{}, 0, newError(MessageUninferredTypeParameter, typeParameter, generic.Name)
+		}
+		typeArguments[i] = t
+	}
+
+	module := functionBuilder.GetInsertBlock().Parent().GlobalParent()
+	caller, err := instantiateGeneric(module, generic, typeArguments)
+	if err != nil {
+		return llvm.Value{}, 0, err
+	}
+	call := functionBuilder.CreateCall(*caller.Type, *caller.Value, llvmParameterValues, "")
+	return call, caller.ReturnType, nil
+}
+
+// instantiateGeneric returns the caller of the instantiation of the generic function with the type
+// arguments, which are bound to its type parameters in order. The instantiation is generated on
+// first use from a copy of the declaration in which every type parameter is replaced by its type
+// argument, including calls of a type parameter, e.g. T(x), which become casts. The copy is resolved
+// on its own, so the names its body uses bind to its parameters and variables, see Resolve.
+//
+// Returns an error if the instantiation can't be generated, e.g. because its body adds values of a
+// type argument which can't be added.
+func instantiateGeneric(module llvm.Module, generic *FunctionNode, typeArguments []dataType) (Caller, error) {
+	spellings := make([]string, len(typeArguments))
+	for i, t := range typeArguments {
+		spellings[i] = t.String()
+	}
+	name := generic.Name + "<" + strings.Join(spellings, ", ") + ">"
+	if caller, ok := globalScope.Callers.Get(name); ok {
+		return caller, nil
+	}
+
+	bindings := make(map[string]dataType)
+	for i, typeParameter := range generic.TypeParameters {
+		bindings[typeParameter] = typeArguments[i]
+	}
+	instance := cloneNode(generic).(*FunctionNode)
+	instance.Name = name
+	instance.TypeParameters = nil
+	r := typeAliasResolver{declared: bindings, resolved: bindings, resolving: make(map[string]bool)}
+	r.resolveNode(instance)
+	resolver := resolver{Resolution: globalScope.Resolution}
+	resolver.resolveFunction(instance)
+
+	if err := generateFunction(module, instance); err != nil {
+		return Caller{}, newError(MessageGenericInstantiation, name, err)
+	}
+	caller, _ := globalScope.Callers.Get(name)
+	return caller, nil
+}
+
+// substituteTypeParameters returns the data type with every bound type parameter it uses replaced
+// by its binding.
+func substituteTypeParameters(t dataType, bindings map[string]dataType) dataType {
+	r := typeAliasResolver{declared: bindings, resolved: bindings}
+	return r.resolveType(t)
+}
+
+// usesTypeParameter reports whether the data type uses any of the type parameters, i.e. whether
+// it changes if they are replaced by the void type, which no parameter can have.
+func usesTypeParameter(t dataType, typeParameters []string) bool {
+	bindings := make(map[string]dataType)
+	for _, typeParameter := range typeParameters {
+		bindings[typeParameter] = VoidType
+	}
+	return substituteTypeParameters(t, bindings) != t
+}
+
+// inferTypeParameters matches the data type of a parameter, which uses the type parameters, with
+// the data type of a value and binds every type parameter it uses to the data type at the same
+// position of the value's data type, e.g. T of []T to i32 of []i32.
+//
+// Returns false if the data types don't match or the value's data type conflicts with a binding.
+func inferTypeParameters(parameterType, valueType dataType, typeParameters []string, bindings map[string]dataType) bool {
+	if structType, ok := parameterType.structure(); ok {
+		for _, typeParameter := range typeParameters {
+			if structType.Name != typeParameter {
+				continue
+			}
+			if bound, ok := bindings[typeParameter]; ok {
+				return bound == valueType
+			}
+			bindings[typeParameter] = valueType
+			return true
+		}
+	}
+	if arrayType, ok := parameterType.array(); ok {
+		valueArrayType, ok := valueType.array()
+		return ok && arrayType.Length == valueArrayType.Length && inferTypeParameters(arrayType.Element, valueArrayType.Element, typeParameters, bindings)
+	}
+	if sliceType, ok := parameterType.slice(); ok {
+		valueSliceType, ok := valueType.slice()
+		return ok && inferTypeParameters(sliceType.Element, valueSliceType.Element, typeParameters, bindings)
+	}
+	if pointerType, ok := parameterType.pointer(); ok {
+		valuePointerType, ok := valueType.pointer()
+		return ok && inferTypeParameters(pointerType.Element, valuePointerType.Element, typeParameters, bindings)
+	}
+	if optionType, ok := parameterType.option(); ok {
+		valueOptionType, ok := valueType.option()
+		return ok && inferTypeParameters(optionType.Element, valueOptionType.Element, typeParameters, bindings)
+	}
+	if resultType, ok := parameterType.result(); ok {
+		valueResultType, ok := valueType.result()
+		return ok && inferTypeParameters(resultType.Element, valueResultType.Element, typeParameters, bindings)
+	}
+	return parameterType == valueType
+}
+
+// cloneNodes returns a deep copy of the nodes.
+func cloneNodes(nodes []Node) []Node {
+	if nodes == nil {
+		return nil
+	}
+	clones := make([]Node, len(nodes))
+	for i, node := range nodes {
+		clones[i] = cloneNode(node).(Node)
+	}
+	return clones
+}
+
+// cloneParameters returns a deep copy of the parameters.
+func cloneParameters(parameters []*Parameter) []*Parameter {
+	if parameters == nil {
+		return nil
+	}
+	clones := make([]*Parameter, len(parameters))
+	for i, parameter := range parameters {
+		clone := *parameter
+		clones[i] = &clone
+	}
+	return clones
+}
+
+// cloneNode returns a deep copy of the node, which may be a statement or a value.
+func cloneNode(node any) any {
+	switch n := node.(type) {
+	case *FunctionNode:
+		clone := *n
+		clone.Parameters = cloneParameters(n.Parameters)
+		clone.Body = cloneNodes(n.Body)
+		return &clone
+	case *LetNode:
+		clone := *n
+		clone.Value = cloneValue(n.Value)
+		return &clone
+	case *AssignmentNode:
+		clone := *n
+		clone.Value = cloneValue(n.Value)
+		return &clone
+	case *IndexAssignmentNode:
+		clone := *n
+		clone.Target = cloneNode(n.Target).(*IndexNode)
+		clone.Value = cloneValue(n.Value)
+		return &clone
+	case *FieldAssignmentNode:
+		clone := *n
+		clone.Target = cloneNode(n.Target).(*FieldNode)
+		clone.Value = cloneValue(n.Value)
+		return &clone
+	case *DereferenceAssignmentNode:
+		clone := *n
+		clone.Target = cloneNode(n.Target).(*DereferenceNode)
+		clone.Value = cloneValue(n.Value)
+		return &clone
+	case *ReturnNode:
+		clone := *n
+		clone.Value = cloneValue(n.Value)
+		return &clone
+	case *CallerNode:
+		clone := *n
+		clone.Args = make([]Expr, len(n.Args))
+		for i, arg := range n.Args {
+			clone.Args[i] = cloneValue(arg)
+		}
+		return &clone
+	case *SpawnNode:
+		clone := *n
+		clone.Call = cloneNode(n.Call).(*CallerNode)
+		return &clone
+	case *AddOperationNode:
+		clone := *n
+		clone.LeftValue = cloneValue(n.LeftValue)
+		clone.RightValue = cloneValue(n.RightValue)
+		return &clone
+	case *ShiftOperationNode:
+		clone := *n
+		clone.LeftValue = cloneValue(n.LeftValue)
+		clone.RightValue = cloneValue(n.RightValue)
+		return &clone
+	case *ComparisonNode:
+		clone := *n
+		clone.LeftValue = cloneValue(n.LeftValue)
+		clone.RightValue = cloneValue(n.RightValue)
+		return &clone
+	case *CastNode:
+		clone := *n
+		clone.Value = cloneValue(n.Value)
+		return &clone
+	case *NewNode:
+		clone := *n
+		return &clone
+	case *IndexNode:
+		clone := *n
+		clone.Value = cloneValue(n.Value)
+		clone.Index = cloneValue(n.Index)
+		return &clone
+	case *FieldNode:
+		clone := *n
+		clone.Value = cloneValue(n.Value)
+		return &clone
+	case *AddressNode:
+		clone := *n
+		clone.Value = cloneValue(n.Value)
+		return &clone
+	case *DereferenceNode:
+		clone := *n
+		clone.Value = cloneValue(n.Value)
+		return &clone
+	case *TryNode:
+		clone := *n
+		clone.Value = cloneValue(n.Value)
+		return &clone
+	case *ConditionalNode:
+		clone := *n
+		clone.Condition = cloneValue(n.Condition)
+		clone.True = cloneValue(n.True)
+		clone.False = cloneValue(n.False)
+		return &clone
+	case *ArrayLiteralNode:
+		clone := *n
+		clone.Elements = make([]Expr, len(n.Elements))
+		for i, element := range n.Elements {
+			clone.Elements[i] = cloneValue(element)
+		}
+		return &clone
+	case *StructLiteralNode:
+		clone := *n
+		clone.Fields = make([]*FieldValue, len(n.Fields))
+		for i, field := range n.Fields {
+			clone.Fields[i] = &FieldValue{Identifier: field.Identifier, Value: cloneValue(field.Value)}
+		}
+		return &clone
+	case *ForNode:
+		clone := *n
+		clone.Init.Value = cloneValue(n.Init.Value)
+		clone.Condition.RightValue = cloneValue(n.Condition.RightValue)
+		clone.Body = cloneNodes(n.Body)
+		return &clone
+	case *WhileNode:
+		clone := *n
+		clone.Body = cloneNodes(n.Body)
+		return &clone
+	case *StringLiteralNode:
+		clone := *n
+		return &clone
+	case *IdentifierNode:
+		clone := *n
+		return &clone
+	case *IntLiteralNode:
+		clone := *n
+		return &clone
+	case *FloatLiteralNode:
+		clone := *n
+		return &clone
+	case *BoolLiteralNode:
+		clone := *n
+		return &clone
+	case *InlineIRNode:
+		clone := *n
+		return &clone
+	}
+	// Every other node holds no values which could use a type parameter
+	return node
+}
+
+// cloneValue returns a deep copy of the value.
+func cloneValue(value Expr) Expr {
+	if value == nil {
+		return nil
+	}
+	return cloneNode(value).(Expr)
+}
@@ -0,0 +1,153 @@
+package lang
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ParseProgram reads, tokenizes and parses the gusty source file with the given name and every
+// file it imports from the file system, and returns the nodes of the combined program. Every
+// import declaration is replaced with the nodes of the imported file, whose path is relative to
+// the importing file, so the declarations of a file precede the files importing it. A file is
+// imported only once, even if several files import it. Type aliases are resolved across all files.
+//
+// Returns an error naming the cycle if files import each other.
+func ParseProgram(files fs.FS, name string) ([]Node, error) {
+	input, err := fs.ReadFile(files, name)
+	if err != nil {
+		return nil, err
+	}
+	i := newImporter(files, Tokenize)
+	return i.parse(i.tokenize(string(input)), path.Clean(name))
+}
+
+// ImportGraph is the dependency graph of the files of a program.
+type ImportGraph struct {
+	Files   []string            // The files in build order, every file follows the files it imports.
+	Imports map[string][]string // The files every file imports, in the order of its import declarations.
+}
+
+// LoadImportGraph reads and parses the gusty source file with the given name and every file it
+// imports from the file system like ParseProgram, and returns their dependency graph.
+func LoadImportGraph(files fs.FS, name string) (ImportGraph, error) {
+	input, err := fs.ReadFile(files, name)
+	if err != nil {
+		return ImportGraph{}, err
+	}
+	i := newImporter(files, Tokenize)
+	if _, err := i.parseFile(i.tokenize(string(input)), path.Clean(name)); err != nil {
+		return ImportGraph{}, err
+	}
+	return i.graph, nil
+}
+
+// String returns the graph as text, a line for every file in build order followed by the files it imports.
+func (g ImportGraph) String() string {
+	var b strings.Builder
+	for _, file := range g.Files {
+		b.WriteString(file)
+		if imports := g.Imports[file]; len(imports) > 0 {
+			b.WriteString(": " + strings.Join(imports, " "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Dot returns the graph in the dot language of Graphviz, with an edge from every file to the files it imports.
+func (g ImportGraph) Dot() string {
+	var b strings.Builder
+	b.WriteString("digraph imports {\n")
+	for _, file := range g.Files {
+		fmt.Fprintf(&b, "\t%q;\n", file)
+		for _, imported := range g.Imports[file] {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", file, imported)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// importer holds the state of parsing a program together with the files it imports.
+type importer struct {
+	files    fs.FS                // The file system imported files are read from.
+	tokenize func(string) []Token // The tokenizer imported files are tokenized with.
+	imported map[string]bool      // The paths of the files imported so far.
+	parsing  []string             // The paths of the files being parsed, every file imports the next one.
+	packages []packageFile        // The top-level nodes of every parsed file by package.
+	graph    ImportGraph          // The dependency graph of the files parsed so far.
+}
+
+// newImporter returns an importer reading files from the file system.
+func newImporter(files fs.FS, tokenize func(string) []Token) *importer {
+	return &importer{files: files, tokenize: tokenize, imported: make(map[string]bool), graph: ImportGraph{Imports: make(map[string][]string)}}
+}
+
+// parse parses the tokens of the file with the given name and the files it imports, and resolves
+// the packages and type aliases of the combined program.
+func (i *importer) parse(tokens []Token, name string) ([]Node, error) {
+	nodes, err := i.parseFile(tokens, name)
+	if err != nil {
+		return nil, err
+	}
+	qualifyPackages(i.packages)
+	if err := resolveTypeAliases(nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// parseFile parses the tokens of the file with the given name and replaces its top-level import
+// declarations with the nodes of the imported files.
+//
+// Returns an error if the file can't be parsed, an imported file can't be read or parsed or
+// imports the file itself.
+func (i *importer) parseFile(tokens []Token, name string) ([]Node, error) {
+	nodes, _, err := (&parserState{}).parseNodes(tokens, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	packageName, nodes := splitPackage(nodes)
+	i.packages = append(i.packages, packageFile{Package: packageName, Nodes: nodes})
+
+	i.imported[name] = true
+	i.parsing = append(i.parsing, name)
+	program := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		importNode, ok := node.(*ImportNode)
+		if !ok {
+			program = append(program, node)
+			continue
+		}
+
+		file := path.Join(path.Dir(name), importNode.Path)
+		i.graph.Imports[name] = append(i.graph.Imports[name], file)
+		for j, parsing := range i.parsing {
+			if parsing == file {
+				return nil, newError(MessageImportCycle, strings.Join(append(i.parsing[j:], file), " -> "))
+			}
+		}
+		if i.imported[file] {
+			continue
+		}
+
+		input, err := fs.ReadFile(i.files, file)
+		if err != nil {
+			return nil, newError(MessageImportFile, file, err)
+		}
+		imported, err := i.parseFile(i.tokenize(string(input)), file)
+		if err != nil {
+			// A cycle is reported by the file closing it, it names all files involved
+			if messageError, ok := err.(*MessageError); ok && messageError.ID == MessageImportCycle {
+				return nil, err
+			}
+			return nil, newError(MessageImportFile, file, err)
+		}
+		program = append(program, imported...)
+	}
+	i.parsing = i.parsing[:len(i.parsing)-1]
+	i.graph.Files = append(i.graph.Files, name)
+	return program, nil
+}
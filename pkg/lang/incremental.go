@@ -0,0 +1,166 @@
+package lang
+
+// TextEdit is a change of the source code, which replaces the text of the span with new text, e.g.
+// typed or deleted in an editor. An empty span inserts the text, an empty text deletes the span.
+type TextEdit struct {
+	Span
+	Text string
+}
+
+// Reparse applies the edit to the source code the nodes were parsed from, and returns the edited
+// source code and its nodes like Parse. Only the top-level statements the edit touches are parsed
+// again, from the statement before the edit on, since statements have no terminator and an edit at
+// the start of a statement may continue the statement before it. The statements before are reused,
+// and the statements following the edit are reused and moved to their new positions once a parsed
+// statement ends where one of them starts, so the nodes mustn't be used with the old source code
+// afterwards.
+// The edited source code is parsed entirely if its edited statements have syntax errors, which may
+// extend beyond them, or if it declares a package or type aliases, which change other statements.
+//
+// Returns an error if the span of the edit isn't in the source code.
+func Reparse(source string, nodes []Node, edit TextEdit) (string, []Node, error) {
+	return (&Parser{}).Reparse(source, nodes, edit)
+}
+
+// Reparse applies the edit to the source code the nodes were parsed from like Reparse, with the
+// configuration of the parser.
+func (p *Parser) Reparse(source string, nodes []Node, edit TextEdit) (string, []Node, error) {
+	from, fromOk := sourceOffset(source, edit.From)
+	to, toOk := sourceOffset(source, edit.To)
+	if !fromOk || !toOk || from > to {
+		return source, nil, newError(MessageInvalidEdit, edit.From, edit.To)
+	}
+	source = source[:from] + edit.Text + source[to:]
+
+	tokens := Tokenize(source)
+	if reparsed, ok := p.reparse(tokens, nodes, edit); ok {
+		return source, reparsed, nil
+	}
+	nodes, err := p.Parse(tokens)
+	return source, nodes, err
+}
+
+// reparse parses the tokens of the edited source code which belong to the top-level statements the
+// edit touches, and returns the nodes of the edited source code, or false if it must be parsed
+// entirely.
+func (p *Parser) reparse(tokens []Token, nodes []Node, edit TextEdit) ([]Node, bool) {
+	if len(tokens) > 0 && tokens[0].Type == TokenPackageType {
+		return nil, false
+	}
+	for _, node := range nodes {
+		if _, ok := node.(*TypeAliasNode); ok || !node.Pos().IsValid() {
+			return nil, false
+		}
+	}
+
+	// The statements before first end before the edit, the statements from last start after it.
+	// The statement before the edit is parsed again, since the edit may continue it.
+	first, last := 0, len(nodes)
+	for first < len(nodes) && before(nodes[first].End(), edit.From) {
+		first++
+	}
+	for last > first && before(edit.To, nodes[last-1].Pos()) {
+		last--
+	}
+	if first > 0 {
+		first--
+	}
+
+	// The statements following the edit are reused from the first one a parsed statement ends at,
+	// at its new position
+	move := edit.mover()
+	starts := make(map[Pos]int, len(nodes)-last)
+	for i := last; i < len(nodes); i++ {
+		starts[move(nodes[i].Pos())] = i
+	}
+	start, reused := 0, len(nodes)
+	if first < len(nodes) {
+		start = tokenIndex(tokens, nodes[first].Pos())
+	}
+
+	s := &parserState{Parser: *p}
+	s.resume = func(index int) bool {
+		i, ok := starts[tokens[index].Pos]
+		if ok {
+			reused = i
+		}
+		return ok
+	}
+	edited, _, err := s.parseNodes(tokens, start, -1)
+	if err != nil {
+		return nil, false
+	}
+	for _, node := range edited {
+		switch node.(type) {
+		case *TypeAliasNode, *PackageNode:
+			return nil, false
+		}
+	}
+
+	for _, node := range nodes[reused:] {
+		Walk(node, func(n Node) bool {
+			if s, ok := n.(spanned); ok && n.Pos().IsValid() {
+				s.setSpan(move(n.Pos()), move(n.End()))
+			}
+			return true
+		})
+	}
+
+	reparsed := make([]Node, 0, first+len(edited)+len(nodes)-reused)
+	reparsed = append(reparsed, nodes[:first]...)
+	reparsed = append(reparsed, edited...)
+	return append(reparsed, nodes[reused:]...), true
+}
+
+// mover returns a function which moves a position following the edit to its position in the
+// edited source code.
+func (e TextEdit) mover() func(Pos) Pos {
+	// The position following the new text
+	end := e.From
+	for _, r := range e.Text {
+		if r == '\n' {
+			end = Pos{Line: end.Line + 1, Column: 1}
+		} else {
+			end.Column++
+		}
+	}
+
+	return func(pos Pos) Pos {
+		if pos.Line == e.To.Line {
+			return Pos{Line: end.Line, Column: end.Column + pos.Column - e.To.Column}
+		}
+		return Pos{Line: pos.Line + end.Line - e.To.Line, Column: pos.Column}
+	}
+}
+
+// before reports whether the position a precedes the position b.
+func before(a, b Pos) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+}
+
+// tokenIndex returns the index of the first token which doesn't precede the position.
+func tokenIndex(tokens []Token, pos Pos) int {
+	for i, token := range tokens {
+		if !before(token.Pos, pos) {
+			return i
+		}
+	}
+	return len(tokens)
+}
+
+// sourceOffset returns the byte offset of the position in the source code, or false if the source
+// code has no such position. The position following the last rune is the length of the source code.
+func sourceOffset(source string, pos Pos) (int, bool) {
+	current := Pos{Line: 1, Column: 1}
+	for offset, r := range source {
+		if current == pos {
+			return offset, true
+		}
+		if r == '\n' {
+			current = Pos{Line: current.Line + 1, Column: 1}
+		} else {
+			current.Column++
+		}
+	}
+	return len(source), current == pos
+}
@@ -0,0 +1,151 @@
+package lang
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"tinygo.org/x/go-llvm"
+)
+
+// inlineIRIdentifier is the prefix of the names of the functions holding the instructions of the
+// inline LLVM IR blocks of function bodies, which are numbered in the order they are generated.
+const inlineIRIdentifier = runtimePrefix + "llvm_"
+
+// generateInlineIR is a function that generates LLVM IR code for an inline LLVM IR block in a
+// function body. The instructions of the block become the body of a function which is called in
+// place of the block and inlined by the optimizer. The function takes the parameters of the
+// enclosing function which are integers, floats, bools, strings or pointers, so the instructions
+// refer to them by their names, e.g. %a. It returns void, the last block of the instructions falls
+// through to its return.
+//
+// The function is defined once the module is generated, together with the declarations and
+// definitions of the blocks at the top level, so its instructions may refer to them, see linkInlineIR.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// inlineIRNode:     The abstract syntax tree (AST) node representing the block.
+func generateInlineIR(scope *Scope, functionBuilder llvm.Builder, inlineIRNode *InlineIRNode) error {
+	module := functionBuilder.GetInsertBlock().Parent().GlobalParent()
+	name := fmt.Sprintf("%s%d", inlineIRIdentifier, len(globalScope.InlineIR))
+
+	var parameters []string
+	var llvmParameters []llvm.Type
+	var arguments []llvm.Value
+	if scope.Function != nil {
+		for _, parameter := range scope.Function.Parameters {
+			argument, ok := scope.Arguments.Get(parameter.Identifier)
+			spelling, spelled := inlineIRType(parameter.Type)
+			if !ok || !spelled {
+				continue
+			}
+			parameters = append(parameters, spelling+" %"+parameter.Identifier)
+			llvmParameters = append(llvmParameters, llvmType(parameter.Type))
+			arguments = append(arguments, *argument.Value)
+		}
+	}
+
+	definition := fmt.Sprintf("define void @%s(%s) alwaysinline {\nentry:\n%s\n  ret void\n}\n", name, strings.Join(parameters, ", "), inlineIRNode.IR)
+	globalScope.InlineIR = append(globalScope.InlineIR, definition)
+
+	functionType := llvm.FunctionType(globalScope.Context.VoidType(), llvmParameters, false)
+	function := llvm.AddFunction(module, name, functionType)
+	functionBuilder.CreateCall(functionType, function, arguments, "")
+	return nil
+}
+
+// inlineIRTypes maps the data types whose values are passed to the instructions of inline LLVM IR
+// blocks, besides pointers, to the spelling of their LLVM types.
+var inlineIRTypes = map[dataType]string{
+	Integer8Type:  "i8",
+	Integer16Type: "i16",
+	Integer32Type: "i32",
+	Integer64Type: "i64",
+	Float32Type:   "float",
+	Float64Type:   "double",
+	BoolType:      "i1",
+	StringType:    "ptr",
+}
+
+// inlineIRType returns the spelling of the LLVM type of the data type and whether values of the
+// data type are passed to the instructions of inline LLVM IR blocks.
+func inlineIRType(t dataType) (string, bool) {
+	if _, ok := t.pointer(); ok {
+		return "ptr", true
+	}
+	spelling, ok := inlineIRTypes[t]
+	return spelling, ok
+}
+
+// linkInlineIR parses the LLVM IR of every inline block of the program as one module and links it
+// into the generated module. The functions holding the instructions of blocks in function bodies
+// are made internal afterwards, as they are only called by the module.
+//
+// Returns an error if the LLVM IR can't be parsed or linked, e.g. because it redefines a function.
+func linkInlineIR(module llvm.Module) error {
+	if len(globalScope.InlineIR) == 0 {
+		return nil
+	}
+
+	// LLVM parses IR from memory buffers, which the bindings only read from files
+	file, err := os.CreateTemp("", "gusty-*.ll")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(strings.Join(globalScope.InlineIR, "\n")); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	buffer, err := llvm.NewMemoryBufferFromFile(file.Name())
+	if err != nil {
+		return err
+	}
+
+	// Parsing takes ownership of the buffer, linking takes ownership of the parsed module
+	inline, err := globalScope.Context.ParseIR(buffer)
+	if err != nil {
+		return newError(MessageInlineIR, err)
+	}
+	// LLVM aborts the process if linking fails, so clashing definitions are reported beforehand
+	if name, ok := redefinedSymbol(module, inline); ok {
+		inline.Dispose()
+		return newError(MessageInlineIRRedefined, name)
+	}
+	if err := llvm.LinkModules(module, inline); err != nil {
+		return newError(MessageInlineIR, err)
+	}
+
+	for function := module.FirstFunction(); !function.IsNil(); function = llvm.NextFunction(function) {
+		if strings.HasPrefix(function.Name(), inlineIRIdentifier) && !function.IsDeclaration() {
+			function.SetLinkage(llvm.InternalLinkage)
+		}
+	}
+	return nil
+}
+
+// redefinedSymbol returns the name of the first function or global variable the inline module
+// defines with external linkage which the module already defines, and whether there is one.
+func redefinedSymbol(module, inline llvm.Module) (string, bool) {
+	defined := func(value llvm.Value) bool {
+		return !value.IsNil() && !value.IsDeclaration()
+	}
+	exported := func(value llvm.Value) bool {
+		return defined(value) && value.Linkage() != llvm.InternalLinkage && value.Linkage() != llvm.PrivateLinkage
+	}
+
+	for function := inline.FirstFunction(); !function.IsNil(); function = llvm.NextFunction(function) {
+		if exported(function) && defined(module.NamedFunction(function.Name())) {
+			return function.Name(), true
+		}
+	}
+	for global := inline.FirstGlobal(); !global.IsNil(); global = llvm.NextGlobal(global) {
+		if exported(global) && defined(module.NamedGlobal(global.Name())) {
+			return global.Name(), true
+		}
+	}
+	return "", false
+}
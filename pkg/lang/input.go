@@ -0,0 +1,129 @@
+package lang
+
+import (
+	"tinygo.org/x/go-llvm"
+)
+
+// Constants for the identifiers of the builtin functions reading from the standard input.
+const (
+	readIntIdentifier  = "read_int"
+	readLineIdentifier = "read_line"
+)
+
+// Constants for the identifiers of the functions and globals reading from the standard input relies on.
+const (
+	// scanfIdentifier is the identifier of the C function reading formatted input.
+	scanfIdentifier = "scanf"
+	// fgetsIdentifier is the identifier of the C function reading a line from a stream.
+	fgetsIdentifier = "fgets"
+	// stdinIdentifier is the identifier of the C global holding the standard input stream.
+	stdinIdentifier = "stdin"
+	// readLineFunctionIdentifier is the identifier of the runtime helper reading a line from the standard input.
+	readLineFunctionIdentifier = runtimePrefix + "read_line"
+)
+
+// readLineSize is the number of bytes of the buffer a line is read into, including the null byte.
+// Longer lines are returned in pieces by successive calls of read_line.
+const readLineSize = 1024
+
+// generateRead is a function that generates LLVM IR code for a call of the read_int or read_line
+// builtin. read_int reads a decimal integer from the standard input with scanf and returns it as
+// i64, or 0 if the input doesn't start with an integer. read_line reads the next line from the
+// standard input with fgets and returns it as a string without its newline, or an empty string
+// at the end of the input.
+//
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+//
+// Returns an error if the call passes any parameter.
+func generateRead(functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) != 0 {
+		return llvm.Value{}, 0, newError(MessageExpectedNoParameters, callerNode.FunctionName, len(callerNode.Args))
+	}
+
+	if callerNode.FunctionName == readLineIdentifier {
+		readLineType, readLine := readLineFunction(functionBuilder)
+		return functionBuilder.CreateCall(readLineType, readLine, []llvm.Value{}, ""), StringType, nil
+	}
+
+	// Read into a zeroed variable, so the result is 0 if scanf doesn't match an integer
+	variable := functionBuilder.CreateAlloca(globalScope.Context.Int64Type(), "read")
+	functionBuilder.CreateStore(llvm.ConstInt(globalScope.Context.Int64Type(), 0, false), variable)
+	format := formatStringGlobal(functionBuilder, printfFormat{Name: formatStringIdentifier + "_" + readIntIdentifier, Format: "%ld"})
+	scanfType, scanf := scanfFunction(functionBuilder)
+	functionBuilder.CreateCall(scanfType, scanf, []llvm.Value{format, variable}, "")
+	return functionBuilder.CreateLoad(globalScope.Context.Int64Type(), variable, ""), Integer64Type, nil
+}
+
+// scanfFunction returns the type and the declaration of the C scanf function.
+func scanfFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	return runtimeFunction(functionBuilder, scanfIdentifier, llvm.FunctionType(globalScope.Context.Int32Type(), []llvm.Type{pointerType}, true), nil)
+}
+
+// fgetsFunction returns the type and the declaration of the C fgets function.
+func fgetsFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	return runtimeFunction(functionBuilder, fgetsIdentifier, llvm.FunctionType(pointerType, []llvm.Type{pointerType, globalScope.Context.Int32Type(), pointerType}, false), nil)
+}
+
+// stdinGlobal returns the declaration of the C global holding the standard input stream.
+func stdinGlobal(functionBuilder llvm.Builder) llvm.Value {
+	module := functionBuilder.GetInsertBlock().Parent().GlobalParent()
+	if stdin := module.NamedGlobal(stdinIdentifier); !stdin.IsNil() {
+		return stdin
+	}
+	return llvm.AddGlobal(module, llvm.PointerType(globalScope.Context.Int8Type(), 0), stdinIdentifier)
+}
+
+// readLineFunction returns the type and the definition of the runtime helper which reads a line from
+// the standard input. It allocates a buffer of readLineSize bytes, reads the line into it with fgets
+// and removes the trailing newline. The buffer holds an empty string at the end of the input.
+func readLineFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	functionType := llvm.FunctionType(pointerType, []llvm.Type{}, false)
+
+	return runtimeFunction(functionBuilder, readLineFunctionIdentifier, functionType, func(function llvm.Value) {
+		builder := globalScope.Context.NewBuilder()
+		defer builder.Dispose()
+
+		entry := globalScope.Context.AddBasicBlock(function, "entry")
+		end := globalScope.Context.AddBasicBlock(function, "end")
+		check := globalScope.Context.AddBasicBlock(function, "check")
+		strip := globalScope.Context.AddBasicBlock(function, "strip")
+		done := globalScope.Context.AddBasicBlock(function, "done")
+
+		// Allocate the buffer and read the line into it
+		builder.SetInsertPointAtEnd(entry)
+		mallocType, malloc := heapAllocFunction(builder)
+		buffer := builder.CreateCall(mallocType, malloc, []llvm.Value{llvm.ConstInt(globalScope.Context.Int64Type(), readLineSize, false)}, "buffer")
+		stdin := builder.CreateLoad(pointerType, stdinGlobal(builder), "stdin")
+		fgetsType, fgets := fgetsFunction(builder)
+		line := builder.CreateCall(fgetsType, fgets, []llvm.Value{buffer, llvm.ConstInt(globalScope.Context.Int32Type(), readLineSize, false), stdin}, "line")
+		eof := builder.CreateICmp(llvm.IntEQ, line, llvm.ConstPointerNull(pointerType), "eof")
+		builder.CreateCondBr(eof, end, check)
+
+		// Return an empty string at the end of the input
+		builder.SetInsertPointAtEnd(end)
+		builder.CreateStore(llvm.ConstInt(globalScope.Context.Int8Type(), 0, false), buffer)
+		builder.CreateRet(buffer)
+
+		// Check whether the line ends with a newline, a line can't be empty if fgets succeeded
+		builder.SetInsertPointAtEnd(check)
+		strlenType, strlen := strlenFunction(builder)
+		length := builder.CreateCall(strlenType, strlen, []llvm.Value{buffer}, "length")
+		lastIndex := builder.CreateSub(length, llvm.ConstInt(globalScope.Context.Int64Type(), 1, false), "last_index")
+		last := builder.CreateInBoundsGEP(globalScope.Context.Int8Type(), buffer, []llvm.Value{lastIndex}, "last")
+		lastByte := builder.CreateLoad(globalScope.Context.Int8Type(), last, "last_byte")
+		newline := builder.CreateICmp(llvm.IntEQ, lastByte, llvm.ConstInt(globalScope.Context.Int8Type(), '\n', false), "newline")
+		builder.CreateCondBr(newline, strip, done)
+
+		// Remove the newline
+		builder.SetInsertPointAtEnd(strip)
+		builder.CreateStore(llvm.ConstInt(globalScope.Context.Int8Type(), 0, false), last)
+		builder.CreateBr(done)
+
+		builder.SetInsertPointAtEnd(done)
+		builder.CreateRet(buffer)
+	})
+}
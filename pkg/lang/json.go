@@ -0,0 +1,652 @@
+package lang
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The abstract syntax tree is encoded as JSON with a JSON object per node holding its exported
+// fields and the tag "node", the kind of the node, e.g.
+// {"node": "ReturnNode", "Value": {"node": "IdentifierNode", "Name": "x"}}. The span of a node is
+// encoded as the field Span, if it is known, and data types by their spelling, e.g. "[]i32".
+
+// jsonNodeTag is the tag of the JSON objects encoding nodes.
+const jsonNodeTag = "node"
+
+// nodeKinds maps the kinds of the nodes, which are the names of their types, to their types.
+var nodeKinds = kindsOf(&LetNode{}, &AddOperationNode{}, &Parameter{}, &WhileNode{}, &FunctionNode{}, &CallerNode{},
+	&ReturnNode{}, &CastNode{}, &ArrayLiteralNode{}, &NewNode{}, &StringLiteralNode{}, &ShiftOperationNode{},
+	&ComparisonNode{}, &ConditionalNode{}, &TryNode{}, &SpawnNode{}, &NoneNode{}, &AddressNode{}, &DereferenceNode{},
+	&DereferenceAssignmentNode{}, &IndexNode{}, &FieldNode{}, &FieldAssignmentNode{}, &IndexAssignmentNode{},
+	&AssignmentNode{}, &StructNode{}, &ExternNode{}, &InlineIRNode{}, &TypeAliasNode{}, &EmbedNode{}, &ImportNode{},
+	&PackageNode{}, &StructLiteralNode{}, &ForNode{}, &ShortVariableAssigmentNode{}, &ConditionNode{}, &PostNode{},
+	&IdentifierNode{}, &IntLiteralNode{}, &FloatLiteralNode{}, &BoolLiteralNode{})
+
+// kindsOf returns the types of the nodes by their kind.
+func kindsOf(nodes ...Node) map[string]reflect.Type {
+	kinds := make(map[string]reflect.Type, len(nodes))
+	for _, node := range nodes {
+		t := reflect.TypeOf(node).Elem()
+		kinds[t.Name()] = t
+	}
+	return kinds
+}
+
+// UnmarshalNodes decodes the nodes of a program from a JSON array of nodes, which json.Marshal
+// encodes the nodes into.
+func UnmarshalNodes(data []byte) ([]Node, error) {
+	var nodes []Node
+	if err := decodeValue(data, reflect.ValueOf(&nodes).Elem()); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *LetNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *LetNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *AddOperationNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *AddOperationNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *Parameter) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *Parameter) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *WhileNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *WhileNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *FunctionNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *FunctionNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *CallerNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *CallerNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *ReturnNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *ReturnNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *CastNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *CastNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *ArrayLiteralNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *ArrayLiteralNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *NewNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *NewNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *StringLiteralNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *StringLiteralNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *ShiftOperationNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *ShiftOperationNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *ComparisonNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *ComparisonNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *ConditionalNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *ConditionalNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *TryNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *TryNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *SpawnNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *SpawnNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *NoneNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *NoneNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *AddressNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *AddressNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *DereferenceNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *DereferenceNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *DereferenceAssignmentNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *DereferenceAssignmentNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *IndexNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *IndexNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *FieldNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *FieldNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *FieldAssignmentNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *FieldAssignmentNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *IndexAssignmentNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *IndexAssignmentNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *AssignmentNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *AssignmentNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *StructNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *StructNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *ExternNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *ExternNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *InlineIRNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *InlineIRNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *TypeAliasNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *TypeAliasNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *EmbedNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *EmbedNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *ImportNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *ImportNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *PackageNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *PackageNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *StructLiteralNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *StructLiteralNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *ForNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *ForNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *ShortVariableAssigmentNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *ShortVariableAssigmentNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *ConditionNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *ConditionNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *PostNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *PostNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *IdentifierNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *IdentifierNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *IntLiteralNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *IntLiteralNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *FloatLiteralNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *FloatLiteralNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalJSON encodes the node as a JSON object tagged with its kind.
+func (n *BoolLiteralNode) MarshalJSON() ([]byte, error) {
+	return marshalNode(n)
+}
+
+// UnmarshalJSON decodes the node from a JSON object tagged with its kind.
+func (n *BoolLiteralNode) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, n)
+}
+
+// MarshalText encodes the data type as its spelling, e.g. [4]i32.
+func (t dataType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes the data type from its spelling.
+func (t *dataType) UnmarshalText(text []byte) error {
+	if string(text) == VoidType.String() {
+		*t = VoidType
+		return nil
+	}
+	tokens := Tokenize(string(text))
+	parsed, index, err := (&parserState{}).parseType(tokens, 0)
+	if err != nil || index != len(tokens) {
+		return newError(MessageJSONDataType, string(text))
+	}
+	*t = parsed
+	return nil
+}
+
+// marshalNode encodes the node as a JSON object tagged with its kind.
+func marshalNode(node Node) ([]byte, error) {
+	return encodeStruct(reflect.ValueOf(node).Elem())
+}
+
+// unmarshalNode decodes the node from a JSON object, which must be tagged with the kind of the node.
+func unmarshalNode(data []byte, node Node) error {
+	return decodeStruct(data, reflect.ValueOf(node).Elem())
+}
+
+// encodeValue encodes the value of a field of a node.
+func encodeValue(v reflect.Value) ([]byte, error) {
+	switch {
+	case v.Type() == dataTypeType:
+		return json.Marshal(v.Interface())
+	case v.Kind() == reflect.Interface:
+		return encodeAny(v.Interface())
+	case v.Kind() == reflect.Pointer:
+		if v.IsNil() {
+			return []byte("null"), nil
+		}
+		return encodeStruct(v.Elem())
+	case v.Kind() == reflect.Struct:
+		return encodeStruct(v)
+	case v.Kind() == reflect.Slice:
+		if v.IsNil() {
+			return []byte("null"), nil
+		}
+		elements := make([]json.RawMessage, v.Len())
+		for i := range elements {
+			element, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = element
+		}
+		return json.Marshal(elements)
+	}
+	return json.Marshal(v.Interface())
+}
+
+// encodeStruct encodes the exported fields of the struct, a node or a part of a node like a
+// field of a struct declaration, as a JSON object. A node is tagged with its kind.
+func encodeStruct(v reflect.Value) ([]byte, error) {
+	object := make(map[string]json.RawMessage)
+	if _, ok := v.Addr().Interface().(Node); ok {
+		object[jsonNodeTag] = json.RawMessage(strconv.Quote(v.Type().Name()))
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" || (field.Type == spanType && v.Field(i).IsZero()) {
+			continue
+		}
+		value, err := encodeValue(v.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		object[field.Name] = value
+	}
+	return json.Marshal(object)
+}
+
+// encodeAny encodes a value of a node held by an interface, which is a node.
+func encodeAny(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return []byte("null"), nil
+	case Node:
+		return marshalNode(v)
+	}
+	return nil, newError(MessageJSONValue, value, value)
+}
+
+// decodeValue decodes the value of a field of a node into v.
+func decodeValue(data []byte, v reflect.Value) error {
+	switch {
+	case v.Type() == dataTypeType:
+		return json.Unmarshal(data, v.Addr().Interface())
+	case v.Kind() == reflect.Interface:
+		value, err := decodeAny(data)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			return nil
+		}
+		if !reflect.TypeOf(value).AssignableTo(v.Type()) {
+			return newError(MessageJSONNode, string(data))
+		}
+		v.Set(reflect.ValueOf(value))
+		return nil
+	case v.Kind() == reflect.Pointer:
+		if isNull(data) {
+			return nil
+		}
+		element := reflect.New(v.Type().Elem())
+		if err := decodeStruct(data, element.Elem()); err != nil {
+			return err
+		}
+		v.Set(element)
+		return nil
+	case v.Kind() == reflect.Struct:
+		return decodeStruct(data, v)
+	case v.Kind() == reflect.Slice:
+		if isNull(data) {
+			return nil
+		}
+		var elements []json.RawMessage
+		if err := json.Unmarshal(data, &elements); err != nil {
+			return err
+		}
+		v.Set(reflect.MakeSlice(v.Type(), len(elements), len(elements)))
+		for i, element := range elements {
+			if err := decodeValue(element, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return json.Unmarshal(data, v.Addr().Interface())
+}
+
+// decodeStruct decodes the fields of the struct from a JSON object. The object of a node must be
+// tagged with the kind of the node.
+func decodeStruct(data []byte, v reflect.Value) error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	if _, ok := v.Addr().Interface().(Node); ok {
+		var kind string
+		if err := json.Unmarshal(object[jsonNodeTag], &kind); err != nil || kind != v.Type().Name() {
+			return newError(MessageJSONNode, string(data))
+		}
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		value, ok := object[field.Name]
+		if !ok || !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		if err := decodeValue(value, v.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeAny decodes a value of a node held by an interface, which is a node.
+func decodeAny(data []byte) (any, error) {
+	if isNull(data) {
+		return nil, nil
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, newError(MessageJSONNode, string(data))
+	}
+	var tag string
+	if err := json.Unmarshal(object[jsonNodeTag], &tag); err != nil {
+		return nil, newError(MessageJSONNode, string(data))
+	}
+	t, ok := nodeKinds[tag]
+	if !ok {
+		return nil, newError(MessageJSONNode, string(data))
+	}
+	node := reflect.New(t)
+	if err := decodeStruct(data, node.Elem()); err != nil {
+		return nil, err
+	}
+	return node.Interface(), nil
+}
+
+// isNull reports whether the JSON value is null.
+func isNull(data []byte) bool {
+	return strings.TrimSpace(string(data)) == "null"
+}
@@ -0,0 +1,18 @@
+package lang
+
+// DefaultComplexityThreshold is the cyclomatic complexity a function may have before
+// LintComplexity reports it.
+const DefaultComplexityThreshold = 10
+
+// LintComplexity returns a diagnostic for every function of the program whose cyclomatic
+// complexity, as measured by Stats, exceeds the threshold. The diagnostics name the function
+// and are returned in declaration order.
+func LintComplexity(nodes []Node, threshold int) []error {
+	var diagnostics []error
+	for _, function := range Stats(nodes).Complexity {
+		if function.Complexity > threshold {
+			diagnostics = append(diagnostics, newError(MessageComplexityTooHigh, function.Name, function.Complexity, threshold))
+		}
+	}
+	return diagnostics
+}
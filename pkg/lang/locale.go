@@ -0,0 +1,115 @@
+package lang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locale identifies the language diagnostic messages are rendered in.
+type Locale string
+
+// Constants for the locales of the message catalog.
+const (
+	// LocaleEnglish renders messages in English. It is the default and the fallback for
+	// messages which aren't translated.
+	LocaleEnglish Locale = "en"
+	// LocaleGerman renders messages in German.
+	LocaleGerman Locale = "de"
+)
+
+// MessageError is a diagnostic reported while compiling. It holds the ID of its message
+// and the arguments to render the message with, so it can be rendered in any locale.
+// Error renders it in English.
+type MessageError struct {
+	ID   MessageID
+	Args []any
+}
+
+// newError returns the diagnostic for the message with the given ID and arguments.
+func newError(id MessageID, args ...any) error {
+	return &MessageError{ID: id, Args: args}
+}
+
+// Error returns the message rendered in English.
+func (e *MessageError) Error() string {
+	return e.Localize(LocaleEnglish)
+}
+
+// Unwrap returns the errors wrapped by the message.
+func (e *MessageError) Unwrap() []error {
+	var wrapped []error
+	for _, arg := range e.Args {
+		if err, ok := arg.(error); ok {
+			wrapped = append(wrapped, err)
+		}
+	}
+	return wrapped
+}
+
+// Localize returns the message rendered in the given locale, falling back to English if the
+// locale or the message isn't translated. Wrapped errors are rendered in the same locale.
+func (e *MessageError) Localize(locale Locale) string {
+	format, ok := messages[locale][e.ID]
+	if !ok {
+		format = messages[LocaleEnglish][e.ID]
+	}
+
+	args := make([]any, len(e.Args))
+	for i, arg := range e.Args {
+		if err, ok := arg.(error); ok {
+			arg = Localize(err, locale)
+		}
+		args[i] = arg
+	}
+	return fmt.Sprintf(strings.ReplaceAll(format, "%w", "%s"), args...)
+}
+
+// Localize returns the message of the error rendered in the given locale. Errors which
+// aren't diagnostics of the message catalog keep their message.
+func Localize(err error, locale Locale) string {
+	switch e := err.(type) {
+	case *MessageError:
+		return e.Localize(locale)
+	case *localizedError:
+		return Localize(e.err, locale)
+	case *ParseError:
+		return Localize(e.Err, locale)
+	case Diagnostic:
+		if !e.From.IsValid() {
+			return Localize(e.Err, locale)
+		}
+		return fmt.Sprintf("%s: %s", e.From, Localize(e.Err, locale))
+	case SyntaxErrors:
+		messages := make([]string, len(e))
+		for i, err := range e {
+			messages[i] = Localize(err, locale)
+		}
+		return strings.Join(messages, "\n")
+	}
+	return err.Error()
+}
+
+// localizedError renders the message of the wrapped error in a locale.
+type localizedError struct {
+	err    error
+	locale Locale
+}
+
+// localize returns the error rendering its message in the given locale.
+// Errors are returned unchanged for the default locale.
+func localize(err error, locale Locale) error {
+	if err == nil || locale == "" || locale == LocaleEnglish {
+		return err
+	}
+	return &localizedError{err: err, locale: locale}
+}
+
+// Error returns the message rendered in the locale.
+func (e *localizedError) Error() string {
+	return Localize(e.err, e.locale)
+}
+
+// Unwrap returns the wrapped error.
+func (e *localizedError) Unwrap() error {
+	return e.err
+}
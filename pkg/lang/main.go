@@ -0,0 +1,82 @@
+package lang
+
+import (
+	"tinygo.org/x/go-llvm"
+)
+
+// mainIdentifier is the identifier of the main function, which the C runtime calls to run the program.
+const mainIdentifier = "main"
+
+// Constants for the LLVM names of the functions holding the top-level statements and main.
+const (
+	// userMainIdentifier is the LLVM name of the main function declared by the program, which the
+	// synthesized main function calls once it ran the top-level statements.
+	userMainIdentifier = runtimePrefix + "main"
+	// libraryMainIdentifier is the LLVM name of the function the top-level declarations of a library
+	// are generated into, which is removed once the module is generated.
+	libraryMainIdentifier = runtimePrefix + "library"
+)
+
+// generateUserMain is a function that generates LLVM IR code for the main function declared by the
+// program, e.g. function main() i32. The function is generated under the name userMainIdentifier
+// and called by the synthesized main function after the top-level statements; the value it returns
+// is the exit status of the program, 0 if it returns nothing.
+//
+// module:        The LLVM module the function is added to.
+// functionNode:  The abstract syntax tree (AST) node representing the main function.
+//
+// Returns an error if main takes parameters, is generic or returns anything but an i32 or nothing.
+func generateUserMain(module llvm.Module, functionNode *FunctionNode) error {
+	if len(functionNode.Parameters) != 0 || len(functionNode.TypeParameters) != 0 || (functionNode.ReturnType != VoidType && functionNode.ReturnType != Integer32Type) {
+		return newError(MessageInvalidMain)
+	}
+	if _, ok := globalScope.Callers.Get(mainIdentifier); ok {
+		return newError(MessageDuplicateMain)
+	}
+	if err := generateFunction(module, functionNode); err != nil {
+		return err
+	}
+
+	main, _ := globalScope.Callers.Get(mainIdentifier)
+	main.Value.SetName(userMainIdentifier)
+	main.Value.SetLinkage(llvm.InternalLinkage)
+	return nil
+}
+
+// generateMainReturn is a function that generates LLVM IR code for the end of the synthesized main
+// function. It calls the main function declared by the program, if there is one, and returns its
+// exit status, or 0. If the top-level statements ended with a return, the block following it is
+// empty and removed instead.
+//
+// mainBuilder:  The LLVM builder associated with the synthesized main function.
+func generateMainReturn(mainBuilder llvm.Builder) {
+	last := mainBuilder.GetInsertBlock()
+	if isUnreachable(last) && last.FirstInstruction().IsNil() {
+		last.EraseFromParent()
+		return
+	}
+
+	status := llvm.ConstInt(globalScope.Context.Int32Type(), 0, false)
+	if main, ok := globalScope.Callers.Get(mainIdentifier); ok {
+		call := mainBuilder.CreateCall(*main.Type, *main.Value, []llvm.Value{}, "")
+		if main.ReturnType == Integer32Type {
+			status = call
+		}
+	}
+	mainBuilder.CreateRet(status)
+}
+
+// removeLibraryMain removes the function the top-level declarations of a library were generated
+// into, which must not hold any instruction, as a library has no main function to run them.
+//
+// Returns an error if a top-level statement generated an instruction, e.g. a call or a let of a
+// value which isn't a constant.
+func removeLibraryMain(libraryMain llvm.Value) error {
+	for block := libraryMain.FirstBasicBlock(); !block.IsNil(); block = llvm.NextBasicBlock(block) {
+		if !block.FirstInstruction().IsNil() {
+			return newError(MessageLibraryStatement)
+		}
+	}
+	libraryMain.EraseFromParentAsFunction()
+	return nil
+}
@@ -0,0 +1,147 @@
+package lang
+
+import (
+	"fmt"
+
+	"tinygo.org/x/go-llvm"
+)
+
+// Constants for the identifiers of the builtin math functions. They are available in every program
+// without an import and are lowered to LLVM intrinsics, which LLVM generates inline or as calls
+// of the functions of libm.
+const (
+	absIdentifier  = "abs"
+	minIdentifier  = "min"
+	maxIdentifier  = "max"
+	powIdentifier  = "pow"
+	sqrtIdentifier = "sqrt"
+)
+
+// mathBuiltin describes a builtin math function by the number of its parameters and the names of
+// the LLVM intrinsics it is lowered to for integer and floating point values. An empty name means
+// that the builtin doesn't accept values of the kind.
+type mathBuiltin struct {
+	Parameters int
+	Integer    string
+	Float      string
+}
+
+// mathBuiltins holds the builtin math functions by identifier.
+var mathBuiltins = map[string]mathBuiltin{
+	absIdentifier:  {Parameters: 1, Integer: "llvm.abs", Float: "llvm.fabs"},
+	minIdentifier:  {Parameters: 2, Integer: "llvm.smin", Float: "llvm.minnum"},
+	maxIdentifier:  {Parameters: 2, Integer: "llvm.smax", Float: "llvm.maxnum"},
+	powIdentifier:  {Parameters: 2, Float: "llvm.pow"},
+	sqrtIdentifier: {Parameters: 1, Float: "llvm.sqrt"},
+}
+
+// isMathBuiltin reports whether a call of the function with the given name calls a builtin math
+// function. Functions of the program shadow the builtin of the same name.
+func isMathBuiltin(scope *Scope, name string) bool {
+	if _, ok := mathBuiltins[name]; !ok {
+		return false
+	}
+	if _, ok := scope.Callers.Get(name); ok {
+		return false
+	}
+	_, ok := globalScope.Callers.Get(name)
+	return !ok
+}
+
+// generateMath is a function that generates LLVM IR code for a call of a builtin math function,
+// which returns a value of the data type of its parameters. The parameters take the data type of
+// the first parameter which isn't a literal. If all parameters are literals, they take the type t
+// or the default type of the first literal, which is f64 for builtins only accepting floating point
+// values.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+// t:                A pointer to the data type of the value, nil to take it from the parameters.
+//
+// Returns an error if the number of parameters is wrong or the builtin doesn't accept values of their data type.
+func generateMath(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode, t *dataType) (llvm.Value, dataType, error) {
+	builtin := mathBuiltins[callerNode.FunctionName]
+	if len(callerNode.Args) != builtin.Parameters {
+		if builtin.Parameters == 1 {
+			return llvm.Value{}, 0, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+		}
+		return llvm.Value{}, 0, newError(MessageExpectedTwoParameters, callerNode.FunctionName, len(callerNode.Args))
+	}
+
+	// Generate the parameters which aren't literals first, they determine the data type of the literals
+	values := make([]llvm.Value, len(callerNode.Args))
+	var valueType dataType
+	typed := false
+	for i, arg := range callerNode.Args {
+		if _, ok := literalDataType(arg); ok {
+			continue
+		}
+		value, parameterType, err := generateValue(scope, functionBuilder, arg)
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, i+1, callerNode.FunctionName, err)
+		}
+		if typed && parameterType != valueType {
+			return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, i+1, callerNode.FunctionName, newError(MessageValueType, parameterType, valueType))
+		}
+		values[i], valueType, typed = value, parameterType, true
+	}
+	if !typed {
+		switch {
+		case t != nil:
+			valueType = *t
+		case builtin.Integer == "":
+			valueType = Float64Type
+		default:
+			valueType, _ = literalDataType(callerNode.Args[0])
+		}
+	}
+
+	var intrinsic string
+	switch {
+	case valueType.isInteger():
+		intrinsic = builtin.Integer
+	case valueType.isFloat():
+		intrinsic = builtin.Float
+	}
+	if intrinsic == "" {
+		if builtin.Integer == "" {
+			return llvm.Value{}, 0, newError(MessageMathFloatType, callerNode.FunctionName, valueType)
+		}
+		return llvm.Value{}, 0, newError(MessageMathType, callerNode.FunctionName, valueType)
+	}
+
+	for i, arg := range callerNode.Args {
+		if _, ok := literalDataType(arg); !ok {
+			continue
+		}
+		value, err := generateConstant(arg, valueType)
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, i+1, callerNode.FunctionName, err)
+		}
+		values[i] = value
+	}
+
+	// The integer abs intrinsic takes whether the absolute value of the minimum is poison, it wraps instead
+	parameterTypes := make([]llvm.Type, len(values))
+	for i := range values {
+		parameterTypes[i] = llvmType(valueType)
+	}
+	if intrinsic == builtin.Integer && callerNode.FunctionName == absIdentifier {
+		values = append(values, llvm.ConstInt(globalScope.Context.Int1Type(), 0, false))
+		parameterTypes = append(parameterTypes, globalScope.Context.Int1Type())
+	}
+
+	functionType, function := runtimeFunction(functionBuilder, intrinsicName(intrinsic, valueType), llvm.FunctionType(llvmType(valueType), parameterTypes, false), nil)
+	return functionBuilder.CreateCall(functionType, function, values, ""), valueType, nil
+}
+
+// intrinsicName returns the name of the overload of the LLVM intrinsic for values of the integer or
+// floating point data type, e.g. llvm.smax.i32 or llvm.sqrt.f64.
+func intrinsicName(intrinsic string, t dataType) string {
+	kind := "i"
+	if t.isFloat() {
+		kind = "f"
+	}
+	return fmt.Sprintf("%s.%s%d", intrinsic, kind, dataTypeBits(t))
+}
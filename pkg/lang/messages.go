@@ -0,0 +1,723 @@
+package lang
+
+// MessageID identifies a diagnostic message in the message catalog.
+// The IDs are stable, so they can be used to match errors and to write translations.
+type MessageID string
+
+// Message IDs of the diagnostics reported while parsing.
+const (
+	MessageExpectedType                                    MessageID = "expected_type"
+	MessageExpectedIdentifierAfterSemicolon                MessageID = "expected_identifier_after_semicolon"
+	MessageExpectedSemicolonAfterValue                     MessageID = "expected_semicolon_after_value"
+	MessageExpectedCloseCurlyAfterFunctionBody             MessageID = "expected_close_curly_after_function_body"
+	MessageExpectedOpenCurlyAfterStructName                MessageID = "expected_open_curly_after_struct_name"
+	MessageExpectedOpenCurlyAfterFunctionParameters        MessageID = "expected_open_curly_after_function_parameters"
+	MessageExpectedIntValue                                MessageID = "expected_int_value"
+	MessageUnexpectedIdentifier                            MessageID = "unexpected_identifier"
+	MessageUnexpectedToken                                 MessageID = "unexpected_token"
+	MessageUnexpectedTokenAfterExpression                  MessageID = "unexpected_token_after_expression"
+	MessageFeatureDisabled                                 MessageID = "feature_disabled"
+	MessageUnexpectedEndOfInput                            MessageID = "unexpected_end_of_input"
+	MessageNestingTooDeep                                  MessageID = "nesting_too_deep"
+	MessageInvalidEdit                                     MessageID = "invalid_edit"
+	MessageReservedWord                                    MessageID = "reserved_word"
+	MessageInvalidArrayLength                              MessageID = "invalid_array_length"
+	MessageReservedPrefix                                  MessageID = "reserved_prefix"
+	MessageExpectedValue                                   MessageID = "expected_value"
+	MessageExpectedTypeAfterStructField                    MessageID = "expected_type_after_struct_field"
+	MessageExpectedTypeAfterFunctionParameter              MessageID = "expected_type_after_function_parameter"
+	MessageExpectedTypeAfterAs                             MessageID = "expected_type_after_as"
+	MessageExpectedTypeAfterColon                          MessageID = "expected_type_after_colon"
+	MessageExpectedIndexAfterIdentifier                    MessageID = "expected_index_after_identifier"
+	MessageExpectedIdentifierAfterStruct                   MessageID = "expected_identifier_after_struct"
+	MessageExpectedIdentifierAfterEmbed                    MessageID = "expected_identifier_after_embed"
+	MessageExpectedFileNameAfterEmbed                      MessageID = "expected_file_name_after_embed"
+	MessageExpectedFileNameAfterImport                     MessageID = "expected_file_name_after_import"
+	MessageExpectedFunctionAfterExtern                     MessageID = "expected_function_after_extern"
+	MessageExternBody                                      MessageID = "extern_body"
+	MessageExpectedInlineIRAfterLLVM                       MessageID = "expected_inline_ir_after_llvm"
+	MessageExpectedPackageName                             MessageID = "expected_package_name"
+	MessagePackageNotFirst                                 MessageID = "package_not_first"
+	MessageExpectedLetAfterQualifier                       MessageID = "expected_let_after_qualifier"
+	MessageDuplicateQualifier                              MessageID = "duplicate_qualifier"
+	MessageExpectedMemoryOrdering                          MessageID = "expected_memory_ordering"
+	MessageUnknownMemoryOrdering                           MessageID = "unknown_memory_ordering"
+	MessageExpectedCloseParenthesisAfterMemoryOrdering     MessageID = "expected_close_parenthesis_after_memory_ordering"
+	MessageExpectedIdentifierAfterLet                      MessageID = "expected_identifier_after_let"
+	MessageExpectedIdentifierAfterFunction                 MessageID = "expected_identifier_after_function"
+	MessageExpectedIdentifierAfterFor                      MessageID = "expected_identifier_after_for"
+	MessageExpectedIdentifierAfterShortVariableAssignment  MessageID = "expected_identifier_after_short_variable_assignment"
+	MessageExpectedFieldName                               MessageID = "expected_field_name"
+	MessageExpectedFieldNameAfterDot                       MessageID = "expected_field_name_after_dot"
+	MessageExpectedArrayLength                             MessageID = "expected_array_length"
+	MessageExpectedLessThanAfterIdentifier                 MessageID = "expected_less_than_after_identifier"
+	MessageExpectedShortVariableAssignmentAfterIdentifier  MessageID = "expected_short_variable_assignment_after_identifier"
+	MessageExpectedCloseCurlyAfterWhileBody                MessageID = "expected_close_curly_after_while_body"
+	MessageExpectedCloseCurlyAfterStructFields             MessageID = "expected_close_curly_after_struct_fields"
+	MessageUnknownStructAttribute                          MessageID = "unknown_struct_attribute"
+	MessageExpectedStructAfterAttribute                    MessageID = "expected_struct_after_attribute"
+	MessageUnknownFunctionAttribute                        MessageID = "unknown_function_attribute"
+	MessageConflictingFunctionAttributes                   MessageID = "conflicting_function_attributes"
+	MessageUnknownFieldAttribute                           MessageID = "unknown_field_attribute"
+	MessageExpectedOpenParenthesisAfterAlign               MessageID = "expected_open_parenthesis_after_align"
+	MessageExpectedAlignment                               MessageID = "expected_alignment"
+	MessageInvalidAlignment                                MessageID = "invalid_alignment"
+	MessageExpectedCloseParenthesisAfterAlignment          MessageID = "expected_close_parenthesis_after_alignment"
+	MessageExpectedGreaterThanAfterElementType             MessageID = "expected_greater_than_after_element_type"
+	MessageExpectedColonInConditional                      MessageID = "expected_colon_in_conditional"
+	MessageExpectedIdentifierAfterTypeKeyword              MessageID = "expected_identifier_after_type_keyword"
+	MessageExpectedEqualsAfterTypeAlias                    MessageID = "expected_equals_after_type_alias"
+	MessageUnexpectedGreaterThanAfterType                  MessageID = "unexpected_greater_than_after_type"
+	MessageExpectedCloseCurlyAfterFieldValues              MessageID = "expected_close_curly_after_field_values"
+	MessageExpectedOpenCurlyAfterWhileCondition            MessageID = "expected_open_curly_after_while_condition"
+	MessageExpectedFor                                     MessageID = "expected_for"
+	MessageExpectedAddSignAfterValue                       MessageID = "expected_add_sign_after_value"
+	MessageExpectedAddSignAfterIdentifier                  MessageID = "expected_add_sign_after_identifier"
+	MessageExpectedAddSignAfterAddSign                     MessageID = "expected_add_sign_after_add_sign"
+	MessageExpectedCloseSquareAfterIndex                   MessageID = "expected_close_square_after_index"
+	MessageExpectedCloseSquareAfterArrayLength             MessageID = "expected_close_square_after_array_length"
+	MessageExpectedCloseSquareAfterArrayElements           MessageID = "expected_close_square_after_array_elements"
+	MessageExpectedOpenSquare                              MessageID = "expected_open_square"
+	MessageExpectedEqualsAfterLet                          MessageID = "expected_equals_after_let"
+	MessageExpectedEqualsAfterIndex                        MessageID = "expected_equals_after_index"
+	MessageExpectedEqualsAfterIdentifier                   MessageID = "expected_equals_after_identifier"
+	MessageExpectedColonAfterFieldName                     MessageID = "expected_colon_after_field_name"
+	MessageExpectedCloseParenthesisAfterWhileCondition     MessageID = "expected_close_parenthesis_after_while_condition"
+	MessageExpectedCloseParenthesisAfterParameters         MessageID = "expected_close_parenthesis_after_parameters"
+	MessageExpectedCloseParenthesisAfterFunctionParameters MessageID = "expected_close_parenthesis_after_function_parameters"
+	MessageExpectedCloseParenthesisAfterCastValue          MessageID = "expected_close_parenthesis_after_cast_value"
+	MessageExpectedOpenParenthesisAfterType                MessageID = "expected_open_parenthesis_after_type"
+	MessageExpectedOpenParenthesisAfterFunctionName        MessageID = "expected_open_parenthesis_after_function_name"
+	MessageExpectedOpenParenthesisAfterCaller              MessageID = "expected_open_parenthesis_after_caller"
+	MessageExpectedTypeAfterNew                            MessageID = "expected_type_after_new"
+	MessageExpectedCloseParenthesisAfterNewType            MessageID = "expected_close_parenthesis_after_new_type"
+	MessageExpectedOpenParenthesisAfterWhile               MessageID = "expected_open_parenthesis_after_while"
+)
+
+// Message IDs of the diagnostics reported while generating LLVM IR.
+const (
+	MessageVoidCallAsValue                       MessageID = "void_call_as_value"
+	MessageVariableNotFound                      MessageID = "variable_not_found"
+	MessageInvalidArrayElement                   MessageID = "invalid_array_element"
+	MessageExpectedOneParameter                  MessageID = "expected_one_parameter"
+	MessageExpectedNoParameters                  MessageID = "expected_no_parameters"
+	MessageExpectedTwoParameters                 MessageID = "expected_two_parameters"
+	MessageUnknownType                           MessageID = "unknown_type"
+	MessageUnknownStruct                         MessageID = "unknown_struct"
+	MessageUnknownField                          MessageID = "unknown_field"
+	MessageFieldType                             MessageID = "field_type"
+	MessageUnexpectedReturnValue                 MessageID = "unexpected_return_value"
+	MessageInvalidExitStatus                     MessageID = "invalid_exit_status"
+	MessageInvalidMain                           MessageID = "invalid_main"
+	MessageDuplicateMain                         MessageID = "duplicate_main"
+	MessageLibraryStatement                      MessageID = "library_statement"
+	MessageExpectedCallAfterSpawn                MessageID = "expected_call_after_spawn"
+	MessageInvalidSpawnTarget                    MessageID = "invalid_spawn_target"
+	MessageSpawnGarbageCollection                MessageID = "spawn_garbage_collection"
+	MessageNilFunctionValue                      MessageID = "nil_function_value"
+	MessageNilFunctionType                       MessageID = "nil_function_type"
+	MessageNestedStruct                          MessageID = "nested_struct"
+	MessageNestedEmbed                           MessageID = "nested_embed"
+	MessageNestedThreadLocal                     MessageID = "nested_thread_local"
+	MessageThreadLocalInitializer                MessageID = "thread_local_initializer"
+	MessageAtomicType                            MessageID = "atomic_type"
+	MessageAtomicOperand                         MessageID = "atomic_operand"
+	MessageAtomicAddOperand                      MessageID = "atomic_add_operand"
+	MessageNestedFunction                        MessageID = "nested_function"
+	MessageMissingReturnValue                    MessageID = "missing_return_value"
+	MessageMissingReturn                         MessageID = "missing_return"
+	MessageInvalidValueType                      MessageID = "invalid_value_type"
+	MessageInvalidInitValueType                  MessageID = "invalid_init_value_type"
+	MessageInvalidLetValue                       MessageID = "invalid_let_value"
+	MessageInvalidFieldValue                     MessageID = "invalid_field_value"
+	MessageInvalidAssignmentValue                MessageID = "invalid_assignment_value"
+	MessageInvalidArrayElementValue              MessageID = "invalid_array_element_value"
+	MessageInvalidFieldAssignmentValue           MessageID = "invalid_field_assignment_value"
+	MessageInvalidDereferenceAssignmentValue     MessageID = "invalid_dereference_assignment_value"
+	MessageInvalidParameterType                  MessageID = "invalid_parameter_type"
+	MessageInvalidFieldType                      MessageID = "invalid_field_type"
+	MessageInvalidLetType                        MessageID = "invalid_let_type"
+	MessageInvalidNewType                        MessageID = "invalid_new_type"
+	MessageInvalidReturnValue                    MessageID = "invalid_return_value"
+	MessageInvalidReturnType                     MessageID = "invalid_return_type"
+	MessageRecursiveStruct                       MessageID = "recursive_struct"
+	MessageInvalidCallerParameter                MessageID = "invalid_caller_parameter"
+	MessageInvalidBuiltinParameter               MessageID = "invalid_builtin_parameter"
+	MessageInvalidAddOperandType                 MessageID = "invalid_add_operand_type"
+	MessageInvalidLiteral                        MessageID = "invalid_literal"
+	MessageInvalidCast                           MessageID = "invalid_cast"
+	MessageInvalidArrayIndex                     MessageID = "invalid_array_index"
+	MessageInvalidArrayIndexType                 MessageID = "invalid_array_index_type"
+	MessageInvalidAddOperation                   MessageID = "invalid_add_operation"
+	MessageInvalidBuiltinValue                   MessageID = "invalid_builtin_value"
+	MessageIndexOutOfBounds                      MessageID = "index_out_of_bounds"
+	MessageExpectedSliceAndValues                MessageID = "expected_slice_and_values"
+	MessageExpectedParameters                    MessageID = "expected_parameters"
+	MessageExpectedTypeParameter                 MessageID = "expected_type_parameter"
+	MessageExpectedCloseAngleAfterTypeParameters MessageID = "expected_close_angle_after_type_parameters"
+	MessageDuplicateTypeParameter                MessageID = "duplicate_type_parameter"
+	MessageUninferredTypeParameter               MessageID = "uninferred_type_parameter"
+	MessageGenericParameterMismatch              MessageID = "generic_parameter_mismatch"
+	MessageGenericInstantiation                  MessageID = "generic_instantiation"
+	MessageGenericExtern                         MessageID = "generic_extern"
+	MessageDuplicateField                        MessageID = "duplicate_field"
+	MessageDuplicateFieldValue                   MessageID = "duplicate_field_value"
+	MessageDuplicateStruct                       MessageID = "duplicate_struct"
+	MessageDuplicateEmbed                        MessageID = "duplicate_embed"
+	MessageEmbedFile                             MessageID = "embed_file"
+	MessageConstantOverflow                      MessageID = "constant_overflow"
+	MessageArrayLiteralType                      MessageID = "array_literal_type"
+	MessageFloatLiteralType                      MessageID = "float_literal_type"
+	MessageBoolLiteralType                       MessageID = "bool_literal_type"
+	MessageValueType                             MessageID = "value_type"
+	MessageIntegerLiteralType                    MessageID = "integer_literal_type"
+	MessagePrintType                             MessageID = "print_type"
+	MessageComparisonType                        MessageID = "comparison_type"
+	MessageJSONDataType                          MessageID = "json_data_type"
+	MessageJSONValue                             MessageID = "json_value"
+	MessageJSONNode                              MessageID = "json_node"
+	MessageInvalidNodeNil                        MessageID = "invalid_node_nil"
+	MessageInvalidNodeName                       MessageID = "invalid_node_name"
+	MessageInvalidNodeType                       MessageID = "invalid_node_type"
+	MessageInvalidNodeOperator                   MessageID = "invalid_node_operator"
+	MessageInvalidNodeKind                       MessageID = "invalid_node_kind"
+	MessageDidYouMean                            MessageID = "did_you_mean"
+	MessageDuplicateFunction                     MessageID = "duplicate_function"
+	MessageDuplicateVariable                     MessageID = "duplicate_variable"
+	MessageDuplicateParameter                    MessageID = "duplicate_parameter"
+	MessageFirstDeclaredAt                       MessageID = "first_declared_at"
+	MessageUnusedVariable                        MessageID = "unused_variable"
+	MessageUnusedParameter                       MessageID = "unused_parameter"
+	MessageUnreachableAfterReturn                MessageID = "unreachable_after_return"
+	MessageUnreachableAfterExit                  MessageID = "unreachable_after_exit"
+	MessageShadowedParameter                     MessageID = "shadowed_parameter"
+	MessageShadowedVariable                      MessageID = "shadowed_variable"
+	MessageFormatType                            MessageID = "format_type"
+	MessageExpectedFormatString                  MessageID = "expected_format_string"
+	MessageEmptyArrayLiteral                     MessageID = "empty_array_literal"
+	MessageIndexType                             MessageID = "index_type"
+	MessageDereferenceType                       MessageID = "dereference_type"
+	MessageAssignToArgument                      MessageID = "assign_to_argument"
+	MessageAssignToArrayValue                    MessageID = "assign_to_array_value"
+	MessageAssignToStructValue                   MessageID = "assign_to_struct_value"
+	MessageAddressOfValue                        MessageID = "address_of_value"
+	MessageAppendType                            MessageID = "append_type"
+	MessageUntypedNone                           MessageID = "untyped_none"
+	MessageInvalidOptionValue                    MessageID = "invalid_option_value"
+	MessageUnwrapType                            MessageID = "unwrap_type"
+	MessageInvalidUnwrapFallback                 MessageID = "invalid_unwrap_fallback"
+	MessageUntypedErr                            MessageID = "untyped_err"
+	MessageInvalidResultValue                    MessageID = "invalid_result_value"
+	MessageInvalidErrorCode                      MessageID = "invalid_error_code"
+	MessageTryType                               MessageID = "try_type"
+	MessageTryOutsideFunction                    MessageID = "try_outside_function"
+	MessageTryReturnType                         MessageID = "try_return_type"
+	MessageConditionType                         MessageID = "condition_type"
+	MessageInvalidConditionalValue               MessageID = "invalid_conditional_value"
+	MessageInvalidShiftOperation                 MessageID = "invalid_shift_operation"
+	MessageShiftOperandType                      MessageID = "shift_operand_type"
+	MessageShiftAmount                           MessageID = "shift_amount"
+	MessageUnknownPass                           MessageID = "unknown_pass"
+	MessagePassFailed                            MessageID = "pass_failed"
+	MessageDuplicateTypeName                     MessageID = "duplicate_type_name"
+	MessageTypeAliasCycle                        MessageID = "type_alias_cycle"
+	MessageNestedTypeAlias                       MessageID = "nested_type_alias"
+	MessageNestedImport                          MessageID = "nested_import"
+	MessageNestedExtern                          MessageID = "nested_extern"
+	MessageExternDeclared                        MessageID = "extern_declared"
+	MessageInlineIR                              MessageID = "inline_ir"
+	MessageInlineIRRedefined                     MessageID = "inline_ir_redefined"
+	MessageImportFile                            MessageID = "import_file"
+	MessageUnresolvedImport                      MessageID = "unresolved_import"
+	MessageImportCycle                           MessageID = "import_cycle"
+	MessageFunctionInstructionBudget             MessageID = "function_instruction_budget"
+	MessageFunctionBlockBudget                   MessageID = "function_block_budget"
+	MessageInstructionBudget                     MessageID = "instruction_budget"
+	MessageBlockBudget                           MessageID = "block_budget"
+	MessageMathType                              MessageID = "math_type"
+	MessageMathFloatType                         MessageID = "math_float_type"
+	MessageEvaluationIncomplete                  MessageID = "evaluation_incomplete"
+	MessageEvaluationSteps                       MessageID = "evaluation_steps"
+	MessageFreeType                              MessageID = "free_type"
+	MessageCallerNotFound                        MessageID = "caller_not_found"
+	MessageArrayLiteralOverflow                  MessageID = "array_literal_overflow"
+)
+
+// Message IDs of the diagnostics reported by lint rules.
+const (
+	MessageComplexityTooHigh MessageID = "complexity_too_high"
+)
+
+// messages is the message catalog. It maps every locale to the formats of its messages,
+// which use the verbs of package fmt. A %w verb marks a wrapped error, which is rendered
+// in the same locale.
+var messages = map[Locale]map[MessageID]string{
+	LocaleEnglish: {
+		MessageExpectedType:                                    "expected type at position %d",
+		MessageExpectedIdentifierAfterSemicolon:                "expected identifier after ';' at position %d",
+		MessageExpectedSemicolonAfterValue:                     "expected ; after 'value' at position %d",
+		MessageExpectedCloseCurlyAfterFunctionBody:             "expected '}' after function body at position %d",
+		MessageExpectedOpenCurlyAfterStructName:                "expected '{' after struct name at position %d",
+		MessageExpectedOpenCurlyAfterFunctionParameters:        "expected '{' after function parameters at position %d",
+		MessageExpectedIntValue:                                "expected 'int' as value at position %d",
+		MessageUnexpectedIdentifier:                            "unexpected identifier '%s' at position %d",
+		MessageUnexpectedToken:                                 "unexpected %s at position %d",
+		MessageUnexpectedTokenAfterExpression:                  "unexpected %s after the expression at position %d",
+		MessageFeatureDisabled:                                 "the language feature %s is disabled at position %d",
+		MessageUnexpectedEndOfInput:                            "unexpected end of input, expected %s in %s",
+		MessageNestingTooDeep:                                  "nesting too deep at position %d, the maximum depth is %d",
+		MessageInvalidEdit:                                     "invalid edit of the source code from %s to %s",
+		MessageReservedWord:                                    "reserved word '%s' used as identifier at position %d",
+		MessageInvalidArrayLength:                              "invalid array length '%s' at position %d",
+		MessageReservedPrefix:                                  "identifier '%s' at position %d uses the reserved prefix '%s'",
+		MessageExpectedValue:                                   "expected value at position %d",
+		MessageExpectedTypeAfterStructField:                    "expected type after struct field at position %d",
+		MessageExpectedTypeAfterFunctionParameter:              "expected type after function parameter at position %d",
+		MessageExpectedTypeAfterAs:                             "expected type after 'as' at position %d",
+		MessageExpectedTypeAfterColon:                          "expected type after ':' at position %d",
+		MessageExpectedIndexAfterIdentifier:                    "expected index after identifier at position %d",
+		MessageExpectedIdentifierAfterStruct:                   "expected identifier after 'struct' at position %d",
+		MessageExpectedIdentifierAfterEmbed:                    "expected identifier after 'embed' at position %d",
+		MessageExpectedFileNameAfterEmbed:                      "expected file name after embed identifier at position %d",
+		MessageExpectedFileNameAfterImport:                     "expected file name after import at position %d",
+		MessageExpectedFunctionAfterExtern:                     "expected function after extern at position %d",
+		MessageExternBody:                                      "extern function %s can't have a body, at position %d",
+		MessageExpectedInlineIRAfterLLVM:                       "expected a block of LLVM IR after llvm at position %d",
+		MessageExpectedPackageName:                             "expected package name after 'package' at position %d",
+		MessagePackageNotFirst:                                 "package declaration must start the file at position %d",
+		MessageExpectedLetAfterQualifier:                       "expected let after %s at position %d",
+		MessageDuplicateQualifier:                              "duplicate qualifier %s at position %d",
+		MessageExpectedMemoryOrdering:                          "expected memory ordering after atomic( at position %d",
+		MessageUnknownMemoryOrdering:                           "unknown memory ordering %s at position %d, expected relaxed, acq_rel or seq_cst",
+		MessageExpectedCloseParenthesisAfterMemoryOrdering:     "expected ')' after memory ordering at position %d",
+		MessageExpectedIdentifierAfterLet:                      "expected identifier after 'let' at position %d",
+		MessageExpectedIdentifierAfterFunction:                 "expected identifier after 'function' at position %d",
+		MessageExpectedIdentifierAfterFor:                      "expected identifier after 'for' at position %d",
+		MessageExpectedIdentifierAfterShortVariableAssignment:  "expected identifier after ':=' at position %d",
+		MessageExpectedFieldName:                               "expected field name at position %d",
+		MessageExpectedFieldNameAfterDot:                       "expected field name after '.' at position %d",
+		MessageExpectedArrayLength:                             "expected array length after '[' at position %d",
+		MessageExpectedLessThanAfterIdentifier:                 "expected < after 'idenfifier' at position %d",
+		MessageExpectedShortVariableAssignmentAfterIdentifier:  "expected := after 'identifier' at position %d",
+		MessageExpectedCloseCurlyAfterWhileBody:                "expected '}' after while body at position %d",
+		MessageExpectedCloseCurlyAfterStructFields:             "expected '}' after struct fields at position %d",
+		MessageUnknownStructAttribute:                          "unknown struct attribute at position %d, expected @packed",
+		MessageExpectedStructAfterAttribute:                    "expected struct, function or extern after attribute at position %d",
+		MessageUnknownFunctionAttribute:                        "unknown function attribute %s at position %d, expected @inline, @noinline or @noreturn",
+		MessageConflictingFunctionAttributes:                   "function %s can't be both @inline and @noinline",
+		MessageUnknownFieldAttribute:                           "unknown field attribute at position %d, expected @align",
+		MessageExpectedOpenParenthesisAfterAlign:               "expected '(' after @align at position %d",
+		MessageExpectedAlignment:                               "expected alignment at position %d",
+		MessageInvalidAlignment:                                "invalid alignment %s at position %d, expected a power of two",
+		MessageExpectedCloseParenthesisAfterAlignment:          "expected ')' after alignment at position %d",
+		MessageExpectedGreaterThanAfterElementType:             "expected '>' after %s element type at position %d",
+		MessageExpectedColonInConditional:                      "expected ':' in conditional expression at position %d",
+		MessageExpectedIdentifierAfterTypeKeyword:              "expected identifier after 'type' at position %d",
+		MessageExpectedEqualsAfterTypeAlias:                    "expected '=' after type alias name at position %d",
+		MessageUnexpectedGreaterThanAfterType:                  "unexpected '>' after type at position %d",
+		MessageExpectedCloseCurlyAfterFieldValues:              "expected '}' after field values at position %d",
+		MessageExpectedOpenCurlyAfterWhileCondition:            "expected '{' after while condition at position %d",
+		MessageExpectedFor:                                     "expected 'for' at start %d",
+		MessageExpectedAddSignAfterValue:                       "expected 'add sign' after value at position %d",
+		MessageExpectedAddSignAfterIdentifier:                  "expected 'add sign' after 'identifier' at position %d",
+		MessageExpectedAddSignAfterAddSign:                     "expected 'add sign' after 'add sign' at position %d",
+		MessageExpectedCloseSquareAfterIndex:                   "expected ']' after index at position %d",
+		MessageExpectedCloseSquareAfterArrayLength:             "expected ']' after array length at position %d",
+		MessageExpectedCloseSquareAfterArrayElements:           "expected ']' after array elements at position %d",
+		MessageExpectedOpenSquare:                              "expected '[' at position %d",
+		MessageExpectedEqualsAfterLet:                          "expected '=' after let at position %d",
+		MessageExpectedEqualsAfterIndex:                        "expected '=' after index at position %d",
+		MessageExpectedEqualsAfterIdentifier:                   "expected '=' after identifier at position %d",
+		MessageExpectedColonAfterFieldName:                     "expected ':' after field name at position %d",
+		MessageExpectedCloseParenthesisAfterWhileCondition:     "expected ')' after while condition at position %d",
+		MessageExpectedCloseParenthesisAfterParameters:         "expected ')' after parameters at position %d",
+		MessageExpectedCloseParenthesisAfterFunctionParameters: "expected ')' after function parameters at position %d",
+		MessageExpectedCloseParenthesisAfterCastValue:          "expected ')' after cast value at position %d",
+		MessageExpectedOpenParenthesisAfterType:                "expected '(' after type at position %d",
+		MessageExpectedOpenParenthesisAfterFunctionName:        "expected '(' after function name at position %d",
+		MessageExpectedOpenParenthesisAfterCaller:              "expected '(' after caller at position %d",
+		MessageExpectedTypeAfterNew:                            "expected type after 'new(' at position %d",
+		MessageExpectedCloseParenthesisAfterNewType:            "expected ')' after type of new at position %d",
+		MessageExpectedOpenParenthesisAfterWhile:               "expected '(' after 'while' at position %d",
+		MessageVoidCallAsValue:                                 "call of a function without return value used as value",
+		MessageVariableNotFound:                                "variable not found in scope: %s",
+		MessageInvalidArrayElement:                             "invalid array element %d: %w",
+		MessageExpectedOneParameter:                            "expected exactly one parameter for %s, got %d",
+		MessageExpectedNoParameters:                            "expected no parameters for %s, got %d",
+		MessageExpectedTwoParameters:                           "expected exactly two parameters for %s, got %d",
+		MessageUnknownType:                                     "unknown type %s",
+		MessageUnknownStruct:                                   "unknown struct %s",
+		MessageUnknownField:                                    "unknown field %s in struct %s",
+		MessageFieldType:                                       "cannot select field %s of %s value",
+		MessageUnexpectedReturnValue:                           "unexpected return value in function %s",
+		MessageInvalidExitStatus:                               "invalid exit status: %w",
+		MessageInvalidMain:                                     "function main must take no parameters and return i32 or nothing",
+		MessageDuplicateMain:                                   "function main declared more than once",
+		MessageLibraryStatement:                                "top-level statements other than declarations need a main function, which isn't generated for a library",
+		MessageExpectedCallAfterSpawn:                          "expected a call after spawn at position %d",
+		MessageInvalidSpawnTarget:                              "spawn expects a function declared by the program, %s isn't one",
+		MessageSpawnGarbageCollection:                          "spawn can't be used with the garbage collector, which doesn't scan the stacks of threads",
+		MessageNilFunctionValue:                                "nil function value for caller: %s",
+		MessageNilFunctionType:                                 "nil function type for caller: %s",
+		MessageNestedStruct:                                    "nested struct declarations are not supported: %s",
+		MessageNestedEmbed:                                     "nested embed declarations are not supported: %s",
+		MessageNestedThreadLocal:                               "thread-local variables must be declared at the top level: %s",
+		MessageThreadLocalInitializer:                          "initializer of thread-local variable %s must be a constant expression",
+		MessageAtomicType:                                      "atomic variable %s must have an integer, floating point or pointer type, got %s",
+		MessageAtomicOperand:                                   "%s expects a pointer to an integer, floating point or pointer value, got %s",
+		MessageAtomicAddOperand:                                "%s expects a pointer to an integer, got %s",
+		MessageNestedFunction:                                  "nested function definitions are not supported: %s",
+		MessageMissingReturnValue:                              "missing return value in function %s",
+		MessageMissingReturn:                                   "missing return at end of function %s",
+		MessageInvalidValueType:                                "invalid value type: %v",
+		MessageInvalidInitValueType:                            "invalid value type for init: %v",
+		MessageInvalidLetValue:                                 "invalid value for let node %s: %w",
+		MessageInvalidFieldValue:                               "invalid value for field %s of struct %s: %w",
+		MessageInvalidAssignmentValue:                          "invalid value for assignment to %s: %w",
+		MessageInvalidArrayElementValue:                        "invalid value for array element: %w",
+		MessageInvalidFieldAssignmentValue:                     "invalid value for field %s: %w",
+		MessageInvalidDereferenceAssignmentValue:               "invalid value for dereferenced pointer: %w",
+		MessageInvalidParameterType:                            "invalid type of parameter %s of function %s: %w",
+		MessageInvalidFieldType:                                "invalid type of field %s in struct %s: %w",
+		MessageInvalidLetType:                                  "invalid type for let node %s: %w",
+		MessageInvalidNewType:                                  "invalid type for new: %w",
+		MessageInvalidReturnValue:                              "invalid return value in function %s: %w",
+		MessageInvalidReturnType:                               "invalid return type of function %s: %w",
+		MessageRecursiveStruct:                                 "invalid recursive struct %s",
+		MessageInvalidCallerParameter:                          "invalid parameter %d of caller %s: %w",
+		MessageInvalidBuiltinParameter:                         "invalid parameter %d of %s: %w",
+		MessageInvalidAddOperandType:                           "invalid operand type %s for add operation",
+		MessageInvalidLiteral:                                  "invalid literal: %v",
+		MessageInvalidCast:                                     "invalid cast from %s to %s",
+		MessageInvalidArrayIndex:                               "invalid array index: %w",
+		MessageInvalidArrayIndexType:                           "invalid array index of type %s",
+		MessageInvalidAddOperation:                             "invalid add operation: %w",
+		MessageInvalidBuiltinValue:                             "invalid %s value for %s",
+		MessageIndexOutOfBounds:                                "index %d out of bounds for %s",
+		MessageExpectedSliceAndValues:                          "expected a slice and at least one value for %s, got %d parameters",
+		MessageExpectedParameters:                              "expected %d parameters for caller %s, got %d",
+		MessageExpectedTypeParameter:                           "expected type parameter at position %d",
+		MessageExpectedCloseAngleAfterTypeParameters:           "expected '>' after type parameters at position %d",
+		MessageDuplicateTypeParameter:                          "type parameter %s of function %s declared more than once",
+		MessageUninferredTypeParameter:                         "can't infer type parameter %s of function %s from its parameters",
+		MessageGenericParameterMismatch:                        "parameter %d of function %s expects %s, got %s",
+		MessageGenericInstantiation:                            "in instantiation %s: %w",
+		MessageGenericExtern:                                   "extern function %s can't have type parameters",
+		MessageDuplicateField:                                  "duplicate field %s in struct %s",
+		MessageDuplicateFieldValue:                             "duplicate field %s in literal of struct %s",
+		MessageDuplicateStruct:                                 "duplicate declaration of struct %s",
+		MessageDuplicateEmbed:                                  "duplicate declaration of embed %s",
+		MessageEmbedFile:                                       "cannot embed file %s: %w",
+		MessageConstantOverflow:                                "constant %d overflows %s",
+		MessageArrayLiteralType:                                "cannot use array literal as %s value",
+		MessageFloatLiteralType:                                "cannot use %v as %s value",
+		MessageBoolLiteralType:                                 "cannot use %t as %s value",
+		MessageValueType:                                       "cannot use %s value as %s value",
+		MessageIntegerLiteralType:                              "cannot use %d as %s value",
+		MessagePrintType:                                       "cannot print %s value",
+		MessageComparisonType:                                  "cannot compare %s values with %s",
+		MessageJSONDataType:                                    "invalid data type %s in JSON",
+		MessageJSONValue:                                       "cannot encode value %v of type %T as JSON",
+		MessageJSONNode:                                        "invalid node in JSON: %s",
+		MessageInvalidNodeNil:                                  "%s has no %s",
+		MessageInvalidNodeName:                                 "%s has an empty %s",
+		MessageInvalidNodeType:                                 "%s has the invalid data type %s",
+		MessageInvalidNodeOperator:                             "%s has the unknown operator %v",
+		MessageInvalidNodeKind:                                 "unknown node %T in %s",
+		MessageDidYouMean:                                      "%w, did you mean %s?",
+		MessageDuplicateFunction:                               "duplicate declaration of function %s",
+		MessageDuplicateVariable:                               "duplicate declaration of variable %s",
+		MessageDuplicateParameter:                              "duplicate parameter %s of function %s",
+		MessageFirstDeclaredAt:                                 "%w, first declared at %s",
+		MessageUnusedVariable:                                  "variable %s is never read",
+		MessageUnusedParameter:                                 "parameter %s of function %s is never read",
+		MessageUnreachableAfterReturn:                          "unreachable code after the return at %s",
+		MessageUnreachableAfterExit:                            "unreachable code after the call of exit at %s",
+		MessageShadowedParameter:                               "parameter %s of function %s shadows the global declared at %s",
+		MessageShadowedVariable:                                "variable %s shadows the declaration at %s",
+		MessageFormatType:                                      "cannot format %s value",
+		MessageExpectedFormatString:                            "expected a format string as first parameter of %s",
+		MessageEmptyArrayLiteral:                               "cannot infer the type of an empty array literal",
+		MessageIndexType:                                       "cannot index %s value",
+		MessageDereferenceType:                                 "cannot dereference %s value",
+		MessageAssignToArgument:                                "cannot assign to argument %s",
+		MessageAssignToArrayValue:                              "cannot assign to an element of an array which isn't a variable",
+		MessageAssignToStructValue:                             "cannot assign to a field of a struct which isn't a variable",
+		MessageAddressOfValue:                                  "cannot take the address of a value which isn't a variable",
+		MessageAppendType:                                      "cannot append to %s value",
+		MessageUntypedNone:                                     "none needs an option type, e.g. let x: option<i32> = none",
+		MessageInvalidOptionValue:                              "invalid option value: %w",
+		MessageUnwrapType:                                      "cannot unwrap %s value, expected an option or result",
+		MessageInvalidUnwrapFallback:                           "invalid fallback value: %w",
+		MessageUntypedErr:                                      "err needs a result type, e.g. let x: result<i32> = err(1)",
+		MessageInvalidResultValue:                              "invalid result value: %w",
+		MessageInvalidErrorCode:                                "invalid error code: %w",
+		MessageTryType:                                         "cannot use try on %s value, expected a result",
+		MessageTryOutsideFunction:                              "try outside of a function",
+		MessageTryReturnType:                                   "cannot use try in function %s returning %s, expected a result return type",
+		MessageConditionType:                                   "condition must be a bool value, got %s",
+		MessageInvalidConditionalValue:                         "invalid conditional value: %w",
+		MessageInvalidShiftOperation:                           "invalid shift operation: %w",
+		MessageShiftOperandType:                                "shift operands must be integers, got %s",
+		MessageShiftAmount:                                     "shift amount %d is out of range for %s",
+		MessageUnknownPass:                                     "unknown pass %s",
+		MessagePassFailed:                                      "pass %s failed: %w",
+		MessageDuplicateTypeName:                               "type %s is declared more than once",
+		MessageTypeAliasCycle:                                  "type alias %s refers to itself",
+		MessageNestedTypeAlias:                                 "type alias %s must be declared at the top level",
+		MessageNestedImport:                                    "import %s must be declared at the top level",
+		MessageNestedExtern:                                    "extern function %s must be declared at the top level",
+		MessageExternDeclared:                                  "extern function %s is already declared",
+		MessageInlineIR:                                        "invalid inline LLVM IR: %w",
+		MessageInlineIRRedefined:                               "inline LLVM IR redefines %s",
+		MessageImportFile:                                      "cannot import file %s: %w",
+		MessageUnresolvedImport:                                "import %s is not resolved, programs with imports are parsed by ParseProgram",
+		MessageImportCycle:                                     "import cycle: %s",
+		MessageFunctionInstructionBudget:                       "function %s has %d instructions, exceeding the budget of %d",
+		MessageFunctionBlockBudget:                             "function %s has %d basic blocks, exceeding the budget of %d",
+		MessageInstructionBudget:                               "program has %d instructions, exceeding the budget of %d",
+		MessageBlockBudget:                                     "program has %d basic blocks, exceeding the budget of %d",
+		MessageMathType:                                        "%s expects integer or floating point values, got %s",
+		MessageMathFloatType:                                   "%s expects floating point values, got %s",
+		MessageEvaluationIncomplete:                            "cannot evaluate call of %s at compile time: %w",
+		MessageEvaluationSteps:                                 "evaluation stopped after %d steps",
+		MessageFreeType:                                        "cannot free %s value",
+		MessageCallerNotFound:                                  "caller not found in scope: %s",
+		MessageArrayLiteralOverflow:                            "array literal with %d elements overflows %s",
+		MessageComplexityTooHigh:                               "function %s has a cyclomatic complexity of %d, which exceeds %d",
+	},
+	LocaleGerman: {
+		MessageExpectedType:                                    "Typ an Position %d erwartet",
+		MessageExpectedIdentifierAfterSemicolon:                "Bezeichner nach ';' an Position %d erwartet",
+		MessageExpectedSemicolonAfterValue:                     "; nach 'value' an Position %d erwartet",
+		MessageExpectedCloseCurlyAfterFunctionBody:             "'}' nach dem Funktionsrumpf an Position %d erwartet",
+		MessageExpectedOpenCurlyAfterStructName:                "'{' nach dem Strukturnamen an Position %d erwartet",
+		MessageExpectedOpenCurlyAfterFunctionParameters:        "'{' nach den Funktionsparametern an Position %d erwartet",
+		MessageExpectedIntValue:                                "'int' als Wert an Position %d erwartet",
+		MessageUnexpectedIdentifier:                            "unerwarteter Bezeichner '%s' an Position %d",
+		MessageUnexpectedToken:                                 "unerwartetes %s an Position %d",
+		MessageUnexpectedTokenAfterExpression:                  "unerwartetes %s nach dem Ausdruck an Position %d",
+		MessageFeatureDisabled:                                 "das Sprachmerkmal %s ist an Position %d deaktiviert",
+		MessageUnexpectedEndOfInput:                            "unerwartetes Ende der Eingabe, %s in %s erwartet",
+		MessageNestingTooDeep:                                  "Verschachtelung zu tief an Position %d, die maximale Tiefe ist %d",
+		MessageInvalidEdit:                                     "ungültige Änderung des Quelltexts von %s bis %s",
+		MessageReservedWord:                                    "reserviertes Wort '%s' an Position %d als Bezeichner verwendet",
+		MessageInvalidArrayLength:                              "ungültige Array-Länge '%s' an Position %d",
+		MessageReservedPrefix:                                  "Bezeichner '%s' an Position %d verwendet das reservierte Präfix '%s'",
+		MessageExpectedValue:                                   "Wert an Position %d erwartet",
+		MessageExpectedTypeAfterStructField:                    "Typ nach dem Strukturfeld an Position %d erwartet",
+		MessageExpectedTypeAfterFunctionParameter:              "Typ nach dem Funktionsparameter an Position %d erwartet",
+		MessageExpectedTypeAfterAs:                             "Typ nach 'as' an Position %d erwartet",
+		MessageExpectedTypeAfterColon:                          "Typ nach ':' an Position %d erwartet",
+		MessageExpectedIndexAfterIdentifier:                    "Index nach dem Bezeichner an Position %d erwartet",
+		MessageExpectedIdentifierAfterStruct:                   "Bezeichner nach 'struct' an Position %d erwartet",
+		MessageExpectedIdentifierAfterEmbed:                    "Bezeichner nach 'embed' an Position %d erwartet",
+		MessageExpectedFileNameAfterEmbed:                      "Dateiname nach dem embed-Bezeichner an Position %d erwartet",
+		MessageExpectedFileNameAfterImport:                     "Dateiname nach import an Position %d erwartet",
+		MessageExpectedFunctionAfterExtern:                     "function nach extern an Position %d erwartet",
+		MessageExternBody:                                      "Externe Funktion %s kann keinen Rumpf haben, an Position %d",
+		MessageExpectedInlineIRAfterLLVM:                       "Block mit LLVM IR nach llvm an Position %d erwartet",
+		MessageExpectedPackageName:                             "Paketname nach 'package' an Position %d erwartet",
+		MessagePackageNotFirst:                                 "Paketdeklaration muss die Datei beginnen an Position %d",
+		MessageExpectedLetAfterQualifier:                       "let nach %s an Position %d erwartet",
+		MessageDuplicateQualifier:                              "doppelter Qualifizierer %s an Position %d",
+		MessageExpectedMemoryOrdering:                          "Speicherordnung nach atomic( an Position %d erwartet",
+		MessageUnknownMemoryOrdering:                           "unbekannte Speicherordnung %s an Position %d, relaxed, acq_rel oder seq_cst erwartet",
+		MessageExpectedCloseParenthesisAfterMemoryOrdering:     "')' nach der Speicherordnung an Position %d erwartet",
+		MessageExpectedIdentifierAfterLet:                      "Bezeichner nach 'let' an Position %d erwartet",
+		MessageExpectedIdentifierAfterFunction:                 "Bezeichner nach 'function' an Position %d erwartet",
+		MessageExpectedIdentifierAfterFor:                      "Bezeichner nach 'for' an Position %d erwartet",
+		MessageExpectedIdentifierAfterShortVariableAssignment:  "Bezeichner nach ':=' an Position %d erwartet",
+		MessageExpectedFieldName:                               "Feldname an Position %d erwartet",
+		MessageExpectedFieldNameAfterDot:                       "Feldname nach '.' an Position %d erwartet",
+		MessageExpectedArrayLength:                             "Array-Länge nach '[' an Position %d erwartet",
+		MessageExpectedLessThanAfterIdentifier:                 "< nach 'idenfifier' an Position %d erwartet",
+		MessageExpectedShortVariableAssignmentAfterIdentifier:  ":= nach 'identifier' an Position %d erwartet",
+		MessageExpectedCloseCurlyAfterWhileBody:                "'}' nach dem while-Rumpf an Position %d erwartet",
+		MessageExpectedCloseCurlyAfterStructFields:             "'}' nach den Strukturfeldern an Position %d erwartet",
+		MessageUnknownStructAttribute:                          "unbekanntes Struct-Attribut an Position %d, @packed erwartet",
+		MessageExpectedStructAfterAttribute:                    "struct, function oder extern nach dem Attribut an Position %d erwartet",
+		MessageUnknownFunctionAttribute:                        "unbekanntes Funktionsattribut %s an Position %d, @inline, @noinline oder @noreturn erwartet",
+		MessageConflictingFunctionAttributes:                   "Funktion %s kann nicht zugleich @inline und @noinline sein",
+		MessageUnknownFieldAttribute:                           "unbekanntes Feldattribut an Position %d, @align erwartet",
+		MessageExpectedOpenParenthesisAfterAlign:               "'(' nach @align an Position %d erwartet",
+		MessageExpectedAlignment:                               "Ausrichtung an Position %d erwartet",
+		MessageInvalidAlignment:                                "ungültige Ausrichtung %s an Position %d, Zweierpotenz erwartet",
+		MessageExpectedCloseParenthesisAfterAlignment:          "')' nach der Ausrichtung an Position %d erwartet",
+		MessageExpectedGreaterThanAfterElementType:             "'>' nach dem Elementtyp von %s an Position %d erwartet",
+		MessageExpectedColonInConditional:                      "':' im bedingten Ausdruck an Position %d erwartet",
+		MessageExpectedIdentifierAfterTypeKeyword:              "Bezeichner nach 'type' an Position %d erwartet",
+		MessageExpectedEqualsAfterTypeAlias:                    "'=' nach dem Namen des Typalias an Position %d erwartet",
+		MessageUnexpectedGreaterThanAfterType:                  "unerwartetes '>' nach Typ an Position %d",
+		MessageExpectedCloseCurlyAfterFieldValues:              "'}' nach den Feldwerten an Position %d erwartet",
+		MessageExpectedOpenCurlyAfterWhileCondition:            "'{' nach der while-Bedingung an Position %d erwartet",
+		MessageExpectedFor:                                     "'for' am Anfang %d erwartet",
+		MessageExpectedAddSignAfterValue:                       "'add sign' nach dem Wert an Position %d erwartet",
+		MessageExpectedAddSignAfterIdentifier:                  "'add sign' nach 'identifier' an Position %d erwartet",
+		MessageExpectedAddSignAfterAddSign:                     "'add sign' nach 'add sign' an Position %d erwartet",
+		MessageExpectedCloseSquareAfterIndex:                   "']' nach dem Index an Position %d erwartet",
+		MessageExpectedCloseSquareAfterArrayLength:             "']' nach der Array-Länge an Position %d erwartet",
+		MessageExpectedCloseSquareAfterArrayElements:           "']' nach den Array-Elementen an Position %d erwartet",
+		MessageExpectedOpenSquare:                              "'[' an Position %d erwartet",
+		MessageExpectedEqualsAfterLet:                          "'=' nach let an Position %d erwartet",
+		MessageExpectedEqualsAfterIndex:                        "'=' nach dem Index an Position %d erwartet",
+		MessageExpectedEqualsAfterIdentifier:                   "'=' nach dem Bezeichner an Position %d erwartet",
+		MessageExpectedColonAfterFieldName:                     "':' nach dem Feldnamen an Position %d erwartet",
+		MessageExpectedCloseParenthesisAfterWhileCondition:     "')' nach der while-Bedingung an Position %d erwartet",
+		MessageExpectedCloseParenthesisAfterParameters:         "')' nach den Parametern an Position %d erwartet",
+		MessageExpectedCloseParenthesisAfterFunctionParameters: "')' nach den Funktionsparametern an Position %d erwartet",
+		MessageExpectedCloseParenthesisAfterCastValue:          "')' nach dem umzuwandelnden Wert an Position %d erwartet",
+		MessageExpectedOpenParenthesisAfterType:                "'(' nach dem Typ an Position %d erwartet",
+		MessageExpectedOpenParenthesisAfterFunctionName:        "'(' nach dem Funktionsnamen an Position %d erwartet",
+		MessageExpectedOpenParenthesisAfterCaller:              "'(' nach dem Aufrufer an Position %d erwartet",
+		MessageExpectedTypeAfterNew:                            "Typ nach 'new(' an Position %d erwartet",
+		MessageExpectedCloseParenthesisAfterNewType:            "')' nach dem Typ von new an Position %d erwartet",
+		MessageExpectedOpenParenthesisAfterWhile:               "'(' nach 'while' an Position %d erwartet",
+		MessageVoidCallAsValue:                                 "Aufruf einer Funktion ohne Rückgabewert als Wert verwendet",
+		MessageVariableNotFound:                                "Variable nicht im Gültigkeitsbereich gefunden: %s",
+		MessageInvalidArrayElement:                             "ungültiges Array-Element %d: %w",
+		MessageExpectedOneParameter:                            "genau ein Parameter für %s erwartet, %d erhalten",
+		MessageExpectedNoParameters:                            "keine Parameter für %s erwartet, %d erhalten",
+		MessageExpectedTwoParameters:                           "genau zwei Parameter für %s erwartet, %d erhalten",
+		MessageUnknownType:                                     "unbekannter Typ %s",
+		MessageUnknownStruct:                                   "unbekannte Struktur %s",
+		MessageUnknownField:                                    "unbekanntes Feld %s in Struktur %s",
+		MessageFieldType:                                       "Feld %s eines %s-Werts kann nicht ausgewählt werden",
+		MessageUnexpectedReturnValue:                           "unerwarteter Rückgabewert in Funktion %s",
+		MessageInvalidExitStatus:                               "ungültiger Exit-Status: %w",
+		MessageInvalidMain:                                     "Funktion main darf keine Parameter haben und muss i32 oder nichts zurückgeben",
+		MessageDuplicateMain:                                   "Funktion main mehrfach deklariert",
+		MessageLibraryStatement:                                "Anweisungen auf oberster Ebene außer Deklarationen benötigen eine main-Funktion, die für eine Bibliothek nicht erzeugt wird",
+		MessageExpectedCallAfterSpawn:                          "Aufruf nach spawn an Position %d erwartet",
+		MessageInvalidSpawnTarget:                              "spawn erwartet eine vom Programm deklarierte Funktion, %s ist keine",
+		MessageSpawnGarbageCollection:                          "spawn kann nicht mit dem Garbage Collector verwendet werden, der die Stacks von Threads nicht durchsucht",
+		MessageNilFunctionValue:                                "kein Funktionswert für Aufrufer: %s",
+		MessageNilFunctionType:                                 "kein Funktionstyp für Aufrufer: %s",
+		MessageNestedStruct:                                    "verschachtelte Strukturdeklarationen werden nicht unterstützt: %s",
+		MessageNestedEmbed:                                     "verschachtelte embed-Deklarationen werden nicht unterstützt: %s",
+		MessageNestedThreadLocal:                               "threadlokale Variablen müssen auf oberster Ebene deklariert werden: %s",
+		MessageThreadLocalInitializer:                          "Initialisierer der threadlokalen Variable %s muss ein konstanter Ausdruck sein",
+		MessageAtomicType:                                      "atomare Variable %s muss einen Ganzzahl-, Gleitkomma- oder Zeigertyp haben, nicht %s",
+		MessageAtomicOperand:                                   "%s erwartet einen Zeiger auf einen Ganzzahl-, Gleitkomma- oder Zeigerwert, nicht %s",
+		MessageAtomicAddOperand:                                "%s erwartet einen Zeiger auf eine Ganzzahl, nicht %s",
+		MessageNestedFunction:                                  "verschachtelte Funktionsdefinitionen werden nicht unterstützt: %s",
+		MessageMissingReturnValue:                              "fehlender Rückgabewert in Funktion %s",
+		MessageMissingReturn:                                   "fehlendes return am Ende der Funktion %s",
+		MessageInvalidValueType:                                "ungültiger Werttyp: %v",
+		MessageInvalidInitValueType:                            "ungültiger Werttyp für die Initialisierung: %v",
+		MessageInvalidLetValue:                                 "ungültiger Wert für let %s: %w",
+		MessageInvalidFieldValue:                               "ungültiger Wert für Feld %s der Struktur %s: %w",
+		MessageInvalidAssignmentValue:                          "ungültiger Wert für die Zuweisung an %s: %w",
+		MessageInvalidArrayElementValue:                        "ungültiger Wert für das Array-Element: %w",
+		MessageInvalidFieldAssignmentValue:                     "ungültiger Wert für Feld %s: %w",
+		MessageInvalidDereferenceAssignmentValue:               "ungültiger Wert für den dereferenzierten Zeiger: %w",
+		MessageInvalidParameterType:                            "ungültiger Typ des Parameters %s der Funktion %s: %w",
+		MessageInvalidFieldType:                                "ungültiger Typ des Feldes %s in Struktur %s: %w",
+		MessageInvalidLetType:                                  "ungültiger Typ für let %s: %w",
+		MessageInvalidNewType:                                  "ungültiger Typ für new: %w",
+		MessageInvalidReturnValue:                              "ungültiger Rückgabewert in Funktion %s: %w",
+		MessageInvalidReturnType:                               "ungültiger Rückgabetyp der Funktion %s: %w",
+		MessageRecursiveStruct:                                 "ungültige rekursive Struktur %s",
+		MessageInvalidCallerParameter:                          "ungültiger Parameter %d des Aufrufers %s: %w",
+		MessageInvalidBuiltinParameter:                         "ungültiger Parameter %d von %s: %w",
+		MessageInvalidAddOperandType:                           "ungültiger Operandentyp %s für die Addition",
+		MessageInvalidLiteral:                                  "ungültiges Literal: %v",
+		MessageInvalidCast:                                     "ungültige Umwandlung von %s nach %s",
+		MessageInvalidArrayIndex:                               "ungültiger Array-Index: %w",
+		MessageInvalidArrayIndexType:                           "ungültiger Array-Index vom Typ %s",
+		MessageInvalidAddOperation:                             "ungültige Addition: %w",
+		MessageInvalidBuiltinValue:                             "ungültiger %s-Wert für %s",
+		MessageIndexOutOfBounds:                                "Index %d außerhalb der Grenzen von %s",
+		MessageExpectedSliceAndValues:                          "ein Slice und mindestens ein Wert für %s erwartet, %d Parameter erhalten",
+		MessageExpectedParameters:                              "%d Parameter für Aufrufer %s erwartet, %d erhalten",
+		MessageExpectedTypeParameter:                           "Typparameter an Position %d erwartet",
+		MessageExpectedCloseAngleAfterTypeParameters:           "'>' nach Typparametern an Position %d erwartet",
+		MessageDuplicateTypeParameter:                          "Typparameter %s der Funktion %s mehrfach deklariert",
+		MessageUninferredTypeParameter:                         "Typparameter %s der Funktion %s kann nicht aus ihren Parametern abgeleitet werden",
+		MessageGenericParameterMismatch:                        "Parameter %d der Funktion %s erwartet %s, %s erhalten",
+		MessageGenericInstantiation:                            "in Instanziierung %s: %w",
+		MessageGenericExtern:                                   "externe Funktion %s kann keine Typparameter haben",
+		MessageDuplicateField:                                  "doppeltes Feld %s in Struktur %s",
+		MessageDuplicateFieldValue:                             "doppeltes Feld %s im Literal der Struktur %s",
+		MessageDuplicateStruct:                                 "doppelte Deklaration der Struktur %s",
+		MessageDuplicateEmbed:                                  "doppelte Deklaration von embed %s",
+		MessageEmbedFile:                                       "Datei %s kann nicht eingebettet werden: %w",
+		MessageConstantOverflow:                                "Konstante %d läuft in %s über",
+		MessageArrayLiteralType:                                "Array-Literal kann nicht als %s-Wert verwendet werden",
+		MessageFloatLiteralType:                                "%v kann nicht als %s-Wert verwendet werden",
+		MessageBoolLiteralType:                                 "%t kann nicht als %s-Wert verwendet werden",
+		MessageValueType:                                       "%s-Wert kann nicht als %s-Wert verwendet werden",
+		MessageIntegerLiteralType:                              "%d kann nicht als %s-Wert verwendet werden",
+		MessagePrintType:                                       "%s-Wert kann nicht ausgegeben werden",
+		MessageComparisonType:                                  "%s-Werte können nicht mit %s verglichen werden",
+		MessageJSONDataType:                                    "ungültiger Datentyp %s in JSON",
+		MessageJSONValue:                                       "Wert %v vom Typ %T kann nicht als JSON kodiert werden",
+		MessageJSONNode:                                        "ungültiger Knoten in JSON: %s",
+		MessageInvalidNodeNil:                                  "%s hat kein %s",
+		MessageInvalidNodeName:                                 "%s hat einen leeren %s",
+		MessageInvalidNodeType:                                 "%s hat den ungültigen Datentyp %s",
+		MessageInvalidNodeOperator:                             "%s hat den unbekannten Operator %v",
+		MessageInvalidNodeKind:                                 "unbekannter Knoten %T in %s",
+		MessageDidYouMean:                                      "%w, meinten Sie %s?",
+		MessageDuplicateFunction:                               "doppelte Deklaration der Funktion %s",
+		MessageDuplicateVariable:                               "doppelte Deklaration der Variable %s",
+		MessageDuplicateParameter:                              "doppelter Parameter %s der Funktion %s",
+		MessageFirstDeclaredAt:                                 "%w, zuerst deklariert bei %s",
+		MessageUnusedVariable:                                  "Variable %s wird nie gelesen",
+		MessageUnusedParameter:                                 "Parameter %s der Funktion %s wird nie gelesen",
+		MessageUnreachableAfterReturn:                          "unerreichbarer Code nach dem return bei %s",
+		MessageUnreachableAfterExit:                            "unerreichbarer Code nach dem Aufruf von exit bei %s",
+		MessageShadowedParameter:                               "Parameter %s der Funktion %s verdeckt die globale Deklaration bei %s",
+		MessageShadowedVariable:                                "Variable %s verdeckt die Deklaration bei %s",
+		MessageFormatType:                                      "%s-Wert kann nicht formatiert werden",
+		MessageExpectedFormatString:                            "Formatzeichenkette als erster Parameter von %s erwartet",
+		MessageEmptyArrayLiteral:                               "der Typ eines leeren Array-Literals kann nicht abgeleitet werden",
+		MessageIndexType:                                       "%s-Wert kann nicht indiziert werden",
+		MessageDereferenceType:                                 "%s-Wert kann nicht dereferenziert werden",
+		MessageAssignToArgument:                                "Zuweisung an das Argument %s nicht möglich",
+		MessageAssignToArrayValue:                              "Zuweisung an ein Element eines Arrays, das keine Variable ist, nicht möglich",
+		MessageAssignToStructValue:                             "Zuweisung an ein Feld einer Struktur, die keine Variable ist, nicht möglich",
+		MessageAddressOfValue:                                  "die Adresse eines Werts, der keine Variable ist, kann nicht genommen werden",
+		MessageAppendType:                                      "an %s-Wert kann nicht angehängt werden",
+		MessageUntypedNone:                                     "none benötigt einen Optionstyp, z. B. let x: option<i32> = none",
+		MessageInvalidOptionValue:                              "ungültiger Optionswert: %w",
+		MessageUnwrapType:                                      "%s-Wert kann nicht entpackt werden, Option oder Ergebnis erwartet",
+		MessageInvalidUnwrapFallback:                           "ungültiger Ersatzwert: %w",
+		MessageUntypedErr:                                      "err benötigt einen Ergebnistyp, z. B. let x: result<i32> = err(1)",
+		MessageInvalidResultValue:                              "ungültiger Ergebniswert: %w",
+		MessageInvalidErrorCode:                                "ungültiger Fehlercode: %w",
+		MessageTryType:                                         "try kann nicht auf einen %s-Wert angewendet werden, Ergebnis erwartet",
+		MessageTryOutsideFunction:                              "try außerhalb einer Funktion",
+		MessageTryReturnType:                                   "try kann nicht in Funktion %s mit Rückgabetyp %s verwendet werden, Ergebnistyp erwartet",
+		MessageConditionType:                                   "Bedingung muss ein bool-Wert sein, nicht %s",
+		MessageInvalidConditionalValue:                         "ungültiger Wert des bedingten Ausdrucks: %w",
+		MessageInvalidShiftOperation:                           "ungültige Verschiebeoperation: %w",
+		MessageShiftOperandType:                                "Operanden einer Verschiebung müssen Ganzzahlen sein, nicht %s",
+		MessageShiftAmount:                                     "Verschiebung um %d liegt außerhalb des Bereichs von %s",
+		MessageUnknownPass:                                     "unbekannter Durchlauf %s",
+		MessagePassFailed:                                      "Durchlauf %s fehlgeschlagen: %w",
+		MessageDuplicateTypeName:                               "Typ %s ist mehrfach deklariert",
+		MessageTypeAliasCycle:                                  "Typalias %s verweist auf sich selbst",
+		MessageNestedTypeAlias:                                 "Typalias %s muss auf oberster Ebene deklariert werden",
+		MessageNestedImport:                                    "Import %s muss auf oberster Ebene deklariert werden",
+		MessageNestedExtern:                                    "Externe Funktion %s muss auf oberster Ebene deklariert werden",
+		MessageExternDeclared:                                  "Externe Funktion %s ist bereits deklariert",
+		MessageInlineIR:                                        "ungültiges eingebettetes LLVM IR: %w",
+		MessageInlineIRRedefined:                               "eingebettetes LLVM IR definiert %s neu",
+		MessageImportFile:                                      "Datei %s kann nicht importiert werden: %w",
+		MessageUnresolvedImport:                                "Import %s ist nicht aufgelöst, Programme mit Importen werden mit ParseProgram geparst",
+		MessageImportCycle:                                     "Importzyklus: %s",
+		MessageFunctionInstructionBudget:                       "Funktion %s hat %d Anweisungen und überschreitet das Budget von %d",
+		MessageFunctionBlockBudget:                             "Funktion %s hat %d Basisblöcke und überschreitet das Budget von %d",
+		MessageInstructionBudget:                               "Programm hat %d Anweisungen und überschreitet das Budget von %d",
+		MessageBlockBudget:                                     "Programm hat %d Basisblöcke und überschreitet das Budget von %d",
+		MessageMathType:                                        "%s erwartet Ganzzahl- oder Gleitkommawerte, erhielt %s",
+		MessageMathFloatType:                                   "%s erwartet Gleitkommawerte, erhielt %s",
+		MessageEvaluationIncomplete:                            "Aufruf von %s kann nicht zur Übersetzungszeit ausgewertet werden: %w",
+		MessageEvaluationSteps:                                 "Auswertung nach %d Schritten abgebrochen",
+		MessageFreeType:                                        "%s-Wert kann nicht freigegeben werden",
+		MessageCallerNotFound:                                  "Aufrufer nicht im Gültigkeitsbereich gefunden: %s",
+		MessageArrayLiteralOverflow:                            "Array-Literal mit %d Elementen läuft in %s über",
+		MessageComplexityTooHigh:                               "Funktion %s hat eine zyklomatische Komplexität von %d, die %d überschreitet",
+	},
+}
@@ -0,0 +1,157 @@
+package lang
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"tinygo.org/x/go-llvm"
+)
+
+// identifierNamesMetadata is the name of the module metadata mapping minified identifiers
+// back to their original names.
+const identifierNamesMetadata = "gusty.identifiers"
+
+// Minify renames every declared function, variable and parameter of the program which isn't
+// exported to a short name, e.g. a, b, ..., z, aa, ab. Exported identifiers start with an upper
+// case letter and keep their names, as do builtins and struct and field names. Names are
+// assigned deterministically in declaration order, and every occurrence of a name is renamed
+// the same way, so shadowing is preserved.
+//
+// The nodes are renamed in place. Minify returns a map from every short name to the original
+// name it replaces.
+func Minify(nodes []Node) map[string]string {
+	m := minifier{renamed: make(map[string]string), kept: make(map[string]bool)}
+	for word := range keywords {
+		m.kept[string(word)] = true
+	}
+	for _, word := range append(ReservedWords, mainIdentifier, printfIndentifier, printIdentifier, printlnIdentifier, formatIdentifier, readIntIdentifier, readLineIdentifier, argsCountIdentifier, argIdentifier, exitIdentifier, joinIdentifier, atomicAddIdentifier, atomicLoadIdentifier, atomicStoreIdentifier, mutexIdentifier, lockIdentifier, unlockIdentifier, lenIdentifier, capIdentifier, appendIdentifier, newIdentifier, freeIdentifier, someIdentifier, unwrapOrIdentifier, optionIdentifier, okIdentifier, errIdentifier, resultIdentifier) {
+		m.kept[word] = true
+	}
+
+	// Collect the declared names first, so that short names never clash with names which are kept
+	m.walkNodes(nodes, m.declare)
+	m.walkNodes(nodes, m.keep)
+	for _, name := range m.declared {
+		m.renamed[name] = m.nextName()
+	}
+	m.walkNodes(nodes, m.rename)
+
+	originals := make(map[string]string, len(m.renamed))
+	for original, short := range m.renamed {
+		originals[short] = original
+	}
+	return originals
+}
+
+// minifier holds the state of a Minify run.
+type minifier struct {
+	declared []string          // The declared names to rename, in declaration order.
+	renamed  map[string]string // The short name of every declared name.
+	kept     map[string]bool   // The names which aren't renamed and can't be used as short names.
+	next     int               // The number of short names generated so far.
+}
+
+// isExported reports whether the name starts with an upper case letter.
+func isExported(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+// declare records a declared name which is to be renamed. Like every visitor, it is called with
+// every identifier and whether the identifier is declared at this occurrence.
+func (m *minifier) declare(name *string, declaration bool) {
+	if !declaration || m.kept[*name] || isExported(*name) || strings.HasPrefix(*name, runtimePrefix) {
+		return
+	}
+	if _, ok := m.renamed[*name]; !ok {
+		m.renamed[*name] = ""
+		m.declared = append(m.declared, *name)
+	}
+}
+
+// keep records a name which isn't declared, e.g. the name of an undeclared identifier.
+func (m *minifier) keep(name *string, declaration bool) {
+	if _, ok := m.renamed[*name]; !ok {
+		m.kept[*name] = true
+	}
+}
+
+// rename replaces a declared name with its short name.
+func (m *minifier) rename(name *string, declaration bool) {
+	if short, ok := m.renamed[*name]; ok {
+		*name = short
+	}
+}
+
+// nextName returns the next short name which doesn't clash with a kept name.
+func (m *minifier) nextName() string {
+	for {
+		n := m.next
+		m.next++
+
+		var name []byte
+		for {
+			name = append([]byte{byte('a' + n%26)}, name...)
+			n = n/26 - 1
+			if n < 0 {
+				break
+			}
+		}
+		if !m.kept[string(name)] {
+			return string(name)
+		}
+	}
+}
+
+// addIdentifierNames adds the map from minified to original identifiers to the module as
+// named metadata, so tools can map the symbols of the module back to the source code.
+// Every operand is a pair of the short and the original name, sorted by short name.
+func addIdentifierNames(module llvm.Module, originals map[string]string) {
+	shorts := make([]string, 0, len(originals))
+	for short := range originals {
+		shorts = append(shorts, short)
+	}
+	sort.Strings(shorts)
+
+	context := module.Context()
+	for _, short := range shorts {
+		pair := context.MDNode([]llvm.Metadata{context.MDString(short), context.MDString(originals[short])})
+		module.AddNamedMetadataOperand(identifierNamesMetadata, pair)
+	}
+}
+
+// walkNodes calls visit with a pointer to every identifier in the nodes, in the order Walk visits
+// them. The parameters of extern functions aren't visited, since they only name the types.
+func (m *minifier) walkNodes(nodes []Node, visit func(name *string, declaration bool)) {
+	WalkNodes(nodes, func(node Node) bool {
+		switch n := node.(type) {
+		case *IdentifierNode:
+			visit(&n.Name, false)
+		case *FunctionNode:
+			visit(&n.Name, true)
+		case *ExternNode:
+			return false
+		case *Parameter:
+			visit(&n.Identifier, true)
+		case *EmbedNode:
+			visit(&n.Identifier, true)
+		case *LetNode:
+			visit(&n.Identifier, true)
+		case *AssignmentNode:
+			visit(&n.Identifier, false)
+		case *CallerNode:
+			visit(&n.FunctionName, false)
+		case *ShortVariableAssigmentNode:
+			visit(&n.Identifier, true)
+		case *ConditionNode:
+			visit(&n.LeftValue, false)
+		case *PostNode:
+			visit(&n.Identifier, false)
+		case *WhileNode:
+			visit(&n.Condition, false)
+		}
+		return true
+	})
+}
@@ -0,0 +1,57 @@
+package lang
+
+// mainPackage is the name of the package of programs, whose functions aren't qualified.
+const mainPackage = "main"
+
+// packageFile holds the top-level nodes of a file of a program and the name of its package.
+// Files without a package header belong to the main package.
+type packageFile struct {
+	Package string
+	Nodes   []Node
+}
+
+// splitPackage returns the name of the package of the file with the given top-level nodes and the
+// nodes without the package header.
+func splitPackage(nodes []Node) (string, []Node) {
+	if len(nodes) > 0 {
+		if packageNode, ok := nodes[0].(*PackageNode); ok {
+			return packageNode.Name, nodes[1:]
+		}
+	}
+	return mainPackage, nodes
+}
+
+// qualifyPackages partitions the functions of a program by package. Every function declared by
+// the files of a package other than main is renamed to its qualified name, e.g. math.abs, which is
+// also its symbol in the module, so functions of different packages don't collide. The files of the
+// package keep calling the function by its name, which is qualified as well, while other packages
+// call it by its qualified name. Struct and type names aren't partitioned.
+func qualifyPackages(files []packageFile) {
+	functions := make(map[string]map[string]bool)
+	for _, file := range files {
+		if file.Package == mainPackage {
+			continue
+		}
+		if functions[file.Package] == nil {
+			functions[file.Package] = make(map[string]bool)
+		}
+		for _, node := range file.Nodes {
+			if functionNode, ok := node.(*FunctionNode); ok {
+				functions[file.Package][functionNode.Name] = true
+			}
+		}
+	}
+
+	var m minifier
+	for _, file := range files {
+		declared := functions[file.Package]
+		if len(declared) == 0 {
+			continue
+		}
+		m.walkNodes(file.Nodes, func(name *string, declaration bool) {
+			if declared[*name] {
+				*name = file.Package + string(TokenDot) + *name
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package lang
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParseError is a syntax error found while parsing. Besides the message, which it wraps, it tells
+// where the error is, what the parser expected there and what it got, so callers can inspect the
+// error with errors.As instead of matching its message.
+type ParseError struct {
+	Filename string // The file the error is in, if the tokens were read from a file by ParseFile.
+	Pos      Pos    // The position of the token the error is at, or the end of the input.
+	Expected string // What the parser expected, e.g. "'('" or "type".
+	Got      string // The token the parser got instead, e.g. "identifier x" or "end of input".
+	Context  string // The construct the parser was parsing, e.g. "call" or "let statement".
+	Err      error  // The message of the error.
+}
+
+// Error returns the message of the error. An error in a file is prefixed with the name of the file
+// and the position, e.g. main.gusty:3:7: expected ')' after parameters at position 12.
+func (e *ParseError) Error() string {
+	if e.Filename != "" {
+		return fmt.Sprintf("%s:%s: %v", e.Filename, e.Pos, e.Err)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the message of the error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseErrorDetail is what the parser expected and the construct it was parsing when it reported
+// a message.
+type parseErrorDetail struct {
+	Expected string
+	Context  string
+}
+
+// parseErrorDetails are the details of the messages reported for syntax errors.
+var parseErrorDetails = map[MessageID]parseErrorDetail{
+	MessageUnexpectedIdentifier:                            {"statement", "statement"},
+	MessageUnexpectedToken:                                 {"statement", "statement"},
+	MessageUnexpectedTokenAfterExpression:                  {"end of expression", "expression"},
+	MessageFeatureDisabled:                                 {"enabled language feature", "statement"},
+	MessageUnexpectedEndOfInput:                            {"token", "statement"},
+	MessageNestingTooDeep:                                  {"shallower nesting", "nested body, value or type"},
+	MessageDuplicateQualifier:                              {"let", "qualified let statement"},
+	MessageExpectedAddSignAfterAddSign:                     {"'+'", "for loop"},
+	MessageExpectedAddSignAfterIdentifier:                  {"'+'", "for loop"},
+	MessageExpectedAddSignAfterValue:                       {"'+'", "add operation"},
+	MessageExpectedAlignment:                               {"alignment", "field attribute"},
+	MessageExpectedArrayLength:                             {"array length", "type"},
+	MessageExpectedCallAfterSpawn:                          {"call", "spawn"},
+	MessageExpectedCloseAngleAfterTypeParameters:           {"'>'", "function declaration"},
+	MessageExpectedCloseCurlyAfterFieldValues:              {"'}'", "struct literal"},
+	MessageExpectedCloseCurlyAfterFunctionBody:             {"'}'", "function body"},
+	MessageExpectedCloseCurlyAfterStructFields:             {"'}'", "struct declaration"},
+	MessageExpectedCloseCurlyAfterWhileBody:                {"'}'", "while loop"},
+	MessageExpectedCloseParenthesisAfterAlignment:          {"')'", "field attribute"},
+	MessageExpectedCloseParenthesisAfterCastValue:          {"')'", "cast"},
+	MessageExpectedCloseParenthesisAfterFunctionParameters: {"')'", "function declaration"},
+	MessageExpectedCloseParenthesisAfterMemoryOrdering:     {"')'", "qualified let statement"},
+	MessageExpectedCloseParenthesisAfterNewType:            {"')'", "new"},
+	MessageExpectedCloseParenthesisAfterParameters:         {"')'", "call"},
+	MessageExpectedCloseParenthesisAfterWhileCondition:     {"')'", "while loop"},
+	MessageExpectedCloseSquareAfterArrayElements:           {"']'", "array literal"},
+	MessageExpectedCloseSquareAfterArrayLength:             {"']'", "type"},
+	MessageExpectedCloseSquareAfterIndex:                   {"']'", "index"},
+	MessageExpectedColonAfterFieldName:                     {"':'", "struct literal"},
+	MessageExpectedColonInConditional:                      {"':'", "conditional expression"},
+	MessageExpectedEqualsAfterIdentifier:                   {"'='", "assignment"},
+	MessageExpectedEqualsAfterIndex:                        {"'='", "assignment"},
+	MessageExpectedEqualsAfterLet:                          {"'='", "let statement"},
+	MessageExpectedEqualsAfterTypeAlias:                    {"'='", "type alias"},
+	MessageExpectedFieldName:                               {"field name", "struct literal"},
+	MessageExpectedFieldNameAfterDot:                       {"field name", "field selection"},
+	MessageExpectedFileNameAfterEmbed:                      {"file name", "embed"},
+	MessageExpectedFileNameAfterImport:                     {"file name", "import"},
+	MessageExpectedFor:                                     {"for", "for loop"},
+	MessageExpectedFunctionAfterExtern:                     {"function", "extern function"},
+	MessageExpectedGreaterThanAfterElementType:             {"'>'", "type"},
+	MessageExpectedIdentifierAfterEmbed:                    {"identifier", "embed"},
+	MessageExpectedIdentifierAfterFor:                      {"identifier", "for loop"},
+	MessageExpectedIdentifierAfterFunction:                 {"identifier", "function declaration"},
+	MessageExpectedIdentifierAfterLet:                      {"identifier", "let statement"},
+	MessageExpectedIdentifierAfterSemicolon:                {"identifier", "for loop"},
+	MessageExpectedIdentifierAfterShortVariableAssignment:  {"identifier", "for loop"},
+	MessageExpectedIdentifierAfterStruct:                   {"identifier", "struct declaration"},
+	MessageExpectedIdentifierAfterTypeKeyword:              {"identifier", "type alias"},
+	MessageExpectedIndexAfterIdentifier:                    {"index", "assignment"},
+	MessageExpectedInlineIRAfterLLVM:                       {"block of LLVM IR", "inline IR"},
+	MessageExpectedIntValue:                                {"int", "for loop"},
+	MessageExpectedLessThanAfterIdentifier:                 {"'<'", "for loop"},
+	MessageExpectedLetAfterQualifier:                       {"let", "qualified let statement"},
+	MessageExpectedMemoryOrdering:                          {"memory ordering", "qualified let statement"},
+	MessageExpectedOpenCurlyAfterFunctionParameters:        {"'{'", "function declaration"},
+	MessageExpectedOpenCurlyAfterStructName:                {"'{'", "struct"},
+	MessageExpectedOpenCurlyAfterWhileCondition:            {"'{'", "while loop"},
+	MessageExpectedOpenParenthesisAfterAlign:               {"'('", "field attribute"},
+	MessageExpectedOpenParenthesisAfterCaller:              {"'('", "call"},
+	MessageExpectedOpenParenthesisAfterFunctionName:        {"'('", "function declaration"},
+	MessageExpectedOpenParenthesisAfterType:                {"'('", "cast"},
+	MessageExpectedOpenParenthesisAfterWhile:               {"'('", "while loop"},
+	MessageExpectedOpenSquare:                              {"'['", "array literal"},
+	MessageExpectedPackageName:                             {"package name", "package declaration"},
+	MessageExpectedSemicolonAfterValue:                     {"';'", "for loop"},
+	MessageExpectedShortVariableAssignmentAfterIdentifier:  {"':='", "for loop"},
+	MessageExpectedStructAfterAttribute:                    {"struct, function or extern", "attribute"},
+	MessageExpectedType:                                    {"type", "type"},
+	MessageExpectedTypeAfterAs:                             {"type", "cast"},
+	MessageExpectedTypeAfterColon:                          {"type", "let statement"},
+	MessageExpectedTypeAfterFunctionParameter:              {"type", "function declaration"},
+	MessageExpectedTypeAfterNew:                            {"type", "new"},
+	MessageExpectedTypeAfterStructField:                    {"type", "struct declaration"},
+	MessageExpectedTypeParameter:                           {"type parameter", "function declaration"},
+	MessageExpectedValue:                                   {"value", "expression"},
+	MessageExternBody:                                      {"end of extern function", "extern function"},
+	MessageInvalidAlignment:                                {"power of two", "field attribute"},
+	MessageInvalidArrayLength:                              {"array length", "type"},
+	MessagePackageNotFirst:                                 {"package declaration first", "package declaration"},
+	MessageReservedPrefix:                                  {"identifier", "declaration"},
+	MessageReservedWord:                                    {"identifier", "declaration"},
+	MessageUnexpectedGreaterThanAfterType:                  {"type", "type"},
+	MessageUnknownFieldAttribute:                           {"@align", "field attribute"},
+	MessageUnknownFunctionAttribute:                        {"@inline, @noinline or @noreturn", "function attribute"},
+	MessageUnknownMemoryOrdering:                           {"relaxed, acq_rel or seq_cst", "qualified let statement"},
+	MessageUnknownStructAttribute:                          {"@packed", "struct attribute"},
+}
+
+// newParseError returns the syntax error for the message with the given ID and arguments, which is
+// at the token at the given index. If the index is past the last token, the error reports the
+// unexpected end of the input instead of the message.
+func newParseError(tokens []Token, index int, id MessageID, args ...any) error {
+	detail := parseErrorDetails[id]
+	parseError := &ParseError{Expected: detail.Expected, Context: detail.Context, Err: newError(id, args...)}
+	if index >= 0 && index < len(tokens) {
+		parseError.Pos = tokens[index].Pos
+		parseError.Got = describeToken(tokens[index])
+		return parseError
+	}
+
+	parseError.Pos = Pos{Line: 1, Column: 1}
+	if len(tokens) > 0 {
+		parseError.Pos = tokens[len(tokens)-1].End
+	}
+	parseError.Got = "end of input"
+	parseError.Err = newError(MessageUnexpectedEndOfInput, detail.Expected, detail.Context)
+	return parseError
+}
+
+// describeToken returns the description of the token a syntax error reports it got, e.g.
+// identifier x, string literal "a" or '('.
+func describeToken(token Token) string {
+	switch token.Type {
+	case TokenIdentifierType:
+		return "identifier " + token.Value
+	case TokenStringType:
+		return "string literal " + strconv.Quote(token.Value)
+	case TokenInlineIRType:
+		return "block of LLVM IR"
+	}
+	if spelling, ok := tokenSpelling(token.Type); ok {
+		return fmt.Sprintf("'%s'", spelling)
+	}
+	return token.String()
+}
+
+// tokenSpelling returns how a token of the type is spelled, if it is a keyword or a symbol.
+func tokenSpelling(tokenType TokenType) (string, bool) {
+	switch tokenType {
+	case TokenShortVariableAssignmentType:
+		return string(TokenShortVariableAssignment), true
+	case TokenShiftLeftType:
+		return string(TokenShiftLeft), true
+	case TokenShiftRightType:
+		return string(TokenShiftRight), true
+	case TokenDotType:
+		return string(TokenDot), true
+	}
+	for keyword, t := range keywords {
+		if t == tokenType {
+			return string(keyword), true
+		}
+	}
+	for r, t := range runeTokens {
+		if t == tokenType {
+			return string(r), true
+		}
+	}
+	for r, t := range comparisonTokens {
+		if t == tokenType {
+			return string(r) + string(TokenEquals), true
+		}
+	}
+	return "", false
+}
@@ -1,28 +1,107 @@
 package lang
 
 import (
-	"fmt"
 	"strconv"
-)
-
-// dataType represents the underlying data type of a value.
-type dataType int
-
-// Constants for different data types.
-const (
-	// Integer32Type represents the 32-bit integer data type.
-	Integer32Type dataType = iota
+	"strings"
 )
 
 // Node is an interface representing nodes in the abstract syntax tree.
 type Node interface {
 	IsNode()
+	// Pos returns the position of the first rune of the node in the source code.
+	Pos() Pos
+	// End returns the position following the last rune of the node in the source code.
+	End() Pos
+}
+
+// Span is the part of the source code a node was parsed from, which every node embeds. Nodes
+// created after parsing, e.g. by constant folding, have an unknown span.
+type Span struct {
+	From Pos
+	To   Pos
+}
+
+// Pos returns the position of the first rune of the span.
+func (s Span) Pos() Pos {
+	return s.From
+}
+
+// End returns the position following the last rune of the span.
+func (s Span) End() Pos {
+	return s.To
+}
+
+// spanned is implemented by the nodes, which embed a Span, to set their span while parsing.
+type spanned interface {
+	Node
+	setSpan(from, to Pos)
+}
+
+// setSpan sets the span to the positions.
+func (s *Span) setSpan(from, to Pos) {
+	s.From, s.To = from, to
+}
+
+// setTokenSpan sets the span of the node to the tokens from start up to end, excluding end, unless
+// the node is nil, has a span already or there are no such tokens.
+func setTokenSpan(value any, tokens []Token, start, end int) {
+	node, ok := value.(spanned)
+	if !ok || node.Pos().IsValid() || start < 0 || end > len(tokens) || start >= end {
+		return
+	}
+	node.setSpan(tokens[start].Pos, tokens[end-1].End)
 }
 
 // LetNode represents a let statement.
+// example: let x = 5, let x: i64 = 5, let x = a + b or let x: [4]i32 = [1, 2, 3, 4]
 type LetNode struct {
-	Identifier string
-	Value      any
+	Span
+	Identifier  string
+	Type        dataType
+	HasType     bool // HasType reports whether Type was declared explicitly rather than taken from a literal.
+	Value       Expr
+	ThreadLocal bool           // ThreadLocal reports whether every thread has its own copy of the global variable.
+	Volatile    bool           // Volatile reports whether every load and store of the variable is volatile.
+	Ordering    MemoryOrdering // Ordering is the ordering of the atomic loads and stores of the variable, if any.
+}
+
+// MemoryOrdering is the ordering of the atomic loads and stores of a variable.
+type MemoryOrdering int
+
+// Constants for the memory orderings of atomic variables.
+const (
+	// NotAtomic is the ordering of variables which aren't accessed atomically.
+	NotAtomic MemoryOrdering = iota
+	// OrderingRelaxed only guarantees that every load and store is atomic.
+	OrderingRelaxed
+	// OrderingAcquireRelease makes loads acquire and stores release the variable.
+	OrderingAcquireRelease
+	// OrderingSequentiallyConsistent additionally orders all sequentially consistent accesses
+	// of all variables. It is the default ordering of atomic variables.
+	OrderingSequentiallyConsistent
+)
+
+// Constants for the names of the attributes of struct declarations and fields.
+const (
+	packedAttribute = "packed"
+	alignAttribute  = "align"
+)
+
+// Constants for the names of the attributes of function definitions and extern declarations.
+const (
+	inlineAttribute   = "inline"   // The function is always inlined.
+	noinlineAttribute = "noinline" // The function is never inlined.
+	noreturnAttribute = "noreturn" // The function never returns, e.g. because it exits the program.
+)
+
+// functionAttributes holds the names of the attributes of function definitions and extern declarations.
+var functionAttributes = map[string]bool{inlineAttribute: true, noinlineAttribute: true, noreturnAttribute: true}
+
+// memoryOrderings maps the names of memory orderings to their values.
+var memoryOrderings = map[string]MemoryOrdering{
+	"relaxed": OrderingRelaxed,
+	"acq_rel": OrderingAcquireRelease,
+	"seq_cst": OrderingSequentiallyConsistent,
 }
 
 // IsNode is an empty method to satisfy the Node interface.
@@ -30,18 +109,19 @@ func (n *LetNode) IsNode() {}
 
 // AddOperationNode represents a add statement.
 type AddOperationNode struct {
-	LeftValue  any
-	RightValue any
+	Span
+	LeftValue  Expr
+	RightValue Expr
 }
 
 // IsNode is an empty method to satisfy the Node interface.
 func (n *AddOperationNode) IsNode() {}
 
-// Parameter represents a parameter in a function or function call.
+// Parameter represents a parameter in a function declaration.
 type Parameter struct {
+	Span
 	Identifier string
 	Type       dataType
-	Value      any
 }
 
 // IsNode is an empty method to satisfy the Node interface.
@@ -49,6 +129,7 @@ func (n *Parameter) IsNode() {}
 
 // WhileNode represents a while loop.
 type WhileNode struct {
+	Span
 	Condition string
 	Body      []Node
 }
@@ -57,10 +138,17 @@ type WhileNode struct {
 func (n *WhileNode) IsNode() {}
 
 // FunctionNode represents a function definition.
+// example: function add(a i32, b i32) i32 { return a + b }
 type FunctionNode struct {
+	Span
 	Name       string
 	Parameters []*Parameter
+	ReturnType dataType
 	Body       []Node
+	Attributes []string // Attributes holds the names of the attributes the function was declared with, e.g. inline.
+	// TypeParameters holds the names of the type parameters of a generic function, e.g. T, which
+	// its parameter types, return type and body use like struct types.
+	TypeParameters []string
 }
 
 // IsNode is an empty method to satisfy the Node interface.
@@ -68,18 +156,375 @@ func (n *FunctionNode) IsNode() {}
 
 // CallerNode represents a function call.
 type CallerNode struct {
-	FunctionName         string
-	Parameters           []*Parameter
-	isParameterOperation bool
-	AddOperationNode     *AddOperationNode
+	Span
+	FunctionName string
+	Args         []Expr
 }
 
 // IsNode is an empty method to satisfy the Node interface.
 func (n *CallerNode) IsNode() {}
 
+// ReturnNode represents a return statement. Value is nil if no value is returned.
+type ReturnNode struct {
+	Span
+	Value Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *ReturnNode) IsNode() {}
+
+// CastNode represents an explicit type conversion of a value.
+// example: i64(x) or x as i64
+type CastNode struct {
+	Span
+	Type  dataType
+	Value Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *CastNode) IsNode() {}
+
+// ArrayLiteralNode represents an array literal. An array literal may have fewer
+// elements than its array type, the remaining elements are zero.
+// example: [1, 2, 3, 4]
+type ArrayLiteralNode struct {
+	Span
+	Elements []Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *ArrayLiteralNode) IsNode() {}
+
+// NewNode represents the allocation of a zeroed value of the given type on the heap, which
+// results in a pointer to the value. The value lives until it is released with free.
+// example: new(Point)
+type NewNode struct {
+	Span
+	Type dataType
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *NewNode) IsNode() {}
+
+// StringLiteralNode represents a string literal, which is a value of the string data type.
+// example: let greeting = "hello"
+type StringLiteralNode struct {
+	Span
+	Value string
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *StringLiteralNode) IsNode() {}
+
+// ShiftOperationNode represents shifting an integer value by a number of bits, to the left or
+// arithmetically to the right. Shifts bind tighter than additions.
+// example: let flags = 1 << 4, let half = n >> 1
+type ShiftOperationNode struct {
+	Span
+	LeftValue  Expr
+	RightValue Expr
+	Left       bool // Whether the value is shifted to the left rather than to the right.
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *ShiftOperationNode) IsNode() {}
+
+// ComparisonNode represents comparing two values of the same data type, which results in a bool.
+// Integers and floats are compared by value, bools only for equality and strings by their bytes.
+// Comparisons bind looser than additions and can't be chained.
+// example: let same = name == "gusty", let small = n < 10
+type ComparisonNode struct {
+	Span
+	LeftValue  Expr
+	RightValue Expr
+	Operator   ComparisonOperator
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *ComparisonNode) IsNode() {}
+
+// ComparisonOperator is the operator of a comparison.
+type ComparisonOperator int
+
+// Constants for the operators of comparisons.
+const (
+	OperatorEqual ComparisonOperator = iota
+	OperatorNotEqual
+	OperatorLess
+	OperatorLessEqual
+	OperatorGreater
+	OperatorGreaterEqual
+)
+
+// comparisonOperators maps the tokens of comparison operators to the operators.
+var comparisonOperators = map[TokenType]ComparisonOperator{
+	TokenEqualEqualType:   OperatorEqual,
+	TokenNotEqualType:     OperatorNotEqual,
+	TokenLessThanType:     OperatorLess,
+	TokenLessEqualType:    OperatorLessEqual,
+	TokenGreaterThanType:  OperatorGreater,
+	TokenGreaterEqualType: OperatorGreaterEqual,
+}
+
+// String returns the spelling of the comparison operator.
+func (o ComparisonOperator) String() string {
+	switch o {
+	case OperatorEqual:
+		return string(TokenEqualEqual)
+	case OperatorNotEqual:
+		return string(TokenNotEqual)
+	case OperatorLess:
+		return string(TokenLessThan)
+	case OperatorLessEqual:
+		return string(TokenLessEqual)
+	case OperatorGreater:
+		return string(TokenGreaterThan)
+	}
+	return string(TokenGreaterEqual)
+}
+
+// ConditionalNode represents a conditional expression, which results in the true value if the
+// condition is true and in the false value otherwise. Only the selected value is evaluated.
+// example: let max = a < b ? b : a, let sign = negative ? -1 : 1
+type ConditionalNode struct {
+	Span
+	Condition Expr
+	True      Expr
+	False     Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *ConditionalNode) IsNode() {}
+
+// TryNode represents taking the value of a result. If the result holds an error, the current
+// function returns the error instead.
+// example: let n = try parse(x)
+type TryNode struct {
+	Span
+	Value Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *TryNode) IsNode() {}
+
+// SpawnNode represents calling a function on a new native thread, which results in the handle of
+// the thread as i64. The caller continues without waiting for the function, join waits for it.
+// example: let worker = spawn count(10)
+type SpawnNode struct {
+	Span
+	Call *CallerNode
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *SpawnNode) IsNode() {}
+
+// NoneNode represents an option without value. Its option type is taken from where it is used.
+// example: let x: option<i32> = none
+type NoneNode struct {
+	Span
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *NoneNode) IsNode() {}
+
+// AddressNode represents taking the address of a variable, an array element or a struct field.
+// example: &x or &p.x
+type AddressNode struct {
+	Span
+	Value Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *AddressNode) IsNode() {}
+
+// DereferenceNode represents the value a pointer refers to.
+// example: *p
+type DereferenceNode struct {
+	Span
+	Value Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *DereferenceNode) IsNode() {}
+
+// DereferenceAssignmentNode represents the assignment of a value to the value a pointer refers to.
+// example: *p = 5
+type DereferenceAssignmentNode struct {
+	Span
+	Target *DereferenceNode
+	Value  Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *DereferenceAssignmentNode) IsNode() {}
+
+// IndexNode represents the access of an array or slice element.
+// example: a[i] or a[i][j]
+type IndexNode struct {
+	Span
+	Value Expr
+	Index Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *IndexNode) IsNode() {}
+
+// FieldNode represents the access of a struct field.
+// example: p.x or l.from.x
+type FieldNode struct {
+	Span
+	Value Expr
+	Field string
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *FieldNode) IsNode() {}
+
+// FieldAssignmentNode represents the assignment of a value to a struct field.
+// example: p.x = 3
+type FieldAssignmentNode struct {
+	Span
+	Target *FieldNode
+	Value  Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *FieldAssignmentNode) IsNode() {}
+
+// IndexAssignmentNode represents the assignment of a value to an array or slice element.
+// example: a[i] = 5
+type IndexAssignmentNode struct {
+	Span
+	Target *IndexNode
+	Value  Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *IndexAssignmentNode) IsNode() {}
+
+// AssignmentNode represents the assignment of a value to an existing variable.
+// example: s = append(s, 4)
+type AssignmentNode struct {
+	Span
+	Identifier string
+	Value      Expr
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *AssignmentNode) IsNode() {}
+
+// Field represents a field of a struct declaration.
+type Field struct {
+	Identifier string
+	Type       dataType
+	Alignment  int // Alignment is the alignment in bytes declared with @align, or 0 for the natural alignment.
+}
+
+// StructNode represents a struct declaration.
+// example: struct Point { x i32 y i32 } or @packed struct Header { tag i8, size i32 @align(2) }
+type StructNode struct {
+	Span
+	Name   string
+	Fields []*Field
+	Packed bool // Packed reports whether the struct was declared @packed, i.e. without padding between fields.
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *StructNode) IsNode() {}
+
+// ExternNode represents the declaration of a function which is defined outside of the program,
+// e.g. by the C library or another library the program is linked with. It has no body.
+// example: extern function puts(s string) i32
+type ExternNode struct {
+	Span
+	Name       string
+	Parameters []*Parameter
+	ReturnType dataType
+	Attributes []string // Attributes holds the names of the attributes the function was declared with, e.g. noreturn.
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *ExternNode) IsNode() {}
+
+// InlineIRNode represents a block of LLVM IR which is spliced into the module as it is. At the
+// top level the block holds declarations and definitions of the module, e.g. functions, in a
+// function body it holds instructions, see generateInlineIR.
+// example: llvm { declare i32 @puts(ptr) }
+type InlineIRNode struct {
+	Span
+	IR string
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *InlineIRNode) IsNode() {}
+
+// TypeAliasNode represents the declaration of a type alias, another name for a data type. The
+// alias and its data type are the same type, Parse replaces every use of the alias with it.
+// example: type Index = i32
+type TypeAliasNode struct {
+	Span
+	Name string
+	Type dataType
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *TypeAliasNode) IsNode() {}
+
+// EmbedNode represents the declaration of a file whose bytes are embedded into the module.
+// The identifier refers to a constant [N]i8 array holding the N bytes of the file.
+// example: embed greeting "greeting.txt"
+type EmbedNode struct {
+	Span
+	Identifier string
+	Path       string
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *EmbedNode) IsNode() {}
+
+// ImportNode represents the import of a gusty source file, whose declarations become part of
+// the program. The path is relative to the importing file, see ParseProgram.
+// example: import "lib.gusty"
+type ImportNode struct {
+	Span
+	Path string
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *ImportNode) IsNode() {}
+
+// PackageNode represents the package header of a file. The functions the files of a package
+// declare are called with the name of the package from other packages, see qualifyPackages.
+// example: package math
+type PackageNode struct {
+	Span
+	Name string
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *PackageNode) IsNode() {}
+
+// FieldValue represents the value of a field in a struct literal.
+type FieldValue struct {
+	Identifier string
+	Value      Expr
+}
+
+// StructLiteralNode represents a struct literal. Fields without value are zero.
+// example: Point{x: 1, y: 2}
+type StructLiteralNode struct {
+	Span
+	Name   string
+	Fields []*FieldValue
+}
+
+// IsNode is an empty method to satisfy the Node interface.
+func (n *StructLiteralNode) IsNode() {}
+
 // ForNode represents a for definition.
 // example: for i := 0; i < 10; i++ {}
 type ForNode struct {
+	Span
 	Init      ShortVariableAssigmentNode
 	Condition ConditionNode
 	Post      PostNode
@@ -91,27 +536,29 @@ func (n *ForNode) IsNode() {}
 
 // ShortVariableAssigmentNode represents a short variable assignment statement.
 type ShortVariableAssigmentNode struct {
+	Span
 	Identifier string
-	Value      any
+	Value      Expr
 }
 
 // IsNode is an empty method to satisfy the Node interface.
 func (n *ShortVariableAssigmentNode) IsNode() {}
 
-// ConditionNode represents a condition of for node
+// ConditionNode represents a condition of for node. The operator is OperatorLess, the only
+// operator of a for loop condition.
 type ConditionNode struct {
+	Span
 	LeftValue  string
-	Operator   any
-	RightValue any
+	Operator   ComparisonOperator
+	RightValue Expr
 }
 
-type LessThanOperator struct{}
-
 // IsNode is an empty method to satisfy the Node interface.
 func (n *ConditionNode) IsNode() {}
 
 // PostNode represents a post statement of for node
 type PostNode struct {
+	Span
 	Identifier string
 	Increment  bool
 }
@@ -120,330 +567,1637 @@ type PostNode struct {
 func (n *PostNode) IsNode() {}
 
 // Parse takes a slice of tokens as input and returns a slice of nodes
-// representing the abstract syntax tree.
+// representing the abstract syntax tree. Type aliases are resolved to their data types and the
+// functions of a package are qualified with its name.
+// Import declarations are kept, programs importing files are parsed by ParseProgram.
+// A syntax error doesn't stop the parsing, all syntax errors found are returned as SyntaxErrors.
+// The parsing can be configured with a Parser.
 func Parse(tokens []Token) ([]Node, error) {
-	nodes, _, err := parseNodes(tokens, 0, -1)
-	return nodes, err
+	return (&Parser{}).Parse(tokens)
 }
 
 // parseNodes takes a slice of tokens, an index, and a token type as input parameters,
 // and returns a slice of nodes, an updated index, and an error if there is any issue
 // during parsing. It processes tokens to generate nodes representing the abstract syntax tree.
-func parseNodes(tokens []Token, index int, tokenType TokenType) ([]Node, int, error) {
+func (s *parserState) parseNodes(tokens []Token, index int, tokenType TokenType) ([]Node, int, error) {
+	err := s.enter(tokens, index)
+	defer s.leave()
+	if err != nil {
+		return nil, -1, err
+	}
+
 	nodes := []Node{}
+	var syntaxErrors SyntaxErrors
 
-	for index < len(tokens) {
+	for index < len(tokens) && !s.stopped() && !s.resumes(index) {
 		token := tokens[index]
+		start, count := index, len(nodes)
 
 		switch token.Type {
 		case TokenIdentifierType:
-			if IsOpenParenthesisToken(index+1, tokens) {
-				callerNode, newIndex, err := parseCaller(tokens, index)
+			if IsOpenParenthesisToken(index+1, tokens) || IsQualifiedCallerToken(index, tokens) {
+				callerNode, newIndex, err := s.parseCaller(tokens, index)
 				if err != nil {
-					return nil, -1, err
+					index = s.recover(&syntaxErrors, err, tokens, start)
+					continue
 				}
 				index = newIndex
 				nodes = append(nodes, callerNode)
+			} else if !IsNotOpenSquareBracketToken(index+1, tokens) || !IsNotDotToken(index+1, tokens) {
+				elementAssignmentNode, newIndex, err := s.parseElementAssignment(tokens, index)
+				if err != nil {
+					index = s.recover(&syntaxErrors, err, tokens, start)
+					continue
+				}
+				index = newIndex
+				nodes = append(nodes, elementAssignmentNode)
+			} else if !IsNotEqualToken(index+1, tokens) {
+				assignmentNode, newIndex, err := s.parseAssignment(tokens, index)
+				if err != nil {
+					index = s.recover(&syntaxErrors, err, tokens, start)
+					continue
+				}
+				index = newIndex
+				nodes = append(nodes, assignmentNode)
 			} else if IsAddToken(index+1, tokens) {
-				addOperationNode, newIndex, err := parseAddOperation(tokens, index)
+				addOperationNode, newIndex, err := s.parseAddOperation(tokens, index)
 				if err != nil {
-					return nil, -1, err
+					index = s.recover(&syntaxErrors, err, tokens, start)
+					continue
 				}
 				index = newIndex
 				nodes = append(nodes, addOperationNode)
+			} else {
+				index = s.recover(&syntaxErrors, newParseError(tokens, index, MessageUnexpectedIdentifier, token.Value, index), tokens, start)
+				continue
+			}
+		case TokenStarType:
+			dereferenceAssignmentNode, newIndex, err := s.parseElementAssignment(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
 			}
+			index = newIndex
+			nodes = append(nodes, dereferenceAssignmentNode)
 		case TokenCloseCurlyBracketType:
-			if tokenType == TokenFunctionType {
-				return nodes, index, nil
-			} else if tokenType == TokenForType {
-				return nodes, index, nil
+			if tokenType == TokenFunctionType || tokenType == TokenForType {
+				return nodes, index, syntaxErrors.err()
+			}
+			if s.Strict {
+				index = s.recover(&syntaxErrors, newParseError(tokens, index, MessageUnexpectedToken, describeToken(token), index), tokens, start)
+				continue
 			}
 			index++
 		case TokenLetType:
-			letNode, newIndex, err := parseLet(tokens, index)
+			letNode, newIndex, err := s.parseLet(tokens, index)
 			if err != nil {
-				return nil, -1, err
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
+			}
+			index = newIndex
+			nodes = append(nodes, letNode)
+		case TokenThreadLocalType, TokenVolatileType, TokenAtomicType:
+			letNode, newIndex, err := s.parseQualifiedLet(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
 			}
 			index = newIndex
 			nodes = append(nodes, letNode)
 		case TokenWhileType:
-			whileNode, newIndex, err := parseWhile(tokens, index)
+			whileNode, newIndex, err := s.parseWhile(tokens, index)
 			if err != nil {
-				return nil, -1, err
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
 			}
 			index = newIndex
 			nodes = append(nodes, whileNode)
 		case TokenFunctionType:
-			functionNode, newIndex, err := parseFunction(tokens, index)
+			functionNode, newIndex, err := s.parseFunction(tokens, index)
 			if err != nil {
-				return nil, -1, err
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
 			}
 			index = newIndex
 			nodes = append(nodes, functionNode)
+		case TokenStructType:
+			structNode, newIndex, err := s.parseStruct(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
+			}
+			index = newIndex
+			nodes = append(nodes, structNode)
+		case TokenAtType:
+			declarationNode, newIndex, err := s.parseAttributedDeclaration(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
+			}
+			index = newIndex
+			nodes = append(nodes, declarationNode)
+		case TokenTypeKeywordType:
+			typeAliasNode, newIndex, err := s.parseTypeAlias(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
+			}
+			index = newIndex
+			nodes = append(nodes, typeAliasNode)
+		case TokenPackageType:
+			packageNode, newIndex, err := parsePackage(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
+			}
+			index = newIndex
+			nodes = append(nodes, packageNode)
+		case TokenLLVMType:
+			inlineIRNode, newIndex, err := parseInlineIR(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
+			}
+			index = newIndex
+			nodes = append(nodes, inlineIRNode)
+		case TokenExternType:
+			externNode, newIndex, err := s.parseExtern(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
+			}
+			index = newIndex
+			nodes = append(nodes, externNode)
+		case TokenImportType:
+			importNode, newIndex, err := parseImport(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
+			}
+			index = newIndex
+			nodes = append(nodes, importNode)
+		case TokenEmbedType:
+			embedNode, newIndex, err := parseEmbed(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
+			}
+			index = newIndex
+			nodes = append(nodes, embedNode)
 		case TokenForType:
-			forNode, newIndex, err := parseFor(tokens, index)
+			forNode, newIndex, err := s.parseFor(tokens, index)
 			if err != nil {
-				return nil, -1, err
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
 			}
 			index = newIndex
 			nodes = append(nodes, forNode)
+		case TokenSpawnType:
+			spawnNode, newIndex, err := s.parseSpawn(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
+			}
+			index = newIndex
+			nodes = append(nodes, spawnNode)
+		case TokenReturnType:
+			returnNode, newIndex, err := s.parseReturn(tokens, index)
+			if err != nil {
+				index = s.recover(&syntaxErrors, err, tokens, start)
+				continue
+			}
+			index = newIndex
+			nodes = append(nodes, returnNode)
 		default:
+			if s.Strict {
+				index = s.recover(&syntaxErrors, newParseError(tokens, index, MessageUnexpectedToken, describeToken(token), index), tokens, start)
+				continue
+			}
 			index++
 		}
+
+		// The statement spans the tokens it was parsed from
+		if len(nodes) > count {
+			setTokenSpan(nodes[len(nodes)-1], tokens, start, index)
+			if feature, disabled := s.feature(nodes[len(nodes)-1]); disabled {
+				nodes = nodes[:count]
+				s.addError(&syntaxErrors, newParseError(tokens, start, MessageFeatureDisabled, feature, start))
+			}
+		}
+	}
+
+	return nodes, index, syntaxErrors.err()
+}
+
+// SyntaxErrors is the list of the syntax errors found while parsing, in the order of the source
+// code. The parser recovers from a syntax error by skipping the tokens up to the next statement,
+// so every statement reports at most one error and the statements following it are still parsed.
+type SyntaxErrors []error
+
+// Error returns the messages of the syntax errors, one per line.
+func (e SyntaxErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the syntax errors.
+func (e SyntaxErrors) Unwrap() []error {
+	return e
+}
+
+// err returns the syntax errors as an error, or nil if there are none.
+func (e SyntaxErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// skipStatement returns the index of the token starting the statement following the statement
+// starting at the index: the next keyword starting a statement which isn't nested in the curly
+// brackets of a body, or the close curly bracket ending the body the statement is in. It is the
+// number of tokens if the statement is the last one.
+func skipStatement(tokens []Token, index int) int {
+	depth := 0
+	for index++; index < len(tokens); index++ {
+		switch tokens[index].Type {
+		case TokenOpenCurlyBracketType:
+			depth++
+		case TokenCloseCurlyBracketType:
+			if depth == 0 {
+				return index
+			}
+			depth--
+		default:
+			if _, ok := statementTokens[tokens[index].Type]; ok && depth == 0 {
+				return index
+			}
+		}
+	}
+	return index
+}
+
+// statementTokens are the types of the keywords which start a statement.
+var statementTokens = map[TokenType]struct{}{
+	TokenLetType:         {},
+	TokenThreadLocalType: {},
+	TokenVolatileType:    {},
+	TokenAtomicType:      {},
+	TokenWhileType:       {},
+	TokenFunctionType:    {},
+	TokenStructType:      {},
+	TokenAtType:          {},
+	TokenTypeKeywordType: {},
+	TokenPackageType:     {},
+	TokenLLVMType:        {},
+	TokenExternType:      {},
+	TokenImportType:      {},
+	TokenEmbedType:       {},
+	TokenForType:         {},
+	TokenReturnType:      {},
+}
+
+// parseFunction takes a slice of tokens and an index as input parameters and
+// returns a slice of nodes, an updated index, and an error if there is any issue
+// during parsing. It processes tokens to generate a FunctionNode with its parameters
+// and body.
+func (s *parserState) parseFunction(tokens []Token, index int) (Node, int, error) {
+	functionNode, index, err := s.parseFunctionSignature(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	// Ensure the next token is an open curly brace '{'
+	if IsNotOpenCurlyBracketToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedOpenCurlyAfterFunctionParameters, index)
+	}
+	index++
+
+	// Parse the function body
+	body, newIndex, err := s.parseNodes(tokens[index:], 0, TokenFunctionType)
+	if err != nil {
+		return nil, -1, err
+	}
+	index += newIndex
+
+	// Ensure the next token is a close curly brace '}'
+	if IsNotCloseCurlyBracketToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedCloseCurlyAfterFunctionBody, index)
+	}
+	index++
+
+	functionNode.Body = body
+	return functionNode, index, nil
+}
+
+// parseExtern takes a slice of tokens and an index as input parameters and
+// returns an ExternNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "extern function puts(s string) i32".
+func (s *parserState) parseExtern(tokens []Token, index int) (*ExternNode, int, error) {
+	// Ensure the next token is the 'function' keyword
+	index++
+	if IsNotFunctionToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedFunctionAfterExtern, index)
+	}
+
+	functionNode, index, err := s.parseFunctionSignature(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+	if len(functionNode.TypeParameters) > 0 {
+		return nil, -1, newError(MessageGenericExtern, functionNode.Name)
+	}
+	if !IsNotOpenCurlyBracketToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExternBody, functionNode.Name, index)
+	}
+	return &ExternNode{Name: functionNode.Name, Parameters: functionNode.Parameters, ReturnType: functionNode.ReturnType}, index, nil
+}
+
+// parseInlineIR takes a slice of tokens and an index as input parameters and
+// returns an InlineIRNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "llvm { ... }", whose block the
+// tokenizer keeps as a single token.
+func parseInlineIR(tokens []Token, index int) (*InlineIRNode, int, error) {
+	// Ensure the next token is the block of LLVM IR
+	index++
+	if index >= len(tokens) || tokens[index].Type != TokenInlineIRType {
+		return nil, -1, newParseError(tokens, index, MessageExpectedInlineIRAfterLLVM, index)
+	}
+	ir := tokens[index].Value
+	index++
+
+	return &InlineIRNode{IR: ir}, index, nil
+}
+
+// parseFunctionSignature takes a slice of tokens and the index of a 'function' keyword as input
+// parameters and returns a FunctionNode without a body, an updated index, and an error if there is
+// any issue during parsing. It processes the name, the parameters and the optional return type of
+// a function definition or an extern declaration.
+func (s *parserState) parseFunctionSignature(tokens []Token, index int) (*FunctionNode, int, error) {
+	// Ensure there is a token following the 'function' keyword
+	index++
+	if IsNotIdentifierToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedIdentifierAfterFunction, index)
+	}
+	if err := checkDeclaredIdentifier(tokens, index); err != nil {
+		return nil, -1, err
+	}
+	name := tokens[index].Value
+	index++
+
+	// Parse the optional type parameters of a generic function, e.g. <T, U>
+	var typeParameters []string
+	if !IsNotLessThanToken(index, tokens) {
+		index++
+		for {
+			if IsNotIdentifierToken(index, tokens) {
+				return nil, -1, newParseError(tokens, index, MessageExpectedTypeParameter, index)
+			}
+			if err := checkDeclaredIdentifier(tokens, index); err != nil {
+				return nil, -1, err
+			}
+			for _, typeParameter := range typeParameters {
+				if typeParameter == tokens[index].Value {
+					return nil, -1, newError(MessageDuplicateTypeParameter, typeParameter, name)
+				}
+			}
+			typeParameters = append(typeParameters, tokens[index].Value)
+			index++
+			if !IsCommaToken(index, tokens) {
+				break
+			}
+			index++
+		}
+		if IsNotGreaterThanToken(index, tokens) {
+			return nil, -1, newParseError(tokens, index, MessageExpectedCloseAngleAfterTypeParameters, index)
+		}
+		index++
+	}
+
+	// Ensure the next token is an open bracket '('
+	if IsNotOpenParenthesisToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedOpenParenthesisAfterFunctionName, index)
+	}
+	index++
+
+	// Initialize parameters slice and parse function parameters
+	var parameters []*Parameter
+	for {
+		if IsIdentifierToken(index, tokens) {
+			if err := checkDeclaredIdentifier(tokens, index); err != nil {
+				return nil, -1, err
+			}
+			var p = &Parameter{Identifier: tokens[index].Value}
+			start := index
+
+			index++
+			if IsNotTypeStartToken(index, tokens) {
+				return nil, -1, newParseError(tokens, index, MessageExpectedTypeAfterFunctionParameter, index)
+			}
+			parameterType, newIndex, err := s.parseType(tokens, index)
+			if err != nil {
+				return nil, -1, err
+			}
+			p.Type = parameterType
+			setTokenSpan(p, tokens, start, newIndex)
+			parameters = append(parameters, p)
+			index = newIndex
+
+			// Skip the comma separating this parameter from the next one
+			if IsCommaToken(index, tokens) {
+				index++
+			}
+		} else {
+			break
+		}
+	}
+
+	// Ensure the next token is a close bracket ')'
+	if IsNotCloseParenthesisToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedCloseParenthesisAfterFunctionParameters, index)
+	}
+	index++
+
+	// Parse the optional return type. An extern declaration has no body, so an identifier following
+	// it may start the next statement, e.g. a call, rather than the name of a struct type.
+	returnType := VoidType
+	if !IsNotTypeStartToken(index, tokens) && !IsStatementIdentifierToken(index, tokens) {
+		parsedType, newIndex, err := s.parseType(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		returnType = parsedType
+		index = newIndex
+	}
+
+	return &FunctionNode{Name: name, Parameters: parameters, ReturnType: returnType, TypeParameters: typeParameters}, index, nil
+}
+
+// parseWhile takes a slice of tokens and an index as input parameters and
+// returns a WhileNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens to generate a WhileNode with its
+// condition and body.
+func (s *parserState) parseWhile(tokens []Token, index int) (*WhileNode, int, error) {
+	// Ensure the next token is an open bracket '('
+	index++
+	if IsNotOpenParenthesisToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedOpenParenthesisAfterWhile, index)
+	}
+	condition := tokens[index+2].Value
+	index += 2
+
+	// Ensure the next token is a close bracket ')'
+	if IsNotCloseParenthesisToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedCloseParenthesisAfterWhileCondition, index)
+	}
+	index++
+
+	// Ensure the next token is an open curly brace '{'
+	if IsNotOpenCurlyBracketToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedOpenCurlyAfterWhileCondition, index)
+	}
+	index++
+
+	// Parse the while loop body
+	body, newIndex, err := s.parseNodes(tokens[index:], 0, -1)
+	if err != nil {
+		return nil, -1, err
+	}
+	index += newIndex
+
+	// Ensure the next token is a close curly brace '}'
+	if IsNotCloseCurlyBracketToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedCloseCurlyAfterWhileBody, index)
+	}
+	index++
+
+	// Create a WhileNode with the parsed condition and body
+	whileNode := &WhileNode{Condition: condition, Body: body}
+	return whileNode, index, nil
+}
+
+// parseLet takes a slice of tokens and an index as input parameters and
+// returns a LetNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens to generate a LetNode with its
+// identifier and value.
+func (s *parserState) parseLet(tokens []Token, index int) (*LetNode, int, error) {
+	// Ensure the next token is an identifier
+	index++
+	if IsNotIdentifierToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedIdentifierAfterLet, index)
+	}
+	if err := checkDeclaredIdentifier(tokens, index); err != nil {
+		return nil, -1, err
+	}
+	name := tokens[index].Value
+	index++
+
+	// Parse the optional type declaration after a colon ':'
+	var letType dataType
+	var hasType bool
+	if IsColonToken(index, tokens) {
+		index++
+		if IsNotTypeStartToken(index, tokens) {
+			return nil, -1, newParseError(tokens, index, MessageExpectedTypeAfterColon, index)
+		}
+		parsedType, newIndex, err := s.parseType(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		letType = parsedType
+		hasType = true
+		index = newIndex
+	}
+
+	// Ensure the next token is an equals sign '='
+	if IsNotEqualToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedEqualsAfterLet, index)
+	}
+	index++
+
+	// Parse the value after the equals sign
+	value, newIndex, err := s.parseValue(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+	index = newIndex
+
+	// Without a type declaration a literal value gives the variable its default type,
+	// the type of any other value is inferred during code generation
+	if literalType, ok := literalDataType(value); ok && !hasType {
+		letType = literalType
+	}
+
+	// Create a LetNode with the parsed identifier, type and value
+	letNode := &LetNode{
+		Identifier: name,
+		Type:       letType,
+		HasType:    hasType,
+		Value:      value,
+	}
+
+	return letNode, index, nil
+}
+
+// parseCaller takes a slice of tokens and an index as input parameters and
+// returns a CallerNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens to generate a CallerNode with its
+// function name and arguments.
+func (s *parserState) parseCaller(tokens []Token, index int) (*CallerNode, int, error) {
+	// Retrieve the function name from the current token
+	if err := checkReservedWord(tokens, index); err != nil {
+		return nil, -1, err
+	}
+	name := tokens[index].Value
+
+	// A qualified caller names the package of the function, e.g. math.abs
+	if IsQualifiedCallerToken(index, tokens) {
+		index += 2
+		name += string(TokenDot) + tokens[index].Value
+	}
+
+	// Ensure the next token is an open bracket '('
+	index++
+	if IsNotOpenParenthesisToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedOpenParenthesisAfterCaller, index)
+	}
+	index++
+
+	// Parse the arguments, each of which may be any value
+	var args []Expr
+	for index < len(tokens) && IsNotCloseParenthesisToken(index, tokens) {
+		value, newIndex, err := s.parseValue(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		args = append(args, value)
+		index = newIndex
+
+		// Skip the comma separating this argument from the next one
+		if IsCommaToken(index, tokens) {
+			index++
+		}
+	}
+
+	// Ensure the next token is a close bracket ')'
+	if IsNotCloseParenthesisToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedCloseParenthesisAfterParameters, index)
+	}
+	index++
+
+	// Create a CallerNode with the parsed function name and arguments
+	callerNode := &CallerNode{FunctionName: name, Args: args}
+
+	return callerNode, index, nil
+}
+
+// parseValue takes a slice of tokens and an index as input parameters and
+// returns a value, an updated index, and an error if there is any issue during
+// parsing. A value is a sum, optionally compared with another sum, e.g. "n + 1 < limit",
+// optionally followed by "? a : b" to select one of two values, e.g. "done ? 0 : n + 1".
+func (s *parserState) parseValue(tokens []Token, index int) (Expr, int, error) {
+	err := s.enter(tokens, index)
+	defer s.leave()
+	if err != nil {
+		return nil, -1, err
+	}
+
+	start := index
+	value, index, err := s.parseSum(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	// Compare the sum with the sum following a comparison operator
+	if IsComparisonToken(index, tokens) {
+		operator := comparisonOperators[tokens[index].Type]
+		rightValue, newIndex, err := s.parseSum(tokens, index+1)
+		if err != nil {
+			return nil, -1, err
+		}
+		index = newIndex
+		value = &ComparisonNode{LeftValue: value, RightValue: rightValue, Operator: operator}
+		setTokenSpan(value, tokens, start, index)
+	}
+
+	// Parse the values of a conditional expression, the false value may be another conditional expression
+	if IsQuestionMarkToken(index, tokens) {
+		trueValue, newIndex, err := s.parseValue(tokens, index+1)
+		if err != nil {
+			return nil, -1, err
+		}
+		index = newIndex
+
+		// Ensure the next token is a colon ':'
+		if !IsColonToken(index, tokens) {
+			return nil, -1, newParseError(tokens, index, MessageExpectedColonInConditional, index)
+		}
+		falseValue, newIndex, err := s.parseValue(tokens, index+1)
+		if err != nil {
+			return nil, -1, err
+		}
+		index = newIndex
+		value = &ConditionalNode{Condition: value, True: trueValue, False: falseValue}
+		setTokenSpan(value, tokens, start, index)
+	}
+
+	return value, index, nil
+}
+
+// parseSum takes a slice of tokens and an index as input parameters and
+// returns a sum, an updated index, and an error if there is any issue during
+// parsing. A sum is an operand or a chain of operands separated by add signs,
+// e.g. "a + b + 1", which becomes nested AddOperationNodes.
+func (s *parserState) parseSum(tokens []Token, index int) (Expr, int, error) {
+	start := index
+	value, index, err := s.parseShiftOperand(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	// Combine the operands from left to right for every add sign
+	for index < len(tokens) && !IsNotAddToken(index, tokens) {
+		index++
+		rightValue, newIndex, err := s.parseShiftOperand(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		index = newIndex
+		value = &AddOperationNode{LeftValue: value, RightValue: rightValue}
+		setTokenSpan(value, tokens, start, index)
+	}
+
+	return value, index, nil
+}
+
+// parseShiftOperand takes a slice of tokens and an index as input parameters and
+// returns an operand of an addition, an updated index, and an error if there is any issue
+// during parsing. It is an operand or a chain of operands separated by shift operators,
+// e.g. "1 << n >> 2", which becomes nested ShiftOperationNodes.
+func (s *parserState) parseShiftOperand(tokens []Token, index int) (Expr, int, error) {
+	start := index
+	value, index, err := s.parseOperand(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	// Combine the operands from left to right for every shift operator
+	for IsShiftToken(index, tokens) {
+		left := tokens[index].Type == TokenShiftLeftType
+		rightValue, newIndex, err := s.parseOperand(tokens, index+1)
+		if err != nil {
+			return nil, -1, err
+		}
+		index = newIndex
+		value = &ShiftOperationNode{LeftValue: value, RightValue: rightValue, Left: left}
+		setTokenSpan(value, tokens, start, index)
+	}
+
+	return value, index, nil
+}
+
+// parseOperand takes a slice of tokens and an index as input parameters and
+// returns an operand, an updated index, and an error if there is any issue
+// during parsing. An operand is a literal, an identifier, a function call, a
+// cast, a struct literal or an array literal, optionally followed by any number of indexes and
+// field selections, optionally preceded by any number of '&' and '*' operators and followed
+// by any number of 'as' casts.
+func (s *parserState) parseOperand(tokens []Token, index int) (Expr, int, error) {
+	start := index
+	value, index, err := s.parseUnaryOperand(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	// Wrap the value in a cast for every trailing 'as' keyword, an identifier names a type alias
+	for IsAsToken(index, tokens) {
+		index++
+		switch {
+		case IsTypeToken(index, tokens):
+			value = &CastNode{Type: typeTokens[tokens[index].Type], Value: value}
+		case IsIdentifierToken(index, tokens):
+			value = &CastNode{Type: structOf(tokens[index].Value), Value: value}
+		default:
+			return nil, -1, newParseError(tokens, index, MessageExpectedTypeAfterAs, index)
+		}
+		index++
+		setTokenSpan(value, tokens, start, index)
+	}
+
+	return value, index, nil
+}
+
+// parseUnaryOperand takes a slice of tokens and an index as input parameters and
+// returns an operand without trailing 'as' casts, an updated index, and an error if there
+// is any issue during parsing. An address-of '&' or dereference '*' operator applies to the
+// operand following it, including its indexes and field selections, e.g. &p.x is &(p.x).
+func (s *parserState) parseUnaryOperand(tokens []Token, index int) (Expr, int, error) {
+	start := index
+	if !IsNotAmpersandToken(index, tokens) || !IsNotStarToken(index, tokens) || IsTryToken(index, tokens) {
+		operator := tokens[index].Type
+		err := s.enter(tokens, index)
+		defer s.leave()
+		if err != nil {
+			return nil, -1, err
+		}
+		value, index, err := s.parseUnaryOperand(tokens, index+1)
+		if err != nil {
+			return nil, -1, err
+		}
+		var unaryNode Expr
+		switch operator {
+		case TokenAmpersandType:
+			unaryNode = &AddressNode{Value: value}
+		case TokenTryType:
+			unaryNode = &TryNode{Value: value}
+		default:
+			unaryNode = &DereferenceNode{Value: value}
+		}
+		setTokenSpan(unaryNode, tokens, start, index)
+		return unaryNode, index, nil
+	}
+
+	var value Expr
+	if IsSpawnToken(index, tokens) {
+		spawnNode, newIndex, err := s.parseSpawn(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		value = spawnNode
+		index = newIndex
+	} else if IsNewToken(index, tokens) && !IsNotOpenParenthesisToken(index+1, tokens) {
+		newNode, newIndex, err := s.parseNew(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		value = newNode
+		index = newIndex
+	} else if IsTypeToken(index, tokens) {
+		castNode, newIndex, err := s.parseCast(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		value = castNode
+		index = newIndex
+	} else if !IsNotIdentifierToken(index, tokens) && (!IsNotOpenParenthesisToken(index+1, tokens) || IsQualifiedCallerToken(index, tokens)) {
+		callerNode, newIndex, err := s.parseCaller(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		value = callerNode
+		index = newIndex
+	} else if !IsNotIdentifierToken(index, tokens) && !IsNotOpenCurlyBracketToken(index+1, tokens) {
+		structLiteralNode, newIndex, err := s.parseStructLiteral(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		value = structLiteralNode
+		index = newIndex
+	} else if IsNoneToken(index, tokens) {
+		value = &NoneNode{}
+		index++
+	} else if !IsNotStringToken(index, tokens) {
+		value = &StringLiteralNode{Value: tokens[index].Value}
+		index++
+	} else if !IsNotOpenSquareBracketToken(index, tokens) {
+		arrayLiteralNode, newIndex, err := s.parseArrayLiteral(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		value = arrayLiteralNode
+		index = newIndex
+	} else if IsNotIdentifierToken(index, tokens) && IsNotBoolLiteralToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedValue, index)
+	} else {
+		if err := checkReservedWord(tokens, index); err != nil {
+			return nil, -1, err
+		}
+		value = parseLiteral(tokens[index])
+		index++
+	}
+	setTokenSpan(value, tokens, start, index)
+
+	// Wrap the value in an index for every trailing '[' index ']' and in a field access
+	// for every trailing '.' field
+	for !IsNotOpenSquareBracketToken(index, tokens) || !IsNotDotToken(index, tokens) {
+		if !IsNotDotToken(index, tokens) {
+			index++
+			if IsNotIdentifierToken(index, tokens) {
+				return nil, -1, newParseError(tokens, index, MessageExpectedFieldNameAfterDot, index)
+			}
+			value = &FieldNode{Value: value, Field: tokens[index].Value}
+			index++
+			setTokenSpan(value, tokens, start, index)
+			continue
+		}
+
+		index++
+		indexValue, newIndex, err := s.parseValue(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		index = newIndex
+
+		// Ensure the next token is a close square bracket ']'
+		if IsNotCloseSquareBracketToken(index, tokens) {
+			return nil, -1, newParseError(tokens, index, MessageExpectedCloseSquareAfterIndex, index)
+		}
+		index++
+		value = &IndexNode{Value: value, Index: indexValue}
+		setTokenSpan(value, tokens, start, index)
+	}
+
+	return value, index, nil
+}
+
+// parseType takes a slice of tokens and an index as input parameters and
+// returns a data type, an updated index, and an error if there is any issue
+// during parsing. A type is a type keyword, the name of a struct, an array type of the
+// form "[4]i32" or a slice type of the form "[]i32", whose element type may be any type.
+func (s *parserState) parseType(tokens []Token, index int) (dataType, int, error) {
+	t, index, halfClosed, err := s.parseNestedType(tokens, index)
+	if err != nil {
+		return 0, -1, err
+	}
+	if halfClosed {
+		// Only the first '>' of the '>>' closes the type
+		return 0, -1, newParseError(tokens, index, MessageUnexpectedGreaterThanAfterType, index)
+	}
+	return t, index, nil
+}
+
+// parseNestedType parses a data type like parseType. The '>>' closing two nested element types,
+// e.g. in option<option<i32>>, is a single token, so the inner type reports that it was closed by
+// the first half of the token at the returned index and leaves the second half to the outer type.
+func (s *parserState) parseNestedType(tokens []Token, index int) (dataType, int, bool, error) {
+	err := s.enter(tokens, index)
+	defer s.leave()
+	if err != nil {
+		return 0, -1, false, err
+	}
+
+	if IsTypeToken(index, tokens) {
+		return typeTokens[tokens[index].Type], index + 1, false, nil
+	}
+	if !IsNotStarToken(index, tokens) {
+		element, index, halfClosed, err := s.parseNestedType(tokens, index+1)
+		if err != nil {
+			return 0, -1, false, err
+		}
+		return pointerTo(element), index, halfClosed, nil
+	}
+	if (IsOptionToken(index, tokens) || IsResultToken(index, tokens)) && !IsNotLessThanToken(index+1, tokens) {
+		wrapper := tokens[index].Value
+		element, index, halfClosed, err := s.parseNestedType(tokens, index+2)
+		if err != nil {
+			return 0, -1, false, err
+		}
+		t := optionOf(element)
+		if wrapper == resultIdentifier {
+			t = resultOf(element)
+		}
+		switch {
+		case halfClosed:
+			// The second half of the '>>' closes this type
+			return t, index + 1, false, nil
+		case IsShiftRightToken(index, tokens):
+			return t, index, true, nil
+		case IsNotGreaterThanToken(index, tokens):
+			// Ensure the next token is a greater than sign '>'
+			return 0, -1, false, newParseError(tokens, index, MessageExpectedGreaterThanAfterElementType, wrapper, index)
+		}
+		return t, index + 1, false, nil
+	}
+	return s.parseCompositeType(tokens, index)
+}
+
+// parseCompositeType parses the struct, slice and array types for parseNestedType.
+func (s *parserState) parseCompositeType(tokens []Token, index int) (dataType, int, bool, error) {
+	if !IsNotIdentifierToken(index, tokens) {
+		if err := checkReservedWord(tokens, index); err != nil {
+			return 0, -1, false, err
+		}
+		return structOf(tokens[index].Value), index + 1, false, nil
+	}
+
+	// Ensure the current token is an open square bracket '['
+	if IsNotOpenSquareBracketToken(index, tokens) {
+		return 0, -1, false, newParseError(tokens, index, MessageExpectedType, index)
+	}
+	index++
+
+	// A close square bracket ']' right after the open one starts a slice type
+	if !IsNotCloseSquareBracketToken(index, tokens) {
+		element, index, halfClosed, err := s.parseNestedType(tokens, index+1)
+		if err != nil {
+			return 0, -1, false, err
+		}
+		return sliceOf(element), index, halfClosed, nil
+	}
+
+	// Parse the array length
+	if IsNotIdentifierToken(index, tokens) {
+		return 0, -1, false, newParseError(tokens, index, MessageExpectedArrayLength, index)
+	}
+	length, err := strconv.Atoi(tokens[index].Value)
+	if err != nil || length < 0 {
+		return 0, -1, false, newParseError(tokens, index, MessageInvalidArrayLength, tokens[index].Value, index)
+	}
+	index++
+
+	// Ensure the next token is a close square bracket ']'
+	if IsNotCloseSquareBracketToken(index, tokens) {
+		return 0, -1, false, newParseError(tokens, index, MessageExpectedCloseSquareAfterArrayLength, index)
+	}
+	index++
+
+	// Parse the element type
+	element, index, halfClosed, err := s.parseNestedType(tokens, index)
+	if err != nil {
+		return 0, -1, false, err
+	}
+
+	return arrayOf(element, length), index, halfClosed, nil
+}
+
+// parseQualifiedLet takes a slice of tokens and an index as input parameters and
+// returns a LetNode, an updated index, and an error if there is any issue
+// during parsing. It processes let statements preceded by the qualifiers threadlocal,
+// volatile and atomic in any order, e.g. "threadlocal let seed = 42", "volatile let flag = true"
+// or "atomic(acq_rel) let counter: i64 = 0". An atomic variable without an ordering is
+// sequentially consistent.
+func (s *parserState) parseQualifiedLet(tokens []Token, index int) (*LetNode, int, error) {
+	var threadLocal, volatile bool
+	var ordering MemoryOrdering
+	for IsNotLetToken(index, tokens) {
+		if index >= len(tokens) {
+			return nil, -1, newParseError(tokens, index, MessageExpectedLetAfterQualifier, tokens[index-1].String(), index)
+		}
+		qualifier := tokens[index]
+		switch qualifier.Type {
+		case TokenThreadLocalType:
+			if threadLocal {
+				return nil, -1, newParseError(tokens, index, MessageDuplicateQualifier, qualifier.String(), index)
+			}
+			threadLocal = true
+			index++
+		case TokenVolatileType:
+			if volatile {
+				return nil, -1, newParseError(tokens, index, MessageDuplicateQualifier, qualifier.String(), index)
+			}
+			volatile = true
+			index++
+		case TokenAtomicType:
+			if ordering != NotAtomic {
+				return nil, -1, newParseError(tokens, index, MessageDuplicateQualifier, qualifier.String(), index)
+			}
+			ordering = OrderingSequentiallyConsistent
+			index++
+
+			// Parse the optional memory ordering in parentheses
+			if IsNotOpenParenthesisToken(index, tokens) {
+				continue
+			}
+			index++
+			if IsNotIdentifierToken(index, tokens) {
+				return nil, -1, newParseError(tokens, index, MessageExpectedMemoryOrdering, index)
+			}
+			o, ok := memoryOrderings[tokens[index].Value]
+			if !ok {
+				return nil, -1, newParseError(tokens, index, MessageUnknownMemoryOrdering, tokens[index].Value, index)
+			}
+			ordering = o
+			index++
+			if IsNotCloseParenthesisToken(index, tokens) {
+				return nil, -1, newParseError(tokens, index, MessageExpectedCloseParenthesisAfterMemoryOrdering, index)
+			}
+			index++
+		default:
+			return nil, -1, newParseError(tokens, index, MessageExpectedLetAfterQualifier, tokens[index-1].String(), index)
+		}
+	}
+
+	letNode, index, err := s.parseLet(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+	letNode.ThreadLocal = threadLocal
+	letNode.Volatile = volatile
+	letNode.Ordering = ordering
+	return letNode, index, nil
+}
+
+// parseEmbed takes a slice of tokens and an index as input parameters and
+// returns an EmbedNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "embed greeting "greeting.txt"".
+func parseEmbed(tokens []Token, index int) (*EmbedNode, int, error) {
+	// Ensure the next token is an identifier
+	index++
+	if IsNotIdentifierToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedIdentifierAfterEmbed, index)
+	}
+	if err := checkDeclaredIdentifier(tokens, index); err != nil {
+		return nil, -1, err
+	}
+	identifier := tokens[index].Value
+	index++
+
+	// Ensure the next token is the file name
+	if IsNotStringToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedFileNameAfterEmbed, index)
+	}
+	path := tokens[index].Value
+	index++
+
+	return &EmbedNode{Identifier: identifier, Path: path}, index, nil
+}
+
+// parsePackage takes a slice of tokens and an index as input parameters and
+// returns a PackageNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "package math", which have to
+// start the file.
+func parsePackage(tokens []Token, index int) (*PackageNode, int, error) {
+	if index != 0 {
+		return nil, -1, newParseError(tokens, index, MessagePackageNotFirst, index)
+	}
+
+	// Ensure the next token is the package name
+	index++
+	if IsNotIdentifierToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedPackageName, index)
+	}
+	if err := checkDeclaredIdentifier(tokens, index); err != nil {
+		return nil, -1, err
+	}
+	name := tokens[index].Value
+	index++
+
+	return &PackageNode{Name: name}, index, nil
+}
+
+// parseImport takes a slice of tokens and an index as input parameters and
+// returns an ImportNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "import "lib.gusty"".
+func parseImport(tokens []Token, index int) (*ImportNode, int, error) {
+	// Ensure the next token is the file name
+	index++
+	if IsNotStringToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedFileNameAfterImport, index)
+	}
+	path := tokens[index].Value
+	index++
+
+	return &ImportNode{Path: path}, index, nil
+}
+
+// parseTypeAlias takes a slice of tokens and an index as input parameters and
+// returns a TypeAliasNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "type Index = i32".
+func (s *parserState) parseTypeAlias(tokens []Token, index int) (*TypeAliasNode, int, error) {
+	// Ensure the next token is an identifier
+	index++
+	if IsNotIdentifierToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedIdentifierAfterTypeKeyword, index)
+	}
+	if err := checkDeclaredIdentifier(tokens, index); err != nil {
+		return nil, -1, err
+	}
+	name := tokens[index].Value
+	index++
+
+	// Ensure the next token is an equal sign '='
+	if IsNotEqualToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedEqualsAfterTypeAlias, index)
+	}
+	index++
+
+	// Parse the aliased type
+	if IsNotTypeStartToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedType, index)
+	}
+	aliasType, index, err := s.parseType(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return &TypeAliasNode{Name: name, Type: aliasType}, index, nil
+}
+
+// parseStruct takes a slice of tokens and an index as input parameters and
+// returns a StructNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "struct Point { x i32 y i32 }",
+// the fields may be separated by commas.
+func (s *parserState) parseStruct(tokens []Token, index int) (*StructNode, int, error) {
+	// Ensure the next token is an identifier
+	index++
+	if IsNotIdentifierToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedIdentifierAfterStruct, index)
+	}
+	if err := checkDeclaredIdentifier(tokens, index); err != nil {
+		return nil, -1, err
+	}
+	name := tokens[index].Value
+	index++
+
+	// Ensure the next token is an open curly brace '{'
+	if IsNotOpenCurlyBracketToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedOpenCurlyAfterStructName, index)
+	}
+	index++
+
+	// Parse the fields
+	var fields []*Field
+	for !IsNotIdentifierToken(index, tokens) {
+		if err := checkDeclaredIdentifier(tokens, index); err != nil {
+			return nil, -1, err
+		}
+		field := &Field{Identifier: tokens[index].Value}
+		index++
+
+		if IsNotTypeStartToken(index, tokens) {
+			return nil, -1, newParseError(tokens, index, MessageExpectedTypeAfterStructField, index)
+		}
+		fieldType, newIndex, err := s.parseType(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		field.Type = fieldType
+		fields = append(fields, field)
+		index = newIndex
+
+		// Parse the optional alignment attribute of the field
+		if !IsNotAtToken(index, tokens) {
+			alignment, newIndex, err := parseAlignAttribute(tokens, index)
+			if err != nil {
+				return nil, -1, err
+			}
+			field.Alignment = alignment
+			index = newIndex
+		}
+
+		// Skip the comma separating this field from the next one
+		if IsCommaToken(index, tokens) {
+			index++
+		}
+	}
+
+	// Ensure the next token is a close curly brace '}'
+	if IsNotCloseCurlyBracketToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedCloseCurlyAfterStructFields, index)
+	}
+	index++
+
+	return &StructNode{Name: name, Fields: fields}, index, nil
+}
+
+// parseAttributedDeclaration takes a slice of tokens and an index as input parameters and
+// returns a StructNode, FunctionNode or ExternNode, an updated index, and an error if there is
+// any issue during parsing. It processes declarations preceded by attributes, e.g.
+// "@packed struct Header { tag i8, size i32 }" or "@noinline function f() {}".
+func (s *parserState) parseAttributedDeclaration(tokens []Token, index int) (Node, int, error) {
+	var attributes []string
+	var positions []int
+	for !IsNotAtToken(index, tokens) {
+		index++
+		if IsNotIdentifierToken(index, tokens) {
+			return nil, -1, newParseError(tokens, index, MessageExpectedStructAfterAttribute, index)
+		}
+		attributes = append(attributes, tokens[index].Value)
+		positions = append(positions, index)
+		index++
+	}
+
+	switch {
+	case !IsNotStructToken(index, tokens):
+		for _, position := range positions {
+			if tokens[position].Value != packedAttribute {
+				return nil, -1, newParseError(tokens, position, MessageUnknownStructAttribute, position)
+			}
+		}
+		structNode, index, err := s.parseStruct(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		structNode.Packed = true
+		return structNode, index, nil
+	case !IsNotFunctionToken(index, tokens), index < len(tokens) && tokens[index].Type == TokenExternType:
+		for i, attribute := range attributes {
+			if !functionAttributes[attribute] {
+				return nil, -1, newParseError(tokens, positions[i], MessageUnknownFunctionAttribute, attribute, positions[i])
+			}
+		}
+		if IsNotFunctionToken(index, tokens) {
+			externNode, index, err := s.parseExtern(tokens, index)
+			if err != nil {
+				return nil, -1, err
+			}
+			externNode.Attributes = attributes
+			return externNode, index, nil
+		}
+		functionNode, index, err := s.parseFunction(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		functionNode.(*FunctionNode).Attributes = attributes
+		return functionNode, index, nil
 	}
-
-	return nodes, index, nil
+	return nil, -1, newParseError(tokens, index, MessageExpectedStructAfterAttribute, index)
 }
 
-// parseFunction takes a slice of tokens and an index as input parameters and
-// returns a slice of nodes, an updated index, and an error if there is any issue
-// during parsing. It processes tokens to generate a FunctionNode with its parameters
-// and body.
-func parseFunction(tokens []Token, index int) (Node, int, error) {
-	// Ensure there is a token following the 'function' keyword
+// parseAlignAttribute takes a slice of tokens and an index as input parameters and
+// returns the alignment, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "@align(16)", the alignment
+// has to be a power of two.
+func parseAlignAttribute(tokens []Token, index int) (int, int, error) {
 	index++
-	if IsNotIdentifierToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected identifier after 'function' at position %d", index)
+	if IsNotIdentifierToken(index, tokens) || tokens[index].Value != alignAttribute {
+		return 0, -1, newParseError(tokens, index, MessageUnknownFieldAttribute, index)
 	}
-	name := tokens[index].Value
-	// Ensure the next token is an open bracket '('
 	index++
+
+	// Ensure the next token is an open parenthesis '('
 	if IsNotOpenParenthesisToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected '(' after function name at position %d", index)
+		return 0, -1, newParseError(tokens, index, MessageExpectedOpenParenthesisAfterAlign, index)
 	}
 	index++
 
-	// Initialize parameters slice and parse function parameters
-	var parameters []*Parameter
-	for {
-		if IsIdentifierToken(index, tokens) {
-			var p = &Parameter{Identifier: tokens[index].Value}
-
-			index++
-			if IsInteger32Token(index, tokens) {
-				return nil, -1, fmt.Errorf("expected 'i32' after function parameter at position %d", index)
-			}
-			p.Type = Integer32Type
-			parameters = append(parameters, p)
-			index++
-		} else {
-			break
-		}
+	if IsNotIdentifierToken(index, tokens) {
+		return 0, -1, newParseError(tokens, index, MessageExpectedAlignment, index)
+	}
+	alignment, err := strconv.Atoi(tokens[index].Value)
+	if err != nil || alignment <= 0 || alignment&(alignment-1) != 0 {
+		return 0, -1, newParseError(tokens, index, MessageInvalidAlignment, tokens[index].Value, index)
 	}
+	index++
 
-	// Ensure the next token is a close bracket ')'
+	// Ensure the next token is a close parenthesis ')'
 	if IsNotCloseParenthesisToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected ')' after function parameters at position %d", index)
+		return 0, -1, newParseError(tokens, index, MessageExpectedCloseParenthesisAfterAlignment, index)
+	}
+	index++
+
+	return alignment, index, nil
+}
+
+// parseStructLiteral takes a slice of tokens and an index as input parameters and
+// returns a StructLiteralNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "Point{x: 1, y: 2}".
+func (s *parserState) parseStructLiteral(tokens []Token, index int) (*StructLiteralNode, int, error) {
+	// Retrieve the struct name from the current token
+	if err := checkReservedWord(tokens, index); err != nil {
+		return nil, -1, err
 	}
+	name := tokens[index].Value
 	index++
 
 	// Ensure the next token is an open curly brace '{'
 	if IsNotOpenCurlyBracketToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected '{' after function parameters at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedOpenCurlyAfterStructName, index)
 	}
 	index++
 
-	// Parse the function body
-	body, newIndex, err := parseNodes(tokens[index:], 0, TokenFunctionType)
-	if err != nil {
-		return nil, -1, err
+	// Parse the field values
+	var fields []*FieldValue
+	for index < len(tokens) && IsNotCloseCurlyBracketToken(index, tokens) {
+		if IsNotIdentifierToken(index, tokens) {
+			return nil, -1, newParseError(tokens, index, MessageExpectedFieldName, index)
+		}
+		field := &FieldValue{Identifier: tokens[index].Value}
+		index++
+
+		// Ensure the next token is a colon ':'
+		if !IsColonToken(index, tokens) {
+			return nil, -1, newParseError(tokens, index, MessageExpectedColonAfterFieldName, index)
+		}
+		index++
+
+		value, newIndex, err := s.parseValue(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		field.Value = value
+		fields = append(fields, field)
+		index = newIndex
+
+		// Skip the comma separating this field from the next one
+		if IsCommaToken(index, tokens) {
+			index++
+		}
 	}
-	index += newIndex
 
 	// Ensure the next token is a close curly brace '}'
 	if IsNotCloseCurlyBracketToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected '}' after function body at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedCloseCurlyAfterFieldValues, index)
 	}
 	index++
 
-	// Create a FunctionNode with the parsed information
-	return &FunctionNode{Name: name, Parameters: parameters, Body: body}, index, nil
+	return &StructLiteralNode{Name: name, Fields: fields}, index, nil
 }
 
-// parseWhile takes a slice of tokens and an index as input parameters and
-// returns a WhileNode, an updated index, and an error if there is any issue
-// during parsing. It processes tokens to generate a WhileNode with its
-// condition and body.
-func parseWhile(tokens []Token, index int) (*WhileNode, int, error) {
-	// Ensure the next token is an open bracket '('
-	index++
-	if IsNotOpenParenthesisToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected '(' after 'while' at position %d", index)
+// parseArrayLiteral takes a slice of tokens and an index as input parameters and
+// returns an ArrayLiteralNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "[1, 2, 3]".
+func (s *parserState) parseArrayLiteral(tokens []Token, index int) (*ArrayLiteralNode, int, error) {
+	// Ensure the current token is an open square bracket '['
+	if IsNotOpenSquareBracketToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedOpenSquare, index)
 	}
-	condition := tokens[index+2].Value
-	index += 2
+	index++
 
-	// Ensure the next token is a close bracket ')'
-	if IsNotCloseParenthesisToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected ')' after while condition at position %d", index)
+	// Parse the elements
+	var elements []Expr
+	for index < len(tokens) && IsNotCloseSquareBracketToken(index, tokens) {
+		value, newIndex, err := s.parseValue(tokens, index)
+		if err != nil {
+			return nil, -1, err
+		}
+		elements = append(elements, value)
+		index = newIndex
+
+		// Skip the comma separating this element from the next one
+		if IsCommaToken(index, tokens) {
+			index++
+		}
 	}
-	index++
 
-	// Ensure the next token is an open curly brace '{'
-	if IsNotOpenCurlyBracketToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected '{' after while condition at position %d", index)
+	// Ensure the next token is a close square bracket ']'
+	if IsNotCloseSquareBracketToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedCloseSquareAfterArrayElements, index)
 	}
 	index++
 
-	// Parse the while loop body
-	body, newIndex, err := parseNodes(tokens[index:], 0, -1)
-	if err != nil {
+	return &ArrayLiteralNode{Elements: elements}, index, nil
+}
+
+// parseAssignment takes a slice of tokens and an index as input parameters and
+// returns an AssignmentNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "x = 5".
+func (s *parserState) parseAssignment(tokens []Token, index int) (*AssignmentNode, int, error) {
+	// Retrieve the variable name from the current token
+	if err := checkReservedWord(tokens, index); err != nil {
 		return nil, -1, err
 	}
-	index += newIndex
+	name := tokens[index].Value
+	index++
 
-	// Ensure the next token is a close curly brace '}'
-	if IsNotCloseCurlyBracketToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected '}' after while body at position %d", index)
+	// Ensure the next token is an equals sign '='
+	if IsNotEqualToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedEqualsAfterIdentifier, index)
 	}
 	index++
 
-	// Create a WhileNode with the parsed condition and body
-	whileNode := &WhileNode{Condition: condition, Body: body}
-	return whileNode, index, nil
+	// Parse the value after the equals sign
+	value, index, err := s.parseValue(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return &AssignmentNode{Identifier: name, Value: value}, index, nil
 }
 
-// parseLet takes a slice of tokens and an index as input parameters and
-// returns a LetNode, an updated index, and an error if there is any issue
-// during parsing. It processes tokens to generate a LetNode with its
-// identifier and value.
-func parseLet(tokens []Token, index int) (*LetNode, int, error) {
-	// Ensure the next token is an identifier
-	index++
-	if IsNotIdentifierToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected identifier after 'let' at position %d", index)
+// parseElementAssignment takes a slice of tokens and an index as input parameters and
+// returns an IndexAssignmentNode, a FieldAssignmentNode or a DereferenceAssignmentNode, an updated
+// index, and an error if there is any issue during parsing. It processes tokens of the form
+// "a[i] = 5", "p.x = 3" or "*p = 1".
+func (s *parserState) parseElementAssignment(tokens []Token, index int) (Node, int, error) {
+	start := index
+
+	// Parse the indexed array element or the selected field
+	target, index, err := s.parseOperand(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+	switch target.(type) {
+	case *IndexNode, *FieldNode, *DereferenceNode:
+	default:
+		return nil, -1, newParseError(tokens, start+1, MessageExpectedIndexAfterIdentifier, start+1)
 	}
-	name := tokens[index].Value
-	index++
 
 	// Ensure the next token is an equals sign '='
 	if IsNotEqualToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected '=' after let at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedEqualsAfterIndex, index)
 	}
 	index++
 
-	// Parse the integer value after the equals sign
-	intValue, err := strconv.Atoi(tokens[index].Value)
+	// Parse the value after the equals sign
+	value, index, err := s.parseValue(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	switch t := target.(type) {
+	case *FieldNode:
+		return &FieldAssignmentNode{Target: t, Value: value}, index, nil
+	case *DereferenceNode:
+		return &DereferenceAssignmentNode{Target: t, Value: value}, index, nil
+	}
+	return &IndexAssignmentNode{Target: target.(*IndexNode), Value: value}, index, nil
+}
+
+// parseNew takes a slice of tokens and an index as input parameters and
+// returns a NewNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "new(Point)".
+func (s *parserState) parseNew(tokens []Token, index int) (*NewNode, int, error) {
+	// Skip the 'new' identifier and the open bracket '('
+	index += 2
+
+	// Parse the type of the allocated value
+	if IsNotTypeStartToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedTypeAfterNew, index)
+	}
+	t, index, err := s.parseType(tokens, index)
 	if err != nil {
-		return nil, -1, fmt.Errorf("expected 'int' after equal condition at position %d", index)
+		return nil, -1, err
+	}
+
+	// Ensure the next token is a close bracket ')'
+	if IsNotCloseParenthesisToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedCloseParenthesisAfterNewType, index)
 	}
 	index++
 
-	// Create a LetNode with the parsed identifier and value
-	letNode := &LetNode{
-		Identifier: name,
-		Value:      int32(intValue),
+	return &NewNode{Type: t}, index, nil
+}
+
+// parseSpawn takes a slice of tokens and an index as input parameters and
+// returns a SpawnNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "spawn count(10)".
+func (s *parserState) parseSpawn(tokens []Token, index int) (*SpawnNode, int, error) {
+	// Skip the 'spawn' keyword
+	index++
+
+	// Ensure the next tokens are the call of a function
+	if IsNotIdentifierToken(index, tokens) || IsNotOpenParenthesisToken(index+1, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedCallAfterSpawn, index)
 	}
+	start := index
+	callerNode, index, err := s.parseCaller(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+	setTokenSpan(callerNode, tokens, start, index)
 
-	return letNode, index, nil
+	return &SpawnNode{Call: callerNode}, index, nil
 }
 
-// parseCaller takes a slice of tokens and an index as input parameters and
-// returns a CallerNode, an updated index, and an error if there is any issue
-// during parsing. It processes tokens to generate a CallerNode with its
-// function name and parameters.
-func parseCaller(tokens []Token, index int) (*CallerNode, int, error) {
-	// Retrieve the function name from the current token
-	name := tokens[index].Value
+// parseCast takes a slice of tokens and an index as input parameters and
+// returns a CastNode, an updated index, and an error if there is any issue
+// during parsing. It processes tokens of the form "i64(x)".
+func (s *parserState) parseCast(tokens []Token, index int) (*CastNode, int, error) {
+	// Ensure the current token is a type
+	if IsNotTypeToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedType, index)
+	}
+	castType := typeTokens[tokens[index].Type]
+	index++
 
 	// Ensure the next token is an open bracket '('
-	index++
 	if IsNotOpenParenthesisToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected '(' after caller at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedOpenParenthesisAfterType, index)
 	}
 	index++
 
-	// Initialize parameters slice
-	var parameters []*Parameter
+	// Parse the value which gets converted
+	value, newIndex, err := s.parseValue(tokens, index)
+	if err != nil {
+		return nil, -1, err
+	}
+	index = newIndex
 
-	// Parse the function parameters
-	var isParameterOperation bool
-	var addOperationNode *AddOperationNode
-	if IsNotAddToken(index+1, tokens) {
-		for {
-			if IsIdentifierToken(index, tokens) {
+	// Ensure the next token is a close bracket ')'
+	if IsNotCloseParenthesisToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedCloseParenthesisAfterCastValue, index)
+	}
+	index++
 
-				intValue, err := strconv.Atoi(tokens[index].Value)
-				if err == nil {
-					parameters = append(parameters, &Parameter{Value: int32(intValue), Type: Integer32Type, Identifier: tokens[index].Value})
-				} else {
-					parameters = append(parameters, &Parameter{Value: tokens[index].Value, Identifier: tokens[index].Value})
-				}
+	return &CastNode{Type: castType, Value: value}, index, nil
+}
 
-				index++
-			} else {
-				break
-			}
-		}
-	} else {
-		var err error
-		addOperationNode, _, err = parseAddOperation(tokens, index)
-		if err != nil {
-			return nil, -1, err
-		}
+// parseLiteral converts a token into its literal value. Integers become IntLiteralNodes, numbers
+// with a fraction FloatLiteralNodes, true and false BoolLiteralNodes and every other identifier an
+// IdentifierNode.
+func parseLiteral(token Token) Expr {
+	switch token.Type {
+	case TokenTrueType:
+		return &BoolLiteralNode{Value: true}
+	case TokenFalseType:
+		return &BoolLiteralNode{Value: false}
+	}
 
-		index += 3
-		isParameterOperation = true
+	if intValue, err := strconv.ParseInt(token.Value, 10, 64); err == nil {
+		return &IntLiteralNode{Value: intValue}
 	}
 
-	// Ensure the next token is a close bracket ')'
-	if IsNotCloseParenthesisToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected ')' after parameters at position %d", index)
+	if floatValue, err := strconv.ParseFloat(token.Value, 64); err == nil {
+		return &FloatLiteralNode{Value: floatValue}
 	}
-	index++
 
-	// Create a CallerNode with the parsed function name and parameters
-	callerNode := &CallerNode{FunctionName: name, Parameters: parameters, isParameterOperation: isParameterOperation, AddOperationNode: addOperationNode}
+	return &IdentifierNode{Name: token.Value}
+}
 
-	return callerNode, index, nil
+// literalDataType returns the default data type of a literal value produced by parseLiteral.
+// It returns false if the value is an identifier or another expression rather than a literal.
+func literalDataType(value Expr) (dataType, bool) {
+	switch v := value.(type) {
+	case *IntLiteralNode:
+		return v.dataType(), true
+	case *FloatLiteralNode:
+		return Float64Type, true
+	case *BoolLiteralNode:
+		return BoolType, true
+	}
+	return 0, false
 }
 
 // parseAddOperation is a function that parses an addition operation from a list of tokens.
-// The function expects two integer values separated by an add sign, e.g., "2 + 3".
+// The function expects two or more values separated by add signs, e.g., "2 + 3" or "a + b + 1".
 // It creates an AddOperationNode that represents the addition operation in the abstract syntax tree (AST).
 //
 // tokens: A list of tokens representing the input code.
 // index:  The current index in the list of tokens.
 //
 // Returns an AddOperationNode representing the addition operation, the updated index after parsing, and an error if any issues are encountered during parsing.
-func parseAddOperation(tokens []Token, index int) (*AddOperationNode, int, error) {
-	// Ensure the next token is an identifier (integer value)
-	if IsNotIdentifierToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected 'identifier' at start %d", index)
-	}
+func (s *parserState) parseAddOperation(tokens []Token, index int) (*AddOperationNode, int, error) {
+	start := index
 
-	// Parse the integer value for the left operand
-	leftValue, err := strconv.Atoi(tokens[index].Value)
+	// Parse the operands and the add signs between them
+	value, index, err := s.parseValue(tokens, index)
 	if err != nil {
-		return nil, -1, fmt.Errorf("expected 'int' as value at position %d", index)
+		return nil, -1, err
 	}
-	index++
 
-	// Ensure the next token is an add sign (+)
-	if IsNotAddToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected 'add sign' after 'identifier' at position %d", index)
+	// Ensure at least one add sign (+) followed the first operand
+	addOperationNode, ok := value.(*AddOperationNode)
+	if !ok {
+		return nil, -1, newParseError(tokens, start+1, MessageExpectedAddSignAfterValue, start+1)
 	}
+
+	return addOperationNode, index, nil
+}
+
+// parseReturn takes a slice of tokens and an index as input parameters and
+// returns a ReturnNode, an updated index, and an error if there is any issue
+// during parsing. The return value is omitted if the return is the last
+// statement of a block.
+func (s *parserState) parseReturn(tokens []Token, index int) (*ReturnNode, int, error) {
+	// Skip the 'return' keyword
 	index++
 
-	// Ensure the next token is an identifier (integer value) for the right operand
-	if IsNotIdentifierToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected 'identifier' after 'add sign' at position %d", index)
+	// A return without value is followed by the end of the block
+	if index >= len(tokens) || !IsNotCloseCurlyBracketToken(index, tokens) {
+		return &ReturnNode{}, index, nil
 	}
 
-	// Parse the integer value for the right operand
-	rightValue, err := strconv.Atoi(tokens[index].Value)
+	// Parse the return value
+	value, newIndex, err := s.parseValue(tokens, index)
 	if err != nil {
-		return nil, -1, fmt.Errorf("expected 'int' as value at position %d", index)
-	}
-	index++
-
-	// Create a AddOperationNode with the parsed left and right values
-	addOperationNode := &AddOperationNode{
-		LeftValue:  int32(leftValue),
-		RightValue: int32(rightValue),
+		return nil, -1, err
 	}
 
-	return addOperationNode, index, nil
+	return &ReturnNode{Value: value}, newIndex, nil
 }
 
 // parseFor is a function that parses a "for" loop from a list of tokens.
@@ -454,48 +2208,53 @@ func parseAddOperation(tokens []Token, index int) (*AddOperationNode, int, error
 // index:  The current index in the list of tokens.
 //
 // Returns a ForNode representing the "for" loop, the updated index after parsing, and an error if any issues are encountered during parsing.
-func parseFor(tokens []Token, index int) (*ForNode, int, error) {
+func (s *parserState) parseFor(tokens []Token, index int) (*ForNode, int, error) {
 	// Ensure the next token is a 'for' keyword
 	if IsNotForToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected 'for' at start %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedFor, index)
 	}
 	index++
 
 	// Parse the loop initialization statement (short variable assignment)
+	initStart := index
 	if IsNotIdentifierToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected identifier after 'for' at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedIdentifierAfterFor, index)
+	}
+	if err := checkDeclaredIdentifier(tokens, index); err != nil {
+		return nil, -1, err
 	}
 
 	shortVariableAssigmentName := tokens[index].Value
 	index++
 
 	if IsNotShortVariableAssigmentToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected := after 'identifier' at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedShortVariableAssignmentAfterIdentifier, index)
 	}
 	index++
 
 	if IsNotIdentifierToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected identifier after ':=' at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedIdentifierAfterShortVariableAssignment, index)
 	}
 
 	// Parse the integer value for loop initialization
 	shortVariableAssigmentRightValue, err := strconv.Atoi(tokens[index].Value)
 	if err != nil {
-		return nil, -1, fmt.Errorf("expected 'int' as value at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedIntValue, index)
 	}
 
 	index++
 
 	// Ensure the next token is a semicolon ';'
 	if IsNotSemicolonToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected ; after 'value' at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedSemicolonAfterValue, index)
 	}
 
 	index++
 
 	// Parse the loop condition statement
+	conditionStart := index
 	if IsNotIdentifierToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected identifier after ';' at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedIdentifierAfterSemicolon, index)
 	}
 
 	conditionLeftValue := tokens[index].Value
@@ -503,29 +2262,33 @@ func parseFor(tokens []Token, index int) (*ForNode, int, error) {
 
 	// Ensure the next token is a less than operator '<'
 	if IsNotLessThanToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected < after 'idenfifier' at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedLessThanAfterIdentifier, index)
 	}
-	operator := LessThanOperator{}
+	operator := OperatorLess
 	index++
 
 	// Parse the integer value for loop condition
+	if IsNotIdentifierToken(index, tokens) {
+		return nil, -1, newParseError(tokens, index, MessageExpectedIntValue, index)
+	}
 	conditionRightValue, err := strconv.Atoi(tokens[index].Value)
 	if err != nil {
-		return nil, -1, fmt.Errorf("expected 'int' as value at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedIntValue, index)
 	}
 
 	index++
 
 	// Ensure the next token is a semicolon ';'
 	if IsNotSemicolonToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected ; after 'value' at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedSemicolonAfterValue, index)
 	}
 
 	index++
 
 	// Parse the loop post statement (increment or decrement)
+	postStart := index
 	if IsNotIdentifierToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected identifier after ';' at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedIdentifierAfterSemicolon, index)
 	}
 
 	postIdentifier := tokens[index].Value
@@ -533,13 +2296,13 @@ func parseFor(tokens []Token, index int) (*ForNode, int, error) {
 	index++
 	// Ensure the next token is an add sign
 	if IsNotAddToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected 'add sign' after 'identifier' at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedAddSignAfterIdentifier, index)
 	}
 	index++
 
 	// Ensure the next token is an add sign '+'
 	if IsNotAddToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected 'add sign' after 'add sign' at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedAddSignAfterAddSign, index)
 	}
 
 	index++
@@ -548,27 +2311,33 @@ func parseFor(tokens []Token, index int) (*ForNode, int, error) {
 	forNode := &ForNode{
 		Init: ShortVariableAssigmentNode{
 			Identifier: shortVariableAssigmentName,
-			Value:      int32(shortVariableAssigmentRightValue),
+			Value:      &IntLiteralNode{Value: int64(shortVariableAssigmentRightValue)},
 		},
 		Condition: ConditionNode{
 			LeftValue:  conditionLeftValue,
 			Operator:   operator,
-			RightValue: int32(conditionRightValue),
+			RightValue: &IntLiteralNode{Value: int64(conditionRightValue)},
 		},
 		Post: PostNode{
 			Identifier: postIdentifier,
 			Increment:  true,
 		},
 	}
+	// The statements span their tokens without the semicolons separating them
+	setTokenSpan(&forNode.Init, tokens, initStart, conditionStart-1)
+	setTokenSpan(&forNode.Condition, tokens, conditionStart, postStart-1)
+	setTokenSpan(&forNode.Post, tokens, postStart, index)
+	setTokenSpan(forNode.Init.Value, tokens, conditionStart-2, conditionStart-1)
+	setTokenSpan(forNode.Condition.RightValue, tokens, postStart-2, postStart-1)
 
 	// Ensure the next token is an open curly brace '{'
 	if IsNotOpenCurlyBracketToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected '{' after function parameters at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedOpenCurlyAfterFunctionParameters, index)
 	}
 	index++
 
 	// Parse the loop body, which is a sequence of statements enclosed in curly braces
-	body, newIndex, err := parseNodes(tokens[index:], 0, TokenForType)
+	body, newIndex, err := s.parseNodes(tokens[index:], 0, TokenForType)
 	if err != nil {
 		return nil, -1, err
 	}
@@ -576,7 +2345,7 @@ func parseFor(tokens []Token, index int) (*ForNode, int, error) {
 
 	// Ensure the next token is a close curly brace '}'
 	if IsNotCloseCurlyBracketToken(index, tokens) {
-		return nil, -1, fmt.Errorf("expected '}' after function body at position %d", index)
+		return nil, -1, newParseError(tokens, index, MessageExpectedCloseCurlyAfterFunctionBody, index)
 	}
 	index++
 
@@ -587,6 +2356,43 @@ func parseFor(tokens []Token, index int) (*ForNode, int, error) {
 	return forNode, index, nil
 }
 
+// ReservedWords holds words which are not keywords yet but are reserved for future language
+// features, so they can't be used as identifiers. It may be changed before parsing to reserve
+// further words or to release reserved ones.
+var ReservedWords = []string{
+	"if",
+	"else",
+	"const",
+	"var",
+	"break",
+	"continue",
+	"match",
+	"defer",
+}
+
+// checkReservedWord returns an error if the identifier token at the given index is one of the ReservedWords.
+func checkReservedWord(tokens []Token, index int) error {
+	for _, word := range ReservedWords {
+		if tokens[index].Value == word {
+			return newParseError(tokens, index, MessageReservedWord, word, index)
+		}
+	}
+	return nil
+}
+
+// checkDeclaredIdentifier returns an error if the identifier token at the given index, which
+// declares a function, parameter or variable, is a reserved word or uses the namespace reserved
+// for generated symbols.
+func checkDeclaredIdentifier(tokens []Token, index int) error {
+	if err := checkReservedWord(tokens, index); err != nil {
+		return err
+	}
+	if strings.HasPrefix(tokens[index].Value, runtimePrefix) {
+		return newParseError(tokens, index, MessageReservedPrefix, tokens[index].Value, index, runtimePrefix)
+	}
+	return nil
+}
+
 // IsNotLessThanToken checks if the token at the given index is not a less than or if the index is out of bounds.
 func IsNotLessThanToken(currentIndex int, tokens []Token) bool {
 	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenLessThanType
@@ -607,9 +2413,16 @@ func IsNotForToken(currentIndex int, tokens []Token) bool {
 	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenForType
 }
 
-// IsOpenParenthesisToken checks if the token at the given index is an open parenthesis or if the index is out of bounds.
+// IsQualifiedCallerToken checks if the tokens at the given index are a caller qualified with the
+// name of a package, i.e. an identifier, a dot, an identifier and an open parenthesis.
+func IsQualifiedCallerToken(currentIndex int, tokens []Token) bool {
+	return currentIndex+3 < len(tokens) && tokens[currentIndex].Type == TokenIdentifierType && tokens[currentIndex+1].Type == TokenDotType &&
+		tokens[currentIndex+2].Type == TokenIdentifierType && tokens[currentIndex+3].Type == TokenOpenParenthesisType
+}
+
+// IsOpenParenthesisToken checks if the token at the given index is an open parenthesis.
 func IsOpenParenthesisToken(currentIndex int, tokens []Token) bool {
-	return currentIndex >= len(tokens) || tokens[currentIndex].Type == TokenOpenParenthesisType
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenOpenParenthesisType
 }
 
 // IsNotOpenParenthesisToken checks if the token at the given index is not an open parenthesis or if the index is out of bounds.
@@ -632,9 +2445,127 @@ func IsNotCloseCurlyBracketToken(currentIndex int, tokens []Token) bool {
 	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenCloseCurlyBracketType
 }
 
-// IsIdentifierToken checks if the token at the given index is an identifier or if the index is out of bounds.
+// IsNotOpenSquareBracketToken checks if the token at the given index is not an open square bracket or if the index is out of bounds.
+func IsNotOpenSquareBracketToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenOpenSquareBracketType
+}
+
+// IsNotStringToken checks if the token at the given index is not a string literal or if the index is out of bounds.
+func IsNotStringToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenStringType
+}
+
+// IsNotAtToken checks if the token at the given index is not an at sign '@' or if the index is out of bounds.
+func IsNotAtToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenAtType
+}
+
+// IsStatementIdentifierToken checks if the token at the given index is an identifier starting a
+// statement, i.e. an identifier followed by the open parenthesis of a call, the equals sign of an
+// assignment, the dot of a field or qualified call, the open square bracket of an index or a plus.
+func IsStatementIdentifierToken(currentIndex int, tokens []Token) bool {
+	if currentIndex+1 >= len(tokens) || tokens[currentIndex].Type != TokenIdentifierType {
+		return false
+	}
+	switch tokens[currentIndex+1].Type {
+	case TokenOpenParenthesisType, TokenEqualsType, TokenDotType, TokenOpenSquareBracketType, TokenAddType:
+		return true
+	}
+	return false
+}
+
+// IsNotFunctionToken checks if the token at the given index is not the function keyword or if the index is out of bounds.
+func IsNotFunctionToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenFunctionType
+}
+
+// IsNotStructToken checks if the token at the given index is not the struct keyword or if the index is out of bounds.
+func IsNotStructToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenStructType
+}
+
+// IsNotLetToken checks if the token at the given index is not the let keyword or if the index is out of bounds.
+func IsNotLetToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenLetType
+}
+
+// IsNoneToken checks if the token at the given index is the none keyword.
+func IsNoneToken(currentIndex int, tokens []Token) bool {
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenNoneType
+}
+
+// IsOptionToken checks if the token at the given index is the identifier starting an option type.
+func IsOptionToken(currentIndex int, tokens []Token) bool {
+	return !IsNotIdentifierToken(currentIndex, tokens) && tokens[currentIndex].Value == optionIdentifier
+}
+
+// IsResultToken checks if the token at the given index is the identifier starting a result type.
+func IsResultToken(currentIndex int, tokens []Token) bool {
+	return !IsNotIdentifierToken(currentIndex, tokens) && tokens[currentIndex].Value == resultIdentifier
+}
+
+// IsTryToken checks if the token at the given index is the try keyword.
+func IsTryToken(currentIndex int, tokens []Token) bool {
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenTryType
+}
+
+// IsShiftToken checks if the token at the given index is a shift operator '<<' or '>>'.
+func IsShiftToken(currentIndex int, tokens []Token) bool {
+	return currentIndex < len(tokens) && (tokens[currentIndex].Type == TokenShiftLeftType || tokens[currentIndex].Type == TokenShiftRightType)
+}
+
+// IsShiftRightToken checks if the token at the given index is a shift right operator '>>'.
+func IsShiftRightToken(currentIndex int, tokens []Token) bool {
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenShiftRightType
+}
+
+// IsComparisonToken checks if the token at the given index is a comparison operator, e.g. '==' or '<'.
+func IsComparisonToken(currentIndex int, tokens []Token) bool {
+	if currentIndex >= len(tokens) {
+		return false
+	}
+	_, ok := comparisonOperators[tokens[currentIndex].Type]
+	return ok
+}
+
+// IsNotGreaterThanToken checks if the token at the given index is not a greater than sign '>' or if the index is out of bounds.
+func IsNotGreaterThanToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenGreaterThanType
+}
+
+// IsSpawnToken checks if the token at the given index is the spawn keyword.
+func IsSpawnToken(currentIndex int, tokens []Token) bool {
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenSpawnType
+}
+
+// IsNewToken checks if the token at the given index is the identifier of the new builtin.
+func IsNewToken(currentIndex int, tokens []Token) bool {
+	return !IsNotIdentifierToken(currentIndex, tokens) && tokens[currentIndex].Value == newIdentifier
+}
+
+// IsNotAmpersandToken checks if the token at the given index is not an ampersand or if the index is out of bounds.
+func IsNotAmpersandToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenAmpersandType
+}
+
+// IsNotStarToken checks if the token at the given index is not a star or if the index is out of bounds.
+func IsNotStarToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenStarType
+}
+
+// IsNotDotToken checks if the token at the given index is not a dot or if the index is out of bounds.
+func IsNotDotToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenDotType
+}
+
+// IsNotCloseSquareBracketToken checks if the token at the given index is not a close square bracket or if the index is out of bounds.
+func IsNotCloseSquareBracketToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenCloseSquareBracketType
+}
+
+// IsIdentifierToken checks if the token at the given index is an identifier.
 func IsIdentifierToken(currentIndex int, tokens []Token) bool {
-	return currentIndex >= len(tokens) || tokens[currentIndex].Type == TokenIdentifierType
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenIdentifierType
 }
 
 // IsNotIdentifierToken checks if the token at the given index is not an identifier or if the index is out of bounds.
@@ -647,9 +2578,54 @@ func IsNotEqualToken(currentIndex int, tokens []Token) bool {
 	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenEqualsType
 }
 
-// IsInteger32Token checks if the token at the given index is an integer32 or if the index is out of bounds.
+// IsInteger32Token checks if the token at the given index is an integer32.
 func IsInteger32Token(currentIndex int, tokens []Token) bool {
-	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenInteger32Type
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenInteger32Type
+}
+
+// IsTypeToken checks if the token at the given index is a type keyword.
+func IsTypeToken(currentIndex int, tokens []Token) bool {
+	if currentIndex >= len(tokens) {
+		return false
+	}
+	_, ok := typeTokens[tokens[currentIndex].Type]
+	return ok
+}
+
+// IsNotTypeToken checks if the token at the given index is not a type keyword or if the index is out of bounds.
+func IsNotTypeToken(currentIndex int, tokens []Token) bool {
+	return !IsTypeToken(currentIndex, tokens)
+}
+
+// IsNotTypeStartToken checks if the token at the given index can't start a type, which is a type keyword,
+// an identifier naming a struct, an open square bracket or a star, or if the index is out of bounds.
+func IsNotTypeStartToken(currentIndex int, tokens []Token) bool {
+	return IsNotTypeToken(currentIndex, tokens) && IsNotIdentifierToken(currentIndex, tokens) && IsNotOpenSquareBracketToken(currentIndex, tokens) && IsNotStarToken(currentIndex, tokens)
+}
+
+// IsCommaToken checks if the token at the given index is a comma.
+func IsCommaToken(currentIndex int, tokens []Token) bool {
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenCommaType
+}
+
+// IsQuestionMarkToken checks if the token at the given index is a question mark '?'.
+func IsQuestionMarkToken(currentIndex int, tokens []Token) bool {
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenQuestionMarkType
+}
+
+// IsColonToken checks if the token at the given index is a colon.
+func IsColonToken(currentIndex int, tokens []Token) bool {
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenColonType
+}
+
+// IsAsToken checks if the token at the given index is an 'as' keyword.
+func IsAsToken(currentIndex int, tokens []Token) bool {
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenAsType
+}
+
+// IsNotBoolLiteralToken checks if the token at the given index is not a 'true' or 'false' literal or if the index is out of bounds.
+func IsNotBoolLiteralToken(currentIndex int, tokens []Token) bool {
+	return currentIndex >= len(tokens) || (tokens[currentIndex].Type != TokenTrueType && tokens[currentIndex].Type != TokenFalseType)
 }
 
 // IsNotAddToken checks if the token at the given index is not an add sign or if the index is out of bounds.
@@ -657,7 +2633,7 @@ func IsNotAddToken(currentIndex int, tokens []Token) bool {
 	return currentIndex >= len(tokens) || tokens[currentIndex].Type != TokenAddType
 }
 
-// IsAddToken checks if the token at the given index is an add sign or if the index is out of bounds.
+// IsAddToken checks if the token at the given index is an add sign.
 func IsAddToken(currentIndex int, tokens []Token) bool {
-	return currentIndex >= len(tokens) || tokens[currentIndex].Type == TokenAddType
+	return currentIndex < len(tokens) && tokens[currentIndex].Type == TokenAddType
 }
@@ -0,0 +1,246 @@
+package lang
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Parser parses tokens into the abstract syntax tree of a program. Its fields tune which source
+// code it accepts and how many syntax errors it reports, e.g. to stop at the first error in an
+// editor or to reject inline IR in source code which isn't trusted.
+// The zero value parses like Parse.
+type Parser struct {
+	// MaxErrors is the number of syntax errors after which the parser stops parsing and returns
+	// the errors found. If it is 0, all syntax errors are returned.
+	MaxErrors int
+	// Strict reports the tokens which don't start a statement, e.g. a stray '}' or ';', as
+	// syntax errors. Otherwise, they are skipped.
+	Strict bool
+	// Disabled holds the language features the parser rejects.
+	Disabled LanguageFeatures
+	// MaxDepth is the maximum depth of nested bodies, values and types, e.g. of calls passed to
+	// calls, beyond which the parser reports that the nesting is too deep rather than overflowing
+	// the stack on adversarial input. If it is 0, DefaultMaxDepth is used.
+	MaxDepth int
+}
+
+// DefaultMaxDepth is the maximum nesting depth of a Parser which doesn't set one.
+const DefaultMaxDepth = 1000
+
+// LanguageFeatures is a set of optional language features, which a Parser can disable.
+type LanguageFeatures uint
+
+// Constants for the optional language features.
+const (
+	FeatureGenerics LanguageFeatures = 1 << iota // Functions with type parameters.
+	FeatureInlineIR                              // Blocks of inline LLVM IR.
+	FeatureSpawn                                 // Threads started with spawn.
+	FeatureAtomics                               // Atomic and volatile variables.
+	FeatureImports                               // Import declarations.
+	FeatureEmbeds                                // Files embedded with embed declarations.
+)
+
+// featureNames are the names of the language features.
+var featureNames = map[LanguageFeatures]string{
+	FeatureGenerics: "generics",
+	FeatureInlineIR: "inline_ir",
+	FeatureSpawn:    "spawn",
+	FeatureAtomics:  "atomics",
+	FeatureImports:  "imports",
+	FeatureEmbeds:   "embeds",
+}
+
+// String returns the names of the features of the set, separated by '|', e.g. generics|spawn.
+func (f LanguageFeatures) String() string {
+	var names []string
+	for feature, name := range featureNames {
+		if f&feature != 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, "|")
+}
+
+// parserState is the state of a parser while it parses tokens. Every parse has its own state, so
+// parsers with different configurations can parse at the same time. The zero value parses like Parse.
+type parserState struct {
+	Parser
+	errors  int  // The number of syntax errors found.
+	depth   int  // The nesting depth of the parsed body, value or type.
+	tooDeep bool // Whether the nesting was too deep, which stops the parser.
+	// resume reports whether the top-level statements from the token at the index on are reused,
+	// which stops the parser, see Reparse. It is nil if the parser parses all statements.
+	resume func(index int) bool
+}
+
+// Parse takes a slice of tokens as input and returns a slice of nodes representing the abstract
+// syntax tree, like Parse, with the configuration of the parser.
+func (p *Parser) Parse(tokens []Token) ([]Node, error) {
+	s := &parserState{Parser: *p}
+	nodes, _, err := s.parseNodes(tokens, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	name, nodes := splitPackage(nodes)
+	qualifyPackages([]packageFile{{Package: name, Nodes: nodes}})
+	if err := resolveTypeAliases(nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// ParseExpr tokenizes and parses the input as a single expression rather than a program, e.g.
+// "n + 1 < limit" or "f(x) as i64", for evaluating snippets of source code.
+//
+// Returns a syntax error if the input isn't exactly one expression.
+func ParseExpr(input string) (Expr, error) {
+	return (&Parser{}).ParseExpr(input)
+}
+
+// ParseExpr tokenizes and parses the input as a single expression like ParseExpr, with the
+// configuration of the parser.
+func (p *Parser) ParseExpr(input string) (Expr, error) {
+	s := &parserState{Parser: *p}
+	tokens := Tokenize(input)
+	value, index, err := s.parseValue(tokens, 0)
+	if err != nil {
+		return nil, err
+	}
+	if index < len(tokens) {
+		return nil, newParseError(tokens, index, MessageUnexpectedTokenAfterExpression, describeToken(tokens[index]), index)
+	}
+
+	// A spawn is the only language feature an expression may use
+	var disabled error
+	Walk(value, func(node Node) bool {
+		if feature, ok := s.feature(node); ok && disabled == nil {
+			index := tokenIndex(tokens, node.Pos())
+			disabled = newParseError(tokens, index, MessageFeatureDisabled, feature, index)
+		}
+		return disabled == nil
+	})
+	if disabled != nil {
+		return nil, disabled
+	}
+	return value, nil
+}
+
+// ParseFile reads, tokenizes and parses the gusty source file at the given path like Parse, and
+// returns the nodes of the file. Import declarations are kept, ParseProgram replaces them with the
+// imported files. The syntax errors name the file and the position of the error, e.g.
+// main.gusty:3:7: expected ')' after parameters at position 12, other errors the file.
+func ParseFile(path string) ([]Node, error) {
+	return (&Parser{}).ParseFile(path)
+}
+
+// ParseFile reads, tokenizes and parses the gusty source file at the given path like ParseFile,
+// with the configuration of the parser.
+func (p *Parser) ParseFile(path string) ([]Node, error) {
+	input, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := p.Parse(Tokenize(string(input)))
+	if err != nil {
+		return nil, fileError(path, err)
+	}
+	return nodes, nil
+}
+
+// fileError returns the error found parsing the file with the given name, with the name of the
+// file set on every syntax error or prefixed to the message of any other error.
+func fileError(filename string, err error) error {
+	switch e := err.(type) {
+	case SyntaxErrors:
+		errs := make(SyntaxErrors, len(e))
+		for i, syntaxError := range e {
+			errs[i] = fileError(filename, syntaxError)
+		}
+		return errs
+	case *ParseError:
+		e.Filename = filename
+		return e
+	}
+	return fmt.Errorf("%s: %w", filename, err)
+}
+
+// recover adds the error of the statement starting at the index to the syntax errors, which holds
+// the errors of its nested statements if it has a body, and returns the index of the token following
+// the statement, or the number of tokens if the parser stopped.
+func (s *parserState) recover(syntaxErrors *SyntaxErrors, err error, tokens []Token, index int) int {
+	if nested, ok := err.(SyntaxErrors); ok {
+		*syntaxErrors = append(*syntaxErrors, nested...)
+	} else {
+		s.addError(syntaxErrors, err)
+	}
+	if s.stopped() {
+		return len(tokens)
+	}
+	return skipStatement(tokens, index)
+}
+
+// addError adds the syntax error to the syntax errors and counts it for the maximum number of
+// errors of the parser.
+func (s *parserState) addError(syntaxErrors *SyntaxErrors, err error) {
+	*syntaxErrors = append(*syntaxErrors, err)
+	s.errors++
+}
+
+// stopped returns whether the parser found the maximum number of syntax errors or nesting which
+// is too deep.
+func (s *parserState) stopped() bool {
+	return s.tooDeep || s.MaxErrors > 0 && s.errors >= s.MaxErrors
+}
+
+// resumes reports whether the top-level statement starting at the index is the first of the
+// statements which are reused, so the parser stops before it.
+func (s *parserState) resumes(index int) bool {
+	return s.resume != nil && s.depth == 1 && s.resume(index)
+}
+
+// enter increases the nesting depth when the parser starts parsing a body, a value or a type at the
+// index, and returns an error if the nesting is too deep. Every call is followed by a call of leave.
+func (s *parserState) enter(tokens []Token, index int) error {
+	s.depth++
+	maxDepth := s.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if s.depth > maxDepth {
+		s.tooDeep = true
+		return newParseError(tokens, index, MessageNestingTooDeep, index, maxDepth)
+	}
+	return nil
+}
+
+// leave decreases the nesting depth when the parser finished parsing a body, a value or a type.
+func (s *parserState) leave() {
+	s.depth--
+}
+
+// feature returns the disabled language feature the statement uses, if any.
+func (s *parserState) feature(node Node) (LanguageFeatures, bool) {
+	var feature LanguageFeatures
+	switch n := node.(type) {
+	case *FunctionNode:
+		if len(n.TypeParameters) > 0 {
+			feature = FeatureGenerics
+		}
+	case *InlineIRNode:
+		feature = FeatureInlineIR
+	case *SpawnNode:
+		feature = FeatureSpawn
+	case *LetNode:
+		if n.Ordering != NotAtomic || n.Volatile {
+			feature = FeatureAtomics
+		}
+	case *ImportNode:
+		feature = FeatureImports
+	case *EmbedNode:
+		feature = FeatureEmbeds
+	}
+	return feature, s.Disabled&feature != 0
+}
@@ -0,0 +1,95 @@
+package lang
+
+import (
+	"sort"
+	"sync"
+
+	"tinygo.org/x/go-llvm"
+)
+
+// Pass is a compiler pass an external module can add to the compilation pipeline, e.g. to
+// instrument programs or to check domain-specific rules. Passes are registered with RegisterPass,
+// usually from the init function of the module, and are run if their name is listed in
+// Options.Passes. Either function may be nil.
+type Pass struct {
+	// AST is called with the nodes of the parsed program before code is generated. It returns the
+	// nodes to generate code for, which may be the changed nodes, or an error stopping the compilation.
+	AST func(nodes []Node) ([]Node, error)
+	// Module is called with the LLVM module generated for the program. It may change the module
+	// in place or return an error stopping the compilation.
+	Module func(module llvm.Module) error
+}
+
+// passes holds the registered passes by name.
+var passes = struct {
+	sync.RWMutex
+	byName map[string]Pass
+}{byName: make(map[string]Pass)}
+
+// RegisterPass makes a pass available under the given name. It panics if a pass is registered
+// twice under the same name or if both functions of the pass are nil.
+func RegisterPass(name string, pass Pass) {
+	passes.Lock()
+	defer passes.Unlock()
+	if pass.AST == nil && pass.Module == nil {
+		panic("lang: RegisterPass of pass " + name + " without functions")
+	}
+	if _, ok := passes.byName[name]; ok {
+		panic("lang: RegisterPass called twice for pass " + name)
+	}
+	passes.byName[name] = pass
+}
+
+// Passes returns the sorted names of the registered passes.
+func Passes() []string {
+	passes.RLock()
+	defer passes.RUnlock()
+	names := make([]string, 0, len(passes.byName))
+	for name := range passes.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupPasses returns the registered passes with the given names, in the same order.
+func lookupPasses(names []string) ([]Pass, error) {
+	passes.RLock()
+	defer passes.RUnlock()
+	selected := make([]Pass, len(names))
+	for i, name := range names {
+		pass, ok := passes.byName[name]
+		if !ok {
+			return nil, newError(MessageUnknownPass, name)
+		}
+		selected[i] = pass
+	}
+	return selected, nil
+}
+
+// runASTPasses runs the AST functions of the passes in order and returns the resulting nodes.
+func runASTPasses(selected []Pass, names []string, nodes []Node) ([]Node, error) {
+	for i, pass := range selected {
+		if pass.AST == nil {
+			continue
+		}
+		var err error
+		if nodes, err = pass.AST(nodes); err != nil {
+			return nil, newError(MessagePassFailed, names[i], err)
+		}
+	}
+	return nodes, nil
+}
+
+// runModulePasses runs the Module functions of the passes in order on the module.
+func runModulePasses(selected []Pass, names []string, module llvm.Module) error {
+	for i, pass := range selected {
+		if pass.Module == nil {
+			continue
+		}
+		if err := pass.Module(module); err != nil {
+			return newError(MessagePassFailed, names[i], err)
+		}
+	}
+	return nil
+}
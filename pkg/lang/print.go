@@ -0,0 +1,137 @@
+package lang
+
+import (
+	"strings"
+
+	"tinygo.org/x/go-llvm"
+)
+
+// Constants for the identifiers of the builtin functions printing a value, without and with a
+// trailing newline. Unlike printf, which prints bools as ints, they print bools as true or false.
+const (
+	printIdentifier   = "print"
+	printlnIdentifier = "println"
+)
+
+// formatIdentifier is the identifier of the builtin function formatting values into a new string.
+const formatIdentifier = "format"
+
+// snprintfIdentifier is the identifier of the C function formatting values into a buffer.
+const snprintfIdentifier = "snprintf"
+
+// printConversions maps the data types print and println accept to the printf conversion printing them.
+var printConversions = map[dataType]string{
+	Integer8Type:  "%d",
+	Integer16Type: "%d",
+	Integer32Type: "%d",
+	Integer64Type: "%ld",
+	Float32Type:   "%f",
+	Float64Type:   "%f",
+	BoolType:      "%s",
+	StringType:    "%s",
+}
+
+// Constants for the names of the globals holding the strings bools are printed as.
+const (
+	trueStringIdentifier  = runtimePrefix + "string_true"
+	falseStringIdentifier = runtimePrefix + "string_false"
+)
+
+// generatePrint is a function that generates LLVM IR code for a call of the print or println
+// builtin, which prints its parameter with the printf format string matching its data type, followed
+// by a newline for println. The call returns the number of bytes printed like printf.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+//
+// Returns an error if there isn't exactly one parameter or its value can't be printed.
+func generatePrint(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) != 1 {
+		return llvm.Value{}, 0, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+	}
+
+	value, valueType, err := generateValue(scope, functionBuilder, callerNode.Args[0])
+	if err != nil {
+		return llvm.Value{}, 0, err
+	}
+	conversion, ok := printConversions[valueType]
+	if !ok {
+		return llvm.Value{}, 0, newError(MessagePrintType, valueType)
+	}
+
+	if valueType == BoolType {
+		trueString := formatStringGlobal(functionBuilder, printfFormat{Name: trueStringIdentifier, Format: "true"})
+		falseString := formatStringGlobal(functionBuilder, printfFormat{Name: falseStringIdentifier, Format: "false"})
+		value = functionBuilder.CreateSelect(value, trueString, falseString, "")
+	} else {
+		value = promoteVariadicArgument(functionBuilder, value, valueType)
+	}
+
+	format := conversion
+	if callerNode.FunctionName == printlnIdentifier {
+		format += "\n"
+	}
+	formatGlobal := formatStringGlobal(functionBuilder, printfFormat{Name: formatStringIdentifier + "_" + callerNode.FunctionName + "_" + strings.TrimPrefix(conversion, "%"), Format: format})
+
+	printf, _ := globalScope.Callers.Get(printfIndentifier)
+	return functionBuilder.CreateCall(*printf.Type, *printf.Value, []llvm.Value{formatGlobal, value}, ""), Integer32Type, nil
+}
+
+// isFormatBuiltin reports whether a call of the name refers to the format builtin, which is the case
+// unless the program declares a function or an extern function named format.
+func isFormatBuiltin(scope *Scope, name string) bool {
+	return name == formatIdentifier && !declaresCaller(scope, name)
+}
+
+// generateFormat is a function that generates LLVM IR code for a call of the format builtin, which
+// formats the parameters following the format string like printf, e.g. format("x=%d", x), into a new
+// string allocated on the heap. The parameters are promoted like the parameters of printf. snprintf
+// is called twice, first to compute the length of the string and then to write it.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+//
+// Returns an error if the first parameter isn't a string or another parameter can't be formatted.
+func generateFormat(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) == 0 {
+		return llvm.Value{}, 0, newError(MessageExpectedFormatString, callerNode.FunctionName)
+	}
+	format, err := generateTypedValue(scope, functionBuilder, callerNode.Args[0], StringType)
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, 1, callerNode.FunctionName, err)
+	}
+
+	var arguments []llvm.Value
+	for i, arg := range callerNode.Args[1:] {
+		value, valueType, err := generateValue(scope, functionBuilder, arg)
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, i+2, callerNode.FunctionName, err)
+		}
+		if _, ok := printConversions[valueType]; !ok {
+			return llvm.Value{}, 0, newError(MessageFormatType, valueType)
+		}
+		arguments = append(arguments, promoteVariadicArgument(functionBuilder, value, valueType))
+	}
+
+	// Compute the length of the string without writing it
+	snprintfType, snprintf := snprintfFunction(functionBuilder)
+	null := llvm.ConstNull(llvm.PointerType(globalScope.Context.Int8Type(), 0))
+	zero := llvm.ConstInt(globalScope.Context.Int64Type(), 0, false)
+	length := functionBuilder.CreateCall(snprintfType, snprintf, append([]llvm.Value{null, zero, format}, arguments...), "length")
+
+	// Write the string and its terminating null byte into a buffer of that length
+	size := functionBuilder.CreateAdd(functionBuilder.CreateSExt(length, globalScope.Context.Int64Type(), ""), llvm.ConstInt(globalScope.Context.Int64Type(), 1, false), "size")
+	allocType, alloc := heapAllocFunction(functionBuilder)
+	buffer := functionBuilder.CreateCall(allocType, alloc, []llvm.Value{size}, "formatted")
+	functionBuilder.CreateCall(snprintfType, snprintf, append([]llvm.Value{buffer, size, format}, arguments...), "")
+	return buffer, StringType, nil
+}
+
+// snprintfFunction returns the type and the declaration of the C snprintf function.
+func snprintfFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	functionType := llvm.FunctionType(globalScope.Context.Int32Type(), []llvm.Type{pointerType, globalScope.Context.Int64Type(), pointerType}, true)
+	return runtimeFunction(functionBuilder, snprintfIdentifier, functionType, nil)
+}
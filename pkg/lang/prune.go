@@ -0,0 +1,73 @@
+package lang
+
+import "strings"
+
+// PruneUnreachable removes every function from the program which can't be called from main, which
+// runs the top-level statements of the program and the main function it declares, if any. A function
+// is reachable if it is main, a top-level statement or a reachable function refers to its name;
+// names are resolved like the minifier sees them, so a variable with the name of a function
+// conservatively keeps the function. Inline LLVM IR may call every function, so a function whose
+// symbol, e.g. @f, occurs in any inline block is reachable.
+//
+// PruneUnreachable returns the remaining nodes, in their original order, and the names of the
+// removed functions in declaration order.
+func PruneUnreachable(nodes []Node) ([]Node, []string) {
+	functions := make(map[string]*FunctionNode)
+	var entry []Node
+	for _, node := range nodes {
+		if functionNode, ok := node.(*FunctionNode); ok {
+			functions[functionNode.Name] = functionNode
+			continue
+		}
+		entry = append(entry, node)
+	}
+
+	reachable := make(map[string]bool)
+	var pending []string
+	var m minifier
+	refer := func(name *string, declaration bool) {
+		if _, ok := functions[*name]; ok && !declaration && !reachable[*name] {
+			reachable[*name] = true
+			pending = append(pending, *name)
+		}
+	}
+	m.walkNodes(entry, refer)
+	// The synthesized main function calls a main function the program declares
+	main := mainIdentifier
+	refer(&main, false)
+	for _, ir := range inlineIR(nodes) {
+		for name := range functions {
+			if strings.Contains(ir, "@"+name) {
+				refer(&name, false)
+			}
+		}
+	}
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		m.walkNodes(functions[name].Body, refer)
+	}
+
+	kept := make([]Node, 0, len(nodes))
+	var pruned []string
+	for _, node := range nodes {
+		if functionNode, ok := node.(*FunctionNode); ok && !reachable[functionNode.Name] {
+			pruned = append(pruned, functionNode.Name)
+			continue
+		}
+		kept = append(kept, node)
+	}
+	return kept, pruned
+}
+
+// inlineIR returns the LLVM IR of every inline block in the nodes, including nested bodies.
+func inlineIR(nodes []Node) []string {
+	var blocks []string
+	WalkNodes(nodes, func(node Node) bool {
+		if n, ok := node.(*InlineIRNode); ok {
+			blocks = append(blocks, n.IR)
+		}
+		return true
+	})
+	return blocks
+}
@@ -0,0 +1,67 @@
+package lang
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RenderDiagnostic renders the diagnostic found in the source code of the file with the given name
+// for a terminal: the line of the source code it starts in, prefixed with its number, the span of
+// the diagnostic underlined beneath it with ^~~~, and the message beneath the underline, e.g.
+//
+//	1 | let x: i32 = true
+//	  |              ^~~~
+//	main.gusty:1:14: error: invalid value for let node x: cannot use true as i32 value
+//
+// A span continuing on the following lines is underlined to the end of its first line. The name
+// of the file may be empty. A diagnostic without a position, or a position the source code doesn't
+// have, is rendered as its message only. The rendered diagnostic ends with a newline.
+func RenderDiagnostic(filename, source string, diagnostic Diagnostic) string {
+	var b strings.Builder
+	from := diagnostic.From
+	lines := strings.Split(source, "\n")
+	if from.IsValid() && from.Line <= len(lines) {
+		line := []rune(strings.TrimSuffix(lines[from.Line-1], "\r"))
+		start := from.Column - 1
+		if start > len(line) {
+			start = len(line)
+		}
+		end := len(line)
+		if diagnostic.To.Line == from.Line && diagnostic.To.Column-1 < end {
+			end = diagnostic.To.Column - 1
+		}
+		width := end - start
+		if width < 1 {
+			width = 1
+		}
+
+		number := strconv.Itoa(from.Line)
+		gutter := strings.Repeat(" ", len(number))
+		fmt.Fprintf(&b, "%s | %s\n", number, string(line))
+		fmt.Fprintf(&b, "%s | %s^%s\n", gutter, indentation(line[:start]), strings.Repeat("~", width-1))
+	}
+
+	if filename != "" {
+		b.WriteString(filename + ":")
+	}
+	if from.IsValid() {
+		fmt.Fprintf(&b, "%s: ", from)
+	}
+	fmt.Fprintf(&b, "%s: %v\n", diagnostic.Severity, diagnostic.Err)
+	return b.String()
+}
+
+// indentation returns the runes as spaces, keeping tabs, so text following it is aligned with the
+// rune following the runes.
+func indentation(runes []rune) string {
+	var b strings.Builder
+	for _, r := range runes {
+		if r == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,332 @@
+package lang
+
+import "fmt"
+
+// ScopeKind is the kind of a scope of a program, see Resolve.
+type ScopeKind int
+
+const (
+	// ScopeGlobal is the scope of the values every function can use: embedded files and thread-local
+	// variables.
+	ScopeGlobal ScopeKind = iota
+	// ScopeFunction is the scope of the parameters and the variables of a function. The top-level
+	// statements have the scope of the synthesized main function.
+	ScopeFunction
+	// ScopeLoop is the scope of the loop variable of a for loop.
+	ScopeLoop
+	// ScopeBlock is the scope of the variables declared in the body of a loop.
+	ScopeBlock
+)
+
+// String returns the name of the scope kind, e.g. function.
+func (k ScopeKind) String() string {
+	switch k {
+	case ScopeGlobal:
+		return "global"
+	case ScopeFunction:
+		return "function"
+	case ScopeLoop:
+		return "loop"
+	case ScopeBlock:
+		return "block"
+	}
+	return fmt.Sprintf("ScopeKind(%d)", int(k))
+}
+
+// SymbolKind is the kind of a declaration of a program.
+type SymbolKind int
+
+const (
+	SymbolVariable  SymbolKind = iota // A variable declared by a let statement or a for loop.
+	SymbolParameter                   // A parameter of a function.
+	SymbolEmbed                       // An embedded file.
+	SymbolFunction                    // A function or an extern function.
+	SymbolStruct                      // A struct.
+	SymbolTypeAlias                   // A type alias.
+)
+
+// String returns the name of the symbol kind, e.g. variable.
+func (k SymbolKind) String() string {
+	switch k {
+	case SymbolVariable:
+		return "variable"
+	case SymbolParameter:
+		return "parameter"
+	case SymbolEmbed:
+		return "embed"
+	case SymbolFunction:
+		return "function"
+	case SymbolStruct:
+		return "struct"
+	case SymbolTypeAlias:
+		return "type alias"
+	}
+	return fmt.Sprintf("SymbolKind(%d)", int(k))
+}
+
+// Symbol is a declaration of a program and the nodes using it.
+type Symbol struct {
+	Name string
+	Kind SymbolKind
+	// Node is the node declaring the symbol: a *LetNode, *ShortVariableAssigmentNode, *Parameter,
+	// *EmbedNode, *FunctionNode, *ExternNode, *StructNode or *TypeAliasNode.
+	Node  Node
+	Scope *SymbolScope // The scope declaring the value, nil for functions and types.
+	Uses  []Node       // The nodes using the symbol, in the order of the source code.
+}
+
+// SymbolScope is a scope of a program, which declares values and holds the scopes nested in it.
+type SymbolScope struct {
+	Kind ScopeKind
+	// Node is the node the scope belongs to: the *FunctionNode, *ForNode or *WhileNode, nil for the
+	// global scope and the scope of the top-level statements.
+	Node     Node
+	Parent   *SymbolScope
+	Children []*SymbolScope
+	Values   *Symbols[*Symbol] // The values declared in the scope, by name.
+}
+
+// Lookup returns the value with the name declared in the scope or in the innermost scope
+// enclosing it, or nil.
+func (s *SymbolScope) Lookup(name string) *Symbol {
+	for ; s != nil; s = s.Parent {
+		if symbol, ok := s.Values.Get(name); ok {
+			return symbol
+		}
+	}
+	return nil
+}
+
+// Duplicate is a declaration of a name which was declared before in the same scope, or for the
+// whole program, e.g. a second function with the same name.
+type Duplicate struct {
+	Symbol   *Symbol // The declaration.
+	Previous *Symbol // The declaration of the name before.
+}
+
+// Resolution is the result of Resolve: the scopes and declarations of a program and the
+// declaration every use of a name binds to.
+type Resolution struct {
+	Global    *SymbolScope      // The global scope, the root of every other scope.
+	Functions *Symbols[*Symbol] // The functions and extern functions, by name.
+	Types     *Symbols[*Symbol] // The structs and type aliases, by name.
+	// Declarations holds the symbol every declaring node declares.
+	Declarations map[Node]*Symbol
+	// Bindings holds the symbol every node using a name binds to: an *IdentifierNode,
+	// *AssignmentNode, *CallerNode, *StructLiteralNode, *ConditionNode, *PostNode or *WhileNode.
+	Bindings map[Node]*Symbol
+	// Scopes holds the scope every node using a name is resolved in.
+	Scopes map[Node]*SymbolScope
+	// Unresolved holds the nodes using a name which isn't declared, in the order of the source code.
+	Unresolved []Node
+	// Duplicates holds the declarations of names which were declared before, in the order of the
+	// source code. Functions and extern functions share their names, as do structs and type aliases.
+	Duplicates []Duplicate
+}
+
+// Resolve builds the scopes of a program and binds every use of a name to its declaration.
+//
+// The code generation looks up the variables, parameters and embedded files the nodes use by
+// their bindings, so the rules are those of the code generation: functions, extern functions and
+// types are declared for the whole program, so they can be used before their declaration, and a
+// call binds to the function of the program with the name, if there is one. Calls of builtins and
+// conversions to the type parameters of generic functions don't bind. Values are declared from
+// the statement declaring them on, in the innermost scope: embedded files and thread-local
+// variables in the global scope, the other top-level variables in the scope of the synthesized
+// main function, which functions don't see. A name is looked up from the innermost scope
+// outwards, so a declaration shadows the declarations of the enclosing scopes until its scope
+// ends, e.g. a variable of a for loop the variable of the function with the same name, see
+// Shadowed, and a declaration of a name the same scope declares already is a duplicate, which
+// replaces it for the following statements. The value of a let statement is resolved before its
+// variable is declared, e.g. let x = x + 1 uses the x declared before.
+//
+// Returns an error if the nodes aren't valid, see Validate.
+func Resolve(nodes []Node) (*Resolution, error) {
+	if err := Validate(nodes); err != nil {
+		return nil, err
+	}
+
+	r := resolver{Resolution: &Resolution{
+		Global:       &SymbolScope{Kind: ScopeGlobal, Values: newSymbols[*Symbol]()},
+		Functions:    newSymbols[*Symbol](),
+		Types:        newSymbols[*Symbol](),
+		Declarations: make(map[Node]*Symbol),
+		Bindings:     make(map[Node]*Symbol),
+		Scopes:       make(map[Node]*SymbolScope),
+	}}
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *FunctionNode:
+			r.add(r.Functions, r.newSymbol(n.Name, SymbolFunction, n, nil))
+		case *ExternNode:
+			r.add(r.Functions, r.newSymbol(n.Name, SymbolFunction, n, nil))
+		case *StructNode:
+			r.add(r.Types, r.newSymbol(n.Name, SymbolStruct, n, nil))
+		case *TypeAliasNode:
+			r.add(r.Types, r.newSymbol(n.Name, SymbolTypeAlias, n, nil))
+		case *EmbedNode:
+			r.declare(r.Global, n.Identifier, SymbolEmbed, n)
+		case *LetNode:
+			if n.ThreadLocal {
+				r.resolveValue(r.Global, n.Value)
+				r.declare(r.Global, n.Identifier, SymbolVariable, n)
+			}
+		}
+	}
+
+	main := r.newScope(r.Global, ScopeFunction, nil)
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *FunctionNode:
+			r.resolveFunction(n)
+		case *LetNode:
+			if !n.ThreadLocal {
+				r.resolveStatement(main, n)
+			}
+		default:
+			r.resolveStatement(main, n)
+		}
+	}
+	return r.Resolution, nil
+}
+
+// resolver is the state of Resolve while it resolves a program.
+type resolver struct {
+	*Resolution
+	function *FunctionNode // The function being resolved, nil for the top-level statements.
+}
+
+// resolveFunction declares the parameters of the function in a new function scope of the global
+// scope and resolves its body in it, e.g. of an instantiation of a generic function, which isn't
+// part of the resolved program.
+func (r *resolver) resolveFunction(functionNode *FunctionNode) {
+	scope := r.newScope(r.Global, ScopeFunction, functionNode)
+	for _, parameter := range functionNode.Parameters {
+		r.declare(scope, parameter.Identifier, SymbolParameter, parameter)
+	}
+	r.function = functionNode
+	r.resolveStatements(scope, functionNode.Body)
+	r.function = nil
+}
+
+// isTypeParameter reports whether the name is a type parameter of the function being resolved,
+// which converts values when it is called, e.g. U(x).
+func (r *resolver) isTypeParameter(name string) bool {
+	if r.function == nil {
+		return false
+	}
+	for _, typeParameter := range r.function.TypeParameters {
+		if typeParameter == name {
+			return true
+		}
+	}
+	return false
+}
+
+// newSymbol returns the symbol of the declaration and records its declaring node.
+func (r *resolver) newSymbol(name string, kind SymbolKind, node Node, scope *SymbolScope) *Symbol {
+	symbol := &Symbol{Name: name, Kind: kind, Node: node, Scope: scope}
+	r.Declarations[node] = symbol
+	return symbol
+}
+
+// newScope returns a new scope nested in the parent scope.
+func (r *resolver) newScope(parent *SymbolScope, kind ScopeKind, node Node) *SymbolScope {
+	scope := &SymbolScope{Kind: kind, Node: node, Parent: parent, Values: newSymbols[*Symbol]()}
+	parent.Children = append(parent.Children, scope)
+	return scope
+}
+
+// declare declares the value in the scope.
+func (r *resolver) declare(scope *SymbolScope, name string, kind SymbolKind, node Node) {
+	r.add(scope.Values, r.newSymbol(name, kind, node, scope))
+}
+
+// add adds the symbol to the symbols, replacing and recording a duplicate declaration of its name.
+func (r *resolver) add(symbols *Symbols[*Symbol], symbol *Symbol) {
+	if previous, ok := symbols.Get(symbol.Name); ok {
+		r.Duplicates = append(r.Duplicates, Duplicate{Symbol: symbol, Previous: previous})
+	}
+	symbols.Set(symbol.Name, symbol)
+}
+
+// bind binds the node using a name in the scope to the symbol, or records it as unresolved if the
+// symbol is nil.
+func (r *resolver) bind(scope *SymbolScope, node Node, symbol *Symbol) {
+	r.Scopes[node] = scope
+	if symbol == nil {
+		r.Unresolved = append(r.Unresolved, node)
+		return
+	}
+	r.Bindings[node] = symbol
+	symbol.Uses = append(symbol.Uses, node)
+}
+
+// resolveStatements resolves the statements of a body in the scope.
+func (r *resolver) resolveStatements(scope *SymbolScope, nodes []Node) {
+	for _, node := range nodes {
+		r.resolveStatement(scope, node)
+	}
+}
+
+// resolveStatement resolves the names the statement uses and declares its variables in the scope.
+func (r *resolver) resolveStatement(scope *SymbolScope, node Node) {
+	switch n := node.(type) {
+	case *LetNode:
+		r.resolveValue(scope, n.Value)
+		r.declare(scope, n.Identifier, SymbolVariable, n)
+	case *AssignmentNode:
+		r.resolveValue(scope, n.Value)
+		r.bind(scope, n, scope.Lookup(n.Identifier))
+	case *IndexAssignmentNode:
+		r.resolveValue(scope, n.Target)
+		r.resolveValue(scope, n.Value)
+	case *FieldAssignmentNode:
+		r.resolveValue(scope, n.Target)
+		r.resolveValue(scope, n.Value)
+	case *DereferenceAssignmentNode:
+		r.resolveValue(scope, n.Target)
+		r.resolveValue(scope, n.Value)
+	case *ReturnNode:
+		if n.Value != nil {
+			r.resolveValue(scope, n.Value)
+		}
+	case *CallerNode:
+		r.resolveValue(scope, n)
+	case *SpawnNode:
+		r.resolveValue(scope, n.Call)
+	case *AddOperationNode:
+		r.resolveValue(scope, n)
+	case *ForNode:
+		loop := r.newScope(scope, ScopeLoop, n)
+		r.resolveValue(scope, n.Init.Value)
+		r.declare(loop, n.Init.Identifier, SymbolVariable, &n.Init)
+		r.bind(loop, &n.Condition, loop.Lookup(n.Condition.LeftValue))
+		r.resolveValue(loop, n.Condition.RightValue)
+		r.bind(loop, &n.Post, loop.Lookup(n.Post.Identifier))
+		r.resolveStatements(r.newScope(loop, ScopeBlock, nil), n.Body)
+	case *WhileNode:
+		r.bind(scope, n, scope.Lookup(n.Condition))
+		loop := r.newScope(scope, ScopeLoop, n)
+		r.resolveStatements(r.newScope(loop, ScopeBlock, nil), n.Body)
+	}
+}
+
+// resolveValue resolves the names the value and its nested values use in the scope.
+func (r *resolver) resolveValue(scope *SymbolScope, value Expr) {
+	Walk(value, func(node Node) bool {
+		switch v := node.(type) {
+		case *IdentifierNode:
+			r.bind(scope, v, scope.Lookup(v.Name))
+		case *CallerNode:
+			symbol, ok := r.Functions.Get(v.FunctionName)
+			if !callsBuiltin(v.FunctionName, ok) && !r.isTypeParameter(v.FunctionName) {
+				r.bind(scope, v, symbol)
+			}
+		case *StructLiteralNode:
+			symbol, _ := r.Types.Get(v.Name)
+			r.bind(scope, v, symbol)
+		}
+		return true
+	})
+}
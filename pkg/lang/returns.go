@@ -0,0 +1,22 @@
+package lang
+
+// checkReturns reports a function with a return type whose body can end without returning a
+// value. A body returns if one of its statements, not nested in a loop, which may not run, is a
+// return or a call of the exit builtin. Functions declared @noreturn don't return at all.
+func (c *checker) checkReturns(function *FunctionNode) {
+	if function.ReturnType == VoidType || hasAttribute(function.Attributes, noreturnAttribute) {
+		return
+	}
+	_, declared := c.resolution.Functions.Get(exitIdentifier)
+	for _, node := range function.Body {
+		switch n := node.(type) {
+		case *ReturnNode:
+			return
+		case *CallerNode:
+			if n.FunctionName == exitIdentifier && !declared {
+				return
+			}
+		}
+	}
+	c.report(function, newError(MessageMissingReturn, function.Name))
+}
@@ -0,0 +1,193 @@
+package lang
+
+import (
+	"tinygo.org/x/go-llvm"
+)
+
+// Constants for the identifiers of the functions the generated code relies on at runtime.
+const (
+	// trapIdentifier is the identifier of the LLVM intrinsic aborting the program.
+	trapIdentifier = "llvm.trap"
+	// mallocIdentifier is the identifier of the C function allocating heap memory.
+	mallocIdentifier = "malloc"
+	// reallocIdentifier is the identifier of the C function resizing heap memory.
+	reallocIdentifier = "realloc"
+	// sliceReserveIdentifier is the identifier of the runtime helper growing the backing array of a slice.
+	sliceReserveIdentifier = runtimePrefix + "slice_reserve"
+	// strlenIdentifier is the identifier of the C function returning the length of a string.
+	strlenIdentifier = "strlen"
+	// memcpyIdentifier is the identifier of the C function copying memory.
+	memcpyIdentifier = "memcpy"
+	// stringConcatIdentifier is the identifier of the runtime helper concatenating two strings.
+	stringConcatIdentifier = runtimePrefix + "string_concat"
+)
+
+// Constants for the positions of the fields of the LLVM struct representing a slice.
+const (
+	sliceData     = iota // The pointer to the backing array.
+	sliceLength          // The number of elements in the slice.
+	sliceCapacity        // The number of elements the backing array can hold.
+)
+
+// Constants for the positions of the fields of the LLVM struct representing an option.
+const (
+	optionPresent = iota // Whether the option holds a value.
+	optionValue          // The value, zero if the option holds no value.
+)
+
+// optionStructType returns the LLVM struct type representing options of the option type, { i1, T }.
+func optionStructType(t OptionType) llvm.Type {
+	return globalScope.Context.StructType([]llvm.Type{globalScope.Context.Int1Type(), llvmType(t.Element)}, false)
+}
+
+// Constants for the positions of the fields of the LLVM struct representing a result. They match
+// the positions of the fields of options, so both are unwrapped the same way.
+const (
+	resultOk    = iota // Whether the result holds a value rather than an error.
+	resultValue        // The value, zero if the result holds an error.
+	resultError        // The error code, zero if the result holds a value.
+)
+
+// resultStructType returns the LLVM struct type representing results of the result type, { i1, T, i32 }.
+func resultStructType(t ResultType) llvm.Type {
+	return globalScope.Context.StructType([]llvm.Type{globalScope.Context.Int1Type(), llvmType(t.Element), globalScope.Context.Int32Type()}, false)
+}
+
+// sliceInitialCapacity is the capacity of the backing array allocated when appending to an empty slice.
+const sliceInitialCapacity = 4
+
+// sliceStructType returns the LLVM struct type representing every slice, { ptr, i64, i64 }.
+func sliceStructType() llvm.Type {
+	return globalScope.Context.StructType([]llvm.Type{llvm.PointerType(globalScope.Context.Int8Type(), 0), globalScope.Context.Int64Type(), globalScope.Context.Int64Type()}, false)
+}
+
+// runtimeFunction returns the function with the given name and type of the module of the current function.
+// The function is added to the module the first time it is requested, generate is called to generate its
+// body, a nil generate leaves it as a declaration.
+func runtimeFunction(functionBuilder llvm.Builder, name string, functionType llvm.Type, generate func(function llvm.Value)) (llvm.Type, llvm.Value) {
+	module := functionBuilder.GetInsertBlock().Parent().GlobalParent()
+	if function := module.NamedFunction(name); !function.IsNil() {
+		return functionType, function
+	}
+
+	function := llvm.AddFunction(module, name, functionType)
+	if generate != nil {
+		function.SetLinkage(llvm.InternalLinkage)
+		generate(function)
+	}
+	return functionType, function
+}
+
+// trapFunction returns the type and the declaration of the llvm.trap intrinsic, which aborts the program.
+func trapFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	return runtimeFunction(functionBuilder, trapIdentifier, llvm.FunctionType(globalScope.Context.VoidType(), []llvm.Type{}, false), nil)
+}
+
+// mallocFunction returns the type and the declaration of the C malloc function.
+func mallocFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	return runtimeFunction(functionBuilder, mallocIdentifier, llvm.FunctionType(pointerType, []llvm.Type{globalScope.Context.Int64Type()}, false), nil)
+}
+
+// freeFunction returns the type and the declaration of the C free function.
+func freeFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	return runtimeFunction(functionBuilder, freeIdentifier, llvm.FunctionType(globalScope.Context.VoidType(), []llvm.Type{pointerType}, false), nil)
+}
+
+// reallocFunction returns the type and the declaration of the C realloc function.
+func reallocFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	return runtimeFunction(functionBuilder, reallocIdentifier, llvm.FunctionType(pointerType, []llvm.Type{pointerType, globalScope.Context.Int64Type()}, false), nil)
+}
+
+// sliceReserveFunction returns the type and the definition of the runtime helper which makes room for
+// one more element in a slice. It takes the slice and the size of its elements in bytes and returns
+// the slice unchanged if its length is smaller than its capacity. Otherwise the backing array is
+// reallocated with twice the capacity, or sliceInitialCapacity elements for an empty slice.
+func sliceReserveFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	sliceType := sliceStructType()
+	functionType := llvm.FunctionType(sliceType, []llvm.Type{sliceType, globalScope.Context.Int64Type()}, false)
+
+	return runtimeFunction(functionBuilder, sliceReserveIdentifier, functionType, func(function llvm.Value) {
+		builder := globalScope.Context.NewBuilder()
+		defer builder.Dispose()
+
+		slice := function.Param(0)
+		elementSize := function.Param(1)
+
+		entry := globalScope.Context.AddBasicBlock(function, "entry")
+		grow := globalScope.Context.AddBasicBlock(function, "grow")
+		done := globalScope.Context.AddBasicBlock(function, "done")
+
+		// Check whether the backing array is full
+		builder.SetInsertPointAtEnd(entry)
+		length := builder.CreateExtractValue(slice, sliceLength, "length")
+		capacity := builder.CreateExtractValue(slice, sliceCapacity, "capacity")
+		full := builder.CreateICmp(llvm.IntEQ, length, capacity, "full")
+		builder.CreateCondBr(full, grow, done)
+
+		// Reallocate the backing array with the new capacity
+		builder.SetInsertPointAtEnd(grow)
+		empty := builder.CreateICmp(llvm.IntEQ, capacity, llvm.ConstInt(globalScope.Context.Int64Type(), 0, false), "empty")
+		doubled := builder.CreateMul(capacity, llvm.ConstInt(globalScope.Context.Int64Type(), 2, false), "doubled")
+		newCapacity := builder.CreateSelect(empty, llvm.ConstInt(globalScope.Context.Int64Type(), sliceInitialCapacity, false), doubled, "new_capacity")
+		size := builder.CreateMul(newCapacity, elementSize, "size")
+		reallocType, realloc := heapReallocFunction(builder)
+		data := builder.CreateCall(reallocType, realloc, []llvm.Value{builder.CreateExtractValue(slice, sliceData, "data"), size}, "new_data")
+		grown := builder.CreateInsertValue(slice, data, sliceData, "")
+		grown = builder.CreateInsertValue(grown, newCapacity, sliceCapacity, "")
+		builder.CreateRet(grown)
+
+		// Return the slice unchanged
+		builder.SetInsertPointAtEnd(done)
+		builder.CreateRet(slice)
+	})
+}
+
+// strlenFunction returns the type and the declaration of the C strlen function.
+func strlenFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	return runtimeFunction(functionBuilder, strlenIdentifier, llvm.FunctionType(globalScope.Context.Int64Type(), []llvm.Type{pointerType}, false), nil)
+}
+
+// memcpyFunction returns the type and the declaration of the C memcpy function.
+func memcpyFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	return runtimeFunction(functionBuilder, memcpyIdentifier, llvm.FunctionType(pointerType, []llvm.Type{pointerType, pointerType, globalScope.Context.Int64Type()}, false), nil)
+}
+
+// stringConcatFunction returns the type and the definition of the runtime helper which concatenates
+// two strings. It allocates a new string holding the bytes of the first string followed by the bytes
+// and the terminating null byte of the second string, the operands are left unchanged.
+func stringConcatFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	functionType := llvm.FunctionType(pointerType, []llvm.Type{pointerType, pointerType}, false)
+
+	return runtimeFunction(functionBuilder, stringConcatIdentifier, functionType, func(function llvm.Value) {
+		builder := globalScope.Context.NewBuilder()
+		defer builder.Dispose()
+
+		left := function.Param(0)
+		right := function.Param(1)
+
+		entry := globalScope.Context.AddBasicBlock(function, "entry")
+		builder.SetInsertPointAtEnd(entry)
+
+		// Allocate room for both strings and the null byte
+		strlenType, strlen := strlenFunction(builder)
+		leftLength := builder.CreateCall(strlenType, strlen, []llvm.Value{left}, "left_length")
+		rightLength := builder.CreateCall(strlenType, strlen, []llvm.Value{right}, "right_length")
+		rightSize := builder.CreateAdd(rightLength, llvm.ConstInt(globalScope.Context.Int64Type(), 1, false), "right_size")
+		size := builder.CreateAdd(leftLength, rightSize, "size")
+		mallocType, malloc := heapAllocFunction(builder)
+		data := builder.CreateCall(mallocType, malloc, []llvm.Value{size}, "data")
+
+		// Copy the first string and then the second one including its null byte
+		memcpyType, memcpy := memcpyFunction(builder)
+		builder.CreateCall(memcpyType, memcpy, []llvm.Value{data, left, leftLength}, "")
+		end := builder.CreateInBoundsGEP(globalScope.Context.Int8Type(), data, []llvm.Value{leftLength}, "end")
+		builder.CreateCall(memcpyType, memcpy, []llvm.Value{end, right, rightSize}, "")
+		builder.CreateRet(data)
+	})
+}
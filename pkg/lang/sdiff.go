@@ -0,0 +1,240 @@
+package lang
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ChangeKind is the kind of a change between two versions of a program.
+type ChangeKind string
+
+// Constants for the kinds of changes reported by SemanticDiff and DiffAST.
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is a difference between two versions of a program found by SemanticDiff or DiffAST.
+type Change struct {
+	Kind        ChangeKind
+	Declaration string // The kind of the declaration, e.g. function or struct, or main for the top-level statements. DiffAST reports the kind of the node, e.g. LetNode.
+	Name        string // The name of the declaration, e.g. the name and signature of a function. DiffAST reports the path of the node, e.g. [2].Body[0].Value.
+	Detail      string // How a changed declaration changed, e.g. its signature.
+	Before      Node   // Before is the node before the change, which DiffAST sets unless the node was added.
+	After       Node   // After is the node after the change, which DiffAST sets unless the node was removed.
+}
+
+// String returns the change as a line of a semantic diff, e.g. "+ function add(a i32, b i32) i32"
+// or "~ struct Point: fields changed".
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s %s", c.Declaration, c.Name)
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s %s", c.Declaration, c.Name)
+	}
+	if c.Declaration == mainDeclaration {
+		return fmt.Sprintf("~ %s: %s", c.Declaration, c.Detail)
+	}
+	return fmt.Sprintf("~ %s %s: %s", c.Declaration, c.Name, c.Detail)
+}
+
+// mainDeclaration is the kind of declaration the changes of the top-level statements are reported by.
+const mainDeclaration = "main"
+
+// declaration is a top-level declaration of a program compared by SemanticDiff.
+type declaration struct {
+	Kind string // The kind of the declaration, e.g. function.
+	Name string // The name of the declaration.
+	Node Node
+}
+
+// SemanticDiff compares the abstract syntax trees of two versions of a program and returns the
+// functions, structs, type aliases and embeds which were removed, changed or added, in the order
+// they are declared. Removed and changed declarations come first, in the order of the old version,
+// followed by the added declarations in the order of the new version, and a change of the top-level
+// statements. As the trees are compared, changes of the formatting of the source code are ignored.
+func SemanticDiff(before, after []Node) []Change {
+	oldDeclarations, oldStatements := declarations(before)
+	newDeclarations, newStatements := declarations(after)
+
+	byName := make(map[string]declaration, len(newDeclarations))
+	for _, d := range newDeclarations {
+		byName[d.Kind+" "+d.Name] = d
+	}
+	var changes []Change
+	compared := make(map[string]bool)
+	for _, d := range oldDeclarations {
+		key := d.Kind + " " + d.Name
+		compared[key] = true
+		other, ok := byName[key]
+		if !ok {
+			changes = append(changes, Change{Kind: ChangeRemoved, Declaration: d.Kind, Name: declarationName(d)})
+			continue
+		}
+		if detail := declarationChange(d.Node, other.Node); detail != "" {
+			changes = append(changes, Change{Kind: ChangeChanged, Declaration: d.Kind, Name: d.Name, Detail: detail})
+		}
+	}
+	for _, d := range newDeclarations {
+		if !compared[d.Kind+" "+d.Name] {
+			changes = append(changes, Change{Kind: ChangeAdded, Declaration: d.Kind, Name: declarationName(d)})
+		}
+	}
+
+	if !equalSyntax(oldStatements, newStatements) {
+		changes = append(changes, Change{Kind: ChangeChanged, Declaration: mainDeclaration, Detail: "top-level statements changed"})
+	}
+	return changes
+}
+
+// declarations returns the top-level declarations of the nodes and the other top-level nodes,
+// which are the statements of the main function.
+func declarations(nodes []Node) ([]declaration, []Node) {
+	var declared []declaration
+	var statements []Node
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *FunctionNode:
+			declared = append(declared, declaration{Kind: "function", Name: n.Name, Node: n})
+		case *StructNode:
+			declared = append(declared, declaration{Kind: "struct", Name: n.Name, Node: n})
+		case *ExternNode:
+			declared = append(declared, declaration{Kind: "extern function", Name: n.Name, Node: n})
+		case *TypeAliasNode:
+			declared = append(declared, declaration{Kind: "type", Name: n.Name, Node: n})
+		case *EmbedNode:
+			declared = append(declared, declaration{Kind: "embed", Name: n.Identifier, Node: n})
+		default:
+			statements = append(statements, node)
+		}
+	}
+	return declared, statements
+}
+
+// declarationName returns the name a declaration is reported by, which is the signature of a function.
+func declarationName(d declaration) string {
+	switch n := d.Node.(type) {
+	case *FunctionNode:
+		return functionSignature(n)
+	case *ExternNode:
+		return externSignature(n)
+	}
+	return d.Name
+}
+
+// functionSignature returns the signature of the function, e.g. add(a i32, b i32) i32 or, for a
+// generic function, max<T>(a T, b T) T.
+func functionSignature(functionNode *FunctionNode) string {
+	parameters := make([]string, len(functionNode.Parameters))
+	for i, parameter := range functionNode.Parameters {
+		parameters[i] = parameter.Identifier + " " + parameter.Type.String()
+	}
+	signature := functionNode.Name
+	if len(functionNode.TypeParameters) > 0 {
+		signature += "<" + strings.Join(functionNode.TypeParameters, ", ") + ">"
+	}
+	signature += "(" + strings.Join(parameters, ", ") + ")"
+	if functionNode.ReturnType != VoidType {
+		signature += " " + functionNode.ReturnType.String()
+	}
+	return signature
+}
+
+// externSignature returns the signature of the extern function, e.g. puts(s string) i32.
+func externSignature(externNode *ExternNode) string {
+	return functionSignature(&FunctionNode{Name: externNode.Name, Parameters: externNode.Parameters, ReturnType: externNode.ReturnType})
+}
+
+// declarationChange returns how the declaration changed between the node before and the node
+// after the change, or the empty string if it didn't change.
+func declarationChange(before, after Node) string {
+	if equalSyntax(before, after) {
+		return ""
+	}
+	switch b := before.(type) {
+	case *FunctionNode:
+		if oldSignature, newSignature := functionSignature(b), functionSignature(after.(*FunctionNode)); oldSignature != newSignature {
+			return fmt.Sprintf("signature changed from %s to %s", oldSignature, newSignature)
+		}
+		if !reflect.DeepEqual(b.Attributes, after.(*FunctionNode).Attributes) {
+			return "attributes changed"
+		}
+		return "body changed"
+	case *ExternNode:
+		return fmt.Sprintf("signature changed from %s to %s", externSignature(b), externSignature(after.(*ExternNode)))
+	case *StructNode:
+		return "fields changed"
+	case *TypeAliasNode:
+		return fmt.Sprintf("type changed from %s to %s", b.Type, after.(*TypeAliasNode).Type)
+	case *EmbedNode:
+		return fmt.Sprintf("file changed from %s to %s", b.Path, after.(*EmbedNode).Path)
+	}
+	return "changed"
+}
+
+// spanType is the type of the spans the nodes embed, which equalSyntax ignores.
+var spanType = reflect.TypeOf(Span{})
+
+// equalSyntax reports whether the trees are deeply equal like reflect.DeepEqual reports, except
+// that the spans of the nodes aren't compared, so nodes parsed from another place are equal.
+func equalSyntax(a, b any) bool {
+	return equalValues(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// equalValues reports whether the values are deeply equal, ignoring the spans of nodes.
+func equalValues(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equalValues(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if a.Type().Field(i).Type != spanType && !equalValues(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equalValues(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for _, key := range a.MapKeys() {
+			if !equalValues(a.MapIndex(key), b.MapIndex(key)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.String:
+		return a.String() == b.String()
+	}
+	return a.IsZero() && b.IsZero()
+}
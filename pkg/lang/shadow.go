@@ -0,0 +1,62 @@
+package lang
+
+import "sort"
+
+// Shadowed returns a warning for every parameter which shadows a global value, i.e. an embedded
+// file or a thread-local variable, and for every variable which shadows a value declared before it
+// in an enclosing scope, e.g. the variable of a for loop or a variable declared in its body which
+// has the name of a variable of the function, in the order of the source code. Shadowing is
+// allowed, see Resolve, but a use of the name in the inner scope may have meant the outer value.
+//
+// Returns an error if the nodes aren't valid, see Validate.
+func Shadowed(nodes []Node) ([]Diagnostic, error) {
+	resolution, err := Resolve(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []Diagnostic
+	var walk func(scope *SymbolScope)
+	walk = func(scope *SymbolScope) {
+		for _, name := range scope.Values.Names() {
+			symbol, _ := scope.Values.Get(name)
+			shadowed := shadowedSymbol(scope, symbol)
+			if shadowed == nil {
+				continue
+			}
+			var err error
+			if symbol.Kind == SymbolParameter {
+				err = newError(MessageShadowedParameter, name, scope.Node.(*FunctionNode).Name, shadowed.Node.Pos())
+			} else {
+				err = newError(MessageShadowedVariable, name, shadowed.Node.Pos())
+			}
+			diagnostic := newDiagnostic(symbol.Node, err)
+			diagnostic.Severity = SeverityWarning
+			diagnostics = append(diagnostics, diagnostic)
+		}
+		for _, child := range scope.Children {
+			walk(child)
+		}
+	}
+	walk(resolution.Global)
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return before(diagnostics[i].From, diagnostics[j].From)
+	})
+	return diagnostics, nil
+}
+
+// shadowedSymbol returns the value with the name of the symbol declared in the scope which the
+// symbol shadows, or nil. Global values are visible in the whole program, the values of other
+// scopes from their declaration on.
+func shadowedSymbol(scope *SymbolScope, symbol *Symbol) *Symbol {
+	if scope.Kind == ScopeGlobal {
+		return nil
+	}
+	for outer := scope.Parent; outer != nil; outer = outer.Parent {
+		if shadowed, ok := outer.Values.Get(symbol.Name); ok && (outer.Kind == ScopeGlobal || before(shadowed.Node.Pos(), symbol.Node.Pos())) {
+			return shadowed
+		}
+	}
+	return nil
+}
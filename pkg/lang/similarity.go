@@ -0,0 +1,188 @@
+package lang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Similarity returns how similar the programs represented by the given nodes are, from 0 for
+// programs without any common structure to 1 for programs with the same structure.
+//
+// Programs are compared by the fingerprints of all their subtrees. Fingerprints are normalized:
+// they keep the kinds of nodes, the types and the kinds of literals, but not the names of
+// identifiers, functions, structs and fields or the values of literals. Renaming identifiers
+// or changing constants therefore doesn't change the similarity, while reordering, adding or
+// removing statements lowers it.
+func Similarity(a, b []Node) float64 {
+	fingerprintsA, fingerprintsB := fingerprints(a), fingerprints(b)
+
+	total := 0
+	for _, count := range fingerprintsA {
+		total += count
+	}
+	for _, count := range fingerprintsB {
+		total += count
+	}
+	if total == 0 {
+		return 1
+	}
+
+	common := 0
+	for fingerprint, count := range fingerprintsA {
+		if other := fingerprintsB[fingerprint]; other < count {
+			count = other
+		}
+		common += count
+	}
+	return float64(2*common) / float64(total)
+}
+
+// fingerprints returns how often every normalized subtree fingerprint occurs in the nodes.
+func fingerprints(nodes []Node) map[string]int {
+	counts := make(map[string]int)
+	for _, node := range nodes {
+		fingerprint(node, counts)
+	}
+	return counts
+}
+
+// fingerprint returns the normalized fingerprint of the value, which is a node or a value of a
+// node, and counts it and the fingerprints of all nested values in counts.
+func fingerprint(value any, counts map[string]int) string {
+	var f string
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case *IdentifierNode:
+		f = "id"
+	case *IntLiteralNode:
+		f = "int"
+	case *FloatLiteralNode:
+		f = "float"
+	case *BoolLiteralNode:
+		f = "bool"
+	case *LetNode:
+		f = fmt.Sprintf("let(%s,%t,%t,%t,%d,%s)", normalizedType(v.Type), v.HasType, v.ThreadLocal, v.Volatile, v.Ordering, fingerprint(v.Value, counts))
+	case *AssignmentNode:
+		f = fmt.Sprintf("assign(%s)", fingerprint(v.Value, counts))
+	case *IndexAssignmentNode:
+		f = fmt.Sprintf("assign(%s,%s)", fingerprint(v.Target, counts), fingerprint(v.Value, counts))
+	case *FieldAssignmentNode:
+		f = fmt.Sprintf("assign(%s,%s)", fingerprint(v.Target, counts), fingerprint(v.Value, counts))
+	case *DereferenceAssignmentNode:
+		f = fmt.Sprintf("assign(%s,%s)", fingerprint(v.Target, counts), fingerprint(v.Value, counts))
+	case *AddOperationNode:
+		f = fmt.Sprintf("add(%s,%s)", fingerprint(v.LeftValue, counts), fingerprint(v.RightValue, counts))
+	case *ShiftOperationNode:
+		f = fmt.Sprintf("shift(%t,%s,%s)", v.Left, fingerprint(v.LeftValue, counts), fingerprint(v.RightValue, counts))
+	case *ComparisonNode:
+		f = fmt.Sprintf("compare(%d,%s,%s)", v.Operator, fingerprint(v.LeftValue, counts), fingerprint(v.RightValue, counts))
+	case *CastNode:
+		f = fmt.Sprintf("cast(%s,%s)", normalizedType(v.Type), fingerprint(v.Value, counts))
+	case *IndexNode:
+		f = fmt.Sprintf("index(%s,%s)", fingerprint(v.Value, counts), fingerprint(v.Index, counts))
+	case *FieldNode:
+		f = fmt.Sprintf("field(%s)", fingerprint(v.Value, counts))
+	case *NewNode:
+		f = fmt.Sprintf("new(%s)", normalizedType(v.Type))
+	case *NoneNode:
+		f = "none"
+	case *StringLiteralNode:
+		f = "string"
+	case *TryNode:
+		f = fmt.Sprintf("try(%s)", fingerprint(v.Value, counts))
+	case *ConditionalNode:
+		f = fmt.Sprintf("conditional(%s,%s,%s)", fingerprint(v.Condition, counts), fingerprint(v.True, counts), fingerprint(v.False, counts))
+	case *AddressNode:
+		f = fmt.Sprintf("address(%s)", fingerprint(v.Value, counts))
+	case *DereferenceNode:
+		f = fmt.Sprintf("dereference(%s)", fingerprint(v.Value, counts))
+	case *ArrayLiteralNode:
+		f = fmt.Sprintf("array(%s)", fingerprintList(v.Elements, counts))
+	case *StructLiteralNode:
+		values := make([]Expr, len(v.Fields))
+		for i, field := range v.Fields {
+			values[i] = field.Value
+		}
+		f = fmt.Sprintf("struct(%s)", fingerprintList(values, counts))
+	case *CallerNode:
+		f = fmt.Sprintf("call(%s)", fingerprintList(v.Args, counts))
+	case *SpawnNode:
+		f = fmt.Sprintf("spawn(%s)", fingerprint(v.Call, counts))
+	case *ReturnNode:
+		f = fmt.Sprintf("return(%s)", fingerprint(v.Value, counts))
+	case *FunctionNode:
+		types := make([]string, len(v.Parameters))
+		for i, parameter := range v.Parameters {
+			types[i] = normalizedType(parameter.Type)
+		}
+		f = fmt.Sprintf("function(%s,%s,%s,%s)", strings.Join(types, ","), normalizedType(v.ReturnType), strings.Join(v.Attributes, ","), fingerprintBody(v.Body, counts))
+	case *StructNode:
+		types := make([]string, len(v.Fields))
+		for i, field := range v.Fields {
+			types[i] = fmt.Sprintf("%s@%d", normalizedType(field.Type), field.Alignment)
+		}
+		f = fmt.Sprintf("structdecl(%t,%s)", v.Packed, strings.Join(types, ","))
+	case *ExternNode:
+		types := make([]string, len(v.Parameters))
+		for i, parameter := range v.Parameters {
+			types[i] = normalizedType(parameter.Type)
+		}
+		f = fmt.Sprintf("extern(%s,%s,%s)", strings.Join(types, ","), normalizedType(v.ReturnType), strings.Join(v.Attributes, ","))
+	case *EmbedNode:
+		f = "embed"
+	case *InlineIRNode:
+		f = "llvm"
+	case *TypeAliasNode:
+		f = fmt.Sprintf("typealias(%s)", normalizedType(v.Type))
+	case *ForNode:
+		f = fmt.Sprintf("for(%s,%s,%t,%s)", fingerprint(v.Init.Value, counts), fingerprint(v.Condition.RightValue, counts), v.Post.Increment, fingerprintBody(v.Body, counts))
+	case *WhileNode:
+		f = fmt.Sprintf("while(%s)", fingerprintBody(v.Body, counts))
+	default:
+		f = fmt.Sprintf("%T", v)
+	}
+	counts[f]++
+	return f
+}
+
+// fingerprintList returns the fingerprints of the values joined by commas.
+func fingerprintList(values []Expr, counts map[string]int) string {
+	fingerprints := make([]string, len(values))
+	for i, value := range values {
+		fingerprints[i] = fingerprint(value, counts)
+	}
+	return strings.Join(fingerprints, ",")
+}
+
+// fingerprintBody returns the fingerprints of the nodes of a body joined by semicolons.
+func fingerprintBody(nodes []Node, counts map[string]int) string {
+	fingerprints := make([]string, len(nodes))
+	for i, node := range nodes {
+		fingerprints[i] = fingerprint(node, counts)
+	}
+	return strings.Join(fingerprints, ";")
+}
+
+// normalizedType returns the spelling of the data type with the names of struct types removed.
+func normalizedType(t dataType) string {
+	if arrayType, ok := t.array(); ok {
+		return fmt.Sprintf("[%d]%s", arrayType.Length, normalizedType(arrayType.Element))
+	}
+	if sliceType, ok := t.slice(); ok {
+		return "[]" + normalizedType(sliceType.Element)
+	}
+	if pointerType, ok := t.pointer(); ok {
+		return "*" + normalizedType(pointerType.Element)
+	}
+	if optionType, ok := t.option(); ok {
+		return "option<" + normalizedType(optionType.Element) + ">"
+	}
+	if resultType, ok := t.result(); ok {
+		return "result<" + normalizedType(resultType.Element) + ">"
+	}
+	if _, ok := t.structure(); ok {
+		return "struct"
+	}
+	return t.String()
+}
@@ -0,0 +1,145 @@
+package lang
+
+import (
+	"tinygo.org/x/go-llvm"
+)
+
+// joinIdentifier is the identifier of the builtin function waiting for a thread started by spawn.
+const joinIdentifier = "join"
+
+// Constants for the identifiers of the functions starting and joining threads.
+const (
+	// spawnTrampolinePrefix is the prefix of the LLVM name of the function a spawned thread runs, which
+	// is followed by the name of the spawned function.
+	spawnTrampolinePrefix = runtimePrefix + "spawn_"
+	// pthreadCreateIdentifier is the identifier of the C function starting a thread.
+	pthreadCreateIdentifier = "pthread_create"
+	// pthreadJoinIdentifier is the identifier of the C function waiting for a thread to end.
+	pthreadJoinIdentifier = "pthread_join"
+)
+
+// isJoinBuiltin reports whether a call of the name refers to the join builtin, which is the case
+// unless the program declares a function or an extern function named join.
+func isJoinBuiltin(scope *Scope, name string) bool {
+	return name == joinIdentifier && !declaresCaller(scope, name)
+}
+
+// generateSpawn is a function that generates LLVM IR code for a spawn, which calls a function on a
+// new native thread started with pthread_create and results in the handle of the thread as i64.
+// The parameters are evaluated by the spawning thread and stored into a heap allocated block,
+// which is passed to a trampoline: a function of the type pthread_create expects, which loads the
+// parameters, releases the block and calls the function. A value the function returns is dropped.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// spawnNode:        The abstract syntax tree (AST) node representing the spawn.
+//
+// Returns an error if the spawned function isn't declared by the program, the parameters don't
+// match it or the garbage collector is enabled.
+func generateSpawn(scope *Scope, functionBuilder llvm.Builder, spawnNode *SpawnNode) (llvm.Value, dataType, error) {
+	if globalScope.Options.GarbageCollection {
+		return llvm.Value{}, 0, newError(MessageSpawnGarbageCollection)
+	}
+	callerNode := spawnNode.Call
+
+	caller, ok := scope.Callers.Get(callerNode.FunctionName)
+	if !ok {
+		caller, ok = globalScope.Callers.Get(callerNode.FunctionName)
+	}
+	if !ok || caller.Type.IsFunctionVarArg() {
+		return llvm.Value{}, 0, newError(MessageInvalidSpawnTarget, callerNode.FunctionName)
+	}
+	if len(caller.ParameterTypes) != len(callerNode.Args) {
+		return llvm.Value{}, 0, newError(MessageExpectedParameters, len(caller.ParameterTypes), callerNode.FunctionName, len(callerNode.Args))
+	}
+
+	var llvmParameterValues []llvm.Value
+	for i, arg := range callerNode.Args {
+		value, err := generateTypedValue(scope, functionBuilder, arg, caller.ParameterTypes[i])
+		if err != nil {
+			return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, i+1, callerNode.FunctionName, err)
+		}
+		llvmParameterValues = append(llvmParameterValues, value)
+	}
+
+	// Store the parameters into a block the trampoline releases
+	parametersType := globalScope.Context.StructType(caller.Type.ParamTypes(), false)
+	mallocType, malloc := mallocFunction(functionBuilder)
+	parameters := functionBuilder.CreateCall(mallocType, malloc, []llvm.Value{llvm.SizeOf(parametersType)}, "spawn_parameters")
+	for i, value := range llvmParameterValues {
+		functionBuilder.CreateStore(value, functionBuilder.CreateStructGEP(parametersType, parameters, i, ""))
+	}
+
+	handle := functionBuilder.CreateAlloca(globalScope.Context.Int64Type(), "thread")
+	trampoline := spawnTrampoline(functionBuilder, callerNode.FunctionName, caller, parametersType)
+	pthreadCreateType, pthreadCreate := pthreadCreateFunction(functionBuilder)
+	null := llvm.ConstNull(llvm.PointerType(globalScope.Context.Int8Type(), 0))
+	functionBuilder.CreateCall(pthreadCreateType, pthreadCreate, []llvm.Value{handle, null, trampoline, parameters}, "")
+	return functionBuilder.CreateLoad(globalScope.Context.Int64Type(), handle, ""), Integer64Type, nil
+}
+
+// spawnTrampoline returns the function threads spawned to call the function of the caller run. It
+// takes the block holding the parameters, which has the parameters type, and returns null.
+func spawnTrampoline(functionBuilder llvm.Builder, name string, caller Caller, parametersType llvm.Type) llvm.Value {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	functionType := llvm.FunctionType(pointerType, []llvm.Type{pointerType}, false)
+
+	_, trampoline := runtimeFunction(functionBuilder, spawnTrampolinePrefix+name, functionType, func(function llvm.Value) {
+		builder := globalScope.Context.NewBuilder()
+		defer builder.Dispose()
+
+		entry := globalScope.Context.AddBasicBlock(function, "entry")
+		builder.SetInsertPointAtEnd(entry)
+
+		// Load the parameters before the block holding them is released
+		parameters := function.Param(0)
+		values := make([]llvm.Value, len(caller.Type.ParamTypes()))
+		for i, t := range caller.Type.ParamTypes() {
+			values[i] = builder.CreateLoad(t, builder.CreateStructGEP(parametersType, parameters, i, ""), "")
+		}
+		freeType, free := freeFunction(builder)
+		builder.CreateCall(freeType, free, []llvm.Value{parameters}, "")
+
+		builder.CreateCall(*caller.Type, *caller.Value, values, "")
+		builder.CreateRet(llvm.ConstNull(pointerType))
+	})
+	return trampoline
+}
+
+// generateJoin is a function that generates LLVM IR code for a call of the join builtin, which
+// waits until the thread whose handle spawn returned ends, by calling pthread_join.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+//
+// Returns an error if there isn't exactly one parameter or it isn't an i64 value.
+func generateJoin(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) != 1 {
+		return llvm.Value{}, 0, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+	}
+	handle, err := generateTypedValue(scope, functionBuilder, callerNode.Args[0], Integer64Type)
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, 1, callerNode.FunctionName, err)
+	}
+
+	pthreadJoinType, pthreadJoin := pthreadJoinFunction(functionBuilder)
+	null := llvm.ConstNull(llvm.PointerType(globalScope.Context.Int8Type(), 0))
+	call := functionBuilder.CreateCall(pthreadJoinType, pthreadJoin, []llvm.Value{handle, null}, "")
+	return call, VoidType, nil
+}
+
+// pthreadCreateFunction returns the type and the declaration of the C pthread_create function.
+// The handle of a thread, pthread_t, is an i64 on the supported targets.
+func pthreadCreateFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	functionType := llvm.FunctionType(globalScope.Context.Int32Type(), []llvm.Type{pointerType, pointerType, pointerType, pointerType}, false)
+	return runtimeFunction(functionBuilder, pthreadCreateIdentifier, functionType, nil)
+}
+
+// pthreadJoinFunction returns the type and the declaration of the C pthread_join function.
+func pthreadJoinFunction(functionBuilder llvm.Builder) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	functionType := llvm.FunctionType(globalScope.Context.Int32Type(), []llvm.Type{globalScope.Context.Int64Type(), pointerType}, false)
+	return runtimeFunction(functionBuilder, pthreadJoinIdentifier, functionType, nil)
+}
@@ -0,0 +1,66 @@
+package lang
+
+// ProgramStats holds the metrics of a program measured on its abstract syntax tree.
+type ProgramStats struct {
+	Functions  int                  // The number of function definitions.
+	Statements int                  // The number of statements, including the statements of bodies.
+	Loops      int                  // The number of for and while loops.
+	MaxDepth   int                  // The maximum nesting depth of a statement, top-level statements have depth 0.
+	Complexity []FunctionComplexity // The cyclomatic complexity of every function, in declaration order.
+}
+
+// FunctionComplexity holds the cyclomatic complexity of a function, which is one plus the
+// number of decision points in its body. The decision points are loops, conditional expressions
+// and try expressions, which return early on an error.
+type FunctionComplexity struct {
+	Name       string
+	Complexity int
+}
+
+// Stats returns the metrics of the program represented by the given nodes.
+func Stats(nodes []Node) ProgramStats {
+	var stats ProgramStats
+	stats.collect(nodes, 0)
+	return stats
+}
+
+// collect adds the metrics of the nodes, which are nested at the given depth, to the stats.
+func (s *ProgramStats) collect(nodes []Node, depth int) {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *FunctionNode:
+			s.Functions++
+			s.Complexity = append(s.Complexity, FunctionComplexity{Name: n.Name, Complexity: 1 + countDecisions(n.Body)})
+			s.collect(n.Body, depth+1)
+			continue
+		case *StructNode, *EmbedNode, *TypeAliasNode, *ExternNode:
+			continue
+		}
+
+		s.Statements++
+		if depth > s.MaxDepth {
+			s.MaxDepth = depth
+		}
+		switch n := node.(type) {
+		case *ForNode:
+			s.Loops++
+			s.collect(n.Body, depth+1)
+		case *WhileNode:
+			s.Loops++
+			s.collect(n.Body, depth+1)
+		}
+	}
+}
+
+// countDecisions returns the number of decision points in the nodes, including nested ones.
+func countDecisions(nodes []Node) int {
+	count := 0
+	WalkNodes(nodes, func(node Node) bool {
+		switch node.(type) {
+		case *ForNode, *WhileNode, *ConditionalNode, *TryNode:
+			count++
+		}
+		return true
+	})
+	return count
+}
@@ -0,0 +1,41 @@
+package lang
+
+// Symbols is a symbol table which maps names to symbols and remembers the
+// order in which the names were declared, so every iteration over a scope
+// yields the same order and the generated code stays deterministic.
+type Symbols[T any] struct {
+	names   []string
+	symbols map[string]T
+}
+
+// newSymbols creates a new empty symbol table.
+func newSymbols[T any]() *Symbols[T] {
+	return &Symbols[T]{symbols: make(map[string]T)}
+}
+
+// Set declares or replaces the symbol with the given name. A replaced symbol
+// keeps the position of its first declaration.
+func (s *Symbols[T]) Set(name string, symbol T) {
+	if _, ok := s.symbols[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.symbols[name] = symbol
+}
+
+// Get returns the symbol with the given name and whether it was declared.
+func (s *Symbols[T]) Get(name string) (T, bool) {
+	symbol, ok := s.symbols[name]
+	return symbol, ok
+}
+
+// Names returns the names of all declared symbols in declaration order.
+func (s *Symbols[T]) Names() []string {
+	names := make([]string, len(s.names))
+	copy(names, s.names)
+	return names
+}
+
+// Len returns the number of declared symbols.
+func (s *Symbols[T]) Len() int {
+	return len(s.names)
+}
@@ -0,0 +1,153 @@
+package lang
+
+import (
+	"tinygo.org/x/go-llvm"
+)
+
+// Constants for the identifiers of the builtin functions synchronizing threads.
+const (
+	atomicAddIdentifier   = "atomic_add"
+	atomicLoadIdentifier  = "atomic_load"
+	atomicStoreIdentifier = "atomic_store"
+	mutexIdentifier       = "mutex"
+	lockIdentifier        = "lock"
+	unlockIdentifier      = "unlock"
+)
+
+// Constants for the identifiers of the C functions the mutex builtins call.
+const (
+	pthreadMutexInitIdentifier   = "pthread_mutex_init"
+	pthreadMutexLockIdentifier   = "pthread_mutex_lock"
+	pthreadMutexUnlockIdentifier = "pthread_mutex_unlock"
+)
+
+// mutexSize is the number of bytes allocated for a mutex, which is larger than the pthread_mutex_t
+// of the supported C libraries.
+const mutexSize = 64
+
+// isSyncBuiltin reports whether a call of the name refers to one of the atomic or mutex builtins,
+// which is the case unless the program declares a function or an extern function of the name.
+func isSyncBuiltin(scope *Scope, name string) bool {
+	switch name {
+	case atomicAddIdentifier, atomicLoadIdentifier, atomicStoreIdentifier, mutexIdentifier, lockIdentifier, unlockIdentifier:
+		return !declaresCaller(scope, name)
+	}
+	return false
+}
+
+// generateSync is a function that generates LLVM IR code for a call of an atomic or mutex builtin.
+// The atomic builtins access the value a pointer refers to with sequentially consistent ordering:
+// atomic_load(p) returns the value, atomic_store(p, v) stores v and atomic_add(p, v) adds v to the
+// integer and returns the value before the addition. mutex() allocates a new mutex and returns its
+// handle as i64, which lock(m) and unlock(m) acquire and release. Mutexes are never released.
+//
+// scope:            A pointer to the current scope.
+// functionBuilder:  The LLVM builder associated with the current function.
+// callerNode:       The abstract syntax tree (AST) node representing the call.
+//
+// Returns an error if the number of parameters doesn't match or a parameter has the wrong type.
+func generateSync(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	switch callerNode.FunctionName {
+	case mutexIdentifier:
+		return generateMutex(functionBuilder, callerNode)
+	case lockIdentifier, unlockIdentifier:
+		return generateLock(scope, functionBuilder, callerNode)
+	}
+
+	expected := 1
+	if callerNode.FunctionName != atomicLoadIdentifier {
+		expected = 2
+	}
+	if len(callerNode.Args) != expected {
+		if expected == 1 {
+			return llvm.Value{}, 0, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+		}
+		return llvm.Value{}, 0, newError(MessageExpectedTwoParameters, callerNode.FunctionName, len(callerNode.Args))
+	}
+
+	address, addressType, err := generateValue(scope, functionBuilder, callerNode.Args[0])
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, 1, callerNode.FunctionName, err)
+	}
+	pointerType, ok := addressType.pointer()
+	element := pointerType.Element
+	if _, isPointer := element.pointer(); ok {
+		ok = element.isInteger() || element.isFloat() || isPointer
+	}
+	if !ok {
+		return llvm.Value{}, 0, newError(MessageAtomicOperand, callerNode.FunctionName, addressType)
+	}
+	if callerNode.FunctionName == atomicAddIdentifier && !element.isInteger() {
+		return llvm.Value{}, 0, newError(MessageAtomicAddOperand, callerNode.FunctionName, addressType)
+	}
+
+	if callerNode.FunctionName == atomicLoadIdentifier {
+		load := functionBuilder.CreateLoad(llvmType(element), address, "")
+		load.SetOrdering(llvm.AtomicOrderingSequentiallyConsistent)
+		load.SetAlignment(dataTypeAlignment(element))
+		return load, element, nil
+	}
+
+	value, err := generateTypedValue(scope, functionBuilder, callerNode.Args[1], element)
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, 2, callerNode.FunctionName, err)
+	}
+	if callerNode.FunctionName == atomicAddIdentifier {
+		add := functionBuilder.CreateAtomicRMW(llvm.AtomicRMWBinOpAdd, address, value, llvm.AtomicOrderingSequentiallyConsistent, false)
+		add.SetAlignment(dataTypeAlignment(element))
+		return add, element, nil
+	}
+	store := functionBuilder.CreateStore(value, address)
+	store.SetOrdering(llvm.AtomicOrderingSequentiallyConsistent)
+	store.SetAlignment(dataTypeAlignment(element))
+	return store, VoidType, nil
+}
+
+// generateMutex is a function that generates LLVM IR code for a call of the mutex builtin, which
+// allocates a mutex initialized with pthread_mutex_init and returns the address as handle.
+func generateMutex(functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) != 0 {
+		return llvm.Value{}, 0, newError(MessageExpectedNoParameters, callerNode.FunctionName, len(callerNode.Args))
+	}
+
+	// The mutex is allocated with malloc also if the garbage collector is enabled, as the handle
+	// doesn't keep it reachable
+	mallocType, malloc := mallocFunction(functionBuilder)
+	mutex := functionBuilder.CreateCall(mallocType, malloc, []llvm.Value{llvm.ConstInt(globalScope.Context.Int64Type(), mutexSize, false)}, "mutex")
+	initType, init := mutexFunction(functionBuilder, pthreadMutexInitIdentifier, true)
+	null := llvm.ConstNull(llvm.PointerType(globalScope.Context.Int8Type(), 0))
+	functionBuilder.CreateCall(initType, init, []llvm.Value{mutex, null}, "")
+	return functionBuilder.CreatePtrToInt(mutex, globalScope.Context.Int64Type(), ""), Integer64Type, nil
+}
+
+// generateLock is a function that generates LLVM IR code for a call of the lock or unlock builtin,
+// which acquires or releases the mutex whose handle the mutex builtin returned.
+func generateLock(scope *Scope, functionBuilder llvm.Builder, callerNode *CallerNode) (llvm.Value, dataType, error) {
+	if len(callerNode.Args) != 1 {
+		return llvm.Value{}, 0, newError(MessageExpectedOneParameter, callerNode.FunctionName, len(callerNode.Args))
+	}
+	handle, err := generateTypedValue(scope, functionBuilder, callerNode.Args[0], Integer64Type)
+	if err != nil {
+		return llvm.Value{}, 0, newError(MessageInvalidCallerParameter, 1, callerNode.FunctionName, err)
+	}
+
+	name := pthreadMutexLockIdentifier
+	if callerNode.FunctionName == unlockIdentifier {
+		name = pthreadMutexUnlockIdentifier
+	}
+	functionType, function := mutexFunction(functionBuilder, name, false)
+	mutex := functionBuilder.CreateIntToPtr(handle, llvm.PointerType(globalScope.Context.Int8Type(), 0), "")
+	call := functionBuilder.CreateCall(functionType, function, []llvm.Value{mutex}, "")
+	return call, VoidType, nil
+}
+
+// mutexFunction returns the type and the declaration of the C function of the name taking a
+// mutex, and the attributes of the mutex if attributes is true, and returning an i32.
+func mutexFunction(functionBuilder llvm.Builder, name string, attributes bool) (llvm.Type, llvm.Value) {
+	pointerType := llvm.PointerType(globalScope.Context.Int8Type(), 0)
+	parameterTypes := []llvm.Type{pointerType}
+	if attributes {
+		parameterTypes = append(parameterTypes, pointerType)
+	}
+	return runtimeFunction(functionBuilder, name, llvm.FunctionType(globalScope.Context.Int32Type(), parameterTypes, false), nil)
+}
@@ -2,6 +2,7 @@ package lang
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -16,20 +17,60 @@ type TokenRune rune
 const (
 	TokenWhile                   TokenValue = "while"
 	TokenLet                     TokenValue = "let"
+	TokenInteger8                TokenValue = "i8"
+	TokenInteger16               TokenValue = "i16"
 	TokenInteger32               TokenValue = "i32"
+	TokenInteger64               TokenValue = "i64"
+	TokenFloat32                 TokenValue = "f32"
+	TokenFloat64                 TokenValue = "f64"
+	TokenBool                    TokenValue = "bool"
+	TokenTrue                    TokenValue = "true"
+	TokenFalse                   TokenValue = "false"
+	TokenAs                      TokenValue = "as"
 	TokenFunction                TokenValue = "function"
+	TokenReturn                  TokenValue = "return"
+	TokenStruct                  TokenValue = "struct"
+	TokenEmbed                   TokenValue = "embed"
+	TokenThreadLocal             TokenValue = "threadlocal"
+	TokenVolatile                TokenValue = "volatile"
+	TokenAtomic                  TokenValue = "atomic"
+	TokenNone                    TokenValue = "none"
+	TokenTry                     TokenValue = "try"
+	TokenStringKeyword           TokenValue = "string"
+	TokenTypeKeyword             TokenValue = "type"
+	TokenImport                  TokenValue = "import"
+	TokenPackage                 TokenValue = "package"
+	TokenExtern                  TokenValue = "extern"
+	TokenLLVM                    TokenValue = "llvm"
+	TokenSpawn                   TokenValue = "spawn"
 	TokenOpenParenthesis         TokenRune  = '('
 	TokenCloseParenthesis        TokenRune  = ')'
 	TokenOpenCurlyBracket        TokenRune  = '{'
 	TokenCloseCurlyBracket       TokenRune  = '}'
+	TokenOpenSquareBracket       TokenRune  = '['
+	TokenCloseSquareBracket      TokenRune  = ']'
 	TokenComma                   TokenRune  = ','
+	TokenDot                     TokenRune  = '.'
 	TokenEquals                  TokenRune  = '='
 	TokenAdd                     TokenRune  = '+'
 	TokenFor                     TokenValue = "for"
 	TokenShortVariableAssignment TokenValue = ":="
+	TokenShiftLeft               TokenValue = "<<"
+	TokenShiftRight              TokenValue = ">>"
+	TokenEqualEqual              TokenValue = "=="
+	TokenNotEqual                TokenValue = "!="
+	TokenLessEqual               TokenValue = "<="
+	TokenGreaterEqual            TokenValue = ">="
+	TokenComment                 TokenValue = "//"
 	TokenSemicolon               TokenRune  = ';'
 	TokenColon                   TokenRune  = ':'
 	TokenLessThan                TokenRune  = '<'
+	TokenGreaterThan             TokenRune  = '>'
+	TokenQuote                   TokenRune  = '"'
+	TokenAmpersand               TokenRune  = '&'
+	TokenStar                    TokenRune  = '*'
+	TokenAt                      TokenRune  = '@'
+	TokenQuestionMark            TokenRune  = '?'
 )
 
 // TokenType represents the type of a token.
@@ -53,6 +94,47 @@ const (
 	TokenSemicolonType
 	TokenLessThanType
 	TokenColonType
+	TokenCommaType
+	TokenInteger8Type
+	TokenInteger16Type
+	TokenInteger64Type
+	TokenFloat32Type
+	TokenFloat64Type
+	TokenBoolType
+	TokenTrueType
+	TokenFalseType
+	TokenAsType
+	TokenReturnType
+	TokenOpenSquareBracketType
+	TokenCloseSquareBracketType
+	TokenStructType
+	TokenDotType
+	TokenEmbedType
+	TokenStringType
+	TokenAmpersandType
+	TokenStarType
+	TokenThreadLocalType
+	TokenVolatileType
+	TokenAtomicType
+	TokenAtType
+	TokenNoneType
+	TokenGreaterThanType
+	TokenTryType
+	TokenQuestionMarkType
+	TokenStringKeywordType
+	TokenShiftLeftType
+	TokenShiftRightType
+	TokenTypeKeywordType
+	TokenImportType
+	TokenPackageType
+	TokenExternType
+	TokenLLVMType
+	TokenInlineIRType
+	TokenSpawnType
+	TokenEqualEqualType
+	TokenNotEqualType
+	TokenLessEqualType
+	TokenGreaterEqualType
 	TokenUnknown
 )
 
@@ -60,6 +142,45 @@ const (
 type Token struct {
 	Type  TokenType
 	Value string
+	Pos   Pos // The position of the first rune of the token.
+	End   Pos // The position following the last rune of the token.
+}
+
+// Pos is a position in the source code, the line and the column of a rune, both counted from 1.
+// Columns count runes rather than bytes. The zero Pos is unknown, e.g. the position of a node
+// created after parsing.
+type Pos struct {
+	Line   int
+	Column int
+}
+
+// IsValid reports whether the position is known.
+func (p Pos) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position in the form line:column, or "-" if it is unknown.
+func (p Pos) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// runePositions returns the position of every rune and the position following the last rune.
+func runePositions(runes []rune) []Pos {
+	positions := make([]Pos, len(runes)+1)
+	pos := Pos{Line: 1, Column: 1}
+	for i, r := range runes {
+		positions[i] = pos
+		if r == '\n' {
+			pos = Pos{Line: pos.Line + 1, Column: 1}
+		} else {
+			pos.Column++
+		}
+	}
+	positions[len(runes)] = pos
+	return positions
 }
 
 // String method returns the string representation of a token.
@@ -79,8 +200,44 @@ func (t Token) String() string {
 		return string(TokenOpenCurlyBracket)
 	case TokenCloseCurlyBracketType:
 		return string(TokenCloseCurlyBracket)
+	case TokenOpenSquareBracketType:
+		return string(TokenOpenSquareBracket)
+	case TokenCloseSquareBracketType:
+		return string(TokenCloseSquareBracket)
+	case TokenInteger8Type:
+		return string(TokenInteger8)
+	case TokenInteger16Type:
+		return string(TokenInteger16)
 	case TokenInteger32Type:
 		return string(TokenInteger32)
+	case TokenInteger64Type:
+		return string(TokenInteger64)
+	case TokenFloat32Type:
+		return string(TokenFloat32)
+	case TokenFloat64Type:
+		return string(TokenFloat64)
+	case TokenBoolType:
+		return string(TokenBool)
+	case TokenTrueType:
+		return string(TokenTrue)
+	case TokenFalseType:
+		return string(TokenFalse)
+	case TokenAsType:
+		return string(TokenAs)
+	case TokenReturnType:
+		return string(TokenReturn)
+	case TokenStructType:
+		return string(TokenStruct)
+	case TokenEmbedType:
+		return string(TokenEmbed)
+	case TokenThreadLocalType:
+		return string(TokenThreadLocal)
+	case TokenVolatileType:
+		return string(TokenVolatile)
+	case TokenAtomicType:
+		return string(TokenAtomic)
+	case TokenStringType:
+		return strconv.Quote(t.Value)
 	case TokenAddType:
 		return string(TokenAdd)
 	case TokenForType:
@@ -93,168 +250,382 @@ func (t Token) String() string {
 		return string(TokenLessThan)
 	case TokenColonType:
 		return string(TokenColon)
+	case TokenCommaType:
+		return string(TokenComma)
+	case TokenDotType:
+		return string(TokenDot)
+	case TokenAmpersandType:
+		return string(TokenAmpersand)
+	case TokenStarType:
+		return string(TokenStar)
+	case TokenAtType:
+		return string(TokenAt)
+	case TokenNoneType:
+		return string(TokenNone)
+	case TokenTryType:
+		return string(TokenTry)
+	case TokenQuestionMarkType:
+		return string(TokenQuestionMark)
+	case TokenStringKeywordType:
+		return string(TokenStringKeyword)
+	case TokenShiftLeftType:
+		return string(TokenShiftLeft)
+	case TokenShiftRightType:
+		return string(TokenShiftRight)
+	case TokenTypeKeywordType:
+		return string(TokenTypeKeyword)
+	case TokenImportType:
+		return string(TokenImport)
+	case TokenPackageType:
+		return string(TokenPackage)
+	case TokenExternType:
+		return string(TokenExtern)
+	case TokenLLVMType:
+		return string(TokenLLVM)
+	case TokenSpawnType:
+		return string(TokenSpawn)
+	case TokenEqualEqualType:
+		return string(TokenEqualEqual)
+	case TokenNotEqualType:
+		return string(TokenNotEqual)
+	case TokenLessEqualType:
+		return string(TokenLessEqual)
+	case TokenGreaterEqualType:
+		return string(TokenGreaterEqual)
+	case TokenInlineIRType:
+		return string(TokenOpenCurlyBracket) + t.Value + string(TokenCloseCurlyBracket)
+	case TokenGreaterThanType:
+		return string(TokenGreaterThan)
 	case TokenIdentifierType:
 		return fmt.Sprintf("identifier(%s)", t.Value)
 	default:
 		return fmt.Sprintf("unknown(%s)", t.Value)
 	}
-	return fmt.Sprintf("unknown(%s)", t.Value)
 }
 
-// isBracket checks if the given rune is a parenthesis.
-func isBracket(r TokenRune) bool {
-	return r == TokenOpenParenthesis || r == TokenCloseParenthesis
+// keywords maps reserved words to their token types. Every other word is
+// tokenized as an identifier.
+var keywords = map[TokenValue]TokenType{
+	TokenWhile:         TokenWhileType,
+	TokenLet:           TokenLetType,
+	TokenFunction:      TokenFunctionType,
+	TokenFor:           TokenForType,
+	TokenInteger8:      TokenInteger8Type,
+	TokenInteger16:     TokenInteger16Type,
+	TokenInteger32:     TokenInteger32Type,
+	TokenInteger64:     TokenInteger64Type,
+	TokenFloat32:       TokenFloat32Type,
+	TokenFloat64:       TokenFloat64Type,
+	TokenBool:          TokenBoolType,
+	TokenTrue:          TokenTrueType,
+	TokenFalse:         TokenFalseType,
+	TokenAs:            TokenAsType,
+	TokenReturn:        TokenReturnType,
+	TokenStruct:        TokenStructType,
+	TokenEmbed:         TokenEmbedType,
+	TokenThreadLocal:   TokenThreadLocalType,
+	TokenVolatile:      TokenVolatileType,
+	TokenAtomic:        TokenAtomicType,
+	TokenNone:          TokenNoneType,
+	TokenTry:           TokenTryType,
+	TokenStringKeyword: TokenStringKeywordType,
+	TokenTypeKeyword:   TokenTypeKeywordType,
+	TokenImport:        TokenImportType,
+	TokenPackage:       TokenPackageType,
+	TokenExtern:        TokenExternType,
+	TokenLLVM:          TokenLLVMType,
+	TokenSpawn:         TokenSpawnType,
+}
+
+// comparisonTokens maps the comparison operators of two runes ending with an equals sign to their
+// token types, keyed by their first rune.
+var comparisonTokens = map[TokenRune]TokenType{
+	TokenEquals:      TokenEqualEqualType,
+	'!':              TokenNotEqualType,
+	TokenLessThan:    TokenLessEqualType,
+	TokenGreaterThan: TokenGreaterEqualType,
+}
+
+// runeTokens maps single rune tokens to their token types.
+var runeTokens = map[TokenRune]TokenType{
+	TokenOpenParenthesis:    TokenOpenParenthesisType,
+	TokenCloseParenthesis:   TokenCloseParenthesisType,
+	TokenOpenCurlyBracket:   TokenOpenCurlyBracketType,
+	TokenCloseCurlyBracket:  TokenCloseCurlyBracketType,
+	TokenOpenSquareBracket:  TokenOpenSquareBracketType,
+	TokenCloseSquareBracket: TokenCloseSquareBracketType,
+	TokenComma:              TokenCommaType,
+	TokenEquals:             TokenEqualsType,
+	TokenAdd:                TokenAddType,
+	TokenSemicolon:          TokenSemicolonType,
+	TokenColon:              TokenColonType,
+	TokenLessThan:           TokenLessThanType,
+	TokenGreaterThan:        TokenGreaterThanType,
+	TokenAmpersand:          TokenAmpersandType,
+	TokenStar:               TokenStarType,
+	TokenAt:                 TokenAtType,
+	TokenQuestionMark:       TokenQuestionMarkType,
+}
+
+// isNumberWord reports whether the accumulated word is the start of a number literal. A dot
+// continues a number literal, e.g. 1.5, and separates every other word, e.g. p.x.
+func isNumberWord(word string) bool {
+	word = strings.TrimPrefix(word, "-")
+	return word != "" && unicode.IsDigit([]rune(word)[0])
+}
+
+// isInlineIRStart reports whether an open curly bracket starts the block of an inline LLVM IR
+// block, i.e. whether it follows the llvm keyword, which is the accumulated word or the last token.
+func isInlineIRStart(tokens []Token, sb *strings.Builder, caseInsensitiveKeywords bool) bool {
+	if sb.Len() > 0 {
+		return wordToken(TokenValue(sb.String()), caseInsensitiveKeywords).Type == TokenLLVMType
+	}
+	return len(tokens) > 0 && tokens[len(tokens)-1].Type == TokenLLVMType
+}
+
+// inlineIREnd returns the index of the close curly bracket matching the open curly bracket at the
+// given index, skipping curly brackets in quoted strings and comments of the LLVM IR, and whether
+// there is a matching one.
+func inlineIREnd(runes []rune, start int) (int, bool) {
+	depth := 0
+	for i := start; i < len(runes); i++ {
+		switch runes[i] {
+		case '"':
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+			}
+		case ';':
+			for i++; i < len(runes) && runes[i] != '\n'; i++ {
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i, true
+			}
+		}
+	}
+	return len(runes), false
 }
 
-// isCurly checks if the given rune is a curly bracket.
-func isCurly(r TokenRune) bool {
-	return r == TokenOpenCurlyBracket || r == TokenCloseCurlyBracket
+// isCommentStart reports whether a comment starts at the given index.
+func isCommentStart(runes []rune, i int) bool {
+	return i+1 < len(runes) && TokenValue(runes[i:i+2]) == TokenComment
 }
 
-// isComma checks if the given rune is a comma.
-func isComma(r TokenRune) bool {
-	return r == TokenComma
+// commentEnd returns the index of the line break ending the comment starting at the given index,
+// or len(runes) if the comment ends the input.
+func commentEnd(runes []rune, start int) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == '\n' {
+			return i
+		}
+	}
+	return len(runes)
 }
 
-// isSemicolon checks if the given rune is a semicolon.
-func isSemicolon(r TokenRune) bool {
-	return r == TokenSemicolon
+// stringLiteralEnd returns the index after the closing quote of the string literal starting with
+// the opening quote at the given index, or len(runes) if the string literal isn't closed.
+func stringLiteralEnd(runes []rune, start int) int {
+	for i := start + 1; i < len(runes); i++ {
+		switch TokenRune(runes[i]) {
+		case '\\':
+			i++
+		case TokenQuote:
+			return i + 1
+		}
+	}
+	return len(runes)
 }
 
-// isEqual checks if the given rune is an equals sign.
-func isEqual(r TokenRune) bool {
-	return r == TokenEquals
+// stringToken converts the quoted text of a string literal into a string token holding the
+// unquoted value. Escape sequences follow Go, text which isn't a valid Go string literal is
+// kept as is.
+func stringToken(quoted string) Token {
+	value, err := strconv.Unquote(quoted)
+	if err != nil {
+		value = strings.TrimSuffix(strings.TrimPrefix(quoted, string(TokenQuote)), string(TokenQuote))
+	}
+	return Token{Type: TokenStringType, Value: value}
 }
 
-// isAdd checks if the given rune is an add sign.
-func isAdd(r TokenRune) bool {
-	return r == TokenAdd
+// wordToken converts an accumulated word into a keyword or identifier token.
+// If caseInsensitiveKeywords is set, keywords are recognized in any case.
+func wordToken(word TokenValue, caseInsensitiveKeywords bool) Token {
+	if caseInsensitiveKeywords {
+		if tokenType, ok := keywords[TokenValue(strings.ToLower(string(word)))]; ok {
+			return Token{Type: tokenType}
+		}
+	}
+	if tokenType, ok := keywords[word]; ok {
+		return Token{Type: tokenType}
+	}
+	return Token{Type: TokenIdentifierType, Value: string(word)}
 }
 
-// Tokenize function converts the input string into a slice of tokens
+// Tokenize function converts the input string into a slice of tokens, skipping comments
 func Tokenize(input string) []Token {
+	tokens, _ := tokenize(input, false)
+	return tokens
+}
+
+// TokenizeCaseInsensitive converts the input string into a slice of tokens like Tokenize, but
+// accepts keywords in any case, e.g. LET or Function. Keyword tokens don't keep their spelling,
+// so both spellings produce the same tokens. Identifiers stay case-sensitive.
+func TokenizeCaseInsensitive(input string) []Token {
+	tokens, _ := tokenize(input, true)
+	return tokens
+}
+
+// TokenizeComments converts the input string into a slice of tokens like Tokenize, and returns the
+// comments it skips, e.g. to attach them to the nodes parsed from the tokens with NewCommentMap.
+func TokenizeComments(input string) ([]Token, []*Comment) {
+	return tokenize(input, false)
+}
+
+// Comment is a line comment, which starts with // and ends at the end of the line.
+// example: // Returns the sum of a and b.
+type Comment struct {
+	Span
+	Text string // The text of the comment including the //.
+}
+
+// tokenize converts the input string into a slice of tokens and the comments between them,
+// recognizing keywords in any case if caseInsensitiveKeywords is set.
+func tokenize(input string, caseInsensitiveKeywords bool) ([]Token, []*Comment) {
 	tokens := make([]Token, 0)
+	var comments []*Comment
+	runes := []rune(input)
+	positions := runePositions(runes)
+
+	// emit appends the token, which spans the runes from start up to end, excluding end
+	emit := func(token Token, start, end int) {
+		token.Pos = positions[start]
+		token.End = positions[end]
+		tokens = append(tokens, token)
+	}
 
 	var sb strings.Builder
-	var previousTokenRune TokenRune
-	for _, r := range input {
-
-		// Handle comma-separated tokens or tokens separated by add sign token or tokens separated by semicolon
-		if isComma(TokenRune(r)) || isAdd(TokenRune(r)) || isSemicolon(TokenRune(r)) {
-			if sb.Len() > 0 {
-				word := TokenValue(sb.String())
-				sb.Reset()
-
-				switch word {
-				case TokenInteger32:
-					tokens = append(tokens, Token{Type: TokenInteger32Type})
-				default:
-					tokens = append(tokens, Token{Type: TokenIdentifierType, Value: string(word)})
-				}
+	var wordStart int
+	// flush appends the accumulated word, if any, as a token
+	flush := func() {
+		if sb.Len() > 0 {
+			word := sb.String()
+			emit(wordToken(TokenValue(word), caseInsensitiveKeywords), wordStart, wordStart+len([]rune(word)))
+			sb.Reset()
+		}
+	}
 
-			}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
 
-			switch TokenRune(r) {
-			case TokenAdd:
-				tokens = append(tokens, Token{Type: TokenAddType})
-			case TokenSemicolon:
-				tokens = append(tokens, Token{Type: TokenSemicolonType})
-			}
-		} else if isEqual(TokenRune(r)) && previousTokenRune != TokenColon {
-			// Handle equal sign tokens
-			tokens = append(tokens, Token{Type: TokenEqualsType})
-		} else if unicode.IsSpace(r) {
+		if unicode.IsSpace(r) {
 			// Handle whitespace-separated tokens
-			if sb.Len() > 0 {
-				word := TokenValue(sb.String())
-				sb.Reset()
-
-				switch word {
-				case TokenValue(TokenLessThan):
-					tokens = append(tokens, Token{Type: TokenLessThanType})
-				case TokenShortVariableAssignment:
-					tokens = append(tokens, Token{Type: TokenShortVariableAssignmentType})
-				case TokenFor:
-					tokens = append(tokens, Token{Type: TokenForType})
-				case TokenWhile:
-					tokens = append(tokens, Token{Type: TokenWhileType})
-				case TokenLet:
-					tokens = append(tokens, Token{Type: TokenLetType})
-				case TokenFunction:
-					tokens = append(tokens, Token{Type: TokenFunctionType})
-				case TokenInteger32:
-					tokens = append(tokens, Token{Type: TokenInteger32Type})
-				default:
-					tokens = append(tokens, Token{Type: TokenIdentifierType, Value: string(word)})
-				}
-			}
-		} else if isCurly(TokenRune(r)) {
-			// Handle curly brackets as tokens
-			if sb.Len() > 0 {
-				word := TokenValue(sb.String())
-				sb.Reset()
-
-				switch word {
-				case TokenWhile:
-					tokens = append(tokens, Token{Type: TokenWhileType})
-				case TokenLet:
-					tokens = append(tokens, Token{Type: TokenLetType})
-				default:
-					tokens = append(tokens, Token{Type: TokenIdentifierType, Value: string(word)})
-				}
-			}
-
-			switch TokenRune(r) {
-			case TokenOpenParenthesis:
-				tokens = append(tokens, Token{Type: TokenOpenParenthesisType})
-			case TokenCloseParenthesis:
-				tokens = append(tokens, Token{Type: TokenCloseParenthesisType})
-			case TokenOpenCurlyBracket:
-				tokens = append(tokens, Token{Type: TokenOpenCurlyBracketType})
-			case TokenCloseCurlyBracket:
-				tokens = append(tokens, Token{Type: TokenCloseCurlyBracketType})
+			flush()
+		} else if isCommentStart(runes, i) {
+			// Handle comments, which aren't tokens
+			flush()
+			end := commentEnd(runes, i)
+			comments = append(comments, &Comment{Span: Span{From: positions[i], To: positions[end]}, Text: string(runes[i:end])})
+			i = end - 1
+		} else if TokenRune(r) == TokenQuote {
+			// Handle string literals
+			flush()
+			end := stringLiteralEnd(runes, i)
+			emit(stringToken(string(runes[i:end])), i, end)
+			i = end - 1
+		} else if TokenRune(r) == TokenOpenCurlyBracket && isInlineIRStart(tokens, &sb, caseInsensitiveKeywords) {
+			// Handle the LLVM IR of an inline block, which is kept as it is
+			flush()
+			if end, ok := inlineIREnd(runes, i); ok {
+				emit(Token{Type: TokenInlineIRType, Value: string(runes[i+1 : end])}, i, end+1)
+				i = end
+			} else {
+				emit(Token{Type: TokenOpenCurlyBracketType}, i, i+1)
 			}
-		} else if isBracket(TokenRune(r)) {
-			// Handle curly parentheses
-			if sb.Len() > 0 {
-				word := TokenValue(sb.String())
-				sb.Reset()
-
-				switch word {
-				case TokenInteger32:
-					tokens = append(tokens, Token{Type: TokenInteger32Type})
-				default:
-					tokens = append(tokens, Token{Type: TokenIdentifierType, Value: string(word)})
-				}
-			}
-
-			switch TokenRune(r) {
-			case TokenOpenParenthesis:
-				tokens = append(tokens, Token{Type: TokenOpenParenthesisType})
-			case TokenCloseParenthesis:
-				tokens = append(tokens, Token{Type: TokenCloseParenthesisType})
+		} else if TokenRune(r) == TokenColon && i+1 < len(runes) && TokenRune(runes[i+1]) == TokenEquals {
+			// Handle short variable assignment tokens
+			flush()
+			emit(Token{Type: TokenShortVariableAssignmentType}, i, i+2)
+			i++
+		} else if (TokenRune(r) == TokenLessThan || TokenRune(r) == TokenGreaterThan) && i+1 < len(runes) && runes[i+1] == r {
+			// Handle shift tokens
+			flush()
+			tokenType := TokenShiftLeftType
+			if TokenRune(r) == TokenGreaterThan {
+				tokenType = TokenShiftRightType
 			}
+			emit(Token{Type: tokenType}, i, i+2)
+			i++
+		} else if tokenType, ok := comparisonTokens[TokenRune(r)]; ok && i+1 < len(runes) && TokenRune(runes[i+1]) == TokenEquals {
+			// Handle comparison tokens
+			flush()
+			emit(Token{Type: tokenType}, i, i+2)
+			i++
+		} else if TokenRune(r) == TokenDot && !isNumberWord(sb.String()) {
+			// Handle dots which select a field
+			flush()
+			emit(Token{Type: TokenDotType}, i, i+1)
+		} else if tokenType, ok := runeTokens[TokenRune(r)]; ok {
+			// Handle special characters as tokens
+			flush()
+			emit(Token{Type: tokenType}, i, i+1)
 		} else {
 			// Accumulate non-special characters into a word
+			if sb.Len() == 0 {
+				wordStart = i
+			}
 			sb.WriteRune(r)
 		}
-		previousTokenRune = TokenRune(r)
 	}
 
 	// Process the last word if any
-	if sb.Len() > 0 {
-		word := TokenValue(sb.String())
-		switch word {
-		case TokenWhile:
-			tokens = append(tokens, Token{Type: TokenWhileType})
-		case TokenLet:
-			tokens = append(tokens, Token{Type: TokenLetType})
-		case TokenFunction:
-			tokens = append(tokens, Token{Type: TokenFunctionType})
-		default:
-			tokens = append(tokens, Token{Type: TokenIdentifierType, Value: string(word)})
+	flush()
+
+	return tokens, comments
+}
+
+// NormalizeKeywords rewrites every keyword of the input written in another case, e.g. LET or
+// Function, into its canonical lowercase spelling. Everything else, including identifiers and
+// whitespace, is kept as is, so the result tokenizes with Tokenize like the input tokenizes
+// with TokenizeCaseInsensitive.
+func NormalizeKeywords(input string) string {
+	var normalized strings.Builder
+	var word strings.Builder
+	// flush writes the accumulated word, if any, lowercasing it if it is a keyword
+	flush := func() {
+		w := word.String()
+		if _, ok := keywords[TokenValue(strings.ToLower(w))]; ok {
+			w = strings.ToLower(w)
 		}
+		normalized.WriteString(w)
+		word.Reset()
 	}
 
-	return tokens
+	runes := []rune(input)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		_, ok := runeTokens[TokenRune(r)]
+		if TokenRune(r) == TokenQuote {
+			// Keep string literals as they are
+			flush()
+			end := stringLiteralEnd(runes, i)
+			normalized.WriteString(string(runes[i:end]))
+			i = end - 1
+		} else if isCommentStart(runes, i) {
+			// Keep comments as they are
+			flush()
+			end := commentEnd(runes, i)
+			normalized.WriteString(string(runes[i:end]))
+			i = end - 1
+		} else if ok || unicode.IsSpace(r) || (TokenRune(r) == TokenDot && !isNumberWord(word.String())) {
+			flush()
+			normalized.WriteRune(r)
+		} else {
+			word.WriteRune(r)
+		}
+	}
+	flush()
+
+	return normalized.String()
 }
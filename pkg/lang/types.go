@@ -0,0 +1,262 @@
+package lang
+
+import (
+	"fmt"
+	"sync"
+)
+
+// dataType represents the underlying data type of a value.
+// Built-in data types are the constants below, composite data types like arrays, slices, pointers, options, results and structs
+// are registered in compositeTypes and identified by their position in it.
+type dataType int
+
+// Constants for different data types.
+const (
+	// Integer32Type represents the 32-bit integer data type.
+	Integer32Type dataType = iota
+	// Integer8Type represents the 8-bit integer data type.
+	Integer8Type
+	// Integer16Type represents the 16-bit integer data type.
+	Integer16Type
+	// Integer64Type represents the 64-bit integer data type.
+	Integer64Type
+	// Float32Type represents the 32-bit floating point data type.
+	Float32Type
+	// Float64Type represents the 64-bit floating point data type.
+	Float64Type
+	// BoolType represents the boolean data type.
+	BoolType
+	// StringType represents the string data type, a pointer to null-terminated bytes.
+	StringType
+	// VoidType represents the absence of a value, e.g. the return type of a function without result.
+	VoidType
+)
+
+// firstCompositeType is the data type of the first registered composite type.
+const firstCompositeType = VoidType + 1
+
+// typeTokens maps type keyword tokens to their data types.
+var typeTokens = map[TokenType]dataType{
+	TokenInteger8Type:      Integer8Type,
+	TokenInteger16Type:     Integer16Type,
+	TokenInteger32Type:     Integer32Type,
+	TokenInteger64Type:     Integer64Type,
+	TokenFloat32Type:       Float32Type,
+	TokenFloat64Type:       Float64Type,
+	TokenBoolType:          BoolType,
+	TokenStringKeywordType: StringType,
+}
+
+// ArrayType describes a fixed-size array data type holding Length values of the Element data type.
+// example: [4]i32
+type ArrayType struct {
+	Element dataType
+	Length  int
+}
+
+// String returns the spelling of the array type.
+func (t ArrayType) String() string {
+	return fmt.Sprintf("[%d]%s", t.Length, t.Element)
+}
+
+// SliceType describes a dynamic array data type holding a growable number of values of the
+// Element data type. A slice refers to a heap-allocated backing array and carries its length
+// and capacity.
+// example: []i32
+type SliceType struct {
+	Element dataType
+}
+
+// String returns the spelling of the slice type.
+func (t SliceType) String() string {
+	return fmt.Sprintf("[]%s", t.Element)
+}
+
+// PointerType describes a pointer data type referring to a value of the Element data type.
+// example: *i32
+type PointerType struct {
+	Element dataType
+}
+
+// String returns the spelling of the pointer type.
+func (t PointerType) String() string {
+	return fmt.Sprintf("*%s", t.Element)
+}
+
+// OptionType describes a data type holding either no value or a value of the Element data type.
+// Options are created with none and some(value) and can only be unwrapped with a fallback value,
+// so a missing value can't be used by accident.
+// example: option<i32>
+type OptionType struct {
+	Element dataType
+}
+
+// String returns the spelling of the option type.
+func (t OptionType) String() string {
+	return fmt.Sprintf("option<%s>", t.Element)
+}
+
+// ResultType describes a data type holding either a value of the Element data type or an i32
+// error code. Results are created with ok(value) and err(code), the value is taken with try,
+// which returns the error from the current function, or with unwrap_or.
+// example: result<i32>
+type ResultType struct {
+	Element dataType
+}
+
+// String returns the spelling of the result type.
+func (t ResultType) String() string {
+	return fmt.Sprintf("result<%s>", t.Element)
+}
+
+// StructType describes a struct data type declared with the given name. Its fields are
+// part of the declaration, which is looked up while generating the module using it.
+// example: Point
+type StructType struct {
+	Name string
+}
+
+// String returns the name of the struct type.
+func (t StructType) String() string {
+	return t.Name
+}
+
+// compositeTypes holds the descriptions of all composite data types registered so far.
+// Every description is registered only once, so equal composite types share the same
+// data type and can be compared with ==.
+var compositeTypes = struct {
+	sync.Mutex
+	descriptions []any
+	types        map[any]dataType
+}{types: make(map[any]dataType)}
+
+// compositeDataType returns the data type of the given composite type description,
+// registering the description if it is new.
+func compositeDataType(description any) dataType {
+	compositeTypes.Lock()
+	defer compositeTypes.Unlock()
+
+	if t, ok := compositeTypes.types[description]; ok {
+		return t
+	}
+	t := firstCompositeType + dataType(len(compositeTypes.descriptions))
+	compositeTypes.descriptions = append(compositeTypes.descriptions, description)
+	compositeTypes.types[description] = t
+	return t
+}
+
+// arrayOf returns the data type of arrays holding length values of the element data type.
+func arrayOf(element dataType, length int) dataType {
+	return compositeDataType(ArrayType{Element: element, Length: length})
+}
+
+// sliceOf returns the data type of slices holding values of the element data type.
+func sliceOf(element dataType) dataType {
+	return compositeDataType(SliceType{Element: element})
+}
+
+// pointerTo returns the data type of pointers referring to values of the element data type.
+func pointerTo(element dataType) dataType {
+	return compositeDataType(PointerType{Element: element})
+}
+
+// optionOf returns the data type of options holding a value of the element data type.
+func optionOf(element dataType) dataType {
+	return compositeDataType(OptionType{Element: element})
+}
+
+// resultOf returns the data type of results holding a value of the element data type.
+func resultOf(element dataType) dataType {
+	return compositeDataType(ResultType{Element: element})
+}
+
+// structOf returns the data type of the struct type declared with the given name.
+func structOf(name string) dataType {
+	return compositeDataType(StructType{Name: name})
+}
+
+// composite returns the description of a composite data type, or nil for built-in data types.
+func (t dataType) composite() any {
+	compositeTypes.Lock()
+	defer compositeTypes.Unlock()
+
+	i := int(t - firstCompositeType)
+	if i < 0 || i >= len(compositeTypes.descriptions) {
+		return nil
+	}
+	return compositeTypes.descriptions[i]
+}
+
+// array returns the array type described by the data type and whether it is an array type.
+func (t dataType) array() (ArrayType, bool) {
+	arrayType, ok := t.composite().(ArrayType)
+	return arrayType, ok
+}
+
+// slice returns the slice type described by the data type and whether it is a slice type.
+func (t dataType) slice() (SliceType, bool) {
+	sliceType, ok := t.composite().(SliceType)
+	return sliceType, ok
+}
+
+// pointer returns the pointer type described by the data type and whether it is a pointer type.
+func (t dataType) pointer() (PointerType, bool) {
+	pointerType, ok := t.composite().(PointerType)
+	return pointerType, ok
+}
+
+// option returns the option type described by the data type and whether it is an option type.
+func (t dataType) option() (OptionType, bool) {
+	optionType, ok := t.composite().(OptionType)
+	return optionType, ok
+}
+
+// result returns the result type described by the data type and whether it is a result type.
+func (t dataType) result() (ResultType, bool) {
+	resultType, ok := t.composite().(ResultType)
+	return resultType, ok
+}
+
+// structure returns the struct type described by the data type and whether it is a struct type.
+func (t dataType) structure() (StructType, bool) {
+	structType, ok := t.composite().(StructType)
+	return structType, ok
+}
+
+// String returns the keyword spelling of the data type.
+func (t dataType) String() string {
+	switch t {
+	case Integer8Type:
+		return string(TokenInteger8)
+	case Integer16Type:
+		return string(TokenInteger16)
+	case Integer32Type:
+		return string(TokenInteger32)
+	case Integer64Type:
+		return string(TokenInteger64)
+	case Float32Type:
+		return string(TokenFloat32)
+	case Float64Type:
+		return string(TokenFloat64)
+	case BoolType:
+		return string(TokenBool)
+	case StringType:
+		return string(TokenStringKeyword)
+	case VoidType:
+		return "void"
+	}
+	if description := t.composite(); description != nil {
+		return fmt.Sprint(description)
+	}
+	return fmt.Sprintf("unknown(%d)", int(t))
+}
+
+// isInteger reports whether the data type is one of the integer types.
+func (t dataType) isInteger() bool {
+	return t == Integer8Type || t == Integer16Type || t == Integer32Type || t == Integer64Type
+}
+
+// isFloat reports whether the data type is one of the floating point types.
+func (t dataType) isFloat() bool {
+	return t == Float32Type || t == Float64Type
+}
@@ -0,0 +1,109 @@
+package lang
+
+// checkUndefined reports a diagnostic for every use of a name which the program doesn't declare,
+// suggesting the declared name closest to it, if there is one which is close enough to be a typo.
+func (c *checker) checkUndefined() {
+	for _, node := range c.resolution.Unresolved {
+		var err error
+		var name string
+		var candidates []string
+		switch n := node.(type) {
+		case *CallerNode:
+			name, candidates = n.FunctionName, c.resolution.Functions.Names()
+			for builtin := range builtinSignatures {
+				candidates = append(candidates, builtin)
+			}
+			err = newError(MessageCallerNotFound, name)
+		case *StructLiteralNode:
+			name, candidates = n.Name, c.resolution.Types.Names()
+			err = newError(MessageUnknownStruct, name)
+		default:
+			name, candidates = usedName(node), c.resolution.visibleValues(node)
+			err = newError(MessageVariableNotFound, name)
+		}
+		if suggestion := suggestName(name, candidates); suggestion != "" {
+			err = newError(MessageDidYouMean, err, suggestion)
+		}
+		c.report(node, err)
+	}
+}
+
+// usedName returns the name of the value the node uses.
+func usedName(node Node) string {
+	switch n := node.(type) {
+	case *IdentifierNode:
+		return n.Name
+	case *AssignmentNode:
+		return n.Identifier
+	case *ConditionNode:
+		return n.LeftValue
+	case *PostNode:
+		return n.Identifier
+	case *WhileNode:
+		return n.Condition
+	}
+	return ""
+}
+
+// visibleValues returns the names of the values the node using a name can use: the values of the
+// scope it is resolved in and of the scopes enclosing it, excluding the variables declared after it.
+func (r *Resolution) visibleValues(node Node) []string {
+	var names []string
+	for scope := r.Scopes[node]; scope != nil; scope = scope.Parent {
+		for _, name := range scope.Values.Names() {
+			symbol, _ := scope.Values.Get(name)
+			if scope.Kind == ScopeGlobal || !node.Pos().IsValid() || before(symbol.Node.Pos(), node.Pos()) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// suggestName returns the candidate closest to the name by edit distance, or "" if no candidate is
+// within a third of the length of the name, so a suggestion is likely a typo of the name. Of
+// equally close candidates, the first in alphabetical order is suggested.
+func suggestName(name string, candidates []string) string {
+	best, bestDistance := "", len(name)/3+1
+	for _, candidate := range candidates {
+		distance := editDistance(name, candidate)
+		if distance < bestDistance || distance == bestDistance && best != "" && candidate < best {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the optimal string alignment distance of the strings, which is the number
+// of runes which have to be inserted, deleted or replaced and of adjacent runes which have to be
+// swapped to turn one into the other, e.g. 1 for cuont and count.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = d[i-1][j-1] + cost
+			if d[i-1][j]+1 < d[i][j] {
+				d[i][j] = d[i-1][j] + 1
+			}
+			if d[i][j-1]+1 < d[i][j] {
+				d[i][j] = d[i][j-1] + 1
+			}
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
@@ -0,0 +1,57 @@
+package lang
+
+// Unreachable returns a warning for the first statement of every run of statements which can never
+// run, in the order of the source code. A statement can't run if it follows a return statement or
+// a call of the exit builtin in the same body, of the top-level statements, a function or a loop.
+// Declarations following a top-level return aren't reported, since they don't run anyway.
+func Unreachable(nodes []Node) []Diagnostic {
+	u := unreachable{declared: make(map[string]bool)}
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *FunctionNode:
+			u.declared[n.Name] = true
+		case *ExternNode:
+			u.declared[n.Name] = true
+		}
+	}
+	u.statements(nodes, true)
+	return u.diagnostics
+}
+
+// unreachable holds the state of finding the unreachable statements of a program.
+type unreachable struct {
+	declared    map[string]bool // The names of the functions and extern functions the program declares.
+	diagnostics []Diagnostic
+}
+
+// statements reports the first statement of the body following a statement which ends it, and the
+// unreachable statements of the nested bodies of the statements which can run.
+func (u *unreachable) statements(nodes []Node, topLevel bool) {
+	var end error
+	reported := false
+	for _, node := range nodes {
+		if end != nil && !(topLevel && isDeclaration(node)) {
+			if !reported {
+				diagnostic := newDiagnostic(node, end)
+				diagnostic.Severity = SeverityWarning
+				u.diagnostics = append(u.diagnostics, diagnostic)
+				reported = true
+			}
+			continue
+		}
+		switch n := node.(type) {
+		case *FunctionNode:
+			u.statements(n.Body, false)
+		case *ForNode:
+			u.statements(n.Body, false)
+		case *WhileNode:
+			u.statements(n.Body, false)
+		case *ReturnNode:
+			end = newError(MessageUnreachableAfterReturn, n.Pos())
+		case *CallerNode:
+			if n.FunctionName == exitIdentifier && !u.declared[exitIdentifier] {
+				end = newError(MessageUnreachableAfterExit, n.Pos())
+			}
+		}
+	}
+}
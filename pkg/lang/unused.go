@@ -0,0 +1,58 @@
+package lang
+
+import "sort"
+
+// Unused returns a warning for every variable declared by a let statement and every parameter of
+// a function which the program never reads, in the order of the source code. Assigning a
+// variable doesn't read it, incrementing it in a for loop does. Names starting with an underscore
+// aren't reported, so a parameter can be declared unused on purpose, e.g. _unused i32.
+//
+// Returns an error if the nodes aren't valid, see Validate.
+func Unused(nodes []Node) ([]Diagnostic, error) {
+	resolution, err := Resolve(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []Diagnostic
+	var walk func(scope *SymbolScope)
+	walk = func(scope *SymbolScope) {
+		for _, name := range scope.Values.Names() {
+			symbol, _ := scope.Values.Get(name)
+			if isRead(symbol) || name[0] == '_' {
+				continue
+			}
+			var err error
+			switch symbol.Node.(type) {
+			case *LetNode:
+				err = newError(MessageUnusedVariable, name)
+			case *Parameter:
+				err = newError(MessageUnusedParameter, name, scope.Node.(*FunctionNode).Name)
+			default:
+				continue
+			}
+			diagnostic := newDiagnostic(symbol.Node, err)
+			diagnostic.Severity = SeverityWarning
+			diagnostics = append(diagnostics, diagnostic)
+		}
+		for _, child := range scope.Children {
+			walk(child)
+		}
+	}
+	walk(resolution.Global)
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return before(diagnostics[i].From, diagnostics[j].From)
+	})
+	return diagnostics, nil
+}
+
+// isRead reports whether a node using the symbol reads its value.
+func isRead(symbol *Symbol) bool {
+	for _, use := range symbol.Uses {
+		if _, ok := use.(*AssignmentNode); !ok {
+			return true
+		}
+	}
+	return false
+}
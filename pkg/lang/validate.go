@@ -0,0 +1,258 @@
+package lang
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Validate checks the structural invariants of the nodes of a program which the parser
+// guarantees, e.g. that bodies and values aren't nil, operators are known and values have valid
+// data types. Nodes built in Go code or decoded from JSON can be validated before they are passed
+// to GenerateLLVMIR, which expects valid nodes.
+//
+// Returns an error describing the first invalid node and its position, if it is known, e.g.
+// LetNode at 1:5 has no Value.
+func Validate(nodes []Node) error {
+	return validateNodes(nodes, "program", "statement")
+}
+
+// validateNodes validates the statements of the program or of the named body field of the
+// described node.
+func validateNodes(nodes []Node, context, field string) error {
+	for i, node := range nodes {
+		if isNilNode(node) {
+			return newError(MessageInvalidNodeNil, context, fmt.Sprintf("%s[%d]", field, i))
+		}
+		if err := validateNode(node, context); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateNode validates the node, which is a statement or a value of the described node, and
+// every child node.
+func validateNode(node Node, context string) error {
+	if t := reflect.TypeOf(node); t.Kind() != reflect.Pointer || nodeKinds[t.Elem().Name()] != t.Elem() {
+		return newError(MessageInvalidNodeKind, node, context)
+	}
+	v := validator{node: describeNode(node)}
+
+	switch n := node.(type) {
+	case *FunctionNode:
+		v.name("Name", n.Name)
+		v.parameters(n.Parameters)
+		v.dataType(n.ReturnType, true)
+		if v.err == nil {
+			v.err = validateNodes(n.Body, v.node, "Body")
+		}
+	case *ExternNode:
+		v.name("Name", n.Name)
+		v.parameters(n.Parameters)
+		v.dataType(n.ReturnType, true)
+	case *Parameter:
+		v.name("Identifier", n.Identifier)
+		v.dataType(n.Type, false)
+	case *LetNode:
+		v.name("Identifier", n.Identifier)
+		v.dataType(n.Type, false)
+		if n.Ordering < NotAtomic || n.Ordering > OrderingSequentiallyConsistent {
+			v.fail(MessageInvalidNodeOperator, int(n.Ordering))
+		}
+		v.value("Value", n.Value)
+	case *AssignmentNode:
+		v.name("Identifier", n.Identifier)
+		v.value("Value", n.Value)
+	case *IndexAssignmentNode:
+		v.target(n.Target)
+		v.value("Value", n.Value)
+	case *FieldAssignmentNode:
+		v.target(n.Target)
+		v.value("Value", n.Value)
+	case *DereferenceAssignmentNode:
+		v.target(n.Target)
+		v.value("Value", n.Value)
+	case *ReturnNode:
+		// A function without result returns no value
+		if n.Value != nil {
+			v.value("Value", n.Value)
+		}
+	case *CallerNode:
+		v.name("FunctionName", n.FunctionName)
+		for i, arg := range n.Args {
+			v.value(fmt.Sprintf("Args[%d]", i), arg)
+		}
+	case *SpawnNode:
+		v.target(n.Call)
+	case *AddOperationNode:
+		v.value("LeftValue", n.LeftValue)
+		v.value("RightValue", n.RightValue)
+	case *ShiftOperationNode:
+		v.value("LeftValue", n.LeftValue)
+		v.value("RightValue", n.RightValue)
+	case *ComparisonNode:
+		if n.Operator < OperatorEqual || n.Operator > OperatorGreaterEqual {
+			v.fail(MessageInvalidNodeOperator, int(n.Operator))
+		}
+		v.value("LeftValue", n.LeftValue)
+		v.value("RightValue", n.RightValue)
+	case *CastNode:
+		v.dataType(n.Type, false)
+		v.value("Value", n.Value)
+	case *NewNode:
+		v.dataType(n.Type, false)
+	case *IndexNode:
+		v.value("Value", n.Value)
+		v.value("Index", n.Index)
+	case *FieldNode:
+		v.value("Value", n.Value)
+		v.name("Field", n.Field)
+	case *AddressNode:
+		v.value("Value", n.Value)
+	case *DereferenceNode:
+		v.value("Value", n.Value)
+	case *TryNode:
+		v.value("Value", n.Value)
+	case *ConditionalNode:
+		v.value("Condition", n.Condition)
+		v.value("True", n.True)
+		v.value("False", n.False)
+	case *ArrayLiteralNode:
+		for i, element := range n.Elements {
+			v.value(fmt.Sprintf("Elements[%d]", i), element)
+		}
+	case *StructLiteralNode:
+		v.name("Name", n.Name)
+		for i, field := range n.Fields {
+			if field == nil {
+				v.fail(MessageInvalidNodeNil, fmt.Sprintf("Fields[%d]", i))
+				continue
+			}
+			v.name(fmt.Sprintf("Fields[%d].Identifier", i), field.Identifier)
+			v.value(fmt.Sprintf("Fields[%d].Value", i), field.Value)
+		}
+	case *StructNode:
+		v.name("Name", n.Name)
+		for i, field := range n.Fields {
+			if field == nil {
+				v.fail(MessageInvalidNodeNil, fmt.Sprintf("Fields[%d]", i))
+				continue
+			}
+			v.name(fmt.Sprintf("Fields[%d].Identifier", i), field.Identifier)
+			v.dataType(field.Type, false)
+		}
+	case *TypeAliasNode:
+		v.name("Name", n.Name)
+		v.dataType(n.Type, false)
+	case *EmbedNode:
+		v.name("Identifier", n.Identifier)
+		v.name("Path", n.Path)
+	case *ImportNode:
+		v.name("Path", n.Path)
+	case *PackageNode:
+		v.name("Name", n.Name)
+	case *IdentifierNode:
+		v.name("Name", n.Name)
+	case *ForNode:
+		v.target(&n.Init)
+		v.target(&n.Condition)
+		v.target(&n.Post)
+		if v.err == nil {
+			v.err = validateNodes(n.Body, v.node, "Body")
+		}
+	case *ShortVariableAssigmentNode:
+		v.name("Identifier", n.Identifier)
+		v.value("Value", n.Value)
+	case *ConditionNode:
+		v.name("LeftValue", n.LeftValue)
+		if n.Operator != OperatorLess {
+			v.fail(MessageInvalidNodeOperator, int(n.Operator))
+		}
+		v.value("RightValue", n.RightValue)
+	case *PostNode:
+		v.name("Identifier", n.Identifier)
+	case *WhileNode:
+		v.name("Condition", n.Condition)
+		if v.err == nil {
+			v.err = validateNodes(n.Body, v.node, "Body")
+		}
+	}
+	return v.err
+}
+
+// isNilNode reports whether the node is nil or a nil pointer, e.g. a nil *LetNode.
+func isNilNode(node Node) bool {
+	if node == nil {
+		return true
+	}
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// describeNode returns the kind of the node followed by its position, if it is known, e.g.
+// LetNode at 1:5.
+func describeNode(node Node) string {
+	kind := nodeKind(node)
+	if !node.Pos().IsValid() {
+		return kind
+	}
+	return fmt.Sprintf("%s at %s", kind, node.Pos())
+}
+
+// validator validates the fields of a node and holds the first error found.
+type validator struct {
+	node string // The description of the node.
+	err  error
+}
+
+// fail sets the error to the message about the node, unless an error was found already.
+func (v *validator) fail(id MessageID, args ...any) {
+	if v.err == nil {
+		v.err = newError(id, append([]any{v.node}, args...)...)
+	}
+}
+
+// name validates that the named field of the node holds a name.
+func (v *validator) name(field, name string) {
+	if name == "" {
+		v.fail(MessageInvalidNodeName, field)
+	}
+}
+
+// value validates that the named field of the node holds a valid value.
+func (v *validator) value(field string, value Expr) {
+	if isNilNode(value) {
+		v.fail(MessageInvalidNodeNil, field)
+	} else if v.err == nil {
+		v.err = validateNode(value, v.node)
+	}
+}
+
+// target validates the node which the node is made of, e.g. the element an index assignment
+// assigns to.
+func (v *validator) target(node Node) {
+	if isNilNode(node) {
+		v.fail(MessageInvalidNodeNil, reflect.TypeOf(node).Elem().Name())
+	} else if v.err == nil {
+		v.err = validateNode(node, v.node)
+	}
+}
+
+// parameters validates the parameters of the function.
+func (v *validator) parameters(parameters []*Parameter) {
+	for i, parameter := range parameters {
+		if parameter == nil {
+			v.fail(MessageInvalidNodeNil, fmt.Sprintf("Parameters[%d]", i))
+		} else if v.err == nil {
+			v.err = validateNode(parameter, v.node)
+		}
+	}
+}
+
+// dataType validates that the data type is built in or registered, and isn't void unless void is
+// allowed, e.g. as the return type of a function.
+func (v *validator) dataType(t dataType, void bool) {
+	if t < Integer32Type || t == VoidType && !void || t > VoidType && t.composite() == nil {
+		v.fail(MessageInvalidNodeType, t)
+	}
+}
@@ -0,0 +1,98 @@
+package lang
+
+// Walk traverses the abstract syntax tree of the node in depth-first order. It calls visitor with
+// the node and, if visitor returns true, walks every child node in the order of the source code:
+// the parameters, statements and values the node holds.
+func Walk(node Node, visitor func(Node) bool) {
+	if node == nil || !visitor(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *FunctionNode:
+		walkParameters(n.Parameters, visitor)
+		WalkNodes(n.Body, visitor)
+	case *ExternNode:
+		walkParameters(n.Parameters, visitor)
+	case *LetNode:
+		Walk(n.Value, visitor)
+	case *AssignmentNode:
+		Walk(n.Value, visitor)
+	case *IndexAssignmentNode:
+		Walk(n.Target, visitor)
+		Walk(n.Value, visitor)
+	case *FieldAssignmentNode:
+		Walk(n.Target, visitor)
+		Walk(n.Value, visitor)
+	case *DereferenceAssignmentNode:
+		Walk(n.Target, visitor)
+		Walk(n.Value, visitor)
+	case *ReturnNode:
+		Walk(n.Value, visitor)
+	case *CallerNode:
+		for _, arg := range n.Args {
+			Walk(arg, visitor)
+		}
+	case *SpawnNode:
+		Walk(n.Call, visitor)
+	case *AddOperationNode:
+		Walk(n.LeftValue, visitor)
+		Walk(n.RightValue, visitor)
+	case *ShiftOperationNode:
+		Walk(n.LeftValue, visitor)
+		Walk(n.RightValue, visitor)
+	case *ComparisonNode:
+		Walk(n.LeftValue, visitor)
+		Walk(n.RightValue, visitor)
+	case *CastNode:
+		Walk(n.Value, visitor)
+	case *IndexNode:
+		Walk(n.Value, visitor)
+		Walk(n.Index, visitor)
+	case *FieldNode:
+		Walk(n.Value, visitor)
+	case *AddressNode:
+		Walk(n.Value, visitor)
+	case *DereferenceNode:
+		Walk(n.Value, visitor)
+	case *TryNode:
+		Walk(n.Value, visitor)
+	case *ConditionalNode:
+		Walk(n.Condition, visitor)
+		Walk(n.True, visitor)
+		Walk(n.False, visitor)
+	case *ArrayLiteralNode:
+		for _, element := range n.Elements {
+			Walk(element, visitor)
+		}
+	case *StructLiteralNode:
+		for _, field := range n.Fields {
+			Walk(field.Value, visitor)
+		}
+	case *ForNode:
+		Walk(&n.Init, visitor)
+		Walk(&n.Condition, visitor)
+		Walk(&n.Post, visitor)
+		WalkNodes(n.Body, visitor)
+	case *ShortVariableAssigmentNode:
+		Walk(n.Value, visitor)
+	case *ConditionNode:
+		Walk(n.RightValue, visitor)
+	case *WhileNode:
+		WalkNodes(n.Body, visitor)
+	}
+}
+
+// WalkNodes walks every node of the slice with Walk, e.g. the nodes of a program.
+func WalkNodes(nodes []Node, visitor func(Node) bool) {
+	for _, node := range nodes {
+		Walk(node, visitor)
+	}
+}
+
+// walkParameters walks every parameter of the slice.
+func walkParameters(parameters []*Parameter, visitor func(Node) bool) {
+	for _, parameter := range parameters {
+		Walk(parameter, visitor)
+	}
+}